@@ -23,6 +23,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/operator-framework/operator-sdk/internal/cmd/helm-operator/render"
 	"github.com/operator-framework/operator-sdk/internal/cmd/helm-operator/run"
 	"github.com/operator-framework/operator-sdk/internal/cmd/helm-operator/version"
 )
@@ -33,6 +34,7 @@ func main() {
 	}
 
 	root.AddCommand(run.NewCmd())
+	root.AddCommand(render.NewCmd())
 	root.AddCommand(version.NewCmd())
 
 	if err := root.Execute(); err != nil {
@@ -26,6 +26,7 @@ import (
 	"github.com/spf13/cobra/doc"
 
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/cli"
+	"github.com/operator-framework/operator-sdk/internal/plugins/external"
 )
 
 const fmTemplate = `---
@@ -39,6 +40,11 @@ func main() {
 		log.Fatalf("Failed to get current directory: %v", err)
 	}
 
+	// Generated docs must not depend on the external plugins installed locally.
+	if err := os.Setenv(external.PluginsDirEnv, ""); err != nil {
+		log.Fatalf("Failed to disable external plugin discovery: %v", err)
+	}
+
 	cliDocsPath := filepath.Join(currentDir, "website", "content", "en", "docs", "cli")
 	_, cliRoot := cli.GetPluginsCLIAndRoot()
 	cliRoot.DisableAutoGenTag = true
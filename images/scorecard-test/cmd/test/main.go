@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	scapiv1alpha3 "github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
 	apimanifests "github.com/operator-framework/api/pkg/manifests"
@@ -54,22 +55,14 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
-	var result scapiv1alpha3.TestStatus
+	// Use CRs passed to scorecard with --cr-manifest instead of alm-examples, if any.
+	crManifests := ""
+	if _, err := os.Stat(scorecard.PodCRManifestsPath); err == nil {
+		crManifests = scorecard.PodCRManifestsPath
+	}
 
-	switch entrypoint[0] {
-	case tests.OLMBundleValidationTest:
-		result = tests.BundleValidationTest(scorecard.PodBundleRoot, metadata)
-	case tests.OLMCRDsHaveValidationTest:
-		result = tests.CRDsHaveValidationTest(bundle)
-	case tests.OLMCRDsHaveResourcesTest:
-		result = tests.CRDsHaveResourcesTest(bundle)
-	case tests.OLMSpecDescriptorsTest:
-		result = tests.SpecDescriptorsTest(bundle)
-	case tests.OLMStatusDescriptorsTest:
-		result = tests.StatusDescriptorsTest(bundle)
-	case tests.BasicCheckSpecTest:
-		result = tests.CheckSpecTest(bundle)
-	default:
+	result, ok := tests.Run(entrypoint[0], scorecard.PodBundleRoot, bundle, metadata, crManifests)
+	if !ok {
 		result = printValidTests()
 	}
 
@@ -88,13 +81,7 @@ func printValidTests() scapiv1alpha3.TestStatus {
 	result.Errors = make([]string, 0)
 	result.Suggestions = make([]string, 0)
 
-	str := fmt.Sprintf("Valid tests for this image include: %s, %s, %s, %s, %s, %s",
-		tests.OLMBundleValidationTest,
-		tests.OLMCRDsHaveValidationTest,
-		tests.OLMCRDsHaveResourcesTest,
-		tests.OLMSpecDescriptorsTest,
-		tests.OLMStatusDescriptorsTest,
-		tests.BasicCheckSpecTest)
+	str := fmt.Sprintf("Valid tests for this image include: %s", strings.Join(tests.Names, ", "))
 	result.Errors = append(result.Errors, str)
 	return scapiv1alpha3.TestStatus{
 		Results: []scapiv1alpha3.TestResult{result},
@@ -34,6 +34,7 @@ import (
 
 	"github.com/operator-framework/operator-sdk/internal/ansible/events"
 	"github.com/operator-framework/operator-sdk/internal/ansible/predicate"
+	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/permissions"
 	"github.com/operator-framework/operator-sdk/internal/ansible/runner"
 )
 
@@ -52,6 +53,8 @@ type Options struct {
 	WatchClusterScopedResources bool
 	MaxConcurrentReconciles     int
 	Selector                    metav1.LabelSelector
+	StandardConditions          bool
+	PermissionTracker           *permissions.Tracker
 }
 
 // Add - Creates a new ansible operator controller and adds it to the manager
@@ -64,14 +67,16 @@ func Add(mgr manager.Manager, options Options) *controller.Controller {
 	eventHandlers := append(options.EventHandlers, events.NewLoggingEventHandler(options.LoggingLevel))
 
 	aor := &AnsibleOperatorReconciler{
-		Client:           mgr.GetClient(),
-		GVK:              options.GVK,
-		Runner:           options.Runner,
-		EventHandlers:    eventHandlers,
-		ReconcilePeriod:  options.ReconcilePeriod,
-		ManageStatus:     options.ManageStatus,
-		AnsibleDebugLogs: options.AnsibleDebugLogs,
-		APIReader:        mgr.GetAPIReader(),
+		Client:             mgr.GetClient(),
+		GVK:                options.GVK,
+		Runner:             options.Runner,
+		EventHandlers:      eventHandlers,
+		ReconcilePeriod:    options.ReconcilePeriod,
+		ManageStatus:       options.ManageStatus,
+		AnsibleDebugLogs:   options.AnsibleDebugLogs,
+		APIReader:          mgr.GetAPIReader(),
+		StandardConditions: options.StandardConditions,
+		PermissionTracker:  options.PermissionTracker,
 	}
 
 	scheme := mgr.GetScheme()
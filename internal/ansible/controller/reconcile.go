@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,8 +40,10 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/ansible/events"
 	"github.com/operator-framework/operator-sdk/internal/ansible/metrics"
 	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/kubeconfig"
+	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/permissions"
 	"github.com/operator-framework/operator-sdk/internal/ansible/runner"
 	"github.com/operator-framework/operator-sdk/internal/ansible/runner/eventapi"
+	"github.com/operator-framework/operator-sdk/internal/util/rbacutil"
 )
 
 const (
@@ -60,6 +63,13 @@ type AnsibleOperatorReconciler struct {
 	ReconcilePeriod  time.Duration
 	ManageStatus     bool
 	AnsibleDebugLogs bool
+	// StandardConditions, if true, makes status conditions follow the
+	// Kubernetes API conventions of metav1.Condition.
+	StandardConditions bool
+	// PermissionTracker, if set, holds the permissions the proxy denied to
+	// requests made on behalf of each custom resource, which are reported in
+	// its MissingPermissions condition.
+	PermissionTracker *permissions.Tracker
 }
 
 // Reconcile - handle the event.
@@ -75,6 +85,11 @@ func (r *AnsibleOperatorReconciler) Reconcile(request reconcile.Request) (reconc
 		return reconcile.Result{}, err
 	}
 
+	// generation is the generation of the custom resource being reconciled.
+	// Status computed from it is not written once the resource has a newer
+	// generation, which is reconciled next.
+	generation := u.GetGeneration()
+
 	ident := strconv.Itoa(rand.Int())
 	logger := logf.Log.WithName("reconciler").WithValues(
 		"job", ident,
@@ -87,7 +102,7 @@ func (r *AnsibleOperatorReconciler) Reconcile(request reconcile.Request) (reconc
 		duration, err := time.ParseDuration(ds)
 		if err != nil {
 			// Should attempt to update to a failed condition
-			errmark := r.markError(u, request.NamespacedName,
+			errmark := r.markError(u, request.NamespacedName, generation,
 				fmt.Sprintf("Unable to parse reconcile period annotation: %v", err))
 			if errmark != nil {
 				logger.Error(errmark, "Unable to mark error annotation")
@@ -134,6 +149,9 @@ func (r *AnsibleOperatorReconciler) Reconcile(request reconcile.Request) (reconc
 			logger.Error(errmark, "Unable to update the status to mark cr as running")
 			return reconcileResult, errmark
 		}
+		// markRunning fetched the latest resource, which is what the
+		// playbook runs with.
+		generation = u.GetGeneration()
 	}
 
 	ownerRef := metav1.OwnerReference{
@@ -145,7 +163,7 @@ func (r *AnsibleOperatorReconciler) Reconcile(request reconcile.Request) (reconc
 
 	kc, err := kubeconfig.Create(ownerRef, "http://localhost:8888", u.GetNamespace())
 	if err != nil {
-		errmark := r.markError(u, request.NamespacedName, "Unable to run reconciliation")
+		errmark := r.markError(u, request.NamespacedName, generation, "Unable to run reconciliation")
 		if errmark != nil {
 			logger.Error(errmark, "Unable to mark error to run reconciliation")
 		}
@@ -157,9 +175,13 @@ func (r *AnsibleOperatorReconciler) Reconcile(request reconcile.Request) (reconc
 			logger.Error(err, "Failed to remove generated kubeconfig file")
 		}
 	}()
+	if r.PermissionTracker != nil {
+		// Discard permissions denied to previous reconciliations.
+		r.PermissionTracker.Pop(u.GetUID())
+	}
 	result, err := r.Runner.Run(ident, u, kc.Name())
 	if err != nil {
-		errmark := r.markError(u, request.NamespacedName, "Unable to run reconciliation")
+		errmark := r.markError(u, request.NamespacedName, generation, "Unable to run reconciliation")
 		if errmark != nil {
 			logger.Error(errmark, "Unable to mark error to run reconciliation")
 		}
@@ -215,11 +237,13 @@ func (r *AnsibleOperatorReconciler) Reconcile(request reconcile.Request) (reconc
 	// To print the full ansible result
 	r.printAnsibleResult(result)
 
+	missingPerms := r.popMissingPermissions(u, logger)
+
 	if statusEvent.Event == "" {
 		eventErr := errors.New("did not receive playbook_on_stats event")
 		stdout, err := result.Stdout()
 		if err != nil {
-			errmark := r.markError(u, request.NamespacedName, "Failed to get ansible-runner stdout")
+			errmark := r.markError(u, request.NamespacedName, generation, "Failed to get ansible-runner stdout")
 			if errmark != nil {
 				logger.Error(errmark, "Unable to mark error to run reconciliation")
 			}
@@ -263,7 +287,7 @@ func (r *AnsibleOperatorReconciler) Reconcile(request reconcile.Request) (reconc
 		}
 	}
 	if r.ManageStatus {
-		errmark := r.markDone(u, request.NamespacedName, statusEvent, failureMessages)
+		errmark := r.markDone(u, request.NamespacedName, generation, statusEvent, failureMessages, missingPerms)
 		if errmark != nil {
 			logger.Error(errmark, "Failed to mark status done")
 		}
@@ -325,6 +349,9 @@ func (r *AnsibleOperatorReconciler) markRunning(u *unstructured.Unstructured,
 		ansiblestatus.RunningMessage,
 	)
 	ansiblestatus.SetCondition(&crStatus, *c)
+	if r.StandardConditions {
+		ansiblestatus.StandardizeConditions(&crStatus, u.GetGeneration())
+	}
 	u.Object["status"] = crStatus.GetJSONMap()
 
 	return r.Client.Status().Update(context.TODO(), u)
@@ -333,7 +360,7 @@ func (r *AnsibleOperatorReconciler) markRunning(u *unstructured.Unstructured,
 // markError - used to alert the user to the issues during the validation of a reconcile run.
 // i.e Annotations that could be incorrect
 func (r *AnsibleOperatorReconciler) markError(u *unstructured.Unstructured, namespacedName types.NamespacedName,
-	failureMessage string) error {
+	generation int64, failureMessage string) error {
 	logger := logf.Log.WithName("markError")
 	// Immediately update metrics with failed reconciliation, since Get()
 	// may fail.
@@ -362,6 +389,15 @@ func (r *AnsibleOperatorReconciler) markError(u *unstructured.Unstructured, name
 		failureMessage,
 	)
 	ansiblestatus.SetCondition(&crStatus, *c)
+	if isStale(u, crStatus, generation) {
+		logger.V(1).Info("Skipping status update computed from an older generation",
+			"generation", generation, "currentGeneration", u.GetGeneration())
+		return nil
+	}
+	crStatus.ObservedGeneration = generation
+	if r.StandardConditions {
+		ansiblestatus.StandardizeConditions(&crStatus, generation)
+	}
 	// This needs the status subresource to be enabled by default.
 	u.Object["status"] = crStatus.GetJSONMap()
 
@@ -369,7 +405,8 @@ func (r *AnsibleOperatorReconciler) markError(u *unstructured.Unstructured, name
 }
 
 func (r *AnsibleOperatorReconciler) markDone(u *unstructured.Unstructured, namespacedName types.NamespacedName,
-	statusEvent eventapi.StatusJobEvent, failureMessages eventapi.FailureMessages) error {
+	generation int64, statusEvent eventapi.StatusJobEvent, failureMessages eventapi.FailureMessages,
+	missingPerms []rbacutil.Permission) error {
 	logger := logf.Log.WithName("markDone")
 	// Get the latest resource to prevent updating a stale status.
 	if err := r.APIReader.Get(context.TODO(), namespacedName, u); err != nil {
@@ -412,12 +449,71 @@ func (r *AnsibleOperatorReconciler) markDone(u *unstructured.Unstructured, names
 		ansiblestatus.RemoveCondition(&crStatus, ansiblestatus.FailureConditionType)
 		ansiblestatus.SetCondition(&crStatus, *c)
 	}
+	setMissingPermissionsCondition(&crStatus, missingPerms)
+	if isStale(u, crStatus, generation) {
+		logger.V(1).Info("Skipping status update computed from an older generation",
+			"generation", generation, "currentGeneration", u.GetGeneration())
+		return nil
+	}
+	crStatus.ObservedGeneration = generation
+	if r.StandardConditions {
+		ansiblestatus.StandardizeConditions(&crStatus, generation)
+	}
 	// This needs the status subresource to be enabled by default.
 	u.Object["status"] = crStatus.GetJSONMap()
 
 	return r.Client.Status().Update(context.TODO(), u)
 }
 
+// popMissingPermissions returns the permissions the proxy denied to the
+// reconciliation of u, logging them and counting them in metrics.
+func (r *AnsibleOperatorReconciler) popMissingPermissions(u *unstructured.Unstructured,
+	logger logr.Logger) []rbacutil.Permission {
+	if r.PermissionTracker == nil {
+		return nil
+	}
+	perms := r.PermissionTracker.Pop(u.GetUID())
+	if len(perms) == 0 {
+		return nil
+	}
+	logger.Info("Reconciliation was denied RBAC permissions, which must be granted to the operator",
+		"permissions", rbacutil.MissingPermissionsMessage(perms))
+	for _, p := range perms {
+		metrics.MissingPermission(r.GVK.String(), p.Verb, p.APIGroup, p.Resource)
+	}
+	return perms
+}
+
+// setMissingPermissionsCondition sets the MissingPermissions condition of
+// crStatus to list perms, or removes it if there are none.
+func setMissingPermissionsCondition(crStatus *ansiblestatus.Status, perms []rbacutil.Permission) {
+	if len(perms) == 0 {
+		ansiblestatus.RemoveCondition(crStatus, ansiblestatus.MissingPermissionsConditionType)
+		return
+	}
+	c := ansiblestatus.NewCondition(
+		ansiblestatus.MissingPermissionsConditionType,
+		v1.ConditionTrue,
+		nil,
+		ansiblestatus.MissingPermissionsReason,
+		rbacutil.MissingPermissionsMessage(perms),
+	)
+	// SetCondition ignores conditions with an unchanged reason, so replace
+	// the condition when the permissions differ.
+	if cur := ansiblestatus.GetCondition(*crStatus, c.Type); cur != nil && cur.Message != c.Message {
+		c.LastTransitionTime = cur.LastTransitionTime
+		ansiblestatus.RemoveCondition(crStatus, c.Type)
+	}
+	ansiblestatus.SetCondition(crStatus, *c)
+}
+
+// isStale returns true if status computed from generation of u should not be
+// written, because u has since been updated to a newer generation or its
+// status was written from a newer generation.
+func isStale(u *unstructured.Unstructured, crStatus ansiblestatus.Status, generation int64) bool {
+	return u.GetGeneration() > generation || crStatus.ObservedGeneration > generation
+}
+
 func contains(l []string, s string) bool {
 	for _, elem := range l {
 		if elem == s {
@@ -600,3 +600,93 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileObservedGeneration(t *testing.T) {
+	gvk := schema.GroupVersionKind{
+		Kind:    "Testing",
+		Group:   "operator-sdk",
+		Version: "v1beta1",
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "reconcile",
+			Namespace: "default",
+		},
+	}
+	newClient := func() client.Client {
+		return fakeclient.NewFakeClient(&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":       "reconcile",
+					"namespace":  "default",
+					"generation": int64(1),
+				},
+				"apiVersion": "operator-sdk/v1beta1",
+				"kind":       "Testing",
+				"spec":       map[string]interface{}{},
+			},
+		})
+	}
+	newRunner := func(onRun func(*unstructured.Unstructured)) *fake.Runner {
+		return &fake.Runner{
+			JobEvents: []eventapi.JobEvent{
+				{
+					Event:   eventapi.EventPlaybookOnStats,
+					Created: eventapi.EventTime{Time: time.Now()},
+				},
+			},
+			OnRun: onRun,
+		}
+	}
+	reconcileStatus := func(t *testing.T, c client.Client, r runner.Runner) ansiblestatus.Status {
+		aor := &controller.AnsibleOperatorReconciler{
+			GVK:          gvk,
+			Runner:       r,
+			Client:       c,
+			APIReader:    c,
+			ManageStatus: true,
+		}
+		if _, err := aor.Reconcile(request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		if err := c.Get(context.TODO(), request.NamespacedName, u); err != nil {
+			t.Fatalf("Failed to get object: (%v)", err)
+		}
+		sMap, _ := u.Object["status"].(map[string]interface{})
+		return ansiblestatus.CreateFromMap(sMap)
+	}
+
+	t.Run("records the reconciled generation", func(t *testing.T) {
+		status := reconcileStatus(t, newClient(), newRunner(nil))
+		if status.ObservedGeneration != 1 {
+			t.Fatalf("Observed generation does not equal\nexpected: 1\nactual: %d", status.ObservedGeneration)
+		}
+		cond := ansiblestatus.GetCondition(status, ansiblestatus.RunningConditionType)
+		if cond == nil || cond.Reason != ansiblestatus.SuccessfulReason {
+			t.Fatalf("Expected a successful running condition, got: %v", cond)
+		}
+	})
+
+	t.Run("skips status computed from an older generation", func(t *testing.T) {
+		c := newClient()
+		status := reconcileStatus(t, c, newRunner(func(u *unstructured.Unstructured) {
+			latest := u.DeepCopy()
+			if err := c.Get(context.TODO(), request.NamespacedName, latest); err != nil {
+				t.Fatalf("Failed to get object: (%v)", err)
+			}
+			latest.SetGeneration(2)
+			if err := c.Update(context.TODO(), latest); err != nil {
+				t.Fatalf("Failed to update object: (%v)", err)
+			}
+		}))
+		if status.ObservedGeneration != 0 {
+			t.Fatalf("Observed generation does not equal\nexpected: 0\nactual: %d", status.ObservedGeneration)
+		}
+		cond := ansiblestatus.GetCondition(status, ansiblestatus.RunningConditionType)
+		if cond == nil || cond.Reason != ansiblestatus.RunningReason {
+			t.Fatalf("Expected the running condition to be unchanged, got: %v", cond)
+		}
+	})
+}
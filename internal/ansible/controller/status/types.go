@@ -93,6 +93,9 @@ const (
 	RunningConditionType ConditionType = "Running"
 	// FailureConditionType - condition type of failure.
 	FailureConditionType ConditionType = "Failure"
+	// MissingPermissionsConditionType - condition type of permissions denied
+	// to the last reconciliation.
+	MissingPermissionsConditionType ConditionType = "MissingPermissions"
 )
 
 // Condition - the condition for the ansible operator.
@@ -103,6 +106,10 @@ type Condition struct {
 	AnsibleResult      *AnsibleResult     `json:"ansibleResult,omitempty"`
 	Reason             string             `json:"reason"`
 	Message            string             `json:"message"`
+	// ObservedGeneration is the generation of the custom resource that the
+	// condition was set for. It is only set by operators run with standard
+	// conditions.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 func createConditionFromMap(cm map[string]interface{}) Condition {
@@ -146,26 +153,51 @@ func createConditionFromMap(cm map[string]interface{}) Condition {
 		Reason:             reason,
 		Message:            message,
 		AnsibleResult:      ansibleResult,
+		ObservedGeneration: parseGeneration(cm["observedGeneration"]),
 	}
 }
 
+// parseGeneration returns the generation in v, an observedGeneration field of
+// a status map.
+func parseGeneration(v interface{}) int64 {
+	switch g := v.(type) {
+	case nil:
+	case int64:
+		return g
+	case float64:
+		// Statuses set from GetJSONMap hold JSON numbers.
+		return int64(g)
+	default:
+		log.Info("Unable to parse observed generation", "ObservedGeneration", g)
+	}
+	return 0
+}
+
 // Status - The status for custom resources managed by the operator-sdk.
 type Status struct {
-	Conditions   []Condition            `json:"conditions"`
-	CustomStatus map[string]interface{} `json:"-"`
+	Conditions []Condition `json:"conditions"`
+	// ObservedGeneration is the generation of the custom resource that the
+	// conditions were computed from.
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+	CustomStatus       map[string]interface{} `json:"-"`
+
+	// set is the types of the conditions set on the status since it was
+	// read from the custom resource.
+	set map[ConditionType]bool
 }
 
 // CreateFromMap - create a status from the map
 func CreateFromMap(statusMap map[string]interface{}) Status {
 	customStatus := make(map[string]interface{})
 	for key, value := range statusMap {
-		if key != "conditions" {
+		if key != "conditions" && key != "observedGeneration" {
 			customStatus[key] = value
 		}
 	}
+	observedGeneration := parseGeneration(statusMap["observedGeneration"])
 	conditionsInterface, ok := statusMap["conditions"].([]interface{})
 	if !ok {
-		return Status{Conditions: []Condition{}, CustomStatus: customStatus}
+		return Status{Conditions: []Condition{}, ObservedGeneration: observedGeneration, CustomStatus: customStatus}
 	}
 	conditions := []Condition{}
 	for _, ci := range conditionsInterface {
@@ -176,7 +208,7 @@ func CreateFromMap(statusMap map[string]interface{}) Status {
 		}
 		conditions = append(conditions, createConditionFromMap(cm))
 	}
-	return Status{Conditions: conditions, CustomStatus: customStatus}
+	return Status{Conditions: conditions, ObservedGeneration: observedGeneration, CustomStatus: customStatus}
 }
 
 // GetJSONMap - gets the map value for the status object.
@@ -0,0 +1,120 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
+)
+
+func TestCreateFromMapObservedGeneration(t *testing.T) {
+	status := CreateFromMap(map[string]interface{}{
+		"observedGeneration": int64(3),
+		"custom":             "value",
+	})
+	if status.ObservedGeneration != 3 {
+		t.Fatalf("Observed generation does not equal\nexpected: 3\nactual: %d", status.ObservedGeneration)
+	}
+	if !reflect.DeepEqual(status.CustomStatus, map[string]interface{}{"custom": "value"}) {
+		t.Fatalf("Custom status does not equal\nexpected: %v\nactual: %v",
+			map[string]interface{}{"custom": "value"}, status.CustomStatus)
+	}
+
+	m := status.GetJSONMap()
+	if m["observedGeneration"] != float64(3) {
+		t.Fatalf("Observed generation was not set on the status map: %v", m)
+	}
+}
+
+func TestStandardizeConditions(t *testing.T) {
+	status := CreateFromMap(map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":               string(RunningConditionType),
+				"status":             "True",
+				"reason":             SuccessfulReason,
+				"observedGeneration": float64(1),
+			},
+			map[string]interface{}{
+				"type":               string(MissingPermissionsConditionType),
+				"status":             "True",
+				"reason":             "",
+				"observedGeneration": float64(1),
+			},
+		},
+	})
+	if status.Conditions[0].ObservedGeneration != 1 {
+		t.Fatalf("Condition observed generation does not equal\nexpected: 1\nactual: %d",
+			status.Conditions[0].ObservedGeneration)
+	}
+
+	SetCondition(&status, *NewCondition(FailureConditionType, v1.ConditionTrue, nil, FailedReason, "failed"))
+	StandardizeConditions(&status, 2)
+	m := status.GetJSONMap()
+	conditions := m["conditions"].([]interface{})
+	if len(conditions) != 3 {
+		t.Fatalf("Unexpected conditions: %v", conditions)
+	}
+
+	testCases := []struct {
+		index              int
+		expectedGeneration float64
+		expectedReason     string
+	}{
+		// Conditions not set since the status was read keep their generation.
+		{index: 0, expectedGeneration: 1, expectedReason: SuccessfulReason},
+		// Conditions without a reason are given the default reason.
+		{index: 1, expectedGeneration: 1, expectedReason: k8sutil.DefaultConditionReason},
+		// Conditions set since the status was read record the generation.
+		{index: 2, expectedGeneration: 2, expectedReason: FailedReason},
+	}
+	for _, tc := range testCases {
+		c := conditions[tc.index].(map[string]interface{})
+		if c["observedGeneration"] != tc.expectedGeneration {
+			t.Errorf("Observed generation of condition %d does not equal\nexpected: %v\nactual: %v",
+				tc.index, tc.expectedGeneration, c["observedGeneration"])
+		}
+		if c["reason"] != tc.expectedReason {
+			t.Errorf("Reason of condition %d does not equal\nexpected: %v\nactual: %v",
+				tc.index, tc.expectedReason, c["reason"])
+		}
+	}
+}
+
+func TestStandardizeConditionsUnchanged(t *testing.T) {
+	status := CreateFromMap(map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":               string(RunningConditionType),
+				"status":             "True",
+				"reason":             RunningReason,
+				"observedGeneration": float64(1),
+			},
+		},
+	})
+
+	// A condition set again without changes was still observed at the new
+	// generation.
+	SetCondition(&status, *NewCondition(RunningConditionType, v1.ConditionTrue, nil, RunningReason, RunningMessage))
+	StandardizeConditions(&status, 2)
+	if status.Conditions[0].ObservedGeneration != 2 {
+		t.Fatalf("Condition observed generation does not equal\nexpected: 2\nactual: %d",
+			status.Conditions[0].ObservedGeneration)
+	}
+}
@@ -17,6 +17,8 @@ package status
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
 )
 
 const (
@@ -28,6 +30,8 @@ const (
 	FailedReason = "Failed"
 	// UnknownFailedReason - Condition is unknown
 	UnknownFailedReason = "Unknown"
+	// MissingPermissionsReason - Condition is set due to requests forbidden by RBAC
+	MissingPermissionsReason = "MissingPermissions"
 )
 
 const (
@@ -64,13 +68,22 @@ func GetCondition(status Status, condType ConditionType) *Condition {
 // SetCondition updates the scheduledReport to include the provided condition. If the condition that
 // we are about to add already exists and has the same status and reason then we are not going to update.
 func SetCondition(status *Status, condition Condition) {
+	if status.set == nil {
+		status.set = map[ConditionType]bool{}
+	}
+	status.set[condition.Type] = true
 	currentCond := GetCondition(*status, condition.Type)
 	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason {
 		return
 	}
-	// Do not update lastTransitionTime if the status of the condition doesn't change.
-	if currentCond != nil && currentCond.Status == condition.Status {
-		condition.LastTransitionTime = currentCond.LastTransitionTime
+	// Do not update lastTransitionTime if the status of the condition doesn't change,
+	// and record the transition if it does.
+	if currentCond != nil {
+		if currentCond.Status == condition.Status {
+			condition.LastTransitionTime = currentCond.LastTransitionTime
+		} else {
+			condition.LastTransitionTime = metav1.Now()
+		}
 	}
 	newConditions := filterOutCondition(status.Conditions, condition.Type)
 	status.Conditions = append(newConditions, condition)
@@ -81,6 +94,21 @@ func RemoveCondition(status *Status, condType ConditionType) {
 	status.Conditions = filterOutCondition(status.Conditions, condType)
 }
 
+// StandardizeConditions makes the conditions of status follow the Kubernetes
+// API conventions of metav1.Condition: conditions set since status was read
+// record generation as their observedGeneration, and conditions without a
+// reason are given k8sutil.DefaultConditionReason.
+func StandardizeConditions(status *Status, generation int64) {
+	for i := range status.Conditions {
+		if status.set[status.Conditions[i].Type] {
+			status.Conditions[i].ObservedGeneration = generation
+		}
+		if status.Conditions[i].Reason == "" {
+			status.Conditions[i].Reason = k8sutil.DefaultConditionReason
+		}
+	}
+}
+
 // filterOutCondition returns a new slice of scheduledReport conditions without conditions with the provided type.
 func filterOutCondition(conditions []Condition, condType ConditionType) []Condition {
 	var newConditions []Condition
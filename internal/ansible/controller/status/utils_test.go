@@ -17,6 +17,7 @@ package status
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -225,6 +226,7 @@ func TestRemoveCondition(t *testing.T) {
 
 func TestSetCondition(t *testing.T) {
 	lastTransitionTime := metav1.Now()
+	earlier := metav1.NewTime(lastTransitionTime.Add(-time.Hour))
 	keeptMessage := SuccessfulMessage
 	testCases := []struct {
 		name                   string
@@ -233,6 +235,7 @@ func TestSetCondition(t *testing.T) {
 		expectedNewSize        int
 		keepLastTransitionTime bool
 		keepMessage            bool
+		flipStatus             bool
 	}{
 		{
 			name: "add new condition",
@@ -278,6 +281,29 @@ func TestSetCondition(t *testing.T) {
 			keepLastTransitionTime: true,
 			keepMessage:            true,
 		},
+		{
+			name: "update failure condition status",
+			status: &Status{
+				Conditions: []Condition{
+					Condition{
+						Type:               FailureConditionType,
+						Status:             v1.ConditionTrue,
+						Reason:             FailedReason,
+						Message:            "failed",
+						LastTransitionTime: earlier,
+					},
+				},
+			},
+			condition: &Condition{
+				Type:               FailureConditionType,
+				Status:             v1.ConditionFalse,
+				Reason:             FailedReason,
+				Message:            "failed",
+				LastTransitionTime: earlier,
+			},
+			expectedNewSize: 1,
+			flipStatus:      true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -294,6 +320,12 @@ func TestSetCondition(t *testing.T) {
 				tc.condition.Message = keeptMessage
 			}
 			ac := GetCondition(*tc.status, tc.condition.Type)
+			if tc.flipStatus {
+				if !earlier.Before(&ac.LastTransitionTime) {
+					t.Fatalf("Last transition time was not updated: %v", ac.LastTransitionTime)
+				}
+				tc.condition.LastTransitionTime = ac.LastTransitionTime
+			}
 			if !reflect.DeepEqual(ac, tc.condition) {
 				t.Fatalf("Condition did not match expected:\nActual: %#v\nExpected: %#v", ac, tc.condition)
 			}
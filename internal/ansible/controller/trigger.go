@@ -0,0 +1,158 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/operator-framework/operator-sdk/internal/ansible/events"
+	"github.com/operator-framework/operator-sdk/internal/ansible/predicate"
+	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/kubeconfig"
+	"github.com/operator-framework/operator-sdk/internal/ansible/runner"
+	"github.com/operator-framework/operator-sdk/internal/ansible/runner/eventapi"
+	"github.com/operator-framework/operator-sdk/internal/ansible/watches"
+)
+
+// EventTriggerOptions - options for an event trigger controller
+type EventTriggerOptions struct {
+	Trigger          watches.EventTrigger
+	Runner           runner.Runner
+	EventHandlers    []events.EventHandler
+	LoggingLevel     events.LogLevel
+	AnsibleDebugLogs bool
+}
+
+// AddEventTrigger - Creates a controller that runs an event trigger's playbook
+// for each matching Kubernetes Event and adds it to the manager
+func AddEventTrigger(mgr manager.Manager, options EventTriggerOptions) error {
+	log.Info("Watching events", "Trigger", options.Trigger.Name, "Reason", options.Trigger.Reason,
+		"Type", options.Trigger.Type, "InvolvedObjectKind", options.Trigger.InvolvedObjectKind)
+	eventHandlers := append(options.EventHandlers, events.NewLoggingEventHandler(options.LoggingLevel))
+
+	r := &eventTriggerReconciler{
+		Trigger:          options.Trigger,
+		Runner:           options.Runner,
+		Client:           mgr.GetClient(),
+		EventHandlers:    eventHandlers,
+		AnsibleDebugLogs: options.AnsibleDebugLogs,
+	}
+
+	c, err := controller.New(fmt.Sprintf("%s-event-trigger", strings.ToLower(options.Trigger.Name)), mgr,
+		controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	return c.Watch(&source.Kind{Type: &corev1.Event{}}, &handler.EnqueueRequestForObject{},
+		predicate.NewEventTriggerPredicate(options.Trigger))
+}
+
+// eventTriggerReconciler - runs an event trigger's playbook for an Event
+type eventTriggerReconciler struct {
+	Trigger          watches.EventTrigger
+	Runner           runner.Runner
+	Client           client.Client
+	EventHandlers    []events.EventHandler
+	AnsibleDebugLogs bool
+}
+
+// Reconcile - runs the trigger's playbook once for the Event. Failed runs are
+// logged but not requeued, so a failing playbook is rerun only when the Event recurs.
+func (r *eventTriggerReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ev := &corev1.Event{}
+	err := r.Client.Get(context.TODO(), request.NamespacedName, ev)
+	if apierrors.IsNotFound(err) {
+		return reconcile.Result{}, nil
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ev)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Event"))
+
+	ident := strconv.Itoa(rand.Int())
+	logger := logf.Log.WithName("event-trigger").WithValues(
+		"job", ident,
+		"trigger", r.Trigger.Name,
+		"name", u.GetName(),
+		"namespace", u.GetNamespace(),
+	)
+
+	// Objects created by trigger playbooks are not owned by the Event.
+	kc, err := kubeconfig.CreateWithoutOwner("http://localhost:8888", u.GetNamespace())
+	if err != nil {
+		logger.Error(err, "Unable to generate kubeconfig")
+		return reconcile.Result{}, err
+	}
+	defer func() {
+		if err := os.Remove(kc.Name()); err != nil {
+			logger.Error(err, "Failed to remove generated kubeconfig file")
+		}
+	}()
+
+	logger.Info("Running event trigger")
+	result, err := r.Runner.Run(ident, u, kc.Name())
+	if err != nil {
+		logger.Error(err, "Unable to run ansible runner")
+		return reconcile.Result{}, err
+	}
+
+	statusEvent := eventapi.StatusJobEvent{}
+	failureMessages := eventapi.FailureMessages{}
+	for event := range result.Events() {
+		for _, eHandler := range r.EventHandlers {
+			go eHandler.Handle(ident, u, event)
+		}
+		if event.Event == eventapi.EventPlaybookOnStats {
+			statusEvent.StdOut = event.StdOut
+		}
+		if event.Event == eventapi.EventRunnerOnFailed && !event.IgnoreError() && !event.Rescued() {
+			failureMessages = append(failureMessages, event.GetFailedPlaybookMessage())
+		}
+	}
+	printEventStats(statusEvent)
+	if r.AnsibleDebugLogs {
+		if res, err := result.Stdout(); err == nil && len(res) > 0 {
+			fmt.Printf("\n--------------------------- Ansible Debug Result -----------------------------\n")
+			fmt.Println(res)
+			fmt.Printf("\n-------------------------------------------------------------------------------\n")
+		}
+	}
+
+	if len(failureMessages) != 0 {
+		logger.Error(fmt.Errorf("%s", strings.Join(failureMessages, "; ")), "Event trigger playbook failed")
+	}
+	return reconcile.Result{}, nil
+}
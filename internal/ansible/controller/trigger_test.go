@@ -0,0 +1,150 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/operator-framework/operator-sdk/internal/ansible/runner/eventapi"
+	"github.com/operator-framework/operator-sdk/internal/ansible/runner/fake"
+	"github.com/operator-framework/operator-sdk/internal/ansible/watches"
+)
+
+func TestEventTriggerReconcile(t *testing.T) {
+	ev := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "Node",
+			Name: "worker-0",
+			UID:  types.UID("0f7c7c1e"),
+		},
+		Reason:  "NodeNotReady",
+		Type:    corev1.EventTypeWarning,
+		Message: "Node worker-0 status is now: NodeNotReady",
+		Count:   2,
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: ev.Name, Namespace: ev.Namespace}}
+	trigger := watches.EventTrigger{
+		Name:   "node-not-ready",
+		Reason: "NodeNotReady",
+		EventVars: map[string]string{
+			"node_name": "involvedObject.name",
+			"node_kind": "involvedObject.kind",
+			"node_uid":  "involvedObject.uid",
+			"reason":    "reason",
+			"message":   "message",
+		},
+	}
+
+	t.Run("passes the event to the runner", func(t *testing.T) {
+		var got *unstructured.Unstructured
+		r := &eventTriggerReconciler{
+			Trigger: trigger,
+			Runner: &fake.Runner{
+				JobEvents: []eventapi.JobEvent{{Event: eventapi.EventPlaybookOnStats}},
+				OnRun:     func(u *unstructured.Unstructured) { got = u.DeepCopy() },
+			},
+			Client: fakeclient.NewFakeClient(ev.DeepCopy()),
+		}
+		result, err := r.Reconcile(request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != (reconcile.Result{}) {
+			t.Fatalf("Unexpected result %+v", result)
+		}
+		if got == nil {
+			t.Fatalf("Runner was not run")
+		}
+		if gvk := got.GroupVersionKind(); gvk != corev1.SchemeGroupVersion.WithKind("Event") {
+			t.Fatalf("Unexpected GVK %v", gvk)
+		}
+		if got.GetName() != ev.Name || got.GetNamespace() != ev.Namespace {
+			t.Fatalf("Unexpected event %s/%s", got.GetNamespace(), got.GetName())
+		}
+		// Each event var's path must resolve against the object the runner receives.
+		expected := map[string]string{
+			"node_name": "worker-0",
+			"node_kind": "Node",
+			"node_uid":  "0f7c7c1e",
+			"reason":    ev.Reason,
+			"message":   ev.Message,
+		}
+		for k, path := range trigger.EventVars {
+			v, found, err := unstructured.NestedString(got.Object, strings.Split(path, ".")...)
+			if err != nil || !found {
+				t.Fatalf("Event var %s at %s was not found: %v", k, path, err)
+			}
+			if v != expected[k] {
+				t.Fatalf("Unexpected event var %s %q, expected %q", k, v, expected[k])
+			}
+		}
+	})
+
+	t.Run("event not found", func(t *testing.T) {
+		ran := false
+		r := &eventTriggerReconciler{
+			Trigger: trigger,
+			Runner:  &fake.Runner{OnRun: func(*unstructured.Unstructured) { ran = true }},
+			Client:  fakeclient.NewFakeClient(),
+		}
+		if _, err := r.Reconcile(request); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if ran {
+			t.Fatalf("Runner was unexpectedly run")
+		}
+	})
+
+	t.Run("runner error", func(t *testing.T) {
+		r := &eventTriggerReconciler{
+			Trigger: trigger,
+			Runner:  &fake.Runner{Error: errors.New("runner failed")},
+			Client:  fakeclient.NewFakeClient(ev.DeepCopy()),
+		}
+		if _, err := r.Reconcile(request); err == nil {
+			t.Fatalf("Expected error")
+		}
+	})
+
+	t.Run("failed playbook is not requeued", func(t *testing.T) {
+		r := &eventTriggerReconciler{
+			Trigger: trigger,
+			Runner: &fake.Runner{
+				JobEvents: []eventapi.JobEvent{{
+					Event:     eventapi.EventRunnerOnFailed,
+					EventData: map[string]interface{}{"res": map[string]interface{}{"msg": "task failed"}},
+				}},
+			},
+			Client: fakeclient.NewFakeClient(ev.DeepCopy()),
+		}
+		result, err := r.Reconcile(request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != (reconcile.Result{}) {
+			t.Fatalf("Unexpected result %+v", result)
+		}
+	})
+}
@@ -23,18 +23,30 @@ import (
 
 // Flags - Options to be used by an ansible operator
 type Flags struct {
-	ReconcilePeriod         time.Duration
-	WatchesFile             string
-	InjectOwnerRef          bool
-	EnableLeaderElection    bool
-	MaxConcurrentReconciles int
-	AnsibleVerbosity        int
-	AnsibleRolesPath        string
-	AnsibleCollectionsPath  string
-	MetricsAddress          string
-	LeaderElectionID        string
-	LeaderElectionNamespace string
-	AnsibleArgs             string
+	ReconcilePeriod            time.Duration
+	WatchesFile                string
+	InjectOwnerRef             bool
+	EnableLeaderElection       bool
+	MaxConcurrentReconciles    int
+	AnsibleVerbosity           int
+	AnsibleRolesPath           string
+	AnsibleCollectionsPath     string
+	MetricsAddress             string
+	MetricsCertFile            string
+	MetricsKeyFile             string
+	MetricsClientCAFile        string
+	MetricsRequireRBAC         bool
+	LeaderElectionID           string
+	LeaderElectionNamespace    string
+	AnsibleArgs                string
+	DependentEventDebounce     time.Duration
+	ProxyMaxIdleConns          int
+	ProxyMaxIdleConnsPerHost   int
+	ProxyIdleConnTimeout       time.Duration
+	ProxyResponseHeaderTimeout time.Duration
+	ProxyMaxRequestBodySize    int64
+	ProxyDisableHTTP2          bool
+	StandardConditions         bool
 }
 
 const AnsibleRolesPathEnvVar = "ANSIBLE_ROLES_PATH"
@@ -82,6 +94,30 @@ func (f *Flags) AddTo(flagSet *pflag.FlagSet) {
 		":8080",
 		"The address the metric endpoint binds to",
 	)
+	flagSet.StringVar(&f.MetricsCertFile,
+		"metrics-cert-file",
+		"",
+		"Path to a TLS certificate with which metrics are served over HTTPS. Requires --metrics-key-file. "+
+			"If empty, metrics are served over HTTP, or over HTTPS with a self-signed certificate if "+
+			"--metrics-client-ca-file or --metrics-require-rbac is set.",
+	)
+	flagSet.StringVar(&f.MetricsKeyFile,
+		"metrics-key-file",
+		"",
+		"Path to the key of the TLS certificate set by --metrics-cert-file.",
+	)
+	flagSet.StringVar(&f.MetricsClientCAFile,
+		"metrics-client-ca-file",
+		"",
+		"Path to a bundle of CA certificates. If set, metrics clients must present a certificate signed by "+
+			"one of them, unless --metrics-require-rbac is set and they present a bearer token.",
+	)
+	flagSet.BoolVar(&f.MetricsRequireRBAC,
+		"metrics-require-rbac",
+		false,
+		"Authenticate metrics requests by client certificate or bearer token, and authorize them with a "+
+			"SubjectAccessReview of the request path, as kube-rbac-proxy does.",
+	)
 	flagSet.BoolVar(&f.EnableLeaderElection,
 		"enable-leader-election",
 		false,
@@ -105,4 +141,48 @@ func (f *Flags) AddTo(flagSet *pflag.FlagSet) {
 		"",
 		"Ansible args. Allows user to specify arbitrary arguments for ansible-based operators.",
 	)
+	flagSet.DurationVar(&f.DependentEventDebounce,
+		"dependent-event-debounce",
+		0,
+		"Delay reconciles triggered by dependent resource events by this duration, coalescing bursts of"+
+			" events for the same custom resource into a single reconcile. Disabled if 0.",
+	)
+	flagSet.IntVar(&f.ProxyMaxIdleConns,
+		"proxy-max-idle-conns",
+		100,
+		"Maximum number of idle connections from the Ansible proxy to the API server. Unlimited if 0.",
+	)
+	flagSet.IntVar(&f.ProxyMaxIdleConnsPerHost,
+		"proxy-max-idle-conns-per-host",
+		25,
+		"Maximum number of idle connections from the Ansible proxy to each API server host.",
+	)
+	flagSet.DurationVar(&f.ProxyIdleConnTimeout,
+		"proxy-idle-conn-timeout",
+		90*time.Second,
+		"How long an idle connection from the Ansible proxy to the API server is kept open. Unlimited if 0.",
+	)
+	flagSet.DurationVar(&f.ProxyResponseHeaderTimeout,
+		"proxy-response-header-timeout",
+		0,
+		"How long the Ansible proxy waits for the API server's response headers after sending a request."+
+			" Unlimited if 0.",
+	)
+	flagSet.Int64Var(&f.ProxyMaxRequestBodySize,
+		"proxy-max-request-body-size",
+		0,
+		"Maximum size in bytes of a request body sent to the Ansible proxy. Larger requests are rejected."+
+			" Unlimited if 0.",
+	)
+	flagSet.BoolVar(&f.ProxyDisableHTTP2,
+		"proxy-disable-http2",
+		false,
+		"Disable HTTP/2 from the Ansible proxy to the API server.",
+	)
+	flagSet.BoolVar(&f.StandardConditions,
+		"standard-conditions",
+		false,
+		"Set status conditions that follow the Kubernetes API conventions of metav1.Condition, with an"+
+			" observedGeneration on every condition.",
+	)
 }
@@ -0,0 +1,79 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	crhandler "sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+// DebouncedEventHandler delays requests enqueued by Handler by Window. The
+// workqueue holds at most one delayed request per key, so a burst of events
+// for dependents of the same owner within Window results in a single reconcile
+// of the owner instead of one per event.
+type DebouncedEventHandler struct {
+	Handler crhandler.EventHandler
+	Window  time.Duration
+}
+
+var _ crhandler.EventHandler = DebouncedEventHandler{}
+
+// NewDebouncedEventHandler returns h wrapped in a DebouncedEventHandler, or h
+// itself if window is not positive.
+func NewDebouncedEventHandler(h crhandler.EventHandler, window time.Duration) crhandler.EventHandler {
+	if window <= 0 {
+		return h
+	}
+	return DebouncedEventHandler{Handler: h, Window: window}
+}
+
+// Create implements EventHandler.
+func (h DebouncedEventHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.Handler.Create(e, h.queue(q))
+}
+
+// Update implements EventHandler.
+func (h DebouncedEventHandler) Update(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.Handler.Update(e, h.queue(q))
+}
+
+// Delete implements EventHandler.
+func (h DebouncedEventHandler) Delete(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.Handler.Delete(e, h.queue(q))
+}
+
+// Generic implements EventHandler.
+func (h DebouncedEventHandler) Generic(e event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.Handler.Generic(e, h.queue(q))
+}
+
+func (h DebouncedEventHandler) queue(q workqueue.RateLimitingInterface) workqueue.RateLimitingInterface {
+	return debounceQueue{RateLimitingInterface: q, window: h.Window}
+}
+
+// debounceQueue delays all items added to it by window.
+type debounceQueue struct {
+	workqueue.RateLimitingInterface
+	window time.Duration
+}
+
+// Add adds item to the queue once window has elapsed. Items already waiting
+// are not added again, which coalesces bursts of events for the same item.
+func (q debounceQueue) Add(item interface{}) {
+	q.RateLimitingInterface.AddAfter(item, q.window)
+}
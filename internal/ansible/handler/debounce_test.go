@@ -0,0 +1,58 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	crhandler "sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+func TestNewDebouncedEventHandler(t *testing.T) {
+	h := &crhandler.EnqueueRequestForObject{}
+	if got := NewDebouncedEventHandler(h, 0); got != h {
+		t.Errorf("expected handler to be returned unwrapped for a zero window, got %#v", got)
+	}
+	if _, ok := NewDebouncedEventHandler(h, time.Second).(DebouncedEventHandler); !ok {
+		t.Errorf("expected handler to be wrapped for a positive window")
+	}
+}
+
+func TestDebouncedEventHandlerCoalescesEvents(t *testing.T) {
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	window := 100 * time.Millisecond
+	h := NewDebouncedEventHandler(&crhandler.EnqueueRequestForObject{}, window)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"}}
+	for i := 0; i < 5; i++ {
+		h.Create(event.CreateEvent{Meta: pod, Object: pod}, q)
+		h.Update(event.UpdateEvent{MetaOld: pod, ObjectOld: pod, MetaNew: pod, ObjectNew: pod}, q)
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expected no requests before the debounce window elapsed, got %d", q.Len())
+	}
+
+	time.Sleep(3 * window)
+	if q.Len() != 1 {
+		t.Fatalf("expected a single request after the debounce window elapsed, got %d", q.Len())
+	}
+}
@@ -47,11 +47,25 @@ var (
 		[]string{
 			"GVK",
 		})
+
+	missingPermissions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "missing_permissions",
+			Help:      "Counter of reconciles denied a permission by the API server.",
+		},
+		[]string{
+			"GVK",
+			"verb",
+			"group",
+			"resource",
+		})
 )
 
 func init() {
 	metrics.Registry.MustRegister(reconcileResults)
 	metrics.Registry.MustRegister(reconciles)
+	metrics.Registry.MustRegister(missingPermissions)
 }
 
 // We will never want to panic our app because of metric saving.
@@ -81,3 +95,10 @@ func ReconcileTimer(gvk string) *prometheus.Timer {
 		reconciles.WithLabelValues(gvk).Observe(duration)
 	}))
 }
+
+// MissingPermission counts a reconcile of a resource of gvk that was denied
+// verb on resource in group.
+func MissingPermission(gvk, verb, group, resource string) {
+	defer recoverMetricPanic()
+	missingPermissions.WithLabelValues(gvk, verb, group, resource).Inc()
+}
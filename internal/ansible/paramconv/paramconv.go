@@ -203,3 +203,9 @@ func MapToSnake(in map[string]interface{}) map[string]interface{} {
 func MapToCamel(in map[string]interface{}) map[string]interface{} {
 	return convertMapKeys(ToCamel, in)
 }
+
+// ValueToSnake converts the keys of the maps in v, including maps nested in
+// arrays, to snake_case.
+func ValueToSnake(v interface{}) interface{} {
+	return convertParameter(ToSnake, v)
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/operator-framework/operator-sdk/internal/ansible/watches"
+)
+
+type eventTriggerPredicate struct {
+	predicate.Funcs
+	Trigger watches.EventTrigger
+	// Since is the time the predicate was created. Events last seen before it
+	// were already handled by a previous operator process, and are skipped.
+	Since time.Time
+}
+
+// NewEventTriggerPredicate returns a predicate that passes Kubernetes Events
+// matching trigger when they are created or recur.
+func NewEventTriggerPredicate(trigger watches.EventTrigger) predicate.Predicate {
+	return eventTriggerPredicate{Trigger: trigger, Since: time.Now()}
+}
+
+func (p eventTriggerPredicate) Create(e event.CreateEvent) bool {
+	ev, ok := toEvent(e.Object)
+	return ok && p.matches(ev) && !lastSeen(ev).Before(p.Since)
+}
+
+// Update passes recurrences of an Event, which increment its count.
+func (p eventTriggerPredicate) Update(e event.UpdateEvent) bool {
+	oldEv, ok := toEvent(e.ObjectOld)
+	if !ok {
+		return false
+	}
+	ev, ok := toEvent(e.ObjectNew)
+	return ok && p.matches(ev) && eventCount(ev) > eventCount(oldEv)
+}
+
+func (p eventTriggerPredicate) Delete(event.DeleteEvent) bool   { return false }
+func (p eventTriggerPredicate) Generic(event.GenericEvent) bool { return false }
+
+// matches returns true if ev matches each of p's trigger fields that is set.
+func (p eventTriggerPredicate) matches(ev *corev1.Event) bool {
+	t := p.Trigger
+	return (t.Reason == "" || t.Reason == ev.Reason) &&
+		(t.Type == "" || t.Type == ev.Type) &&
+		(t.InvolvedObjectKind == "" || t.InvolvedObjectKind == ev.InvolvedObject.Kind)
+}
+
+func toEvent(obj runtime.Object) (*corev1.Event, bool) {
+	ev, ok := obj.(*corev1.Event)
+	return ev, ok && ev != nil
+}
+
+// lastSeen returns the last time ev occurred.
+func lastSeen(ev *corev1.Event) time.Time {
+	if ev.Series != nil {
+		return ev.Series.LastObservedTime.Time
+	}
+	if !ev.LastTimestamp.IsZero() {
+		return ev.LastTimestamp.Time
+	}
+	if !ev.EventTime.IsZero() {
+		return ev.EventTime.Time
+	}
+	return ev.CreationTimestamp.Time
+}
+
+// eventCount returns the number of times ev occurred.
+func eventCount(ev *corev1.Event) int32 {
+	if ev.Series != nil {
+		return ev.Series.Count
+	}
+	return ev.Count
+}
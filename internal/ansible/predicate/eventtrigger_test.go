@@ -0,0 +1,243 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package predicate
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/operator-framework/operator-sdk/internal/ansible/watches"
+)
+
+var (
+	since  = time.Date(2020, time.October, 1, 12, 0, 0, 0, time.UTC)
+	before = metav1.NewTime(since.Add(-time.Minute))
+	after  = metav1.NewTime(since.Add(time.Minute))
+)
+
+func newEvent(reason, eventType, kind string, count int32, last metav1.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "worker-0.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: "worker-0"},
+		Reason:         reason,
+		Type:           eventType,
+		Count:          count,
+		LastTimestamp:  last,
+	}
+}
+
+func TestEventTriggerPredicateCreate(t *testing.T) {
+	trigger := watches.EventTrigger{
+		Name:               "node-not-ready",
+		Reason:             "NodeNotReady",
+		Type:               corev1.EventTypeWarning,
+		InvolvedObjectKind: "Node",
+	}
+	testCases := []struct {
+		name     string
+		trigger  watches.EventTrigger
+		object   runtime.Object
+		expected bool
+	}{
+		{
+			name:     "matching event",
+			trigger:  trigger,
+			object:   newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 1, after),
+			expected: true,
+		},
+		{
+			name:     "reason does not match",
+			trigger:  trigger,
+			object:   newEvent("NodeReady", corev1.EventTypeWarning, "Node", 1, after),
+			expected: false,
+		},
+		{
+			name:     "type does not match",
+			trigger:  trigger,
+			object:   newEvent("NodeNotReady", corev1.EventTypeNormal, "Node", 1, after),
+			expected: false,
+		},
+		{
+			name:     "involved object kind does not match",
+			trigger:  trigger,
+			object:   newEvent("NodeNotReady", corev1.EventTypeWarning, "Pod", 1, after),
+			expected: false,
+		},
+		{
+			name:     "unset trigger fields match any event",
+			trigger:  watches.EventTrigger{Name: "any"},
+			object:   newEvent("BackOff", corev1.EventTypeNormal, "Pod", 1, after),
+			expected: true,
+		},
+		{
+			name:     "event last seen before startup",
+			trigger:  trigger,
+			object:   newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 3, before),
+			expected: false,
+		},
+		{
+			name:    "series last observed after startup",
+			trigger: trigger,
+			object: func() runtime.Object {
+				ev := newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 0, before)
+				ev.Series = &corev1.EventSeries{Count: 2, LastObservedTime: metav1.NewMicroTime(after.Time)}
+				return ev
+			}(),
+			expected: true,
+		},
+		{
+			name:    "series last observed before startup",
+			trigger: trigger,
+			object: func() runtime.Object {
+				ev := newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 0, after)
+				ev.Series = &corev1.EventSeries{Count: 2, LastObservedTime: metav1.NewMicroTime(before.Time)}
+				return ev
+			}(),
+			expected: false,
+		},
+		{
+			name:    "event time after startup",
+			trigger: trigger,
+			object: func() runtime.Object {
+				ev := newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 0, metav1.Time{})
+				ev.EventTime = metav1.NewMicroTime(after.Time)
+				return ev
+			}(),
+			expected: true,
+		},
+		{
+			name:    "creation timestamp before startup",
+			trigger: trigger,
+			object: func() runtime.Object {
+				ev := newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 0, metav1.Time{})
+				ev.CreationTimestamp = before
+				return ev
+			}(),
+			expected: false,
+		},
+		{
+			name:     "not an event",
+			trigger:  watches.EventTrigger{Name: "any"},
+			object:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := eventTriggerPredicate{Trigger: tc.trigger, Since: since}
+			if got := p.Create(event.CreateEvent{Object: tc.object}); got != tc.expected {
+				t.Fatalf("Create returned %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEventTriggerPredicateUpdate(t *testing.T) {
+	trigger := watches.EventTrigger{
+		Name:               "node-not-ready",
+		Reason:             "NodeNotReady",
+		Type:               corev1.EventTypeWarning,
+		InvolvedObjectKind: "Node",
+	}
+	withSeries := func(ev *corev1.Event, count int32) *corev1.Event {
+		ev.Series = &corev1.EventSeries{Count: count, LastObservedTime: metav1.NewMicroTime(after.Time)}
+		return ev
+	}
+	testCases := []struct {
+		name      string
+		trigger   watches.EventTrigger
+		oldObject runtime.Object
+		newObject runtime.Object
+		expected  bool
+	}{
+		{
+			name:      "count incremented",
+			trigger:   trigger,
+			oldObject: newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 1, before),
+			newObject: newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 2, after),
+			expected:  true,
+		},
+		{
+			// Recurrences are passed even if the Event was first seen before startup.
+			name:      "count incremented on event first seen before startup",
+			trigger:   trigger,
+			oldObject: newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 1, before),
+			newObject: newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 2, before),
+			expected:  true,
+		},
+		{
+			name:      "count unchanged",
+			trigger:   trigger,
+			oldObject: newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 2, before),
+			newObject: newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 2, after),
+			expected:  false,
+		},
+		{
+			name:      "series count incremented",
+			trigger:   trigger,
+			oldObject: withSeries(newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 0, before), 2),
+			newObject: withSeries(newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 0, before), 3),
+			expected:  true,
+		},
+		{
+			name:      "series count unchanged",
+			trigger:   trigger,
+			oldObject: withSeries(newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 0, before), 3),
+			newObject: withSeries(newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 0, before), 3),
+			expected:  false,
+		},
+		{
+			name:      "count incremented on event that does not match",
+			trigger:   trigger,
+			oldObject: newEvent("NodeNotReady", corev1.EventTypeWarning, "Pod", 1, before),
+			newObject: newEvent("NodeNotReady", corev1.EventTypeWarning, "Pod", 2, after),
+			expected:  false,
+		},
+		{
+			name:      "not an event",
+			trigger:   watches.EventTrigger{Name: "any"},
+			oldObject: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}},
+			newObject: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}},
+			expected:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := eventTriggerPredicate{Trigger: tc.trigger, Since: since}
+			got := p.Update(event.UpdateEvent{ObjectOld: tc.oldObject, ObjectNew: tc.newObject})
+			if got != tc.expected {
+				t.Fatalf("Update returned %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestEventTriggerPredicateDeleteAndGeneric(t *testing.T) {
+	p := NewEventTriggerPredicate(watches.EventTrigger{Name: "any"})
+	ev := newEvent("NodeNotReady", corev1.EventTypeWarning, "Node", 1, metav1.Now())
+	if p.Delete(event.DeleteEvent{Object: ev}) {
+		t.Fatalf("Delete unexpectedly passed event")
+	}
+	if p.Generic(event.GenericEvent{Object: ev}) {
+		t.Fatalf("Generic unexpectedly passed event")
+	}
+}
@@ -16,6 +16,7 @@ package controllermap
 
 import (
 	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -41,6 +42,9 @@ type Contents struct {
 	OwnerWatchMap               *WatchMap
 	AnnotationWatchMap          *WatchMap
 	Blacklist                   map[schema.GroupVersionKind]bool
+	// DependentEventDebounce is how long events for dependent resources are
+	// delayed, so bursts of them trigger a single reconcile of their owner.
+	DependentEventDebounce time.Duration
 }
 
 // NewControllerMap returns a new object that contains a mapping between GVK
@@ -0,0 +1,105 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/permissions"
+	k8sRequest "github.com/operator-framework/operator-sdk/internal/ansible/proxy/requestfactory"
+	"github.com/operator-framework/operator-sdk/internal/util/rbacutil"
+)
+
+// trackForbidden records the permission denied by each Forbidden response to
+// a resource request made on behalf of a custom resource in tracker. It must
+// wrap handlers that remove the Authorization header, which identifies the
+// custom resource.
+func trackForbidden(h http.Handler, tracker *permissions.Tracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		owner, err := getRequestOwnerRef(req)
+		if err != nil || owner == nil || owner.UID == "" {
+			h.ServeHTTP(w, req)
+			return
+		}
+		sw := &statusResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(sw, req)
+		if sw.status != http.StatusForbidden {
+			return
+		}
+
+		rf := k8sRequest.RequestInfoFactory{APIPrefixes: sets.NewString("api", "apis"),
+			GrouplessAPIPrefixes: sets.NewString("api")}
+		r, err := rf.NewRequestInfo(req)
+		if err != nil {
+			log.Error(err, "Failed to convert forbidden request")
+			return
+		}
+		if !r.IsResourceRequest {
+			return
+		}
+		p := rbacutil.Permission{
+			Verb:      r.Verb,
+			Resource:  r.Resource,
+			APIGroup:  r.APIGroup,
+			Namespace: r.Namespace,
+		}
+		if r.Subresource != "" {
+			p.Resource += "/" + r.Subresource
+		}
+		log.V(1).Info("Request was forbidden", "owner", owner.Name, "namespace", owner.Namespace,
+			"permission", p.String())
+		tracker.Record(owner.UID, p)
+	})
+}
+
+// statusResponseWriter records the status code of a response. It supports
+// flushing for watches and hijacking for upgraded connections, such as exec.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hj.Hijack()
+}
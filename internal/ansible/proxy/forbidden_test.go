@@ -0,0 +1,122 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/kubeconfig"
+	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/permissions"
+	"github.com/operator-framework/operator-sdk/internal/util/rbacutil"
+)
+
+func TestTrackForbidden(t *testing.T) {
+	owner := kubeconfig.NamespacedOwnerReference{
+		OwnerReference: kmetav1.OwnerReference{
+			APIVersion: "cache.example.com/v1alpha1",
+			Kind:       "Memcached",
+			Name:       "example",
+			UID:        types.UID("1234"),
+		},
+		Namespace: "default",
+	}
+	ownerJSON, err := json.Marshal(owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	username := base64.StdEncoding.EncodeToString(ownerJSON)
+
+	forbidden := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/namespaces/default/configmaps" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		auth     bool
+		expected []rbacutil.Permission
+	}{
+		{
+			name:   "namespaced request",
+			method: http.MethodPost,
+			path:   "/apis/apps/v1/namespaces/default/deployments",
+			auth:   true,
+			expected: []rbacutil.Permission{
+				{Verb: "create", Resource: "deployments", APIGroup: "apps", Namespace: "default"},
+			},
+		},
+		{
+			name:   "cluster scoped subresource request",
+			method: http.MethodGet,
+			path:   "/api/v1/nodes/node-1/status",
+			auth:   true,
+			expected: []rbacutil.Permission{
+				{Verb: "get", Resource: "nodes/status"},
+			},
+		},
+		{
+			name:   "allowed request",
+			method: http.MethodGet,
+			path:   "/api/v1/namespaces/default/configmaps",
+			auth:   true,
+		},
+		{
+			name:   "request without owner",
+			method: http.MethodGet,
+			path:   "/api/v1/namespaces/default/secrets",
+		},
+		{
+			name:   "non-resource request",
+			method: http.MethodGet,
+			path:   "/version",
+			auth:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := permissions.NewTracker()
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.auth {
+				req.SetBasicAuth(username, "unused")
+			}
+			trackForbidden(removeAuthorizationHeader(forbidden), tracker).ServeHTTP(httptest.NewRecorder(), req)
+
+			perms := tracker.Pop(owner.UID)
+			if len(perms) == 0 && len(tc.expected) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(perms, tc.expected) {
+				t.Fatalf("expected permissions %v, got %v", tc.expected, perms)
+			}
+			if perms := tracker.Pop(owner.UID); len(perms) != 0 {
+				t.Fatalf("expected permissions to be forgotten, got %v", perms)
+			}
+		})
+	}
+}
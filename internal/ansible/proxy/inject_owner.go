@@ -143,6 +143,13 @@ func (i *injectOwnerReferenceHandler) ServeHTTP(w http.ResponseWriter, req *http
 				http.Error(w, m, http.StatusBadRequest)
 				return
 			}
+			ownerClusterScoped, err := k8sutil.IsClusterScoped(i.restMapper, ownerGVK)
+			if err != nil {
+				m := "Could not determine if owner is cluster-scoped"
+				log.Error(err, m)
+				http.Error(w, m, http.StatusBadRequest)
+				return
+			}
 			if addOwnerRef {
 				data.SetOwnerReferences(append(data.GetOwnerReferences(), owner.OwnerReference))
 			} else {
@@ -170,10 +177,12 @@ func (i *injectOwnerReferenceHandler) ServeHTTP(w http.ResponseWriter, req *http
 			// if watchedNamespaces[""] exists then we are watching all namespaces
 			// and want to continue
 			// This is making sure we are not attempting to watch a resource outside of the
-			// namespaces that the cache can watch.
+			// namespaces that the cache can watch. Cluster-scoped dependents of
+			// cluster-scoped owners are always watched.
 			_, allNsPresent := i.watchedNamespaces[metav1.NamespaceAll]
 			_, reqNsPresent := i.watchedNamespaces[r.Namespace]
-			if allNsPresent || reqNsPresent {
+			clusterScopedDependent := ownerClusterScoped && r.Namespace == metav1.NamespaceNone
+			if allNsPresent || reqNsPresent || clusterScopedDependent {
 				err = addWatchToController(*owner, i.cMap, data, i.restMapper, addOwnerRef)
 				if err != nil {
 					m := "could not add watch to controller"
@@ -51,9 +51,9 @@ current-context: {{.Namespace}}/proxy-server
 preferences: {}
 users:
 - name: admin/proxy-server
-  user:
+  user:{{if .Username}}
     username: {{.Username}}
-    password: unused
+    password: unused{{else}} {}{{end}}
 `
 
 // values holds the data used to render the template
@@ -81,12 +81,29 @@ func Create(ownerRef metav1.OwnerReference, proxyURL string, namespace string) (
 	}
 	username := base64.URLEncoding.EncodeToString(ownerRefJSON)
 	parsedURL.User = url.User(username)
-	v := values{
+	return write(values{
 		Username:  username,
 		ProxyURL:  parsedURL.String(),
 		Namespace: namespace,
+	})
+}
+
+// CreateWithoutOwner renders a kubeconfig template without an owner reference
+// and writes it to disk. The proxy will not inject owner references into
+// objects created with it.
+func CreateWithoutOwner(proxyURL string, namespace string) (*os.File, error) {
+	parsedURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
 	}
+	return write(values{
+		ProxyURL:  parsedURL.String(),
+		Namespace: namespace,
+	})
+}
 
+// write renders the kubeconfig template with v and writes it to a temp file.
+func write(v values) (*os.File, error) {
 	var parsed bytes.Buffer
 
 	t := template.Must(template.New("kubeconfig").Parse(kubeConfigTemplate))
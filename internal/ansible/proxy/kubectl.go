@@ -191,7 +191,7 @@ func makeUpgradeTransport(config *rest.Config) (k8sproxy.UpgradeRequestRoundTrip
 }
 
 // NewServer creates and installs a new Server.
-func newServer(apiProxyPrefix string, cfg *rest.Config) (*server, error) {
+func newServer(apiProxyPrefix string, cfg *rest.Config, transportOpts TransportOptions) (*server, error) {
 	host := cfg.Host
 	if !strings.HasSuffix(host, "/") {
 		host = host + "/"
@@ -202,7 +202,7 @@ func newServer(apiProxyPrefix string, cfg *rest.Config) (*server, error) {
 	}
 
 	responder := &responder{}
-	transport, err := rest.TransportFor(cfg)
+	transport, err := makeTransport(cfg, transportOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,61 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package permissions
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/operator-framework/operator-sdk/internal/util/rbacutil"
+)
+
+// Tracker records the permissions denied to requests made through the proxy
+// on behalf of each custom resource, identified by its UID.
+type Tracker struct {
+	mu     sync.Mutex
+	denied map[types.UID]map[rbacutil.Permission]struct{}
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{denied: map[types.UID]map[rbacutil.Permission]struct{}{}}
+}
+
+// Record records that a request made on behalf of owner was denied p.
+func (t *Tracker) Record(owner types.UID, p rbacutil.Permission) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.denied[owner]; !ok {
+		t.denied[owner] = map[rbacutil.Permission]struct{}{}
+	}
+	t.denied[owner][p] = struct{}{}
+}
+
+// Pop returns the distinct permissions recorded for owner, sorted by
+// namespace, API group, resource, and verb, and forgets them.
+func (t *Tracker) Pop(owner types.UID) []rbacutil.Permission {
+	t.mu.Lock()
+	denied := t.denied[owner]
+	delete(t.denied, owner)
+	t.mu.Unlock()
+
+	perms := make([]rbacutil.Permission, 0, len(denied))
+	for p := range denied {
+		perms = append(perms, p)
+	}
+	rbacutil.SortPermissions(perms)
+	return perms
+}
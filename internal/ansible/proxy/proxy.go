@@ -38,8 +38,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	sdkhandler "github.com/operator-framework/operator-sdk/internal/ansible/handler"
 	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/controllermap"
 	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/kubeconfig"
+	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/permissions"
 	k8sRequest "github.com/operator-framework/operator-sdk/internal/ansible/proxy/requestfactory"
 )
 
@@ -84,13 +86,21 @@ type Options struct {
 	DisableCache      bool
 	OwnerInjection    bool
 	LogRequests       bool
+	// Transport configures the transport to the API server.
+	Transport TransportOptions
+	// MaxRequestBodySize, if positive, is the maximum size in bytes of a
+	// request body. Larger requests are rejected.
+	MaxRequestBodySize int64
+	// PermissionTracker, if set, records the permissions denied to requests
+	// made on behalf of each custom resource.
+	PermissionTracker *permissions.Tracker
 }
 
 // Run will start a proxy server in a go routine that returns on the error
 // channel if something is not correct on startup. Run will not return until
 // the network socket is listening.
 func Run(done chan error, o Options) error {
-	server, err := newServer("/", o.KubeConfig)
+	server, err := newServer("/", o.KubeConfig, o.Transport)
 	if err != nil {
 		return err
 	}
@@ -147,6 +157,10 @@ func Run(done chan error, o Options) error {
 	// Remove the authorization header so the proxy can correctly inject the header.
 	server.Handler = removeAuthorizationHeader(server.Handler)
 
+	if o.PermissionTracker != nil {
+		server.Handler = trackForbidden(server.Handler, o.PermissionTracker)
+	}
+
 	if o.OwnerInjection {
 		server.Handler = &injectOwnerReferenceHandler{
 			next:              server.Handler,
@@ -178,6 +192,10 @@ func Run(done chan error, o Options) error {
 		}
 	}
 
+	if o.MaxRequestBodySize > 0 {
+		server.Handler = limitRequestBody(server.Handler, o.MaxRequestBodySize)
+	}
+
 	l, err := server.Listen(o.Address, o.Port)
 	if err != nil {
 		return err
@@ -240,7 +258,7 @@ func addWatchToController(owner kubeconfig.NamespacedOwnerReference, cMap *contr
 			log.Info("Watching child resource", "kind", resource.GroupVersionKind(),
 				"enqueue_kind", u.GroupVersionKind())
 			err := contents.Controller.Watch(&source.Kind{Type: resource},
-				&handler.EnqueueRequestForOwner{OwnerType: u}, predicate.DependentPredicate{})
+				dependentHandler(&handler.EnqueueRequestForOwner{OwnerType: u}, contents), predicate.DependentPredicate{})
 			// Store watch in map
 			if err != nil {
 				log.Error(err, "Failed to watch child resource",
@@ -261,7 +279,8 @@ func addWatchToController(owner kubeconfig.NamespacedOwnerReference, cMap *contr
 			log.Info("Watching child resource", "kind", resource.GroupVersionKind(),
 				"enqueue_annotation_type", ownerGK.String())
 			err = contents.Controller.Watch(&source.Kind{Type: resource},
-				&libhandler.EnqueueRequestForAnnotation{Type: ownerGK}, predicate.DependentPredicate{})
+				dependentHandler(&libhandler.EnqueueRequestForAnnotation{Type: ownerGK}, contents),
+				predicate.DependentPredicate{})
 			if err != nil {
 				log.Error(err, "Failed to watch child resource",
 					"kind", resource.GroupVersionKind(), "enqueue_kind", u.GroupVersionKind())
@@ -274,6 +293,12 @@ func addWatchToController(owner kubeconfig.NamespacedOwnerReference, cMap *contr
 	return nil
 }
 
+// dependentHandler debounces events enqueued by h for the owner of a dependent
+// resource if the owner's controller has a debounce window configured.
+func dependentHandler(h handler.EventHandler, contents *controllermap.Contents) handler.EventHandler {
+	return sdkhandler.NewDebouncedEventHandler(h, contents.DependentEventDebounce)
+}
+
 func removeAuthorizationHeader(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		req.Header.Del("Authorization")
@@ -0,0 +1,97 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+// TransportOptions configures the transport the proxy uses to send requests
+// to the API server. Zero values use client-go's defaults.
+type TransportOptions struct {
+	// MaxIdleConns is the maximum number of idle connections to the API server.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections per API server host.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open.
+	// Unlimited if 0.
+	IdleConnTimeout time.Duration
+	// ResponseHeaderTimeout is how long to wait for the API server's response
+	// headers after a request is sent.
+	ResponseHeaderTimeout time.Duration
+	// DisableHTTP2 disables HTTP/2 to the API server, so each concurrent
+	// request uses its own HTTP/1.1 connection.
+	DisableHTTP2 bool
+}
+
+// makeTransport creates a transport to the API server configured by cfg and opts.
+// If cfg has a custom transport, opts are ignored.
+func makeTransport(cfg *rest.Config, opts TransportOptions) (http.RoundTripper, error) {
+	if cfg.Transport != nil {
+		return rest.TransportFor(cfg)
+	}
+	transportConfig, err := cfg.TransportConfig()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := transport.TLSConfigFor(transportConfig)
+	if err != nil {
+		return nil, err
+	}
+	rt := &http.Transport{
+		TLSClientConfig: tlsConfig,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+	}
+	if opts.DisableHTTP2 {
+		rt = utilnet.SetOldTransportDefaults(rt)
+	} else {
+		rt = utilnet.SetTransportDefaults(rt)
+	}
+	// The defaults replace a zero IdleConnTimeout, which means unlimited.
+	rt.IdleConnTimeout = opts.IdleConnTimeout
+	return transport.HTTPWrappersForConfig(transportConfig, rt)
+}
+
+// limitRequestBody rejects requests whose bodies are larger than maxBytes with
+// 413 Request Entity Too Large.
+func limitRequestBody(h http.Handler, maxBytes int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > maxBytes {
+			msg := fmt.Sprintf("request body of %d bytes exceeds the proxy's limit of %d bytes", req.ContentLength, maxBytes)
+			log.Info("Rejected request", "method", req.Method, "path", req.URL.Path, "reason", msg)
+			http.Error(w, msg, http.StatusRequestEntityTooLarge)
+			return
+		}
+		if req.Body != nil {
+			// Bodies of unknown length fail when read past the limit.
+			req.Body = http.MaxBytesReader(w, req.Body, maxBytes)
+		}
+		h.ServeHTTP(w, req)
+	})
+}
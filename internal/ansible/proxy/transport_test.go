@@ -0,0 +1,77 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestMakeTransportIdleConnTimeout(t *testing.T) {
+	for _, disableHTTP2 := range []bool{false, true} {
+		for _, timeout := range []time.Duration{0, 30 * time.Second} {
+			opts := TransportOptions{IdleConnTimeout: timeout, DisableHTTP2: disableHTTP2}
+			rt, err := makeTransport(&rest.Config{Host: "https://127.0.0.1:6443"}, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tr, ok := rt.(*http.Transport)
+			if !ok {
+				t.Fatalf("expected *http.Transport, got %T", rt)
+			}
+			if tr.IdleConnTimeout != timeout {
+				t.Errorf("disableHTTP2=%v: expected idle conn timeout %v, got %v", disableHTTP2, timeout, tr.IdleConnTimeout)
+			}
+		}
+	}
+}
+
+func TestLimitRequestBody(t *testing.T) {
+	h := limitRequestBody(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, err := ioutil.ReadAll(req.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	}), 4)
+
+	tests := []struct {
+		name          string
+		body          string
+		unknownLength bool
+		expectCode    int
+	}{
+		{name: "within limit", body: "abcd", expectCode: http.StatusOK},
+		{name: "over limit", body: "abcde", expectCode: http.StatusRequestEntityTooLarge},
+		{name: "unknown length within limit", body: "abcd", unknownLength: true, expectCode: http.StatusOK},
+		{name: "unknown length over limit", body: "abcde", unknownLength: true,
+			expectCode: http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/default/configmaps", strings.NewReader(test.body))
+		if test.unknownLength {
+			req.ContentLength = -1
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != test.expectCode {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.expectCode, rec.Code)
+		}
+	}
+}
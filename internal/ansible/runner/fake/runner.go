@@ -37,6 +37,9 @@ type Runner struct {
 	JobEvents []eventapi.JobEvent
 	//Stdout standard out to reply if failure occurs.
 	Stdout string
+	// OnRun, if set, is called with the resource when Run is called, to
+	// simulate changes made to the resource during a run.
+	OnRun func(u *unstructured.Unstructured)
 }
 
 type runResult struct {
@@ -60,6 +63,9 @@ func (r *Runner) Run(_ string, u *unstructured.Unstructured, _ string) (runner.R
 	if r.Error != nil {
 		return nil, r.Error
 	}
+	if r.OnRun != nil {
+		r.OnRun(u)
+	}
 	c := make(chan eventapi.JobEvent)
 	go func() {
 		for _, je := range r.JobEvents {
@@ -26,6 +26,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/operator-framework/operator-sdk/internal/ansible/metrics"
@@ -137,6 +138,11 @@ func New(watch watches.Watch, runnerArgs string) (Runner, error) {
 		cmdFunc = roleCmdFunc(path)
 	}
 
+	var selector *playbookSelector
+	if watch.PlaybookSelector != nil {
+		selector = newPlaybookSelector(*watch.PlaybookSelector)
+	}
+
 	// handle finalizer
 	switch {
 	case watch.Finalizer == nil:
@@ -160,6 +166,33 @@ func New(watch watches.Watch, runnerArgs string) (Runner, error) {
 		ansibleVerbosity:    watch.AnsibleVerbosity,
 		ansibleArgs:         runnerArgs,
 		snakeCaseParameters: watch.SnakeCaseParameters,
+		snakeCaseKeys:       sets.NewString(watch.SnakeCaseParameterKeys...),
+		parameterNames:      watch.ParameterNames,
+		selector:            selector,
+	}, nil
+}
+
+// eventGVK is the GroupVersionKind of objects passed to event trigger runners.
+var eventGVK = schema.GroupVersionKind{Version: "v1", Kind: "Event"}
+
+// NewEventTriggerRunner - creates a Runner that runs trigger's playbook for
+// a matching Event, using watch's runner settings.
+func NewEventTriggerRunner(watch watches.Watch, trigger watches.EventTrigger, runnerArgs string) (Runner, error) {
+	if err := watch.Validate(); err != nil {
+		log.Error(err, "Failed to validate watch")
+		return nil, err
+	}
+
+	return &runner{
+		Path:               trigger.Playbook,
+		cmdFunc:            playbookCmdFunc(trigger.Playbook),
+		Vars:               trigger.Vars,
+		GVK:                eventGVK,
+		maxRunnerArtifacts: watch.MaxRunnerArtifacts,
+		ansibleVerbosity:   watch.AnsibleVerbosity,
+		ansibleArgs:        runnerArgs,
+		eventTrigger:       trigger.Name,
+		eventVars:          trigger.EventVars,
 	}, nil
 }
 
@@ -174,7 +207,55 @@ type runner struct {
 	maxRunnerArtifacts  int
 	ansibleVerbosity    int
 	snakeCaseParameters bool
+	snakeCaseKeys       sets.String       // if not empty, the only spec keys converted to snake_case
+	parameterNames      map[string]string // spec keys mapped to the parameter names they are passed as
 	ansibleArgs         string
+	eventTrigger        string            // name of the event trigger this runner runs, if any
+	eventVars           map[string]string // extra vars extracted from an Event by an event trigger
+	selector            *playbookSelector // if set, selects Path and cmdFunc per CR
+}
+
+// playbookSelector - selects the playbook or role run for a CR by the value
+// of one of its fields.
+type playbookSelector struct {
+	field []string
+	cases map[string]playbookCase
+}
+
+type playbookCase struct {
+	path    string
+	cmdFunc cmdFuncType
+}
+
+func newPlaybookSelector(s watches.PlaybookSelector) *playbookSelector {
+	selector := &playbookSelector{
+		field: strings.Split(s.Field, "."),
+		cases: make(map[string]playbookCase, len(s.Cases)),
+	}
+	for _, c := range s.Cases {
+		if c.Playbook != "" {
+			selector.cases[c.Value] = playbookCase{path: c.Playbook, cmdFunc: playbookCmdFunc(c.Playbook)}
+		} else {
+			selector.cases[c.Value] = playbookCase{path: c.Role, cmdFunc: roleCmdFunc(c.Role)}
+		}
+	}
+	return selector
+}
+
+// selectPlaybook - returns the path and cmdFunc of the playbook or role run
+// for u, which are the runner's own unless the selector matches u.
+func (r *runner) selectPlaybook(u *unstructured.Unstructured) (string, cmdFuncType) {
+	if r.selector == nil {
+		return r.Path, r.cmdFunc
+	}
+	value, found, err := unstructured.NestedFieldNoCopy(u.Object, r.selector.field...)
+	if err != nil || !found || value == nil {
+		return r.Path, r.cmdFunc
+	}
+	if c, ok := r.selector.cases[fmt.Sprintf("%v", value)]; ok {
+		return c.path, c.cmdFunc
+	}
+	return r.Path, r.cmdFunc
 }
 
 func (r *runner) Run(ident string, u *unstructured.Unstructured, kubeconfig string) (RunResult, error) {
@@ -197,9 +278,15 @@ func (r *runner) Run(ident string, u *unstructured.Unstructured, kubeconfig stri
 	if err != nil {
 		return nil, err
 	}
+	inputDirPath := filepath.Join("/tmp/ansible-operator/runner/", r.GVK.Group, r.GVK.Version, r.GVK.Kind,
+		u.GetNamespace(), u.GetName())
+	if r.eventTrigger != "" {
+		// Several triggers may match the same Event, so give each its own directory.
+		inputDirPath = filepath.Join("/tmp/ansible-operator/runner/triggers", r.eventTrigger,
+			u.GetNamespace(), u.GetName())
+	}
 	inputDir := inputdir.InputDir{
-		Path: filepath.Join("/tmp/ansible-operator/runner/", r.GVK.Group, r.GVK.Version, r.GVK.Kind,
-			u.GetNamespace(), u.GetName()),
+		Path:       inputDirPath,
 		Parameters: r.makeParameters(u),
 		EnvVars: map[string]string{
 			"K8S_AUTH_KUBECONFIG": kubeconfig,
@@ -211,14 +298,15 @@ func (r *runner) Run(ident string, u *unstructured.Unstructured, kubeconfig stri
 		},
 		CmdLine: r.ansibleArgs,
 	}
-	// If Path is a dir, assume it is a role path. Otherwise assume it's a
+	// If path is a dir, assume it is a role path. Otherwise assume it's a
 	// playbook path
-	fi, err := os.Lstat(r.Path)
+	path, cmdFunc := r.selectPlaybook(u)
+	fi, err := os.Lstat(path)
 	if err != nil {
 		return nil, err
 	}
 	if !fi.IsDir() {
-		inputDir.PlaybookPath = r.Path
+		inputDir.PlaybookPath = path
 	}
 	err = inputDir.Write()
 	if err != nil {
@@ -249,9 +337,14 @@ func (r *runner) Run(ident string, u *unstructured.Unstructured, kubeconfig stri
 		if r.isFinalizerRun(u) {
 			logger.V(1).Info("Resource is marked for deletion, running finalizer",
 				"Finalizer", r.Finalizer.Name)
-			dc = r.finalizerCmdFunc(ident, inputDir.Path, maxArtifacts, verbosity)
+			finalizerCmdFunc := r.finalizerCmdFunc
+			// A finalizer with only vars runs the CR's selected playbook or role.
+			if r.Finalizer.Playbook == "" && r.Finalizer.Role == "" {
+				finalizerCmdFunc = cmdFunc
+			}
+			dc = finalizerCmdFunc(ident, inputDir.Path, maxArtifacts, verbosity)
 		} else {
-			dc = r.cmdFunc(ident, inputDir.Path, maxArtifacts, verbosity)
+			dc = cmdFunc(ident, inputDir.Path, maxArtifacts, verbosity)
 		}
 		// Append current environment since setting dc.Env to anything other than nil overwrites current env
 		dc.Env = append(dc.Env, os.Environ()...)
@@ -308,38 +401,35 @@ func (r *runner) isFinalizerRun(u *unstructured.Unstructured) bool {
 
 // makeParameters - creates the extravars parameters for ansible
 // The resulting structure in json is:
-// { "ansible_operator_meta": {
-//      "name": <object_name>,
-//      "namespace": <object_namespace>,
-//   },
-//   <cr_spec_fields_as_snake_case>,
-//   <watch vars>,
-//   <finalizer vars>,
-//   _<group_as_snake>_<kind>: {
-//       <cr_object> as is
-//   }
-//   _<group_as_snake>_<kind>_spec: {
-//       <cr_object.spec> as is
-//   }
-// }
+//
+//	{ "ansible_operator_meta": {
+//	     "name": <object_name>,
+//	     "namespace": <object_namespace>, (empty for cluster-scoped objects)
+//	  },
+//	  <cr_spec_fields_as_snake_case>,
+//	  <watch vars>,
+//	  <finalizer vars>,
+//	  <event trigger vars extracted from the event>,
+//	  _<group_as_snake>_<kind>: {
+//	      <cr_object> as is
+//	  }
+//	  _<group_as_snake>_<kind>_spec: {
+//	      <cr_object.spec> as is
+//	  }
+//	}
 func (r *runner) makeParameters(u *unstructured.Unstructured) map[string]interface{} {
 	s := u.Object["spec"]
 	spec, ok := s.(map[string]interface{})
 	if !ok {
-		log.Info("Spec was not found for CR", "GroupVersionKind", u.GroupVersionKind(),
-			"Namespace", u.GetNamespace(), "Name", u.GetName())
+		// Events passed to event trigger runners never have a spec.
+		if r.eventTrigger == "" {
+			log.Info("Spec was not found for CR", "GroupVersionKind", u.GroupVersionKind(),
+				"Namespace", u.GetNamespace(), "Name", u.GetName())
+		}
 		spec = map[string]interface{}{}
 	}
 
-	parameters := map[string]interface{}{}
-
-	if r.snakeCaseParameters {
-		parameters = paramconv.MapToSnake(spec)
-	} else {
-		for k, v := range spec {
-			parameters[k] = v
-		}
-	}
+	parameters := r.specParameters(spec)
 
 	parameters["ansible_operator_meta"] = map[string]string{"namespace": u.GetNamespace(), "name": u.GetName()}
 
@@ -357,6 +447,33 @@ func (r *runner) makeParameters(u *unstructured.Unstructured) map[string]interfa
 			parameters[k] = v
 		}
 	}
+	for k, path := range r.eventVars {
+		v, found, err := unstructured.NestedFieldNoCopy(u.Object, strings.Split(path, ".")...)
+		if err != nil || !found {
+			log.Info("Event var was not found in event", "var", k, "path", path, "Name", u.GetName())
+			continue
+		}
+		parameters[k] = v
+	}
+	return parameters
+}
+
+// specParameters - converts the top-level keys of spec to parameter names.
+// Keys are converted to snake_case, along with the keys nested under them,
+// if snakeCaseParameters is true and snakeCaseKeys is empty or contains the
+// key. parameterNames overrides the names of mapped keys.
+func (r *runner) specParameters(spec map[string]interface{}) map[string]interface{} {
+	parameters := make(map[string]interface{}, len(spec))
+	for k, v := range spec {
+		name := k
+		if r.snakeCaseParameters && (r.snakeCaseKeys.Len() == 0 || r.snakeCaseKeys.Has(k)) {
+			name, v = paramconv.ToSnake(k), paramconv.ValueToSnake(v)
+		}
+		if mapped, ok := r.parameterNames[k]; ok {
+			name = mapped
+		}
+		parameters[name] = v
+	}
 	return parameters
 }
 
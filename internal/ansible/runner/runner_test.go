@@ -23,6 +23,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/operator-framework/operator-sdk/internal/ansible/watches"
 )
@@ -225,6 +226,200 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewEventTriggerRunner(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get working director: %v", err)
+	}
+	validPlaybook := filepath.Join(cwd, "testdata", "playbook.yml")
+	gvk := schema.GroupVersionKind{Group: "operator.example.com", Version: "v1alpha1", Kind: "Example"}
+	trigger := watches.EventTrigger{
+		Name:     "node-not-ready",
+		Reason:   "NodeNotReady",
+		Playbook: validPlaybook,
+		Vars:     map[string]interface{}{"sentinel": "reconciling"},
+		EventVars: map[string]string{
+			"node_name": "involvedObject.name",
+			"missing":   "involvedObject.uid",
+		},
+	}
+	testWatch := watches.New(gvk, "", validPlaybook, nil, nil)
+	testWatch.EventTriggers = []watches.EventTrigger{trigger}
+
+	testRunner, err := NewEventTriggerRunner(*testWatch, trigger, "")
+	if err != nil {
+		t.Fatalf("Error occurred unexpectedly: %v", err)
+	}
+	testRunnerStruct, ok := testRunner.(*runner)
+	if !ok {
+		t.Fatalf("Unexpected runner type %T", testRunner)
+	}
+	if testRunnerStruct.GVK != eventGVK {
+		t.Fatalf("Unexpected GVK %v expected GVK %v", testRunnerStruct.GVK, eventGVK)
+	}
+	checkCmdFunc(t, testRunnerStruct.cmdFunc, trigger.Playbook, "", testWatch.AnsibleVerbosity)
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"involvedObject": map[string]interface{}{"kind": "Node", "name": "worker-0"},
+	}}
+	parameters := testRunnerStruct.makeParameters(u)
+	if parameters["node_name"] != "worker-0" {
+		t.Fatalf("Unexpected node_name %v expected worker-0", parameters["node_name"])
+	}
+	if _, ok := parameters["missing"]; ok {
+		t.Fatalf("Unexpected var missing in parameters %+v", parameters)
+	}
+	if parameters["sentinel"] != "reconciling" {
+		t.Fatalf("Unexpected sentinel %v expected reconciling", parameters["sentinel"])
+	}
+	if _, ok := parameters["__event"]; !ok {
+		t.Fatalf("Did not find expected objKey __event in parameters %+v", parameters)
+	}
+}
+
+func TestMakeParametersMeta(t *testing.T) {
+	testCases := []struct {
+		name         string
+		namespace    string
+		expectedMeta map[string]string
+	}{
+		{
+			name:         "namespaced object",
+			namespace:    "default",
+			expectedMeta: map[string]string{"name": "example", "namespace": "default"},
+		},
+		{
+			name:         "cluster-scoped object",
+			expectedMeta: map[string]string{"name": "example", "namespace": ""},
+		},
+	}
+
+	gvk := schema.GroupVersionKind{Group: "operator.example.com", Version: "v1alpha1", Kind: "Example"}
+	testRunner := &runner{GVK: gvk}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := &unstructured.Unstructured{}
+			u.SetGroupVersionKind(gvk)
+			u.SetName("example")
+			u.SetNamespace(tc.namespace)
+			parameters := testRunner.makeParameters(u)
+			if !reflect.DeepEqual(parameters["ansible_operator_meta"], tc.expectedMeta) {
+				t.Fatalf("Unexpected ansible_operator_meta %v expected %v",
+					parameters["ansible_operator_meta"], tc.expectedMeta)
+			}
+		})
+	}
+}
+
+func TestSelectPlaybook(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get working director: %v", err)
+	}
+	validPlaybook := filepath.Join(cwd, "testdata", "playbook.yml")
+	validRole := filepath.Join(cwd, "testdata", "roles", "role")
+	restorePlaybook := filepath.Join(cwd, "testdata", "restore.yml")
+
+	testRunner := &runner{
+		Path:    validPlaybook,
+		cmdFunc: playbookCmdFunc(validPlaybook),
+		selector: newPlaybookSelector(watches.PlaybookSelector{
+			Field: "spec.mode",
+			Cases: []watches.PlaybookCase{
+				{Value: "restore", Playbook: restorePlaybook},
+				{Value: "upgrade", Role: validRole},
+			},
+		}),
+	}
+
+	testCases := []struct {
+		name     string
+		mode     interface{}
+		playbook string
+		role     string
+	}{
+		{name: "unset field", playbook: validPlaybook},
+		{name: "unmatched value", mode: "install", playbook: validPlaybook},
+		{name: "playbook case", mode: "restore", playbook: restorePlaybook},
+		{name: "role case", mode: "upgrade", role: validRole},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tc.mode != nil {
+				u.Object["spec"] = map[string]interface{}{"mode": tc.mode}
+			}
+			path, cmdFunc := testRunner.selectPlaybook(u)
+			if expected := tc.playbook + tc.role; path != expected {
+				t.Fatalf("Unexpected path %v expected %v", path, expected)
+			}
+			checkCmdFunc(t, cmdFunc, tc.playbook, tc.role, 0)
+		})
+	}
+}
+
+func TestMakeParametersConversion(t *testing.T) {
+	spec := map[string]interface{}{
+		"storageClass":  "fast",
+		"clusterConfig": map[string]interface{}{"nodeCount": int64(3)},
+		"serverConfig":  map[string]interface{}{"logLevel": "debug"},
+	}
+	testCases := []struct {
+		name     string
+		runner   *runner
+		expected map[string]interface{}
+	}{
+		{
+			name:   "no conversion",
+			runner: &runner{},
+			expected: map[string]interface{}{
+				"storageClass":  "fast",
+				"clusterConfig": map[string]interface{}{"nodeCount": int64(3)},
+				"serverConfig":  map[string]interface{}{"logLevel": "debug"},
+			},
+		},
+		{
+			name:   "all keys",
+			runner: &runner{snakeCaseParameters: true},
+			expected: map[string]interface{}{
+				"storage_class":  "fast",
+				"cluster_config": map[string]interface{}{"node_count": int64(3)},
+				"server_config":  map[string]interface{}{"log_level": "debug"},
+			},
+		},
+		{
+			name:   "allowed keys",
+			runner: &runner{snakeCaseParameters: true, snakeCaseKeys: sets.NewString("clusterConfig")},
+			expected: map[string]interface{}{
+				"storageClass":   "fast",
+				"cluster_config": map[string]interface{}{"node_count": int64(3)},
+				"serverConfig":   map[string]interface{}{"logLevel": "debug"},
+			},
+		},
+		{
+			name: "mapped names",
+			runner: &runner{snakeCaseParameters: true, parameterNames: map[string]string{
+				"storageClass":  "storageClass",
+				"clusterConfig": "cluster",
+			}},
+			expected: map[string]interface{}{
+				"storageClass":  "fast",
+				"cluster":       map[string]interface{}{"node_count": int64(3)},
+				"server_config": map[string]interface{}{"log_level": "debug"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parameters := tc.runner.specParameters(spec)
+			if !reflect.DeepEqual(parameters, tc.expected) {
+				t.Fatalf("Unexpected parameters %v expected %v", parameters, tc.expected)
+			}
+		})
+	}
+}
+
 func TestAnsibleVerbosityString(t *testing.T) {
 	testCases := []struct {
 		verbosity      int
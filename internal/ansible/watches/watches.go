@@ -53,6 +53,18 @@ type Watch struct {
 	WatchClusterScopedResources bool                      `yaml:"watchClusterScopedResources"`
 	SnakeCaseParameters         bool                      `yaml:"snakeCaseParameters"`
 	Selector                    metav1.LabelSelector      `yaml:"selector"`
+	EventTriggers               []EventTrigger            `yaml:"eventTriggers"`
+	// SnakeCaseParameterKeys, if not empty, lists the only top-level spec
+	// keys converted to snake_case when SnakeCaseParameters is true. Other
+	// keys, and the keys nested under them, are passed to ansible as is.
+	SnakeCaseParameterKeys []string `yaml:"snakeCaseParameterKeys"`
+	// ParameterNames maps top-level spec keys to the names of the variables
+	// they are passed to ansible as, overriding snake_case conversion of the
+	// key. Keys nested under them are converted as if the key were not mapped.
+	ParameterNames map[string]string `yaml:"parameterNames"`
+	// PlaybookSelector, if set, selects the playbook or role run for each CR
+	// by the value of one of its fields, instead of Playbook or Role.
+	PlaybookSelector *PlaybookSelector `yaml:"playbookSelector"`
 
 	// Not configurable via watches.yaml
 	MaxConcurrentReconciles int `yaml:"-"`
@@ -67,6 +79,40 @@ type Finalizer struct {
 	Vars     map[string]interface{} `yaml:"vars"`
 }
 
+// PlaybookSelector - selects the playbook or role run for a CR by the value of
+// the CR's Field. CRs whose field is unset or matches no case run the watch's
+// playbook or role.
+type PlaybookSelector struct {
+	// Field is the dot-separated path of the CR field, ex. "spec.mode".
+	Field string         `yaml:"field"`
+	Cases []PlaybookCase `yaml:"cases"`
+}
+
+// PlaybookCase - maps a value of a PlaybookSelector's field to a playbook or
+// role.
+type PlaybookCase struct {
+	Value    string `yaml:"value"`
+	Playbook string `yaml:"playbook"`
+	Role     string `yaml:"role"`
+}
+
+// EventTrigger - runs a playbook when a Kubernetes Event matching Reason, Type,
+// and InvolvedObjectKind is created or recurs.
+type EventTrigger struct {
+	// Name uniquely identifies the trigger across the watches file.
+	Name string `yaml:"name"`
+	// Reason, Type, and InvolvedObjectKind are matched against the Event's
+	// reason, type, and involvedObject.kind. Unset fields match any Event.
+	Reason             string                 `yaml:"reason"`
+	Type               string                 `yaml:"type"`
+	InvolvedObjectKind string                 `yaml:"involvedObjectKind"`
+	Playbook           string                 `yaml:"playbook"`
+	Vars               map[string]interface{} `yaml:"vars"`
+	// EventVars maps extra var names to dot-separated paths of Event fields,
+	// ex. "involvedObject.name".
+	EventVars map[string]string `yaml:"eventVars"`
+}
+
 // Default values for optional fields on Watch
 var (
 	blacklistDefault                   = []schema.GroupVersionKind{}
@@ -130,6 +176,10 @@ type alias struct {
 	Blacklist                   []schema.GroupVersionKind `yaml:"blacklist,omitempty"`
 	Finalizer                   *Finalizer                `yaml:"finalizer"`
 	Selector                    tempLabelSelector         `yaml:"selector"`
+	EventTriggers               []EventTrigger            `yaml:"eventTriggers,omitempty"`
+	SnakeCaseParameterKeys      []string                  `yaml:"snakeCaseParameterKeys,omitempty"`
+	ParameterNames              map[string]string         `yaml:"parameterNames,omitempty"`
+	PlaybookSelector            *PlaybookSelector         `yaml:"playbookSelector,omitempty"`
 }
 
 // buildWatch will build Watch based on the values parsed from alias
@@ -183,10 +233,14 @@ func (w *Watch) setValuesFromAlias(tmp alias) error {
 	w.ManageStatus = *tmp.ManageStatus
 	w.WatchDependentResources = *tmp.WatchDependentResources
 	w.SnakeCaseParameters = *tmp.SnakeCaseParameters
+	w.SnakeCaseParameterKeys = tmp.SnakeCaseParameterKeys
+	w.ParameterNames = tmp.ParameterNames
+	w.PlaybookSelector = tmp.PlaybookSelector
 	w.WatchClusterScopedResources = *tmp.WatchClusterScopedResources
 	w.Finalizer = tmp.Finalizer
 	w.AnsibleVerbosity = getAnsibleVerbosity(gvk, ansibleVerbosityDefault)
 	w.Blacklist = tmp.Blacklist
+	w.EventTriggers = tmp.EventTriggers
 
 	wd, err := os.Getwd()
 	if err != nil {
@@ -225,6 +279,23 @@ func (w *Watch) addRolePlaybookPaths(rootDir string) {
 	if w.Finalizer != nil && len(w.Finalizer.Playbook) > 0 {
 		w.Finalizer.Playbook = getFullPath(rootDir, w.Finalizer.Playbook)
 	}
+	for i := range w.EventTriggers {
+		w.EventTriggers[i].Playbook = getFullPath(rootDir, w.EventTriggers[i].Playbook)
+	}
+	if w.PlaybookSelector != nil {
+		for i := range w.PlaybookSelector.Cases {
+			c := &w.PlaybookSelector.Cases[i]
+			c.Playbook = getFullPath(rootDir, c.Playbook)
+			if len(c.Role) > 0 {
+				for _, possiblePath := range getPossibleRolePaths(rootDir, c.Role) {
+					if _, err := os.Stat(possiblePath); err == nil {
+						c.Role = possiblePath
+						break
+					}
+				}
+			}
+		}
+	}
 }
 
 // getFullPath returns an absolute path for the playbook
@@ -276,6 +347,7 @@ func getPossibleRolePaths(rootDir, path string) []string {
 // A Watch is considered valid if it:
 // - Specifies a valid path to a Role||Playbook
 // - If a Finalizer is non-nil, it must have a name + valid path to a Role||Playbook or Vars
+// - Each EventTrigger must have a name + valid path to a Playbook
 func (w *Watch) Validate() error {
 	err := verifyAnsiblePath(w.Playbook, w.Role)
 	if err != nil {
@@ -298,6 +370,84 @@ func (w *Watch) Validate() error {
 		}
 	}
 
+	for _, t := range w.EventTriggers {
+		if err := t.validate(); err != nil {
+			log.Error(err, fmt.Sprintf("Invalid event trigger for GVK: %v", w.GroupVersionKind.String()))
+			return err
+		}
+	}
+
+	if w.PlaybookSelector != nil {
+		if err := w.PlaybookSelector.validate(); err != nil {
+			log.Error(err, fmt.Sprintf("Invalid playbook selector for GVK: %v", w.GroupVersionKind.String()))
+			return err
+		}
+	}
+
+	if err := w.validateParameterConversion(); err != nil {
+		log.Error(err, fmt.Sprintf("Invalid parameter conversion for GVK: %v", w.GroupVersionKind.String()))
+		return err
+	}
+
+	return nil
+}
+
+// validateParameterConversion - ensures that snakeCaseParameterKeys and
+// parameterNames are consistent
+func (w *Watch) validateParameterConversion() error {
+	if len(w.SnakeCaseParameterKeys) != 0 && !w.SnakeCaseParameters {
+		return errors.New("snakeCaseParameterKeys must not be set when snakeCaseParameters is false")
+	}
+	names := map[string]string{}
+	for key, name := range w.ParameterNames {
+		if name == "" {
+			return fmt.Errorf("parameter name for spec key %q must not be empty", key)
+		}
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("spec keys %q and %q must not have the same parameter name %q", other, key, name)
+		}
+		names[name] = key
+	}
+	return nil
+}
+
+// validate - ensures that a PlaybookSelector is valid
+func (s PlaybookSelector) validate() error {
+	if s.Field == "" {
+		return errors.New("playbook selector must have field")
+	}
+	if len(s.Cases) == 0 {
+		return fmt.Errorf("playbook selector for field %q must have cases", s.Field)
+	}
+	values := map[string]struct{}{}
+	for _, c := range s.Cases {
+		if _, ok := values[c.Value]; ok {
+			return fmt.Errorf("playbook selector for field %q has duplicate value %q", s.Field, c.Value)
+		}
+		values[c.Value] = struct{}{}
+		if err := verifyAnsiblePath(c.Playbook, c.Role); err != nil {
+			return fmt.Errorf("playbook selector case %q: %v", c.Value, err)
+		}
+	}
+	return nil
+}
+
+// validate - ensures that an EventTrigger is valid
+func (t EventTrigger) validate() error {
+	if t.Name == "" {
+		return errors.New("event trigger must have name")
+	}
+	if t.Playbook == "" {
+		return fmt.Errorf("event trigger %q must specify Playbook", t.Name)
+	}
+	if _, err := os.Stat(t.Playbook); err != nil {
+		return fmt.Errorf("event trigger %q playbook: %v was not found", t.Name, t.Playbook)
+	}
+	for name, path := range t.EventVars {
+		if path == "" {
+			return fmt.Errorf("event trigger %q event var %q must have a path", t.Name, name)
+		}
+	}
 	return nil
 }
 
@@ -353,6 +503,7 @@ func Load(path string, maxReconciler, ansibleVerbosity int) ([]Watch, error) {
 	}
 
 	watchesMap := make(map[schema.GroupVersionKind]bool)
+	triggersMap := make(map[string]bool)
 	for _, watch := range watches {
 		// prevent dupes
 		if _, ok := watchesMap[watch.GroupVersionKind]; ok {
@@ -366,6 +517,13 @@ func Load(path string, maxReconciler, ansibleVerbosity int) ([]Watch, error) {
 			log.Error(err, fmt.Sprintf("Watch with GVK %v failed validation", watch.GroupVersionKind.String()))
 			return nil, err
 		}
+
+		for _, t := range watch.EventTriggers {
+			if triggersMap[t.Name] {
+				return nil, fmt.Errorf("duplicate event trigger name: %v", t.Name)
+			}
+			triggersMap[t.Name] = true
+		}
 	}
 
 	return watches, nil
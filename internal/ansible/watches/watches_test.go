@@ -434,6 +434,41 @@ func TestLoad(t *testing.T) { //nolint:gocyclo
 			path:        "testdata/invalid_finalizer_no_vars.yaml",
 			shouldError: true,
 		},
+		{
+			name:        "error invalid event trigger without name",
+			path:        "testdata/invalid_event_trigger_without_name.yaml",
+			shouldError: true,
+		},
+		{
+			name:        "error invalid event trigger playbook path",
+			path:        "testdata/invalid_event_trigger_playbook_path.yaml",
+			shouldError: true,
+		},
+		{
+			name:        "error duplicate event trigger name",
+			path:        "testdata/duplicate_event_trigger.yaml",
+			shouldError: true,
+		},
+		{
+			name:        "error invalid playbook selector path",
+			path:        "testdata/invalid_playbook_selector_path.yaml",
+			shouldError: true,
+		},
+		{
+			name:        "error duplicate playbook selector value",
+			path:        "testdata/duplicate_playbook_selector_value.yaml",
+			shouldError: true,
+		},
+		{
+			name:        "error snake case parameter keys without snake case parameters",
+			path:        "testdata/invalid_parameter_conversion_keys.yaml",
+			shouldError: true,
+		},
+		{
+			name:        "error duplicate parameter names",
+			path:        "testdata/invalid_parameter_conversion_names.yaml",
+			shouldError: true,
+		},
 		{
 			name:        "error invalid duration",
 			path:        "testdata/invalid_duration.yaml",
@@ -565,6 +600,80 @@ func TestLoad(t *testing.T) { //nolint:gocyclo
 	}
 }
 
+func TestLoadEventTriggers(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	watchSlice, err := Load("testdata/valid_event_triggers.yaml", 1, 2)
+	if err != nil {
+		t.Fatalf("Error occurred unexpectedly: %v", err)
+	}
+	if len(watchSlice) != 1 {
+		t.Fatalf("Unexpected watches length: %v expected: 1", len(watchSlice))
+	}
+
+	expected := []EventTrigger{
+		{
+			Name:               "node-not-ready",
+			InvolvedObjectKind: "Node",
+			Reason:             "NodeNotReady",
+			Playbook:           filepath.Join(wd, "testdata", "playbook.yml"),
+			Vars:               map[string]interface{}{"sentinel": "remediating"},
+			EventVars:          map[string]string{"node_name": "involvedObject.name"},
+		},
+	}
+	if !reflect.DeepEqual(watchSlice[0].EventTriggers, expected) {
+		t.Fatalf("Unexpected event triggers:\n\tgot %#v\n\texpected %#v", watchSlice[0].EventTriggers, expected)
+	}
+}
+
+func TestLoadPlaybookSelector(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	watchSlice, err := Load("testdata/valid_playbook_selector.yaml", 1, 2)
+	if err != nil {
+		t.Fatalf("Error occurred unexpectedly: %v", err)
+	}
+	if len(watchSlice) != 1 {
+		t.Fatalf("Unexpected watches length: %v expected: 1", len(watchSlice))
+	}
+
+	expected := &PlaybookSelector{
+		Field: "spec.mode",
+		Cases: []PlaybookCase{
+			{Value: "restore", Playbook: filepath.Join(wd, "testdata", "playbook.yml")},
+			{Value: "upgrade", Role: filepath.Join(wd, "testdata", "roles", "role")},
+		},
+	}
+	if !reflect.DeepEqual(watchSlice[0].PlaybookSelector, expected) {
+		t.Fatalf("Unexpected playbook selector:\n\tgot %#v\n\texpected %#v", watchSlice[0].PlaybookSelector, expected)
+	}
+}
+
+func TestLoadParameterConversion(t *testing.T) {
+	watchSlice, err := Load("testdata/valid_parameter_conversion.yaml", 1, 2)
+	if err != nil {
+		t.Fatalf("Error occurred unexpectedly: %v", err)
+	}
+	if len(watchSlice) != 1 {
+		t.Fatalf("Unexpected watches length: %v expected: 1", len(watchSlice))
+	}
+
+	w := watchSlice[0]
+	if !w.SnakeCaseParameters {
+		t.Fatalf("Unexpected snakeCaseParameters %v expected true", w.SnakeCaseParameters)
+	}
+	if expected := []string{"storageClass"}; !reflect.DeepEqual(w.SnakeCaseParameterKeys, expected) {
+		t.Fatalf("Unexpected snakeCaseParameterKeys %v expected %v", w.SnakeCaseParameterKeys, expected)
+	}
+	if expected := map[string]string{"clusterConfig": "clusterConfig"}; !reflect.DeepEqual(w.ParameterNames, expected) {
+		t.Fatalf("Unexpected parameterNames %v expected %v", w.ParameterNames, expected)
+	}
+}
+
 func TestMaxConcurrentReconciles(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -0,0 +1,276 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bench measures how quickly an operator reconciles custom resources
+// by creating many of them and waiting for each to become ready.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// RunLabel is set on every custom resource created by a benchmark, with a
+// value unique to the benchmark run.
+const RunLabel = "operator-sdk.bench/run"
+
+// DefaultReadyConditions are the condition types that, if true, mark a custom
+// resource as ready by default: "Ready", and the conditions set by Ansible
+// ("Successful") and Helm ("Deployed") operators once a resource is
+// reconciled.
+var DefaultReadyConditions = []string{"Ready", "Successful", "Deployed"}
+
+// TemplateData is passed to the custom resource template for each custom
+// resource created by a benchmark.
+type TemplateData struct {
+	// Index is the index of the custom resource, from 0 to Count-1.
+	Index int
+	// Name is the default name of the custom resource.
+	Name string
+	// Namespace is the namespace the benchmark runs in.
+	Namespace string
+}
+
+// Options configures a benchmark.
+type Options struct {
+	Client    client.Client
+	Namespace string
+	// Template renders a custom resource manifest from TemplateData. The
+	// resource's name and namespace default to TemplateData's if unset.
+	Template *template.Template
+	// Count is the number of custom resources to create, at most Concurrency
+	// at a time.
+	Count       int
+	Concurrency int
+	NamePrefix  string
+	// ReadyConditions are the condition types that, if any is true, mark a
+	// custom resource as ready.
+	ReadyConditions []string
+	// Timeout is how long to wait for all custom resources to become ready,
+	// checking every PollInterval.
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// MetricsURL, if set, is the URL of the operator's Prometheus metrics,
+	// which are scraped to measure reconciles and resource usage.
+	MetricsURL string
+	// SkipCleanup, if true, leaves the created custom resources in the
+	// cluster.
+	SkipCleanup bool
+}
+
+// Run runs a benchmark and returns its report. Custom resources created by
+// the benchmark are deleted before Run returns unless SkipCleanup is set.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Count <= 0 {
+		return nil, errors.New("count must be positive")
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if len(opts.ReadyConditions) == 0 {
+		opts.ReadyConditions = DefaultReadyConditions
+	}
+
+	runID := rand.String(8)
+	objs, err := renderObjects(opts, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	var scraper *metricsScraper
+	if opts.MetricsURL != "" {
+		scraper = &metricsScraper{url: opts.MetricsURL}
+		if err := scraper.start(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	created, err := createObjects(ctx, opts, objs)
+	if !opts.SkipCleanup {
+		defer deleteObjects(opts.Client, objs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	readyAt, err := waitForReady(ctx, opts, objs[0], runID, created, scraper)
+	if err != nil {
+		return nil, err
+	}
+	end := time.Now()
+
+	r := newReport(objs[0].GroupVersionKind().String(), opts.Count, end.Sub(start), created, readyAt)
+	if scraper != nil {
+		if r.Operator, err = scraper.stop(ctx, end.Sub(start)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// renderObjects renders opts.Count custom resources, labeled with runID.
+func renderObjects(opts Options, runID string) ([]*unstructured.Unstructured, error) {
+	objs := make([]*unstructured.Unstructured, opts.Count)
+	for i := range objs {
+		data := TemplateData{
+			Index:     i,
+			Name:      opts.NamePrefix + strconv.Itoa(i),
+			Namespace: opts.Namespace,
+		}
+		buf := &bytes.Buffer{}
+		if err := opts.Template.Execute(buf, data); err != nil {
+			return nil, fmt.Errorf("error rendering custom resource %d: %v", i, err)
+		}
+		j, err := yaml.YAMLToJSON(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("error decoding custom resource %d: %v", i, err)
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(j); err != nil {
+			return nil, fmt.Errorf("error decoding custom resource %d: %v", i, err)
+		}
+		if i > 0 && obj.GroupVersionKind() != objs[0].GroupVersionKind() {
+			return nil, fmt.Errorf("custom resource %d has kind %s, expected %s", i,
+				obj.GroupVersionKind(), objs[0].GroupVersionKind())
+		}
+		if obj.GetName() == "" {
+			obj.SetName(data.Name)
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(data.Namespace)
+		}
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[RunLabel] = runID
+		obj.SetLabels(labels)
+		objs[i] = obj
+	}
+	return objs, nil
+}
+
+// createObjects creates objs, at most opts.Concurrency at a time, and returns
+// the time each was created, keyed by name.
+func createObjects(ctx context.Context, opts Options, objs []*unstructured.Unstructured) (map[string]time.Time, error) {
+	var (
+		m       sync.Mutex
+		wg      sync.WaitGroup
+		created = make(map[string]time.Time, len(objs))
+		errs    []error
+	)
+	sem := make(chan struct{}, opts.Concurrency)
+	for _, obj := range objs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(obj *unstructured.Unstructured) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := opts.Client.Create(ctx, obj)
+			m.Lock()
+			defer m.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error creating %s: %v", obj.GetName(), err))
+				return
+			}
+			created[obj.GetName()] = time.Now()
+		}(obj)
+	}
+	wg.Wait()
+	if len(errs) != 0 {
+		return created, errs[0]
+	}
+	return created, nil
+}
+
+// waitForReady lists the run's custom resources every opts.PollInterval until
+// all are ready or opts.Timeout elapses, and returns the time each became
+// ready, keyed by name.
+func waitForReady(ctx context.Context, opts Options, example *unstructured.Unstructured, runID string,
+	created map[string]time.Time, scraper *metricsScraper) (map[string]time.Time, error) {
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(example.GroupVersionKind().GroupVersion().WithKind(example.GetKind() + "List"))
+	listOpts := []client.ListOption{
+		client.InNamespace(example.GetNamespace()),
+		client.MatchingLabels{RunLabel: runID},
+	}
+
+	readyAt := make(map[string]time.Time, len(created))
+	err := wait.PollImmediate(opts.PollInterval, opts.Timeout, func() (bool, error) {
+		if scraper != nil {
+			if err := scraper.sample(ctx); err != nil {
+				return false, err
+			}
+		}
+		if err := opts.Client.List(ctx, list, listOpts...); err != nil {
+			return false, err
+		}
+		now := time.Now()
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if _, ok := readyAt[obj.GetName()]; ok {
+				continue
+			}
+			if isReady(obj, opts.ReadyConditions) {
+				readyAt[obj.GetName()] = now
+			}
+		}
+		return len(readyAt) == len(created), nil
+	})
+	if err != nil && !errors.Is(err, wait.ErrWaitTimeout) {
+		return nil, fmt.Errorf("error waiting for custom resources: %v", err)
+	}
+	return readyAt, nil
+}
+
+// isReady returns true if any of obj's conditions with a type in
+// conditionTypes has status "True".
+func isReady(obj *unstructured.Unstructured, conditionTypes []string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["status"] != "True" {
+			continue
+		}
+		for _, t := range conditionTypes {
+			if cond["type"] == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deleteObjects deletes objs, ignoring errors.
+func deleteObjects(c client.Client, objs []*unstructured.Unstructured) {
+	for _, obj := range objs {
+		_ = client.IgnoreNotFound(c.Delete(context.TODO(), obj))
+	}
+}
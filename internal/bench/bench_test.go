@@ -0,0 +1,148 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBench(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bench Suite")
+}
+
+var _ = Describe("renderObjects", func() {
+	opts := Options{
+		Namespace:  "bench",
+		Count:      3,
+		NamePrefix: "memcached-",
+	}
+
+	It("renders, names, and labels each custom resource", func() {
+		opts.Template = template.Must(template.New("cr").Parse(`apiVersion: cache.example.com/v1alpha1
+kind: Memcached
+spec:
+  size: {{ .Index }}
+`))
+		objs, err := renderObjects(opts, "run1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(3))
+		for i, obj := range objs {
+			Expect(obj.GetName()).To(Equal("memcached-" + strconv.Itoa(i)))
+			Expect(obj.GetNamespace()).To(Equal("bench"))
+			Expect(obj.GetLabels()).To(HaveKeyWithValue(RunLabel, "run1"))
+			size, _, _ := unstructured.NestedInt64(obj.Object, "spec", "size")
+			Expect(size).To(Equal(int64(i)))
+		}
+	})
+
+	It("keeps names set by the template", func() {
+		opts.Template = template.Must(template.New("cr").Parse(`apiVersion: cache.example.com/v1alpha1
+kind: Memcached
+metadata:
+  name: custom-{{ .Index }}
+`))
+		objs, err := renderObjects(opts, "run1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs[2].GetName()).To(Equal("custom-2"))
+	})
+
+	It("fails for a manifest without a kind", func() {
+		opts.Template = template.Must(template.New("cr").Parse(`apiVersion: cache.example.com/v1alpha1
+`))
+		_, err := renderObjects(opts, "run1")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("isReady", func() {
+	newObj := func(conditions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"conditions": conditions},
+		}}
+	}
+
+	It("returns true for a true ready condition", func() {
+		obj := newObj(map[string]interface{}{"type": "Deployed", "status": "True"})
+		Expect(isReady(obj, DefaultReadyConditions)).To(BeTrue())
+	})
+	It("returns false for a false ready condition", func() {
+		obj := newObj(map[string]interface{}{"type": "Ready", "status": "False"})
+		Expect(isReady(obj, DefaultReadyConditions)).To(BeFalse())
+	})
+	It("returns false for other conditions", func() {
+		obj := newObj(map[string]interface{}{"type": "Available", "status": "True"})
+		Expect(isReady(obj, DefaultReadyConditions)).To(BeFalse())
+	})
+	It("returns false without a status", func() {
+		Expect(isReady(&unstructured.Unstructured{Object: map[string]interface{}{}}, DefaultReadyConditions)).To(BeFalse())
+	})
+})
+
+var _ = Describe("parseMetrics", func() {
+	It("sums samples across labels", func() {
+		m, err := parseMetrics(strings.NewReader(`# HELP controller_runtime_reconcile_total Total number of reconciliations
+# TYPE controller_runtime_reconcile_total counter
+controller_runtime_reconcile_total{controller="memcached-controller",result="success"} 10
+controller_runtime_reconcile_total{controller="memcached-controller",result="error"} 2
+process_resident_memory_bytes 4.2e+07
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(m).To(HaveKeyWithValue(reconcileTotalMetric, 12.0))
+		Expect(m).To(HaveKeyWithValue(residentMemoryMetric, 4.2e+07))
+	})
+
+	It("fails for an invalid value", func() {
+		_, err := parseMetrics(strings.NewReader("process_cpu_seconds_total abc\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("newReport", func() {
+	It("summarizes time to ready", func() {
+		start := time.Now()
+		created := map[string]time.Time{"a": start, "b": start, "c": start, "d": start}
+		readyAt := map[string]time.Time{
+			"a": start.Add(1 * time.Second),
+			"b": start.Add(2 * time.Second),
+			"c": start.Add(4 * time.Second),
+		}
+		r := newReport("cache.example.com/v1alpha1, Kind=Memcached", 4, 6*time.Second, created, readyAt)
+		Expect(r.Created).To(Equal(4))
+		Expect(r.Ready).To(Equal(3))
+		Expect(r.ReadyPerSecond).To(BeNumerically("~", 0.5))
+		Expect(r.TimeToReady).To(Equal(Stats{
+			Min:  1,
+			Mean: 7.0 / 3,
+			P50:  2,
+			P90:  4,
+			P99:  4,
+			Max:  4,
+		}))
+	})
+
+	It("returns empty stats if nothing is ready", func() {
+		r := newReport("cache.example.com/v1alpha1, Kind=Memcached", 1, time.Second, map[string]time.Time{}, nil)
+		Expect(r.TimeToReady).To(Equal(Stats{}))
+	})
+})
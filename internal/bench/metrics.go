@@ -0,0 +1,139 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metrics scraped from the operator. The reconcile metrics are recorded by
+// controller-runtime for every controller, and the process metrics by the
+// Prometheus client's process collector.
+const (
+	reconcileTotalMetric       = "controller_runtime_reconcile_total"
+	reconcileErrorsTotalMetric = "controller_runtime_reconcile_errors_total"
+	cpuSecondsTotalMetric      = "process_cpu_seconds_total"
+	residentMemoryMetric       = "process_resident_memory_bytes"
+)
+
+// metricsScraper measures the operator's reconciles and resource usage
+// during a benchmark.
+type metricsScraper struct {
+	url string
+
+	first     map[string]float64
+	maxMemory float64
+}
+
+func (s *metricsScraper) start(ctx context.Context) (err error) {
+	s.first, err = s.scrape(ctx)
+	if err != nil {
+		return err
+	}
+	s.maxMemory = s.first[residentMemoryMetric]
+	return nil
+}
+
+// sample scrapes the operator's metrics to track its peak memory usage.
+func (s *metricsScraper) sample(ctx context.Context) error {
+	m, err := s.scrape(ctx)
+	if err != nil {
+		return err
+	}
+	if m[residentMemoryMetric] > s.maxMemory {
+		s.maxMemory = m[residentMemoryMetric]
+	}
+	return nil
+}
+
+// stop returns the operator's statistics since start, which was elapsed ago.
+func (s *metricsScraper) stop(ctx context.Context, elapsed time.Duration) (*OperatorStats, error) {
+	last, err := s.scrape(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if last[residentMemoryMetric] > s.maxMemory {
+		s.maxMemory = last[residentMemoryMetric]
+	}
+	stats := &OperatorStats{
+		Reconciles:      last[reconcileTotalMetric] - s.first[reconcileTotalMetric],
+		ReconcileErrors: last[reconcileErrorsTotalMetric] - s.first[reconcileErrorsTotalMetric],
+		CPUSeconds:      last[cpuSecondsTotalMetric] - s.first[cpuSecondsTotalMetric],
+		MaxMemoryBytes:  s.maxMemory,
+	}
+	if elapsed > 0 {
+		stats.ReconcilesPerSecond = stats.Reconciles / elapsed.Seconds()
+	}
+	return stats, nil
+}
+
+func (s *metricsScraper) scrape(ctx context.Context) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error scraping metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error scraping metrics: unexpected status %s", resp.Status)
+	}
+	return parseMetrics(resp.Body)
+}
+
+// parseMetrics parses metrics in the Prometheus text format, and returns the
+// sum of the samples of each metric across all label values.
+func parseMetrics(r io.Reader) (map[string]float64, error) {
+	metrics := map[string]float64{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name := line
+		if i := strings.IndexAny(line, "{ "); i >= 0 {
+			name = line[:i]
+		}
+		// Labels may contain spaces, so the value follows the closing brace.
+		rest := line[len(name):]
+		if strings.HasPrefix(rest, "{") {
+			i := strings.LastIndex(rest, "}")
+			if i < 0 {
+				return nil, fmt.Errorf("invalid metric line %q", line)
+			}
+			rest = rest[i+1:]
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid metric line %q", line)
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric line %q: %v", line, err)
+		}
+		metrics[name] += v
+	}
+	return metrics, scanner.Err()
+}
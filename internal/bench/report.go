@@ -0,0 +1,130 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+// Report is the result of a benchmark. Durations are in seconds.
+type Report struct {
+	GVK string `json:"gvk"`
+	// Count is the number of custom resources in the benchmark, of which
+	// Created were created and Ready became ready before the timeout.
+	Count   int `json:"count"`
+	Created int `json:"created"`
+	Ready   int `json:"ready"`
+	// Duration is the time from the first create until all custom resources
+	// were ready or the timeout elapsed.
+	Duration float64 `json:"duration"`
+	// ReadyPerSecond is the number of custom resources that became ready per
+	// second of Duration.
+	ReadyPerSecond float64 `json:"readyPerSecond"`
+	// TimeToReady summarizes the time from each ready custom resource's
+	// creation until it was first seen ready.
+	TimeToReady Stats `json:"timeToReady"`
+	// Operator is set if the operator's metrics were scraped.
+	Operator *OperatorStats `json:"operator,omitempty"`
+}
+
+// Stats summarizes a set of durations.
+type Stats struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// OperatorStats are the operator's reconciles and resource usage during a
+// benchmark, from its metrics.
+type OperatorStats struct {
+	Reconciles          float64 `json:"reconciles"`
+	ReconcileErrors     float64 `json:"reconcileErrors"`
+	ReconcilesPerSecond float64 `json:"reconcilesPerSecond"`
+	CPUSeconds          float64 `json:"cpuSeconds"`
+	MaxMemoryBytes      float64 `json:"maxMemoryBytes"`
+}
+
+func newReport(gvk string, count int, elapsed time.Duration, created, readyAt map[string]time.Time) *Report {
+	r := &Report{
+		GVK:      gvk,
+		Count:    count,
+		Created:  len(created),
+		Ready:    len(readyAt),
+		Duration: elapsed.Seconds(),
+	}
+	if elapsed > 0 {
+		r.ReadyPerSecond = float64(r.Ready) / elapsed.Seconds()
+	}
+	var durations []float64
+	for name, ready := range readyAt {
+		if c, ok := created[name]; ok {
+			durations = append(durations, math.Max(ready.Sub(c).Seconds(), 0))
+		}
+	}
+	r.TimeToReady = newStats(durations)
+	return r
+}
+
+func newStats(durations []float64) Stats {
+	if len(durations) == 0 {
+		return Stats{}
+	}
+	sort.Float64s(durations)
+	sum := 0.0
+	for _, d := range durations {
+		sum += d
+	}
+	return Stats{
+		Min:  durations[0],
+		Mean: sum / float64(len(durations)),
+		P50:  percentile(durations, 50),
+		P90:  percentile(durations, 90),
+		P99:  percentile(durations, 99),
+		Max:  durations[len(durations)-1],
+	}
+}
+
+// percentile returns the p-th percentile of sorted using the nearest-rank
+// method.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Print writes a human-readable summary of r to w.
+func (r Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "Kind: %s\n", r.GVK)
+	fmt.Fprintf(w, "Custom resources: %d created, %d ready of %d\n", r.Created, r.Ready, r.Count)
+	fmt.Fprintf(w, "Duration: %.2fs (%.2f ready/s)\n", r.Duration, r.ReadyPerSecond)
+	fmt.Fprintf(w, "Time to ready: min %.2fs, mean %.2fs, p50 %.2fs, p90 %.2fs, p99 %.2fs, max %.2fs\n",
+		r.TimeToReady.Min, r.TimeToReady.Mean, r.TimeToReady.P50, r.TimeToReady.P90, r.TimeToReady.P99,
+		r.TimeToReady.Max)
+	if o := r.Operator; o != nil {
+		fmt.Fprintf(w, "Reconciles: %.0f (%.0f errors, %.2f/s)\n", o.Reconciles, o.ReconcileErrors,
+			o.ReconcilesPerSecond)
+		fmt.Fprintf(w, "Operator CPU: %.2fs\n", o.CPUSeconds)
+		fmt.Fprintf(w, "Operator peak memory: %.1f MiB\n", o.MaxMemoryBytes/(1<<20))
+	}
+}
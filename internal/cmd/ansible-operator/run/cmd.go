@@ -38,9 +38,11 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/ansible/flags"
 	"github.com/operator-framework/operator-sdk/internal/ansible/proxy"
 	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/controllermap"
+	"github.com/operator-framework/operator-sdk/internal/ansible/proxy/permissions"
 	"github.com/operator-framework/operator-sdk/internal/ansible/runner"
 	"github.com/operator-framework/operator-sdk/internal/ansible/watches"
 	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
+	"github.com/operator-framework/operator-sdk/internal/util/metricsutil"
 	sdkVersion "github.com/operator-framework/operator-sdk/internal/version"
 )
 
@@ -142,6 +144,22 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 		options.Namespace = metav1.NamespaceAll
 	}
 
+	// Serve metrics over TLS with a server of our own, since the manager's
+	// metrics listener serves only plain HTTP.
+	metricsServer, err := metricsutil.NewTLSServer(cfg, metricsutil.TLSServer{
+		BindAddress:  f.MetricsAddress,
+		CertFile:     f.MetricsCertFile,
+		KeyFile:      f.MetricsKeyFile,
+		ClientCAFile: f.MetricsClientCAFile,
+	}, f.MetricsRequireRBAC)
+	if err != nil {
+		log.Error(err, "Invalid metrics TLS configuration.")
+		os.Exit(1)
+	}
+	if metricsServer != nil {
+		options.MetricsBindAddress = "0"
+	}
+
 	err = setAnsibleEnvVars(f)
 	if err != nil {
 		log.Error(err, "Failed to set environment variable.")
@@ -155,7 +173,15 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 		os.Exit(1)
 	}
 
+	if metricsServer != nil {
+		if err := mgr.Add(metricsServer); err != nil {
+			log.Error(err, "Failed to add metrics server.")
+			os.Exit(1)
+		}
+	}
+
 	cMap := controllermap.NewControllerMap()
+	permissionTracker := permissions.NewTracker()
 	watches, err := watches.Load(f.WatchesFile, f.MaxConcurrentReconciles, f.AnsibleVerbosity)
 	if err != nil {
 		log.Error(err, "Failed to load watches.")
@@ -176,17 +202,37 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 			MaxConcurrentReconciles: w.MaxConcurrentReconciles,
 			ReconcilePeriod:         w.ReconcilePeriod,
 			Selector:                w.Selector,
+			StandardConditions:      f.StandardConditions,
+			PermissionTracker:       permissionTracker,
 		})
 		if ctr == nil {
 			log.Error(fmt.Errorf("failed to add controller for GVK %v", w.GroupVersionKind.String()), "")
 			os.Exit(1)
 		}
 
+		for _, t := range w.EventTriggers {
+			triggerRunner, err := runner.NewEventTriggerRunner(w, t, f.AnsibleArgs)
+			if err != nil {
+				log.Error(err, "Failed to create event trigger runner")
+				os.Exit(1)
+			}
+			err = controller.AddEventTrigger(mgr, controller.EventTriggerOptions{
+				Trigger:          t,
+				Runner:           triggerRunner,
+				AnsibleDebugLogs: getAnsibleDebugLog(),
+			})
+			if err != nil {
+				log.Error(err, "Failed to add event trigger controller", "Trigger", t.Name)
+				os.Exit(1)
+			}
+		}
+
 		cMap.Store(w.GroupVersionKind, &controllermap.Contents{Controller: *ctr,
 			WatchDependentResources:     w.WatchDependentResources,
 			WatchClusterScopedResources: w.WatchClusterScopedResources,
 			OwnerWatchMap:               controllermap.NewWatchMap(),
 			AnnotationWatchMap:          controllermap.NewWatchMap(),
+			DependentEventDebounce:      f.DependentEventDebounce,
 		}, w.Blacklist)
 	}
 
@@ -207,6 +253,15 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 		ControllerMap:     cMap,
 		OwnerInjection:    f.InjectOwnerRef,
 		WatchedNamespaces: []string{namespace},
+		Transport: proxy.TransportOptions{
+			MaxIdleConns:          f.ProxyMaxIdleConns,
+			MaxIdleConnsPerHost:   f.ProxyMaxIdleConnsPerHost,
+			IdleConnTimeout:       f.ProxyIdleConnTimeout,
+			ResponseHeaderTimeout: f.ProxyResponseHeaderTimeout,
+			DisableHTTP2:          f.ProxyDisableHTTP2,
+		},
+		MaxRequestBodySize: f.ProxyMaxRequestBodySize,
+		PermissionTracker:  permissionTracker,
 	})
 	if err != nil {
 		log.Error(err, "Error starting proxy.")
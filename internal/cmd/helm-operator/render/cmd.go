@@ -0,0 +1,161 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/helm/release"
+	"github.com/operator-framework/operator-sdk/internal/helm/watches"
+)
+
+const longHelp = `Render prints the manifest that the operator would apply for a custom resource, without
+contacting a cluster, as 'helm template' does. The custom resource's kind is looked up in the
+watches file, and its chart is rendered with the watch's default values, the custom resource's
+spec, and the watch's override values, followed by the charts of the watch's sub-releases.
+
+Templates that look up resources in the cluster render as if the resources do not exist, and
+chart dependencies that are missing from a chart's charts/ directory are not rendered.`
+
+const examples = `  # Render the manifest of a sample custom resource:
+  $ helm-operator render config/samples/demo_v1_nginx.yaml --watches-file watches.yaml`
+
+type renderCmd struct {
+	watchesFile    string
+	chartCacheDir  string
+	registryConfig string
+}
+
+// NewCmd returns the 'render' command.
+func NewCmd() *cobra.Command {
+	c := &renderCmd{}
+	cmd := &cobra.Command{
+		Use:     "render <custom-resource-manifest>",
+		Short:   "Prints the manifest the operator would apply for a custom resource",
+		Long:    longHelp,
+		Example: examples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("command %s requires exactly one argument", cmd.CommandPath())
+			}
+			return c.run(cmd.OutOrStdout(), args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(&c.watchesFile, "watches-file", "./watches.yaml", "Path to the watches file to use")
+	cmd.Flags().StringVar(&c.chartCacheDir, "chart-cache-dir",
+		filepath.Join(os.TempDir(), "helm-operator", "charts"),
+		"Directory in which charts pulled from OCI registries are cached")
+	cmd.Flags().StringVar(&c.registryConfig, "registry-config", "",
+		"Path to a Docker config file containing credentials for OCI registries. "+
+			"Defaults to Docker's config file if empty")
+
+	return cmd
+}
+
+func (c renderCmd) run(w io.Writer, crFile string) error {
+	cr, err := readCR(crFile)
+	if err != nil {
+		return err
+	}
+
+	ws, err := watches.Load(c.watchesFile)
+	if err != nil {
+		return fmt.Errorf("error loading watches file: %v", err)
+	}
+	var watch *watches.Watch
+	for i := range ws {
+		if ws[i].GroupVersionKind == cr.GroupVersionKind() {
+			watch = &ws[i]
+			break
+		}
+	}
+	if watch == nil {
+		return fmt.Errorf("no watch for %s in %s", cr.GroupVersionKind(), c.watchesFile)
+	}
+
+	opts := []release.ManagerFactoryOption{release.ReleaseNamespace(watch.ReleaseNamespace)}
+	if watch.ReleaseName != "" {
+		releaseNameTmpl, err := release.ParseReleaseNameTemplate(watch.ReleaseName)
+		if err != nil {
+			return fmt.Errorf("error parsing release name template: %v", err)
+		}
+		opts = append(opts, release.ReleaseNameTemplate(releaseNameTmpl))
+	}
+
+	// Default values only apply to the watch's chart, not its sub-releases.
+	chartOpts := append([]release.ManagerFactoryOption{release.DefaultValues(watch.DefaultValues)}, opts...)
+	manifest, err := c.render(watch.ChartDir, cr, watch.OverrideValues, chartOpts...)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, manifest); err != nil {
+		return err
+	}
+	for _, sub := range watch.SubReleases {
+		subOpts := append([]release.ManagerFactoryOption{
+			release.ReleaseNameSuffix(sub.Name),
+			release.ValuesField(sub.ValuesField),
+		}, opts...)
+		manifest, err := c.render(sub.ChartDir, cr, sub.OverrideValues, subOpts...)
+		if err != nil {
+			return fmt.Errorf("error rendering sub-release %s: %v", sub.Name, err)
+		}
+		if _, err := io.WriteString(w, manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render renders the chart at chartDir, pulling it first if it is an OCI
+// chart reference.
+func (c renderCmd) render(chartDir string, cr *unstructured.Unstructured, overrideValues map[string]string,
+	opts ...release.ManagerFactoryOption) (string, error) {
+	if release.IsOCIChart(chartDir) {
+		path, err := release.PullOCIChart(context.TODO(), chartDir, c.chartCacheDir, c.registryConfig)
+		if err != nil {
+			return "", fmt.Errorf("error pulling chart %s: %v", chartDir, err)
+		}
+		chartDir = path
+	}
+	return release.Render(chartDir, cr, overrideValues, opts...)
+}
+
+// readCR reads a custom resource manifest from path.
+func readCR(path string) (*unstructured.Unstructured, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding custom resource: %v", err)
+	}
+	cr := &unstructured.Unstructured{}
+	if err := cr.UnmarshalJSON(j); err != nil {
+		return nil, fmt.Errorf("error decoding custom resource: %v", err)
+	}
+	return cr, nil
+}
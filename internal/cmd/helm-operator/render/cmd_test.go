@@ -0,0 +1,138 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Running a render command", func() {
+	var (
+		dir         string
+		watchesFile string
+		crFile      string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "helm-operator-render")
+		Expect(err).NotTo(HaveOccurred())
+		chartDir, err := filepath.Abs("../../../plugins/helm/v1/chartutil/testdata/test-chart")
+		Expect(err).NotTo(HaveOccurred())
+
+		watchesFile = filepath.Join(dir, "watches.yaml")
+		Expect(ioutil.WriteFile(watchesFile, []byte(fmt.Sprintf(`---
+- group: example.com
+  version: v1alpha1
+  kind: Nginx
+  chart: %[1]s
+  overrideValues:
+    service.type: NodePort
+  subReleases:
+  - name: extra
+    chart: %[1]s
+    valuesField: extra
+`, chartDir)), 0644)).To(Succeed())
+
+		crFile = filepath.Join(dir, "cr.yaml")
+		Expect(ioutil.WriteFile(crFile, []byte(`apiVersion: example.com/v1alpha1
+kind: Nginx
+metadata:
+  name: example
+  namespace: default
+spec:
+  replicaCount: 3
+  extra:
+    replicaCount: 5
+`), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("builds a cobra command", func() {
+		cmd := NewCmd()
+		Expect(cmd).NotTo(BeNil())
+		Expect(cmd.Use).NotTo(Equal(""))
+		Expect(cmd.Short).NotTo(Equal(""))
+	})
+
+	It("renders the release and sub-releases of a custom resource", func() {
+		cmd := NewCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{crFile, "--watches-file", watchesFile})
+		Expect(cmd.Execute()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("app.kubernetes.io/instance: example\n"))
+		Expect(out.String()).To(ContainSubstring("replicas: 3"))
+		Expect(out.String()).To(ContainSubstring("type: NodePort"))
+		Expect(out.String()).To(ContainSubstring("app.kubernetes.io/instance: example-extra"))
+		Expect(out.String()).To(ContainSubstring("replicas: 5"))
+	})
+
+	It("applies the watch's default values to its chart only", func() {
+		chartDir, err := filepath.Abs("../../../plugins/helm/v1/chartutil/testdata/test-chart")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(watchesFile, []byte(fmt.Sprintf(`---
+- group: example.com
+  version: v1alpha1
+  kind: Nginx
+  chart: %[1]s
+  defaultValues:
+    replicaCount: 7
+  subReleases:
+  - name: extra
+    chart: %[1]s
+    valuesField: extra
+`, chartDir)), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(crFile, []byte(`apiVersion: example.com/v1alpha1
+kind: Nginx
+metadata:
+  name: example
+  namespace: default
+spec:
+  extra: {}
+`), 0644)).To(Succeed())
+
+		cmd := NewCmd()
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		cmd.SetArgs([]string{crFile, "--watches-file", watchesFile})
+		Expect(cmd.Execute()).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("replicas: 7"))
+		Expect(out.String()).To(ContainSubstring("replicas: 1"))
+	})
+
+	It("fails for a kind without a watch", func() {
+		Expect(ioutil.WriteFile(crFile, []byte(`apiVersion: example.com/v1alpha1
+kind: Other
+metadata:
+  name: example
+spec: {}
+`), 0644)).To(Succeed())
+		cmd := NewCmd()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetArgs([]string{crFile, "--watches-file", watchesFile})
+		Expect(cmd.Execute()).To(MatchError(ContainSubstring("no watch for")))
+	})
+})
@@ -15,33 +15,50 @@
 package run
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	zapf "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
+	"github.com/operator-framework/operator-sdk/internal/helm/audit"
+	helmcache "github.com/operator-framework/operator-sdk/internal/helm/cache"
+	helmclient "github.com/operator-framework/operator-sdk/internal/helm/client"
 	"github.com/operator-framework/operator-sdk/internal/helm/controller"
+	"github.com/operator-framework/operator-sdk/internal/helm/conversion"
+	"github.com/operator-framework/operator-sdk/internal/helm/debug"
 	"github.com/operator-framework/operator-sdk/internal/helm/flags"
+	"github.com/operator-framework/operator-sdk/internal/helm/metrics"
 	"github.com/operator-framework/operator-sdk/internal/helm/release"
 	"github.com/operator-framework/operator-sdk/internal/helm/watches"
 	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
+	"github.com/operator-framework/operator-sdk/internal/util/metricsutil"
 	sdkVersion "github.com/operator-framework/operator-sdk/internal/version"
 )
 
 var log = logf.Log.WithName("cmd")
 
+// throttleBackoffBase is the backoff after the first throttled response from
+// the API server.
+const throttleBackoffBase = time.Second
+
 func printVersion() {
 	log.Info("Version",
 		"Go Version", runtime.Version(),
@@ -94,6 +111,7 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 	// Set default manager options
 	options := manager.Options{
 		MetricsBindAddress:      f.MetricsAddress,
+		HealthProbeBindAddress:  f.HealthProbeAddress,
 		LeaderElection:          f.EnableLeaderElection,
 		LeaderElectionID:        f.LeaderElectionID,
 		LeaderElectionNamespace: f.LeaderElectionNamespace,
@@ -110,20 +128,36 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 		},
 	}
 
+	// Serve metrics over TLS with a server of our own, since the manager's
+	// metrics listener serves only plain HTTP.
+	metricsServer, err := metricsutil.NewTLSServer(cfg, metricsutil.TLSServer{
+		BindAddress:  f.MetricsAddress,
+		CertFile:     f.MetricsCertFile,
+		KeyFile:      f.MetricsKeyFile,
+		ClientCAFile: f.MetricsClientCAFile,
+	}, f.MetricsRequireRBAC)
+	if err != nil {
+		log.Error(err, "Invalid metrics TLS configuration.")
+		os.Exit(1)
+	}
+	if metricsServer != nil {
+		options.MetricsBindAddress = "0"
+	}
+
 	namespace, found := os.LookupEnv(k8sutil.WatchNamespaceEnvVar)
 	log = log.WithValues("Namespace", namespace)
+	namespaces := splitNamespaces(namespace)
 	if found {
-		if namespace == metav1.NamespaceAll {
+		switch len(namespaces) {
+		case 0:
 			log.Info("Watching all namespaces.")
 			options.Namespace = metav1.NamespaceAll
-		} else {
-			if strings.Contains(namespace, ",") {
-				log.Info("Watching multiple namespaces.")
-				options.NewCache = cache.MultiNamespacedCacheBuilder(strings.Split(namespace, ","))
-			} else {
-				log.Info("Watching single namespace.")
-				options.Namespace = namespace
-			}
+		case 1:
+			log.Info("Watching single namespace.")
+			options.Namespace = namespaces[0]
+		default:
+			log.Info("Watching multiple namespaces.")
+			options.NewCache = helmcache.MultiNamespacedCacheBuilder(namespaces)
 		}
 	} else {
 		log.Info(fmt.Sprintf("%v environment variable not set. Watching all namespaces.",
@@ -137,26 +171,200 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 		os.Exit(1)
 	}
 
+	if metricsServer != nil {
+		if err := mgr.Add(metricsServer); err != nil {
+			log.Error(err, "Failed to add metrics server.")
+			os.Exit(1)
+		}
+	}
+	if f.DebugAddress != "" {
+		debugServer, err := newDebugServer(cfg, f)
+		if err != nil {
+			log.Error(err, "Invalid debug endpoint configuration.")
+			os.Exit(1)
+		}
+		if err := mgr.Add(debugServer); err != nil {
+			log.Error(err, "Failed to add debug server.")
+			os.Exit(1)
+		}
+	}
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		log.Error(err, "Failed to add Healthz check.")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
+		log.Error(err, "Failed to add Readyz check.")
+		os.Exit(1)
+	}
+
+	var auditLogger *audit.Logger
+	if f.AuditLogFile != "" {
+		auditLogger, err = audit.NewLogger(f.AuditLogFile, int64(f.AuditLogMaxSize)*1024*1024, f.AuditLogMaxBackups)
+		if err != nil {
+			log.Error(err, "Failed to open audit log.", "file", f.AuditLogFile)
+			os.Exit(1)
+		}
+		defer auditLogger.Close()
+	}
+
 	ws, err := watches.Load(f.WatchesFile)
 	if err != nil {
 		log.Error(err, "Failed to create new manager factories.")
 		os.Exit(1)
 	}
+	// Share one throttling backoff between all releases, so that a throttled
+	// response slows down every release operation.
+	var baseFactoryOpts []release.ManagerFactoryOption
+	if f.ThrottleBackoffMax > 0 {
+		throttleBackoff := helmclient.NewThrottleBackoff(throttleBackoffBase, f.ThrottleBackoffMax)
+		baseFactoryOpts = append(baseFactoryOpts, release.WrapRESTConfig(func(cfg *rest.Config) (*rest.Config, error) {
+			wrap := cfg.WrapTransport
+			cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+				if wrap != nil {
+					rt = wrap(rt)
+				}
+				return throttleBackoff.WrapTransport(rt)
+			}
+			return cfg, nil
+		}))
+	}
+
+	gvks := make([]schema.GroupVersionKind, 0, len(ws))
 	for _, w := range ws {
+		gvks = append(gvks, w.GroupVersionKind)
+	}
+	controllerNames := controller.ControllerNames(gvks)
+
+	conversionWebhook := conversion.NewWebhook()
+	for _, w := range ws {
+		if w.ChartDir, err = prepareChart(f, w.ChartDir); err != nil {
+			log.Error(err, "Failed to prepare chart.", "GVK", w.GroupVersionKind.String())
+			os.Exit(1)
+		}
+		if f.LintCharts {
+			if err := lintChart(w.GroupVersionKind, w.ChartDir); err != nil {
+				log.Error(err, "Chart failed lint.", "GVK", w.GroupVersionKind.String())
+				os.Exit(1)
+			}
+		}
+
+		maxHistory := f.MaxReleaseHistory
+		if w.MaxHistory != nil {
+			maxHistory = *w.MaxHistory
+		}
+		factoryOpts := append([]release.ManagerFactoryOption{
+			release.MaxHistory(maxHistory),
+			release.ReleaseNamespace(w.ReleaseNamespace),
+		}, baseFactoryOpts...)
+		if w.DriftPatchStrategy != "" {
+			factoryOpts = append(factoryOpts, release.DriftPatchStrategy(w.DriftPatchStrategy))
+		}
+		for _, s := range w.DriftPatchStrategyOverrides {
+			gk := schema.GroupKind{Group: s.Group, Kind: s.Kind}
+			factoryOpts = append(factoryOpts, release.KindDriftPatchStrategy(gk, s.Strategy))
+		}
+		if w.ReleaseName != "" {
+			releaseNameTmpl, err := release.ParseReleaseNameTemplate(w.ReleaseName)
+			if err != nil {
+				log.Error(err, "Failed to parse release name template.", "GVK", w.GroupVersionKind.String())
+				os.Exit(1)
+			}
+			factoryOpts = append(factoryOpts, release.ReleaseNameTemplate(releaseNameTmpl))
+		}
+		if w.AdoptExistingRelease {
+			factoryOpts = append(factoryOpts, release.AdoptExistingReleases(true))
+		}
+		if w.AdoptExistingResources {
+			factoryOpts = append(factoryOpts, release.AdoptExistingResources(true))
+		}
+		if w.Uninstall != nil {
+			factoryOpts = append(factoryOpts, release.WithUninstallPolicy(release.UninstallPolicy{
+				PropagationPolicy: w.Uninstall.PropagationPolicy,
+				Wait:              w.Uninstall.Wait,
+				Timeout:           durationOrZero(w.Uninstall.Timeout),
+				KeepResources:     w.Uninstall.KeepResources,
+			}))
+		}
+		// Default values only apply to the watch's chart, not its sub-releases.
+		chartOpts := append([]release.ManagerFactoryOption{
+			release.DefaultValues(w.DefaultValues),
+		}, factoryOpts...)
+		managerFactory := release.NewManagerFactory(mgr, w.ChartDir, chartOpts...)
+		if w.ChartVerification != nil {
+			managerFactory = release.NewVerifyingManagerFactory(mgr, w.ChartDir, w.ChartVerification.Keyring,
+				chartOpts...)
+		}
+
+		var subReleases []controller.SubRelease
+		for _, sub := range w.SubReleases {
+			chartDir, err := prepareChart(f, sub.ChartDir)
+			if err != nil {
+				log.Error(err, "Failed to prepare sub-release chart.", "GVK", w.GroupVersionKind.String(),
+					"SubRelease", sub.Name)
+				os.Exit(1)
+			}
+			if f.LintCharts {
+				if err := lintChart(w.GroupVersionKind, chartDir); err != nil {
+					log.Error(err, "Sub-release chart failed lint.", "GVK", w.GroupVersionKind.String(),
+						"SubRelease", sub.Name)
+					os.Exit(1)
+				}
+			}
+			subOpts := append([]release.ManagerFactoryOption{
+				release.ReleaseNameSuffix(sub.Name),
+				release.ValuesField(sub.ValuesField),
+			}, factoryOpts...)
+			subReleases = append(subReleases, controller.SubRelease{
+				Name:           sub.Name,
+				ManagerFactory: release.NewManagerFactory(mgr, chartDir, subOpts...),
+				OverrideValues: sub.OverrideValues,
+			})
+		}
+
 		// Register the controller with the factory.
-		err := controller.Add(mgr, controller.WatchOptions{
-			Namespace:               namespace,
+		opts := controller.WatchOptions{
+			Namespaces:              namespaces,
 			GVK:                     w.GroupVersionKind,
-			ManagerFactory:          release.NewManagerFactory(mgr, w.ChartDir),
+			ManagerFactory:          managerFactory,
 			ReconcilePeriod:         f.ReconcilePeriod,
 			WatchDependentResources: *w.WatchDependentResources,
 			OverrideValues:          w.OverrideValues,
 			MaxConcurrentReconciles: f.MaxConcurrentReconciles,
-		})
+			InstallTimeout:          durationOrZero(w.InstallTimeout),
+			UpgradeTimeout:          durationOrZero(w.UpgradeTimeout),
+			AuditLogger:             auditLogger,
+			ValidateValuesSchema:    w.ValidateValuesSchema,
+			RollbackOnFailure:       w.RollbackOnFailure,
+			SubReleases:             subReleases,
+			StandardConditions:      f.StandardConditions,
+			ControllerName:          controllerNames[w.GroupVersionKind],
+		}
+		if w.DependentResources != nil {
+			opts.IncludeDependentKinds = groupKinds(w.DependentResources.Include)
+			opts.ExcludeDependentKinds = groupKinds(w.DependentResources.Exclude)
+		}
+		if w.OperandNamespace != nil {
+			opts.OperandNamespace = &controller.OperandNamespace{
+				ValuesField: w.OperandNamespace.ValuesField,
+				Labels:      w.OperandNamespace.Labels,
+				Annotations: w.OperandNamespace.Annotations,
+			}
+		}
+		err := controller.Add(mgr, opts)
 		if err != nil {
 			log.Error(err, "Failed to add manager factory to controller.")
 			os.Exit(1)
 		}
+
+		if w.ConversionFile != "" || len(w.Conversions) != 0 {
+			conversionWebhook.Add(w.GroupVersionKind, w.Conversions)
+		}
+	}
+
+	// Serve the conversion webhook only if a watch has conversions, since the
+	// webhook server requires serving certificates.
+	if conversionWebhook.Len() != 0 {
+		mgr.GetWebhookServer().Register(conversion.WebhookPath, conversionWebhook)
 	}
 
 	// Start the Cmd
@@ -165,3 +373,83 @@ func run(cmd *cobra.Command, f *flags.Flags) {
 		os.Exit(1)
 	}
 }
+
+// newDebugServer returns a server of the releases deployed by the operator
+// on f.DebugAddress.
+func newDebugServer(cfg *rest.Config, f *flags.Flags) (*debug.Server, error) {
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	server := &debug.Server{BindAddress: f.DebugAddress, Client: client.CoreV1()}
+	if err := server.Validate(); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// prepareChart pulls the chart at chartDir if it is an OCI chart reference,
+// or downloads its dependencies unless the operator is offline, and returns
+// the path of the chart to load.
+func prepareChart(f *flags.Flags, chartDir string) (string, error) {
+	if release.IsOCIChart(chartDir) {
+		path, err := release.PullOCIChart(context.TODO(), chartDir, f.ChartCacheDir, f.RegistryConfig)
+		if err != nil {
+			return "", fmt.Errorf("failed to pull chart: %v", err)
+		}
+		log.Info("Pulled chart.", "Chart", chartDir, "Path", path)
+		return path, nil
+	}
+	if f.Offline {
+		return chartDir, nil
+	}
+	built, err := release.BuildDependencies(chartDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to build chart dependencies: %v", err)
+	}
+	if built {
+		log.Info("Downloaded chart dependencies.", "Chart", chartDir)
+	}
+	return chartDir, nil
+}
+
+// lintChart runs lint rules against the chart at chartDir of gvk's watch,
+// logging and recording its warnings, and returns an error if it fails a rule.
+func lintChart(gvk schema.GroupVersionKind, chartDir string) error {
+	warnings, err := release.LintChart(chartDir)
+	for _, warning := range warnings {
+		log.Info("Chart lint warning.", "GVK", gvk.String(), "Chart", chartDir, "Warning", warning)
+	}
+	metrics.ChartLinted(gvk.String(), chartDir, len(warnings))
+	return err
+}
+
+// groupKinds converts in to a slice of schema.GroupKind.
+func groupKinds(in []metav1.GroupKind) []schema.GroupKind {
+	var out []schema.GroupKind
+	for _, gk := range in {
+		out = append(out, schema.GroupKind{Group: gk.Group, Kind: gk.Kind})
+	}
+	return out
+}
+
+// splitNamespaces returns the namespaces in the comma-separated list
+// namespace, ignoring whitespace and empty entries. An empty result means all
+// namespaces.
+func splitNamespaces(namespace string) []string {
+	var namespaces []string
+	for _, ns := range strings.Split(namespace, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// durationOrZero returns d's duration, or 0 if d is nil.
+func durationOrZero(d *metav1.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.Duration
+}
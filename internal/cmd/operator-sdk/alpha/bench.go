@@ -0,0 +1,185 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/operator-framework/operator-sdk/internal/bench"
+	"github.com/operator-framework/operator-sdk/internal/olm/operator"
+)
+
+const benchLongHelp = `
+Running 'alpha bench' creates many custom resources from a template, waits for the operator to
+reconcile each of them, and reports how long they took to become ready. Use it to plan the
+capacity of an operator, or to compare its throughput across changes.
+
+The template is a custom resource manifest, rendered with Go's text/template for each custom
+resource with these fields:
+  .Index      the index of the custom resource, from 0 to --count - 1
+  .Name       the default name of the custom resource, --name-prefix followed by .Index
+  .Namespace  the namespace the benchmark runs in
+
+A custom resource is ready once any of its status conditions with a type in --ready-condition
+is "True". The defaults cover Helm and Ansible operators, and Go operators that set a "Ready"
+condition.
+
+If --metrics-url is set, the operator's Prometheus metrics are scraped from that URL to report
+its reconciles, CPU time, and peak memory during the benchmark. Port-forward to the operator's
+metrics port to make them reachable.
+
+Created custom resources are deleted when the benchmark finishes, unless --skip-cleanup is set.
+`
+
+const benchExamples = `
+  # Create 100 Memcached custom resources, 10 at a time:
+  $ operator-sdk alpha bench --cr-template config/samples/cache_v1alpha1_memcached.yaml --count 100
+  Kind: cache.example.com/v1alpha1, Kind=Memcached
+  Custom resources: 100 created, 100 ready of 100
+  Duration: 41.35s (2.42 ready/s)
+  Time to ready: min 2.01s, mean 18.22s, p50 18.10s, p90 34.07s, p99 38.12s, max 38.12s
+
+  # Also report the operator's reconciles and resource usage:
+  $ kubectl port-forward -n memcached-operator-system deploy/memcached-operator-controller-manager 8080 &
+  $ operator-sdk alpha bench --cr-template memcached.yaml --count 100 --metrics-url http://localhost:8080/metrics
+`
+
+type benchCmd struct {
+	crTemplate      string
+	count           int
+	concurrency     int
+	namePrefix      string
+	readyConditions []string
+	timeout         time.Duration
+	pollInterval    time.Duration
+	metricsURL      string
+	skipCleanup     bool
+	output          string
+
+	cfg operator.Configuration
+}
+
+// newBenchCmd returns the 'bench' command.
+func newBenchCmd() *cobra.Command {
+	c := &benchCmd{}
+	cmd := &cobra.Command{
+		Use:     "bench",
+		Short:   "Measures how quickly an operator reconciles many custom resources",
+		Long:    benchLongHelp,
+		Example: benchExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("command %s doesn't accept any arguments", cmd.CommandPath())
+			}
+			if err := c.validate(); err != nil {
+				return err
+			}
+			return c.run(cmd.OutOrStdout())
+		},
+	}
+
+	c.addFlagsTo(cmd.Flags())
+
+	return cmd
+}
+
+func (c *benchCmd) addFlagsTo(fs *pflag.FlagSet) {
+	fs.StringVar(&c.crTemplate, "cr-template", "", "Path to a custom resource manifest template")
+	fs.IntVar(&c.count, "count", 10, "Number of custom resources to create")
+	fs.IntVar(&c.concurrency, "concurrency", 10, "Maximum number of custom resources to create at a time")
+	fs.StringVar(&c.namePrefix, "name-prefix", "bench-", "Prefix of the default custom resource names")
+	fs.StringSliceVar(&c.readyConditions, "ready-condition", bench.DefaultReadyConditions,
+		"Status condition types that mark a custom resource as ready if any is true")
+	fs.DurationVar(&c.timeout, "timeout", 10*time.Minute, "Time to wait for all custom resources to become ready")
+	fs.DurationVar(&c.pollInterval, "poll-interval", 2*time.Second, "Time between checks of custom resource status")
+	fs.StringVar(&c.metricsURL, "metrics-url", "", "URL of the operator's Prometheus metrics, "+
+		"ex. http://localhost:8080/metrics")
+	fs.BoolVar(&c.skipCleanup, "skip-cleanup", false, "Do not delete the created custom resources")
+	fs.StringVarP(&c.output, "output", "o", "text", "Report format, one of: text, json")
+	c.cfg.BindFlags(fs)
+}
+
+func (c benchCmd) validate() error {
+	if c.crTemplate == "" {
+		return errors.New("--cr-template must be set")
+	}
+	if c.count <= 0 {
+		return errors.New("--count must be positive")
+	}
+	if c.concurrency <= 0 {
+		return errors.New("--concurrency must be positive")
+	}
+	if c.output != "text" && c.output != "json" {
+		return fmt.Errorf("invalid --output %q: must be one of: text, json", c.output)
+	}
+	return nil
+}
+
+func (c benchCmd) run(w io.Writer) error {
+	b, err := ioutil.ReadFile(c.crTemplate)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(filepath.Base(c.crTemplate)).Parse(string(b))
+	if err != nil {
+		return fmt.Errorf("error parsing custom resource template: %v", err)
+	}
+	if err := c.cfg.Load(); err != nil {
+		return fmt.Errorf("error loading cluster configuration: %v", err)
+	}
+
+	report, err := bench.Run(context.TODO(), bench.Options{
+		Client:          c.cfg.Client,
+		Namespace:       c.cfg.Namespace,
+		Template:        tmpl,
+		Count:           c.count,
+		Concurrency:     c.concurrency,
+		NamePrefix:      c.namePrefix,
+		ReadyConditions: c.readyConditions,
+		Timeout:         c.timeout,
+		PollInterval:    c.pollInterval,
+		MetricsURL:      c.metricsURL,
+		SkipCleanup:     c.skipCleanup,
+	})
+	if err != nil {
+		return fmt.Errorf("error running benchmark: %v", err)
+	}
+
+	if c.output == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		report.Print(w)
+	}
+	if report.Ready < report.Count {
+		return fmt.Errorf("%d of %d custom resources did not become ready within %s",
+			report.Count-report.Ready, report.Count, c.timeout)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	internalregistry "github.com/operator-framework/operator-sdk/internal/registry"
+	"github.com/operator-framework/operator-sdk/internal/registry/bundlediff"
+)
+
+const bundleDiffLongHelp = `
+Running 'alpha bundle-diff' compares two operator bundles, each either a bundle directory or a bundle image,
+and prints the changes from the old bundle to the new one that affect upgrades:
+
+- CSV fields used to build the upgrade graph, such as 'spec.version', 'spec.replaces', and install modes.
+- CRDs and their schemas. Each change is classified as compatible or BREAKING, where breaking changes
+  are those that existing custom resources or clients may not be compatible with, such as removed
+  versions or fields, changed field types, and new required fields.
+- RBAC rules in the CSV's cluster permissions and permissions, where '+' marks an added permission and
+  '-' a removed one.
+- Images of the CSV's deployments and related images.
+
+Bundle images are pulled and unpacked into a temporary directory.
+`
+
+const bundleDiffExamples = `
+  # Compare the previous release's bundle image with the bundle directory of the next release:
+  $ operator-sdk alpha bundle-diff quay.io/example/memcached-operator-bundle:v0.0.1 ./bundle
+  CSV:
+    metadata.name: "memcached-operator.v0.0.1" -> "memcached-operator.v0.0.2"
+    spec.replaces: added "memcached-operator.v0.0.1"
+  CRDs:
+    [BREAKING] memcacheds.cache.example.com v1alpha1 .spec.size: type changed from "integer" to "string"
+    [compatible] memcacheds.cache.example.com v1alpha1 .spec.labels: field added
+  Cluster permissions:
+    + list deployments.apps
+  Images:
+    memcached-operator-controller-manager/manager: "quay.io/example/memcached-operator:v0.0.1" -> "quay.io/example/memcached-operator:v0.0.2"
+`
+
+type bundleDiffCmd struct {
+	failOnBreaking bool
+	timeout        time.Duration
+}
+
+// newBundleDiffCmd returns the 'bundle-diff' command.
+func newBundleDiffCmd() *cobra.Command {
+	c := &bundleDiffCmd{}
+	cmd := &cobra.Command{
+		Use:     "bundle-diff <old-bundle> <new-bundle>",
+		Short:   "Prints the upgrade-relevant differences between two operator bundles",
+		Long:    bundleDiffLongHelp,
+		Example: bundleDiffExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("command %s requires exactly two arguments", cmd.CommandPath())
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+			defer cancel()
+			return c.run(ctx, cmd.OutOrStdout(), args[0], args[1])
+		},
+	}
+
+	c.addFlagsTo(cmd.Flags())
+
+	return cmd
+}
+
+func (c *bundleDiffCmd) addFlagsTo(fs *pflag.FlagSet) {
+	fs.BoolVar(&c.failOnBreaking, "fail-on-breaking", false, "Exit with an error if any CRD change is breaking")
+	fs.DurationVar(&c.timeout, "timeout", 2*time.Minute, "Time to wait for bundle images to be pulled")
+}
+
+func (c bundleDiffCmd) run(ctx context.Context, w io.Writer, oldRaw, newRaw string) error {
+	oldBundle, err := loadBundle(ctx, oldRaw)
+	if err != nil {
+		return err
+	}
+	newBundle, err := loadBundle(ctx, newRaw)
+	if err != nil {
+		return err
+	}
+
+	report, err := bundlediff.Compare(oldBundle, newBundle)
+	if err != nil {
+		return fmt.Errorf("error comparing bundles: %v", err)
+	}
+	report.WriteText(w)
+
+	if c.failOnBreaking && report.HasBreakingChanges() {
+		return errors.New("new bundle has breaking CRD changes")
+	}
+	return nil
+}
+
+// loadBundle loads the bundle in directory bundleRaw, or if bundleRaw is not a
+// directory, from the bundle image bundleRaw.
+func loadBundle(ctx context.Context, bundleRaw string) (*apimanifests.Bundle, error) {
+	if info, err := os.Stat(bundleRaw); err == nil && info.IsDir() {
+		return bundlediff.LoadBundle(bundleRaw)
+	}
+
+	logger := log.WithFields(log.Fields{"image": bundleRaw})
+	logger.Info("Pulling and unpacking bundle image")
+	dir, err := internalregistry.ExtractBundleImage(ctx, logger, bundleRaw, false)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Errorf("Error removing temp bundle dir: %v", err)
+		}
+	}()
+	return bundlediff.LoadBundle(dir)
+}
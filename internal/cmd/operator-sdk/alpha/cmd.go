@@ -0,0 +1,50 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd returns the 'alpha' command, which contains subcommands whose
+// behavior and flags may change in future releases.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alpha",
+		Short: "Run an alpha subcommand",
+		Long: `The 'operator-sdk alpha' command contains subcommands that are experimental.
+Their behavior and flags may change in future releases.`,
+	}
+
+	cmd.AddCommand(
+		newBenchCmd(),
+		newBundleDiffCmd(),
+		newGenerateCmd(),
+	)
+	return cmd
+}
+
+// newGenerateCmd returns the 'alpha generate' command.
+func newGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate <generator>",
+		Short: "Invokes a specific alpha generator",
+	}
+
+	cmd.AddCommand(
+		newConfigRBACDiffCmd(),
+	)
+	return cmd
+}
@@ -0,0 +1,243 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alpha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/generate/rbacdiff"
+	"github.com/operator-framework/operator-sdk/internal/olm/operator"
+	"github.com/operator-framework/operator-sdk/internal/plugins/util/kustomize"
+)
+
+const configRBACDiffLongHelp = `
+Running 'alpha generate config-rbac-diff' compares the Roles and ClusterRoles in the project's
+'config/rbac' directory with deployed rules, and reports permissions that are missing from or in
+excess of the project's rules. Use it to find RBAC drift, for example after rules were manually
+edited in a cluster.
+
+By default, each role is compared with the role of the same name in the cluster. Role names are
+prefixed with the 'namePrefix' in 'config/default/kustomization.yaml', and namespaced Roles without
+a namespace are looked up in that file's 'namespace', unless overridden by flags.
+
+If '--csv' is set, the project's ClusterRole rules are instead compared with the CSV's
+'clusterPermissions', and its Role rules with the CSV's 'permissions'.
+
+Permissions granted by wildcards are not reported as missing.
+`
+
+const configRBACDiffExamples = `
+  # Compare config/rbac with the roles deployed by 'make deploy':
+  $ operator-sdk alpha generate config-rbac-diff
+  ClusterRole memcached-operator-manager-role:
+    missing (in project, not deployed):
+      - update deployments.apps
+  Role memcached-operator-system/memcached-operator-leader-election-role: no differences
+
+  # Compare config/rbac with a bundle's CSV:
+  $ operator-sdk alpha generate config-rbac-diff --csv bundle/manifests/memcached-operator.clusterserviceversion.yaml
+`
+
+type configRBACDiffCmd struct {
+	rbacDir    string
+	csvPath    string
+	namePrefix string
+	namespace  string
+
+	cfg operator.Configuration
+}
+
+// newConfigRBACDiffCmd returns the 'config-rbac-diff' command.
+func newConfigRBACDiffCmd() *cobra.Command {
+	c := &configRBACDiffCmd{}
+	cmd := &cobra.Command{
+		Use:     "config-rbac-diff",
+		Short:   "Compares the project's RBAC rules with rules deployed in a cluster or a CSV",
+		Long:    configRBACDiffLongHelp,
+		Example: configRBACDiffExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("command %s doesn't accept any arguments", cmd.CommandPath())
+			}
+			if err := c.setDefaults(cmd.Flags()); err != nil {
+				return err
+			}
+			return c.run(cmd.OutOrStdout())
+		},
+	}
+
+	c.addFlagsTo(cmd.Flags())
+
+	return cmd
+}
+
+func (c *configRBACDiffCmd) addFlagsTo(fs *pflag.FlagSet) {
+	fs.StringVar(&c.rbacDir, "rbac-dir", filepath.Join("config", "rbac"),
+		"Directory containing the project's Roles and ClusterRoles")
+	fs.StringVar(&c.csvPath, "csv", "", "Path to a ClusterServiceVersion to compare with instead of the cluster")
+	fs.StringVar(&c.namePrefix, "name-prefix", "", "Prefix of deployed role names. "+
+		"Defaults to the namePrefix in config/default/kustomization.yaml")
+	fs.StringVar(&c.namespace, "role-namespace", "", "Namespace of deployed Roles without a namespace. "+
+		"Defaults to the namespace in config/default/kustomization.yaml")
+	c.cfg.BindFlags(fs)
+}
+
+// setDefaults sets the name prefix and role namespace from the project's
+// default kustomization.yaml, unless set by flags.
+func (c *configRBACDiffCmd) setDefaults(fs *pflag.FlagSet) error {
+	if c.csvPath != "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(filepath.Join("config", "default", kustomize.File))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	k := struct {
+		NamePrefix string `json:"namePrefix"`
+		Namespace  string `json:"namespace"`
+	}{}
+	if err := yaml.Unmarshal(b, &k); err != nil {
+		return fmt.Errorf("error reading default kustomization: %v", err)
+	}
+	if !fs.Changed("name-prefix") {
+		c.namePrefix = k.NamePrefix
+	}
+	if !fs.Changed("role-namespace") {
+		c.namespace = k.Namespace
+	}
+	return nil
+}
+
+func (c configRBACDiffCmd) run(w io.Writer) error {
+	roles, err := rbacdiff.ReadDir(c.rbacDir)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return fmt.Errorf("no Roles or ClusterRoles found in %s", c.rbacDir)
+	}
+
+	if c.csvPath != "" {
+		return c.diffCSV(w, roles)
+	}
+	return c.diffCluster(w, roles)
+}
+
+// diffCluster compares each of roles with the deployed role of the same name.
+func (c configRBACDiffCmd) diffCluster(w io.Writer, roles []rbacdiff.Role) error {
+	if err := c.cfg.Load(); err != nil {
+		return fmt.Errorf("error loading cluster configuration: %v", err)
+	}
+	ctx := context.TODO()
+
+	for _, role := range roles {
+		deployed := rbacdiff.Role{Kind: role.Kind, Name: c.namePrefix + role.Name}
+		var err error
+		if role.Kind == "ClusterRole" {
+			cr := rbacv1.ClusterRole{}
+			err = c.cfg.Client.Get(ctx, types.NamespacedName{Name: deployed.Name}, &cr)
+			deployed.Rules = cr.Rules
+		} else {
+			deployed.Namespace = role.Namespace
+			if deployed.Namespace == "" {
+				deployed.Namespace = c.namespace
+			}
+			if deployed.Namespace == "" {
+				deployed.Namespace = c.cfg.Namespace
+			}
+			r := rbacv1.Role{}
+			err = c.cfg.Client.Get(ctx, types.NamespacedName{Namespace: deployed.Namespace, Name: deployed.Name}, &r)
+			deployed.Rules = r.Rules
+		}
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(w, "%s: not found\n", deployed)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("error getting %s: %v", deployed, err)
+		}
+		printDiff(w, deployed.String(), rbacdiff.Compare(role.Rules, deployed.Rules))
+	}
+	return nil
+}
+
+// diffCSV compares the rules of all ClusterRoles and Roles in roles with the
+// CSV's cluster permissions and permissions, respectively.
+func (c configRBACDiffCmd) diffCSV(w io.Writer, roles []rbacdiff.Role) error {
+	b, err := ioutil.ReadFile(c.csvPath)
+	if err != nil {
+		return err
+	}
+	csv := v1alpha1.ClusterServiceVersion{}
+	if err := yaml.Unmarshal(b, &csv); err != nil {
+		return fmt.Errorf("error reading CSV %s: %v", c.csvPath, err)
+	}
+	strategy := csv.Spec.InstallStrategy.StrategySpec
+
+	var clusterRules, rules []rbacv1.PolicyRule
+	for _, role := range roles {
+		if role.Kind == "ClusterRole" {
+			clusterRules = append(clusterRules, role.Rules...)
+		} else {
+			rules = append(rules, role.Rules...)
+		}
+	}
+	printDiff(w, "clusterPermissions", rbacdiff.Compare(clusterRules, permissionRules(strategy.ClusterPermissions)))
+	printDiff(w, "permissions", rbacdiff.Compare(rules, permissionRules(strategy.Permissions)))
+	return nil
+}
+
+// permissionRules returns the rules of all perms.
+func permissionRules(perms []v1alpha1.StrategyDeploymentPermissions) (rules []rbacv1.PolicyRule) {
+	for _, perm := range perms {
+		rules = append(rules, perm.Rules...)
+	}
+	return rules
+}
+
+func printDiff(w io.Writer, name string, diff rbacdiff.Diff) {
+	if diff.IsEmpty() {
+		fmt.Fprintf(w, "%s: no differences\n", name)
+		return
+	}
+	fmt.Fprintf(w, "%s:\n", name)
+	if len(diff.Missing) != 0 {
+		fmt.Fprintln(w, "  missing (in project, not deployed):")
+		for _, p := range diff.Missing {
+			fmt.Fprintf(w, "    - %s\n", p)
+		}
+	}
+	if len(diff.Excess) != 0 {
+		fmt.Fprintln(w, "  excess (deployed, not in project):")
+		for _, p := range diff.Excess {
+			fmt.Fprintf(w, "    - %s\n", p)
+		}
+	}
+}
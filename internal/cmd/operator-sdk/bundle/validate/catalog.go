@@ -0,0 +1,291 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	apierrors "github.com/operator-framework/api/pkg/validation/errors"
+	registryimage "github.com/operator-framework/operator-registry/pkg/image"
+	registrybundle "github.com/operator-framework/operator-registry/pkg/lib/bundle"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/bundle/validate/internal"
+	internalregistry "github.com/operator-framework/operator-sdk/internal/registry"
+)
+
+// catalogBundle is a bundle read from a directory, with its metadata.
+type catalogBundle struct {
+	dir      string
+	bundle   *apimanifests.Bundle
+	metadata internalregistry.Labels
+}
+
+// runCatalog validates each bundle in catalogDir, then the channels and
+// upgrade graph of each package they belong to.
+func (c bundleValidateCmd) runCatalog(logger *log.Entry, reg registryimage.Registry,
+	catalogDir string) (*internal.Result, error) {
+	fbc, err := isFileBasedCatalog(catalogDir)
+	if err != nil {
+		return nil, err
+	}
+	if fbc {
+		return nil, fmt.Errorf("catalog %s is a file-based catalog, which is not supported; "+
+			"validate a directory of bundles instead", catalogDir)
+	}
+	if info, err := os.Stat(catalogDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("catalog %s must be a directory", catalogDir)
+	}
+	dirs, err := findBundleDirs(catalogDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no bundles found in %s", catalogDir)
+	}
+
+	res := internal.NewResult()
+	var bundles []catalogBundle
+	for _, dir := range dirs {
+		bundleRes := internal.NewResult()
+		b, err := c.validateBundle(logger, reg, dir, bundleRes)
+		if err != nil {
+			bundleRes.AddError(err)
+		} else {
+			bundles = append(bundles, *b)
+		}
+		res.AddResult(fmt.Sprintf("bundle %s", dir), bundleRes)
+	}
+	res.AddManifestResults(validateUpgradeGraphs(bundles)...)
+	res.AddInfo(fmt.Sprintf("Validated %d bundles in %s", len(dirs), catalogDir))
+	return res, nil
+}
+
+// findBundleDirs returns each directory under root, including root, that
+// contains bundle metadata. Directories in a bundle are not searched.
+func findBundleDirs(root string) (dirs []string, err error) {
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		annotationsPath := filepath.Join(path, registrybundle.MetadataDir, registrybundle.AnnotationsFile)
+		if _, err := os.Stat(annotationsPath); err == nil {
+			dirs = append(dirs, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// fbcSchemaPattern matches the schema of a file-based catalog's blobs, ex.
+// `"schema": "olm.package"` or `schema: olm.bundle`.
+var fbcSchemaPattern = regexp.MustCompile(`(?m)(^|[{,])\s*"?schema"?\s*:\s*"?olm\.(package|channel|bundle)\b`)
+
+// isFileBasedCatalog returns true if path is, or is a directory containing
+// outside of bundles, a JSON or YAML file with file-based catalog blobs.
+func isFileBasedCatalog(path string) (fbc bool, err error) {
+	if _, err := os.Stat(path); err != nil {
+		return false, nil
+	}
+	err = filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err != nil || fbc {
+			return err
+		}
+		if info.IsDir() {
+			annotationsPath := filepath.Join(path, registrybundle.MetadataDir, registrybundle.AnnotationsFile)
+			if _, err := os.Stat(annotationsPath); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fbc = fbcSchemaPattern.Match(b)
+		return nil
+	})
+	return fbc, err
+}
+
+// validateUpgradeGraphs checks the channels and upgrade graph of each package
+// of bundles, returning a result per package.
+func validateUpgradeGraphs(bundles []catalogBundle) (results []apierrors.ManifestResult) {
+	packages := map[string][]catalogBundle{}
+	var names []string
+	for _, b := range bundles {
+		pkg := b.metadata[registrybundle.PackageLabel]
+		if pkg == "" {
+			results = append(results, apierrors.ManifestResult{Name: b.dir, Errors: []apierrors.Error{
+				apierrors.ErrInvalidBundle(fmt.Sprintf("bundle %s has no %s annotation", b.dir,
+					registrybundle.PackageLabel), b.dir),
+			}})
+			continue
+		}
+		if b.bundle.CSV == nil {
+			// Reported by bundle content validation.
+			continue
+		}
+		if _, ok := packages[pkg]; !ok {
+			names = append(names, pkg)
+		}
+		packages[pkg] = append(packages[pkg], b)
+	}
+	sort.Strings(names)
+	for _, pkg := range names {
+		results = append(results, apierrors.ManifestResult{
+			Name:   pkg,
+			Errors: validatePackageGraph(pkg, packages[pkg]),
+		})
+	}
+	return results
+}
+
+// validatePackageGraph returns errors in the channels and upgrade graph of
+// pkg's bundles: duplicate CSV names, replaced CSVs that are not in the
+// package, replaces cycles, channels with more than one head, and missing or
+// inconsistent default channels.
+func validatePackageGraph(pkg string, bundles []catalogBundle) (errs []apierrors.Error) {
+	csvs := map[string]catalogBundle{}
+	var names []string
+	channels := map[string][]string{}
+	var channelNames []string
+	var defaultChannels []string
+	for _, b := range bundles {
+		name := b.bundle.CSV.GetName()
+		if other, ok := csvs[name]; ok {
+			errs = append(errs, apierrors.ErrInvalidCSV(fmt.Sprintf("CSV %s is in bundles %s and %s",
+				name, other.dir, b.dir), name))
+			continue
+		}
+		csvs[name] = b
+		names = append(names, name)
+		for _, ch := range bundleChannels(b.metadata) {
+			if _, ok := channels[ch]; !ok {
+				channelNames = append(channelNames, ch)
+			}
+			channels[ch] = append(channels[ch], name)
+		}
+		if def := b.metadata[registrybundle.ChannelDefaultLabel]; def != "" && !contains(defaultChannels, def) {
+			defaultChannels = append(defaultChannels, def)
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(channelNames)
+	sort.Strings(defaultChannels)
+
+	for _, name := range names {
+		spec := csvs[name].bundle.CSV.Spec
+		if spec.Replaces == "" {
+			continue
+		}
+		if _, ok := csvs[spec.Replaces]; !ok && !contains(spec.Skips, spec.Replaces) {
+			errs = append(errs, apierrors.ErrInvalidCSV(fmt.Sprintf("CSV %s replaces %s, which is not in package %s",
+				name, spec.Replaces, pkg), name))
+		}
+		if hasReplacesCycle(name, csvs) {
+			errs = append(errs, apierrors.ErrInvalidCSV(fmt.Sprintf("CSV %s is in a replaces cycle", name), name))
+		}
+	}
+
+	for _, ch := range channelNames {
+		if heads := channelHeads(channels[ch], csvs); len(heads) > 1 {
+			errs = append(errs, apierrors.ErrInvalidBundle(fmt.Sprintf("channel %s of package %s has multiple heads: %s",
+				ch, pkg, strings.Join(heads, ", ")), ch))
+		}
+	}
+
+	switch {
+	case len(defaultChannels) > 1:
+		errs = append(errs, apierrors.ErrInvalidBundle(fmt.Sprintf("bundles of package %s set different default "+
+			"channels: %s", pkg, strings.Join(defaultChannels, ", ")), pkg))
+	case len(defaultChannels) == 1:
+		if def := defaultChannels[0]; channels[def] == nil {
+			errs = append(errs, apierrors.ErrInvalidBundle(fmt.Sprintf("default channel %s of package %s has "+
+				"no bundles", def, pkg), pkg))
+		}
+	case len(channels) > 1:
+		errs = append(errs, apierrors.ErrInvalidBundle(fmt.Sprintf("package %s has channels %s but no default "+
+			"channel", pkg, strings.Join(channelNames, ", ")), pkg))
+	}
+	return errs
+}
+
+// bundleChannels returns the channels in a bundle's channels annotation.
+func bundleChannels(metadata internalregistry.Labels) (channels []string) {
+	for _, ch := range strings.Split(metadata[registrybundle.ChannelsLabel], ",") {
+		if ch = strings.TrimSpace(ch); ch != "" {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
+
+// hasReplacesCycle returns true if following replaces from the CSV name in
+// csvs leads back to it.
+func hasReplacesCycle(name string, csvs map[string]catalogBundle) bool {
+	seen := map[string]bool{}
+	for next := name; ; {
+		b, ok := csvs[next]
+		if !ok {
+			return false
+		}
+		next = b.bundle.CSV.Spec.Replaces
+		if next == name {
+			return true
+		}
+		if next == "" || seen[next] {
+			return false
+		}
+		seen[next] = true
+	}
+}
+
+// channelHeads returns the CSVs in a channel's members that no other member
+// replaces or skips.
+func channelHeads(members []string, csvs map[string]catalogBundle) []string {
+	replaced := map[string]bool{}
+	for _, name := range members {
+		spec := csvs[name].bundle.CSV.Spec
+		replaced[spec.Replaces] = true
+		for _, skip := range spec.Skips {
+			replaced[skip] = true
+		}
+	}
+	var heads []string
+	for _, name := range members {
+		if !replaced[name] {
+			heads = append(heads, name)
+		}
+	}
+	sort.Strings(heads)
+	return heads
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apierrors "github.com/operator-framework/api/pkg/validation/errors"
+	registrybundle "github.com/operator-framework/operator-registry/pkg/lib/bundle"
+
+	internalregistry "github.com/operator-framework/operator-sdk/internal/registry"
+)
+
+func newCatalogBundle(name, replaces, channels, defaultChannel string) catalogBundle {
+	csv := &v1alpha1.ClusterServiceVersion{}
+	csv.SetName(name)
+	csv.Spec.Replaces = replaces
+	metadata := internalregistry.Labels{
+		registrybundle.PackageLabel:  "memcached-operator",
+		registrybundle.ChannelsLabel: channels,
+	}
+	if defaultChannel != "" {
+		metadata[registrybundle.ChannelDefaultLabel] = defaultChannel
+	}
+	return catalogBundle{
+		dir:      name,
+		bundle:   &apimanifests.Bundle{Name: name, CSV: csv},
+		metadata: metadata,
+	}
+}
+
+func graphErrorDetails(bundles ...catalogBundle) (details []string) {
+	for _, result := range validateUpgradeGraphs(bundles) {
+		for _, err := range result.Errors {
+			Expect(err.Type).To(Or(Equal(apierrors.ErrorInvalidCSV), Equal(apierrors.ErrorInvalidBundle)))
+			details = append(details, err.Detail)
+		}
+	}
+	return details
+}
+
+var _ = Describe("Catalog validation", func() {
+	Describe("validateUpgradeGraphs", func() {
+		It("passes a valid upgrade graph", func() {
+			Expect(graphErrorDetails(
+				newCatalogBundle("memcached-operator.v0.0.1", "", "alpha", "alpha"),
+				newCatalogBundle("memcached-operator.v0.0.2", "memcached-operator.v0.0.1", "alpha,beta", "alpha"),
+				newCatalogBundle("memcached-operator.v0.0.3", "memcached-operator.v0.0.2", "beta", "alpha"),
+			)).To(BeEmpty())
+		})
+
+		It("fails when a bundle has no package", func() {
+			b := newCatalogBundle("memcached-operator.v0.0.1", "", "alpha", "")
+			delete(b.metadata, registrybundle.PackageLabel)
+			Expect(graphErrorDetails(b)).To(ConsistOf(ContainSubstring("has no")))
+		})
+
+		It("fails on duplicate CSV names", func() {
+			Expect(graphErrorDetails(
+				newCatalogBundle("memcached-operator.v0.0.1", "", "alpha", ""),
+				newCatalogBundle("memcached-operator.v0.0.1", "", "alpha", ""),
+			)).To(ConsistOf(ContainSubstring("is in bundles")))
+		})
+
+		It("fails when a replaced CSV is not in the package unless it is skipped", func() {
+			Expect(graphErrorDetails(
+				newCatalogBundle("memcached-operator.v0.0.2", "memcached-operator.v0.0.1", "alpha", ""),
+			)).To(ConsistOf(ContainSubstring("which is not in package memcached-operator")))
+
+			b := newCatalogBundle("memcached-operator.v0.0.2", "memcached-operator.v0.0.1", "alpha", "")
+			b.bundle.CSV.Spec.Skips = []string{"memcached-operator.v0.0.1"}
+			Expect(graphErrorDetails(b)).To(BeEmpty())
+		})
+
+		It("fails on replaces cycles", func() {
+			Expect(graphErrorDetails(
+				newCatalogBundle("memcached-operator.v0.0.1", "memcached-operator.v0.0.2", "alpha", ""),
+				newCatalogBundle("memcached-operator.v0.0.2", "memcached-operator.v0.0.1", "alpha", ""),
+			)).To(ConsistOf(
+				ContainSubstring("memcached-operator.v0.0.1 is in a replaces cycle"),
+				ContainSubstring("memcached-operator.v0.0.2 is in a replaces cycle"),
+			))
+		})
+
+		It("fails when a channel has multiple heads", func() {
+			Expect(graphErrorDetails(
+				newCatalogBundle("memcached-operator.v0.0.1", "", "alpha", ""),
+				newCatalogBundle("memcached-operator.v0.0.2", "", "alpha", ""),
+			)).To(ConsistOf(ContainSubstring(
+				"channel alpha of package memcached-operator has multiple heads: " +
+					"memcached-operator.v0.0.1, memcached-operator.v0.0.2")))
+		})
+
+		It("fails on missing or inconsistent default channels", func() {
+			Expect(graphErrorDetails(
+				newCatalogBundle("memcached-operator.v0.0.1", "", "alpha", "alpha"),
+				newCatalogBundle("memcached-operator.v0.0.2", "memcached-operator.v0.0.1", "alpha", "beta"),
+			)).To(ConsistOf(ContainSubstring("set different default channels: alpha, beta")))
+
+			Expect(graphErrorDetails(
+				newCatalogBundle("memcached-operator.v0.0.1", "", "alpha", "stable"),
+			)).To(ConsistOf(ContainSubstring("default channel stable of package memcached-operator has no bundles")))
+
+			Expect(graphErrorDetails(
+				newCatalogBundle("memcached-operator.v0.0.1", "", "alpha,beta", ""),
+			)).To(ConsistOf(ContainSubstring("has channels alpha, beta but no default channel")))
+		})
+	})
+
+	Describe("findBundleDirs", func() {
+		var root string
+
+		BeforeEach(func() {
+			var err error
+			root, err = ioutil.TempDir("", "catalog-")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(root)).To(Succeed())
+		})
+
+		It("finds nested bundle directories without searching in them", func() {
+			for _, dir := range []string{
+				filepath.Join("memcached-operator", "0.0.1"),
+				filepath.Join("memcached-operator", "0.0.2"),
+				filepath.Join("memcached-operator", "0.0.2", "manifests", "nested"),
+			} {
+				metadataDir := filepath.Join(root, dir, registrybundle.MetadataDir)
+				Expect(os.MkdirAll(metadataDir, 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(metadataDir, registrybundle.AnnotationsFile), nil, 0644)).To(Succeed())
+			}
+			Expect(os.MkdirAll(filepath.Join(root, "docs"), 0755)).To(Succeed())
+
+			dirs, err := findBundleDirs(root)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dirs).To(Equal([]string{
+				filepath.Join(root, "memcached-operator", "0.0.1"),
+				filepath.Join(root, "memcached-operator", "0.0.2"),
+			}))
+		})
+	})
+
+	Describe("isFileBasedCatalog", func() {
+		var root string
+
+		BeforeEach(func() {
+			var err error
+			root, err = ioutil.TempDir("", "catalog-")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(root)).To(Succeed())
+		})
+
+		writeFile := func(path, contents string) string {
+			path = filepath.Join(root, path)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+			return path
+		}
+
+		It("detects catalog files and directories of them", func() {
+			file := writeFile(filepath.Join("memcached-operator", "catalog.json"),
+				`{"schema": "olm.package", "name": "memcached-operator", "defaultChannel": "alpha"}`)
+			Expect(isFileBasedCatalog(file)).To(BeTrue())
+			Expect(isFileBasedCatalog(root)).To(BeTrue())
+
+			writeFile(filepath.Join("etcd-operator", "catalog.yaml"), "---\nschema: olm.bundle\nname: etcd.v0.0.1\n")
+			Expect(isFileBasedCatalog(filepath.Join(root, "etcd-operator"))).To(BeTrue())
+		})
+
+		It("does not detect directories of bundles", func() {
+			writeFile(filepath.Join("memcached-operator", "0.0.1", registrybundle.MetadataDir,
+				registrybundle.AnnotationsFile), "annotations: {}\n")
+			writeFile(filepath.Join("memcached-operator", "0.0.1", "manifests", "catalog.yaml"),
+				"schema: olm.bundle\n")
+			writeFile("README.md", "schema: olm.package\n")
+			Expect(isFileBasedCatalog(root)).To(BeFalse())
+			Expect(isFileBasedCatalog(filepath.Join(root, "missing"))).To(BeFalse())
+		})
+
+		It("fails catalog validation", func() {
+			writeFile("catalog.json", `{"schema": "olm.package", "name": "memcached-operator"}`)
+			_, err := bundleValidateCmd{}.runCatalog(nil, nil, root)
+			Expect(err).To(MatchError(ContainSubstring("is a file-based catalog, which is not supported")))
+		})
+	})
+})
@@ -60,10 +60,42 @@ To build and validate a *pullable* bundle image:
 To list and run optional validators, which are specified by a label selector:
 
   $ operator-sdk bundle validate --list-optional
-  NAME           LABELS                     DESCRIPTION
-  operatorhub    name=operatorhub           OperatorHub.io metadata validation
-                 suite=operatorframework
+  NAME                LABELS                     DESCRIPTION
+  operatorhub         name=operatorhub           OperatorHub.io metadata validation
+                      suite=operatorframework
+  image-references    name=image-references      Image reference digest pinning and registry allowlist validation, configured by --image-policy
+                      suite=supplychain
+  descriptors         name=descriptors           CSV spec and status descriptor paths exist in owned CRD schemas
+                      suite=operatorframework
+  icons               name=icons                 CSV icons have a supported media type and valid base64 data within the maximum size
+                      suite=operatorframework
   $ operator-sdk bundle validate ./bundle --select-optional suite=operatorframework
+
+To require that all images in a bundle are pinned by digest and pulled from allowed registries:
+
+  $ cat image-policy.yaml
+  requireDigests: true
+  allowedRegistries:
+  - quay.io/my-org
+  - registry.example.com
+  $ operator-sdk bundle validate ./bundle --select-optional name=image-references --image-policy image-policy.yaml
+
+To check that the bundle's CRDs can safely replace the CRDs of the previous release's bundle:
+
+  $ operator-sdk bundle validate ./bundle --from-bundle <some-registry>/<operator-bundle-name>:<previous-tag>
+
+To validate every bundle in a directory, and the channels and upgrade graph of each package they belong to:
+
+  $ tree ./catalog
+  ./catalog
+  └── memcached-operator
+      ├── 0.0.1
+      │   ├── manifests
+      │   └── metadata
+      └── 0.0.2
+          ├── manifests
+          └── metadata
+  $ operator-sdk bundle validate ./catalog --catalog
 `
 )
 
@@ -0,0 +1,56 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"fmt"
+
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	apierrors "github.com/operator-framework/api/pkg/validation/errors"
+
+	"github.com/operator-framework/operator-sdk/internal/registry/bundlediff"
+)
+
+// crdUpgradeSafetyValidator validates that a bundle's CRDs can safely replace
+// the CRDs of a previous release's bundle. Removed CRDs, versions, and fields,
+// type changes, and tightened validation are errors, since objects stored by
+// the previous release may no longer be served or valid.
+type crdUpgradeSafetyValidator struct {
+	fromBundle *apimanifests.Bundle
+}
+
+// Validate implements interfaces.Validator.
+func (v crdUpgradeSafetyValidator) Validate(objs ...interface{}) (results []apierrors.ManifestResult) {
+	for _, obj := range objs {
+		bundle, ok := obj.(*apimanifests.Bundle)
+		if !ok || bundle == nil {
+			continue
+		}
+		result := apierrors.ManifestResult{Name: bundle.Name}
+		changes, err := bundlediff.CompareCRDs(v.fromBundle, bundle)
+		if err != nil {
+			result.Errors = append(result.Errors, apierrors.ErrInvalidBundle(
+				fmt.Sprintf("error comparing CRDs with bundle %s: %v", v.fromBundle.Name, err), bundle.Name))
+		}
+		for _, change := range changes {
+			if change.Breaking {
+				result.Errors = append(result.Errors, apierrors.ErrInvalidBundle(
+					fmt.Sprintf("unsafe CRD upgrade from bundle %s: %s", v.fromBundle.Name, change), change.CRD))
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
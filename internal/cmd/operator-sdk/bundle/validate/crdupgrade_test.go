@@ -0,0 +1,84 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func newUpgradeTestBundle(name string, specProps map[string]apiextv1.JSONSchemaProps) *apimanifests.Bundle {
+	crd := &apiextv1.CustomResourceDefinition{}
+	crd.SetName("memcacheds.cache.example.com")
+	crd.Spec.Scope = apiextv1.NamespaceScoped
+	crd.Spec.Versions = []apiextv1.CustomResourceDefinitionVersion{{
+		Name:    "v1alpha1",
+		Served:  true,
+		Storage: true,
+		Schema: &apiextv1.CustomResourceValidation{
+			OpenAPIV3Schema: &apiextv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]apiextv1.JSONSchemaProps{
+					"spec": {Type: "object", Properties: specProps},
+				},
+			},
+		},
+	}}
+	return &apimanifests.Bundle{Name: name, V1CRDs: []*apiextv1.CustomResourceDefinition{crd}}
+}
+
+var _ = Describe("CRD upgrade safety", func() {
+	var fromBundle *apimanifests.Bundle
+
+	BeforeEach(func() {
+		fromBundle = newUpgradeTestBundle("memcached-operator.v0.0.1", map[string]apiextv1.JSONSchemaProps{
+			"size": {Type: "integer"},
+			"mode": {Type: "string"},
+		})
+	})
+
+	It("passes compatible changes", func() {
+		max := 10.0
+		bundle := newUpgradeTestBundle("memcached-operator.v0.0.2", map[string]apiextv1.JSONSchemaProps{
+			"size":   {Type: "integer"},
+			"mode":   {Type: "string"},
+			"labels": {Type: "object"},
+			"count":  {Type: "integer", Maximum: &max},
+		})
+		results := crdUpgradeSafetyValidator{fromBundle: fromBundle}.Validate(bundle)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Errors).To(BeEmpty())
+	})
+
+	It("fails removed fields, type changes, and tightened validation", func() {
+		min := 1.0
+		bundle := newUpgradeTestBundle("memcached-operator.v0.0.2", map[string]apiextv1.JSONSchemaProps{
+			"size": {Type: "integer", Minimum: &min},
+			"mode": {Type: "boolean"},
+		})
+		results := crdUpgradeSafetyValidator{fromBundle: fromBundle}.Validate(bundle)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Name).To(Equal("memcached-operator.v0.0.2"))
+		Expect(results[0].Errors).To(HaveLen(2))
+		Expect(results[0].Errors[0].Detail).To(ContainSubstring(`.spec.mode: type changed from "string" to "boolean"`))
+		Expect(results[0].Errors[1].Detail).To(ContainSubstring(".spec.size: minimum added: 1"))
+	})
+
+	It("ignores objects that are not bundles", func() {
+		Expect(crdUpgradeSafetyValidator{fromBundle: fromBundle}.Validate("not a bundle")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,218 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	apierrors "github.com/operator-framework/api/pkg/validation/errors"
+)
+
+// descriptorsValidatorName is the name of the descriptor path validator.
+const descriptorsValidatorName = "descriptors"
+
+// conditionsXDescriptor marks a status descriptor of a resource's conditions.
+const conditionsXDescriptor = "urn:alm:descriptor:io.kubernetes.conditions"
+
+// descriptorValidator validates that the spec and status descriptors of a
+// bundle's owned CRD descriptions refer to fields in the schemas of those
+// CRDs, so that stale UI hints are caught when a schema is refactored.
+type descriptorValidator struct{}
+
+// Validate implements interfaces.Validator.
+func (descriptorValidator) Validate(objs ...interface{}) (results []apierrors.ManifestResult) {
+	for _, obj := range objs {
+		bundle, ok := obj.(*apimanifests.Bundle)
+		if !ok || bundle == nil || bundle.CSV == nil {
+			continue
+		}
+		csvName := bundle.CSV.GetName()
+		result := apierrors.ManifestResult{Name: csvName}
+		schemas, err := crdVersionSchemas(bundle)
+		if err != nil {
+			result.Errors = append(result.Errors, apierrors.ErrInvalidBundle(err.Error(), bundle.Name))
+		}
+		for _, desc := range bundle.CSV.Spec.CustomResourceDefinitions.Owned {
+			// Missing CRDs and versions are reported by the default validators.
+			root, ok := schemas[desc.Name][desc.Version]
+			if !ok {
+				continue
+			}
+			for _, d := range desc.SpecDescriptors {
+				if _, found := root.lookup("spec", d.Path); !found {
+					result.Errors = append(result.Errors, apierrors.ErrInvalidCSV(fmt.Sprintf(
+						"%s %s spec descriptor %q: field .spec.%s does not exist in the CRD schema",
+						desc.Name, desc.Version, d.DisplayName, d.Path), csvName))
+				}
+			}
+			for _, d := range desc.StatusDescriptors {
+				s, found := root.lookup("status", d.Path)
+				if !found {
+					result.Errors = append(result.Errors, apierrors.ErrInvalidCSV(fmt.Sprintf(
+						"%s %s status descriptor %q: field .status.%s does not exist in the CRD schema",
+						desc.Name, desc.Version, d.DisplayName, d.Path), csvName))
+					continue
+				}
+				if contains(d.XDescriptors, conditionsXDescriptor) && s != nil && s.Type != "" && s.Type != "array" {
+					result.Errors = append(result.Errors, apierrors.ErrInvalidCSV(fmt.Sprintf(
+						"%s %s status descriptor %q: field .status.%s has x-descriptor %s but is of type %s, not array",
+						desc.Name, desc.Version, d.DisplayName, d.Path, conditionsXDescriptor, s.Type), csvName))
+				}
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// descriptorSchema holds the fields of a JSON schema needed to find the fields
+// that descriptors refer to.
+type descriptorSchema struct {
+	Type       string                       `json:"type,omitempty"`
+	Properties map[string]*descriptorSchema `json:"properties,omitempty"`
+	Items      *descriptorSchema            `json:"items,omitempty"`
+	// AdditionalProperties is either a bool or a schema.
+	AdditionalProperties   json.RawMessage `json:"additionalProperties,omitempty"`
+	XPreserveUnknownFields bool            `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+}
+
+// lookup returns the schema of the field at the dot-separated path in s's
+// top-level field top. Array elements are referred to by index, ex.
+// "containers[0].image". found is true if the field exists or the schema
+// does not constrain it, in which case the returned schema is nil.
+func (s *descriptorSchema) lookup(top, path string) (field *descriptorSchema, found bool) {
+	if path == "" {
+		return nil, true
+	}
+	field = s
+	for _, name := range append([]string{top}, strings.Split(path, ".")...) {
+		indexes := 0
+		if i := strings.Index(name, "["); i >= 0 {
+			indexes = strings.Count(name[i:], "[")
+			name = name[:i]
+		}
+		if field, found = field.child(name); !found || field == nil {
+			return field, found
+		}
+		for ; indexes > 0; indexes-- {
+			if field.Items == nil {
+				return nil, field.isOpen()
+			}
+			field = field.Items
+		}
+	}
+	return field, true
+}
+
+// child returns the schema of s's property name. found is true if the
+// property exists or s does not constrain it, in which case the returned
+// schema is nil.
+func (s *descriptorSchema) child(name string) (field *descriptorSchema, found bool) {
+	if p, ok := s.Properties[name]; ok {
+		return p, true
+	}
+	if len(s.AdditionalProperties) != 0 {
+		var additional descriptorSchema
+		if err := json.Unmarshal(s.AdditionalProperties, &additional); err == nil {
+			return &additional, true
+		}
+		var allowed bool
+		if err := json.Unmarshal(s.AdditionalProperties, &allowed); err == nil && allowed {
+			return nil, true
+		}
+	}
+	return nil, s.isOpen()
+}
+
+// isOpen returns true if s does not constrain the fields of its value.
+func (s *descriptorSchema) isOpen() bool {
+	return s.XPreserveUnknownFields || (s.Type == "" && len(s.Properties) == 0 && s.Items == nil)
+}
+
+// descriptorCRD holds the fields of v1 and v1beta1 CRDs needed to get the
+// schema of each of their versions.
+type descriptorCRD struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		// Version and Validation are the only version and schema of all
+		// versions of a v1beta1 CRD.
+		Version    string                   `json:"version,omitempty"`
+		Validation *descriptorCRDValidation `json:"validation,omitempty"`
+		Versions   []struct {
+			Name   string                   `json:"name"`
+			Schema *descriptorCRDValidation `json:"schema,omitempty"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+type descriptorCRDValidation struct {
+	OpenAPIV3Schema *descriptorSchema `json:"openAPIV3Schema,omitempty"`
+}
+
+// crdVersionSchemas returns the schemas of bundle's CRDs keyed by CRD name
+// and version. Versions without a schema are omitted, since their fields
+// cannot be checked.
+func crdVersionSchemas(bundle *apimanifests.Bundle) (map[string]map[string]*descriptorSchema, error) {
+	var objs []interface{}
+	for _, c := range bundle.V1CRDs {
+		objs = append(objs, c)
+	}
+	for _, c := range bundle.V1beta1CRDs {
+		objs = append(objs, c)
+	}
+	schemas := map[string]map[string]*descriptorSchema{}
+	for _, obj := range objs {
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		crd := descriptorCRD{}
+		if err := json.Unmarshal(b, &crd); err != nil {
+			return nil, fmt.Errorf("error reading CRD schema: %v", err)
+		}
+		versions := map[string]*descriptorSchema{}
+		var shared *descriptorSchema
+		if crd.Spec.Validation != nil {
+			shared = crd.Spec.Validation.OpenAPIV3Schema
+		}
+		if crd.Spec.Version != "" && shared != nil {
+			versions[crd.Spec.Version] = shared
+		}
+		for _, v := range crd.Spec.Versions {
+			switch {
+			case v.Schema != nil && v.Schema.OpenAPIV3Schema != nil:
+				versions[v.Name] = v.Schema.OpenAPIV3Schema
+			case shared != nil:
+				versions[v.Name] = shared
+			}
+		}
+		schemas[crd.Metadata.Name] = versions
+	}
+	return schemas, nil
+}
+
+func contains(l []string, s string) bool {
+	for _, elem := range l {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
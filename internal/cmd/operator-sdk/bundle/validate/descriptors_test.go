@@ -0,0 +1,132 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var _ = Describe("Descriptor paths", func() {
+
+	var bundle *apimanifests.Bundle
+
+	BeforeEach(func() {
+		crd := &apiextv1.CustomResourceDefinition{}
+		crd.SetName("memcacheds.cache.example.com")
+		crd.Spec.Versions = []apiextv1.CustomResourceDefinitionVersion{{
+			Name: "v1alpha1",
+			Schema: &apiextv1.CustomResourceValidation{
+				OpenAPIV3Schema: &apiextv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextv1.JSONSchemaProps{
+						"spec": {
+							Type: "object",
+							Properties: map[string]apiextv1.JSONSchemaProps{
+								"size": {Type: "integer"},
+								"containers": {
+									Type: "array",
+									Items: &apiextv1.JSONSchemaPropsOrArray{Schema: &apiextv1.JSONSchemaProps{
+										Type: "object",
+										Properties: map[string]apiextv1.JSONSchemaProps{
+											"image": {Type: "string"},
+										},
+									}},
+								},
+								"labels": {
+									Type: "object",
+									AdditionalProperties: &apiextv1.JSONSchemaPropsOrBool{
+										Allows: true,
+										Schema: &apiextv1.JSONSchemaProps{Type: "string"},
+									},
+								},
+							},
+						},
+						"status": {
+							Type: "object",
+							Properties: map[string]apiextv1.JSONSchemaProps{
+								"nodes":      {Type: "array", Items: &apiextv1.JSONSchemaPropsOrArray{Schema: &apiextv1.JSONSchemaProps{Type: "string"}}},
+								"phase":      {Type: "string"},
+								"conditions": {Type: "array"},
+								"extra":      {Type: "object", XPreserveUnknownFields: boolPtr(true)},
+							},
+						},
+					},
+				},
+			},
+		}}
+
+		csv := &v1alpha1.ClusterServiceVersion{}
+		csv.SetName("memcached-operator.v0.0.1")
+		bundle = &apimanifests.Bundle{
+			CSV:    csv,
+			V1CRDs: []*apiextv1.CustomResourceDefinition{crd},
+		}
+	})
+
+	setDescription := func(specPaths []string, statusDescs []v1alpha1.StatusDescriptor) {
+		desc := v1alpha1.CRDDescription{Name: "memcacheds.cache.example.com", Version: "v1alpha1", Kind: "Memcached"}
+		for _, p := range specPaths {
+			desc.SpecDescriptors = append(desc.SpecDescriptors, v1alpha1.SpecDescriptor{DisplayName: p, Path: p})
+		}
+		desc.StatusDescriptors = statusDescs
+		bundle.CSV.Spec.CustomResourceDefinitions.Owned = []v1alpha1.CRDDescription{desc}
+	}
+
+	It("accepts descriptors of fields in the CRD schema", func() {
+		setDescription(
+			[]string{"size", "containers", "containers[0].image", "labels.app"},
+			[]v1alpha1.StatusDescriptor{
+				{DisplayName: "Nodes", Path: "nodes"},
+				{DisplayName: "Conditions", Path: "conditions", XDescriptors: []string{conditionsXDescriptor}},
+				{DisplayName: "Extra", Path: "extra.anything.at.all"},
+			},
+		)
+		results := descriptorValidator{}.Validate(bundle)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Errors).To(BeEmpty())
+	})
+	It("flags descriptors of missing fields", func() {
+		setDescription(
+			[]string{"replicas", "containers[0].name"},
+			[]v1alpha1.StatusDescriptor{{DisplayName: "Pods", Path: "pods"}},
+		)
+		results := descriptorValidator{}.Validate(bundle)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Errors).To(HaveLen(3))
+		Expect(results[0].Errors[0].Detail).To(ContainSubstring(".spec.replicas"))
+	})
+	It("flags conditions descriptors of fields that are not arrays", func() {
+		setDescription(nil, []v1alpha1.StatusDescriptor{
+			{DisplayName: "Conditions", Path: "phase", XDescriptors: []string{conditionsXDescriptor}},
+		})
+		results := descriptorValidator{}.Validate(bundle)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Errors).To(HaveLen(1))
+		Expect(results[0].Errors[0].Detail).To(ContainSubstring("not array"))
+	})
+	It("skips descriptions of CRDs that are not in the bundle", func() {
+		setDescription([]string{"replicas"}, nil)
+		bundle.V1CRDs = nil
+		results := descriptorValidator{}.Validate(bundle)
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Errors).To(BeEmpty())
+	})
+})
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,55 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"fmt"
+
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	apierrors "github.com/operator-framework/api/pkg/validation/errors"
+
+	"github.com/operator-framework/operator-sdk/internal/generate/clusterserviceversion/bases"
+)
+
+// iconsValidatorName is the name of the CSV icon validator.
+const iconsValidatorName = "icons"
+
+// iconValidator validates that each icon of a bundle's CSV has a supported
+// media type and valid base64 data within the maximum icon size. Empty icons,
+// which are scaffolded as placeholders, are skipped.
+type iconValidator struct{}
+
+// Validate implements interfaces.Validator.
+func (iconValidator) Validate(objs ...interface{}) (results []apierrors.ManifestResult) {
+	for _, obj := range objs {
+		bundle, ok := obj.(*apimanifests.Bundle)
+		if !ok || bundle == nil || bundle.CSV == nil {
+			continue
+		}
+		csvName := bundle.CSV.GetName()
+		result := apierrors.ManifestResult{Name: csvName}
+		for i, icon := range bundle.CSV.Spec.Icon {
+			if icon.Data == "" && icon.MediaType == "" {
+				continue
+			}
+			if err := bases.ValidateIcon(icon); err != nil {
+				result.Errors = append(result.Errors, apierrors.ErrInvalidCSV(
+					fmt.Sprintf("icon %d: %v", i, err), csvName))
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
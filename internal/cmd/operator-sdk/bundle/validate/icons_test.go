@@ -0,0 +1,129 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var testBundleDir = filepath.Join("..", "..", "..", "..", "..", "testdata", "helm", "memcached-operator", "bundle")
+
+// pngIconData is base64-encoded data of a valid icon.
+var pngIconData = base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n"))
+
+func iconErrorDetails(icons ...v1alpha1.Icon) (details []string) {
+	csv := &v1alpha1.ClusterServiceVersion{}
+	csv.SetName("memcached-operator.v0.0.1")
+	csv.Spec.Icon = icons
+	for _, result := range (iconValidator{}).Validate(&apimanifests.Bundle{CSV: csv}) {
+		for _, err := range result.Errors {
+			details = append(details, err.Detail)
+		}
+	}
+	return details
+}
+
+// writeIconBundle copies the test bundle to a temporary directory, setting
+// its CSV's icon data and media type, and returns the directory.
+func writeIconBundle(data, mediaType string) string {
+	dir, err := ioutil.TempDir("", "icon-bundle-")
+	Expect(err).NotTo(HaveOccurred())
+	for _, sub := range []string{"manifests", "metadata"} {
+		Expect(os.MkdirAll(filepath.Join(dir, sub), 0755)).To(Succeed())
+		infos, err := ioutil.ReadDir(filepath.Join(testBundleDir, sub))
+		Expect(err).NotTo(HaveOccurred())
+		for _, info := range infos {
+			b, err := ioutil.ReadFile(filepath.Join(testBundleDir, sub, info.Name()))
+			Expect(err).NotTo(HaveOccurred())
+			if strings.HasSuffix(info.Name(), ".clusterserviceversion.yaml") {
+				b = []byte(strings.Replace(string(b),
+					"  - base64data: \"\"\n    mediatype: \"\"\n",
+					"  - base64data: \""+data+"\"\n    mediatype: \""+mediaType+"\"\n", 1))
+			}
+			Expect(ioutil.WriteFile(filepath.Join(dir, sub, info.Name()), b, 0644)).To(Succeed())
+		}
+	}
+	return dir
+}
+
+func outputMessages(c bundleValidateCmd, dir string) (messages []string) {
+	res, err := c.run(log.NewEntry(log.New()), dir)
+	Expect(err).NotTo(HaveOccurred())
+	for _, out := range res.Outputs {
+		messages = append(messages, out.Message)
+	}
+	return messages
+}
+
+var _ = Describe("Icon validation", func() {
+	Describe("iconValidator", func() {
+		It("passes valid and empty placeholder icons", func() {
+			Expect(iconErrorDetails(
+				v1alpha1.Icon{Data: pngIconData, MediaType: "image/png"},
+				v1alpha1.Icon{},
+			)).To(BeEmpty())
+		})
+
+		It("fails invalid icons", func() {
+			Expect(iconErrorDetails(
+				v1alpha1.Icon{Data: pngIconData, MediaType: "image/bmp"},
+				v1alpha1.Icon{Data: "not base64!", MediaType: "image/png"},
+			)).To(ConsistOf(
+				ContainSubstring(`icon 0: unsupported media type "image/bmp"`),
+				ContainSubstring("icon 1: error decoding base64 data"),
+			))
+		})
+	})
+
+	Describe("bundle validate --select-optional name=icons", func() {
+		var (
+			c   bundleValidateCmd
+			dir string
+		)
+
+		BeforeEach(func() {
+			c = bundleValidateCmd{
+				imageBuilder: "none",
+				selectorRaw:  "name=icons",
+				selector:     labels.SelectorFromSet(labels.Set{nameKey: iconsValidatorName}),
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("reports an invalid icon", func() {
+			dir = writeIconBundle(pngIconData, "image/bmp")
+			Expect(outputMessages(c, dir)).To(ContainElement(ContainSubstring(`unsupported media type "image/bmp"`)))
+		})
+
+		It("does not report a valid icon", func() {
+			dir = writeIconBundle(pngIconData, "image/png")
+			Expect(outputMessages(c, dir)).NotTo(ContainElement(ContainSubstring("icon 0")))
+		})
+	})
+})
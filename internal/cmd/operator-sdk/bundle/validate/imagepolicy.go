@@ -0,0 +1,167 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	apierrors "github.com/operator-framework/api/pkg/validation/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// imageReferencesValidatorName is the name of the image reference policy validator.
+const imageReferencesValidatorName = "image-references"
+
+// defaultDockerRegistry is the registry of image references without a registry host.
+const defaultDockerRegistry = "docker.io"
+
+// imagePolicy configures the image reference policy validator.
+type imagePolicy struct {
+	// RequireDigests requires all image references to be pinned by digest.
+	RequireDigests bool `json:"requireDigests"`
+	// AllowedRegistries is a list of registry hosts, optionally followed by a
+	// repository path prefix, that images must be pulled from. If empty, images
+	// from any registry are allowed.
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+}
+
+// defaultImagePolicy is used when the image reference policy validator is
+// selected without a policy file.
+var defaultImagePolicy = imagePolicy{RequireDigests: true}
+
+// readImagePolicy reads an imagePolicy from the YAML file at path.
+func readImagePolicy(path string) (*imagePolicy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policy := &imagePolicy{}
+	if err := yaml.UnmarshalStrict(b, policy); err != nil {
+		return nil, fmt.Errorf("error parsing image policy %s: %v", path, err)
+	}
+	return policy, nil
+}
+
+// imagePolicyValidator validates images referenced by a bundle's CSV
+// deployments and relatedImages against a policy.
+type imagePolicyValidator struct {
+	policy imagePolicy
+}
+
+// Validate implements interfaces.Validator.
+func (v imagePolicyValidator) Validate(objs ...interface{}) (results []apierrors.ManifestResult) {
+	for _, obj := range objs {
+		bundle, ok := obj.(*apimanifests.Bundle)
+		if !ok || bundle == nil || bundle.CSV == nil {
+			continue
+		}
+		result := apierrors.ManifestResult{Name: bundle.CSV.GetName()}
+		images, err := getBundleImages(bundle)
+		if err != nil {
+			result.Errors = append(result.Errors, apierrors.ErrInvalidCSV(err.Error(), bundle.CSV.GetName()))
+		}
+		for _, image := range images {
+			for _, msg := range v.policy.check(image) {
+				result.Errors = append(result.Errors, apierrors.ErrInvalidCSV(msg, bundle.CSV.GetName()))
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// check returns a message for each way image violates p.
+func (p imagePolicy) check(image string) (msgs []string) {
+	if p.RequireDigests && !strings.Contains(image, "@") {
+		msgs = append(msgs, fmt.Sprintf("image %q is not pinned by digest", image))
+	}
+	if len(p.AllowedRegistries) != 0 && !p.isAllowedRegistry(image) {
+		msgs = append(msgs, fmt.Sprintf("image %q is not from an allowed registry %v", image, p.AllowedRegistries))
+	}
+	return msgs
+}
+
+// isAllowedRegistry returns true if image's registry and repository begin with
+// one of p's allowed registries.
+func (p imagePolicy) isAllowedRegistry(image string) bool {
+	name := normalizeImageName(image)
+	for _, allowed := range p.AllowedRegistries {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if name == allowed || strings.HasPrefix(name, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImageName returns image's repository name, without tag or digest,
+// prefixed with the default registry if image has none.
+func normalizeImageName(image string) string {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	// A tag follows the last colon only if no slash follows that colon,
+	// otherwise the colon separates a registry host and port.
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i:], "/") {
+		name = name[:i]
+	}
+	// The first path component is a registry host if it looks like a domain or is localhost.
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			return name
+		}
+	}
+	return defaultDockerRegistry + "/" + name
+}
+
+// getBundleImages returns all images referenced by bundle's CSV deployment
+// containers and relatedImages.
+func getBundleImages(bundle *apimanifests.Bundle) (images []string, err error) {
+	for _, dep := range bundle.CSV.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		podSpec := dep.Spec.Template.Spec
+		for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+			for _, c := range containers {
+				images = append(images, c.Image)
+			}
+		}
+	}
+
+	// Read relatedImages from the unstructured CSV since not all CSV API versions define the field.
+	for _, obj := range bundle.Objects {
+		if obj.GetKind() != v1alpha1.ClusterServiceVersionKind {
+			continue
+		}
+		relatedImages, _, err := unstructured.NestedSlice(obj.Object, "spec", "relatedImages")
+		if err != nil {
+			return nil, fmt.Errorf("error reading relatedImages: %v", err)
+		}
+		for _, ri := range relatedImages {
+			if riMap, ok := ri.(map[string]interface{}); ok {
+				if image, ok := riMap["image"].(string); ok {
+					images = append(images, image)
+				}
+			}
+		}
+	}
+	return images, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Image reference policy", func() {
+
+	Describe("normalizeImageName", func() {
+		It("adds the default registry to images without one", func() {
+			Expect(normalizeImageName("nginx:1.19")).To(Equal("docker.io/nginx"))
+			Expect(normalizeImageName("library/nginx")).To(Equal("docker.io/library/nginx"))
+		})
+		It("strips tags and digests but keeps registry ports", func() {
+			Expect(normalizeImageName("quay.io/org/app:v1")).To(Equal("quay.io/org/app"))
+			Expect(normalizeImageName("quay.io/org/app@sha256:abc")).To(Equal("quay.io/org/app"))
+			Expect(normalizeImageName("localhost:5000/app:v1")).To(Equal("localhost:5000/app"))
+		})
+	})
+
+	Describe("check", func() {
+		It("flags images not pinned by digest", func() {
+			p := imagePolicy{RequireDigests: true}
+			Expect(p.check("quay.io/org/app:v1")).To(HaveLen(1))
+			Expect(p.check("quay.io/org/app@sha256:abc")).To(BeEmpty())
+		})
+		It("flags images outside of allowed registries", func() {
+			p := imagePolicy{AllowedRegistries: []string{"quay.io/org", "registry.example.com"}}
+			Expect(p.check("quay.io/org/app:v1")).To(BeEmpty())
+			Expect(p.check("registry.example.com/any/app:v1")).To(BeEmpty())
+			Expect(p.check("quay.io/other/app:v1")).To(HaveLen(1))
+			Expect(p.check("quay.io/organization/app:v1")).To(HaveLen(1))
+			Expect(p.check("nginx")).To(HaveLen(1))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("checks deployment and related images", func() {
+			csv := &v1alpha1.ClusterServiceVersion{}
+			csv.SetName("app.v0.0.1")
+			csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs = []v1alpha1.StrategyDeploymentSpec{{
+				Name: "app",
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							InitContainers: []corev1.Container{{Image: "quay.io/org/init@sha256:abc"}},
+							Containers:     []corev1.Container{{Image: "quay.io/org/app:v1"}},
+						},
+					},
+				},
+			}}
+			u := &unstructured.Unstructured{Object: map[string]interface{}{
+				"kind": v1alpha1.ClusterServiceVersionKind,
+				"spec": map[string]interface{}{
+					"relatedImages": []interface{}{
+						map[string]interface{}{"name": "db", "image": "docker.io/db:v1"},
+					},
+				},
+			}}
+			bundle := &apimanifests.Bundle{CSV: csv, Objects: []*unstructured.Unstructured{u}}
+
+			results := imagePolicyValidator{policy: imagePolicy{RequireDigests: true}}.Validate(bundle)
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Errors).To(HaveLen(2))
+		})
+	})
+})
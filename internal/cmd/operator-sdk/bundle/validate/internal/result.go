@@ -94,6 +94,19 @@ func (o *Result) AddWarn(err error) {
 	})
 }
 
+// AddResult adds the outputs of r to o, prefixing each message with prefix.
+func (o *Result) AddResult(prefix string, r *Result) {
+	for _, obj := range r.Outputs {
+		o.Outputs = append(o.Outputs, output{
+			Type:    obj.Type,
+			Message: fmt.Sprintf("%s: %s", prefix, obj.Message),
+		})
+	}
+	if !r.Passed {
+		o.Passed = false
+	}
+}
+
 // printText will print the output in human readable format
 func (o *Result) printText(logger *logrus.Entry) error {
 	for _, obj := range o.Outputs {
@@ -76,6 +76,22 @@ var _ = Describe("Output Result", func() {
 			Expect(result.Outputs).To(HaveLen(3))
 		})
 
+		It("should add another result's outputs with a prefix and its passed flag", func() {
+			result.AddInfo("Example of an info")
+			other := NewResult()
+			other.AddWarn(errors.New("example of a warn"))
+			other.AddError(errors.New("example of an error"))
+
+			result.AddResult("bundle a", other)
+
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.Outputs).To(HaveLen(3))
+			Expect(result.Outputs[1].Type).To(Equal(log.WarnLevel.String()))
+			Expect(result.Outputs[1].Message).To(Equal("bundle a: example of a warn"))
+			Expect(result.Outputs[2].Type).To(Equal(log.ErrorLevel.String()))
+			Expect(result.Outputs[2].Message).To(Equal("bundle a: example of an error"))
+		})
+
 	})
 
 	Describe("Test PrintText", func() {
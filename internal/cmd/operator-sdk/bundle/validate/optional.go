@@ -44,11 +44,50 @@ var optionalValidators = validators{
 		},
 		desc: "OperatorHub.io metadata validation",
 	},
+	{
+		Validator: imagePolicyValidator{policy: defaultImagePolicy},
+		name:      imageReferencesValidatorName,
+		labels: map[string]string{
+			nameKey:  imageReferencesValidatorName,
+			suiteKey: "supplychain",
+		},
+		desc: "Image reference digest pinning and registry allowlist validation, configured by --image-policy",
+	},
+	{
+		Validator: descriptorValidator{},
+		name:      descriptorsValidatorName,
+		labels: map[string]string{
+			nameKey:  descriptorsValidatorName,
+			suiteKey: "operatorframework",
+		},
+		desc: "CSV spec and status descriptor paths exist in owned CRD schemas",
+	},
+	{
+		Validator: iconValidator{},
+		name:      iconsValidatorName,
+		labels: map[string]string{
+			nameKey:  iconsValidatorName,
+			suiteKey: "operatorframework",
+		},
+		desc: "CSV icons have a supported media type and valid base64 data within the maximum size",
+	},
 }
 
 // runOptionalValidators runs optional validators selected by sel on bundle.
-func runOptionalValidators(bundle *apimanifests.Bundle, sel labels.Selector) []apierrors.ManifestResult {
-	return optionalValidators.run(bundle, sel)
+// If policy is not nil, it replaces the image reference validator's default policy.
+func runOptionalValidators(bundle *apimanifests.Bundle, sel labels.Selector,
+	policy *imagePolicy) []apierrors.ManifestResult {
+
+	vals := make(validators, len(optionalValidators))
+	copy(vals, optionalValidators)
+	if policy != nil {
+		for i := range vals {
+			if vals[i].name == imageReferencesValidatorName {
+				vals[i].Validator = imagePolicyValidator{policy: *policy}
+			}
+		}
+	}
+	return vals.run(bundle, sel)
 }
 
 // listOptionalValidators lists all optional validators.
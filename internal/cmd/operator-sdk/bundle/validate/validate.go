@@ -33,6 +33,7 @@ import (
 
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/bundle/validate/internal"
 	internalregistry "github.com/operator-framework/operator-sdk/internal/registry"
+	"github.com/operator-framework/operator-sdk/internal/registry/bundlediff"
 )
 
 type bundleValidateCmd struct {
@@ -42,6 +43,10 @@ type bundleValidateCmd struct {
 	selectorRaw  string
 	selector     labels.Selector
 	listOptional bool
+	imagePolicy  string
+	fromBundle   string
+	catalog      bool
+	policy       *imagePolicy
 }
 
 // validate verifies the command args
@@ -56,6 +61,10 @@ func (c bundleValidateCmd) validate(args []string) error {
 	if c.outputFormat != internal.JSONAlpha1 && c.outputFormat != internal.Text {
 		return fmt.Errorf("invalid value for output flag: %v", c.outputFormat)
 	}
+	// Bundles in a catalog are upgraded from each other, so checked by the upgrade graph validation.
+	if c.catalog && c.fromBundle != "" {
+		return errors.New("--from-bundle cannot be set with --catalog")
+	}
 
 	// Check optional selector.
 	if c.selectorRaw != "" {
@@ -64,6 +73,20 @@ func (c bundleValidateCmd) validate(args []string) error {
 		}
 	}
 
+	// An image policy is only used by the image reference validator, so it must be selected.
+	if c.imagePolicy != "" {
+		imageVals := validators{}
+		for _, val := range optionalValidators {
+			if val.name == imageReferencesValidatorName {
+				imageVals = append(imageVals, val)
+			}
+		}
+		if c.selectorRaw == "" || imageVals.checkMatches(c.selector) != nil {
+			return fmt.Errorf("--image-policy requires --select-optional to select the %s validator",
+				imageReferencesValidatorName)
+		}
+	}
+
 	return nil
 }
 
@@ -78,6 +101,17 @@ func (c *bundleValidateCmd) addToFlagSet(fs *pflag.FlagSet) {
 			"Run this command with '--list-optional' to list available optional validators")
 	fs.BoolVar(&c.listOptional, "list-optional", false,
 		"List all optional validators available. When set, no validators will be run")
+	fs.StringVar(&c.imagePolicy, "image-policy", "",
+		"Path to an image reference policy file used by the image-references optional validator. "+
+			"By default, the validator requires all images be pinned by digest")
+	fs.StringVar(&c.fromBundle, "from-bundle", "",
+		"Image tag or directory of the previous release's bundle. If set, the bundle's CRDs are checked for "+
+			"changes that are unsafe to upgrade from this bundle's CRDs, such as removed fields, type changes, "+
+			"and tightened validation")
+	fs.BoolVar(&c.catalog, "catalog", false,
+		"Validate a directory of bundles, such as a catalog's bundles for one or more packages. Each directory "+
+			"containing metadata/annotations.yaml is validated as a bundle, and the channels and upgrade graph "+
+			"of each package are checked. File-based catalogs are not supported")
 
 	fs.StringVarP(&c.outputFormat, "output", "o", internal.Text,
 		"Result format for results. One of: [text, json-alpha1]")
@@ -100,6 +134,16 @@ func (c bundleValidateCmd) run(logger *log.Entry, bundleRaw string) (res *intern
 		}
 	}()
 
+	if c.imagePolicy != "" {
+		if c.policy, err = readImagePolicy(c.imagePolicy); err != nil {
+			return res, err
+		}
+	}
+
+	if c.catalog {
+		return c.runCatalog(logger, reg, bundleRaw)
+	}
+
 	// If bundle isn't a directory, assume it's an image.
 	if isExist(bundleRaw) {
 		if c.directory, err = relWd(bundleRaw); err != nil {
@@ -123,24 +167,33 @@ func (c bundleValidateCmd) run(logger *log.Entry, bundleRaw string) (res *intern
 		}
 	}
 
-	// Read the bundle object and metadata from the created/passed in directory.
-	bundle, mediaType, err := getBundleDataFromDir(c.directory)
-	if err != nil {
+	// Create Result to be output.
+	res = internal.NewResult()
+	if _, err := c.validateBundle(logger, reg, c.directory, res); err != nil {
 		return res, err
 	}
+	return res, nil
+}
 
-	// Create Result to be output.
-	res = internal.NewResult()
+// validateBundle validates the format and content of the bundle in dir, and
+// runs selected optional validators on it, adding their results to res.
+func (c bundleValidateCmd) validateBundle(logger *log.Entry, reg registryimage.Registry, dir string,
+	res *internal.Result) (*catalogBundle, error) {
+	// Read the bundle object and metadata from the created/passed in directory.
+	bundle, metadata, mediaType, err := getBundleDataFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
 
 	logger = logger.WithFields(log.Fields{
-		"bundle-dir":     c.directory,
+		"bundle-dir":     dir,
 		"container-tool": c.imageBuilder,
 	})
 	val := registrybundle.NewImageValidator(reg, logger)
 
 	// Validate bundle format.
-	if err := val.ValidateBundleFormat(c.directory); err != nil {
-		res.AddError(fmt.Errorf("error validating format in %s: %v", c.directory, err))
+	if err := val.ValidateBundleFormat(dir); err != nil {
+		res.AddError(fmt.Errorf("error validating format in %s: %v", dir, err))
 	}
 
 	// Validate bundle content.
@@ -148,10 +201,42 @@ func (c bundleValidateCmd) run(logger *log.Entry, bundleRaw string) (res *intern
 	res.AddManifestResults(results...)
 
 	// Run optional validators.
-	results = runOptionalValidators(bundle, c.selector)
+	results = runOptionalValidators(bundle, c.selector, c.policy)
 	res.AddManifestResults(results...)
 
-	return res, nil
+	// Check that CRDs can be upgraded from the previous bundle's CRDs.
+	if c.fromBundle != "" {
+		fromBundle, err := c.loadFromBundle(logger, reg)
+		if err != nil {
+			return nil, err
+		}
+		results = crdUpgradeSafetyValidator{fromBundle: fromBundle}.Validate(bundle)
+		res.AddManifestResults(results...)
+	}
+
+	return &catalogBundle{dir: dir, bundle: bundle, metadata: metadata}, nil
+}
+
+// loadFromBundle loads the bundle passed to --from-bundle, unpacking it with
+// reg if it is an image.
+func (c bundleValidateCmd) loadFromBundle(logger *log.Entry, reg registryimage.Registry) (*apimanifests.Bundle, error) {
+	if isExist(c.fromBundle) {
+		return bundlediff.LoadBundle(c.fromBundle)
+	}
+	dir, err := ioutil.TempDir("", "from-bundle-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Errorf("Error removing temp bundle dir: %v", err)
+		}
+	}()
+	logger.Info("Unpacking --from-bundle image layers")
+	if err := c.unpackImageIntoDir(reg, c.fromBundle, dir); err != nil {
+		return nil, fmt.Errorf("error unpacking image %s: %v", c.fromBundle, err)
+	}
+	return bundlediff.LoadBundle(dir)
 }
 
 // list prints a list of validators that can be turned off/on by selectors to stdout.
@@ -159,12 +244,12 @@ func (c bundleValidateCmd) list() error {
 	return listOptionalValidators(os.Stdout)
 }
 
-// getBundleDataFromDir returns the bundle object and associated metadata from dir, if any.
-func getBundleDataFromDir(dir string) (*apimanifests.Bundle, string, error) {
+// getBundleDataFromDir returns the bundle object, its metadata, and its media type from dir, if any.
+func getBundleDataFromDir(dir string) (*apimanifests.Bundle, internalregistry.Labels, string, error) {
 	// Gather bundle metadata.
 	metadata, _, err := internalregistry.FindBundleMetadata(dir)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, "", err
 	}
 	manifestsDirName, hasLabel := metadata.GetManifestsDir()
 	if !hasLabel {
@@ -174,14 +259,14 @@ func getBundleDataFromDir(dir string) (*apimanifests.Bundle, string, error) {
 	// Detect mediaType.
 	mediaType, err := registrybundle.GetMediaType(manifestsDir)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, "", err
 	}
 	// Read the bundle.
 	bundle, err := apimanifests.GetBundleFromDir(manifestsDir)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, "", err
 	}
-	return bundle, mediaType, nil
+	return bundle, metadata, mediaType, nil
 }
 
 // newImageRegistryForTool returns an image registry based on what type of image tool is passed.
@@ -44,6 +44,10 @@ var _ = Describe("Running a bundle validate command", func() {
 			flag = cmd.Flags().Lookup("list-optional")
 			Expect(flag).NotTo(BeNil())
 
+			flag = cmd.Flags().Lookup("catalog")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("false"))
+
 			flag = cmd.Flags().Lookup("output")
 			Expect(flag).NotTo(BeNil())
 			Expect(flag.Shorthand).To(Equal("o"))
@@ -89,6 +93,14 @@ var _ = Describe("Running a bundle validate command", func() {
 			Expect(err.Error()).To(Equal("invalid value for output flag: " + wrongArg))
 		})
 
+		It("fails if --from-bundle is set with --catalog", func() {
+			cmd.outputFormat = internal.Text
+			cmd.catalog = true
+			cmd.fromBundle = "quay.io/person/example:v0.0.1"
+			err := cmd.validate([]string{"./catalog"})
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("succeeds if the arg is text or json-alpha1", func() {
 			cmd.outputFormat = "text"
 			err := cmd.validate([]string{"quay.io/person/example"})
@@ -16,6 +16,8 @@ package cleanup
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -26,20 +28,37 @@ import (
 
 func NewCmd() *cobra.Command {
 	var timeout time.Duration
+	var wait bool
+	var session string
 	cfg := &operator.Configuration{}
 	cmd := &cobra.Command{
-		Use:   "cleanup <operatorPackageName>",
+		Use:   "cleanup [<operatorPackageName>]",
 		Short: "Clean up an Operator deployed with the 'run' subcommand",
-		Long:  "This command has subcommands that will destroy an Operator deployed with OLM.",
-		Args:  cobra.ExactArgs(1),
+		Long: `This command has subcommands that will destroy an Operator deployed with OLM.
+
+The Operator is identified by its package name, by the ID of the 'run bundle' session that deployed it
+with --session, or by both.`,
+		Args: func(_ *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("accepts at most 1 arg(s), received %d", len(args))
+			}
+			if len(args) == 0 && session == "" {
+				return errors.New("an operator package name or --session is required")
+			}
+			return nil
+		},
 		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
 			return cfg.Load()
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			u := operator.NewUninstall(cfg)
-			u.Package = args[0]
+			if len(args) == 1 {
+				u.Package = args[0]
+			}
+			u.Session = session
 			u.DeleteAll = true
 			u.DeleteOperatorGroupNames = []string{operator.SDKOperatorGroupName}
+			u.NoWait = !wait
 			u.Logf = log.Infof
 
 			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
@@ -48,10 +67,17 @@ func NewCmd() *cobra.Command {
 			if err := u.Run(ctx); err != nil {
 				log.Fatalf("Uninstall operator: %v\n", err)
 			}
+			if u.NoWait {
+				log.Infof("Operator %q deletion requested\n", u.Package)
+				return
+			}
 			log.Infof("Operator %q uninstalled\n", u.Package)
 		},
 	}
 	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute, "Time to wait for the command to complete before failing")
+	cmd.Flags().BoolVar(&wait, "wait", true, "Wait for each resource to be removed before deleting the next")
+	cmd.Flags().StringVar(&session, "session", "", "ID of the 'run bundle' session whose Operator and labeled "+
+		"resources are deleted")
 	cfg.BindFlags(cmd.PersistentFlags())
 
 	return cmd
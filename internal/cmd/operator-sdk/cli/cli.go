@@ -15,6 +15,9 @@
 package cli
 
 import (
+	"time"
+
+	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/alpha"
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/bundle"
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/cleanup"
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/completion"
@@ -22,9 +25,12 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/olm"
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/run"
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/scorecard"
+	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/sign"
+	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/stats"
 	"github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/version"
 	"github.com/operator-framework/operator-sdk/internal/flags"
 	ansiblev1 "github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1"
+	"github.com/operator-framework/operator-sdk/internal/plugins/external"
 	golangv2 "github.com/operator-framework/operator-sdk/internal/plugins/golang/v2"
 	helmv1 "github.com/operator-framework/operator-sdk/internal/plugins/helm/v1"
 	"github.com/operator-framework/operator-sdk/internal/util/projutil"
@@ -33,9 +39,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"sigs.k8s.io/kubebuilder/pkg/cli"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
 )
 
 var commands = []*cobra.Command{
+	alpha.NewCmd(),
 	bundle.NewCmd(),
 	cleanup.NewCmd(),
 	completion.NewCmd(),
@@ -43,25 +51,34 @@ var commands = []*cobra.Command{
 	olm.NewCmd(),
 	run.NewCmd(),
 	scorecard.NewCmd(),
+	sign.NewCmd(),
+	stats.NewCmd(),
 	version.NewCmd(),
 }
 
 func Run() error {
-	cli, _ := GetPluginsCLIAndRoot()
-	return cli.Run()
+	cli, root := GetPluginsCLIAndRoot()
+	start := time.Now()
+	err := cli.Run()
+	recordTelemetry(root, start, err)
+	return err
 }
 
 // GetPluginsCLIAndRoot returns the plugins based CLI configured to use operator-sdk as the root command
 // This CLI can run kubebuilder commands and certain SDK specific commands that are aligned for
 // the kubebuilder project layout
 func GetPluginsCLIAndRoot() (cli.CLI, *cobra.Command) {
+	plugins := []plugin.Base{
+		&golangv2.Plugin{},
+		&helmv1.Plugin{},
+		&ansiblev1.Plugin{},
+	}
+	plugins = append(plugins, getExternalPlugins(plugins)...)
+	registeredPlugins = plugins
+
 	c, err := cli.New(
 		cli.WithCommandName("operator-sdk"),
-		cli.WithPlugins(
-			&golangv2.Plugin{},
-			&helmv1.Plugin{},
-			&ansiblev1.Plugin{},
-		),
+		cli.WithPlugins(plugins...),
 		cli.WithDefaultPlugins(
 			&golangv2.Plugin{},
 		),
@@ -86,7 +103,27 @@ func GetPluginsCLIAndRoot() (cli.CLI, *cobra.Command) {
 	return c, root
 }
 
+// getExternalPlugins returns all external plugins discovered in the default plugins directory,
+// except those that collide with builtin or each other.
+func getExternalPlugins(builtin []plugin.Base) []plugin.Base {
+	dir, err := external.DefaultPluginsDir()
+	if err != nil {
+		log.Debugf("Not loading external plugins: %v", err)
+		return nil
+	}
+	if dir == "" {
+		return nil
+	}
+	plugins, err := external.Discover(dir)
+	if err != nil {
+		log.Warnf("Error discovering external plugins in %s: %v", dir, err)
+		return nil
+	}
+	return external.WithoutCollisions(builtin, plugins)
+}
+
 func rootPersistentPreRun(cmd *cobra.Command, args []string) {
+	executedCommand = cmd.CommandPath()
 	if viper.GetBool(flags.VerboseOpt) {
 		if err := projutil.SetGoVerbose(); err != nil {
 			log.Fatalf("Could not set GOFLAGS: (%v)", err)
@@ -0,0 +1,127 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+
+	"github.com/operator-framework/operator-sdk/internal/telemetry"
+	"github.com/operator-framework/operator-sdk/internal/util/projutil"
+	ver "github.com/operator-framework/operator-sdk/internal/version"
+)
+
+var (
+	// executedCommand is the path of the command being run, set before it runs.
+	executedCommand string
+	// registeredPlugins are the plugins the CLI was configured with.
+	registeredPlugins []plugin.Base
+)
+
+// recordTelemetry records an anonymized event for the command run by root if
+// telemetry is enabled. Failures are logged at debug level so they never
+// affect the command's outcome.
+func recordTelemetry(root *cobra.Command, start time.Time, cmdErr error) {
+	if !telemetry.Enabled() {
+		return
+	}
+
+	command := executedCommand
+	if command == "" {
+		command = root.Name()
+	}
+	e := telemetry.Event{
+		Timestamp:       start.UTC(),
+		Command:         command,
+		Plugins:         projectPlugins(),
+		SDKVersion:      ver.GitVersion,
+		GOOS:            runtime.GOOS,
+		GOARCH:          runtime.GOARCH,
+		DurationSeconds: time.Since(start).Seconds(),
+		ErrorCategory:   telemetry.CategorizeError(cmdErr),
+	}
+
+	path, err := telemetry.DefaultReportFile()
+	if err != nil {
+		log.Debugf("Not recording telemetry: %v", err)
+		return
+	}
+	if err := telemetry.Record(path, e); err != nil {
+		log.Debugf("Failed to record telemetry to %s: %v", path, err)
+	}
+	if endpoint := os.Getenv(telemetry.EndpointEnv); endpoint != "" {
+		if err := telemetry.Send(endpoint, e); err != nil {
+			log.Debugf("Failed to send telemetry: %v", err)
+		}
+	}
+}
+
+// projectPlugins returns the plugin keys of the project in the current directory, if any.
+func projectPlugins() []string {
+	if !projutil.HasProjectFile() {
+		return nil
+	}
+	cfg, err := projutil.ReadConfig()
+	if err != nil {
+		return nil
+	}
+	return pluginKeys(cfg, registeredPlugins)
+}
+
+// pluginKeys returns the sorted keys of cfg's layout plugin and of each plugin
+// configured in cfg. Keys without a version are resolved to the key of the
+// plugin in registered with that name, if there is exactly one.
+func pluginKeys(cfg *config.Config, registered []plugin.Base) []string {
+	keys := sets.NewString()
+	if cfg.Layout != "" {
+		keys.Insert(resolvePluginKey(cfg.Layout, registered))
+	}
+	for key := range cfg.Plugins {
+		keys.Insert(resolvePluginKey(key, registered))
+	}
+	if keys.Len() == 0 {
+		return nil
+	}
+	return keys.List()
+}
+
+// resolvePluginKey returns key with the version of the registered plugin it
+// names, or key unchanged if it has a version or does not name exactly one plugin.
+func resolvePluginKey(key string, registered []plugin.Base) string {
+	if strings.Contains(key, "/") {
+		return key
+	}
+	resolved := ""
+	for _, p := range registered {
+		if name := p.Name(); name == key || strings.HasPrefix(name, key+".") {
+			if resolved != "" {
+				return key
+			}
+			resolved = plugin.KeyFor(p)
+		}
+	}
+	if resolved == "" {
+		return key
+	}
+	return resolved
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+
+	ansiblev1 "github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1"
+	golangv2 "github.com/operator-framework/operator-sdk/internal/plugins/golang/v2"
+	helmv1 "github.com/operator-framework/operator-sdk/internal/plugins/helm/v1"
+)
+
+var _ = Describe("Telemetry", func() {
+	Describe("pluginKeys", func() {
+		registered := []plugin.Base{&golangv2.Plugin{}, &helmv1.Plugin{}, &ansiblev1.Plugin{}}
+
+		It("returns nothing for a project without plugins", func() {
+			Expect(pluginKeys(&config.Config{Version: config.Version2}, registered)).To(BeEmpty())
+		})
+		It("returns the layout plugin key", func() {
+			cfg := &config.Config{Version: config.Version3Alpha, Layout: "helm.sdk.operatorframework.io/v1"}
+			Expect(pluginKeys(cfg, registered)).To(Equal([]string{"helm.sdk.operatorframework.io/v1"}))
+		})
+		It("returns the keys of configured plugins", func() {
+			cfg := &config.Config{Version: config.Version3Alpha, Layout: "go.kubebuilder.io/v2"}
+			Expect(cfg.EncodePluginConfig("go.sdk.operatorframework.io/v2-alpha", golangv2.Config{})).To(Succeed())
+			Expect(pluginKeys(cfg, registered)).To(Equal([]string{
+				"go.kubebuilder.io/v2",
+				"go.sdk.operatorframework.io/v2-alpha",
+			}))
+		})
+		It("resolves keys without a version", func() {
+			cfg := &config.Config{Version: config.Version3Alpha, Layout: "ansible"}
+			Expect(pluginKeys(cfg, registered)).To(Equal([]string{"ansible.sdk.operatorframework.io/v1"}))
+
+			cfg.Layout = "ansible.sdk.operatorframework.io"
+			Expect(pluginKeys(cfg, registered)).To(Equal([]string{"ansible.sdk.operatorframework.io/v1"}))
+		})
+		It("keeps keys that do not name exactly one plugin", func() {
+			cfg := &config.Config{Version: config.Version3Alpha, Layout: "java"}
+			Expect(pluginKeys(cfg, registered)).To(Equal([]string{"java"}))
+
+			registered := append(registered, &helmv1.Plugin{})
+			cfg.Layout = "helm"
+			Expect(pluginKeys(cfg, registered)).To(Equal([]string{"helm"}))
+		})
+	})
+})
@@ -15,6 +15,7 @@
 package bundle
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -175,10 +176,17 @@ func (c bundleCmd) runManifests(cfg *config.Config) (err error) {
 	}
 
 	csvGen := gencsv.Generator{
-		OperatorName: c.projectName,
-		OperatorType: projutil.PluginKeyToOperatorType(cfg.Layout),
-		Version:      c.version,
-		Collector:    col,
+		OperatorName:  c.projectName,
+		OperatorType:  projutil.PluginKeyToOperatorType(cfg.Layout),
+		Version:       c.version,
+		Collector:     col,
+		IconPath:      c.iconFile,
+		RelatedImages: c.relatedImages,
+	}
+	if c.useImageDigests {
+		csvGen.ResolveImage = func(image string) (string, error) {
+			return registry.ResolveImageDigest(context.TODO(), image)
+		}
 	}
 
 	stdout := genutil.NewMultiManifestWriter(os.Stdout)
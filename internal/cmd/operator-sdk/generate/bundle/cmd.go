@@ -39,9 +39,14 @@ type bundleCmd struct {
 	kustomizeDir string
 	deployDir    string
 	crdsDir      string
+	iconFile     string
 	stdout       bool
 	quiet        bool
 
+	// Image options.
+	relatedImages   bool
+	useImageDigests bool
+
 	// Metadata options.
 	channels       string
 	defaultChannel string
@@ -127,6 +132,13 @@ func (c *bundleCmd) addFlagsTo(fs *pflag.FlagSet) {
 	fs.StringVar(&c.deployDir, "deploy-dir", "", "Root directory for operator manifests such as "+
 		"Deployments and RBAC, ex. 'deploy'. This directory is different from that passed to --input-dir")
 	fs.StringVar(&c.crdsDir, "crds-dir", "", "Root directory for CustomResoureDefinition manifests")
+	fs.StringVar(&c.iconFile, "icon-file", "", "Path to an icon file (svg, png, jpeg, or gif) to base64-encode "+
+		"and embed in the bundle's ClusterServiceVersion")
+	fs.BoolVar(&c.relatedImages, "related-images", false, "Populate the ClusterServiceVersion's "+
+		"spec.relatedImages with the images of its deployments' containers and RELATED_IMAGE_* environment variables")
+	fs.BoolVar(&c.useImageDigests, "use-image-digests", false, "Pin the images of the ClusterServiceVersion's "+
+		"deployments' containers and RELATED_IMAGE_* environment variables by digest, resolving tags from their "+
+		"registries. Fails if a tag cannot be resolved")
 	fs.StringVar(&c.channels, "channels", "alpha", "A comma-separated list of channels the bundle belongs to")
 	fs.StringVar(&c.defaultChannel, "default-channel", "", "The default channel for the bundle")
 	fs.BoolVar(&c.overwrite, "overwrite", true, "Overwrite the bundle's metadata and Dockerfile if they exist")
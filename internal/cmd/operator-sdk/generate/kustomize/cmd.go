@@ -27,6 +27,7 @@ func NewCmd() *cobra.Command {
 
 	cmd.AddCommand(
 		newManifestsCmd(),
+		newCRMetricsCmd(),
 	)
 
 	return cmd
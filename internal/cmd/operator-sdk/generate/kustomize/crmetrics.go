@@ -0,0 +1,172 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kustomize
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+
+	genutil "github.com/operator-framework/operator-sdk/internal/cmd/operator-sdk/generate/internal"
+	"github.com/operator-framework/operator-sdk/internal/generate/crmetrics"
+	"github.com/operator-framework/operator-sdk/internal/plugins/util/kustomize"
+	"github.com/operator-framework/operator-sdk/internal/util/projutil"
+)
+
+const crMetricsLongHelp = `
+Running 'generate kustomize crmetrics' will (re)generate a kube-state-metrics custom resource state
+configuration and a kustomization.yaml in 'config/crmetrics'. The configuration exposes per-CR gauges for
+each API in the project: the creation timestamp, the status of each condition in 'status.conditions',
+and any spec fields set with '--spec-field'.
+
+The kustomization.yaml wraps the configuration in a ConfigMap that can be mounted into a kube-state-metrics
+deployment and passed to its '--custom-resource-state-config-file' flag. kube-state-metrics must also be
+granted permission to list and watch the project's custom resources.
+`
+
+const crMetricsExamples = `
+  # Expose the size field of each Memcached CR as a metric in addition to default metrics:
+  $ operator-sdk generate kustomize crmetrics --spec-field Memcached:spec.size
+
+  $ tree config/crmetrics
+  config/crmetrics
+  ├── custom-resource-state.yaml
+  └── kustomization.yaml
+`
+
+// crMetricsFile is the name of the generated custom resource state configuration file.
+const crMetricsFile = "custom-resource-state.yaml"
+
+type crMetricsCmd struct {
+	projectName string
+	outputDir   string
+	specFields  []string
+	quiet       bool
+}
+
+// newCRMetricsCmd returns the 'crmetrics' command configured for the new project layout.
+func newCRMetricsCmd() *cobra.Command {
+	c := &crMetricsCmd{}
+	cmd := &cobra.Command{
+		Use:     "crmetrics",
+		Short:   "Generates a kube-state-metrics configuration exposing per-CR metrics for project APIs",
+		Long:    crMetricsLongHelp,
+		Example: crMetricsExamples,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("command %s doesn't accept any arguments", cmd.CommandPath())
+			}
+
+			cfg, err := projutil.ReadConfig()
+			if err != nil {
+				return fmt.Errorf("error reading configuration: %v", err)
+			}
+			if c.projectName, err = genutil.GetOperatorName(cfg); err != nil {
+				return err
+			}
+
+			if err = c.run(cfg); err != nil {
+				log.Fatalf("Error generating custom resource metrics configuration: %v", err)
+			}
+			return nil
+		},
+	}
+
+	c.addFlagsTo(cmd.Flags())
+
+	return cmd
+}
+
+func (c *crMetricsCmd) addFlagsTo(fs *pflag.FlagSet) {
+	fs.StringVar(&c.outputDir, "output-dir", filepath.Join("config", "crmetrics"),
+		"Directory to write the configuration and kustomization.yaml to")
+	fs.StringSliceVar(&c.specFields, "spec-field", nil, "Spec field to expose as a gauge, in the format "+
+		"<kind>:<path>, ex. Memcached:spec.size. May be set more than once")
+	fs.BoolVarP(&c.quiet, "quiet", "q", false, "Run in quiet mode")
+}
+
+// crMetricsKustomization is the kustomization.yaml written with the configuration.
+const crMetricsKustomization = `configMapGenerator:
+- name: custom-resource-state-config
+  files:
+  - ` + crMetricsFile + `
+`
+
+// run generates a custom resource state configuration for all APIs in cfg.
+func (c crMetricsCmd) run(cfg *config.Config) error {
+	specFields, err := crmetrics.ParseSpecFields(c.specFields)
+	if err != nil {
+		return err
+	}
+
+	gvks := make([]schema.GroupVersionKind, len(cfg.Resources))
+	for i, r := range cfg.Resources {
+		gvks[i] = schema.GroupVersionKind{
+			Group:   fmt.Sprintf("%s.%s", r.Group, cfg.Domain),
+			Version: r.Version,
+			Kind:    r.Kind,
+		}
+	}
+	for kind := range specFields {
+		if !hasKind(gvks, kind) {
+			return fmt.Errorf("spec field kind %q is not a project API", kind)
+		}
+	}
+
+	if !c.quiet {
+		fmt.Println("Generating custom resource metrics configuration in", c.outputDir)
+	}
+
+	gen := crmetrics.Generator{
+		OperatorName: c.projectName,
+		GVKs:         gvks,
+		SpecFields:   specFields,
+	}
+	b, err := gen.GenerateYAML()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.outputDir, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(c.outputDir, crMetricsFile), b, 0644); err != nil {
+		return err
+	}
+	if err := kustomize.WriteIfNotExist(c.outputDir, crMetricsKustomization); err != nil {
+		return fmt.Errorf("error writing kustomization.yaml: %v", err)
+	}
+
+	if !c.quiet {
+		fmt.Println("Custom resource metrics configuration generated successfully")
+	}
+	return nil
+}
+
+// hasKind returns true if a GVK in gvks has kind.
+func hasKind(gvks []schema.GroupVersionKind, kind string) bool {
+	for _, gvk := range gvks {
+		if gvk.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
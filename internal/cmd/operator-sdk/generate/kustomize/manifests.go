@@ -64,6 +64,7 @@ type manifestsCmd struct {
 	inputDir    string
 	outputDir   string
 	apisDir     string
+	iconFile    string
 	quiet       bool
 
 	// Interactive options.
@@ -121,6 +122,8 @@ func (c *manifestsCmd) addFlagsTo(fs *pflag.FlagSet) {
 	fs.StringVar(&c.inputDir, "input-dir", "", "Directory containing existing kustomize files")
 	fs.StringVar(&c.outputDir, "output-dir", "", "Directory to write kustomize files")
 	fs.StringVar(&c.apisDir, "apis-dir", "", "Root directory for API type defintions")
+	fs.StringVar(&c.iconFile, "icon-file", "", "Path to an icon file (svg, png, jpeg, or gif) to base64-encode "+
+		"and embed in the ClusterServiceVersion base")
 	fs.BoolVarP(&c.quiet, "quiet", "q", false, "Run in quiet mode")
 	fs.BoolVar(&c.interactive, "interactive", false, "When set or no kustomize base exists, an interactive "+
 		"command prompt will be presented to accept non-inferrable metadata")
@@ -169,6 +172,7 @@ func (c manifestsCmd) run(cfg *config.Config) error {
 	csvGen := gencsv.Generator{
 		OperatorName: c.projectName,
 		OperatorType: projutil.PluginKeyToOperatorType(cfg.Layout),
+		IconPath:     c.iconFile,
 	}
 	opts := []gencsv.Option{
 		gencsv.WithBase(c.inputDir, c.apisDir, c.interactiveLevel),
@@ -35,6 +35,17 @@ func newInstallCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&mgr.Version, "version", installer.DefaultVersion, "version of OLM resources to install")
+	cmd.Flags().BoolVar(&mgr.Resume, "resume", false, "continue a previous install of the same version that "+
+		"did not complete, skipping resources already created and steps already completed")
+	cmd.Flags().BoolVar(&mgr.Reapply, "reapply", false, "update OLM resources that already exist to match "+
+		"the manifests of the OLM version by server-side apply, reconciling resources that have drifted")
+	cmd.Flags().BoolVar(&mgr.Force, "force", false, "install the OLM version even if it does not support "+
+		"the cluster's Kubernetes version")
+	cmd.Flags().StringToStringVar(&mgr.NodeSelector, "node-selector", nil, "node labels that OLM's pods "+
+		"must be scheduled on, ex. node-role.kubernetes.io/infra=")
+	cmd.Flags().StringArrayVar(&mgr.Tolerations, "toleration", nil, "toleration added to OLM's pods, "+
+		"of the form key[=value][:effect], ex. node-role.kubernetes.io/infra:NoSchedule; may be repeated")
+	cmd.Flags().StringVar(&mgr.PriorityClassName, "priority-class-name", "", "priority class of OLM's pods")
 	mgr.AddToFlagSet(cmd.Flags())
 	return cmd
 }
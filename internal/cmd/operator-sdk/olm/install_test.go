@@ -33,6 +33,53 @@ var _ = Describe("Running an olm install command", func() {
 			Expect(flag).NotTo(BeNil())
 			Expect(flag.DefValue).To(Equal(installer.DefaultVersion))
 			Expect(flag.Usage).NotTo(BeNil())
+
+			flag = cmd.Flags().Lookup("resume")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("false"))
+			Expect(flag.Usage).NotTo(BeNil())
+
+			flag = cmd.Flags().Lookup("reapply")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("false"))
+			Expect(flag.Usage).NotTo(BeNil())
+
+			flag = cmd.Flags().Lookup("force")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("false"))
+			Expect(flag.Usage).NotTo(BeNil())
+
+			flag = cmd.Flags().Lookup("olm-namespace")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(installer.DefaultOLMNamespace))
+
+			flag = cmd.Flags().Lookup("kubeconfig")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(""))
+
+			flag = cmd.Flags().Lookup("kubeconfig-context")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(""))
+
+			flag = cmd.Flags().Lookup("manifests-dir")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(""))
+
+			flag = cmd.Flags().Lookup("base-url")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(installer.DefaultBaseDownloadURL))
+
+			flag = cmd.Flags().Lookup("node-selector")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("[]"))
+
+			flag = cmd.Flags().Lookup("toleration")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("[]"))
+
+			flag = cmd.Flags().Lookup("priority-class-name")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(""))
 		})
 	})
 })
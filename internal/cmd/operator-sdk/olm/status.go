@@ -34,9 +34,10 @@ func newStatusCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&mgr.OLMNamespace, "olm-namespace", installer.DefaultOLMNamespace, "namespace where OLM is installed")
 	cmd.Flags().StringVar(&mgr.Version, "version", "", "version of OLM installed on cluster; if unset"+
 		"operator-sdk attempts to auto-discover the version")
+	cmd.Flags().StringVarP(&mgr.Output, "output", "o", installer.OutputText, "output format, one of: "+
+		installer.OutputText+", "+installer.OutputJSON+", "+installer.OutputYAML)
 	mgr.AddToFlagSet(cmd.Flags())
 	return cmd
 }
@@ -35,8 +35,10 @@ func newUninstallCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&mgr.Version, "version", "", "version of OLM resources to uninstall.")
-	cmd.Flags().StringVar(&mgr.OLMNamespace, "olm-namespace", installer.DefaultOLMNamespace,
-		"namespace from where OLM is to be uninstalled.")
+	cmd.Flags().BoolVar(&mgr.Foreground, "foreground", false, "delete each OLM resource only after "+
+		"its dependents are deleted.")
+	cmd.Flags().BoolVar(&mgr.DryRun, "dry-run", false, "list the OLM resources that would be deleted, "+
+		"such as CSVs, CRDs, deployments, and RBAC, without deleting them.")
 	mgr.AddToFlagSet(cmd.Flags())
 	return cmd
 }
@@ -37,6 +37,24 @@ var _ = Describe("Running an olm uninstall command", func() {
 			Expect(flag).NotTo(BeNil())
 			Expect(flag.DefValue).To(Equal(installer.DefaultOLMNamespace))
 			Expect(flag.Usage).NotTo(BeNil())
+
+			flag = cmd.Flags().Lookup("foreground")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("false"))
+			Expect(flag.Usage).NotTo(BeNil())
+
+			flag = cmd.Flags().Lookup("dry-run")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("false"))
+			Expect(flag.Usage).NotTo(BeNil())
+
+			flag = cmd.Flags().Lookup("manifests-dir")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(""))
+
+			flag = cmd.Flags().Lookup("base-url")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(installer.DefaultBaseDownloadURL))
 		})
 	})
 })
@@ -27,6 +27,8 @@ import (
 
 func NewCmd(cfg *operator.Configuration) *cobra.Command {
 	var timeout time.Duration
+	var wait bool
+	var exportDir string
 
 	i := bundle.NewInstall(cfg)
 	cmd := &cobra.Command{
@@ -41,9 +43,23 @@ func NewCmd(cfg *operator.Configuration) *cobra.Command {
 			defer cancel()
 
 			i.BundleImage = args[0]
+			i.NoWait = !wait
+
+			// Label and record each resource created, so the session can be
+			// cleaned up with 'cleanup --session' and exported.
+			session := operator.NewSession(cfg.Client, cfg.Scheme)
+			cfg.Client = session
+			logrus.Infof("Run session ID: %s", session.ID)
 
 			// TODO(joelanford): Add cleanup logic if this fails?
 			_, err := i.Run(ctx)
+			if exportDir != "" {
+				if err := session.WriteManifests(exportDir); err != nil {
+					logrus.Errorf("Failed to export manifests: %v", err)
+				} else {
+					logrus.Infof("Exported created resources to %s", exportDir)
+				}
+			}
 			if err != nil {
 				logrus.Fatalf("Failed to run bundle: %v\n", err)
 			}
@@ -54,5 +70,9 @@ func NewCmd(cfg *operator.Configuration) *cobra.Command {
 	i.BindFlags(cmd.Flags())
 
 	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute, "install timeout")
+	cmd.Flags().BoolVar(&wait, "wait", true, "wait for the operator to be installed. If false, the operator's "+
+		"resources are created and install plans are approved automatically")
+	cmd.Flags().StringVar(&exportDir, "export-manifests", "", "directory to write the resources created by the "+
+		"command to, ex. to reproduce the session")
 	return cmd
 }
@@ -17,13 +17,13 @@ package scorecard
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -33,18 +33,23 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/flags"
 	registryutil "github.com/operator-framework/operator-sdk/internal/registry"
 	"github.com/operator-framework/operator-sdk/internal/scorecard"
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
 )
 
 type scorecardCmd struct {
 	bundle         string
 	config         string
+	crManifests    []string
+	failOn         string
 	kubeconfig     string
+	kubeContext    string
 	namespace      string
 	outputFormat   string
 	selector       string
 	serviceAccount string
 	list           bool
 	skipCleanup    bool
+	local          bool
 	waitTime       time.Duration
 }
 
@@ -68,25 +73,35 @@ If the argument holds an image tag, it must be present remotely.`,
 		},
 	}
 
-	scorecardCmd.Flags().StringVar(&c.kubeconfig, "kubeconfig", "", "kubeconfig path")
+	k8sutil.BindKubeconfigFlags(scorecardCmd.Flags(), &c.kubeconfig, &c.kubeContext)
 	scorecardCmd.Flags().StringVarP(&c.selector, "selector", "l", "", "label selector to determine which tests are run")
 	scorecardCmd.Flags().StringVarP(&c.config, "config", "c", "", "path to scorecard config file")
 	scorecardCmd.Flags().StringVarP(&c.namespace, "namespace", "n", "", "namespace to run the test images in")
 	scorecardCmd.Flags().StringVarP(&c.outputFormat, "output", "o", "text",
-		"Output format for results. Valid values: text, json")
+		"Output format for results. Valid values: text, json, junit")
 	scorecardCmd.Flags().StringVarP(&c.serviceAccount, "service-account", "s", "default",
 		"Service account to use for tests")
 	scorecardCmd.Flags().BoolVarP(&c.list, "list", "L", false,
 		"Option to enable listing which tests are run")
 	scorecardCmd.Flags().BoolVarP(&c.skipCleanup, "skip-cleanup", "x", false,
 		"Disable resource cleanup after tests are run")
+	scorecardCmd.Flags().StringSliceVar(&c.crManifests, "cr-manifest", nil,
+		"path to a file of custom resources used by basic and olm tests instead of the CSV's alm-examples. "+
+			"May be set multiple times")
+	scorecardCmd.Flags().BoolVar(&c.local, "local", false,
+		"run built-in basic and olm tests in-process instead of in pods. Other tests are run in pods, "+
+			"and only these require a cluster")
+	scorecardCmd.Flags().StringVar(&c.failOn, "fail-on", string(scorecard.SeverityError),
+		"least severe failing test that fails the run. Tests set their severity with the \"severity\" label. "+
+			"Valid values: error, warning")
 	scorecardCmd.Flags().DurationVarP(&c.waitTime, "wait-time", "w", 30*time.Second,
 		"seconds to wait for tests to complete. Example: 35s")
 
 	return scorecardCmd
 }
 
-func (c *scorecardCmd) printOutput(output v1alpha3.TestList) error {
+func (c *scorecardCmd) printOutput(stages []scorecard.StageTests) error {
+	output := scorecard.FlattenStages(stages)
 	switch c.outputFormat {
 	case "text":
 		if len(output.Items) == 0 {
@@ -102,6 +117,12 @@ func (c *scorecardCmd) printOutput(output v1alpha3.TestList) error {
 			return fmt.Errorf("marshal json error: %v", err)
 		}
 		fmt.Printf("%s\n", string(bytes))
+	case "junit":
+		bytes, err := xml.MarshalIndent(scorecard.JUnit(stages), "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal junit error: %v", err)
+		}
+		fmt.Printf("%s%s\n", xml.Header, string(bytes))
 	default:
 		return fmt.Errorf("invalid output format selected")
 	}
@@ -142,64 +163,110 @@ func (c *scorecardCmd) run() (err error) {
 	if err != nil {
 		return fmt.Errorf("could not find config file %w", err)
 	}
+	o.Assertions, err = scorecard.LoadAssertions(configPath)
+	if err != nil {
+		return fmt.Errorf("could not load config assertions %w", err)
+	}
+	o.TestPods, err = scorecard.LoadTestPods(configPath)
+	if err != nil {
+		return fmt.Errorf("could not load config test pods %w", err)
+	}
+	o.Fixtures, err = scorecard.LoadFixtures(configPath)
+	if err != nil {
+		return fmt.Errorf("could not load config fixtures %w", err)
+	}
 
 	o.Selector, err = labels.Parse(c.selector)
 	if err != nil {
 		return fmt.Errorf("could not parse selector %w", err)
 	}
 
-	var scorecardTests v1alpha3.TestList
+	var stages []scorecard.StageTests
 	if c.list {
-		scorecardTests = o.List()
+		stages = o.ListStages()
 	} else {
 		runner := scorecard.PodTestRunner{
 			ServiceAccount: c.serviceAccount,
-			Namespace:      scorecard.GetKubeNamespace(c.kubeconfig, c.namespace),
+			Namespace:      scorecard.GetKubeNamespace(c.kubeconfig, c.kubeContext, c.namespace),
 			BundlePath:     c.bundle,
 			BundleMetadata: metadata,
+			CRManifests:    c.crManifests,
 		}
 
-		// Only get the client if running tests.
-		if runner.Client, err = scorecard.GetKubeClient(c.kubeconfig); err != nil {
-			return fmt.Errorf("error getting kubernetes client: %w", err)
+		if c.local {
+			// Built-in tests run in-process, so a cluster is only needed by other tests.
+			local := &scorecard.LocalTestRunner{
+				BundlePath:     c.bundle,
+				BundleMetadata: metadata,
+				CRManifests:    c.crManifests,
+			}
+			if err := c.addClusterRunners(&o, &runner); err != nil {
+				log.Debugf("Running tests without a cluster: %v", err)
+			} else {
+				local.Fallback = &runner
+			}
+			o.TestRunner = local
+		} else {
+			if err := c.addClusterRunners(&o, &runner); err != nil {
+				return err
+			}
+			o.TestRunner = &runner
 		}
 
-		o.TestRunner = &runner
-
 		ctx, cancel := context.WithTimeout(context.Background(), c.waitTime)
 		defer cancel()
 
-		scorecardTests, err = o.Run(ctx)
+		stages, err = o.RunStages(ctx)
 		if err != nil {
 			return fmt.Errorf("error running tests %w", err)
 		}
 	}
 
-	if err := c.printOutput(scorecardTests); err != nil {
+	if err := c.printOutput(stages); err != nil {
 		log.Fatal(err)
 	}
 
-	if hasFailingTest(scorecardTests) {
+	// Validated by c.validate.
+	failOn, _ := scorecard.ParseFailOn(c.failOn)
+	if scorecard.HasFailingTest(scorecard.FlattenStages(stages), failOn) {
 		os.Exit(1)
 	}
 	return nil
 }
 
-func hasFailingTest(list v1alpha3.TestList) bool {
-	for _, t := range list.Items {
-		for _, r := range t.Status.Results {
-			if r.State != v1alpha3.PassState {
-				return true
-			}
-		}
+// addClusterRunners gets the clients of runner and of o's assertion and fixture
+// runners, which run tests against a cluster.
+func (c *scorecardCmd) addClusterRunners(o *scorecard.Scorecard, runner *scorecard.PodTestRunner) (err error) {
+	if runner.Client, err = scorecard.GetKubeClient(c.kubeconfig, c.kubeContext); err != nil {
+		return fmt.Errorf("error getting kubernetes client: %w", err)
 	}
-	return false
+
+	assertionRunner := scorecard.AssertionRunner{Namespace: runner.Namespace}
+	if assertionRunner.Client, err = scorecard.GetRuntimeClient(c.kubeconfig, c.kubeContext); err != nil {
+		return fmt.Errorf("error getting kubernetes client: %w", err)
+	}
+	o.AssertionRunner = &assertionRunner
+
+	fixtureRunner := scorecard.FixtureRunner{Client: assertionRunner.Client, Namespace: runner.Namespace}
+	if fixtureRunner.Mapper, err = scorecard.GetRESTMapper(c.kubeconfig, c.kubeContext); err != nil {
+		return fmt.Errorf("error getting kubernetes REST mapper: %w", err)
+	}
+	o.FixtureRunner = &fixtureRunner
+	return nil
 }
 
 func (c *scorecardCmd) validate(args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("a bundle image or directory argument is required")
 	}
+	if _, err := scorecard.ParseFailOn(c.failOn); err != nil {
+		return fmt.Errorf("invalid --fail-on: %v", err)
+	}
+	for _, path := range c.crManifests {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("invalid CR manifest: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -28,6 +28,10 @@ var _ = Describe("Running the scorecard command", func() {
 			flag := cmd.Flags().Lookup("kubeconfig")
 			Expect(flag).NotTo(BeNil())
 
+			flag = cmd.Flags().Lookup("kubeconfig-context")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal(""))
+
 			flag = cmd.Flags().Lookup("selector")
 			Expect(flag).NotTo(BeNil())
 			Expect(flag.Shorthand).To(Equal("l"))
@@ -65,13 +69,21 @@ var _ = Describe("Running the scorecard command", func() {
 			Expect(flag).NotTo(BeNil())
 			Expect(flag.Shorthand).To(Equal("w"))
 			Expect(flag.DefValue).To(Equal("30s"))
+
+			flag = cmd.Flags().Lookup("cr-manifest")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("[]"))
+
+			flag = cmd.Flags().Lookup("fail-on")
+			Expect(flag).NotTo(BeNil())
+			Expect(flag.DefValue).To(Equal("error"))
 		})
 	})
 
 	Describe("validate", func() {
 		var cmd scorecardCmd
 		BeforeEach(func() {
-			cmd = scorecardCmd{}
+			cmd = scorecardCmd{failOn: "error"}
 		})
 		It("fails if anything other than exactly one arg is provided", func() {
 			err := cmd.validate([]string{})
@@ -86,5 +98,17 @@ var _ = Describe("Running the scorecard command", func() {
 			err := cmd.validate([]string{input})
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("fails if --fail-on is not a valid severity", func() {
+			cmd.failOn = "suggestion"
+			err := cmd.validate([]string{"cherry"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("fails if a CR manifest does not exist", func() {
+			cmd.crManifests = []string{"does-not-exist.yaml"}
+			err := cmd.validate([]string{"cherry"})
+			Expect(err).To(HaveOccurred())
+		})
 	})
 })
@@ -0,0 +1,37 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign operator bundle and catalog images with cosign",
+		Long: `Sign operator bundle and catalog images with cosign (https://github.com/sigstore/cosign),
+which must be installed and on your PATH or set with $COSIGN. Images are signed keylessly
+unless a key is set with '--key'.
+
+Signatures can be verified before installation by running 'operator-sdk run bundle --verify-signature'.
+`,
+	}
+	cmd.AddCommand(
+		newImageCmd("bundle", "bundle"),
+		newImageCmd("catalog", "catalog (index)"),
+	)
+	return cmd
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-sdk/internal/registry"
+)
+
+// newImageCmd returns a command that signs an image of the given kind.
+func newImageCmd(use, kind string) *cobra.Command {
+	var key string
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s <%s-image>", use, use),
+		Short: fmt.Sprintf("Sign an operator %s image with cosign", kind),
+		Example: fmt.Sprintf(`  # Sign a %[1]s image keylessly:
+  $ operator-sdk sign %[1]s quay.io/example/memcached-operator-%[1]s:v0.0.1
+
+  # Sign a %[1]s image with a private key:
+  $ operator-sdk sign %[1]s quay.io/example/memcached-operator-%[1]s:v0.0.1 --key cosign.key
+`, use),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := registry.SignImage(cmd.Context(), log.NewEntry(log.StandardLogger()), args[0], key); err != nil {
+				log.Fatalf("Failed to sign %s image: %v", kind, err)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&key, "key", "", "path or KMS URI of the private key to sign with. "+
+		"If unset, the image is signed keylessly")
+	return cmd
+}
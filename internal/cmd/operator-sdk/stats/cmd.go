@@ -0,0 +1,142 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/operator-framework/operator-sdk/internal/telemetry"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+type statsCmd struct {
+	output string
+	clear  bool
+}
+
+func NewCmd() *cobra.Command {
+	c := &statsCmd{}
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Print a summary of locally recorded operator-sdk usage telemetry",
+		Long: fmt.Sprintf(`Print a summary of the operator-sdk usage telemetry recorded on this machine.
+
+Telemetry is disabled by default. Set $%[1]s=true to record an anonymized event for each
+command run: the command path, project plugin keys, SDK version, platform, duration, and the
+category of any error returned. Arguments, flag values, paths, and project names are never recorded.
+
+Events are recorded to $%[2]s if set, otherwise to 'operator-sdk/telemetry.jsonl' in the user's
+config directory. If $%[3]s is set, events are also POSTed to that URL as JSON.
+`, telemetry.EnabledEnv, telemetry.ReportFileEnv, telemetry.EndpointEnv),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("command %s doesn't accept any arguments", cmd.CommandPath())
+			}
+			return c.run(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&c.output, "output", "o", outputText, "Output format, one of: text, json")
+	cmd.Flags().BoolVar(&c.clear, "clear", false, "Delete the local telemetry report")
+
+	return cmd
+}
+
+func (c statsCmd) run(w io.Writer) error {
+	path, err := telemetry.DefaultReportFile()
+	if err != nil {
+		return fmt.Errorf("error getting telemetry report path: %v", err)
+	}
+
+	if c.clear {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error deleting telemetry report: %v", err)
+		}
+		fmt.Fprintf(w, "Deleted telemetry report %s\n", path)
+		return nil
+	}
+
+	events, err := telemetry.ReadEvents(path)
+	if err != nil {
+		return fmt.Errorf("error reading telemetry report: %v", err)
+	}
+	summary := telemetry.Summarize(events)
+
+	switch c.output {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	case outputText:
+		printSummary(w, path, summary)
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, must be one of: %s, %s", c.output, outputText, outputJSON)
+	}
+}
+
+func printSummary(w io.Writer, path string, s telemetry.Summary) {
+	if !telemetry.Enabled() {
+		fmt.Fprintf(w, "Telemetry is disabled. Set %s=true to enable it.\n", telemetry.EnabledEnv)
+	}
+	fmt.Fprintf(w, "Report: %s\n", path)
+	if s.Total == 0 {
+		fmt.Fprintln(w, "No commands recorded")
+		return
+	}
+	fmt.Fprintf(w, "Commands recorded: %d (%d failed) between %s and %s\n",
+		s.Total, s.Errors, s.First.Local().Format(time.RFC3339), s.Last.Local().Format(time.RFC3339))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	printCounts(tw, "COMMAND", s.Commands)
+	printCounts(tw, "PLUGIN", s.Plugins)
+	printCounts(tw, "SDK VERSION", s.SDKVersions)
+	printCounts(tw, "ERROR CATEGORY", s.ErrorCategories)
+	tw.Flush()
+}
+
+// printCounts prints counts in descending order, breaking ties by key.
+func printCounts(w io.Writer, header string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	fmt.Fprintf(w, "\n%s\tCOUNT\n", header)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\t%d\n", k, counts[k])
+	}
+}
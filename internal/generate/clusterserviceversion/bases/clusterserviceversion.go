@@ -46,6 +46,9 @@ type ClusterServiceVersion struct {
 	GVKs []schema.GroupVersionKind
 	// Interactive turns on an interactive prompt.
 	Interactive bool
+	// IconPath is the path to an icon file. If set, the icon is base64-encoded
+	// and replaces any icon in the base.
+	IconPath string
 
 	// Fields for input to the base.
 	DisplayName  string
@@ -56,7 +59,7 @@ type ClusterServiceVersion struct {
 	Provider     v1alpha1.AppLink
 	Links        []v1alpha1.AppLink
 	Maintainers  []v1alpha1.Maintainer
-	Icon         []v1alpha1.Icon // TODO(estroz): read icon bytes from files.
+	Icon         []v1alpha1.Icon
 }
 
 // GetBase returns a base v1alpha1.ClusterServiceVersion, populated
@@ -78,6 +81,14 @@ func (b ClusterServiceVersion) GetBase() (base *v1alpha1.ClusterServiceVersion,
 		meta.apply(base)
 	}
 
+	if b.IconPath != "" {
+		icon, err := ReadIcon(b.IconPath)
+		if err != nil {
+			return nil, err
+		}
+		base.Spec.Icon = []v1alpha1.Icon{icon}
+	}
+
 	if b.APIsDir != "" {
 		switch b.OperatorType {
 		case projutil.OperatorTypeGo:
@@ -0,0 +1,86 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bases
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+)
+
+// MaxIconSize is the maximum size in bytes of a decoded CSV icon.
+const MaxIconSize = 256 * 1024
+
+// iconMediaTypes maps icon file extensions to their media types.
+var iconMediaTypes = map[string]string{
+	".svg":  "image/svg+xml",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+}
+
+// ReadIcon reads the icon file at path and returns a v1alpha1.Icon containing
+// its base64-encoded data and a media type inferred from path's extension.
+func ReadIcon(path string) (icon v1alpha1.Icon, err error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	mediaType, ok := iconMediaTypes[ext]
+	if !ok {
+		return icon, fmt.Errorf("icon file %s has unsupported extension %q", path, ext)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return icon, err
+	}
+	icon.Data = base64.StdEncoding.EncodeToString(b)
+	icon.MediaType = mediaType
+
+	if err := ValidateIcon(icon); err != nil {
+		return icon, fmt.Errorf("invalid icon file %s: %v", path, err)
+	}
+	return icon, nil
+}
+
+// ValidateIcon returns an error if icon has an unsupported media type,
+// does not contain valid base64 data, or exceeds MaxIconSize when decoded.
+func ValidateIcon(icon v1alpha1.Icon) error {
+	supported := false
+	for _, mediaType := range iconMediaTypes {
+		if icon.MediaType == mediaType {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("unsupported media type %q", icon.MediaType)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(icon.Data)
+	if err != nil {
+		return fmt.Errorf("error decoding base64 data: %v", err)
+	}
+	if len(b) == 0 {
+		return fmt.Errorf("icon data is empty")
+	}
+	if len(b) > MaxIconSize {
+		return fmt.Errorf("icon size %d bytes exceeds maximum of %d bytes", len(b), MaxIconSize)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bases
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+)
+
+var _ = Describe("Icon", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "icon-")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Describe("ReadIcon", func() {
+		It("encodes an svg icon with the svg media type", func() {
+			data := []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+			path := filepath.Join(dir, "icon.svg")
+			Expect(ioutil.WriteFile(path, data, 0644)).To(Succeed())
+
+			icon, err := ReadIcon(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(icon.MediaType).To(Equal("image/svg+xml"))
+			Expect(icon.Data).To(Equal(base64.StdEncoding.EncodeToString(data)))
+		})
+		It("returns an error for an unsupported extension", func() {
+			path := filepath.Join(dir, "icon.bmp")
+			Expect(ioutil.WriteFile(path, []byte("data"), 0644)).To(Succeed())
+
+			_, err := ReadIcon(path)
+			Expect(err).To(HaveOccurred())
+		})
+		It("returns an error for an icon that is too large", func() {
+			path := filepath.Join(dir, "icon.png")
+			Expect(ioutil.WriteFile(path, make([]byte, MaxIconSize+1), 0644)).To(Succeed())
+
+			_, err := ReadIcon(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("ValidateIcon", func() {
+		It("returns an error for invalid base64 data", func() {
+			err := ValidateIcon(v1alpha1.Icon{Data: "not base64!", MediaType: "image/png"})
+			Expect(err).To(HaveOccurred())
+		})
+		It("returns an error for empty data", func() {
+			err := ValidateIcon(v1alpha1.Icon{MediaType: "image/png"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
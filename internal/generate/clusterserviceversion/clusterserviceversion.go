@@ -59,6 +59,14 @@ type Generator struct {
 	FromVersion string
 	// Collector holds all manifests relevant to the Generator.
 	Collector *collector.Manifests
+	// IconPath is the path to an icon file embedded in the CSV.
+	IconPath string
+	// RelatedImages, if true, sets the CSV's spec.relatedImages to the images
+	// of its deployments' containers and RELATED_IMAGE_* environment variables.
+	RelatedImages bool
+	// ResolveImage, if set, pins the images of the CSV's deployments'
+	// containers and RELATED_IMAGE_* environment variables by digest.
+	ResolveImage ImageResolver
 
 	// Project configuration.
 	config *config.Config
@@ -164,11 +172,23 @@ func (g *Generator) Generate(cfg *config.Config, opts ...Option) (err error) {
 	// Add sdk labels to csv
 	g.setSDKAnnotations(csv)
 
+	if g.ResolveImage != nil {
+		if err := pinImages(csv, g.ResolveImage); err != nil {
+			return err
+		}
+	}
+	var obj interface{} = csv
+	if g.RelatedImages {
+		if obj, err = withRelatedImages(csv, getRelatedImages(csv)); err != nil {
+			return err
+		}
+	}
+
 	w, err := g.getWriter()
 	if err != nil {
 		return err
 	}
-	return genutil.WriteObject(w, csv)
+	return genutil.WriteObject(w, obj)
 }
 
 // setSDKAnnotations adds SDK metric labels to the base if they do not exist.
@@ -241,6 +261,7 @@ func (g Generator) makeBaseGetter(basePath, apisDir string, interactive bool) ge
 			APIsDir:      apisDir,
 			GVKs:         gvks,
 			Interactive:  interactive,
+			IconPath:     g.IconPath,
 		}
 		return b.GetBase()
 	}
@@ -0,0 +1,133 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterserviceversion
+
+import (
+	"fmt"
+	"strings"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// relatedImageEnvPrefix prefixes the names of container environment variables
+// holding images the operator deploys, ex. RELATED_IMAGE_MEMCACHED.
+const relatedImageEnvPrefix = "RELATED_IMAGE_"
+
+// ImageResolver returns image pinned by digest, ex.
+// quay.io/example/memcached@sha256:<digest>.
+type ImageResolver func(image string) (string, error)
+
+// relatedImage is an entry of a CSV's spec.relatedImages.
+type relatedImage struct {
+	Name  string
+	Image string
+}
+
+// pinImages replaces each image of csv's deployment containers, and each
+// value of their RELATED_IMAGE_* environment variables, with the image
+// pinned by digest by resolve.
+func pinImages(csv *operatorsv1alpha1.ClusterServiceVersion, resolve ImageResolver) error {
+	pinned := map[string]string{}
+	pin := func(image *string) error {
+		if strings.Contains(*image, "@") {
+			return nil
+		}
+		if p, ok := pinned[*image]; ok {
+			*image = p
+			return nil
+		}
+		p, err := resolve(*image)
+		if err != nil {
+			return fmt.Errorf("error resolving digest of image %s: %v", *image, err)
+		}
+		pinned[*image] = p
+		*image = p
+		return nil
+	}
+	for _, c := range deploymentContainers(csv) {
+		if err := pin(&c.Image); err != nil {
+			return err
+		}
+		for i := range c.Env {
+			if strings.HasPrefix(c.Env[i].Name, relatedImageEnvPrefix) && c.Env[i].Value != "" {
+				if err := pin(&c.Env[i].Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getRelatedImages returns the images of csv's deployment containers, named
+// by their container, and the values of their RELATED_IMAGE_* environment
+// variables, named by the lowercase variable name suffix. Each image is
+// listed once, with its first name.
+func getRelatedImages(csv *operatorsv1alpha1.ClusterServiceVersion) (images []relatedImage) {
+	seen := map[string]bool{}
+	add := func(name, image string) {
+		if image != "" && !seen[image] {
+			seen[image] = true
+			images = append(images, relatedImage{Name: name, Image: image})
+		}
+	}
+	for _, c := range deploymentContainers(csv) {
+		add(c.Name, c.Image)
+		for _, env := range c.Env {
+			if strings.HasPrefix(env.Name, relatedImageEnvPrefix) {
+				add(strings.ToLower(strings.TrimPrefix(env.Name, relatedImageEnvPrefix)), env.Value)
+			}
+		}
+	}
+	return images
+}
+
+// deploymentContainers returns the init containers and containers of csv's
+// install strategy deployments.
+func deploymentContainers(csv *operatorsv1alpha1.ClusterServiceVersion) (containers []*corev1.Container) {
+	for i := range csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		podSpec := &csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[i].Spec.Template.Spec
+		for j := range podSpec.InitContainers {
+			containers = append(containers, &podSpec.InitContainers[j])
+		}
+		for j := range podSpec.Containers {
+			containers = append(containers, &podSpec.Containers[j])
+		}
+	}
+	return containers
+}
+
+// withRelatedImages returns csv as an unstructured object with
+// spec.relatedImages set to images, since not all CSV API versions define
+// the field.
+func withRelatedImages(csv *operatorsv1alpha1.ClusterServiceVersion,
+	images []relatedImage) (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(csv)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]interface{}, len(images))
+	for i, image := range images {
+		list[i] = map[string]interface{}{"name": image.Name, "image": image.Image}
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	if err := unstructured.SetNestedSlice(u.Object, list, "spec", "relatedImages"); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterserviceversion
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newRelatedImagesCSV() *v1alpha1.ClusterServiceVersion {
+	csv := &v1alpha1.ClusterServiceVersion{}
+	csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs = []v1alpha1.StrategyDeploymentSpec{{Name: "manager"}}
+	podSpec := &csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[0].Spec.Template.Spec
+	podSpec.InitContainers = []corev1.Container{{Name: "init", Image: "quay.io/example/init:v0.0.1"}}
+	podSpec.Containers = []corev1.Container{
+		{
+			Name:  "manager",
+			Image: "quay.io/example/memcached-operator:v0.0.1",
+			Env: []corev1.EnvVar{
+				{Name: "RELATED_IMAGE_MEMCACHED", Value: "docker.io/library/memcached:1.4.36"},
+				{Name: "RELATED_IMAGE_INIT", Value: "quay.io/example/init:v0.0.1"},
+				{Name: "WATCH_NAMESPACE", Value: "default"},
+			},
+		},
+		{Name: "proxy", Image: "gcr.io/kubebuilder/kube-rbac-proxy@sha256:abc"},
+	}
+	return csv
+}
+
+var _ = Describe("Related images", func() {
+	var csv *v1alpha1.ClusterServiceVersion
+
+	BeforeEach(func() {
+		csv = newRelatedImagesCSV()
+	})
+
+	Describe("getRelatedImages", func() {
+		It("returns container and RELATED_IMAGE_* images once each", func() {
+			Expect(getRelatedImages(csv)).To(Equal([]relatedImage{
+				{Name: "init", Image: "quay.io/example/init:v0.0.1"},
+				{Name: "manager", Image: "quay.io/example/memcached-operator:v0.0.1"},
+				{Name: "memcached", Image: "docker.io/library/memcached:1.4.36"},
+				{Name: "proxy", Image: "gcr.io/kubebuilder/kube-rbac-proxy@sha256:abc"},
+			}))
+		})
+	})
+
+	Describe("pinImages", func() {
+		It("pins tagged images by digest", func() {
+			var resolved []string
+			resolve := func(image string) (string, error) {
+				resolved = append(resolved, image)
+				return image + "@sha256:123", nil
+			}
+			Expect(pinImages(csv, resolve)).To(Succeed())
+			Expect(resolved).To(ConsistOf(
+				"quay.io/example/init:v0.0.1",
+				"quay.io/example/memcached-operator:v0.0.1",
+				"docker.io/library/memcached:1.4.36",
+			))
+
+			podSpec := csv.Spec.InstallStrategy.StrategySpec.DeploymentSpecs[0].Spec.Template.Spec
+			Expect(podSpec.InitContainers[0].Image).To(Equal("quay.io/example/init:v0.0.1@sha256:123"))
+			Expect(podSpec.Containers[0].Image).To(Equal("quay.io/example/memcached-operator:v0.0.1@sha256:123"))
+			Expect(podSpec.Containers[0].Env[0].Value).To(Equal("docker.io/library/memcached:1.4.36@sha256:123"))
+			Expect(podSpec.Containers[0].Env[1].Value).To(Equal("quay.io/example/init:v0.0.1@sha256:123"))
+			Expect(podSpec.Containers[0].Env[2].Value).To(Equal("default"))
+			Expect(podSpec.Containers[1].Image).To(Equal("gcr.io/kubebuilder/kube-rbac-proxy@sha256:abc"))
+		})
+
+		It("fails if an image cannot be resolved", func() {
+			resolve := func(image string) (string, error) {
+				return "", errors.New("not found")
+			}
+			err := pinImages(csv, resolve)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("quay.io/example/init:v0.0.1"))
+		})
+	})
+
+	Describe("withRelatedImages", func() {
+		It("sets spec.relatedImages", func() {
+			obj, err := withRelatedImages(csv, []relatedImage{{Name: "memcached", Image: "docker.io/library/memcached:1.4.36"}})
+			Expect(err).NotTo(HaveOccurred())
+			images, found, err := unstructured.NestedSlice(obj.Object, "spec", "relatedImages")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(images).To(Equal([]interface{}{
+				map[string]interface{}{"name": "memcached", "image": "docker.io/library/memcached:1.4.36"},
+			}))
+		})
+	})
+})
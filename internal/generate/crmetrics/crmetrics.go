@@ -0,0 +1,193 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crmetrics generates kube-state-metrics custom resource state
+// configuration, which exposes per-CR gauges for a project's APIs.
+package crmetrics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigKind is the kind of kube-state-metrics custom resource state configuration.
+const ConfigKind = "CustomResourceStateMetrics"
+
+// Config is a kube-state-metrics custom resource state configuration.
+// See https://github.com/kubernetes/kube-state-metrics/blob/master/docs/customresourcestate-metrics.md
+type Config struct {
+	Kind string     `json:"kind"`
+	Spec ConfigSpec `json:"spec"`
+}
+
+// ConfigSpec lists the resources metrics are exposed for.
+type ConfigSpec struct {
+	Resources []Resource `json:"resources"`
+}
+
+// Resource configures metrics for one GVK.
+type Resource struct {
+	GroupVersionKind GroupVersionKind    `json:"groupVersionKind"`
+	MetricNamePrefix string              `json:"metricNamePrefix,omitempty"`
+	LabelsFromPath   map[string][]string `json:"labelsFromPath,omitempty"`
+	Metrics          []Metric            `json:"metrics"`
+}
+
+// GroupVersionKind identifies a resource's API.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// Metric is a single metric family exposed for each resource.
+type Metric struct {
+	Name string     `json:"name"`
+	Help string     `json:"help,omitempty"`
+	Each MetricType `json:"each"`
+}
+
+// MetricType configures how a metric's value is read from a resource.
+type MetricType struct {
+	Type  string `json:"type"`
+	Gauge *Gauge `json:"gauge,omitempty"`
+}
+
+// Gauge reads a gauge value from a path in a resource.
+type Gauge struct {
+	Path           []string            `json:"path"`
+	ValueFrom      []string            `json:"valueFrom,omitempty"`
+	LabelsFromPath map[string][]string `json:"labelsFromPath,omitempty"`
+}
+
+const metricTypeGauge = "Gauge"
+
+// Generator generates a custom resource state configuration.
+type Generator struct {
+	// OperatorName prefixes all metric names.
+	OperatorName string
+	// GVKs are the APIs to expose metrics for.
+	GVKs []schema.GroupVersionKind
+	// SpecFields maps a kind to dot-separated paths of its spec fields exposed
+	// as gauges, ex. "spec.size".
+	SpecFields map[string][]string
+}
+
+// Generate returns a custom resource state configuration exposing the created
+// timestamp, condition statuses, and spec fields of g's GVKs.
+func (g Generator) Generate() (*Config, error) {
+	cfg := &Config{Kind: ConfigKind}
+	for _, gvk := range g.GVKs {
+		r := Resource{
+			GroupVersionKind: GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+			MetricNamePrefix: metricName(g.OperatorName, gvk.Kind),
+			LabelsFromPath: map[string][]string{
+				"name":      {"metadata", "name"},
+				"namespace": {"metadata", "namespace"},
+			},
+			Metrics: []Metric{
+				{
+					Name: "created",
+					Help: fmt.Sprintf("Unix creation timestamp of a %s.", gvk.Kind),
+					Each: MetricType{
+						Type:  metricTypeGauge,
+						Gauge: &Gauge{Path: []string{"metadata", "creationTimestamp"}},
+					},
+				},
+				{
+					Name: "status_condition",
+					Help: fmt.Sprintf("The status of each condition of a %s, 1 if True and 0 otherwise.", gvk.Kind),
+					Each: MetricType{
+						Type: metricTypeGauge,
+						Gauge: &Gauge{
+							Path:           []string{"status", "conditions"},
+							ValueFrom:      []string{"status"},
+							LabelsFromPath: map[string][]string{"type": {"type"}},
+						},
+					},
+				},
+			},
+		}
+
+		for _, field := range g.SpecFields[gvk.Kind] {
+			path := strings.Split(field, ".")
+			if len(path) < 2 || path[0] != "spec" {
+				return nil, fmt.Errorf("%s field %q is not a spec field", gvk.Kind, field)
+			}
+			r.Metrics = append(r.Metrics, Metric{
+				Name: metricName(path...),
+				Help: fmt.Sprintf("The value of %s of a %s.", field, gvk.Kind),
+				Each: MetricType{
+					Type:  metricTypeGauge,
+					Gauge: &Gauge{Path: path},
+				},
+			})
+		}
+		cfg.Spec.Resources = append(cfg.Spec.Resources, r)
+	}
+	return cfg, nil
+}
+
+// GenerateYAML returns g's configuration as YAML.
+func (g Generator) GenerateYAML() ([]byte, error) {
+	cfg, err := g.Generate()
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(cfg)
+}
+
+// ParseSpecFields parses "<kind>:<path>" values, ex. "Memcached:spec.size",
+// into a map of kinds to spec field paths.
+func ParseSpecFields(values []string) (map[string][]string, error) {
+	fields := make(map[string][]string)
+	for _, v := range values {
+		split := strings.SplitN(v, ":", 2)
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return nil, fmt.Errorf("spec field %q must have the format <kind>:<path>", v)
+		}
+		fields[split[0]] = append(fields[split[0]], split[1])
+	}
+	return fields, nil
+}
+
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// metricName joins parts into a snake_case Prometheus metric name.
+func metricName(parts ...string) string {
+	names := make([]string, len(parts))
+	for i, p := range parts {
+		names[i] = invalidMetricChars.ReplaceAllString(toSnake(p), "_")
+	}
+	return strings.Join(names, "_")
+}
+
+// toSnake converts a camelCase string to snake_case.
+func toSnake(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
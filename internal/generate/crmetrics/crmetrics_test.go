@@ -0,0 +1,75 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crmetrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCRMetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CR Metrics Suite")
+}
+
+var _ = Describe("Generator", func() {
+	gvk := schema.GroupVersionKind{Group: "cache.example.com", Version: "v1alpha1", Kind: "Memcached"}
+
+	It("generates default metrics for each GVK", func() {
+		g := Generator{OperatorName: "memcached-operator", GVKs: []schema.GroupVersionKind{gvk}}
+		cfg, err := g.Generate()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Kind).To(Equal(ConfigKind))
+		Expect(cfg.Spec.Resources).To(HaveLen(1))
+
+		r := cfg.Spec.Resources[0]
+		Expect(r.GroupVersionKind).To(Equal(GroupVersionKind{Group: "cache.example.com", Version: "v1alpha1", Kind: "Memcached"}))
+		Expect(r.MetricNamePrefix).To(Equal("memcached_operator_memcached"))
+		Expect(r.Metrics).To(HaveLen(2))
+		Expect(r.Metrics[0].Name).To(Equal("created"))
+		Expect(r.Metrics[0].Each.Gauge.Path).To(Equal([]string{"metadata", "creationTimestamp"}))
+		Expect(r.Metrics[1].Name).To(Equal("status_condition"))
+		Expect(r.Metrics[1].Each.Gauge.ValueFrom).To(Equal([]string{"status"}))
+	})
+
+	It("generates metrics for flagged spec fields", func() {
+		fields, err := ParseSpecFields([]string{"Memcached:spec.size", "Memcached:spec.cacheConfig.maxItems"})
+		Expect(err).NotTo(HaveOccurred())
+		g := Generator{OperatorName: "memcached-operator", GVKs: []schema.GroupVersionKind{gvk}, SpecFields: fields}
+		cfg, err := g.Generate()
+		Expect(err).NotTo(HaveOccurred())
+
+		metrics := cfg.Spec.Resources[0].Metrics
+		Expect(metrics).To(HaveLen(4))
+		Expect(metrics[2].Name).To(Equal("spec_size"))
+		Expect(metrics[2].Each.Gauge.Path).To(Equal([]string{"spec", "size"}))
+		Expect(metrics[3].Name).To(Equal("spec_cache_config_max_items"))
+		Expect(metrics[3].Each.Gauge.Path).To(Equal([]string{"spec", "cacheConfig", "maxItems"}))
+	})
+
+	It("returns an error for a non-spec field", func() {
+		g := Generator{GVKs: []schema.GroupVersionKind{gvk}, SpecFields: map[string][]string{"Memcached": {"status.nodes"}}}
+		_, err := g.Generate()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error for a malformed spec field flag", func() {
+		_, err := ParseSpecFields([]string{"spec.size"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,199 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rbacdiff compares a project's RBAC rules with deployed rules.
+package rbacdiff
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
+)
+
+// Role is a Role or ClusterRole's identity and rules.
+type Role struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Rules     []rbacv1.PolicyRule
+}
+
+// String returns r's kind and name, ex. "ClusterRole manager-role".
+func (r Role) String() string {
+	if r.Namespace != "" {
+		return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+	}
+	return fmt.Sprintf("%s %s", r.Kind, r.Name)
+}
+
+// ReadDir returns the Roles and ClusterRoles in the YAML files in dir.
+func ReadDir(dir string) (roles []Role, err error) {
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return err
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		scanner := k8sutil.NewYAMLScanner(bytes.NewBuffer(b))
+		for scanner.Scan() {
+			manifest := scanner.Bytes()
+			typeMeta, err := k8sutil.GetTypeMetaFromBytes(manifest)
+			if err != nil || typeMeta.GroupVersionKind().Group != rbacv1.GroupName {
+				continue
+			}
+			switch typeMeta.Kind {
+			case "Role":
+				role := rbacv1.Role{}
+				if err := yaml.Unmarshal(manifest, &role); err != nil {
+					return fmt.Errorf("error unmarshalling Role in %s: %v", path, err)
+				}
+				roles = append(roles, Role{Kind: "Role", Namespace: role.GetNamespace(), Name: role.GetName(), Rules: role.Rules})
+			case "ClusterRole":
+				role := rbacv1.ClusterRole{}
+				if err := yaml.Unmarshal(manifest, &role); err != nil {
+					return fmt.Errorf("error unmarshalling ClusterRole in %s: %v", path, err)
+				}
+				roles = append(roles, Role{Kind: "ClusterRole", Name: role.GetName(), Rules: role.Rules})
+			}
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading roles from %s: %v", dir, err)
+	}
+	return roles, nil
+}
+
+// Permission is a single verb granted on a resource or non-resource URL.
+type Permission struct {
+	APIGroup       string
+	Resource       string
+	ResourceName   string
+	NonResourceURL string
+	Verb           string
+}
+
+// String returns p in the form "<verb> <resource>[.<group>][/<name>]" or "<verb> <url>".
+func (p Permission) String() string {
+	if p.NonResourceURL != "" {
+		return fmt.Sprintf("%s %s", p.Verb, p.NonResourceURL)
+	}
+	s := p.Resource
+	if p.APIGroup != "" {
+		s += "." + p.APIGroup
+	}
+	if p.ResourceName != "" {
+		s += "/" + p.ResourceName
+	}
+	return fmt.Sprintf("%s %s", p.Verb, s)
+}
+
+// covers returns true if p grants o, accounting for wildcards.
+func (p Permission) covers(o Permission) bool {
+	if !matches(p.Verb, o.Verb) {
+		return false
+	}
+	if p.NonResourceURL != "" || o.NonResourceURL != "" {
+		if strings.HasSuffix(p.NonResourceURL, "*") {
+			return strings.HasPrefix(o.NonResourceURL, strings.TrimSuffix(p.NonResourceURL, "*"))
+		}
+		return p.NonResourceURL == o.NonResourceURL
+	}
+	return matches(p.APIGroup, o.APIGroup) && matches(p.Resource, o.Resource) &&
+		(p.ResourceName == "" || p.ResourceName == o.ResourceName)
+}
+
+// matches returns true if pattern is a wildcard or equal to s.
+func matches(pattern, s string) bool {
+	return pattern == "*" || pattern == s
+}
+
+// Expand returns each Permission granted by rules.
+func Expand(rules []rbacv1.PolicyRule) (perms []Permission) {
+	for _, rule := range rules {
+		for _, verb := range rule.Verbs {
+			for _, url := range rule.NonResourceURLs {
+				perms = append(perms, Permission{NonResourceURL: url, Verb: verb})
+			}
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					if len(rule.ResourceNames) == 0 {
+						perms = append(perms, Permission{APIGroup: group, Resource: resource, Verb: verb})
+						continue
+					}
+					for _, name := range rule.ResourceNames {
+						perms = append(perms, Permission{APIGroup: group, Resource: resource, ResourceName: name, Verb: verb})
+					}
+				}
+			}
+		}
+	}
+	return perms
+}
+
+// Diff is the difference between a project's rules and deployed rules.
+type Diff struct {
+	// Missing are granted by the project's rules but not by the deployed rules.
+	Missing []Permission
+	// Excess are granted by the deployed rules but not by the project's rules.
+	Excess []Permission
+}
+
+// IsEmpty returns true if d has no missing or excess permissions.
+func (d Diff) IsEmpty() bool {
+	return len(d.Missing) == 0 && len(d.Excess) == 0
+}
+
+// Compare returns the Diff between project and deployed rules.
+func Compare(project, deployed []rbacv1.PolicyRule) Diff {
+	projectPerms, deployedPerms := Expand(project), Expand(deployed)
+	return Diff{
+		Missing: uncovered(projectPerms, deployedPerms),
+		Excess:  uncovered(deployedPerms, projectPerms),
+	}
+}
+
+// uncovered returns the unique, sorted Permissions in perms not covered by any of by.
+func uncovered(perms, by []Permission) (out []Permission) {
+	seen := map[Permission]struct{}{}
+	for _, p := range perms {
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		covered := false
+		for _, b := range by {
+			if b.covers(p) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out
+}
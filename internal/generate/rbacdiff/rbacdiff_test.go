@@ -0,0 +1,121 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbacdiff
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestRBACDiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RBAC Diff Suite")
+}
+
+var _ = Describe("Compare", func() {
+	deployments := rbacv1.PolicyRule{
+		APIGroups: []string{"apps"},
+		Resources: []string{"deployments"},
+		Verbs:     []string{"get", "list"},
+	}
+
+	It("returns an empty diff for equal rules", func() {
+		Expect(Compare([]rbacv1.PolicyRule{deployments}, []rbacv1.PolicyRule{deployments}).IsEmpty()).To(BeTrue())
+	})
+
+	It("reports missing and excess permissions", func() {
+		deployed := []rbacv1.PolicyRule{
+			{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}},
+			{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+		}
+		diff := Compare([]rbacv1.PolicyRule{deployments}, deployed)
+		Expect(diff.Missing).To(Equal([]Permission{{APIGroup: "apps", Resource: "deployments", Verb: "list"}}))
+		Expect(diff.Excess).To(Equal([]Permission{{Resource: "secrets", Verb: "get"}}))
+		Expect(diff.Missing[0].String()).To(Equal("list deployments.apps"))
+	})
+
+	It("treats permissions granted by wildcards as covered", func() {
+		deployed := []rbacv1.PolicyRule{
+			{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+			{NonResourceURLs: []string{"/metrics*"}, Verbs: []string{"get"}},
+		}
+		project := []rbacv1.PolicyRule{
+			deployments,
+			{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}},
+		}
+		diff := Compare(project, deployed)
+		Expect(diff.Missing).To(BeEmpty())
+		Expect(diff.Excess).To(HaveLen(2))
+	})
+
+	It("treats rules without resource names as covering named resources", func() {
+		named := rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"configmaps"},
+			ResourceNames: []string{"lock"},
+			Verbs:         []string{"update"},
+		}
+		unnamed := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"update"}}
+		diff := Compare([]rbacv1.PolicyRule{named}, []rbacv1.PolicyRule{unnamed})
+		Expect(diff.Missing).To(BeEmpty())
+		Expect(diff.Excess).To(Equal([]Permission{{Resource: "configmaps", Verb: "update"}}))
+	})
+})
+
+var _ = Describe("ReadDir", func() {
+	It("reads Roles and ClusterRoles", func() {
+		dir, err := ioutil.TempDir("", "rbacdiff")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		manifests := `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: manager-role
+rules:
+- apiGroups: ["apps"]
+  resources: ["deployments"]
+  verbs: ["get"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: leader-election-role
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["get"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: leader-election-rolebinding
+`
+		Expect(ioutil.WriteFile(filepath.Join(dir, "roles.yaml"), []byte(manifests), 0644)).To(Succeed())
+
+		roles, err := ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(roles).To(HaveLen(2))
+		Expect(roles[0].String()).To(Equal("ClusterRole manager-role"))
+		Expect(roles[1].String()).To(Equal("Role leader-election-role"))
+		Expect(roles[1].Rules).To(HaveLen(1))
+	})
+})
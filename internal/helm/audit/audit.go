@@ -0,0 +1,152 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records the manifest changes made by Helm release actions
+// to a size-rotated file of JSON lines.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Action is a Helm release action recorded in the audit log.
+type Action string
+
+const (
+	ActionInstall   Action = "install"
+	ActionUpgrade   Action = "upgrade"
+	ActionUninstall Action = "uninstall"
+)
+
+// Entry is a single audit log record.
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Action     Action    `json:"action"`
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Release    string    `json:"release"`
+	Revision   int       `json:"revision"`
+	// Diff is the uncolored diff between the release's previous and new manifests.
+	Diff string `json:"diff"`
+}
+
+// Logger writes Entries to a file as JSON lines. Once the file reaches
+// its maximum size, it is rotated to <file>.1, <file>.1 to <file>.2,
+// and so on, keeping at most a configured number of backups.
+// Logger is safe for concurrent use.
+type Logger struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewLogger returns a Logger that appends to the file at path, creating it
+// and its parent directories if necessary. If maxSize is greater than zero,
+// the file is rotated before a write would exceed maxSize bytes.
+func NewLogger(path string, maxSize int64, maxBackups int) (*Logger, error) {
+	if maxBackups < 0 {
+		return nil, fmt.Errorf("max backups must not be negative: %d", maxBackups)
+	}
+	l := &Logger{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Log writes e to the audit file. If e.Time is zero, it is set to the current time.
+func (l *Logger) Log(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(b)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("error rotating audit log: %v", err)
+		}
+	}
+	n, err := l.file.Write(b)
+	l.size += int64(n)
+	return err
+}
+
+// Close closes the audit file.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+func (l *Logger) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// rotate shifts the audit file and its backups, dropping the oldest backup,
+// then opens a new audit file.
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	if l.maxBackups == 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return l.open()
+	}
+	for i := l.maxBackups - 1; i > 0; i-- {
+		if err := os.Rename(l.backupPath(i), l.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, l.backupPath(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return l.open()
+}
+
+func (l *Logger) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "logs", "audit.log")
+	l, err := NewLogger(path, 0, 0)
+	require.NoError(t, err)
+
+	entry := Entry{
+		Action:     ActionInstall,
+		APIVersion: "example.com/v1alpha1",
+		Kind:       "Nginx",
+		Namespace:  "default",
+		Name:       "test",
+		Release:    "test",
+		Revision:   1,
+		Diff:       "+kind: Service\n",
+	}
+	require.NoError(t, l.Log(entry))
+	require.NoError(t, l.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	require.True(t, scanner.Scan())
+	got := Entry{}
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &got))
+	assert.False(t, got.Time.IsZero())
+	got.Time = entry.Time
+	assert.Equal(t, entry, got)
+	assert.False(t, scanner.Scan())
+}
+
+func TestLoggerRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit.log")
+	l, err := NewLogger(path, 1, 2)
+	require.NoError(t, err)
+	defer l.Close()
+
+	// Each entry exceeds the maximum size, so every write after the first rotates.
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, l.Log(Entry{Action: ActionUpgrade, Revision: i}))
+	}
+
+	for path, expRevision := range map[string]int{path: 4, path + ".1": 3, path + ".2": 2} {
+		b, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		got := Entry{}
+		require.NoError(t, json.Unmarshal(b, &got))
+		assert.Equal(t, expRevision, got.Revision, path)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache provides a controller-runtime cache for operators that watch
+// a set of namespaces.
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
+)
+
+// MultiNamespacedCacheBuilder returns a function that creates a cache which
+// stores namespace-scoped objects only from namespaces, and cluster-scoped
+// objects from a single cluster-wide cache.
+//
+// Unlike cache.MultiNamespacedCacheBuilder, the returned cache can get
+// cluster-scoped objects, such as cluster-scoped custom resources and their
+// dependents, and watches each cluster-scoped kind only once.
+func MultiNamespacedCacheBuilder(namespaces []string) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		if opts.Scheme == nil {
+			opts.Scheme = scheme.Scheme
+		}
+		if opts.Mapper == nil {
+			var err error
+			opts.Mapper, err = apiutil.NewDynamicRESTMapper(config)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		namespaced, err := cache.MultiNamespacedCacheBuilder(namespaces)(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		opts.Namespace = ""
+		clusterScoped, err := cache.New(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &multiNamespaceCache{
+			namespaced:    namespaced,
+			clusterScoped: clusterScoped,
+			scheme:        opts.Scheme,
+			mapper:        opts.Mapper,
+		}, nil
+	}
+}
+
+// multiNamespaceCache delegates requests for namespace-scoped kinds to
+// namespaced and requests for cluster-scoped kinds to clusterScoped.
+type multiNamespaceCache struct {
+	namespaced    cache.Cache
+	clusterScoped cache.Cache
+	scheme        *runtime.Scheme
+	mapper        meta.RESTMapper
+}
+
+var _ cache.Cache = &multiNamespaceCache{}
+
+func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	delegate, err := c.cacheForObject(obj)
+	if err != nil {
+		return err
+	}
+	return delegate.Get(ctx, key, obj)
+}
+
+func (c *multiNamespaceCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	delegate, err := c.cacheForKind(gvk)
+	if err != nil {
+		return err
+	}
+	return delegate.List(ctx, list, opts...)
+}
+
+func (c *multiNamespaceCache) GetInformer(ctx context.Context, obj runtime.Object) (cache.Informer, error) {
+	delegate, err := c.cacheForObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	return delegate.GetInformer(ctx, obj)
+}
+
+func (c *multiNamespaceCache) GetInformerForKind(ctx context.Context,
+	gvk schema.GroupVersionKind) (cache.Informer, error) {
+	delegate, err := c.cacheForKind(gvk)
+	if err != nil {
+		return nil, err
+	}
+	return delegate.GetInformerForKind(ctx, gvk)
+}
+
+func (c *multiNamespaceCache) IndexField(ctx context.Context, obj runtime.Object, field string,
+	extractValue client.IndexerFunc) error {
+	delegate, err := c.cacheForObject(obj)
+	if err != nil {
+		return err
+	}
+	return delegate.IndexField(ctx, obj, field, extractValue)
+}
+
+// Start runs both caches until stop is closed.
+func (c *multiNamespaceCache) Start(stop <-chan struct{}) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.clusterScoped.Start(stop)
+	}()
+	if err := c.namespaced.Start(stop); err != nil {
+		return err
+	}
+	return <-errCh
+}
+
+func (c *multiNamespaceCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	return c.clusterScoped.WaitForCacheSync(stop) && c.namespaced.WaitForCacheSync(stop)
+}
+
+func (c *multiNamespaceCache) cacheForObject(obj runtime.Object) (cache.Cache, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, err
+	}
+	return c.cacheForKind(gvk)
+}
+
+func (c *multiNamespaceCache) cacheForKind(gvk schema.GroupVersionKind) (cache.Cache, error) {
+	clusterScoped, err := k8sutil.IsClusterScoped(c.mapper, gvk)
+	if err != nil {
+		return nil, err
+	}
+	if clusterScoped {
+		return c.clusterScoped, nil
+	}
+	return c.namespaced, nil
+}
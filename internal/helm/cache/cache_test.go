@@ -0,0 +1,91 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// namedCache is a cache.Cache that is distinguishable by name.
+type namedCache struct {
+	cache.Cache
+	name string
+}
+
+func TestMultiNamespaceCacheRouting(t *testing.T) {
+	namespacedKind := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Nginx"}
+	clusterKind := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "ClusterNginx"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(namespacedKind, meta.RESTScopeNamespace)
+	mapper.Add(clusterKind, meta.RESTScopeRoot)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+
+	c := &multiNamespaceCache{
+		namespaced:    &namedCache{name: "namespaced"},
+		clusterScoped: &namedCache{name: "clusterScoped"},
+		scheme:        scheme.Scheme,
+		mapper:        mapper,
+	}
+
+	newUnstructured := func(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		return u
+	}
+
+	testCases := []struct {
+		name     string
+		gvk      schema.GroupVersionKind
+		expected string
+	}{
+		{"namespaced custom resource", namespacedKind, "namespaced"},
+		{"cluster-scoped custom resource", clusterKind, "clusterScoped"},
+		{"namespaced core kind", corev1.SchemeGroupVersion.WithKind("ConfigMap"), "namespaced"},
+		{"cluster-scoped core kind", corev1.SchemeGroupVersion.WithKind("Namespace"), "clusterScoped"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delegate, err := c.cacheForKind(tc.gvk)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, delegate.(*namedCache).name)
+
+			delegate, err = c.cacheForObject(newUnstructured(tc.gvk))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, delegate.(*namedCache).name)
+		})
+	}
+
+	t.Run("typed object", func(t *testing.T) {
+		delegate, err := c.cacheForObject(&corev1.Namespace{})
+		require.NoError(t, err)
+		assert.Equal(t, "clusterScoped", delegate.(*namedCache).name)
+	})
+
+	t.Run("unmapped kind", func(t *testing.T) {
+		_, err := c.cacheForKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"})
+		assert.Error(t, err)
+	})
+}
@@ -83,13 +83,17 @@ func (c namespaceClientConfig) ConfigAccess() clientcmd.ConfigAccess {
 }
 
 func NewRESTClientGetter(mgr manager.Manager, ns string) (genericclioptions.RESTClientGetter, error) {
-	cfg := mgr.GetConfig()
+	return NewRESTClientGetterForConfig(mgr.GetConfig(), mgr.GetRESTMapper(), ns)
+}
+
+// NewRESTClientGetterForConfig returns a RESTClientGetter that uses cfg and rm
+// for requests in namespace ns.
+func NewRESTClientGetterForConfig(cfg *rest.Config, rm meta.RESTMapper, ns string) (genericclioptions.RESTClientGetter, error) {
 	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
 	if err != nil {
 		return nil, err
 	}
 	cdc := cached.NewMemCacheClient(dc)
-	rm := mgr.GetRESTMapper()
 
 	return &restClientGetter{
 		restConfig:      cfg,
@@ -130,6 +134,12 @@ func (c *ownerRefInjectingClient) Build(reader io.Reader, validate bool) (kube.R
 	if err != nil {
 		return resourceList, err
 	}
+	// Dependents of cluster-scoped owners are tracked with annotations, so
+	// that they are watched the same way whether or not they are namespaced.
+	ownerClusterScoped, err := k8sutil.IsClusterScoped(c.restMapper, c.owner.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
 	err = resourceList.Visit(func(r *resource.Info, err error) error {
 		if err != nil {
 			return err
@@ -139,9 +149,12 @@ func (c *ownerRefInjectingClient) Build(reader io.Reader, validate bool) (kube.R
 			return err
 		}
 		u := &unstructured.Unstructured{Object: objMap}
-		useOwnerRef, err := k8sutil.SupportsOwnerReference(c.restMapper, c.owner, u)
-		if err != nil {
-			return err
+		useOwnerRef := false
+		if !ownerClusterScoped {
+			useOwnerRef, err = k8sutil.SupportsOwnerReference(c.restMapper, c.owner, u)
+			if err != nil {
+				return err
+			}
 		}
 
 		if useOwnerRef {
@@ -0,0 +1,162 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/operator-framework/operator-sdk/internal/helm/metrics"
+)
+
+// ThrottleBackoff delays every request sent through the transports it wraps
+// after the API server throttles any one of them with a 429 Too Many Requests
+// response, such as when API Priority and Fairness rejects a request. Client
+// retries of a throttled request only slow down that request; sharing a
+// ThrottleBackoff between all release clients slows down the whole operator
+// until the API server accepts requests again.
+//
+// Each consecutive throttled response doubles the backoff, starting from
+// base, up to max. Backoffs are jittered, so that requests waiting for the
+// same backoff do not all resume at once, and are never shorter than the
+// response's Retry-After header. A response that is not throttled resets the
+// backoff.
+type ThrottleBackoff struct {
+	base time.Duration
+	max  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	until    time.Time
+
+	now    func() time.Time
+	jitter func(time.Duration) time.Duration
+}
+
+// NewThrottleBackoff returns a ThrottleBackoff with backoffs from base to max.
+func NewThrottleBackoff(base, max time.Duration) *ThrottleBackoff {
+	return &ThrottleBackoff{
+		base:   base,
+		max:    max,
+		now:    time.Now,
+		jitter: equalJitter,
+	}
+}
+
+// WrapTransport returns a transport that sends requests with rt, delaying
+// them while b is backing off. It can be used as a rest.Config's
+// WrapTransport.
+func (b *ThrottleBackoff) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &throttlingTransport{rt: rt, backoff: b}
+}
+
+type throttlingTransport struct {
+	rt      http.RoundTripper
+	backoff *ThrottleBackoff
+}
+
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.backoff.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		t.backoff.throttled(retryAfter(resp))
+	} else {
+		t.backoff.reset()
+	}
+	return resp, nil
+}
+
+// wait blocks until b's backoff has elapsed or ctx is done.
+func (b *ThrottleBackoff) wait(ctx context.Context) error {
+	b.mu.Lock()
+	delay := b.until.Sub(b.now())
+	b.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttled extends b's backoff after a throttled response whose Retry-After
+// header is retryAfter, and returns the new backoff.
+func (b *ThrottleBackoff) throttled(retryAfter time.Duration) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delay := b.max
+	if b.failures < 32 && b.base<<uint(b.failures) < b.max {
+		delay = b.base << uint(b.failures)
+	}
+	delay = b.jitter(delay)
+	if delay < retryAfter {
+		delay = retryAfter
+	}
+	if delay > b.max {
+		delay = b.max
+	}
+	b.failures++
+
+	if until := b.now().Add(delay); until.After(b.until) {
+		b.until = until
+	}
+	metrics.APIThrottled(delay)
+	return delay
+}
+
+// reset resets b's backoff after a response that was not throttled. Requests
+// already waiting for the backoff are not resumed early.
+func (b *ThrottleBackoff) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures == 0 {
+		return
+	}
+	b.failures = 0
+	metrics.APIThrottleReset()
+}
+
+// equalJitter returns a random duration between d/2 and d.
+func equalJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter returns the delay in resp's Retry-After header, or 0 if it has
+// none. The API server sends the delay in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
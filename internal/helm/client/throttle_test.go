@@ -0,0 +1,113 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestThrottleBackoff(base, max time.Duration) (*ThrottleBackoff, *time.Time) {
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	b := NewThrottleBackoff(base, max)
+	b.now = func() time.Time { return now }
+	b.jitter = func(d time.Duration) time.Duration { return d }
+	return b, &now
+}
+
+func TestThrottleBackoffThrottled(t *testing.T) {
+	b, now := newTestThrottleBackoff(time.Second, 10*time.Second)
+
+	assert.Equal(t, time.Second, b.throttled(0))
+	assert.Equal(t, 2*time.Second, b.throttled(0))
+	assert.Equal(t, 4*time.Second, b.throttled(0))
+	assert.Equal(t, now.Add(4*time.Second), b.until)
+	assert.Equal(t, 8*time.Second, b.throttled(0))
+	assert.Equal(t, 10*time.Second, b.throttled(0), "backoff must not exceed max")
+
+	b.reset()
+	assert.Equal(t, time.Second, b.throttled(0))
+	assert.Equal(t, now.Add(10*time.Second), b.until, "backoff must not be shortened")
+
+	b.reset()
+	assert.Equal(t, 5*time.Second, b.throttled(5*time.Second), "backoff must not be shorter than Retry-After")
+	assert.Equal(t, 10*time.Second, b.throttled(time.Minute), "Retry-After must not exceed max")
+}
+
+func TestThrottleBackoffWait(t *testing.T) {
+	b := NewThrottleBackoff(time.Hour, time.Hour)
+	assert.NoError(t, b.wait(context.TODO()))
+
+	b.throttled(0)
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.wait(ctx))
+}
+
+func TestThrottlingTransport(t *testing.T) {
+	throttle := int32(1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&throttle) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	b := NewThrottleBackoff(20*time.Millisecond, 50*time.Millisecond)
+	c := &http.Client{Transport: b.WrapTransport(http.DefaultTransport)}
+
+	resp, err := c.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 1, b.failures)
+
+	atomic.StoreInt32(&throttle, 0)
+	start := time.Now()
+	resp, err = c.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, time.Since(start) >= 40*time.Millisecond, "request must wait for the backoff")
+	assert.Equal(t, 0, b.failures)
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	assert.Equal(t, time.Duration(0), retryAfter(resp))
+
+	resp.Header.Set("Retry-After", "3")
+	assert.Equal(t, 3*time.Second, retryAfter(resp))
+
+	resp.Header.Set("Retry-After", "Wed, 21 Oct 2015 07:28:00 GMT")
+	assert.Equal(t, time.Duration(0), retryAfter(resp))
+}
+
+func TestEqualJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := equalJitter(time.Second)
+		assert.True(t, d >= 500*time.Millisecond && d <= time.Second, "jittered backoff %s out of range", d)
+	}
+}
@@ -15,7 +15,9 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -23,18 +25,25 @@ import (
 	"github.com/operator-framework/operator-lib/handler"
 	rpb "helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	crthandler "sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	crtpredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sigs.k8s.io/yaml"
 
 	libhandler "github.com/operator-framework/operator-lib/handler"
 	"github.com/operator-framework/operator-lib/predicate"
+	"github.com/operator-framework/operator-sdk/internal/helm/audit"
+	"github.com/operator-framework/operator-sdk/internal/helm/metrics"
 	"github.com/operator-framework/operator-sdk/internal/helm/release"
 	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
 )
@@ -44,26 +53,58 @@ var log = logf.Log.WithName("helm.controller")
 // WatchOptions contains the necessary values to create a new controller that
 // manages helm releases in a particular namespace based on a GVK watch.
 type WatchOptions struct {
-	Namespace               string
+	// Namespaces are the namespaces watched by the manager's cache. If empty,
+	// all namespaces are watched.
+	Namespaces              []string
 	GVK                     schema.GroupVersionKind
 	ManagerFactory          release.ManagerFactory
 	ReconcilePeriod         time.Duration
 	WatchDependentResources bool
 	OverrideValues          map[string]string
 	MaxConcurrentReconciles int
+	InstallTimeout          time.Duration
+	UpgradeTimeout          time.Duration
+	AuditLogger             *audit.Logger
+	ValidateValuesSchema    bool
+	RollbackOnFailure       bool
+	// IncludeDependentKinds, if not empty, are the only kinds of dependent
+	// resources watched. ExcludeDependentKinds are never watched.
+	IncludeDependentKinds []schema.GroupKind
+	ExcludeDependentKinds []schema.GroupKind
+	OperandNamespace      *OperandNamespace
+	// SubReleases are additional charts installed as separate releases for
+	// each custom resource.
+	SubReleases []SubRelease
+	// StandardConditions, if true, makes status conditions follow the
+	// Kubernetes API conventions of metav1.Condition.
+	StandardConditions bool
+	// ControllerName is the name of the controller, which labels its
+	// controller-runtime metrics. If empty, it is derived from GVK's kind.
+	ControllerName string
 }
 
 // Add creates a new helm operator controller and adds it to the manager
 func Add(mgr manager.Manager, options WatchOptions) error {
-	controllerName := fmt.Sprintf("%v-controller", strings.ToLower(options.GVK.Kind))
+	controllerName := options.ControllerName
+	if controllerName == "" {
+		controllerName = fmt.Sprintf("%v-controller", strings.ToLower(options.GVK.Kind))
+	}
 
 	r := &HelmOperatorReconciler{
-		Client:          mgr.GetClient(),
-		EventRecorder:   mgr.GetEventRecorderFor(controllerName),
-		GVK:             options.GVK,
-		ManagerFactory:  options.ManagerFactory,
-		ReconcilePeriod: options.ReconcilePeriod,
-		OverrideValues:  options.OverrideValues,
+		Client:               mgr.GetClient(),
+		EventRecorder:        mgr.GetEventRecorderFor(controllerName),
+		GVK:                  options.GVK,
+		ManagerFactory:       options.ManagerFactory,
+		ReconcilePeriod:      options.ReconcilePeriod,
+		OverrideValues:       options.OverrideValues,
+		InstallTimeout:       options.InstallTimeout,
+		UpgradeTimeout:       options.UpgradeTimeout,
+		AuditLogger:          options.AuditLogger,
+		ValidateValuesSchema: options.ValidateValuesSchema,
+		RollbackOnFailure:    options.RollbackOnFailure,
+		OperandNamespace:     options.OperandNamespace,
+		SubReleases:          options.SubReleases,
+		StandardConditions:   options.StandardConditions,
 	}
 
 	// Register the GVK with the schema
@@ -80,28 +121,80 @@ func Add(mgr manager.Manager, options WatchOptions) error {
 
 	o := &unstructured.Unstructured{}
 	o.SetGroupVersionKind(options.GVK)
-	if err := c.Watch(&source.Kind{Type: o}, &handler.InstrumentedEnqueueRequestForObject{}); err != nil {
+	if err := c.Watch(&source.Kind{Type: o}, &handler.InstrumentedEnqueueRequestForObject{},
+		ignoreStatusUpdates); err != nil {
+		return err
+	}
+
+	cs, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
 		return err
 	}
+	r.missingObjects = newMissingObjectWatcher(c, cs.AuthorizationV1().SelfSubjectAccessReviews(), options.Namespaces)
 
 	if options.WatchDependentResources {
-		watchDependentResources(mgr, r, c)
+		watchDependentResources(mgr, r, c, options.IncludeDependentKinds, options.ExcludeDependentKinds)
 	}
 
+	metrics.ControllerAdded(options.GVK.String(), controllerName, options.MaxConcurrentReconciles)
+
 	log.Info("Watching resource", "apiVersion", options.GVK.GroupVersion(), "kind",
-		options.GVK.Kind, "namespace", options.Namespace, "reconcilePeriod", options.ReconcilePeriod.String())
+		options.GVK.Kind, "namespaces", options.Namespaces, "reconcilePeriod", options.ReconcilePeriod.String())
 	return nil
 }
 
+// ControllerNames returns a unique controller name for each of gvks. A name is
+// derived from the kind alone, as in "memcached-controller", unless another of
+// gvks has the same kind, in which case the version and group are included, as
+// in "memcached.v1alpha1.cache.example.com-controller", so that the
+// controller-runtime metrics of each GVK are kept separate.
+func ControllerNames(gvks []schema.GroupVersionKind) map[schema.GroupVersionKind]string {
+	kinds := map[string]int{}
+	for _, gvk := range gvks {
+		kinds[strings.ToLower(gvk.Kind)]++
+	}
+	names := make(map[schema.GroupVersionKind]string, len(gvks))
+	for _, gvk := range gvks {
+		kind := strings.ToLower(gvk.Kind)
+		if kinds[kind] > 1 {
+			names[gvk] = fmt.Sprintf("%v.%v.%v-controller", kind, gvk.Version, gvk.Group)
+			continue
+		}
+		names[gvk] = fmt.Sprintf("%v-controller", kind)
+	}
+	return names
+}
+
+// ignoreStatusUpdates filters out updates to a custom resource that only
+// change its status. The reconciler records the time of every reconcile in the
+// status, so without this filter each reconcile would trigger another.
+var ignoreStatusUpdates = crtpredicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration() ||
+			!reflect.DeepEqual(e.MetaOld.GetLabels(), e.MetaNew.GetLabels()) ||
+			!reflect.DeepEqual(e.MetaOld.GetAnnotations(), e.MetaNew.GetAnnotations()) ||
+			!reflect.DeepEqual(e.MetaOld.GetFinalizers(), e.MetaNew.GetFinalizers()) ||
+			!reflect.DeepEqual(e.MetaOld.GetOwnerReferences(), e.MetaNew.GetOwnerReferences()) ||
+			!reflect.DeepEqual(e.MetaOld.GetDeletionTimestamp(), e.MetaNew.GetDeletionTimestamp())
+	},
+}
+
 // watchDependentResources adds a release hook function to the HelmOperatorReconciler
-// that adds watches for resources in released Helm charts.
-func watchDependentResources(mgr manager.Manager, r *HelmOperatorReconciler, c controller.Controller) {
+// that adds watches for resources in released Helm charts whose kinds are
+// selected by include and exclude.
+func watchDependentResources(mgr manager.Manager, r *HelmOperatorReconciler, c controller.Controller,
+	include, exclude []schema.GroupKind) {
 	owner := &unstructured.Unstructured{}
 	owner.SetGroupVersionKind(r.GVK)
 
 	var m sync.RWMutex
 	watches := map[schema.GroupVersionKind]struct{}{}
 	releaseHook := func(release *rpb.Release) error {
+		restMapper := mgr.GetRESTMapper()
+		ownerClusterScoped, err := k8sutil.IsClusterScoped(restMapper, r.GVK)
+		if err != nil {
+			return err
+		}
 		resources := releaseutil.SplitManifests(release.Manifest)
 		for _, resource := range resources {
 			var u unstructured.Unstructured
@@ -110,7 +203,7 @@ func watchDependentResources(mgr manager.Manager, r *HelmOperatorReconciler, c c
 			}
 
 			gvk := u.GroupVersionKind()
-			if gvk.Empty() {
+			if gvk.Empty() || !isSelectedKind(gvk.GroupKind(), include, exclude) {
 				continue
 			}
 			m.RLock()
@@ -120,12 +213,22 @@ func watchDependentResources(mgr manager.Manager, r *HelmOperatorReconciler, c c
 				continue
 			}
 
-			restMapper := mgr.GetRESTMapper()
-			useOwnerRef, err := k8sutil.SupportsOwnerReference(restMapper, owner, &u)
-			if err != nil {
+			// The kinds of resources defined by CRDs in the chart's crds/
+			// directory may not be mapped until discovery is refreshed.
+			if err := waitForRESTMapping(restMapper, gvk); err != nil {
 				return err
 			}
 
+			// Dependents of cluster-scoped owners are always annotated by the
+			// release manager's client rather than given owner references.
+			useOwnerRef := false
+			if !ownerClusterScoped {
+				useOwnerRef, err = k8sutil.SupportsOwnerReference(restMapper, owner, &u)
+				if err != nil {
+					return err
+				}
+			}
+
 			if useOwnerRef { // Setup watch using owner references.
 				err = c.Watch(&source.Kind{Type: &u}, &crthandler.EnqueueRequestForOwner{OwnerType: owner},
 					predicate.DependentPredicate{})
@@ -133,7 +236,7 @@ func watchDependentResources(mgr manager.Manager, r *HelmOperatorReconciler, c c
 					return err
 				}
 			} else { // Setup watch using annotations.
-				err = c.Watch(&source.Kind{Type: &u}, &libhandler.EnqueueRequestForAnnotation{Type: gvk.GroupKind()},
+				err = c.Watch(&source.Kind{Type: &u}, &libhandler.EnqueueRequestForAnnotation{Type: r.GVK.GroupKind()},
 					predicate.DependentPredicate{})
 				if err != nil {
 					return err
@@ -149,3 +252,49 @@ func watchDependentResources(mgr manager.Manager, r *HelmOperatorReconciler, c c
 	}
 	r.releaseHook = releaseHook
 }
+
+// restMappingBackoff is the backoff used by waitForRESTMapping.
+var restMappingBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Steps:    5,
+}
+
+// waitForRESTMapping waits, with backoff, until restMapper maps gvk, resetting
+// restMapper's cached mappings between attempts if it supports it.
+func waitForRESTMapping(restMapper meta.RESTMapper, gvk schema.GroupVersionKind) error {
+	var mappingErr error
+	err := wait.ExponentialBackoff(restMappingBackoff, func() (bool, error) {
+		_, mappingErr = restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(mappingErr) {
+			if r, ok := restMapper.(meta.ResettableRESTMapper); ok {
+				r.Reset()
+			}
+			return false, nil
+		}
+		return mappingErr == nil, mappingErr
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return fmt.Errorf("timed out waiting for %s to be mapped: %w", gvk, mappingErr)
+	}
+	return err
+}
+
+// isSelectedKind returns true if gk is not in exclude and, if include is not
+// empty, is in include.
+func isSelectedKind(gk schema.GroupKind, include, exclude []schema.GroupKind) bool {
+	for _, e := range exclude {
+		if gk == e {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, i := range include {
+		if gk == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestIsSelectedKind(t *testing.T) {
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	endpoints := schema.GroupKind{Kind: "Endpoints"}
+	service := schema.GroupKind{Kind: "Service"}
+
+	assert.True(t, isSelectedKind(endpoints, nil, nil))
+
+	exclude := []schema.GroupKind{endpoints}
+	assert.False(t, isSelectedKind(endpoints, nil, exclude))
+	assert.True(t, isSelectedKind(deployment, nil, exclude))
+
+	include := []schema.GroupKind{deployment, endpoints}
+	assert.True(t, isSelectedKind(deployment, include, nil))
+	assert.False(t, isSelectedKind(service, include, nil))
+	assert.False(t, isSelectedKind(endpoints, include, exclude))
+	assert.False(t, isSelectedKind(schema.GroupKind{Group: "extensions", Kind: "Deployment"}, include, nil))
+}
+
+func TestControllerNames(t *testing.T) {
+	memcached := schema.GroupVersionKind{Group: "cache.example.com", Version: "v1alpha1", Kind: "Memcached"}
+	memcachedV2 := schema.GroupVersionKind{Group: "cache.example.com", Version: "v1alpha2", Kind: "Memcached"}
+	nginx := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Nginx"}
+
+	assert.Equal(t, map[schema.GroupVersionKind]string{
+		memcached:   "memcached.v1alpha1.cache.example.com-controller",
+		memcachedV2: "memcached.v1alpha2.cache.example.com-controller",
+		nginx:       "nginx-controller",
+	}, ControllerNames([]schema.GroupVersionKind{memcached, memcachedV2, nginx}))
+}
+
+// resettableRESTMapper maps kinds added to it only after it is reset, like a
+// mapper with stale cached discovery information.
+type resettableRESTMapper struct {
+	*meta.DefaultRESTMapper
+	pending []schema.GroupVersionKind
+	resets  int
+}
+
+func (m *resettableRESTMapper) Reset() {
+	m.resets++
+	for _, gvk := range m.pending {
+		m.Add(gvk, meta.RESTScopeNamespace)
+	}
+	m.pending = nil
+}
+
+func TestWaitForRESTMapping(t *testing.T) {
+	defer func(b wait.Backoff) { restMappingBackoff = b }(restMappingBackoff)
+	restMappingBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+
+	memcached := schema.GroupVersionKind{Group: "cache.example.com", Version: "v1alpha1", Kind: "Memcached"}
+	m := &resettableRESTMapper{
+		DefaultRESTMapper: meta.NewDefaultRESTMapper(nil),
+		pending:           []schema.GroupVersionKind{memcached},
+	}
+	assert.NoError(t, waitForRESTMapping(m, memcached))
+	assert.Equal(t, 1, m.resets)
+
+	unknown := schema.GroupVersionKind{Group: "cache.example.com", Version: "v1alpha1", Kind: "Unknown"}
+	assert.Error(t, waitForRESTMapping(m, unknown))
+}
+
+func TestIgnoreStatusUpdates(t *testing.T) {
+	newObj := func(generation int64, annotations map[string]string, status string) *unstructured.Unstructured {
+		o := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"phase": status},
+		}}
+		o.SetGeneration(generation)
+		o.SetAnnotations(annotations)
+		return o
+	}
+	update := func(oldObj, newObj *unstructured.Unstructured) bool {
+		return ignoreStatusUpdates.Update(event.UpdateEvent{
+			MetaOld: oldObj, ObjectOld: oldObj,
+			MetaNew: newObj, ObjectNew: newObj,
+		})
+	}
+
+	old := newObj(1, nil, "a")
+	assert.False(t, update(old, newObj(1, nil, "b")))
+	assert.True(t, update(old, newObj(2, nil, "a")))
+	assert.True(t, update(old, newObj(1, map[string]string{"foo": "bar"}, "a")))
+}
@@ -0,0 +1,334 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	crthandler "sigs.k8s.io/controller-runtime/pkg/handler"
+	crtpredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/operator-framework/operator-sdk/internal/util/rbacutil"
+)
+
+// notFoundPattern matches the API server's message for a missing object,
+// capturing the resource, API group, and name, ex. `secrets "db" not found`
+// or `storageclasses.storage.k8s.io "fast" not found`. Charts can report
+// objects they look up but require in the same format with the fail function.
+var notFoundPattern = regexp.MustCompile(`\b([a-z0-9]+)(?:\.([a-z0-9.-]+))? "([^"]+)" not found`)
+
+// watchedKind is a kind of object whose creation is watched when a release
+// fails because an object of that kind is missing.
+type watchedKind struct {
+	gvk        schema.GroupVersionKind
+	namespaced bool
+}
+
+// missingObjectKinds are the kinds of objects watched by resource, which are
+// those charts commonly reference but do not create.
+var missingObjectKinds = map[schema.GroupResource]watchedKind{
+	{Resource: "secrets"}:    {gvk: schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, namespaced: true},
+	{Resource: "configmaps"}: {gvk: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, namespaced: true},
+	{Group: "storage.k8s.io", Resource: "storageclasses"}: {
+		gvk: schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"},
+	},
+}
+
+// missingObject identifies an object a release failed on because it does not
+// exist. namespace is empty for cluster-scoped objects, and for namespaced
+// objects missing from any namespace.
+type missingObject struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+func (o missingObject) String() string {
+	if o.namespace == "" {
+		return fmt.Sprintf("%s %s", o.gvk.Kind, o.name)
+	}
+	return fmt.Sprintf("%s %s/%s", o.gvk.Kind, o.namespace, o.name)
+}
+
+// missingObjects returns the distinct objects of missingObjectKinds reported
+// as not found in err's message, sorted by kind and name. Namespaced objects
+// are expected in namespace.
+func missingObjects(err error, namespace string) []missingObject {
+	seen := map[missingObject]bool{}
+	var objs []missingObject
+	for _, m := range notFoundPattern.FindAllStringSubmatch(err.Error(), -1) {
+		kind, ok := missingObjectKinds[schema.GroupResource{Group: m[2], Resource: m[1]}]
+		if !ok {
+			continue
+		}
+		obj := missingObject{gvk: kind.gvk, name: m[3]}
+		if kind.namespaced {
+			obj.namespace = namespace
+		}
+		if !seen[obj] {
+			seen[obj] = true
+			objs = append(objs, obj)
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].gvk.Kind != objs[j].gvk.Kind {
+			return objs[i].gvk.Kind < objs[j].gvk.Kind
+		}
+		return objs[i].name < objs[j].name
+	})
+	return objs
+}
+
+// missingObjectWatchTimeout is how long a watch for missing objects may take
+// to start before it is reported as stuck.
+const missingObjectWatchTimeout = 2 * time.Minute
+
+// missingObjectWatcher requeues custom resources whose releases failed on
+// missing objects as soon as those objects are created, rather than after
+// their reconcile period.
+type missingObjectWatcher struct {
+	controller controller.Controller
+	// reviews checks that the operator may watch a kind before its watch
+	// is started, since a watch that is not allowed never starts.
+	reviews authorizationv1client.SelfSubjectAccessReviewInterface
+	// namespaces are the namespaces watched by the manager's cache. If
+	// empty, all namespaces are watched.
+	namespaces   []string
+	startTimeout time.Duration
+
+	mu      sync.Mutex
+	watches map[schema.GroupVersionKind]bool
+	waiting map[missingObject]map[reconcile.Request]bool
+}
+
+func newMissingObjectWatcher(c controller.Controller, reviews authorizationv1client.SelfSubjectAccessReviewInterface,
+	namespaces []string) *missingObjectWatcher {
+	return &missingObjectWatcher{
+		controller:   c,
+		reviews:      reviews,
+		namespaces:   namespaces,
+		startTimeout: missingObjectWatchTimeout,
+		watches:      map[schema.GroupVersionKind]bool{},
+		waiting:      map[missingObject]map[reconcile.Request]bool{},
+	}
+}
+
+// watch requeues req when each of objs is created, starting a watch for each
+// kind of objs not yet watched. Watches are started in the background since
+// they block until the kind's cache is synced.
+func (w *missingObjectWatcher) watch(log logr.Logger, req reconcile.Request, objs []missingObject) {
+	for _, gvk := range w.add(req, objs) {
+		go w.startWatch(log.WithValues("apiVersion", gvk.GroupVersion(), "kind", gvk.Kind), gvk)
+	}
+}
+
+// startWatch starts a watch for gvk if the operator is allowed to list and
+// watch it. Otherwise gvk is no longer marked watched, so that a later
+// failure checks again, and requests waiting for objects of gvk are
+// requeued after their reconcile period instead.
+func (w *missingObjectWatcher) startWatch(log logr.Logger, gvk schema.GroupVersionKind) {
+	if err := w.checkAccess(gvk); err != nil {
+		log.Error(err, "Not watching for missing objects")
+		w.unwatch(gvk)
+		return
+	}
+
+	// The cache gives no way to cancel a start, so one that times out keeps
+	// gvk marked watched rather than piling up more of them.
+	errs := make(chan error, 1)
+	go func() { errs <- w.start(gvk) }()
+	select {
+	case err := <-errs:
+		if err != nil {
+			log.Error(err, "Failed to watch for missing objects")
+			w.unwatch(gvk)
+			return
+		}
+		log.Info("Watching for missing objects")
+	case <-time.After(w.startTimeout):
+		log.Error(fmt.Errorf("cache did not sync within %s", w.startTimeout),
+			"Timed out watching for missing objects")
+	}
+}
+
+// checkAccess returns an error listing the permissions to list and watch gvk
+// in the cache's namespaces that the operator is missing.
+func (w *missingObjectWatcher) checkAccess(gvk schema.GroupVersionKind) error {
+	var gr schema.GroupResource
+	namespaced := false
+	for r, kind := range missingObjectKinds {
+		if kind.gvk == gvk {
+			gr, namespaced = r, kind.namespaced
+		}
+	}
+	namespaces := w.namespaces
+	if len(namespaces) == 0 || !namespaced {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var missing []rbacutil.Permission
+	for _, ns := range namespaces {
+		for _, verb := range []string{"list", "watch"} {
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: ns,
+						Verb:      verb,
+						Group:     gr.Group,
+						Resource:  gr.Resource,
+					},
+				},
+			}
+			review, err := w.reviews.Create(context.TODO(), review, metav1.CreateOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to review access to %s: %w", gr, err)
+			}
+			if !review.Status.Allowed {
+				missing = append(missing, rbacutil.Permission{Verb: verb, Resource: gr.Resource,
+					APIGroup: gr.Group, Namespace: ns})
+			}
+		}
+	}
+	if len(missing) != 0 {
+		rbacutil.SortPermissions(missing)
+		return errors.New(rbacutil.MissingPermissionsMessage(missing))
+	}
+	return nil
+}
+
+// add records that req is waiting for objs, and returns the kinds of objs
+// that are not yet watched, marking them watched.
+func (w *missingObjectWatcher) add(req reconcile.Request, objs []missingObject) (unwatched []schema.GroupVersionKind) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, obj := range objs {
+		if w.waiting[obj] == nil {
+			w.waiting[obj] = map[reconcile.Request]bool{}
+		}
+		w.waiting[obj][req] = true
+		if !w.watches[obj.gvk] {
+			w.watches[obj.gvk] = true
+			unwatched = append(unwatched, obj.gvk)
+		}
+	}
+	return unwatched
+}
+
+// unwatch marks gvk not watched and forgets the requests waiting for objects
+// of gvk.
+func (w *missingObjectWatcher) unwatch(gvk schema.GroupVersionKind) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.watches, gvk)
+	for obj := range w.waiting {
+		if obj.gvk == gvk {
+			delete(w.waiting, obj)
+		}
+	}
+}
+
+// forget stops requeueing req when any object is created, once its release
+// no longer fails or its custom resource is deleted.
+func (w *missingObjectWatcher) forget(req reconcile.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for obj, reqs := range w.waiting {
+		delete(reqs, req)
+		if len(reqs) == 0 {
+			delete(w.waiting, obj)
+		}
+	}
+}
+
+// start watches the creation of objects of gvk.
+func (w *missingObjectWatcher) start(gvk schema.GroupVersionKind) error {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	mapper := crthandler.ToRequestsFunc(func(o crthandler.MapObject) []reconcile.Request {
+		return w.created(gvk, ktypes.NamespacedName{Namespace: o.Meta.GetNamespace(), Name: o.Meta.GetName()})
+	})
+	return w.controller.Watch(&source.Kind{Type: u}, &crthandler.EnqueueRequestsFromMapFunc{ToRequests: mapper},
+		onlyCreates)
+}
+
+// created returns the requests waiting for the object of gvk named key,
+// which are no longer waiting once returned.
+func (w *missingObjectWatcher) created(gvk schema.GroupVersionKind, key ktypes.NamespacedName) []reconcile.Request {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var reqs []reconcile.Request
+	for _, obj := range []missingObject{
+		{gvk: gvk, namespace: key.Namespace, name: key.Name},
+		{gvk: gvk, name: key.Name},
+	} {
+		for req := range w.waiting[obj] {
+			reqs = append(reqs, req)
+		}
+		delete(w.waiting, obj)
+	}
+	return reqs
+}
+
+// onlyCreates filters out all but create events, which include those for
+// objects that already exist when a watch starts.
+var onlyCreates = crtpredicate.Funcs{
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// watchMissingObjects requeues o when the objects whose absence caused err
+// are created, if err was caused by any.
+func (r HelmOperatorReconciler) watchMissingObjects(log logr.Logger, o *unstructured.Unstructured, err error) {
+	if r.missingObjects == nil {
+		return
+	}
+	objs := missingObjects(err, o.GetNamespace())
+	if len(objs) == 0 {
+		return
+	}
+	names := make([]string, len(objs))
+	for i, obj := range objs {
+		names[i] = obj.String()
+	}
+	log.Info("Release is waiting for missing objects", "objects", names)
+	req := reconcile.Request{NamespacedName: ktypes.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}}
+	r.missingObjects.watch(log, req, objs)
+}
+
+// forgetMissingObjects stops requeueing the custom resource of req when
+// missing objects are created.
+func (r HelmOperatorReconciler) forgetMissingObjects(req reconcile.Request) {
+	if r.missingObjects != nil {
+		r.missingObjects.forget(req)
+	}
+}
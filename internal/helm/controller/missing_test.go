@@ -0,0 +1,158 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	crthandler "sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	crtpredicate "sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var (
+	secretGVK       = schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	storageClassGVK = schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}
+)
+
+func TestMissingObjects(t *testing.T) {
+	err := errors.New(`failed to install release: Secret "db" is invalid; ` +
+		`storageclasses.storage.k8s.io "fast" not found; secrets "db-credentials" not found; ` +
+		`release "nginx" not found; secrets "db-credentials" not found`)
+	assert.Equal(t, []missingObject{
+		{gvk: secretGVK, namespace: "default", name: "db-credentials"},
+		{gvk: storageClassGVK, name: "fast"},
+	}, missingObjects(err, "default"))
+
+	assert.Empty(t, missingObjects(errors.New("failed to install release: timed out"), "default"))
+	assert.Empty(t, missingObjects(errors.New(`deployments.apps "nginx" not found`), "default"))
+}
+
+func TestMissingObjectWatcher(t *testing.T) {
+	w := newMissingObjectWatcher(nil, nil, nil)
+	req1 := reconcile.Request{NamespacedName: ktypes.NamespacedName{Namespace: "default", Name: "nginx-1"}}
+	req2 := reconcile.Request{NamespacedName: ktypes.NamespacedName{Name: "nginx-2"}}
+
+	assert.Equal(t, []schema.GroupVersionKind{secretGVK}, w.add(req1, []missingObject{
+		{gvk: secretGVK, namespace: "default", name: "db-credentials"},
+	}))
+	assert.Equal(t, []schema.GroupVersionKind{storageClassGVK}, w.add(req2, []missingObject{
+		{gvk: secretGVK, name: "db-credentials"},
+		{gvk: storageClassGVK, name: "fast"},
+	}))
+
+	assert.Empty(t, w.created(secretGVK, ktypes.NamespacedName{Namespace: "default", Name: "other"}))
+	assert.ElementsMatch(t, []reconcile.Request{req1, req2},
+		w.created(secretGVK, ktypes.NamespacedName{Namespace: "default", Name: "db-credentials"}))
+	assert.Empty(t, w.created(secretGVK, ktypes.NamespacedName{Namespace: "default", Name: "db-credentials"}))
+	assert.Equal(t, []reconcile.Request{req2}, w.created(storageClassGVK, ktypes.NamespacedName{Name: "fast"}))
+}
+
+func TestMissingObjectWatcherForget(t *testing.T) {
+	w := newMissingObjectWatcher(nil, nil, nil)
+	req1 := reconcile.Request{NamespacedName: ktypes.NamespacedName{Namespace: "default", Name: "nginx-1"}}
+	req2 := reconcile.Request{NamespacedName: ktypes.NamespacedName{Namespace: "default", Name: "nginx-2"}}
+	w.add(req1, []missingObject{{gvk: secretGVK, namespace: "default", name: "db-credentials"}})
+	w.add(req2, []missingObject{
+		{gvk: secretGVK, namespace: "default", name: "db-credentials"},
+		{gvk: storageClassGVK, name: "fast"},
+	})
+
+	w.forget(req2)
+	assert.Len(t, w.waiting, 1)
+	assert.Equal(t, []reconcile.Request{req1},
+		w.created(secretGVK, ktypes.NamespacedName{Namespace: "default", Name: "db-credentials"}))
+	assert.Empty(t, w.created(storageClassGVK, ktypes.NamespacedName{Name: "fast"}))
+}
+
+// newReviewClient returns a client that allows list and watch of only the
+// resources in allowed.
+func newReviewClient(allowed ...string) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			for _, resource := range allowed {
+				review.Status.Allowed = review.Status.Allowed || review.Spec.ResourceAttributes.Resource == resource
+			}
+			return true, review, nil
+		})
+	return client
+}
+
+func TestMissingObjectWatcherCheckAccess(t *testing.T) {
+	reviews := newReviewClient("secrets").AuthorizationV1().SelfSubjectAccessReviews()
+
+	w := newMissingObjectWatcher(nil, reviews, nil)
+	assert.NoError(t, w.checkAccess(secretGVK))
+	assert.EqualError(t, w.checkAccess(storageClassGVK), "missing RBAC permissions: "+
+		"list storageclasses.storage.k8s.io at the cluster scope; "+
+		"watch storageclasses.storage.k8s.io at the cluster scope")
+
+	w = newMissingObjectWatcher(nil, newReviewClient().AuthorizationV1().SelfSubjectAccessReviews(),
+		[]string{"ns-1", "ns-2"})
+	assert.EqualError(t, w.checkAccess(secretGVK), `missing RBAC permissions: `+
+		`list secrets in namespace "ns-1"; watch secrets in namespace "ns-1"; `+
+		`list secrets in namespace "ns-2"; watch secrets in namespace "ns-2"`)
+}
+
+func TestMissingObjectWatcherStartWatch(t *testing.T) {
+	req := reconcile.Request{NamespacedName: ktypes.NamespacedName{Namespace: "default", Name: "nginx"}}
+	obj := missingObject{gvk: storageClassGVK, name: "fast"}
+
+	// A watch that is not allowed is not started, and is checked again on
+	// the next failure.
+	c := &blockingController{started: make(chan struct{})}
+	w := newMissingObjectWatcher(c, newReviewClient("secrets").AuthorizationV1().SelfSubjectAccessReviews(), nil)
+	w.add(req, []missingObject{obj})
+	w.startWatch(logf.Log, storageClassGVK)
+	assert.Empty(t, w.watches)
+	assert.Empty(t, w.waiting)
+	assert.Equal(t, []schema.GroupVersionKind{storageClassGVK}, w.add(req, []missingObject{obj}))
+
+	// A watch that does not start in time stays marked watched.
+	w = newMissingObjectWatcher(c, newReviewClient("storageclasses").AuthorizationV1().SelfSubjectAccessReviews(),
+		nil)
+	w.startTimeout = 10 * time.Millisecond
+	w.add(req, []missingObject{obj})
+	w.startWatch(logf.Log, storageClassGVK)
+	<-c.started
+	assert.True(t, w.watches[storageClassGVK])
+	assert.Empty(t, w.add(req, []missingObject{obj}))
+}
+
+// blockingController is a controller whose watches never start, like those
+// of a cache that cannot sync.
+type blockingController struct {
+	controller.Controller
+	started chan struct{}
+}
+
+func (c *blockingController) Watch(source.Source, crthandler.EventHandler, ...crtpredicate.Predicate) error {
+	close(c.started)
+	select {}
+}
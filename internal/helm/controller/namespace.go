@@ -0,0 +1,125 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	libhandler "github.com/operator-framework/operator-lib/handler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OperandNamespace configures the namespace, named by a field of each custom
+// resource's spec, that the resource's release deploys operands into. The
+// namespace is created if it does not exist and adopted if it does, by
+// setting owner annotations that reference the custom resource.
+type OperandNamespace struct {
+	// ValuesField is the dot-separated path of the spec field holding the
+	// namespace name, ex. "operand.namespace".
+	ValuesField string
+	// Labels and Annotations are set on the namespace.
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+var namespaceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+// operandNamespaceName returns the name of o's operand namespace, or an empty
+// string if o's spec does not set one.
+func (n OperandNamespace) operandNamespaceName(o *unstructured.Unstructured) (string, error) {
+	fields := append([]string{"spec"}, strings.Split(n.ValuesField, ".")...)
+	name, _, err := unstructured.NestedString(o.Object, fields...)
+	if err != nil {
+		return "", fmt.Errorf("invalid operand namespace field %q: %w", n.ValuesField, err)
+	}
+	return name, nil
+}
+
+// ensureOperandNamespace creates o's operand namespace, or adopts it if it
+// exists and is not owned by another custom resource, and sets its labels
+// and annotations.
+func (r HelmOperatorReconciler) ensureOperandNamespace(o *unstructured.Unstructured) error {
+	name, err := r.OperandNamespace.operandNamespaceName(o)
+	if err != nil || name == "" || name == o.GetNamespace() {
+		return err
+	}
+
+	ns := &unstructured.Unstructured{}
+	ns.SetGroupVersionKind(namespaceGVK)
+	err = r.Client.Get(context.TODO(), client.ObjectKey{Name: name}, ns)
+	if apierrors.IsNotFound(err) {
+		ns = &unstructured.Unstructured{}
+		ns.SetGroupVersionKind(namespaceGVK)
+		ns.SetName(name)
+		if _, err := r.OperandNamespace.apply(ns, o); err != nil {
+			return err
+		}
+		log.Info("Creating operand namespace", "namespace", name)
+		return r.Client.Create(context.TODO(), ns)
+	} else if err != nil {
+		return fmt.Errorf("failed to get operand namespace %s: %w", name, err)
+	}
+
+	changed, err := r.OperandNamespace.apply(ns, o)
+	if err != nil || !changed {
+		return err
+	}
+	log.Info("Updating operand namespace", "namespace", name)
+	return r.Client.Update(context.TODO(), ns)
+}
+
+// apply sets n's labels and annotations, and owner annotations referencing
+// owner, on ns. It returns true if ns was changed, and an error if ns is
+// owned by a different resource.
+func (n OperandNamespace) apply(ns, owner *unstructured.Unstructured) (bool, error) {
+	want := &unstructured.Unstructured{}
+	if err := libhandler.SetOwnerAnnotations(want, owner); err != nil {
+		return false, err
+	}
+	ownerAnnotations := want.GetAnnotations()
+	annotations := ns.GetAnnotations()
+	if current, ok := annotations[libhandler.NamespacedNameAnnotation]; ok &&
+		(current != ownerAnnotations[libhandler.NamespacedNameAnnotation] ||
+			annotations[libhandler.TypeAnnotation] != ownerAnnotations[libhandler.TypeAnnotation]) {
+		return false, fmt.Errorf("namespace %s is owned by %s %s", ns.GetName(),
+			annotations[libhandler.TypeAnnotation], current)
+	}
+
+	changed := false
+	merge := func(dst, src map[string]string) map[string]string {
+		if dst == nil {
+			dst = map[string]string{}
+		}
+		for k, v := range src {
+			if cur, ok := dst[k]; !ok || cur != v {
+				dst[k] = v
+				changed = true
+			}
+		}
+		return dst
+	}
+	annotations = merge(annotations, n.Annotations)
+	annotations = merge(annotations, ownerAnnotations)
+	ns.SetAnnotations(annotations)
+	if len(n.Labels) != 0 {
+		ns.SetLabels(merge(ns.GetLabels(), n.Labels))
+	}
+	return changed, nil
+}
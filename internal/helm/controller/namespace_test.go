@@ -0,0 +1,81 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	libhandler "github.com/operator-framework/operator-lib/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newOperandNamespaceCR(name, operandNamespace string) *unstructured.Unstructured {
+	o := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"operand": map[string]interface{}{"namespace": operandNamespace},
+		},
+	}}
+	o.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Nginx"})
+	o.SetNamespace("default")
+	o.SetName(name)
+	return o
+}
+
+func TestEnsureOperandNamespace(t *testing.T) {
+	r := HelmOperatorReconciler{
+		Client: fake.NewFakeClient(),
+		OperandNamespace: &OperandNamespace{
+			ValuesField: "operand.namespace",
+			Labels:      map[string]string{"team": "web"},
+		},
+	}
+
+	// The namespace is created with labels and owner annotations.
+	require.NoError(t, r.ensureOperandNamespace(newOperandNamespaceCR("test", "nginx")))
+	ns := &corev1.Namespace{}
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: "nginx"}, ns))
+	assert.Equal(t, "web", ns.Labels["team"])
+	assert.Equal(t, "default/test", ns.Annotations[libhandler.NamespacedNameAnnotation])
+	assert.Equal(t, "Nginx.example.com", ns.Annotations[libhandler.TypeAnnotation])
+
+	// Reconciling the owner again is a no-op.
+	require.NoError(t, r.ensureOperandNamespace(newOperandNamespaceCR("test", "nginx")))
+
+	// A namespace owned by another resource is not adopted.
+	assert.Error(t, r.ensureOperandNamespace(newOperandNamespaceCR("other", "nginx")))
+
+	// An existing namespace without an owner is adopted.
+	existing := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "existing", Labels: map[string]string{"a": "b"}}}
+	require.NoError(t, r.Client.Create(context.TODO(), existing))
+	require.NoError(t, r.ensureOperandNamespace(newOperandNamespaceCR("test", "existing")))
+	require.NoError(t, r.Client.Get(context.TODO(), client.ObjectKey{Name: "existing"}, ns))
+	assert.Equal(t, map[string]string{"a": "b", "team": "web"}, ns.Labels)
+	assert.Equal(t, "default/test", ns.Annotations[libhandler.NamespacedNameAnnotation])
+
+	// CRs without an operand namespace, or whose operand namespace is their
+	// own, are skipped.
+	require.NoError(t, r.ensureOperandNamespace(newOperandNamespaceCR("test", "")))
+	require.NoError(t, r.ensureOperandNamespace(newOperandNamespaceCR("test", "default")))
+	err := r.Client.Get(context.TODO(), client.ObjectKey{Name: "default"}, ns)
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/helm/internal/types"
+	"github.com/operator-framework/operator-sdk/internal/util/rbacutil"
+)
+
+// forbiddenPattern matches the API server's message for a request denied by
+// RBAC, capturing the user, verb, resource, API group, and namespace.
+var forbiddenPattern = regexp.MustCompile(`User "([^"]*)" cannot ([a-z]+) resource "([^"]+)" ` +
+	`in API group "([^"]*)"(?: in the namespace "([^"]+)")?`)
+
+// missingPermissions returns the distinct permissions denied by each
+// Forbidden error reported in err's message, sorted by namespace, API group,
+// resource, and verb.
+func missingPermissions(err error) []rbacutil.Permission {
+	seen := map[rbacutil.Permission]bool{}
+	var perms []rbacutil.Permission
+	for _, m := range forbiddenPattern.FindAllStringSubmatch(err.Error(), -1) {
+		p := rbacutil.Permission{User: m[1], Verb: m[2], Resource: m[3], APIGroup: m[4], Namespace: m[5]}
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+	rbacutil.SortPermissions(perms)
+	return perms
+}
+
+// setFailedCondition sets a condition of conditionType on status for err,
+// with reason unless err was caused by missing RBAC permissions. In that case
+// the condition lists all of them, and if they differ from those of the
+// previous failure, a Role that grants them is logged.
+func (r HelmOperatorReconciler) setFailedCondition(log logr.Logger, status *types.HelmAppStatus,
+	conditionType types.HelmAppConditionType, reason types.HelmAppConditionReason, err error) {
+	condition := types.HelmAppCondition{
+		Type:    conditionType,
+		Status:  types.StatusTrue,
+		Reason:  reason,
+		Message: err.Error(),
+	}
+	perms := missingPermissions(err)
+	if len(perms) != 0 {
+		condition.Reason = types.ReasonMissingPermissions
+		condition.Message = rbacutil.MissingPermissionsMessage(perms)
+		if !hasCondition(status, condition) {
+			r.logMissingPermissions(log, perms)
+		}
+	}
+	status.SetCondition(condition)
+}
+
+// hasCondition returns true if status has a condition with the type, reason,
+// and message of condition.
+func hasCondition(status *types.HelmAppStatus, condition types.HelmAppCondition) bool {
+	for _, c := range status.Conditions {
+		if c.Type == condition.Type && c.Reason == condition.Reason && c.Message == condition.Message {
+			return true
+		}
+	}
+	return false
+}
+
+// logMissingPermissions logs perms and prints RBAC manifests that grant them.
+func (r HelmOperatorReconciler) logMissingPermissions(log logr.Logger, perms []rbacutil.Permission) {
+	name := fmt.Sprintf("%s-release-permissions", strings.ToLower(r.GVK.Kind))
+	manifests, err := rbacManifests(name, perms)
+	if err != nil {
+		log.Error(err, "Failed to generate RBAC manifests for missing permissions")
+		return
+	}
+	log.Info("Release is missing RBAC permissions, which are granted by the following manifests",
+		"permissions", rbacutil.MissingPermissionsMessage(perms))
+	if log.V(0).Enabled() {
+		fmt.Println(manifests)
+	}
+}
+
+// rbacManifests returns YAML manifests of a Role and RoleBinding named name
+// in each namespace of perms, and a ClusterRole and ClusterRoleBinding for
+// perms at the cluster scope, that grant perms to the users denied them.
+func rbacManifests(name string, perms []rbacutil.Permission) (string, error) {
+	var objs []runtime.Object
+	namespaces := []string{}
+	byNamespace := map[string][]rbacutil.Permission{}
+	for _, p := range perms {
+		if _, ok := byNamespace[p.Namespace]; !ok {
+			namespaces = append(namespaces, p.Namespace)
+		}
+		byNamespace[p.Namespace] = append(byNamespace[p.Namespace], p)
+	}
+
+	for _, ns := range namespaces {
+		nsPerms := byNamespace[ns]
+		meta := metav1.ObjectMeta{Name: name, Namespace: ns}
+		roleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Name: name}
+		if ns == "" {
+			roleRef.Kind = "ClusterRole"
+			objs = append(objs,
+				&rbacv1.ClusterRole{
+					TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+					ObjectMeta: meta,
+					Rules:      policyRules(nsPerms),
+				},
+				&rbacv1.ClusterRoleBinding{
+					TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+					ObjectMeta: meta,
+					RoleRef:    roleRef,
+					Subjects:   subjects(nsPerms),
+				})
+			continue
+		}
+		roleRef.Kind = "Role"
+		objs = append(objs,
+			&rbacv1.Role{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+				ObjectMeta: meta,
+				Rules:      policyRules(nsPerms),
+			},
+			&rbacv1.RoleBinding{
+				TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+				ObjectMeta: meta,
+				RoleRef:    roleRef,
+				Subjects:   subjects(nsPerms),
+			})
+	}
+
+	docs := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return "", err
+		}
+		unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(b))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// policyRules returns a rule for each API group and resource of perms.
+func policyRules(perms []rbacutil.Permission) []rbacv1.PolicyRule {
+	var rules []rbacv1.PolicyRule
+	for _, p := range perms {
+		n := len(rules)
+		if n != 0 && rules[n-1].APIGroups[0] == p.APIGroup && rules[n-1].Resources[0] == p.Resource {
+			rules[n-1].Verbs = append(rules[n-1].Verbs, p.Verb)
+			continue
+		}
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{p.APIGroup},
+			Resources: []string{p.Resource},
+			Verbs:     []string{p.Verb},
+		})
+	}
+	return rules
+}
+
+// subjects returns the distinct users of perms as RBAC subjects.
+func subjects(perms []rbacutil.Permission) []rbacv1.Subject {
+	seen := map[string]bool{}
+	var subjects []rbacv1.Subject
+	for _, p := range perms {
+		if seen[p.User] {
+			continue
+		}
+		seen[p.User] = true
+		if parts := strings.Split(p.User, ":"); len(parts) == 4 && parts[0] == "system" &&
+			parts[1] == "serviceaccount" {
+			subjects = append(subjects, rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Namespace: parts[2],
+				Name:      parts[3],
+			})
+			continue
+		}
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: p.User})
+	}
+	return subjects
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/operator-framework/operator-sdk/internal/helm/internal/types"
+	"github.com/operator-framework/operator-sdk/internal/util/rbacutil"
+)
+
+const forbiddenErr = `failed to upgrade release: ` +
+	`deployments.apps "test" is forbidden: User "system:serviceaccount:ops:helm-operator" cannot patch ` +
+	`resource "deployments" in API group "apps" in the namespace "default" && ` +
+	`services "test" is forbidden: User "system:serviceaccount:ops:helm-operator" cannot create ` +
+	`resource "services" in API group "" in the namespace "default" && ` +
+	`deployments.apps "test-2" is forbidden: User "system:serviceaccount:ops:helm-operator" cannot patch ` +
+	`resource "deployments" in API group "apps" in the namespace "default" && ` +
+	`clusterroles.rbac.authorization.k8s.io "test" is forbidden: User "system:serviceaccount:ops:helm-operator" ` +
+	`cannot get resource "clusterroles" in API group "rbac.authorization.k8s.io" at the cluster scope`
+
+func TestMissingPermissions(t *testing.T) {
+	assert.Empty(t, missingPermissions(errors.New("failed to upgrade release: timed out")))
+
+	perms := missingPermissions(errors.New(forbiddenErr))
+	user := "system:serviceaccount:ops:helm-operator"
+	assert.Equal(t, []rbacutil.Permission{
+		{User: user, Verb: "get", Resource: "clusterroles", APIGroup: "rbac.authorization.k8s.io"},
+		{User: user, Verb: "create", Resource: "services", APIGroup: "", Namespace: "default"},
+		{User: user, Verb: "patch", Resource: "deployments", APIGroup: "apps", Namespace: "default"},
+	}, perms)
+	assert.Equal(t, `missing RBAC permissions: get clusterroles.rbac.authorization.k8s.io at the cluster scope; `+
+		`create services in namespace "default"; patch deployments.apps in namespace "default"`,
+		rbacutil.MissingPermissionsMessage(perms))
+}
+
+func TestRBACManifests(t *testing.T) {
+	manifests, err := rbacManifests("nginx-release-permissions", missingPermissions(errors.New(forbiddenErr)))
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: nginx-release-permissions
+rules:
+- apiGroups:
+  - rbac.authorization.k8s.io
+  resources:
+  - clusterroles
+  verbs:
+  - get
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: nginx-release-permissions
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: nginx-release-permissions
+subjects:
+- kind: ServiceAccount
+  name: helm-operator
+  namespace: ops
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: nginx-release-permissions
+  namespace: default
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - services
+  verbs:
+  - create
+- apiGroups:
+  - apps
+  resources:
+  - deployments
+  verbs:
+  - patch
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: nginx-release-permissions
+  namespace: default
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: nginx-release-permissions
+subjects:
+- kind: ServiceAccount
+  name: helm-operator
+  namespace: ops
+`, manifests)
+}
+
+func TestSetFailedCondition(t *testing.T) {
+	r := HelmOperatorReconciler{GVK: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Nginx"}}
+	log := logf.Log.WithName("test")
+
+	status := &types.HelmAppStatus{}
+	r.setFailedCondition(log, status, types.ConditionReleaseFailed, types.ReasonUpgradeError,
+		errors.New("failed to upgrade release: timed out"))
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, types.ReasonUpgradeError, status.Conditions[0].Reason)
+	assert.Equal(t, "failed to upgrade release: timed out", status.Conditions[0].Message)
+
+	r.setFailedCondition(log, status, types.ConditionReleaseFailed, types.ReasonUpgradeError, errors.New(forbiddenErr))
+	require.Len(t, status.Conditions, 1)
+	assert.Equal(t, types.ReasonMissingPermissions, status.Conditions[0].Reason)
+	assert.Contains(t, status.Conditions[0].Message, "missing RBAC permissions: ")
+	assert.True(t, hasCondition(status, status.Conditions[0]))
+}
@@ -16,14 +16,22 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	rpb "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -33,9 +41,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
+	"github.com/operator-framework/operator-sdk/internal/helm/audit"
 	"github.com/operator-framework/operator-sdk/internal/helm/internal/diff"
 	"github.com/operator-framework/operator-sdk/internal/helm/internal/types"
+	"github.com/operator-framework/operator-sdk/internal/helm/metrics"
 	"github.com/operator-framework/operator-sdk/internal/helm/release"
 )
 
@@ -53,7 +64,31 @@ type HelmOperatorReconciler struct {
 	ManagerFactory  release.ManagerFactory
 	ReconcilePeriod time.Duration
 	OverrideValues  map[string]string
-	releaseHook     ReleaseHookFunc
+	// InstallTimeout and UpgradeTimeout, if non-zero, are how long installs
+	// and upgrades wait for release resources to become ready.
+	InstallTimeout time.Duration
+	UpgradeTimeout time.Duration
+	// AuditLogger, if set, records a diff of each release install, upgrade, and uninstall.
+	AuditLogger *audit.Logger
+	// ValidateValuesSchema, if true, validates values against the chart's
+	// values schema before installs and upgrades.
+	ValidateValuesSchema bool
+	// RollbackOnFailure, if true, rolls a release back to its last deployed
+	// revision when an upgrade fails.
+	RollbackOnFailure bool
+	// OperandNamespace, if set, is created or adopted before each release
+	// install or upgrade.
+	OperandNamespace *OperandNamespace
+	// SubReleases are additional charts installed as separate releases for
+	// each custom resource once its release is deployed.
+	SubReleases []SubRelease
+	// StandardConditions, if true, makes status conditions follow the
+	// Kubernetes API conventions of metav1.Condition.
+	StandardConditions bool
+	releaseHook        ReleaseHookFunc
+	// missingObjects, if set, requeues custom resources whose releases
+	// failed on missing objects once those objects are created.
+	missingObjects *missingObjectWatcher
 }
 
 const (
@@ -64,8 +99,24 @@ const (
 // uninstalling a Helm release based on the resource's current state. If no
 // release changes are necessary, Reconcile will create or patch the underlying
 // resources to match the expected release manifest.
+func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	metrics.ReconcileStarted(r.GVK.String())
+	defer metrics.ReconcileFinished(r.GVK.String())
+	timer := metrics.ReconcileTimer(r.GVK.String())
+	defer timer.ObserveDuration()
 
-func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo
+	result, err := r.reconcile(request)
+	if err != nil {
+		metrics.ReconcileFailed(r.GVK.String())
+	} else {
+		metrics.ReconcileSucceeded(r.GVK.String())
+		// The release no longer fails on missing objects, or the CR is gone.
+		r.forgetMissingObjects(request)
+	}
+	return result, err
+}
+
+func (r HelmOperatorReconciler) reconcile(request reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo
 	o := &unstructured.Unstructured{}
 	o.SetGroupVersionKind(r.GVK)
 	o.SetNamespace(request.Namespace)
@@ -90,6 +141,17 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 	manager, err := r.ManagerFactory.NewManager(o, r.OverrideValues)
 	if err != nil {
 		log.Error(err, "Failed to get release manager")
+		var verifyErr *release.ChartVerificationError
+		if errors.As(err, &verifyErr) {
+			status := types.StatusFor(o)
+			status.SetCondition(types.HelmAppCondition{
+				Type:    types.ConditionIrreconcilable,
+				Status:  types.StatusTrue,
+				Reason:  types.ReasonChartVerifyError,
+				Message: err.Error(),
+			})
+			_ = r.updateResourceStatus(o, status)
+		}
 		return reconcile.Result{}, err
 	}
 
@@ -102,13 +164,29 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 			return reconcile.Result{}, nil
 		}
 
+		if err := r.uninstallSubReleases(log, o, status); err != nil {
+			log.Error(err, "Failed to uninstall sub-releases")
+			status.SetCondition(types.HelmAppCondition{
+				Type:    types.ConditionReleaseFailed,
+				Status:  types.StatusTrue,
+				Reason:  types.ReasonUninstallError,
+				Message: err.Error(),
+			})
+			_ = r.updateResourceStatus(o, status)
+			return reconcile.Result{}, err
+		}
+
 		uninstalledRelease, err := manager.UninstallRelease(context.TODO())
 		if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
 			log.Error(err, "Failed to uninstall release")
+			reason := types.ReasonUninstallError
+			if setFailedHooks(status, err) {
+				reason = types.ReasonHookFailed
+			}
 			status.SetCondition(types.HelmAppCondition{
 				Type:    types.ConditionReleaseFailed,
 				Status:  types.StatusTrue,
-				Reason:  types.ReasonUninstallError,
+				Reason:  reason,
 				Message: err.Error(),
 			})
 			_ = r.updateResourceStatus(o, status)
@@ -119,16 +197,18 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 		if errors.Is(err, driver.ErrReleaseNotFound) {
 			log.Info("Release not found, removing finalizer")
 		} else {
-			log.Info("Uninstalled release")
-			if log.V(0).Enabled() {
-				fmt.Println(diff.Generate(uninstalledRelease.Manifest, ""))
-			}
+			summary := uninstallSummary(uninstalledRelease.Manifest)
+			log.Info("Uninstalled release", "summary", summary)
+			log.V(1).Info("Uninstall diff", "diff", diff.Plain(uninstalledRelease.Manifest, ""))
+			r.recordAudit(log, o, audit.ActionUninstall, uninstalledRelease, uninstalledRelease.Manifest, "")
 			status.SetCondition(types.HelmAppCondition{
-				Type:   types.ConditionDeployed,
-				Status: types.StatusFalse,
-				Reason: types.ReasonUninstallSuccessful,
+				Type:    types.ConditionDeployed,
+				Status:  types.StatusFalse,
+				Reason:  types.ReasonUninstallSuccessful,
+				Message: summary,
 			})
 			status.DeployedRelease = nil
+			status.Hooks = hookStatuses(uninstalledRelease.Hooks, "")
 		}
 		if err := r.updateResourceStatus(o, status); err != nil {
 			log.Info("Failed to update CR status")
@@ -160,6 +240,7 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 
 	if err := manager.Sync(context.TODO()); err != nil {
 		log.Error(err, "Failed to sync release")
+		r.watchMissingObjects(log, o, err)
 		status.SetCondition(types.HelmAppCondition{
 			Type:    types.ConditionIrreconcilable,
 			Status:  types.StatusTrue,
@@ -171,20 +252,72 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 	}
 	status.RemoveCondition(types.ConditionIrreconcilable)
 
+	recoveredPending := false
+	if pending := manager.RecoveredPendingRelease(); pending != nil {
+		recoveredPending = true
+		message := fmt.Sprintf("Removed revision %d of release %q stuck in status %q before retrying",
+			pending.Version, pending.Name, pending.Info.Status)
+		log.Info("Recovered release from pending state", "revision", pending.Version,
+			"status", pending.Info.Status.String())
+		r.EventRecorder.Event(o, "Warning", string(types.ConditionRecoveredFromPending), message)
+		status.SetCondition(types.HelmAppCondition{
+			Type:    types.ConditionRecoveredFromPending,
+			Status:  types.StatusTrue,
+			Reason:  types.ReasonPendingReleaseRemoved,
+			Message: message,
+		})
+	}
+
+	if r.ValidateValuesSchema && (!manager.IsInstalled() || manager.IsUpgradeRequired()) {
+		if err := manager.ValidateValues(); err != nil {
+			// The spec must be changed to pass validation, which triggers
+			// a new reconcile, so don't requeue.
+			log.Error(err, "Custom resource spec does not match the chart's values schema")
+			status.SetCondition(types.HelmAppCondition{
+				Type:    types.ConditionInvalidSpec,
+				Status:  types.StatusTrue,
+				Reason:  types.ReasonValuesSchemaViolation,
+				Message: err.Error(),
+			})
+			err = r.updateResourceStatus(o, status)
+			return reconcile.Result{}, err
+		}
+	}
+	status.RemoveCondition(types.ConditionInvalidSpec)
+
+	if r.OperandNamespace != nil {
+		if err := r.ensureOperandNamespace(o); err != nil {
+			log.Error(err, "Failed to create or adopt operand namespace")
+			status.SetCondition(types.HelmAppCondition{
+				Type:    types.ConditionIrreconcilable,
+				Status:  types.StatusTrue,
+				Reason:  types.ReasonOperandNamespaceError,
+				Message: err.Error(),
+			})
+			_ = r.updateResourceStatus(o, status)
+			return reconcile.Result{}, err
+		}
+	}
+
 	if !manager.IsInstalled() {
 		for k, v := range r.OverrideValues {
 			r.EventRecorder.Eventf(o, "Warning", "OverrideValuesInUse",
 				"Chart value %q overridden to %q by operator's watches.yaml", k, v)
 		}
-		installedRelease, err := manager.InstallRelease(context.TODO())
+		var installOpts []release.InstallOption
+		if r.InstallTimeout > 0 {
+			installOpts = append(installOpts, release.InstallTimeout(r.InstallTimeout))
+		}
+		installTimer := metrics.ReleaseInstallTimer(r.GVK.String())
+		installedRelease, err := manager.InstallRelease(context.TODO(), installOpts...)
+		installTimer.ObserveDuration()
 		if err != nil {
 			log.Error(err, "Release failed")
-			status.SetCondition(types.HelmAppCondition{
-				Type:    types.ConditionReleaseFailed,
-				Status:  types.StatusTrue,
-				Reason:  types.ReasonInstallError,
-				Message: err.Error(),
-			})
+			metrics.ReleaseInstallFailed(r.GVK.String())
+			r.watchMissingObjects(log, o, err)
+			setFailedHooks(status, err)
+			r.setFailedCondition(log, status, types.ConditionReleaseFailed,
+				failureReason(err, types.ReasonInstallError), err)
 			_ = r.updateResourceStatus(o, status)
 			return reconcile.Result{}, err
 		}
@@ -208,6 +341,7 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 		if log.V(0).Enabled() {
 			fmt.Println(diff.Generate("", installedRelease.Manifest))
 		}
+		r.recordAudit(log, o, audit.ActionInstall, installedRelease, "", installedRelease.Manifest)
 		log.V(1).Info("Config values", "values", installedRelease.Config)
 		message := ""
 		if installedRelease.Info != nil {
@@ -219,12 +353,9 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 			Reason:  types.ReasonInstallSuccessful,
 			Message: message,
 		})
-		status.DeployedRelease = &types.HelmAppRelease{
-			Name:     installedRelease.Name,
-			Manifest: installedRelease.Manifest,
-		}
-		err = r.updateResourceStatus(o, status)
-		return reconcile.Result{RequeueAfter: r.ReconcilePeriod}, err
+		status.DeployedRelease = releaseStatus(installedRelease)
+		status.Hooks = hookStatuses(installedRelease.Hooks, "")
+		return r.deployed(log, o, status, recoveredPending)
 	}
 
 	if !contains(o.GetFinalizers(), finalizer) {
@@ -242,19 +373,29 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 				"Chart value %q overridden to %q by operator's watches.yaml", k, v)
 		}
 		force := hasHelmUpgradeForceAnnotation(o)
-		previousRelease, upgradedRelease, err := manager.UpgradeRelease(context.TODO(), release.ForceUpgrade(force))
+		upgradeOpts := []release.UpgradeOption{release.ForceUpgrade(force)}
+		if r.UpgradeTimeout > 0 {
+			upgradeOpts = append(upgradeOpts, release.UpgradeTimeout(r.UpgradeTimeout))
+		}
+		if r.RollbackOnFailure {
+			upgradeOpts = append(upgradeOpts, release.RollbackOnFailure(true))
+		}
+		upgradeTimer := metrics.ReleaseUpgradeTimer(r.GVK.String())
+		previousRelease, upgradedRelease, err := manager.UpgradeRelease(context.TODO(), upgradeOpts...)
+		upgradeTimer.ObserveDuration()
 		if err != nil {
 			log.Error(err, "Release failed")
-			status.SetCondition(types.HelmAppCondition{
-				Type:    types.ConditionReleaseFailed,
-				Status:  types.StatusTrue,
-				Reason:  types.ReasonUpgradeError,
-				Message: err.Error(),
-			})
+			metrics.ReleaseUpgradeFailed(r.GVK.String())
+			r.watchMissingObjects(log, o, err)
+			setFailedHooks(status, err)
+			r.setFailedCondition(log, status, types.ConditionReleaseFailed,
+				failureReason(err, types.ReasonUpgradeError), err)
+			setRolledBack(status, err)
 			_ = r.updateResourceStatus(o, status)
 			return reconcile.Result{}, err
 		}
 		status.RemoveCondition(types.ConditionReleaseFailed)
+		status.RemoveCondition(types.ConditionRolledBack)
 
 		if r.releaseHook != nil {
 			if err := r.releaseHook(upgradedRelease); err != nil {
@@ -267,6 +408,7 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 		if log.V(0).Enabled() {
 			fmt.Println(diff.Generate(previousRelease.Manifest, upgradedRelease.Manifest))
 		}
+		r.recordAudit(log, o, audit.ActionUpgrade, upgradedRelease, previousRelease.Manifest, upgradedRelease.Manifest)
 		log.V(1).Info("Config values", "values", upgradedRelease.Config)
 		message := ""
 		if upgradedRelease.Info != nil {
@@ -278,12 +420,9 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 			Reason:  types.ReasonUpgradeSuccessful,
 			Message: message,
 		})
-		status.DeployedRelease = &types.HelmAppRelease{
-			Name:     upgradedRelease.Name,
-			Manifest: upgradedRelease.Manifest,
-		}
-		err = r.updateResourceStatus(o, status)
-		return reconcile.Result{RequeueAfter: r.ReconcilePeriod}, err
+		status.DeployedRelease = releaseStatus(upgradedRelease)
+		status.Hooks = hookStatuses(upgradedRelease.Hooks, "")
+		return r.deployed(log, o, status, recoveredPending)
 	}
 
 	// If a change is made to the CR spec that causes a release failure, a
@@ -297,12 +436,8 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 	expectedRelease, err := manager.ReconcileRelease(context.TODO())
 	if err != nil {
 		log.Error(err, "Failed to reconcile release")
-		status.SetCondition(types.HelmAppCondition{
-			Type:    types.ConditionIrreconcilable,
-			Status:  types.StatusTrue,
-			Reason:  types.ReasonReconcileError,
-			Message: err.Error(),
-		})
+		r.watchMissingObjects(log, o, err)
+		r.setFailedCondition(log, status, types.ConditionIrreconcilable, types.ReasonReconcileError, err)
 		_ = r.updateResourceStatus(o, status)
 		return reconcile.Result{}, err
 	}
@@ -330,12 +465,25 @@ func (r HelmOperatorReconciler) Reconcile(request reconcile.Request) (reconcile.
 		Reason:  reason,
 		Message: message,
 	})
-	status.DeployedRelease = &types.HelmAppRelease{
-		Name:     expectedRelease.Name,
-		Manifest: expectedRelease.Manifest,
+	status.DeployedRelease = releaseStatus(expectedRelease)
+	return r.deployed(log, o, status, recoveredPending)
+}
+
+// deployed reconciles o's sub-releases once its release is deployed, updates
+// its status, and requeues it after its reconcile period. The
+// RecoveredFromPending condition of an earlier reconcile is removed unless
+// recoveredPending reports that this reconcile recovered a pending release.
+func (r HelmOperatorReconciler) deployed(log logr.Logger, o *unstructured.Unstructured,
+	status *types.HelmAppStatus, recoveredPending bool) (reconcile.Result, error) {
+	if err := r.reconcileSubReleases(log, o, status); err != nil {
+		_ = r.updateResourceStatus(o, status)
+		return reconcile.Result{}, err
+	}
+	if !recoveredPending {
+		status.RemoveCondition(types.ConditionRecoveredFromPending)
 	}
-	err = r.updateResourceStatus(o, status)
-	return reconcile.Result{RequeueAfter: r.ReconcilePeriod}, err
+	err := r.updateResourceStatus(o, status)
+	return reconcile.Result{RequeueAfter: reconcilePeriod(o, r.ReconcilePeriod)}, err
 }
 
 // returns the boolean representation of the annotation string
@@ -356,6 +504,174 @@ func hasHelmUpgradeForceAnnotation(o *unstructured.Unstructured) bool {
 	return value
 }
 
+// reconcilePeriod returns the duration set by the reconcile period annotation
+// on o, or defaultPeriod if the annotation is not set or is not a positive
+// duration.
+func reconcilePeriod(o *unstructured.Unstructured, defaultPeriod time.Duration) time.Duration {
+	const helmReconcilePeriodAnnotation = "helm.sdk.operatorframework.io/reconcile-period"
+	period := o.GetAnnotations()[helmReconcilePeriodAnnotation]
+	if period == "" {
+		return defaultPeriod
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		log.Info("Could not parse annotation as a positive duration",
+			"annotation", helmReconcilePeriodAnnotation, "value informed", period)
+		return defaultPeriod
+	}
+	return d
+}
+
+// failureReason returns the ReleaseFailed condition reason for err, which is
+// ReasonProgressDeadline if the release timed out and reason otherwise.
+func failureReason(err error, reason types.HelmAppConditionReason) types.HelmAppConditionReason {
+	var hookErr *release.HookFailedError
+	if errors.As(err, &hookErr) {
+		return types.ReasonHookFailed
+	}
+	if release.IsTimeoutError(err) {
+		return types.ReasonProgressDeadline
+	}
+	return reason
+}
+
+// setFailedHooks sets status's hooks from err and returns true if err was
+// caused by a failed chart hook.
+func setFailedHooks(status *types.HelmAppStatus, err error) bool {
+	var hookErr *release.HookFailedError
+	if !errors.As(err, &hookErr) {
+		return false
+	}
+	status.Hooks = hookStatuses(hookErr.Hooks, err.Error())
+	return true
+}
+
+// setRolledBack sets status's RolledBack condition and deployed release and
+// returns true if err reports that a failed upgrade was rolled back.
+func setRolledBack(status *types.HelmAppStatus, err error) bool {
+	var rbErr *release.RolledBackError
+	if !errors.As(err, &rbErr) {
+		return false
+	}
+	message := ""
+	if rbErr.Release.Info != nil {
+		message = rbErr.Release.Info.Description
+	}
+	status.SetCondition(types.HelmAppCondition{
+		Type:    types.ConditionRolledBack,
+		Status:  types.StatusTrue,
+		Reason:  types.ReasonRollbackSuccessful,
+		Message: message,
+	})
+	status.DeployedRelease = releaseStatus(rbErr.Release)
+	return true
+}
+
+// releaseStatus returns the status of the deployed release rel.
+func releaseStatus(rel *rpb.Release) *types.HelmAppRelease {
+	status := &types.HelmAppRelease{
+		Name:         rel.Name,
+		Manifest:     rel.Manifest,
+		Revision:     rel.Version,
+		ManifestHash: sha256Hex([]byte(rel.Manifest)),
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		status.ChartName = rel.Chart.Metadata.Name
+		status.ChartVersion = rel.Chart.Metadata.Version
+	}
+	// Maps are encoded with sorted keys, so equal values have equal checksums.
+	if values, err := json.Marshal(rel.Config); err == nil {
+		status.ValuesChecksum = sha256Hex(values)
+	}
+	return status
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// hookStatuses returns the status of each hook in hooks that has run. Failed
+// hooks have failureMessage as their message.
+func hookStatuses(hooks []*rpb.Hook, failureMessage string) []types.HelmAppHook {
+	var out []types.HelmAppHook
+	for _, h := range hooks {
+		if h.LastRun.StartedAt.IsZero() {
+			continue
+		}
+		hook := types.HelmAppHook{
+			Name:  h.Name,
+			Kind:  h.Kind,
+			Phase: h.LastRun.Phase.String(),
+		}
+		for _, e := range h.Events {
+			hook.Events = append(hook.Events, e.String())
+		}
+		startedAt := metav1.NewTime(h.LastRun.StartedAt.Time)
+		hook.StartedAt = &startedAt
+		if !h.LastRun.CompletedAt.IsZero() {
+			completedAt := metav1.NewTime(h.LastRun.CompletedAt.Time)
+			hook.CompletedAt = &completedAt
+		}
+		if h.LastRun.Phase == rpb.HookPhaseFailed {
+			hook.Message = failureMessage
+		}
+		out = append(out, hook)
+	}
+	return out
+}
+
+// uninstallSummary returns a message counting the resources in manifest by
+// kind, ex. "Uninstalled 3 resources: 1 ConfigMap, 2 Service".
+func uninstallSummary(manifest string) string {
+	counts := map[string]int{}
+	total := 0
+	for _, m := range releaseutil.SplitManifests(manifest) {
+		var u unstructured.Unstructured
+		if err := yaml.Unmarshal([]byte(m), &u); err != nil || u.GetKind() == "" {
+			continue
+		}
+		counts[u.GetKind()]++
+		total++
+	}
+	if total == 0 {
+		return "Uninstalled 0 resources"
+	}
+
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", counts[kind], kind)
+	}
+	return fmt.Sprintf("Uninstalled %d resources: %s", total, strings.Join(parts, ", "))
+}
+
+// recordAudit writes the diff between the before and after manifests of rel,
+// the result of action on o, to r.AuditLogger if set.
+func (r HelmOperatorReconciler) recordAudit(log logr.Logger, o *unstructured.Unstructured, action audit.Action,
+	rel *rpb.Release, before, after string) {
+	if r.AuditLogger == nil {
+		return
+	}
+	entry := audit.Entry{
+		Action:     action,
+		APIVersion: o.GetAPIVersion(),
+		Kind:       o.GetKind(),
+		Namespace:  o.GetNamespace(),
+		Name:       o.GetName(),
+		Release:    rel.Name,
+		Revision:   rel.Version,
+		Diff:       diff.Plain(before, after),
+	}
+	if err := r.AuditLogger.Log(entry); err != nil {
+		log.Error(err, "Failed to write audit log entry", "action", action)
+	}
+}
+
 func (r HelmOperatorReconciler) updateResource(o runtime.Object) error {
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		return r.Client.Update(context.TODO(), o)
@@ -363,12 +679,36 @@ func (r HelmOperatorReconciler) updateResource(o runtime.Object) error {
 }
 
 func (r HelmOperatorReconciler) updateResourceStatus(o *unstructured.Unstructured, status *types.HelmAppStatus) error {
+	status.SetReconciled(o.GetGeneration(), !reconcileFailed(status))
+	if r.StandardConditions {
+		status.StandardizeConditions(o.GetGeneration())
+	}
+	// Store the status as unstructured content, which unlike *HelmAppStatus
+	// can be deep copied, ex. by clients that cache o.
+	u, err := status.ToMap()
+	if err != nil {
+		return fmt.Errorf("failed to convert status: %w", err)
+	}
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		o.Object["status"] = status
+		o.Object["status"] = u
 		return r.Client.Status().Update(context.TODO(), o)
 	})
 }
 
+// reconcileFailed returns true if status has a true condition that marks a
+// failed reconcile.
+func reconcileFailed(status *types.HelmAppStatus) bool {
+	for _, c := range status.Conditions {
+		switch c.Type {
+		case types.ConditionReleaseFailed, types.ConditionIrreconcilable, types.ConditionInvalidSpec:
+			if c.Status == types.StatusTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r HelmOperatorReconciler) waitForDeletion(o runtime.Object) error {
 	key, err := client.ObjectKeyFromObject(o)
 	if err != nil {
@@ -15,10 +15,29 @@
 package controller
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	rpb "helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/operator-framework/operator-sdk/internal/helm/internal/types"
+	"github.com/operator-framework/operator-sdk/internal/helm/release"
 )
 
 func TestHasHelmUpgradeForceAnnotation(t *testing.T) {
@@ -77,6 +96,277 @@ func TestHasHelmUpgradeForceAnnotation(t *testing.T) {
 	}
 }
 
+func TestReconcilePeriod(t *testing.T) {
+	const defaultPeriod = time.Minute
+	tests := []struct {
+		input       map[string]interface{}
+		expectedVal time.Duration
+		name        string
+	}{
+		{
+			input: map[string]interface{}{
+				"helm.sdk.operatorframework.io/reconcile-period": "5s",
+			},
+			expectedVal: 5 * time.Second,
+			name:        "base case",
+		},
+		{
+			input: map[string]interface{}{
+				"helm.sdk.operatorframework.io/wrong-annotation": "5s",
+			},
+			expectedVal: defaultPeriod,
+			name:        "annotation not set",
+		},
+		{
+			input: map[string]interface{}{
+				"helm.sdk.operatorframework.io/reconcile-period": "invalid",
+			},
+			expectedVal: defaultPeriod,
+			name:        "invalid value",
+		},
+		{
+			input: map[string]interface{}{
+				"helm.sdk.operatorframework.io/reconcile-period": "-5s",
+			},
+			expectedVal: defaultPeriod,
+			name:        "negative value",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expectedVal, reconcilePeriod(annotations(test.input), defaultPeriod), test.name)
+	}
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		err         error
+		expectedVal types.HelmAppConditionReason
+		name        string
+	}{
+		{
+			err:         errors.New("failed to render chart"),
+			expectedVal: types.ReasonInstallError,
+			name:        "other error",
+		},
+		{
+			err:         fmt.Errorf("failed to install release: %w", wait.ErrWaitTimeout),
+			expectedVal: types.ReasonProgressDeadline,
+			name:        "wrapped wait timeout",
+		},
+		{
+			err: fmt.Errorf("failed to install release: %w",
+				&release.HookFailedError{Err: errors.New("pre-install hooks failed: job failed: BackoffLimitExceeded")}),
+			expectedVal: types.ReasonHookFailed,
+			name:        "wrapped hook failure",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expectedVal, failureReason(test.err, types.ReasonInstallError), test.name)
+	}
+}
+
+func TestHookStatuses(t *testing.T) {
+	started := helmtime.Unix(1600000000, 0)
+	completed := helmtime.Unix(1600000060, 0)
+	hooks := []*rpb.Hook{
+		{
+			Name:    "db-migrate",
+			Kind:    "Job",
+			Events:  []rpb.HookEvent{rpb.HookPreUpgrade},
+			LastRun: rpb.HookExecution{StartedAt: started, CompletedAt: completed, Phase: rpb.HookPhaseSucceeded},
+		},
+		{
+			Name:    "smoke-test",
+			Kind:    "Pod",
+			Events:  []rpb.HookEvent{rpb.HookPostInstall, rpb.HookPostUpgrade},
+			LastRun: rpb.HookExecution{StartedAt: started, Phase: rpb.HookPhaseFailed},
+		},
+		{
+			Name:   "cleanup",
+			Kind:   "Job",
+			Events: []rpb.HookEvent{rpb.HookPreDelete},
+		},
+	}
+
+	startedAt := metav1.NewTime(started.Time)
+	completedAt := metav1.NewTime(completed.Time)
+	expected := []types.HelmAppHook{
+		{
+			Name:        "db-migrate",
+			Kind:        "Job",
+			Events:      []string{"pre-upgrade"},
+			Phase:       "Succeeded",
+			StartedAt:   &startedAt,
+			CompletedAt: &completedAt,
+		},
+		{
+			Name:      "smoke-test",
+			Kind:      "Pod",
+			Events:    []string{"post-install", "post-upgrade"},
+			Phase:     "Failed",
+			StartedAt: &startedAt,
+			Message:   "post-upgrade hooks failed",
+		},
+	}
+	assert.Equal(t, expected, hookStatuses(hooks, "post-upgrade hooks failed"))
+}
+
+func TestSetRolledBack(t *testing.T) {
+	status := &types.HelmAppStatus{}
+	assert.False(t, setRolledBack(status, errors.New("failed to upgrade release")))
+	assert.Empty(t, status.Conditions)
+	assert.Nil(t, status.DeployedRelease)
+
+	rolledBack := &rpb.Release{
+		Name:     "test",
+		Manifest: "manifest",
+		Info:     &rpb.Info{Description: "Rollback to 2"},
+	}
+	err := fmt.Errorf("reconcile: %w", &release.RolledBackError{Release: rolledBack, Err: errors.New("failed to upgrade release")})
+	assert.True(t, setRolledBack(status, err))
+	if assert.Len(t, status.Conditions, 1) {
+		assert.Equal(t, types.ConditionRolledBack, status.Conditions[0].Type)
+		assert.Equal(t, types.StatusTrue, status.Conditions[0].Status)
+		assert.Equal(t, types.ReasonRollbackSuccessful, status.Conditions[0].Reason)
+		assert.Equal(t, "Rollback to 2", status.Conditions[0].Message)
+	}
+	if assert.NotNil(t, status.DeployedRelease) {
+		assert.Equal(t, "test", status.DeployedRelease.Name)
+		assert.Equal(t, "manifest", status.DeployedRelease.Manifest)
+	}
+}
+
+func TestReleaseStatus(t *testing.T) {
+	rel := &rpb.Release{
+		Name:     "test",
+		Version:  3,
+		Manifest: "manifest",
+		Chart:    &chart.Chart{Metadata: &chart.Metadata{Name: "nginx", Version: "1.2.3"}},
+		Config: map[string]interface{}{
+			"replicas": 2,
+			"image":    map[string]interface{}{"tag": "1.19"},
+		},
+	}
+	expected := &types.HelmAppRelease{
+		Name:           "test",
+		Manifest:       "manifest",
+		Revision:       3,
+		ChartName:      "nginx",
+		ChartVersion:   "1.2.3",
+		ValuesChecksum: "c2b07e7c4e8a97ad7772ff1a073a66b94b2d5484eed15577e750a8164a59b9ce",
+		ManifestHash:   "05b3abf2579a5eb66403cd78be557fd860633a1fe2103c7642030defe32c657f",
+	}
+	assert.Equal(t, expected, releaseStatus(rel))
+
+	// A release without a chart still records its revision and hashes.
+	rel.Chart = nil
+	status := releaseStatus(rel)
+	assert.Empty(t, status.ChartName)
+	assert.Empty(t, status.ChartVersion)
+	assert.Equal(t, expected.ValuesChecksum, status.ValuesChecksum)
+}
+
+func TestUninstallSummary(t *testing.T) {
+	manifest := `---
+# Source: nginx/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+---
+# Source: nginx/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+---
+# Source: nginx/templates/metrics-service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx-metrics
+`
+	assert.Equal(t, "Uninstalled 3 resources: 1 Deployment, 2 Service", uninstallSummary(manifest))
+	assert.Equal(t, "Uninstalled 0 resources", uninstallSummary(""))
+}
+
+func TestDeployedRecoveredFromPending(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Nginx"}
+	recovered := types.HelmAppCondition{
+		Type:   types.ConditionRecoveredFromPending,
+		Status: types.StatusTrue,
+		Reason: types.ReasonPendingReleaseRemoved,
+	}
+	newRecoveredStatus := func() *types.HelmAppStatus {
+		return (&types.HelmAppStatus{}).SetCondition(recovered)
+	}
+	newReconciler := func() (HelmOperatorReconciler, *unstructured.Unstructured) {
+		o := &unstructured.Unstructured{}
+		o.SetGroupVersionKind(gvk)
+		o.SetNamespace("default")
+		o.SetName("test")
+		s := runtime.NewScheme()
+		s.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		return HelmOperatorReconciler{GVK: gvk, Client: fake.NewFakeClientWithScheme(s, o.DeepCopy())}, o
+	}
+	log := logf.Log.WithName("test")
+
+	// The condition set by this reconcile is kept.
+	r, o := newReconciler()
+	status := newRecoveredStatus()
+	_, err := r.deployed(log, o, status, true)
+	require.NoError(t, err)
+	assert.True(t, hasCondition(status, recovered))
+
+	// The condition set by an earlier reconcile is removed.
+	r, o = newReconciler()
+	status = newRecoveredStatus()
+	_, err = r.deployed(log, o, status, false)
+	require.NoError(t, err)
+	assert.False(t, hasCondition(status, recovered))
+}
+
+func TestReconcileChartVerificationError(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Nginx"}
+	o := &unstructured.Unstructured{}
+	o.SetGroupVersionKind(gvk)
+	o.SetNamespace("default")
+	o.SetName("test")
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+	c := fake.NewFakeClientWithScheme(s, o)
+	verifyErr := &release.ChartVerificationError{Chart: "nginx-0.1.0.tgz", Err: errors.New("sha256 sum does not match")}
+	r := HelmOperatorReconciler{
+		GVK:    gvk,
+		Client: c,
+		ManagerFactory: managerFactoryFunc(
+			func(*unstructured.Unstructured, map[string]string) (release.Manager, error) { return nil, verifyErr }),
+	}
+
+	key := client.ObjectKey{Namespace: "default", Name: "test"}
+	_, err := r.Reconcile(reconcile.Request{NamespacedName: key})
+	assert.Equal(t, verifyErr, err)
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(gvk)
+	require.NoError(t, c.Get(context.TODO(), key, got))
+	assert.True(t, hasCondition(types.StatusFor(got), types.HelmAppCondition{
+		Type:    types.ConditionIrreconcilable,
+		Reason:  types.ReasonChartVerifyError,
+		Message: verifyErr.Error(),
+	}))
+}
+
+// managerFactoryFunc is a release.ManagerFactory implemented by a function.
+type managerFactoryFunc func(*unstructured.Unstructured, map[string]string) (release.Manager, error)
+
+func (f managerFactoryFunc) NewManager(cr *unstructured.Unstructured,
+	overrideValues map[string]string) (release.Manager, error) {
+	return f(cr, overrideValues)
+}
+
 func annotations(m map[string]interface{}) *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
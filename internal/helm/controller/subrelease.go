@@ -0,0 +1,165 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	rpb "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/operator-framework/operator-sdk/internal/helm/internal/types"
+	"github.com/operator-framework/operator-sdk/internal/helm/release"
+)
+
+// SubRelease configures an additional chart that is installed as a separate
+// release for each custom resource, after the custom resource's own release
+// is deployed.
+type SubRelease struct {
+	// Name identifies the sub-release in the custom resource's status.
+	Name           string
+	ManagerFactory release.ManagerFactory
+	OverrideValues map[string]string
+}
+
+// reconcileSubReleases installs, upgrades, or reconciles each of o's
+// sub-releases and records their statuses in status. If any sub-release
+// fails, status's ReleaseFailed condition is set and an error is returned
+// after the remaining sub-releases are reconciled.
+func (r HelmOperatorReconciler) reconcileSubReleases(log logr.Logger, o *unstructured.Unstructured,
+	status *types.HelmAppStatus) error {
+	var failed []string
+	var firstErr error
+	for _, sub := range r.SubReleases {
+		subStatus := status.SubRelease(sub.Name)
+		rel, reason, err := r.reconcileSubRelease(o, sub)
+		if err != nil {
+			log.Error(err, "Sub-release failed", "subRelease", sub.Name)
+			subStatus.SetCondition(types.HelmAppCondition{
+				Type:    types.ConditionReleaseFailed,
+				Status:  types.StatusTrue,
+				Reason:  failureReason(err, reason),
+				Message: err.Error(),
+			})
+			failed = append(failed, sub.Name)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		subStatus.RemoveCondition(types.ConditionReleaseFailed)
+
+		if r.releaseHook != nil {
+			if err := r.releaseHook(rel); err != nil {
+				return err
+			}
+		}
+
+		message := ""
+		if rel.Info != nil {
+			message = rel.Info.Notes
+		}
+		subStatus.SetCondition(types.HelmAppCondition{
+			Type:    types.ConditionDeployed,
+			Status:  types.StatusTrue,
+			Reason:  reason,
+			Message: message,
+		})
+		subStatus.DeployedRelease = releaseStatus(rel)
+	}
+	if firstErr != nil {
+		status.SetCondition(types.HelmAppCondition{
+			Type:    types.ConditionReleaseFailed,
+			Status:  types.StatusTrue,
+			Reason:  types.ReasonSubReleaseError,
+			Message: fmt.Sprintf("sub-releases failed: %v", failed),
+		})
+	}
+	return firstErr
+}
+
+// reconcileSubRelease installs, upgrades, or reconciles o's release of sub's
+// chart. It returns the deployed release and the reason for its Deployed
+// condition, or, on failure, the reason for its ReleaseFailed condition.
+func (r HelmOperatorReconciler) reconcileSubRelease(o *unstructured.Unstructured,
+	sub SubRelease) (*rpb.Release, types.HelmAppConditionReason, error) {
+	manager, err := sub.ManagerFactory.NewManager(o, sub.OverrideValues)
+	if err != nil {
+		return nil, types.ReasonReconcileError, err
+	}
+	if err := manager.Sync(context.TODO()); err != nil {
+		return nil, types.ReasonReconcileError, err
+	}
+
+	if !manager.IsInstalled() {
+		var installOpts []release.InstallOption
+		if r.InstallTimeout > 0 {
+			installOpts = append(installOpts, release.InstallTimeout(r.InstallTimeout))
+		}
+		rel, err := manager.InstallRelease(context.TODO(), installOpts...)
+		if err != nil {
+			return nil, types.ReasonInstallError, err
+		}
+		return rel, types.ReasonInstallSuccessful, nil
+	}
+
+	if manager.IsUpgradeRequired() {
+		upgradeOpts := []release.UpgradeOption{release.ForceUpgrade(hasHelmUpgradeForceAnnotation(o))}
+		if r.UpgradeTimeout > 0 {
+			upgradeOpts = append(upgradeOpts, release.UpgradeTimeout(r.UpgradeTimeout))
+		}
+		_, rel, err := manager.UpgradeRelease(context.TODO(), upgradeOpts...)
+		if err != nil {
+			return nil, types.ReasonUpgradeError, err
+		}
+		return rel, types.ReasonUpgradeSuccessful, nil
+	}
+
+	rel, err := manager.ReconcileRelease(context.TODO())
+	if err != nil {
+		return nil, types.ReasonReconcileError, err
+	}
+	reason := types.ReasonUpgradeSuccessful
+	if rel.Version == 1 {
+		reason = types.ReasonInstallSuccessful
+	}
+	return rel, reason, nil
+}
+
+// uninstallSubReleases uninstalls o's sub-releases, in reverse order, and
+// removes their statuses from status.
+func (r HelmOperatorReconciler) uninstallSubReleases(log logr.Logger, o *unstructured.Unstructured,
+	status *types.HelmAppStatus) error {
+	for i := len(r.SubReleases) - 1; i >= 0; i-- {
+		sub := r.SubReleases[i]
+		manager, err := sub.ManagerFactory.NewManager(o, sub.OverrideValues)
+		if err != nil {
+			return fmt.Errorf("failed to get manager for sub-release %s: %w", sub.Name, err)
+		}
+		_, err = manager.UninstallRelease(context.TODO())
+		if errors.Is(err, driver.ErrReleaseNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to uninstall sub-release %s: %w", sub.Name, err)
+		}
+		log.Info("Uninstalled sub-release", "subRelease", sub.Name)
+	}
+	status.SubReleases = nil
+	return nil
+}
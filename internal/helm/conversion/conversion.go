@@ -0,0 +1,214 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conversion converts custom resources between the versions of a
+// helm-based API by moving fields according to per-version mappings.
+package conversion
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// VersionMapping maps the fields of a custom resource in Version to their
+// paths in the watched version, whose fields are the chart's values.
+type VersionMapping struct {
+	Version string         `json:"version"`
+	Fields  []FieldMapping `json:"fields,omitempty"`
+}
+
+// FieldMapping moves the field at the dot-separated path From, in a mapped
+// version, to the path To in the watched version.
+type FieldMapping struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// LoadFile reads a list of version mappings from the YAML file at path.
+func LoadFile(path string) ([]VersionMapping, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mappings []VersionMapping
+	if err := yaml.Unmarshal(b, &mappings); err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+// Validate checks that mappings are valid for a kind watched at version
+// watchedVersion.
+func Validate(watchedVersion string, mappings []VersionMapping) error {
+	versions := map[string]struct{}{}
+	for _, m := range mappings {
+		if m.Version == "" {
+			return errors.New("version must not be empty")
+		}
+		if m.Version == watchedVersion {
+			return fmt.Errorf("version %q is the watched version", m.Version)
+		}
+		if _, ok := versions[m.Version]; ok {
+			return fmt.Errorf("duplicate version %q", m.Version)
+		}
+		versions[m.Version] = struct{}{}
+
+		from, to := map[string]struct{}{}, map[string]struct{}{}
+		for _, f := range m.Fields {
+			if err := validatePath(f.From); err != nil {
+				return fmt.Errorf("version %q: invalid from path %q: %w", m.Version, f.From, err)
+			}
+			if err := validatePath(f.To); err != nil {
+				return fmt.Errorf("version %q: invalid to path %q: %w", m.Version, f.To, err)
+			}
+			if _, ok := from[f.From]; ok {
+				return fmt.Errorf("version %q: duplicate from path %q", m.Version, f.From)
+			}
+			if _, ok := to[f.To]; ok {
+				return fmt.Errorf("version %q: duplicate to path %q", m.Version, f.To)
+			}
+			from[f.From], to[f.To] = struct{}{}, struct{}{}
+		}
+	}
+	return nil
+}
+
+func validatePath(path string) error {
+	fields := strings.Split(path, ".")
+	if len(fields) < 2 || fields[0] != "spec" {
+		return errors.New("must be a field of spec")
+	}
+	for _, f := range fields {
+		if f == "" {
+			return errors.New("must not contain empty fields")
+		}
+	}
+	return nil
+}
+
+// Converter converts custom resources of a kind between its watched version
+// and the versions of its mappings. Fields without a mapping are copied
+// unchanged.
+type Converter struct {
+	watched  schema.GroupVersionKind
+	mappings map[string][]FieldMapping
+}
+
+// NewConverter returns a Converter for the kind watched at watched. It
+// assumes that mappings are valid.
+func NewConverter(watched schema.GroupVersionKind, mappings []VersionMapping) *Converter {
+	c := &Converter{
+		watched:  watched,
+		mappings: make(map[string][]FieldMapping, len(mappings)),
+	}
+	for _, m := range mappings {
+		c.mappings[m.Version] = m.Fields
+	}
+	return c
+}
+
+// Convert returns a copy of obj converted to apiVersion. obj is converted to
+// the watched version first, and from there to apiVersion.
+func (c *Converter) Convert(obj *unstructured.Unstructured, apiVersion string) (*unstructured.Unstructured, error) {
+	fromGV, err := schema.ParseGroupVersion(obj.GetAPIVersion())
+	if err != nil {
+		return nil, err
+	}
+	toGV, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	if fromGV.Group != c.watched.Group || toGV.Group != c.watched.Group {
+		return nil, fmt.Errorf("cannot convert %s to %s: group must be %s", obj.GetAPIVersion(), apiVersion,
+			c.watched.Group)
+	}
+	if err := c.checkVersion(fromGV.Version); err != nil {
+		return nil, err
+	}
+	if err := c.checkVersion(toGV.Version); err != nil {
+		return nil, err
+	}
+
+	out := obj.DeepCopy()
+	if err := moveFields(out.Object, c.mappings[fromGV.Version], false); err != nil {
+		return nil, fmt.Errorf("convert %s to %s: %w", fromGV.Version, c.watched.Version, err)
+	}
+	if err := moveFields(out.Object, c.mappings[toGV.Version], true); err != nil {
+		return nil, fmt.Errorf("convert %s to %s: %w", c.watched.Version, toGV.Version, err)
+	}
+	out.SetAPIVersion(apiVersion)
+	return out, nil
+}
+
+func (c *Converter) checkVersion(version string) error {
+	if version == c.watched.Version {
+		return nil
+	}
+	if _, ok := c.mappings[version]; !ok {
+		return fmt.Errorf("no mapping for version %s of %s", version, c.watched.GroupKind())
+	}
+	return nil
+}
+
+// moveFields moves each field in obj from its From path to its To path, or
+// the reverse if reverse is true. All fields are removed before any is set,
+// so that mappings may swap fields.
+func moveFields(obj map[string]interface{}, fields []FieldMapping, reverse bool) error {
+	type move struct {
+		to    []string
+		value interface{}
+	}
+	var moves []move
+	for _, f := range fields {
+		from, to := f.From, f.To
+		if reverse {
+			from, to = to, from
+		}
+		fromPath := strings.Split(from, ".")
+		value, found, err := unstructured.NestedFieldNoCopy(obj, fromPath...)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		removeField(obj, fromPath)
+		moves = append(moves, move{to: strings.Split(to, "."), value: value})
+	}
+	for _, m := range moves {
+		if err := unstructured.SetNestedField(obj, m.value, m.to...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeField removes the field at path from obj, along with any of its
+// parents below the top-level field that are left empty.
+func removeField(obj map[string]interface{}, path []string) {
+	unstructured.RemoveNestedField(obj, path...)
+	for i := len(path) - 1; i > 1; i-- {
+		parent, found, err := unstructured.NestedMap(obj, path[:i]...)
+		if err != nil || !found || len(parent) != 0 {
+			return
+		}
+		unstructured.RemoveNestedField(obj, path[:i]...)
+	}
+}
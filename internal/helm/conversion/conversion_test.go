@@ -0,0 +1,212 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var watchedGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Nginx"}
+
+var testMappings = []VersionMapping{
+	{
+		Version: "v1beta1",
+		Fields: []FieldMapping{
+			{From: "spec.replicas", To: "spec.replicaCount"},
+			{From: "spec.image.tag", To: "spec.tag"},
+		},
+	},
+	{Version: "v1"},
+}
+
+func newNginx(apiVersion string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       "Nginx",
+		"metadata":   map[string]interface{}{"name": "test", "namespace": "default"},
+		"spec":       spec,
+	}}
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mappings []VersionMapping
+		errMsg   string
+	}{
+		{"valid", testMappings, ""},
+		{"empty version", []VersionMapping{{}}, "version must not be empty"},
+		{"watched version", []VersionMapping{{Version: "v1alpha1"}}, `version "v1alpha1" is the watched version`},
+		{"duplicate version", []VersionMapping{{Version: "v1"}, {Version: "v1"}}, `duplicate version "v1"`},
+		{
+			"path outside spec",
+			[]VersionMapping{{Version: "v1", Fields: []FieldMapping{{From: "metadata.name", To: "spec.name"}}}},
+			`version "v1": invalid from path "metadata.name": must be a field of spec`,
+		},
+		{
+			"empty path field",
+			[]VersionMapping{{Version: "v1", Fields: []FieldMapping{{From: "spec.a", To: "spec..b"}}}},
+			`version "v1": invalid to path "spec..b": must not contain empty fields`,
+		},
+		{
+			"duplicate to path",
+			[]VersionMapping{{Version: "v1", Fields: []FieldMapping{
+				{From: "spec.a", To: "spec.c"},
+				{From: "spec.b", To: "spec.c"},
+			}}},
+			`version "v1": duplicate to path "spec.c"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(watchedGVK.Version, tc.mappings)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errMsg)
+			}
+		})
+	}
+}
+
+func TestConverterConvert(t *testing.T) {
+	c := NewConverter(watchedGVK, testMappings)
+
+	watched := newNginx("example.com/v1alpha1", map[string]interface{}{
+		"replicaCount": int64(2),
+		"tag":          "1.19",
+		"service":      map[string]interface{}{"type": "ClusterIP"},
+	})
+	beta := newNginx("example.com/v1beta1", map[string]interface{}{
+		"replicas": int64(2),
+		"image":    map[string]interface{}{"tag": "1.19"},
+		"service":  map[string]interface{}{"type": "ClusterIP"},
+	})
+	v1 := newNginx("example.com/v1", map[string]interface{}{
+		"replicaCount": int64(2),
+		"tag":          "1.19",
+		"service":      map[string]interface{}{"type": "ClusterIP"},
+	})
+
+	testCases := []struct {
+		name       string
+		in         *unstructured.Unstructured
+		apiVersion string
+		expected   *unstructured.Unstructured
+	}{
+		{"to mapped version", watched, "example.com/v1beta1", beta},
+		{"to watched version", beta, "example.com/v1alpha1", watched},
+		{"between mapped versions", beta, "example.com/v1", v1},
+		{"to same version", beta, "example.com/v1beta1", beta},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := tc.in.DeepCopy()
+			out, err := c.Convert(in, tc.apiVersion)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+			assert.Equal(t, tc.in, in, "input must not be modified")
+		})
+	}
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := c.Convert(watched, "example.com/v2")
+		assert.EqualError(t, err, "no mapping for version v2 of Nginx.example.com")
+	})
+	t.Run("other group", func(t *testing.T) {
+		_, err := c.Convert(watched, "other.com/v1beta1")
+		assert.Error(t, err)
+	})
+}
+
+func TestMoveFieldsSwap(t *testing.T) {
+	obj := map[string]interface{}{"spec": map[string]interface{}{"a": "1", "b": "2"}}
+	fields := []FieldMapping{{From: "spec.a", To: "spec.b"}, {From: "spec.b", To: "spec.a"}}
+	require.NoError(t, moveFields(obj, fields, false))
+	assert.Equal(t, map[string]interface{}{"spec": map[string]interface{}{"a": "2", "b": "1"}}, obj)
+}
+
+func TestWebhookServeHTTP(t *testing.T) {
+	wh := NewWebhook()
+	wh.Add(watchedGVK, testMappings)
+
+	doReview := func(t *testing.T, objs ...*unstructured.Unstructured) *apiextv1.ConversionReview {
+		review := apiextv1.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+			Request: &apiextv1.ConversionRequest{
+				UID:               "test-uid",
+				DesiredAPIVersion: "example.com/v1beta1",
+			},
+		}
+		for _, obj := range objs {
+			b, err := obj.MarshalJSON()
+			require.NoError(t, err)
+			review.Request.Objects = append(review.Request.Objects, runtime.RawExtension{Raw: b})
+		}
+		body, err := json.Marshal(review)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		wh.ServeHTTP(w, httptest.NewRequest(http.MethodPost, WebhookPath, bytes.NewReader(body)))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		resp := &apiextv1.ConversionReview{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+		require.NotNil(t, resp.Response)
+		assert.Nil(t, resp.Request)
+		assert.Equal(t, "apiextensions.k8s.io/v1", resp.APIVersion)
+		assert.EqualValues(t, "test-uid", resp.Response.UID)
+		return resp
+	}
+
+	t.Run("success", func(t *testing.T) {
+		resp := doReview(t, newNginx("example.com/v1alpha1", map[string]interface{}{"replicaCount": int64(3)}))
+		assert.Equal(t, metav1.StatusSuccess, resp.Response.Result.Status)
+		require.Len(t, resp.Response.ConvertedObjects, 1)
+
+		out := &unstructured.Unstructured{}
+		require.NoError(t, out.UnmarshalJSON(resp.Response.ConvertedObjects[0].Raw))
+		assert.Equal(t, "example.com/v1beta1", out.GetAPIVersion())
+		replicas, _, _ := unstructured.NestedInt64(out.Object, "spec", "replicas")
+		assert.EqualValues(t, 3, replicas)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		other := newNginx("example.com/v1alpha1", nil)
+		other.SetKind("Apache")
+		resp := doReview(t, other)
+		assert.Equal(t, metav1.StatusFailure, resp.Response.Result.Status)
+		assert.Equal(t, "no conversion for kind Apache.example.com", resp.Response.Result.Message)
+		assert.Empty(t, resp.Response.ConvertedObjects)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		wh.ServeHTTP(w, httptest.NewRequest(http.MethodPost, WebhookPath, bytes.NewBufferString("{}")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// WebhookPath is the path at which the conversion webhook is served.
+const WebhookPath = "/convert"
+
+var log = logf.Log.WithName("helm.conversion")
+
+// Webhook is an http.Handler that serves ConversionReview requests for the
+// kinds of its converters.
+type Webhook struct {
+	converters map[schema.GroupKind]*Converter
+}
+
+var _ http.Handler = &Webhook{}
+
+// NewWebhook returns an empty Webhook.
+func NewWebhook() *Webhook {
+	return &Webhook{converters: map[schema.GroupKind]*Converter{}}
+}
+
+// Add adds a converter for the kind watched at watched.
+func (wh *Webhook) Add(watched schema.GroupVersionKind, mappings []VersionMapping) {
+	wh.converters[watched.GroupKind()] = NewConverter(watched, mappings)
+}
+
+// Len returns the number of kinds that wh converts.
+func (wh *Webhook) Len() int {
+	return len(wh.converters)
+}
+
+// ServeHTTP implements http.Handler. ConversionReviews of both
+// apiextensions.k8s.io/v1 and v1beta1 are accepted, and the response has the
+// same API version as the request.
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &apiextv1.ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		log.Error(err, "Failed to decode conversion request")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "conversion request is empty", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = wh.convert(review.Request)
+	review.Request = nil
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Error(err, "Failed to encode conversion response")
+	}
+}
+
+func (wh *Webhook) convert(req *apiextv1.ConversionRequest) *apiextv1.ConversionResponse {
+	resp := &apiextv1.ConversionResponse{UID: req.UID}
+	for _, raw := range req.Objects {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			return failed(resp, err)
+		}
+		converter, ok := wh.converters[obj.GroupVersionKind().GroupKind()]
+		if !ok {
+			return failed(resp, fmt.Errorf("no conversion for kind %s", obj.GroupVersionKind().GroupKind()))
+		}
+		converted, err := converter.Convert(obj, req.DesiredAPIVersion)
+		if err != nil {
+			return failed(resp, err)
+		}
+		b, err := converted.MarshalJSON()
+		if err != nil {
+			return failed(resp, err)
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: b})
+	}
+	resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return resp
+}
+
+func failed(resp *apiextv1.ConversionResponse, err error) *apiextv1.ConversionResponse {
+	log.Error(err, "Failed to convert objects", "uid", resp.UID)
+	resp.ConvertedObjects = nil
+	resp.Result = metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+	}
+	return resp
+}
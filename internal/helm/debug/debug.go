@@ -0,0 +1,144 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package debug serves the releases deployed by a helm operator, so that
+// developers can see what the operator deployed for a custom resource.
+package debug
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// releasesPath is the path prefix of release endpoints.
+const releasesPath = "/releases/"
+
+// shutdownTimeout is the time given to in-flight requests when the server stops.
+const shutdownTimeout = 5 * time.Second
+
+// Server serves the manifest and values of the deployed revision of release
+// name in namespace at /releases/{namespace}/{name}/manifest and
+// /releases/{namespace}/{name}/values. Releases are read from their storage
+// secrets, so they are served as the operator last deployed them. It
+// implements manager.Runnable.
+type Server struct {
+	// BindAddress is the address the server listens on. It must be a
+	// loopback address, since release manifests and values may contain
+	// secrets.
+	BindAddress string
+	// Client reads release storage secrets.
+	Client corev1client.SecretsGetter
+}
+
+// Validate returns an error if s's bind address is not a loopback address.
+func (s Server) Validate() error {
+	host, _, err := net.SplitHostPort(s.BindAddress)
+	if err != nil {
+		return fmt.Errorf("invalid debug bind address %q: %w", s.BindAddress, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("debug bind address %q must be a loopback address, ex. 127.0.0.1:8082", s.BindAddress)
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, releasesPath), "/")
+	if !strings.HasPrefix(r.URL.Path, releasesPath) || len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	namespace, name, field := parts[0], parts[1], parts[2]
+	if field != "manifest" && field != "values" {
+		http.NotFound(w, r)
+		return
+	}
+
+	store := storage.Init(driver.NewSecrets(s.Client.Secrets(namespace)))
+	rel, err := store.Deployed(name)
+	if err != nil {
+		if errors.Is(err, driver.ErrReleaseNotFound) || strings.Contains(err.Error(), "has no deployed releases") {
+			http.Error(w, fmt.Sprintf("release %s/%s has no deployed revision", namespace, name), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var body []byte
+	switch field {
+	case "manifest":
+		body = []byte(rel.Manifest)
+	case "values":
+		if body, err = yaml.Marshal(rel.Config); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+// Start serves releases until stop is closed.
+func (s Server) Start(stop <-chan struct{}) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", s.BindAddress)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", s.BindAddress, err)
+	}
+	server := &http.Server{Handler: s}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(ctx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that
+// every replica serves its view of releases.
+func (Server) NeedLeaderElection() bool {
+	return false
+}
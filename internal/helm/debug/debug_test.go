@@ -0,0 +1,81 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestServer(t *testing.T) Server {
+	client := fake.NewSimpleClientset()
+	store := storage.Init(driver.NewSecrets(client.CoreV1().Secrets("testns")))
+	require.NoError(t, store.Create(&helmrelease.Release{
+		Name:      "example",
+		Namespace: "testns",
+		Version:   1,
+		Info:      &helmrelease.Info{Status: helmrelease.StatusDeployed},
+		Manifest:  "kind: ConfigMap\n",
+		Config:    map[string]interface{}{"replicaCount": 3},
+	}))
+	return Server{BindAddress: "127.0.0.1:0", Client: client.CoreV1()}
+}
+
+func get(s Server, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestServeRelease(t *testing.T) {
+	s := newTestServer(t)
+
+	w := get(s, "/releases/testns/example/manifest")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "kind: ConfigMap\n", w.Body.String())
+
+	w = get(s, "/releases/testns/example/values")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "replicaCount: 3\n", w.Body.String())
+}
+
+func TestServeReleaseNotFound(t *testing.T) {
+	s := newTestServer(t)
+	assert.Equal(t, http.StatusNotFound, get(s, "/releases/testns/missing/manifest").Code)
+	assert.Equal(t, http.StatusNotFound, get(s, "/releases/testns/example/chart").Code)
+	assert.Equal(t, http.StatusNotFound, get(s, "/releases/testns/manifest").Code)
+	assert.Equal(t, http.StatusNotFound, get(s, "/other/testns/example/manifest").Code)
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/releases/testns/example/manifest", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestServerValidate(t *testing.T) {
+	assert.NoError(t, Server{BindAddress: "127.0.0.1:8082"}.Validate())
+	assert.NoError(t, Server{BindAddress: "localhost:8082"}.Validate())
+	assert.NoError(t, Server{BindAddress: "[::1]:8082"}.Validate())
+	assert.Error(t, Server{BindAddress: ":8082"}.Validate())
+	assert.Error(t, Server{BindAddress: "0.0.0.0:8082"}.Validate())
+	assert.Error(t, Server{BindAddress: "8082"}.Validate())
+}
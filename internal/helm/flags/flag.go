@@ -15,6 +15,8 @@
 package flags
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
 	"time"
 
@@ -26,10 +28,26 @@ type Flags struct {
 	ReconcilePeriod         time.Duration
 	WatchesFile             string
 	MetricsAddress          string
+	MetricsCertFile         string
+	MetricsKeyFile          string
+	MetricsClientCAFile     string
+	MetricsRequireRBAC      bool
+	HealthProbeAddress      string
+	DebugAddress            string
 	EnableLeaderElection    bool
 	LeaderElectionID        string
 	LeaderElectionNamespace string
 	MaxConcurrentReconciles int
+	Offline                 bool
+	LintCharts              bool
+	AuditLogFile            string
+	AuditLogMaxSize         int
+	AuditLogMaxBackups      int
+	ChartCacheDir           string
+	RegistryConfig          string
+	MaxReleaseHistory       int
+	ThrottleBackoffMax      time.Duration
+	StandardConditions      bool
 }
 
 // AddTo - Add the helm operator flags to the the flagset
@@ -44,11 +62,54 @@ func (f *Flags) AddTo(flagSet *pflag.FlagSet) {
 		"./watches.yaml",
 		"Path to the watches file to use",
 	)
+	flagSet.StringVar(&f.MetricsAddress,
+		"metrics-bind-address",
+		":8080",
+		"The address the metric endpoint binds to",
+	)
+	// TODO: remove --metrics-addr in favor of --metrics-bind-address.
 	flagSet.StringVar(&f.MetricsAddress,
 		"metrics-addr",
 		":8080",
 		"The address the metric endpoint binds to",
 	)
+	_ = flagSet.MarkDeprecated("metrics-addr", "use --metrics-bind-address instead")
+	flagSet.StringVar(&f.MetricsCertFile,
+		"metrics-cert-file",
+		"",
+		"Path to a TLS certificate with which metrics are served over HTTPS. Requires --metrics-key-file. "+
+			"If empty, metrics are served over HTTP, or over HTTPS with a self-signed certificate if "+
+			"--metrics-client-ca-file or --metrics-require-rbac is set.",
+	)
+	flagSet.StringVar(&f.MetricsKeyFile,
+		"metrics-key-file",
+		"",
+		"Path to the key of the TLS certificate set by --metrics-cert-file.",
+	)
+	flagSet.StringVar(&f.MetricsClientCAFile,
+		"metrics-client-ca-file",
+		"",
+		"Path to a bundle of CA certificates. If set, metrics clients must present a certificate signed by "+
+			"one of them, unless --metrics-require-rbac is set and they present a bearer token.",
+	)
+	flagSet.BoolVar(&f.MetricsRequireRBAC,
+		"metrics-require-rbac",
+		false,
+		"Authenticate metrics requests by client certificate or bearer token, and authorize them with a "+
+			"SubjectAccessReview of the request path, as kube-rbac-proxy does.",
+	)
+	flagSet.StringVar(&f.HealthProbeAddress,
+		"health-probe-bind-address",
+		":8081",
+		"The address the health probe endpoints, /healthz and /readyz, bind to. Disabled if \"0\".",
+	)
+	flagSet.StringVar(&f.DebugAddress,
+		"debug-bind-address",
+		"",
+		"The loopback address, ex. 127.0.0.1:8082, that a read-only debug endpoint binds to. It serves the "+
+			"manifest and values of each deployed release at /releases/{namespace}/{name}/manifest and "+
+			"/releases/{namespace}/{name}/values. Disabled if empty.",
+	)
 	flagSet.BoolVar(&f.EnableLeaderElection,
 		"enable-leader-election",
 		false,
@@ -69,4 +130,59 @@ func (f *Flags) AddTo(flagSet *pflag.FlagSet) {
 		runtime.NumCPU(),
 		"Maximum number of concurrent reconciles for controllers.",
 	)
+	flagSet.BoolVar(&f.Offline,
+		"offline",
+		false,
+		"Do not download chart dependencies that are declared in Chart.yaml but missing from charts/ at startup.",
+	)
+	flagSet.BoolVar(&f.LintCharts,
+		"lint-charts",
+		false,
+		"Run the rules of 'helm lint' against each chart at startup. Warnings are logged and exported as metrics, "+
+			"and the operator exits if a chart fails a rule.",
+	)
+	flagSet.StringVar(&f.AuditLogFile,
+		"audit-log-file",
+		"",
+		"Path to a file to which a diff of each release install, upgrade, and uninstall is appended as a JSON line. Disabled if empty.",
+	)
+	flagSet.IntVar(&f.AuditLogMaxSize,
+		"audit-log-max-size",
+		100,
+		"Maximum size in megabytes of the audit log file before it is rotated. Rotation is disabled if 0.",
+	)
+	flagSet.IntVar(&f.AuditLogMaxBackups,
+		"audit-log-max-backups",
+		3,
+		"Maximum number of rotated audit log files to retain.",
+	)
+	flagSet.StringVar(&f.ChartCacheDir,
+		"chart-cache-dir",
+		filepath.Join(os.TempDir(), "helm-operator", "charts"),
+		"Directory in which charts pulled from OCI registries are cached.",
+	)
+	flagSet.StringVar(&f.RegistryConfig,
+		"registry-config",
+		"",
+		"Path to a Docker config file containing credentials for OCI registries, such as a mounted "+
+			"kubernetes.io/dockerconfigjson secret. Defaults to Docker's config file if empty.",
+	)
+	flagSet.IntVar(&f.MaxReleaseHistory,
+		"max-release-history",
+		0,
+		"Maximum number of revisions kept for each release. Older revisions are pruned on upgrade. "+
+			"Unlimited if 0. Overridden by a watch's maxHistory.",
+	)
+	flagSet.DurationVar(&f.ThrottleBackoffMax,
+		"throttle-backoff-max",
+		time.Minute,
+		"Maximum time that all release operations are delayed after the API server throttles a request "+
+			"with a 429 Too Many Requests response. Throttling backoff is disabled if 0.",
+	)
+	flagSet.BoolVar(&f.StandardConditions,
+		"standard-conditions",
+		false,
+		"Set status conditions that follow the Kubernetes API conventions of metav1.Condition, with an "+
+			"observedGeneration and a reason on every condition.",
+	)
 }
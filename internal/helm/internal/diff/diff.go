@@ -24,6 +24,16 @@ import (
 
 // Generate generates a diff between a and b, in color.
 func Generate(a, b string) string {
+	return generate(a, b, true)
+}
+
+// Plain generates a diff between a and b without color, for output that is
+// not written to a terminal.
+func Plain(a, b string) string {
+	return generate(a, b, false)
+}
+
+func generate(a, b string, color bool) string {
 	dmp := diffmatchpatch.New()
 
 	wSrc, wDst, warray := dmp.DiffLinesToRunes(a, b)
@@ -35,13 +45,9 @@ func Generate(a, b string) string {
 
 		switch diff.Type {
 		case diffmatchpatch.DiffInsert:
-			_, _ = buff.WriteString("\x1b[32m")
-			_, _ = buff.WriteString(prefixLines(text, "+"))
-			_, _ = buff.WriteString("\x1b[0m")
+			writeColored(&buff, prefixLines(text, "+"), "\x1b[32m", color)
 		case diffmatchpatch.DiffDelete:
-			_, _ = buff.WriteString("\x1b[31m")
-			_, _ = buff.WriteString(prefixLines(text, "-"))
-			_, _ = buff.WriteString("\x1b[0m")
+			writeColored(&buff, prefixLines(text, "-"), "\x1b[31m", color)
 		case diffmatchpatch.DiffEqual:
 			_, _ = buff.WriteString(prefixLines(text, " "))
 		}
@@ -49,6 +55,17 @@ func Generate(a, b string) string {
 	return buff.String()
 }
 
+// writeColored writes s to buff, surrounded by the colorCode and reset escape codes if color is true.
+func writeColored(buff *bytes.Buffer, s, colorCode string, color bool) {
+	if color {
+		_, _ = buff.WriteString(colorCode)
+	}
+	_, _ = buff.WriteString(s)
+	if color {
+		_, _ = buff.WriteString("\x1b[0m")
+	}
+}
+
 func prefixLines(s, prefix string) string {
 	var buf bytes.Buffer
 	lines := strings.Split(s, "\n")
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
 )
 
 type HelmAppList struct {
@@ -46,6 +48,10 @@ type HelmAppCondition struct {
 	Status  ConditionStatus        `json:"status"`
 	Reason  HelmAppConditionReason `json:"reason,omitempty"`
 	Message string                 `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the custom resource that the
+	// condition was set for. It is only set by operators run with
+	// standard conditions.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
@@ -53,6 +59,30 @@ type HelmAppCondition struct {
 type HelmAppRelease struct {
 	Name     string `json:"name,omitempty"`
 	Manifest string `json:"manifest,omitempty"`
+	// Revision is the release's revision number.
+	Revision int `json:"revision,omitempty"`
+	// ChartName and ChartVersion identify the chart the release was
+	// installed or upgraded from.
+	ChartName    string `json:"chartName,omitempty"`
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// ValuesChecksum is the hex-encoded SHA-256 digest of the release's
+	// values, encoded as JSON, and ManifestHash that of its manifest.
+	ValuesChecksum string `json:"valuesChecksum,omitempty"`
+	ManifestHash   string `json:"manifestHash,omitempty"`
+}
+
+// HelmAppHook records the last execution of a chart hook.
+type HelmAppHook struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+	// Events are the release events that run the hook, ex. "pre-upgrade".
+	Events []string `json:"events,omitempty"`
+	// Phase is the phase of the hook's last execution, ex. "Succeeded" or "Failed".
+	Phase       string       `json:"phase,omitempty"`
+	StartedAt   *metav1.Time `json:"startedAt,omitempty"`
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+	// Message explains why the hook failed.
+	Message string `json:"message,omitempty"`
 }
 
 const (
@@ -61,22 +91,75 @@ const (
 	ConditionReleaseFailed  HelmAppConditionType = "ReleaseFailed"
 	ConditionIrreconcilable HelmAppConditionType = "Irreconcilable"
 
+	ConditionRecoveredFromPending HelmAppConditionType = "RecoveredFromPending"
+	ConditionInvalidSpec          HelmAppConditionType = "InvalidSpec"
+	ConditionRolledBack           HelmAppConditionType = "RolledBack"
+
 	StatusTrue    ConditionStatus = "True"
 	StatusFalse   ConditionStatus = "False"
 	StatusUnknown ConditionStatus = "Unknown"
 
-	ReasonInstallSuccessful   HelmAppConditionReason = "InstallSuccessful"
-	ReasonUpgradeSuccessful   HelmAppConditionReason = "UpgradeSuccessful"
-	ReasonUninstallSuccessful HelmAppConditionReason = "UninstallSuccessful"
-	ReasonInstallError        HelmAppConditionReason = "InstallError"
-	ReasonUpgradeError        HelmAppConditionReason = "UpgradeError"
-	ReasonReconcileError      HelmAppConditionReason = "ReconcileError"
-	ReasonUninstallError      HelmAppConditionReason = "UninstallError"
+	ReasonInstallSuccessful     HelmAppConditionReason = "InstallSuccessful"
+	ReasonUpgradeSuccessful     HelmAppConditionReason = "UpgradeSuccessful"
+	ReasonUninstallSuccessful   HelmAppConditionReason = "UninstallSuccessful"
+	ReasonInstallError          HelmAppConditionReason = "InstallError"
+	ReasonUpgradeError          HelmAppConditionReason = "UpgradeError"
+	ReasonReconcileError        HelmAppConditionReason = "ReconcileError"
+	ReasonUninstallError        HelmAppConditionReason = "UninstallError"
+	ReasonChartVerifyError      HelmAppConditionReason = "ChartVerificationError"
+	ReasonPendingReleaseRemoved HelmAppConditionReason = "PendingReleaseRemoved"
+	ReasonProgressDeadline      HelmAppConditionReason = "ProgressDeadlineExceeded"
+	ReasonValuesSchemaViolation HelmAppConditionReason = "ValuesSchemaViolation"
+	ReasonHookFailed            HelmAppConditionReason = "HookFailed"
+	ReasonRollbackSuccessful    HelmAppConditionReason = "RollbackSuccessful"
+	ReasonOperandNamespaceError HelmAppConditionReason = "OperandNamespaceError"
+	ReasonSubReleaseError       HelmAppConditionReason = "SubReleaseError"
+	ReasonMissingPermissions    HelmAppConditionReason = "MissingPermissions"
 )
 
 type HelmAppStatus struct {
 	Conditions      []HelmAppCondition `json:"conditions"`
 	DeployedRelease *HelmAppRelease    `json:"deployedRelease,omitempty"`
+	// Hooks are the chart hooks run by the last install, upgrade, or uninstall.
+	Hooks []HelmAppHook `json:"hooks,omitempty"`
+	// ObservedGeneration is the generation of the custom resource last
+	// reconciled, successfully or not.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastReconcileTime is when the custom resource was last reconciled, and
+	// LastSuccessfulReconcileTime when it was last reconciled without
+	// failing.
+	LastReconcileTime           *metav1.Time `json:"lastReconcileTime,omitempty"`
+	LastSuccessfulReconcileTime *metav1.Time `json:"lastSuccessfulReconcileTime,omitempty"`
+	// SubReleases are the statuses of the releases of the watch's additional
+	// charts, in the order the charts are listed.
+	SubReleases []HelmAppSubRelease `json:"subReleases,omitempty"`
+
+	// set is the types of the conditions set on the status object since it
+	// was read from the custom resource.
+	set conditionTypes
+}
+
+// HelmAppSubRelease is the status of a release of one of a watch's additional
+// charts.
+type HelmAppSubRelease struct {
+	// Name is the name of the additional chart in the watch.
+	Name            string             `json:"name"`
+	Conditions      []HelmAppCondition `json:"conditions,omitempty"`
+	DeployedRelease *HelmAppRelease    `json:"deployedRelease,omitempty"`
+
+	// set is the types of the conditions set on the sub-release status since
+	// it was read from the custom resource.
+	set conditionTypes
+}
+
+// conditionTypes is a set of condition types.
+type conditionTypes map[HelmAppConditionType]bool
+
+func (t *conditionTypes) add(conditionType HelmAppConditionType) {
+	if *t == nil {
+		*t = conditionTypes{}
+	}
+	(*t)[conditionType] = true
 }
 
 func (s *HelmAppStatus) ToMap() (map[string]interface{}, error) {
@@ -95,24 +178,29 @@ func (s *HelmAppStatus) ToMap() (map[string]interface{}, error) {
 // exists, it will be replaced. SetCondition does not update the resource in
 // the cluster.
 func (s *HelmAppStatus) SetCondition(condition HelmAppCondition) *HelmAppStatus {
+	s.Conditions = setCondition(s.Conditions, condition)
+	s.set.add(condition.Type)
+	return s
+}
+
+func setCondition(conditions []HelmAppCondition, condition HelmAppCondition) []HelmAppCondition {
 	now := metav1.Now()
-	for i := range s.Conditions {
-		if s.Conditions[i].Type == condition.Type {
-			if s.Conditions[i].Status != condition.Status {
+	for i := range conditions {
+		if conditions[i].Type == condition.Type {
+			if conditions[i].Status != condition.Status {
 				condition.LastTransitionTime = now
 			} else {
-				condition.LastTransitionTime = s.Conditions[i].LastTransitionTime
+				condition.LastTransitionTime = conditions[i].LastTransitionTime
 			}
-			s.Conditions[i] = condition
-			return s
+			conditions[i] = condition
+			return conditions
 		}
 	}
 
 	// If the condition does not exist,
 	// initialize the lastTransitionTime
 	condition.LastTransitionTime = now
-	s.Conditions = append(s.Conditions, condition)
-	return s
+	return append(conditions, condition)
 }
 
 // RemoveCondition removes the condition with the passed condition type from
@@ -120,15 +208,85 @@ func (s *HelmAppStatus) SetCondition(condition HelmAppCondition) *HelmAppStatus
 // status object is returned unchanged. RemoveCondition does not update the
 // resource in the cluster.
 func (s *HelmAppStatus) RemoveCondition(conditionType HelmAppConditionType) *HelmAppStatus {
-	for i := range s.Conditions {
-		if s.Conditions[i].Type == conditionType {
-			s.Conditions = append(s.Conditions[:i], s.Conditions[i+1:]...)
-			return s
+	s.Conditions = removeCondition(s.Conditions, conditionType)
+	return s
+}
+
+func removeCondition(conditions []HelmAppCondition, conditionType HelmAppConditionType) []HelmAppCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return append(conditions[:i], conditions[i+1:]...)
+		}
+	}
+	return conditions
+}
+
+// SubRelease returns the status of the sub-release with the passed name,
+// adding it to the status object if it is not already present.
+func (s *HelmAppStatus) SubRelease(name string) *HelmAppSubRelease {
+	for i := range s.SubReleases {
+		if s.SubReleases[i].Name == name {
+			return &s.SubReleases[i]
 		}
 	}
+	s.SubReleases = append(s.SubReleases, HelmAppSubRelease{Name: name})
+	return &s.SubReleases[len(s.SubReleases)-1]
+}
+
+// SetCondition sets a condition on the sub-release status. If the condition
+// already exists, it will be replaced.
+func (s *HelmAppSubRelease) SetCondition(condition HelmAppCondition) *HelmAppSubRelease {
+	s.Conditions = setCondition(s.Conditions, condition)
+	s.set.add(condition.Type)
+	return s
+}
+
+// RemoveCondition removes the condition with the passed condition type from
+// the sub-release status.
+func (s *HelmAppSubRelease) RemoveCondition(conditionType HelmAppConditionType) *HelmAppSubRelease {
+	s.Conditions = removeCondition(s.Conditions, conditionType)
+	return s
+}
+
+// SetReconciled records a reconcile of the passed generation of the custom
+// resource at the current time. LastSuccessfulReconcileTime is only updated if
+// the reconcile succeeded. SetReconciled does not update the resource in the
+// cluster.
+func (s *HelmAppStatus) SetReconciled(generation int64, succeeded bool) *HelmAppStatus {
+	now := metav1.Now()
+	s.ObservedGeneration = generation
+	s.LastReconcileTime = &now
+	if succeeded {
+		s.LastSuccessfulReconcileTime = &now
+	}
+	return s
+}
+
+// StandardizeConditions makes the conditions of the status object and its
+// sub-releases follow the Kubernetes API conventions of metav1.Condition:
+// conditions set since the status object was read record the passed
+// generation of the custom resource as their observedGeneration, and
+// conditions without a reason are given k8sutil.DefaultConditionReason.
+// StandardizeConditions does not update the resource in the cluster.
+func (s *HelmAppStatus) StandardizeConditions(generation int64) *HelmAppStatus {
+	standardizeConditions(s.Conditions, s.set, generation)
+	for i := range s.SubReleases {
+		standardizeConditions(s.SubReleases[i].Conditions, s.SubReleases[i].set, generation)
+	}
 	return s
 }
 
+func standardizeConditions(conditions []HelmAppCondition, set conditionTypes, generation int64) {
+	for i := range conditions {
+		if set[conditions[i].Type] {
+			conditions[i].ObservedGeneration = generation
+		}
+		if conditions[i].Reason == "" {
+			conditions[i].Reason = k8sutil.DefaultConditionReason
+		}
+	}
+}
+
 // StatusFor safely returns a typed status block from a custom resource.
 func StatusFor(cr *unstructured.Unstructured) *HelmAppStatus {
 	switch s := cr.Object["status"].(type) {
@@ -16,10 +16,13 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
 )
 
 const (
@@ -59,6 +62,123 @@ func TestRemoveCondition(t *testing.T) {
 	assert.Empty(t, actual.Conditions)
 }
 
+func TestSetReconciled(t *testing.T) {
+	status := newTestStatus().SetReconciled(2, true)
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	assert.NotNil(t, status.LastReconcileTime)
+	assert.Equal(t, status.LastReconcileTime, status.LastSuccessfulReconcileTime)
+
+	lastSuccess := status.LastSuccessfulReconcileTime
+	status = status.SetReconciled(3, false)
+	assert.Equal(t, int64(3), status.ObservedGeneration)
+	assert.NotNil(t, status.LastReconcileTime)
+	assert.Equal(t, lastSuccess, status.LastSuccessfulReconcileTime)
+}
+
+func TestSubRelease(t *testing.T) {
+	status := newTestStatus()
+	status.SubRelease("monitoring").SetCondition(HelmAppCondition{
+		Type:   ConditionDeployed,
+		Status: StatusTrue,
+		Reason: ReasonInstallSuccessful,
+	})
+	status.SubRelease("logging")
+
+	assert.Len(t, status.SubReleases, 2)
+	sub := status.SubRelease("monitoring")
+	assert.Len(t, status.SubReleases, 2)
+	assert.Equal(t, "monitoring", sub.Name)
+	assert.Equal(t, ConditionDeployed, sub.Conditions[0].Type)
+
+	sub.RemoveCondition(ConditionDeployed)
+	assert.Empty(t, status.SubReleases[0].Conditions)
+}
+
+func TestStandardizeConditions(t *testing.T) {
+	status := newTestStatus()
+	status.Conditions = append(status.Conditions, HelmAppCondition{
+		Type:               ConditionIrreconcilable,
+		Status:             StatusFalse,
+		ObservedGeneration: 3,
+	})
+	status.Conditions[0].ObservedGeneration = 3
+	status.SetCondition(HelmAppCondition{
+		Type:   ConditionInitialized,
+		Status: StatusTrue,
+	})
+	status.SubRelease("monitoring").SetCondition(HelmAppCondition{
+		Type:   ConditionDeployed,
+		Status: StatusTrue,
+		Reason: ReasonInstallSuccessful,
+	})
+	logging := status.SubRelease("logging")
+	logging.Conditions = append(logging.Conditions, HelmAppCondition{
+		Type:               ConditionDeployed,
+		Status:             StatusTrue,
+		Reason:             ReasonInstallSuccessful,
+		ObservedGeneration: 3,
+	})
+
+	status.StandardizeConditions(4)
+
+	// Conditions not set since the status was read keep their generation.
+	assert.Equal(t, int64(3), status.Conditions[0].ObservedGeneration)
+	assert.Equal(t, ReasonInstallSuccessful, status.Conditions[0].Reason)
+	assert.Equal(t, int64(3), status.SubReleases[1].Conditions[0].ObservedGeneration)
+
+	// Conditions without a reason are given the default reason.
+	assert.Equal(t, int64(3), status.Conditions[1].ObservedGeneration)
+	assert.Equal(t, HelmAppConditionReason(k8sutil.DefaultConditionReason), status.Conditions[1].Reason)
+	assert.Equal(t, HelmAppConditionReason(k8sutil.DefaultConditionReason), status.Conditions[2].Reason)
+
+	// Conditions set since the status was read record the generation.
+	assert.Equal(t, int64(4), status.Conditions[2].ObservedGeneration)
+	assert.Equal(t, int64(4), status.SubReleases[0].Conditions[0].ObservedGeneration)
+	assert.Equal(t, ReasonInstallSuccessful, status.SubReleases[0].Conditions[0].Reason)
+
+	m, err := status.ToMap()
+	assert.NoError(t, err)
+	conditions := m["conditions"].([]interface{})
+	assert.Equal(t, float64(3), conditions[0].(map[string]interface{})["observedGeneration"])
+	assert.Equal(t, float64(4), conditions[2].(map[string]interface{})["observedGeneration"])
+
+	// The conditions set on a status object are not carried over when the
+	// status is read from the custom resource again.
+	resource := newTestResource()
+	resource.Object["status"] = m
+	status = StatusFor(resource)
+	status.StandardizeConditions(5)
+	assert.Equal(t, int64(4), status.Conditions[2].ObservedGeneration)
+}
+
+func TestStandardizeConditionsTransitionTime(t *testing.T) {
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+	status := &HelmAppStatus{Conditions: []HelmAppCondition{{
+		Type:               ConditionDeployed,
+		Status:             StatusTrue,
+		Reason:             ReasonInstallSuccessful,
+		LastTransitionTime: earlier,
+	}}}
+
+	// The last transition time is kept while the status does not change.
+	status.SetCondition(HelmAppCondition{
+		Type:   ConditionDeployed,
+		Status: StatusTrue,
+		Reason: ReasonUpgradeSuccessful,
+	}).StandardizeConditions(2)
+	assert.Equal(t, earlier, status.Conditions[0].LastTransitionTime)
+	assert.Equal(t, int64(2), status.Conditions[0].ObservedGeneration)
+
+	// The last transition time is updated when the status changes.
+	status.SetCondition(HelmAppCondition{
+		Type:   ConditionDeployed,
+		Status: StatusFalse,
+		Reason: ReasonUninstallSuccessful,
+	}).StandardizeConditions(3)
+	assert.True(t, earlier.Before(&status.Conditions[0].LastTransitionTime))
+	assert.Equal(t, int64(3), status.Conditions[0].ObservedGeneration)
+}
+
 func TestStatusForEmpty(t *testing.T) {
 	status := StatusFor(newTestResource())
 
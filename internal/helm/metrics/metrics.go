@@ -0,0 +1,253 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	subsystem = "helm_operator"
+)
+
+var (
+	reconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "reconcile_total",
+			Help:      "Total number of reconciles by result.",
+		},
+		[]string{
+			"GVK",
+			"result",
+		})
+
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: subsystem,
+			Name:      "reconcile_duration_seconds",
+			Help:      "How long in seconds a reconcile takes.",
+		},
+		[]string{
+			"GVK",
+		})
+
+	releaseInstallDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: subsystem,
+			Name:      "release_install_duration_seconds",
+			Help:      "How long in seconds a release install takes.",
+		},
+		[]string{
+			"GVK",
+		})
+
+	releaseUpgradeDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: subsystem,
+			Name:      "release_upgrade_duration_seconds",
+			Help:      "How long in seconds a release upgrade takes.",
+		},
+		[]string{
+			"GVK",
+		})
+
+	releaseInstallFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "release_install_failures_total",
+			Help:      "Total number of failed release installs.",
+		},
+		[]string{
+			"GVK",
+		})
+
+	releaseUpgradeFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "release_upgrade_failures_total",
+			Help:      "Total number of failed release upgrades.",
+		},
+		[]string{
+			"GVK",
+		})
+
+	reconcilesActive = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "reconciles_active",
+			Help:      "Number of reconciles in progress.",
+		},
+		[]string{
+			"GVK",
+		})
+
+	maxConcurrentReconciles = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "max_concurrent_reconciles",
+			Help:      "Maximum number of concurrent reconciles.",
+		},
+		[]string{
+			"GVK",
+		})
+
+	controllerInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "controller_info",
+			Help:      "Name of the controller of each GVK, as used by the controller-runtime metrics. Always 1.",
+		},
+		[]string{
+			"GVK",
+			"controller",
+		})
+
+	chartLintWarnings = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "chart_lint_warnings",
+			Help:      "Number of lint warnings of each chart when it was loaded.",
+		},
+		[]string{
+			"GVK",
+			"chart",
+		})
+
+	apiThrottledTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "api_throttled_total",
+			Help:      "Total number of API server responses with status 429 Too Many Requests.",
+		})
+
+	apiThrottleBackoff = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "api_throttle_backoff_seconds",
+			Help:      "How long in seconds API requests are delayed after the last throttled response, or 0 if not throttled.",
+		})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileTotal)
+	metrics.Registry.MustRegister(reconcileDuration)
+	metrics.Registry.MustRegister(releaseInstallDuration)
+	metrics.Registry.MustRegister(releaseUpgradeDuration)
+	metrics.Registry.MustRegister(releaseInstallFailures)
+	metrics.Registry.MustRegister(releaseUpgradeFailures)
+	metrics.Registry.MustRegister(reconcilesActive)
+	metrics.Registry.MustRegister(maxConcurrentReconciles)
+	metrics.Registry.MustRegister(controllerInfo)
+	metrics.Registry.MustRegister(chartLintWarnings)
+	metrics.Registry.MustRegister(apiThrottledTotal)
+	metrics.Registry.MustRegister(apiThrottleBackoff)
+}
+
+// We will never want to panic our app because of metric saving.
+// Therefore, we will recover our panics here and error log them
+// for later diagnosis but will never fail the app.
+func recoverMetricPanic() {
+	if r := recover(); r != nil {
+		logf.Log.WithName("metrics").Error(fmt.Errorf("%v", r),
+			"Recovering from metric function")
+	}
+}
+
+func ReconcileSucceeded(gvk string) {
+	defer recoverMetricPanic()
+	reconcileTotal.WithLabelValues(gvk, "succeeded").Inc()
+}
+
+func ReconcileFailed(gvk string) {
+	defer recoverMetricPanic()
+	reconcileTotal.WithLabelValues(gvk, "failed").Inc()
+}
+
+func ReconcileTimer(gvk string) *prometheus.Timer {
+	defer recoverMetricPanic()
+	return newTimer(reconcileDuration, gvk)
+}
+
+// ReconcileStarted records the start of a reconcile of gvk.
+func ReconcileStarted(gvk string) {
+	defer recoverMetricPanic()
+	reconcilesActive.WithLabelValues(gvk).Inc()
+}
+
+// ReconcileFinished records the end of a reconcile of gvk.
+func ReconcileFinished(gvk string) {
+	defer recoverMetricPanic()
+	reconcilesActive.WithLabelValues(gvk).Dec()
+}
+
+// ControllerAdded records the name and reconcile capacity of the controller
+// of gvk, so that controller-runtime's workqueue and reconcile metrics, which
+// are labeled by controller name, can be joined with the metrics of gvk.
+func ControllerAdded(gvk, controller string, maxReconciles int) {
+	defer recoverMetricPanic()
+	controllerInfo.WithLabelValues(gvk, controller).Set(1)
+	maxConcurrentReconciles.WithLabelValues(gvk).Set(float64(maxReconciles))
+}
+
+func ReleaseInstallTimer(gvk string) *prometheus.Timer {
+	defer recoverMetricPanic()
+	return newTimer(releaseInstallDuration, gvk)
+}
+
+func ReleaseUpgradeTimer(gvk string) *prometheus.Timer {
+	defer recoverMetricPanic()
+	return newTimer(releaseUpgradeDuration, gvk)
+}
+
+func ReleaseInstallFailed(gvk string) {
+	defer recoverMetricPanic()
+	releaseInstallFailures.WithLabelValues(gvk).Inc()
+}
+
+func ReleaseUpgradeFailed(gvk string) {
+	defer recoverMetricPanic()
+	releaseUpgradeFailures.WithLabelValues(gvk).Inc()
+}
+
+// ChartLinted records the number of lint warnings of the chart of gvk.
+func ChartLinted(gvk, chart string, warnings int) {
+	defer recoverMetricPanic()
+	chartLintWarnings.WithLabelValues(gvk, chart).Set(float64(warnings))
+}
+
+// APIThrottled records a throttled API response and the backoff it caused.
+func APIThrottled(backoff time.Duration) {
+	defer recoverMetricPanic()
+	apiThrottledTotal.Inc()
+	apiThrottleBackoff.Set(backoff.Seconds())
+}
+
+// APIThrottleReset records that API requests are no longer throttled.
+func APIThrottleReset() {
+	defer recoverMetricPanic()
+	apiThrottleBackoff.Set(0)
+}
+
+func newTimer(h *prometheus.HistogramVec, gvk string) *prometheus.Timer {
+	return prometheus.NewTimer(prometheus.ObserverFunc(func(duration float64) {
+		h.WithLabelValues(gvk).Observe(duration)
+	}))
+}
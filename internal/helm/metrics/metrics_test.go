@@ -0,0 +1,73 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileResults(t *testing.T) {
+	gvk := "cache.example.com/v1alpha1, Kind=Memcached"
+	ReconcileSucceeded(gvk)
+	ReconcileSucceeded(gvk)
+	ReconcileFailed(gvk)
+	assert.Equal(t, 2.0, testutil.ToFloat64(reconcileTotal.WithLabelValues(gvk, "succeeded")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(reconcileTotal.WithLabelValues(gvk, "failed")))
+}
+
+func TestReconcilesActive(t *testing.T) {
+	gvk := "cache.example.com/v1alpha1, Kind=Redis"
+	ReconcileStarted(gvk)
+	ReconcileStarted(gvk)
+	assert.Equal(t, 2.0, testutil.ToFloat64(reconcilesActive.WithLabelValues(gvk)))
+	ReconcileFinished(gvk)
+	assert.Equal(t, 1.0, testutil.ToFloat64(reconcilesActive.WithLabelValues(gvk)))
+}
+
+func TestControllerAdded(t *testing.T) {
+	gvk := "cache.example.com/v1alpha1, Kind=Memcached"
+	ControllerAdded(gvk, "memcached-controller", 4)
+	assert.Equal(t, 1.0, testutil.ToFloat64(controllerInfo.WithLabelValues(gvk, "memcached-controller")))
+	assert.Equal(t, 4.0, testutil.ToFloat64(maxConcurrentReconciles.WithLabelValues(gvk)))
+}
+
+func TestReleaseFailures(t *testing.T) {
+	gvk := "cache.example.com/v1alpha1, Kind=Nginx"
+	ReleaseInstallFailed(gvk)
+	ReleaseUpgradeFailed(gvk)
+	ReleaseUpgradeFailed(gvk)
+	assert.Equal(t, 1.0, testutil.ToFloat64(releaseInstallFailures.WithLabelValues(gvk)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(releaseUpgradeFailures.WithLabelValues(gvk)))
+}
+
+func TestChartLinted(t *testing.T) {
+	gvk := "cache.example.com/v1alpha1, Kind=Memcached"
+	ChartLinted(gvk, "helm-charts/memcached", 2)
+	assert.Equal(t, 2.0, testutil.ToFloat64(chartLintWarnings.WithLabelValues(gvk, "helm-charts/memcached")))
+}
+
+func TestAPIThrottled(t *testing.T) {
+	before := testutil.ToFloat64(apiThrottledTotal)
+	APIThrottled(2 * time.Second)
+	assert.Equal(t, before+1, testutil.ToFloat64(apiThrottledTotal))
+	assert.Equal(t, 2.0, testutil.ToFloat64(apiThrottleBackoff))
+
+	APIThrottleReset()
+	assert.Equal(t, 0.0, testutil.ToFloat64(apiThrottleBackoff))
+}
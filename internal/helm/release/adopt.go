@@ -0,0 +1,126 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"helm.sh/helm/v3/pkg/kube"
+	rpb "helm.sh/helm/v3/pkg/release"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// The ownership metadata that Helm requires of an existing resource to adopt
+// it into a release.
+const (
+	managedByLabel             = "app.kubernetes.io/managed-by"
+	managedByHelm              = "Helm"
+	releaseNameAnnotation      = "meta.helm.sh/release-name"
+	releaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// AdoptExistingResources configures whether a ManagerFactory's Managers take
+// ownership of existing resources that a release install or upgrade would
+// create, instead of failing because they already exist. Adopted resources
+// are updated to match the release's manifest, including owner references to
+// the custom resource. Resources that belong to another Helm release are
+// never adopted.
+func AdoptExistingResources(adopt bool) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.adoptResources = adopt
+	}
+}
+
+// adoptingClient is a kube client that prepares existing resources to be
+// adopted into a release. Helm only adopts resources that already have the
+// release's ownership metadata, so adoptingClient adds that metadata to the
+// existing resources of each manifest it builds, other than hooks.
+type adoptingClient struct {
+	kube.Interface
+	releaseName      string
+	releaseNamespace string
+}
+
+func (c *adoptingClient) Build(reader io.Reader, validate bool) (kube.ResourceList, error) {
+	resources, err := c.Interface.Build(reader, validate)
+	if err != nil {
+		return nil, err
+	}
+	if err := resources.Visit(c.adopt); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// adopt adds the release's ownership metadata to the resource of info, if it
+// exists and does not belong to a release.
+func (c *adoptingClient) adopt(info *resource.Info, err error) error {
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(info.Object)
+	if err != nil {
+		return err
+	}
+	if _, ok := accessor.GetAnnotations()[rpb.HookAnnotation]; ok {
+		return nil
+	}
+
+	helper := resource.NewHelper(info.Client, info.Mapping)
+	existing, err := helper.Get(info.Namespace, info.Name, false)
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get %s: %w", info.ObjectName(), err)
+	}
+	existingAccessor, err := meta.Accessor(existing)
+	if err != nil {
+		return err
+	}
+	if _, ok := existingAccessor.GetAnnotations()[releaseNameAnnotation]; ok {
+		// Helm adopts the resource if it belongs to this release, and fails
+		// if it belongs to another.
+		return nil
+	}
+
+	if _, err := helper.Patch(info.Namespace, info.Name, apitypes.MergePatchType, c.adoptPatch(),
+		&metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to adopt %s: %w", info.ObjectName(), err)
+	}
+	return nil
+}
+
+// adoptPatch returns a JSON merge patch that adds the release's ownership
+// metadata to a resource.
+func (c *adoptingClient) adoptPatch() []byte {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				managedByLabel: managedByHelm,
+			},
+			"annotations": map[string]interface{}{
+				releaseNameAnnotation:      c.releaseName,
+				releaseNamespaceAnnotation: c.releaseNamespace,
+			},
+		},
+	})
+	return patch
+}
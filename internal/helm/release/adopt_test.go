@@ -0,0 +1,59 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/kube"
+	"k8s.io/client-go/rest"
+)
+
+func TestAdoptPatch(t *testing.T) {
+	c := &adoptingClient{releaseName: "example", releaseNamespace: "default"}
+	assert.JSONEq(t, `{"metadata": {
+		"labels": {"app.kubernetes.io/managed-by": "Helm"},
+		"annotations": {"meta.helm.sh/release-name": "example", "meta.helm.sh/release-namespace": "default"}
+	}}`, string(c.adoptPatch()))
+}
+
+func TestManagerDeployConfig(t *testing.T) {
+	kubeClient := &kube.Client{}
+	m := manager{
+		actionConfig: &action.Configuration{KubeClient: kubeClient},
+		kubeClient:   kubeClient,
+		releaseName:  "example",
+		namespace:    "default",
+	}
+	assert.Same(t, m.actionConfig, m.deployConfig())
+
+	m.adoptResources = true
+	cfg := m.deployConfig()
+	assert.Equal(t, &adoptingClient{Interface: kubeClient, releaseName: "example", releaseNamespace: "default"},
+		cfg.KubeClient)
+	assert.Same(t, kubeClient, m.actionConfig.KubeClient, "manager's action config must not be modified")
+}
+
+func TestManagerFactoryAdoptExistingResources(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	assert.False(t, f.adoptResources)
+
+	f = NewManagerFactory(mgr, "chart", AdoptExistingResources(true)).(*managerFactory)
+	assert.True(t, f.adoptResources)
+}
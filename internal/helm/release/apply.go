@@ -0,0 +1,139 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// PatchStrategy is how a Manager corrects drift between a release's manifest
+// and the release's resources in the cluster.
+type PatchStrategy string
+
+const (
+	// PatchStrategyMerge patches each resource with the difference between
+	// its manifest and its live state, using a strategic merge patch for
+	// built-in kinds and a JSON patch for other kinds. Every field set by
+	// the manifest is reset, even if another controller manages it.
+	PatchStrategyMerge PatchStrategy = "Merge"
+	// PatchStrategyServerSideApply server-side applies each resource's
+	// manifest. Fields that another field manager has taken ownership of,
+	// such as replicas managed by a HorizontalPodAutoscaler, are left
+	// unchanged.
+	PatchStrategyServerSideApply PatchStrategy = "ServerSideApply"
+	// PatchStrategyReplace deletes each drifted resource and re-creates it
+	// from its manifest. It corrects drift in fields that cannot be patched
+	// because they are immutable, such as the pod template of a Job.
+	PatchStrategyReplace PatchStrategy = "Replace"
+)
+
+// patchStrategies are the patch strategies of a release's resources: a
+// default strategy, and overrides for resources of specific kinds.
+type patchStrategies struct {
+	defaultStrategy PatchStrategy
+	kinds           map[schema.GroupKind]PatchStrategy
+}
+
+// forKind returns the patch strategy of resources of kind gk.
+func (s patchStrategies) forKind(gk schema.GroupKind) PatchStrategy {
+	if strategy, ok := s.kinds[gk]; ok {
+		return strategy
+	}
+	return s.defaultStrategy
+}
+
+// fieldManager is the field manager of resources applied by a Manager.
+const fieldManager = "helm-operator"
+
+// applyServerSide server-side applies expected. If the apply conflicts with
+// fields owned by other field managers, it is retried once without those
+// fields.
+func applyServerSide(helper *resource.Helper, expected *resource.Info) error {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(expected.Object)
+	if err != nil {
+		return err
+	}
+	for retried := false; ; retried = true {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		_, err = helper.Patch(expected.Namespace, expected.Name, apitypes.ApplyPatchType, data,
+			&metav1.PatchOptions{FieldManager: fieldManager})
+		if err == nil {
+			return nil
+		}
+		if retried || !apierrors.IsConflict(err) {
+			return fmt.Errorf("apply error: %w", err)
+		}
+		fields, ferr := conflictingFields(err)
+		if ferr != nil {
+			return fmt.Errorf("apply error: %v: %w", ferr, err)
+		}
+		for _, field := range fields {
+			unstructured.RemoveNestedField(obj, field...)
+		}
+	}
+}
+
+// replace deletes the existing resource of expected and re-creates it from
+// expected. If the existing resource has not been removed yet, for example
+// because it has finalizers, the create fails and is retried by the next
+// reconcile.
+func replace(helper *resource.Helper, expected *resource.Info) error {
+	policy := metav1.DeletePropagationBackground
+	if _, err := helper.DeleteWithOptions(expected.Namespace, expected.Name,
+		&metav1.DeleteOptions{PropagationPolicy: &policy}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("replace error: %w", err)
+	}
+	if _, err := helper.Create(expected.Namespace, true, expected.Object); err != nil {
+		return fmt.Errorf("replace error: %w", err)
+	}
+	return nil
+}
+
+// conflictingFields returns the paths of the fields in an apply conflict
+// error. Only paths through maps, ex. ".spec.replicas", are supported.
+func conflictingFields(err error) ([][]string, error) {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil, errors.New("no conflicting fields found")
+	}
+	var fields [][]string
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		if strings.ContainsAny(cause.Field, "[]") {
+			return nil, fmt.Errorf("unsupported conflicting field %q", cause.Field)
+		}
+		fields = append(fields, strings.Split(strings.TrimPrefix(cause.Field, "."), "."))
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("no conflicting fields found")
+	}
+	return fields, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newConflictError(fields ...string) error {
+	causes := []metav1.StatusCause{}
+	for _, f := range fields {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kube-controller-manager"`,
+			Field:   f,
+		})
+	}
+	err := apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "test",
+		errors.New("apply failed"))
+	err.ErrStatus.Details.Causes = causes
+	return err
+}
+
+func TestConflictingFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		expFields [][]string
+		expErr    bool
+	}{
+		{
+			name:      "single field",
+			err:       newConflictError(".spec.replicas"),
+			expFields: [][]string{{"spec", "replicas"}},
+		},
+		{
+			name:      "wrapped error with multiple fields",
+			err:       fmt.Errorf("patch error: %w", newConflictError(".spec.replicas", ".metadata.labels.app")),
+			expFields: [][]string{{"spec", "replicas"}, {"metadata", "labels", "app"}},
+		},
+		{
+			name:   "list field",
+			err:    newConflictError(`.spec.template.spec.containers[name="nginx"].image`),
+			expErr: true,
+		},
+		{
+			name:   "no causes",
+			err:    newConflictError(),
+			expErr: true,
+		},
+		{
+			name:   "not a status error",
+			err:    errors.New("conflict"),
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields, err := conflictingFields(test.err)
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expFields, fields)
+		})
+	}
+}
+
+func TestPatchStrategiesForKind(t *testing.T) {
+	job := schema.GroupKind{Group: "batch", Kind: "Job"}
+	s := patchStrategies{
+		defaultStrategy: PatchStrategyMerge,
+		kinds:           map[schema.GroupKind]PatchStrategy{job: PatchStrategyReplace},
+	}
+	assert.Equal(t, PatchStrategyReplace, s.forKind(job))
+	assert.Equal(t, PatchStrategyMerge, s.forKind(schema.GroupKind{Group: "apps", Kind: "Deployment"}))
+	assert.Equal(t, PatchStrategyMerge, s.forKind(schema.GroupKind{Kind: "Job"}))
+
+	assert.Equal(t, PatchStrategy(""), patchStrategies{}.forKind(job))
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+)
+
+// BuildDependencies downloads the dependencies declared in the Chart.yaml of
+// the chart directory chartDir that are missing from its charts/ directory,
+// as `helm dependency build` does. It returns true if dependencies were
+// downloaded. Chart archives are skipped, since their dependencies must be
+// packaged with them.
+func BuildDependencies(chartDir string) (bool, error) {
+	info, err := os.Stat(chartDir)
+	if err != nil {
+		return false, err
+	}
+	if !info.IsDir() {
+		return false, nil
+	}
+
+	chrt, err := loader.LoadDir(chartDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to load chart %s: %w", chartDir, err)
+	}
+	if len(chrt.Metadata.Dependencies) == 0 {
+		return false, nil
+	}
+	if err := action.CheckDependencies(chrt, chrt.Metadata.Dependencies); err == nil {
+		return false, nil
+	}
+
+	settings := cli.New()
+	out := &bytes.Buffer{}
+	man := &downloader.Manager{
+		Out:              out,
+		ChartPath:        chartDir,
+		Getters:          getter.All(settings),
+		RepositoryConfig: settings.RepositoryConfig,
+		RepositoryCache:  settings.RepositoryCache,
+	}
+	if err := man.Build(); err != nil {
+		return false, fmt.Errorf("failed to build dependencies of chart %s: %w: %s", chartDir, err,
+			strings.TrimSpace(out.String()))
+	}
+	return true, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const childDependency = `dependencies:
+- name: child
+  version: 0.1.0
+  repository: file://../child
+`
+
+func TestBuildDependencies(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "dependencies-test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	// Keep helm from reading the user's repositories.
+	for k, v := range map[string]string{
+		"HELM_REPOSITORY_CONFIG": filepath.Join(tmpDir, "repositories.yaml"),
+		"HELM_REPOSITORY_CACHE":  filepath.Join(tmpDir, "repository"),
+	} {
+		old, ok := os.LookupEnv(k)
+		require.NoError(t, os.Setenv(k, v))
+		if ok {
+			defer os.Setenv(k, old)
+		} else {
+			defer os.Unsetenv(k)
+		}
+	}
+	writeTestChart(t, filepath.Join(tmpDir, "child"), "child", "")
+
+	t.Run("chart archive is skipped", func(t *testing.T) {
+		built, err := BuildDependencies(filepath.Join(testChartDir, "..", "test-chart-1.2.3.tgz"))
+		assert.NoError(t, err)
+		assert.False(t, built)
+	})
+
+	t.Run("chart without dependencies", func(t *testing.T) {
+		built, err := BuildDependencies(testChartDir)
+		assert.NoError(t, err)
+		assert.False(t, built)
+	})
+
+	t.Run("vendored dependencies", func(t *testing.T) {
+		dir := filepath.Join(tmpDir, "vendored")
+		writeTestChart(t, dir, "vendored", childDependency)
+		writeTestChart(t, filepath.Join(dir, "charts", "child"), "child", "")
+
+		built, err := BuildDependencies(dir)
+		assert.NoError(t, err)
+		assert.False(t, built)
+	})
+
+	t.Run("file dependency is built", func(t *testing.T) {
+		dir := filepath.Join(tmpDir, "parent")
+		writeTestChart(t, dir, "parent", childDependency)
+
+		built, err := BuildDependencies(dir)
+		require.NoError(t, err)
+		assert.True(t, built)
+		assert.FileExists(t, filepath.Join(dir, "charts", "child-0.1.0.tgz"))
+
+		// Once built, the dependencies are not built again.
+		built, err = BuildDependencies(dir)
+		assert.NoError(t, err)
+		assert.False(t, built)
+	})
+
+	t.Run("missing chart", func(t *testing.T) {
+		_, err := BuildDependencies(filepath.Join(tmpDir, "missing"))
+		assert.Error(t, err)
+	})
+}
+
+// writeTestChart writes a chart named name to dir, appending extra to its Chart.yaml.
+func writeTestChart(t *testing.T, dir, name, extra string) {
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0755))
+	chartYAML := "apiVersion: v2\nname: " + name + "\nversion: 0.1.0\n" + extra
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(chartYAML), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "values.yaml"), nil, 0644))
+	configMap := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "templates", "configmap.yaml"), []byte(configMap), 0644))
+}
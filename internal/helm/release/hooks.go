@@ -0,0 +1,49 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	rpb "helm.sh/helm/v3/pkg/release"
+)
+
+// HookFailedError is returned by a Manager when a chart hook fails during an
+// install, upgrade, or uninstall. Hooks are the release's hooks, including the
+// failed hook, as last run.
+type HookFailedError struct {
+	Hooks []*rpb.Hook
+	Err   error
+}
+
+func (e *HookFailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HookFailedError) Unwrap() error {
+	return e.Err
+}
+
+// hookError returns a *HookFailedError wrapping err if a hook of rel failed,
+// and err otherwise.
+func hookError(rel *rpb.Release, err error) error {
+	if rel == nil {
+		return err
+	}
+	for _, h := range rel.Hooks {
+		if h.LastRun.Phase == rpb.HookPhaseFailed {
+			return &HookFailedError{Hooks: rel.Hooks, Err: err}
+		}
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/lint"
+	"helm.sh/helm/v3/pkg/lint/support"
+)
+
+// LintChart runs the rules of `helm lint` against the chart directory or
+// archive at chartPath with the chart's default values. It returns the
+// messages of rules that warn, and an error listing the messages of rules
+// that fail, if any do.
+func LintChart(chartPath string) ([]string, error) {
+	info, err := os.Stat(chartPath)
+	if err != nil {
+		return nil, err
+	}
+	chartDir := chartPath
+	if !info.IsDir() {
+		tmpDir, err := ioutil.TempDir("", "helm-operator-lint-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create lint directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+		if err := chartutil.ExpandFile(tmpDir, chartPath); err != nil {
+			return nil, fmt.Errorf("failed to expand chart archive: %w", err)
+		}
+		if chartDir, err = findChartDir(tmpDir); err != nil {
+			return nil, err
+		}
+	}
+
+	linter := lint.All(chartDir, nil, "", false)
+	var warnings, errs []string
+	for _, msg := range linter.Messages {
+		text := msg.Err.Error()
+		if msg.Path != "" {
+			text = filepath.ToSlash(msg.Path) + ": " + text
+		}
+		switch msg.Severity {
+		case support.WarningSev:
+			warnings = append(warnings, text)
+		case support.ErrorSev:
+			errs = append(errs, text)
+		}
+	}
+	if len(errs) != 0 {
+		return warnings, fmt.Errorf("chart %s failed lint: %s", chartPath, strings.Join(errs, "; "))
+	}
+	return warnings, nil
+}
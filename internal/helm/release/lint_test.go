@@ -0,0 +1,55 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintChart(t *testing.T) {
+	_, err := LintChart(testChartDir)
+	assert.NoError(t, err)
+
+	_, err = LintChart(filepath.Join(testChartDir, "..", "test-chart-1.2.3.tgz"))
+	assert.NoError(t, err)
+}
+
+func TestLintChartWarningsAndErrors(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "lint-test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	dir := filepath.Join(tmpDir, "lint-test")
+	require.NoError(t, os.Mkdir(dir, 0755))
+	chartYAML := filepath.Join(dir, "Chart.yaml")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "values.yaml"), nil, 0644))
+
+	// A chart without templates only warns.
+	require.NoError(t, ioutil.WriteFile(chartYAML, []byte("apiVersion: v2\nname: lint-test\nversion: 0.1.0\n"), 0644))
+	warnings, err := LintChart(dir)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, warnings)
+
+	// A chart without a version fails.
+	require.NoError(t, ioutil.WriteFile(chartYAML, []byte("apiVersion: v2\nname: lint-test\n"), 0644))
+	_, err = LintChart(dir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "version")
+}
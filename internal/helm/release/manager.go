@@ -21,10 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	jsonpatch "gomodules.xyz/jsonpatch/v3"
 	"helm.sh/helm/v3/pkg/action"
 	cpb "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/kube"
 	helmkube "helm.sh/helm/v3/pkg/kube"
 	rpb "helm.sh/helm/v3/pkg/release"
@@ -36,18 +38,26 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apitypes "k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/resource"
 
 	"github.com/operator-framework/operator-sdk/internal/helm/internal/types"
 )
 
 // Manager manages a Helm release. It can install, upgrade, reconcile,
-// and uninstall a release.
+// and uninstall a release. Its read-only methods expose the release's state
+// to callers, such as custom controllers reporting status or drift.
 type Manager interface {
 	ReleaseName() string
 	IsInstalled() bool
 	IsUpgradeRequired() bool
+	RecoveredPendingRelease() *rpb.Release
+	GetRelease() *rpb.Release
+	GetValues() (map[string]interface{}, error)
+	GetChartMetadata() *cpb.Metadata
+	ValidateValues() error
 	Sync(context.Context) error
 	InstallRelease(context.Context, ...InstallOption) (*rpb.Release, error)
 	UpgradeRelease(context.Context, ...UpgradeOption) (*rpb.Release, *rpb.Release, error)
@@ -62,6 +72,18 @@ type manager struct {
 
 	releaseName string
 	namespace   string
+	// maxHistory is the maximum number of revisions kept by upgrades and
+	// rollbacks, or 0 for no limit.
+	maxHistory int
+	// patchStrategies are how ReconcileRelease corrects drift.
+	patchStrategies patchStrategies
+	// uninstallPolicy is how UninstallRelease deletes the release's
+	// resources, which are owned by the custom resource with ownerUID.
+	uninstallPolicy UninstallPolicy
+	ownerUID        apitypes.UID
+	// adoptResources is whether installs and upgrades adopt existing
+	// resources of the release's manifest.
+	adoptResources bool
 
 	values map[string]interface{}
 	status *types.HelmAppStatus
@@ -70,6 +92,10 @@ type manager struct {
 	isUpgradeRequired bool
 	deployedRelease   *rpb.Release
 	chart             *cpb.Chart
+
+	// recoveredPendingRelease is the latest release stuck in a pending state
+	// that Sync removed, if any.
+	recoveredPendingRelease *rpb.Release
 }
 
 type InstallOption func(*action.Install) error
@@ -89,6 +115,47 @@ func (m manager) IsUpgradeRequired() bool {
 	return m.isUpgradeRequired
 }
 
+// RecoveredPendingRelease returns the latest release that Sync found stuck in
+// a pending-install, pending-upgrade, or pending-rollback state and removed,
+// or nil if there was none. A release is only left pending if the operator
+// stopped during a Helm operation, since the operator is the only client
+// managing its releases.
+func (m manager) RecoveredPendingRelease() *rpb.Release {
+	return m.recoveredPendingRelease
+}
+
+// GetRelease returns the deployed release loaded by Sync, or nil if the
+// release is not installed or Sync has not been called.
+func (m manager) GetRelease() *rpb.Release {
+	return m.deployedRelease
+}
+
+// GetValues returns the values the release is rendered with: the chart's
+// default values merged with the custom resource's spec and override values.
+func (m manager) GetValues() (map[string]interface{}, error) {
+	values, err := chartutil.CoalesceValues(m.chart, m.values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge chart values: %w", err)
+	}
+	return values, nil
+}
+
+// GetChartMetadata returns the metadata of the chart the release is managed with.
+func (m manager) GetChartMetadata() *cpb.Metadata {
+	return m.chart.Metadata
+}
+
+// ValidateValues validates the values returned by GetValues against the
+// values.schema.json of the chart and its dependencies. It returns nil if
+// the chart has no schema.
+func (m manager) ValidateValues() error {
+	values, err := m.GetValues()
+	if err != nil {
+		return err
+	}
+	return chartutil.ValidateAgainstSchema(m.chart, values)
+}
+
 // Sync ensures the Helm storage backend is in sync with the status of the
 // custom resource.
 func (m *manager) Sync(ctx context.Context) error {
@@ -100,13 +167,20 @@ func (m *manager) Sync(ctx context.Context) error {
 
 	// Cleanup non-deployed release versions. If all release versions are
 	// non-deployed, this will ensure that failed installations are correctly
-	// retried.
+	// retried. Removing releases stuck in a pending state unblocks Helm, which
+	// refuses to operate on a release with a pending operation. Resources
+	// partially changed by a pending upgrade are then restored by reconciling
+	// or upgrading from the deployed release.
 	for _, rel := range releases {
 		if rel.Info != nil && rel.Info.Status != rpb.StatusDeployed {
 			_, err := m.storageBackend.Delete(rel.Name, rel.Version)
 			if err != nil && !notFoundErr(err) {
 				return fmt.Errorf("failed to delete stale release version: %w", err)
 			}
+			if rel.Info.Status.IsPending() &&
+				(m.recoveredPendingRelease == nil || rel.Version > m.recoveredPendingRelease.Version) {
+				m.recoveredPendingRelease = rel
+			}
 		}
 	}
 
@@ -156,9 +230,25 @@ func (m manager) getCandidateRelease(namespace, name string, chart *cpb.Chart,
 	return upgrade.Run(name, chart, values)
 }
 
+// deployConfig returns the action config of installs and upgrades. If m
+// adopts resources, its kube client adopts the existing resources of the
+// release's manifest.
+func (m manager) deployConfig() *action.Configuration {
+	if !m.adoptResources {
+		return m.actionConfig
+	}
+	actionConfig := *m.actionConfig
+	actionConfig.KubeClient = &adoptingClient{
+		Interface:        m.kubeClient,
+		releaseName:      m.releaseName,
+		releaseNamespace: m.namespace,
+	}
+	return &actionConfig
+}
+
 // InstallRelease performs a Helm release install.
 func (m manager) InstallRelease(ctx context.Context, opts ...InstallOption) (*rpb.Release, error) {
-	install := action.NewInstall(m.actionConfig)
+	install := action.NewInstall(m.deployConfig())
 	install.ReleaseName = m.releaseName
 	install.Namespace = m.namespace
 	for _, o := range opts {
@@ -169,6 +259,7 @@ func (m manager) InstallRelease(ctx context.Context, opts ...InstallOption) (*rp
 
 	installedRelease, err := install.Run(m.chart, m.values)
 	if err != nil {
+		err = hookError(installedRelease, err)
 		// Workaround for helm/helm#3338
 		if installedRelease != nil {
 			uninstall := action.NewUninstall(m.actionConfig)
@@ -198,22 +289,84 @@ func ForceUpgrade(force bool) UpgradeOption {
 	}
 }
 
+// InstallTimeout makes an install wait up to timeout for the release's
+// resources to become ready, failing the release if they do not.
+func InstallTimeout(timeout time.Duration) InstallOption {
+	return func(i *action.Install) error {
+		i.Wait = true
+		i.Timeout = timeout
+		return nil
+	}
+}
+
+// UpgradeTimeout makes an upgrade wait up to timeout for the release's
+// resources to become ready, failing the release if they do not.
+func UpgradeTimeout(timeout time.Duration) UpgradeOption {
+	return func(u *action.Upgrade) error {
+		u.Wait = true
+		u.Timeout = timeout
+		return nil
+	}
+}
+
+// RollbackOnFailure makes a failed upgrade roll the release back to its last
+// successfully deployed revision, waiting for the rolled back resources to
+// become ready. When the rollback succeeds, UpgradeRelease returns a
+// *RolledBackError. If the manager keeps all revisions, the upgrade and its
+// rollback keep at most defaultRollbackMaxHistory revisions.
+func RollbackOnFailure(rollback bool) UpgradeOption {
+	return func(u *action.Upgrade) error {
+		u.Atomic = rollback
+		if rollback && u.MaxHistory == 0 {
+			u.MaxHistory = defaultRollbackMaxHistory
+		}
+		return nil
+	}
+}
+
+// IsTimeoutError returns true if err was caused by an install or upgrade
+// exceeding its timeout.
+func IsTimeoutError(err error) bool {
+	return errors.Is(err, wait.ErrWaitTimeout) || errors.Is(err, context.DeadlineExceeded)
+}
+
 // UpgradeRelease performs a Helm release upgrade.
 func (m manager) UpgradeRelease(ctx context.Context, opts ...UpgradeOption) (*rpb.Release, *rpb.Release, error) {
-	upgrade := action.NewUpgrade(m.actionConfig)
+	upgrade := action.NewUpgrade(m.deployConfig())
 	upgrade.Namespace = m.namespace
+	upgrade.MaxHistory = m.maxHistory
 	for _, o := range opts {
 		if err := o(upgrade); err != nil {
 			return nil, nil, fmt.Errorf("failed to apply upgrade option: %w", err)
 		}
 	}
 
+	if upgrade.Atomic {
+		// Atomic upgrades wait for resources, so make sure the wait (and the
+		// rollback's wait) is bounded.
+		upgrade.Wait = true
+		if upgrade.Timeout == 0 {
+			upgrade.Timeout = defaultRollbackTimeout
+		}
+	}
+
+	// Unlike installs, Helm upgrades do not install the chart's CRDs, so
+	// install any that were added to the chart since the release was installed.
+	if err := m.installCRDs(); err != nil {
+		return nil, nil, err
+	}
+
 	upgradedRelease, err := upgrade.Run(m.releaseName, m.chart, m.values)
 	if err != nil {
+		err = hookError(upgradedRelease, err)
+		if upgrade.Atomic {
+			return nil, nil, m.rolledBackError(upgradedRelease, err)
+		}
 		// Workaround for helm/helm#3338
 		if upgradedRelease != nil {
 			rollback := action.NewRollback(m.actionConfig)
 			rollback.Force = true
+			rollback.MaxHistory = m.maxHistory
 
 			// As of Helm 2.13, if UpgradeRelease returns a non-nil release, that
 			// means the release was also recorded in the release store.
@@ -230,56 +383,137 @@ func (m manager) UpgradeRelease(ctx context.Context, opts ...UpgradeOption) (*rp
 	return m.deployedRelease, upgradedRelease, err
 }
 
+// rolledBackError returns a *RolledBackError if helm rolled back the failed
+// upgradedRelease, and wraps err otherwise.
+func (m manager) rolledBackError(upgradedRelease *rpb.Release, err error) error {
+	if upgradedRelease != nil {
+		deployed, derr := m.storageBackend.Deployed(m.releaseName)
+		if derr == nil && deployed.Version > upgradedRelease.Version {
+			return &RolledBackError{Release: deployed, Err: fmt.Errorf("failed to upgrade release: %w", err)}
+		}
+	}
+	return fmt.Errorf("failed to upgrade release: %w", err)
+}
+
+// crdEstablishTimeout is how long installCRDs waits for new CRDs to be
+// established.
+const crdEstablishTimeout = 60 * time.Second
+
+// installCRDs creates the CRDs in the chart's crds/ directory that do not
+// exist, waits for them to be established, and invalidates the cached
+// discovery information so that their kinds can be mapped. Like Helm, it
+// never updates existing CRDs.
+func (m manager) installCRDs() error {
+	var created kube.ResourceList
+	for _, crd := range m.chart.CRDObjects() {
+		res, err := m.kubeClient.Build(bytes.NewBuffer(crd.File.Data), false)
+		if err != nil {
+			return fmt.Errorf("failed to build CRD %s: %w", crd.Name, err)
+		}
+		if _, err := m.kubeClient.Create(res); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				continue
+			}
+			return fmt.Errorf("failed to install CRD %s: %w", crd.Name, err)
+		}
+		created = append(created, res...)
+	}
+	if len(created) == 0 {
+		return nil
+	}
+	if err := m.kubeClient.Wait(created, crdEstablishTimeout); err != nil {
+		return fmt.Errorf("failed waiting for CRDs to be established: %w", err)
+	}
+	discoveryClient, err := m.actionConfig.RESTClientGetter.ToDiscoveryClient()
+	if err != nil {
+		return fmt.Errorf("failed to get discovery client: %w", err)
+	}
+	discoveryClient.Invalidate()
+	return nil
+}
+
 // ReconcileRelease creates or patches resources as necessary to match the
 // deployed release's manifest.
 func (m manager) ReconcileRelease(ctx context.Context) (*rpb.Release, error) {
-	err := reconcileRelease(ctx, m.kubeClient, m.deployedRelease.Manifest)
+	err := reconcileRelease(ctx, m.kubeClient, m.deployedRelease.Manifest, m.patchStrategies)
 	return m.deployedRelease, err
 }
 
-func reconcileRelease(_ context.Context, kubeClient kube.Interface, expectedManifest string) error {
+func reconcileRelease(_ context.Context, kubeClient kube.Interface, expectedManifest string,
+	patchStrategies patchStrategies) error {
 	expectedInfos, err := kubeClient.Build(bytes.NewBufferString(expectedManifest), false)
 	if err != nil {
 		return err
 	}
-	return expectedInfos.Visit(func(expected *resource.Info, err error) error {
+	// Forbidden errors are collected rather than returned, so that all
+	// permissions the release is missing are reported at once.
+	var forbidden []error
+	err = expectedInfos.Visit(func(expected *resource.Info, err error) error {
 		if err != nil {
 			return fmt.Errorf("visit error: %w", err)
 		}
-
-		helper := resource.NewHelper(expected.Client, expected.Mapping)
-		existing, err := helper.Get(expected.Namespace, expected.Name, expected.Export)
-		if apierrors.IsNotFound(err) {
-			if _, err := helper.Create(expected.Namespace, true, expected.Object); err != nil {
-				return fmt.Errorf("create error: %s", err)
-			}
+		err = reconcileResource(expected, patchStrategies)
+		if isForbidden(err) {
+			forbidden = append(forbidden, err)
 			return nil
-		} else if err != nil {
-			return fmt.Errorf("could not get object: %w", err)
 		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate(forbidden)
+}
 
-		// Replicate helm's patch creation, which will create a Three-Way-Merge patch for
-		// native kubernetes Objects and fall back to a JSON merge patch for unstructured Objects such as CRDs
-		// We also extend the JSON merge patch by ignoring "remove" operations for fields added by kubernetes
-		// Reference in the helm source code:
-		// https://github.com/helm/helm/blob/1c9b54ad7f62a5ce12f87c3ae55136ca20f09c98/pkg/kube/client.go#L392
-		patch, patchType, err := createPatch(existing, expected)
-		if err != nil {
-			return fmt.Errorf("error creating patch: %w", err)
-		}
+// isForbidden returns true if err was caused by a Forbidden API error.
+func isForbidden(err error) bool {
+	var status apierrors.APIStatus
+	return errors.As(err, &status) && status.Status().Reason == metav1.StatusReasonForbidden
+}
 
-		if patch == nil {
-			// nothing to do
-			return nil
+// reconcileResource creates expected, or patches it with the strategy of its
+// kind in patchStrategies if it exists.
+func reconcileResource(expected *resource.Info, patchStrategies patchStrategies) error {
+	helper := resource.NewHelper(expected.Client, expected.Mapping)
+	existing, err := helper.Get(expected.Namespace, expected.Name, expected.Export)
+	if apierrors.IsNotFound(err) {
+		if _, err := helper.Create(expected.Namespace, true, expected.Object); err != nil {
+			return fmt.Errorf("create error: %w", err)
 		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("could not get object: %w", err)
+	}
 
-		_, err = helper.Patch(expected.Namespace, expected.Name, patchType, patch,
-			&metav1.PatchOptions{})
-		if err != nil {
-			return fmt.Errorf("patch error: %w", err)
-		}
+	patchStrategy := patchStrategies.forKind(expected.Mapping.GroupVersionKind.GroupKind())
+	if patchStrategy == PatchStrategyServerSideApply {
+		return applyServerSide(helper, expected)
+	}
+
+	// Replicate helm's patch creation, which will create a Three-Way-Merge patch for
+	// native kubernetes Objects and fall back to a JSON merge patch for unstructured Objects such as CRDs
+	// We also extend the JSON merge patch by ignoring "remove" operations for fields added by kubernetes
+	// Reference in the helm source code:
+	// https://github.com/helm/helm/blob/1c9b54ad7f62a5ce12f87c3ae55136ca20f09c98/pkg/kube/client.go#L392
+	patch, patchType, err := createPatch(existing, expected)
+	if err != nil {
+		return fmt.Errorf("error creating patch: %w", err)
+	}
+
+	if patch == nil {
+		// nothing to do
 		return nil
-	})
+	}
+	if patchStrategy == PatchStrategyReplace {
+		return replace(helper, expected)
+	}
+
+	_, err = helper.Patch(expected.Namespace, expected.Name, patchType, patch,
+		&metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("patch error: %w", err)
+	}
+	return nil
 }
 
 func createPatch(existing runtime.Object, expected *resource.Info) ([]byte, apitypes.PatchType, error) {
@@ -363,12 +597,28 @@ func (m manager) UninstallRelease(ctx context.Context, opts ...UninstallOption)
 		return nil, driver.ErrReleaseNotFound
 	}
 
-	uninstall := action.NewUninstall(m.actionConfig)
+	// Uninstall with a kube client that deletes the release's resources
+	// according to the uninstall policy.
+	actionConfig := *m.actionConfig
+	actionConfig.KubeClient = &uninstallingClient{
+		Interface: m.kubeClient,
+		policy:    m.uninstallPolicy,
+		ownerUID:  m.ownerUID,
+	}
+	uninstall := action.NewUninstall(&actionConfig)
+	uninstall.Timeout = m.uninstallPolicy.Timeout
+	uninstall.DisableHooks = m.uninstallPolicy.KeepResources
 	for _, o := range opts {
 		if err := o(uninstall); err != nil {
 			return nil, fmt.Errorf("failed to apply uninstall option: %w", err)
 		}
 	}
 	uninstallResponse, err := uninstall.Run(m.releaseName)
+	if uninstallResponse == nil {
+		return nil, err
+	}
+	if err != nil {
+		err = hookError(uninstallResponse.Release, err)
+	}
 	return uninstallResponse.Release, err
 }
@@ -16,16 +16,23 @@ package release
 
 import (
 	"fmt"
+	"strings"
+	"text/template"
 
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/kube"
 	helmrelease "helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	"helm.sh/helm/v3/pkg/strvals"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
 	crmanager "sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/operator-framework/operator-sdk/internal/helm/client"
@@ -41,57 +48,216 @@ type ManagerFactory interface {
 }
 
 type managerFactory struct {
-	mgr      crmanager.Manager
-	chartDir string
+	mgr              crmanager.Manager
+	chartDir         string
+	keyring          string
+	wrapRESTConfig   RESTConfigWrapper
+	maxHistory       int
+	releaseNamespace string
+	patchStrategy    PatchStrategy
+	kindStrategies   map[schema.GroupKind]PatchStrategy
+	uninstallPolicy  UninstallPolicy
+	releaseSuffix    string
+	releaseNameTmpl  *template.Template
+	adoptReleases    bool
+	adoptResources   bool
+	valuesField      string
+	defaultValues    map[string]interface{}
+}
+
+// ManagerFactoryOption configures a ManagerFactory.
+type ManagerFactoryOption func(*managerFactory)
+
+// RESTConfigWrapper returns the REST config used to manage releases, given a
+// copy of the controller manager's REST config. It may modify and return cfg,
+// for example to set cfg.WrapTransport to add a proxy, request tracing, or
+// custom authentication, or return a different config.
+type RESTConfigWrapper func(cfg *rest.Config) (*rest.Config, error)
+
+// WrapRESTConfig configures a ManagerFactory to manage releases with the REST
+// config returned by wrap, instead of the controller manager's REST config.
+func WrapRESTConfig(wrap RESTConfigWrapper) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.wrapRESTConfig = wrap
+	}
+}
+
+// MaxHistory configures a ManagerFactory's Managers to prune a release's
+// oldest revisions when it has more than maxHistory revisions. If maxHistory
+// is 0, revisions are never pruned.
+func MaxHistory(maxHistory int) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.maxHistory = maxHistory
+	}
+}
+
+// ReleaseNamespace configures a ManagerFactory's Managers to store releases
+// of cluster-scoped custom resources in namespace, which is also the default
+// namespace of their namespaced resources. If namespace is empty, the
+// "default" namespace is used. Releases of namespaced custom resources are
+// always stored in the custom resource's namespace.
+func ReleaseNamespace(namespace string) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.releaseNamespace = namespace
+	}
+}
+
+// DriftPatchStrategy configures how a ManagerFactory's Managers correct drift
+// between a release's manifest and its resources in the cluster. The default
+// is PatchStrategyMerge.
+func DriftPatchStrategy(strategy PatchStrategy) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.patchStrategy = strategy
+	}
+}
+
+// KindDriftPatchStrategy configures a ManagerFactory's Managers to correct
+// drift of resources of kind gk with strategy, overriding the strategy set
+// by DriftPatchStrategy for that kind.
+func KindDriftPatchStrategy(gk schema.GroupKind, strategy PatchStrategy) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		if f.kindStrategies == nil {
+			f.kindStrategies = map[schema.GroupKind]PatchStrategy{}
+		}
+		f.kindStrategies[gk] = strategy
+	}
+}
+
+// ReleaseNameSuffix configures a ManagerFactory's Managers to name releases
+// "<cr-name>-<suffix>" instead of after the custom resource, so that several
+// releases can be installed for each custom resource.
+func ReleaseNameSuffix(suffix string) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.releaseSuffix = suffix
+	}
+}
+
+// ReleaseNameTemplate configures a ManagerFactory's Managers to name releases
+// by executing tmpl with the custom resource's metadata, instead of after the
+// custom resource. Use ParseReleaseNameTemplate to parse tmpl. If a release
+// name suffix is also configured, it is appended to the executed name.
+func ReleaseNameTemplate(tmpl *template.Template) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.releaseNameTmpl = tmpl
+	}
+}
+
+// AdoptExistingReleases configures whether a ManagerFactory's Managers take
+// ownership of an existing release with the same name as a custom resource's
+// release, even if it was installed from a different chart. The adopted
+// release is upgraded with the factory's chart, which adds owner references
+// to the custom resource to its resources. If adopt is false, a release name
+// conflict with a release of another chart is an error.
+func AdoptExistingReleases(adopt bool) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.adoptReleases = adopt
+	}
+}
+
+// ValuesField configures a ManagerFactory's Managers to use the custom
+// resource's spec field at the dot-separated path field as release values,
+// instead of the whole spec. If the field is unset, the release has no values
+// other than overrides.
+func ValuesField(field string) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.valuesField = field
+	}
+}
+
+// DefaultValues configures a ManagerFactory's Managers to merge values over
+// the chart's default values and under each custom resource's values. This
+// allows several kinds to share a chart with different defaults.
+func DefaultValues(values map[string]interface{}) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.defaultValues = values
+	}
 }
 
 // NewManagerFactory returns a new Helm manager factory capable of installing and uninstalling releases.
-func NewManagerFactory(mgr crmanager.Manager, chartDir string) ManagerFactory {
-	return &managerFactory{mgr, chartDir}
+func NewManagerFactory(mgr crmanager.Manager, chartDir string, opts ...ManagerFactoryOption) ManagerFactory {
+	f := &managerFactory{mgr: mgr, chartDir: chartDir}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// NewVerifyingManagerFactory returns a new Helm manager factory that verifies
+// the provenance of the chart archive at chartPath against keyring each time
+// it loads the chart. If verification fails, NewManager returns a
+// *ChartVerificationError.
+func NewVerifyingManagerFactory(mgr crmanager.Manager, chartPath, keyring string, opts ...ManagerFactoryOption) ManagerFactory {
+	f := &managerFactory{mgr: mgr, chartDir: chartPath, keyring: keyring}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// ChartVerificationError is returned by a ManagerFactory when a chart's
+// provenance cannot be verified.
+type ChartVerificationError struct {
+	Chart string
+	Err   error
+}
+
+func (e *ChartVerificationError) Error() string {
+	return fmt.Sprintf("failed to verify chart %s: %v", e.Chart, e.Err)
+}
+
+func (e *ChartVerificationError) Unwrap() error {
+	return e.Err
 }
 
 func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues map[string]string) (Manager, error) {
+	// Load the chart first, so that a chart that fails verification is
+	// rejected before any client is created.
+	crChart, err := f.loadChart()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := f.restConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get REST config: %w", err)
+	}
+
 	// Get both v2 and v3 storage backends
-	clientv1, err := v1.NewForConfig(f.mgr.GetConfig())
+	clientv1, err := v1.NewForConfig(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get core/v1 client: %w", err)
 	}
-	storageBackend := storage.Init(driver.NewSecrets(clientv1.Secrets(cr.GetNamespace())))
+	namespace := f.namespaceFor(cr)
+	storageBackend := storage.Init(driver.NewSecrets(clientv1.Secrets(namespace)))
+	storageBackend.MaxHistory = f.maxHistory
 
 	// Get the necessary clients and client getters. Use a client that injects the CR
 	// as an owner reference into all resources templated by the chart.
-	rcg, err := client.NewRESTClientGetter(f.mgr, cr.GetNamespace())
+	restMapper := f.mgr.GetRESTMapper()
+	rcg, err := client.NewRESTClientGetterForConfig(cfg, restMapper, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get REST client getter from manager: %w", err)
 	}
 
 	kubeClient := kube.New(rcg)
-	restMapper := f.mgr.GetRESTMapper()
 	ownerRefClient, err := client.NewOwnerRefInjectingClient(*kubeClient, restMapper, cr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to inject owner references: %w", err)
 	}
 
-	crChart, err := loader.LoadDir(f.chartDir)
+	releaseName, err := f.releaseNameFor(cr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load chart dir: %w", err)
+		return nil, err
 	}
-
-	releaseName, err := getReleaseName(storageBackend, crChart.Name(), cr)
+	releaseName, err = getReleaseName(storageBackend, crChart.Name(), releaseName, f.adoptReleases)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get helm release name: %w", err)
 	}
 
-	crValues, ok := cr.Object["spec"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("failed to get spec: expected map[string]interface{}")
-	}
-
-	expOverrides, err := parseOverrides(overrideValues)
+	values, err := f.releaseValues(cr, overrideValues)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse override values: %w", err)
+		return nil, err
 	}
-	values := mergeMaps(crValues, expOverrides)
 
 	actionConfig := &action.Configuration{
 		RESTClientGetter: rcg,
@@ -106,7 +272,16 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 		kubeClient:     ownerRefClient,
 
 		releaseName: releaseName,
-		namespace:   cr.GetNamespace(),
+		namespace:   namespace,
+		maxHistory:  f.maxHistory,
+		patchStrategies: patchStrategies{
+			defaultStrategy: f.patchStrategy,
+			kinds:           f.kindStrategies,
+		},
+
+		uninstallPolicy: f.uninstallPolicy,
+		ownerUID:        cr.GetUID(),
+		adoptResources:  f.adoptResources,
 
 		chart:  crChart,
 		values: values,
@@ -114,16 +289,116 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 	}, nil
 }
 
+// namespaceFor returns the namespace of cr's release.
+func (f managerFactory) namespaceFor(cr *unstructured.Unstructured) string {
+	if ns := cr.GetNamespace(); ns != "" {
+		return ns
+	}
+	if f.releaseNamespace != "" {
+		return f.releaseNamespace
+	}
+	return metav1.NamespaceDefault
+}
+
+// releaseNameFor returns the name of cr's release.
+// Names from a template or with a suffix are validated once complete.
+func (f managerFactory) releaseNameFor(cr *unstructured.Unstructured) (string, error) {
+	name := cr.GetName()
+	if f.releaseNameTmpl == nil && f.releaseSuffix == "" {
+		return name, nil
+	}
+	if f.releaseNameTmpl != nil {
+		var err error
+		if name, err = executeReleaseNameTemplate(f.releaseNameTmpl, cr); err != nil {
+			return "", err
+		}
+	}
+	if f.releaseSuffix != "" {
+		name = name + "-" + f.releaseSuffix
+	}
+	if err := validateReleaseName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// valuesFor returns the values of cr's release, before overrides.
+func (f managerFactory) valuesFor(cr *unstructured.Unstructured) (map[string]interface{}, error) {
+	spec, ok := cr.Object["spec"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to get spec: expected map[string]interface{}")
+	}
+	if f.valuesField == "" {
+		return spec, nil
+	}
+	values, _, err := unstructured.NestedMap(spec, strings.Split(f.valuesField, ".")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get values field %q: %w", f.valuesField, err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values, nil
+}
+
+// releaseValues returns the values of cr's release, merged over the
+// factory's default values, with overrideValues applied.
+func (f managerFactory) releaseValues(cr *unstructured.Unstructured, overrideValues map[string]string) (map[string]interface{}, error) {
+	crValues, err := f.valuesFor(cr)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.defaultValues) != 0 {
+		crValues = mergeMaps(f.defaultValues, crValues)
+	}
+	expOverrides, err := parseOverrides(overrideValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse override values: %w", err)
+	}
+	return mergeMaps(crValues, expOverrides), nil
+}
+
+// restConfig returns the REST config used to manage releases.
+func (f managerFactory) restConfig() (*rest.Config, error) {
+	cfg := rest.CopyConfig(f.mgr.GetConfig())
+	if f.wrapRESTConfig == nil {
+		return cfg, nil
+	}
+	return f.wrapRESTConfig(cfg)
+}
+
+// loadChart loads the factory's chart, verifying its provenance first if a
+// keyring is configured.
+func (f managerFactory) loadChart() (*chart.Chart, error) {
+	if f.keyring == "" {
+		crChart, err := loader.LoadDir(f.chartDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load chart dir: %w", err)
+		}
+		return crChart, nil
+	}
+
+	if _, err := downloader.VerifyChart(f.chartDir, f.keyring); err != nil {
+		return nil, &ChartVerificationError{Chart: f.chartDir, Err: err}
+	}
+	crChart, err := loader.Load(f.chartDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart archive: %w", err)
+	}
+	return crChart, nil
+}
+
 // getReleaseName returns a release name for the CR.
 //
-// getReleaseName searches for a release using the CR name. If a release
-// cannot be found, or if it is found and was created by the chart managed
-// by this manager, the CR name is returned.
+// getReleaseName searches for a release named releaseName, which is derived
+// from the CR name. If a release cannot be found, or if it is found and was
+// created by the chart managed by this manager, releaseName is returned.
 //
 // If a release is found but it was created by another chart, that means we
-// have a release name collision, so return an error. This case is possible
-// because Kubernetes allows instances of different types to have the same name
-// in the same namespace.
+// have a release name collision, so return an error, unless adopt is true.
+// This case is possible because Kubernetes allows instances of different types
+// to have the same name in the same namespace, and because release names may
+// be templated.
 //
 // TODO(jlanford): As noted above, using the CR name as the release name raises
 //   the possibility of collision. We should move this logic to a validating
@@ -131,14 +406,13 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 //   collision. As is, the only indication of collision will be in the CR status
 //   and operator logs.
 func getReleaseName(storageBackend *storage.Storage, crChartName string,
-	cr *unstructured.Unstructured) (string, error) {
+	releaseName string, adopt bool) (string, error) {
 	// If a release with the CR name does not exist, return the CR name.
-	releaseName := cr.GetName()
 	history, exists, err := releaseHistory(storageBackend, releaseName)
 	if err != nil {
 		return "", err
 	}
-	if !exists {
+	if !exists || adopt {
 		return releaseName, nil
 	}
 
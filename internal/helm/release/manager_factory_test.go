@@ -0,0 +1,439 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	cpb "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+	rpb "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	crmanager "sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// configManager is a controller manager that only implements GetConfig.
+type configManager struct {
+	crmanager.Manager
+	cfg *rest.Config
+}
+
+func (m configManager) GetConfig() *rest.Config {
+	return m.cfg
+}
+
+func TestManagerFactoryRESTConfig(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{Host: "https://example.com"}}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	cfg, err := f.restConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", cfg.Host)
+
+	wrapTransport := func(rt http.RoundTripper) http.RoundTripper { return rt }
+	f = NewManagerFactory(mgr, "chart", WrapRESTConfig(func(cfg *rest.Config) (*rest.Config, error) {
+		cfg.WrapTransport = wrapTransport
+		return cfg, nil
+	})).(*managerFactory)
+	cfg, err = f.restConfig()
+	assert.NoError(t, err)
+	assert.NotNil(t, cfg.WrapTransport)
+	assert.Nil(t, mgr.cfg.WrapTransport, "manager's config must not be modified")
+
+	f = NewVerifyingManagerFactory(mgr, "chart.tgz", "pubring.gpg", WrapRESTConfig(func(*rest.Config) (*rest.Config, error) {
+		return nil, errors.New("wrap error")
+	})).(*managerFactory)
+	_, err = f.restConfig()
+	assert.EqualError(t, err, "wrap error")
+}
+
+func TestManagerFactoryMaxHistory(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	assert.Equal(t, 0, f.maxHistory)
+
+	f = NewManagerFactory(mgr, "chart", MaxHistory(10)).(*managerFactory)
+	assert.Equal(t, 10, f.maxHistory)
+
+	f = NewVerifyingManagerFactory(mgr, "chart.tgz", "pubring.gpg", MaxHistory(3)).(*managerFactory)
+	assert.Equal(t, 3, f.maxHistory)
+}
+
+func TestManagerFactoryNamespaceFor(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+	namespaced := &unstructured.Unstructured{}
+	namespaced.SetNamespace("cr-namespace")
+	clusterScoped := &unstructured.Unstructured{}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	assert.Equal(t, "cr-namespace", f.namespaceFor(namespaced))
+	assert.Equal(t, "default", f.namespaceFor(clusterScoped))
+
+	f = NewManagerFactory(mgr, "chart", ReleaseNamespace("releases")).(*managerFactory)
+	assert.Equal(t, "cr-namespace", f.namespaceFor(namespaced))
+	assert.Equal(t, "releases", f.namespaceFor(clusterScoped))
+}
+
+func TestManagerFactoryDriftPatchStrategy(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	assert.Equal(t, PatchStrategy(""), f.patchStrategy)
+
+	f = NewManagerFactory(mgr, "chart", DriftPatchStrategy(PatchStrategyServerSideApply)).(*managerFactory)
+	assert.Equal(t, PatchStrategyServerSideApply, f.patchStrategy)
+}
+
+func TestManagerFactoryKindDriftPatchStrategy(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+	job := schema.GroupKind{Group: "batch", Kind: "Job"}
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	assert.Empty(t, f.kindStrategies)
+
+	f = NewManagerFactory(mgr, "chart",
+		KindDriftPatchStrategy(job, PatchStrategyReplace),
+		KindDriftPatchStrategy(deployment, PatchStrategyServerSideApply),
+	).(*managerFactory)
+	assert.Equal(t, map[schema.GroupKind]PatchStrategy{
+		job:        PatchStrategyReplace,
+		deployment: PatchStrategyServerSideApply,
+	}, f.kindStrategies)
+}
+
+func TestManagerFactoryUninstallPolicy(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	assert.Equal(t, UninstallPolicy{}, f.uninstallPolicy)
+
+	policy := UninstallPolicy{KeepResources: true}
+	f = NewManagerFactory(mgr, "chart", WithUninstallPolicy(policy)).(*managerFactory)
+	assert.Equal(t, policy, f.uninstallPolicy)
+}
+
+func TestManagerFactoryReleaseNameFor(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+	cr := &unstructured.Unstructured{}
+	cr.SetName("example")
+
+	cr.SetNamespace("team-a")
+	cr.SetUID("6f5b4c3e")
+
+	nameTmpl := func(text string) *template.Template {
+		tmpl, err := ParseReleaseNameTemplate(text)
+		require.NoError(t, err)
+		return tmpl
+	}
+
+	testCases := []struct {
+		name     string
+		opts     []ManagerFactoryOption
+		expected string
+		errMsg   string
+	}{
+		{"default", nil, "example", ""},
+		{"suffix", []ManagerFactoryOption{ReleaseNameSuffix("monitoring")}, "example-monitoring", ""},
+		{
+			"template",
+			[]ManagerFactoryOption{ReleaseNameTemplate(nameTmpl("{{ .Namespace }}-{{ .Name }}"))},
+			"team-a-example", "",
+		},
+		{
+			"template uid",
+			[]ManagerFactoryOption{ReleaseNameTemplate(nameTmpl("{{ .Name }}-{{ .UID }}"))},
+			"example-6f5b4c3e", "",
+		},
+		{
+			"template and suffix",
+			[]ManagerFactoryOption{
+				ReleaseNameTemplate(nameTmpl("{{ .Namespace }}-{{ .Name }}")),
+				ReleaseNameSuffix("monitoring"),
+			},
+			"team-a-example-monitoring", "",
+		},
+		{
+			"template invalid name",
+			[]ManagerFactoryOption{ReleaseNameTemplate(nameTmpl("{{ .Name }}_{{ .Namespace }}"))},
+			"", `invalid release name "example_team-a"`,
+		},
+		{
+			"template too long",
+			[]ManagerFactoryOption{ReleaseNameTemplate(nameTmpl(strings.Repeat("{{ .Name }}-", 7) + "x"))},
+			"", "must be no more than 53 characters",
+		},
+		{
+			// 46 characters, 53 with the suffix.
+			"template at limit with suffix",
+			[]ManagerFactoryOption{
+				ReleaseNameTemplate(nameTmpl(strings.Repeat("{{ .Name }}-", 5) + "{{ .Namespace }}")),
+				ReleaseNameSuffix("metric"),
+			},
+			strings.Repeat("example-", 5) + "team-a-metric", "",
+		},
+		{
+			"template too long with suffix",
+			[]ManagerFactoryOption{
+				ReleaseNameTemplate(nameTmpl(strings.Repeat("{{ .Name }}-", 5) + "{{ .Namespace }}")),
+				ReleaseNameSuffix("metrics"),
+			},
+			"", "must be no more than 53 characters",
+		},
+		{
+			"suffix too long",
+			[]ManagerFactoryOption{ReleaseNameSuffix(strings.Repeat("x", 50))},
+			"", "must be no more than 53 characters",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewManagerFactory(mgr, "chart", tc.opts...).(*managerFactory)
+			name, err := f.releaseNameFor(cr)
+			if tc.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, name)
+		})
+	}
+}
+
+func TestParseReleaseNameTemplate(t *testing.T) {
+	valid := []string{
+		"{{ .Name }}",
+		"{{ .Namespace }}-{{ .Name }}",
+		"{{ if .Namespace }}{{ .Namespace }}-{{ end }}{{ .Name }}",
+		"{{ printf \"%s-%s\" $.Name .UID }}",
+	}
+	for _, text := range valid {
+		_, err := ParseReleaseNameTemplate(text)
+		assert.NoError(t, err, text)
+	}
+
+	_, err := ParseReleaseNameTemplate("{{ .Name ")
+	assert.Error(t, err)
+
+	invalid := []string{
+		"{{ .Labels.team }}",
+		`{{ index .Annotations "example.com/release" }}`,
+		"{{ with .Name }}{{ $.Labels }}{{ end }}",
+		"{{ .Name }}-{{ .Spec.name }}",
+	}
+	for _, text := range invalid {
+		_, err := ParseReleaseNameTemplate(text)
+		if assert.Error(t, err, text) {
+			assert.Contains(t, err.Error(), "only .Name, .Namespace, and .UID are allowed")
+		}
+	}
+}
+
+func TestGetReleaseName(t *testing.T) {
+	s := storage.Init(driver.NewMemory())
+	require.NoError(t, s.Create(&rpb.Release{
+		Name:      "legacy",
+		Namespace: "ns",
+		Version:   1,
+		Info:      &rpb.Info{Status: rpb.StatusDeployed},
+		Chart:     &cpb.Chart{Metadata: &cpb.Metadata{Name: "other"}},
+	}))
+
+	name, err := getReleaseName(s, "nginx", "example", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "example", name)
+
+	name, err = getReleaseName(s, "other", "legacy", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy", name)
+
+	_, err = getReleaseName(s, "nginx", "legacy", false)
+	assert.EqualError(t, err, `duplicate release name: found existing release with name "legacy" for chart "other"`)
+
+	name, err = getReleaseName(s, "nginx", "legacy", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "legacy", name)
+}
+
+func TestManagerFactoryAdoptExistingReleases(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	assert.False(t, f.adoptReleases)
+
+	f = NewManagerFactory(mgr, "chart", AdoptExistingReleases(true)).(*managerFactory)
+	assert.True(t, f.adoptReleases)
+}
+
+func TestManagerFactoryValuesFor(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"monitoring": map[string]interface{}{
+				"prometheus": map[string]interface{}{"enabled": true},
+			},
+		},
+	}}
+
+	f := NewManagerFactory(mgr, "chart").(*managerFactory)
+	values, err := f.valuesFor(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, cr.Object["spec"], values)
+
+	f = NewManagerFactory(mgr, "chart", ValuesField("monitoring")).(*managerFactory)
+	values, err = f.valuesFor(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"prometheus": map[string]interface{}{"enabled": true}}, values)
+
+	f = NewManagerFactory(mgr, "chart", ValuesField("logging")).(*managerFactory)
+	values, err = f.valuesFor(cr)
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+
+	f = NewManagerFactory(mgr, "chart", ValuesField("replicas")).(*managerFactory)
+	_, err = f.valuesFor(cr)
+	assert.Error(t, err)
+}
+
+func TestManagerFactoryReleaseValuesDefaults(t *testing.T) {
+	mgr := configManager{cfg: &rest.Config{}}
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"limits": map[string]interface{}{"memory": "8Gi"},
+			},
+		},
+	}}
+
+	f := NewManagerFactory(mgr, "chart", DefaultValues(map[string]interface{}{
+		"replicaCount": float64(5),
+		"image":        "nginx",
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"cpu": float64(2), "memory": "4Gi"},
+		},
+	})).(*managerFactory)
+	values, err := f.releaseValues(cr, map[string]string{"image": "nginx:stable"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"replicaCount": float64(5),
+		"image":        "nginx:stable",
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"cpu": float64(2), "memory": "8Gi"},
+		},
+	}, values)
+}
+
+func TestManagerFactoryChartVerification(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chart-verification-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	signer := newTestSigner(t)
+	keyring := filepath.Join(dir, "pubring.gpg")
+	writeKeyring(t, keyring, signer.Entity)
+
+	// Each case saves its chart archive, and its provenance file if signed,
+	// in its own directory.
+	tests := []struct {
+		name   string
+		signer *provenance.Signatory
+		// tamper, if true, replaces the archive after it is signed.
+		tamper    bool
+		expectErr string
+	}{
+		{name: "signed", signer: signer},
+		{name: "unsigned", expectErr: "nginx-0.1.0.tgz.prov"},
+		{name: "tampered", signer: signer, tamper: true, expectErr: "sha256 sum does not match"},
+		{name: "untrusted key", signer: newTestSigner(t), expectErr: "signature"},
+	}
+	mgr := configManager{cfg: &rest.Config{}}
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+	for _, test := range tests {
+		caseDir := filepath.Join(dir, strings.ReplaceAll(test.name, " ", "-"))
+		archive := saveTestChart(t, caseDir, 1)
+		if test.signer != nil {
+			sig, err := test.signer.ClearSign(archive)
+			require.NoError(t, err, test.name)
+			require.NoError(t, ioutil.WriteFile(archive+".prov", []byte(sig), 0644), test.name)
+		}
+		if test.tamper {
+			saveTestChart(t, caseDir, 100)
+		}
+
+		f := NewVerifyingManagerFactory(mgr, archive, keyring).(*managerFactory)
+		if test.expectErr == "" {
+			c, err := f.loadChart()
+			assert.NoError(t, err, test.name)
+			assert.Equal(t, "nginx", c.Name(), test.name)
+			continue
+		}
+		_, err := f.NewManager(cr, nil)
+		var verifyErr *ChartVerificationError
+		if assert.True(t, errors.As(err, &verifyErr), "%s: expected a *ChartVerificationError, got %v", test.name, err) {
+			assert.Equal(t, archive, verifyErr.Chart, test.name)
+			assert.Contains(t, verifyErr.Error(), test.expectErr, test.name)
+		}
+	}
+}
+
+// newTestSigner returns a signatory with a new private key.
+func newTestSigner(t *testing.T) *provenance.Signatory {
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	require.NoError(t, err)
+	return &provenance.Signatory{Entity: entity}
+}
+
+// writeKeyring writes a keyring that trusts entity's public key to path.
+func writeKeyring(t *testing.T, path string, entity *openpgp.Entity) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, entity.Serialize(f))
+}
+
+// saveTestChart saves the archive of an nginx chart with a default number of
+// replicas to dir, replacing any archive saved before, and returns the
+// archive's path.
+func saveTestChart(t *testing.T, dir string, replicas int) string {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	c := &cpb.Chart{
+		Metadata: &cpb.Metadata{APIVersion: cpb.APIVersionV2, Name: "nginx", Version: "0.1.0"},
+		Templates: []*cpb.File{
+			{Name: "templates/configmap.yaml", Data: []byte("kind: ConfigMap\ndata: {{ toYaml .Values }}\n")},
+		},
+		Values: map[string]interface{}{"replicas": replicas},
+	}
+	archive, err := chartutil.Save(c, dir)
+	require.NoError(t, err)
+	return archive
+}
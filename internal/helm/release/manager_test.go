@@ -15,8 +15,14 @@
 package release
 
 import (
+	"context"
 	"testing"
 
+	cpb "helm.sh/helm/v3/pkg/chart"
+	rpb "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/resource"
@@ -213,3 +219,131 @@ func TestManagerGenerateStrategicMergePatch(t *testing.T) {
 		assert.Equal(t, test.patch, string(diff))
 	}
 }
+
+func TestManagerSyncRecoversPendingRelease(t *testing.T) {
+	tests := []struct {
+		name            string
+		statuses        []rpb.Status
+		expectRecovered int
+	}{
+		{
+			name:     "no releases",
+			statuses: nil,
+		},
+		{
+			name:     "failed install",
+			statuses: []rpb.Status{rpb.StatusFailed},
+		},
+		{
+			name:            "pending install",
+			statuses:        []rpb.Status{rpb.StatusPendingInstall},
+			expectRecovered: 1,
+		},
+		{
+			name:            "failed then pending install",
+			statuses:        []rpb.Status{rpb.StatusFailed, rpb.StatusPendingInstall},
+			expectRecovered: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := storage.Init(driver.NewMemory())
+			for i, status := range test.statuses {
+				rel := &rpb.Release{Name: "test", Namespace: "ns", Version: i + 1, Info: &rpb.Info{Status: status}}
+				assert.NoError(t, s.Create(rel))
+			}
+
+			m := &manager{storageBackend: s, releaseName: "test", namespace: "ns"}
+			assert.NoError(t, m.Sync(context.TODO()))
+			assert.False(t, m.IsInstalled())
+
+			recovered := m.RecoveredPendingRelease()
+			if test.expectRecovered == 0 {
+				assert.Nil(t, recovered)
+			} else if assert.NotNil(t, recovered) {
+				assert.Equal(t, test.expectRecovered, recovered.Version)
+			}
+
+			// The memory driver returns a not found error for an empty history.
+			history, _ := s.History("test")
+			assert.Empty(t, history)
+		})
+	}
+}
+
+func TestManagerGetValues(t *testing.T) {
+	chrt := &cpb.Chart{
+		Metadata: &cpb.Metadata{Name: "test", Version: "1.2.3"},
+		Values: map[string]interface{}{
+			"replicaCount": 1,
+			"image":        map[string]interface{}{"repository": "nginx", "tag": "stable"},
+		},
+	}
+	m := &manager{
+		chart:  chrt,
+		values: map[string]interface{}{"image": map[string]interface{}{"tag": "1.19"}},
+	}
+
+	values, err := m.GetValues()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"replicaCount": 1,
+		"image":        map[string]interface{}{"repository": "nginx", "tag": "1.19"},
+	}, values)
+	assert.Equal(t, chrt.Metadata, m.GetChartMetadata())
+	assert.Nil(t, m.GetRelease())
+}
+
+func TestManagerValidateValues(t *testing.T) {
+	schema := []byte(`{
+  "$schema": "http://json-schema.org/schema#",
+  "type": "object",
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1}
+  }
+}`)
+	tests := []struct {
+		name      string
+		schema    []byte
+		values    map[string]interface{}
+		expectErr bool
+	}{
+		{
+			name:   "no schema",
+			values: map[string]interface{}{"replicaCount": "one"},
+		},
+		{
+			name:   "valid values",
+			schema: schema,
+			values: map[string]interface{}{"replicaCount": 2},
+		},
+		{
+			name:      "invalid type",
+			schema:    schema,
+			values:    map[string]interface{}{"replicaCount": "one"},
+			expectErr: true,
+		},
+		{
+			name:      "below minimum",
+			schema:    schema,
+			values:    map[string]interface{}{"replicaCount": 0},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &manager{
+				chart:  &cpb.Chart{Metadata: &cpb.Metadata{Name: "test"}, Schema: test.schema},
+				values: test.values,
+			}
+			err := m.ValidateValues()
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/deislabs/oras/pkg/auth/docker"
+	"github.com/deislabs/oras/pkg/content"
+	"github.com/deislabs/oras/pkg/oras"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// OCIScheme is the prefix of chart references that are pulled from an OCI registry.
+const OCIScheme = "oci://"
+
+const (
+	helmConfigMediaType = "application/vnd.cncf.helm.config.v1+json"
+	// helmChartLayerMediaType is the media type of chart layers pushed by Helm 3.3 and earlier.
+	helmChartLayerMediaType = "application/tar+gzip"
+	// helmChartContentMediaType is the media type of chart layers pushed by later Helm versions.
+	helmChartContentMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+)
+
+// IsOCIChart returns true if chart is an OCI chart reference, ex.
+// "oci://quay.io/example/nginx:0.1.0".
+func IsOCIChart(chart string) bool {
+	return strings.HasPrefix(chart, OCIScheme)
+}
+
+// ValidateOCIChart returns an error if the OCI chart reference chart does not
+// name a repository and a tag or digest.
+func ValidateOCIChart(chart string) error {
+	ref := strings.TrimPrefix(chart, OCIScheme)
+	slash := strings.Index(ref, "/")
+	if slash <= 0 || slash == len(ref)-1 {
+		return fmt.Errorf("chart reference %q must be of the form %s<registry>/<repository>:<tag>", chart, OCIScheme)
+	}
+	if !strings.Contains(ref, "@") && !strings.Contains(ref[strings.LastIndex(ref, "/"):], ":") {
+		return fmt.Errorf("chart reference %q must have a tag or digest", chart)
+	}
+	return nil
+}
+
+// PullOCIChart pulls the chart referenced by chart, an OCI chart reference,
+// and expands it into a subdirectory of cacheDir named after the digest of
+// the chart's content. If that directory exists, the chart is not expanded
+// again. Registry credentials are read from the Docker config file at
+// registryConfig, or from Docker's default config file if registryConfig is
+// empty. PullOCIChart returns the path to the expanded chart directory.
+func PullOCIChart(ctx context.Context, chart, cacheDir, registryConfig string) (string, error) {
+	if err := ValidateOCIChart(chart); err != nil {
+		return "", err
+	}
+	ref := strings.TrimPrefix(chart, OCIScheme)
+
+	var configPaths []string
+	if registryConfig != "" {
+		configPaths = append(configPaths, registryConfig)
+	}
+	authClient, err := docker.NewClient(configPaths...)
+	if err != nil {
+		return "", fmt.Errorf("failed to load registry credentials: %w", err)
+	}
+	resolver, err := authClient.Resolver(ctx, http.DefaultClient, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to create registry resolver: %w", err)
+	}
+
+	store := content.NewMemoryStore()
+	allowedMediaTypes := []string{helmConfigMediaType, helmChartLayerMediaType, helmChartContentMediaType}
+	_, layers, err := oras.Pull(ctx, resolver, ref, store,
+		oras.WithPullEmptyNameAllowed(),
+		oras.WithAllowedMediaTypes(allowedMediaTypes),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to pull chart %s: %w", chart, err)
+	}
+
+	for _, layer := range layers {
+		if layer.MediaType != helmChartLayerMediaType && layer.MediaType != helmChartContentMediaType {
+			continue
+		}
+		_, b, ok := store.Get(layer)
+		if !ok {
+			return "", fmt.Errorf("failed to pull chart %s: content of layer %s not found", chart, layer.Digest)
+		}
+		return expandChart(b, filepath.Join(cacheDir, layer.Digest.Encoded()))
+	}
+	return "", fmt.Errorf("failed to pull chart %s: manifest has no chart content layer", chart)
+}
+
+// expandChart expands the chart archive b into dir, unless dir already
+// exists, and returns the path to the expanded chart directory.
+func expandChart(b []byte, dir string) (string, error) {
+	if chartDir, err := findChartDir(dir); err == nil {
+		return chartDir, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	// Expand into a temporary directory and rename it, so a partially expanded
+	// chart is never mistaken for a cached one.
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return "", fmt.Errorf("failed to create chart cache directory: %w", err)
+	}
+	tmpDir, err := ioutil.TempDir(filepath.Dir(dir), ".tmp-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create chart cache directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	archive := filepath.Join(tmpDir, "chart.tgz")
+	if err := ioutil.WriteFile(archive, b, 0644); err != nil {
+		return "", fmt.Errorf("failed to write chart archive: %w", err)
+	}
+	expandDir := filepath.Join(tmpDir, "chart")
+	if err := chartutil.ExpandFile(expandDir, archive); err != nil {
+		return "", fmt.Errorf("failed to expand chart archive: %w", err)
+	}
+	if err := os.Rename(expandDir, dir); err != nil {
+		return "", fmt.Errorf("failed to cache chart: %w", err)
+	}
+	return findChartDir(dir)
+}
+
+// findChartDir returns the path of the single chart directory in dir.
+func findChartDir(dir string) (string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, info := range infos {
+		if info.IsDir() {
+			return filepath.Join(dir, info.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("no chart found in %s", dir)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOCIChart(t *testing.T) {
+	tests := []struct {
+		chart     string
+		expectErr bool
+	}{
+		{chart: "oci://quay.io/example/nginx:0.1.0"},
+		{chart: "oci://localhost:5000/nginx:0.1.0"},
+		{chart: "oci://quay.io/example/nginx@sha256:0123456789abcdef"},
+		{chart: "oci://quay.io/example/nginx", expectErr: true},
+		{chart: "oci://localhost:5000/nginx", expectErr: true},
+		{chart: "oci://nginx:0.1.0", expectErr: true},
+		{chart: "oci://quay.io/", expectErr: true},
+	}
+
+	for _, test := range tests {
+		err := ValidateOCIChart(test.chart)
+		if test.expectErr {
+			assert.Error(t, err, test.chart)
+		} else {
+			assert.NoError(t, err, test.chart)
+		}
+	}
+}
+
+func TestExpandChart(t *testing.T) {
+	b, err := ioutil.ReadFile(filepath.Join("..", "..", "plugins", "helm", "v1", "chartutil", "testdata", "test-chart-1.2.3.tgz"))
+	assert.NoError(t, err)
+
+	cacheDir, err := ioutil.TempDir("", "chart-cache-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	dir := filepath.Join(cacheDir, "digest")
+	chartDir, err := expandChart(b, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "test-chart"), chartDir)
+	assert.FileExists(t, filepath.Join(chartDir, "Chart.yaml"))
+
+	// A cached chart is not expanded again.
+	cachedDir, err := expandChart(nil, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, chartDir, cachedDir)
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// releaseNameMaxLen is the maximum length of a release name allowed by Helm.
+const releaseNameMaxLen = 53
+
+// releaseNameData is the data that release name templates are executed with.
+// It only has fields that cannot change during a custom resource's lifetime,
+// since a new release name installs a new release and leaves the old one
+// behind.
+type releaseNameData struct {
+	Name      string
+	Namespace string
+	UID       apitypes.UID
+}
+
+// releaseNameFields are the fields of releaseNameData.
+var releaseNameFields = map[string]bool{"Name": true, "Namespace": true, "UID": true}
+
+// ParseReleaseNameTemplate parses text as a release name template. The
+// template is executed with the custom resource's Name, Namespace, and UID,
+// ex. "{{ .Namespace }}-{{ .Name }}". Referring to any other field is an
+// error.
+func ParseReleaseNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("releaseName").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReleaseNameFields(tmpl.Tree.Root); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// checkReleaseNameFields returns an error if node refers to a field that is
+// not one of releaseNameFields.
+func checkReleaseNameFields(node parse.Node) error {
+	var nodes []parse.Node
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			nodes = n.Nodes
+		}
+	case *parse.ActionNode:
+		nodes = []parse.Node{n.Pipe}
+	case *parse.PipeNode:
+		if n != nil {
+			for _, cmd := range n.Cmds {
+				nodes = append(nodes, cmd)
+			}
+		}
+	case *parse.CommandNode:
+		nodes = n.Args
+	case *parse.ChainNode:
+		nodes = []parse.Node{n.Node}
+	case *parse.IfNode:
+		nodes = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.RangeNode:
+		nodes = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.WithNode:
+		nodes = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.TemplateNode:
+		nodes = []parse.Node{n.Pipe}
+	case *parse.FieldNode:
+		return checkReleaseNameField(n.Ident[0])
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			return checkReleaseNameField(n.Ident[1])
+		}
+	}
+	for _, n := range nodes {
+		if err := checkReleaseNameFields(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkReleaseNameField(field string) error {
+	if !releaseNameFields[field] {
+		return fmt.Errorf("release name template refers to field %q: only .Name, .Namespace, and .UID are allowed",
+			field)
+	}
+	return nil
+}
+
+// executeReleaseNameTemplate returns the name tmpl gives cr's release.
+func executeReleaseNameTemplate(tmpl *template.Template, cr *unstructured.Unstructured) (string, error) {
+	data := releaseNameData{
+		Name:      cr.GetName(),
+		Namespace: cr.GetNamespace(),
+		UID:       cr.GetUID(),
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to execute release name template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// validateReleaseName returns an error if name is not a valid release name.
+func validateReleaseName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return fmt.Errorf("invalid release name %q: %s", name, strings.Join(errs, ", "))
+	}
+	if len(name) > releaseNameMaxLen {
+		return fmt.Errorf("invalid release name %q: must be no more than %d characters", name, releaseNameMaxLen)
+	}
+	return nil
+}
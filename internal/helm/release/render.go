@@ -0,0 +1,66 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Render renders the manifest of cr's release of the chart at chartDir, with
+// the values that a Manager created by a ManagerFactory with opts would
+// install the release with, as "helm template" does. Render does not contact
+// a cluster, so templates that look up cluster resources render as if the
+// resources do not exist. Chart hooks are appended to the manifest.
+func Render(chartDir string, cr *unstructured.Unstructured, overrideValues map[string]string,
+	opts ...ManagerFactoryOption) (string, error) {
+	f := &managerFactory{chartDir: chartDir}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	crChart, err := f.loadChart()
+	if err != nil {
+		return "", err
+	}
+	values, err := f.releaseValues(cr, overrideValues)
+	if err != nil {
+		return "", err
+	}
+
+	install := action.NewInstall(&action.Configuration{Log: func(_ string, _ ...interface{}) {}})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	if install.ReleaseName, err = f.releaseNameFor(cr); err != nil {
+		return "", err
+	}
+	install.Namespace = f.namespaceFor(cr)
+	rel, err := install.Run(crChart, values)
+	if err != nil {
+		return "", fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimSpace(rel.Manifest))
+	sb.WriteString("\n")
+	for _, h := range rel.Hooks {
+		fmt.Fprintf(&sb, "---\n# Source: %s\n%s\n", h.Path, strings.TrimSpace(h.Manifest))
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testChartDir = "../../plugins/helm/v1/chartutil/testdata/test-chart"
+
+func TestRender(t *testing.T) {
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"replicaCount": int64(3)},
+	}}
+	cr.SetName("example")
+	cr.SetNamespace("example-ns")
+
+	manifest, err := Render(testChartDir, cr, map[string]string{"service.type": "NodePort"})
+	assert.NoError(t, err)
+	assert.Contains(t, manifest, "# Source: test-chart/templates/deployment.yaml")
+	assert.Contains(t, manifest, "replicas: 3")
+	assert.Contains(t, manifest, "type: NodePort")
+	assert.Contains(t, manifest, "app.kubernetes.io/instance: example")
+	// Chart hooks are rendered too.
+	assert.Contains(t, manifest, "# Source: test-chart/templates/tests/")
+
+	manifest, err = Render(testChartDir, cr, nil, ReleaseNameSuffix("monitoring"))
+	assert.NoError(t, err)
+	assert.Contains(t, manifest, "app.kubernetes.io/instance: example-monitoring")
+
+	_, err = Render(testChartDir, &unstructured.Unstructured{Object: map[string]interface{}{}}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"time"
+
+	rpb "helm.sh/helm/v3/pkg/release"
+)
+
+// defaultRollbackTimeout is how long an upgrade made with RollbackOnFailure,
+// and its rollback, wait for resources when no upgrade timeout is set. It
+// matches the helm CLI's default.
+const defaultRollbackTimeout = 5 * time.Minute
+
+// defaultRollbackMaxHistory is the maximum number of revisions kept by an
+// upgrade made with RollbackOnFailure when no maximum history is set. Every
+// retry of a failing upgrade adds a failed and a rolled back revision, so
+// without a limit the release history grows until the upgrade succeeds. It
+// matches the helm CLI's default.
+const defaultRollbackMaxHistory = 10
+
+// RolledBackError is returned by a Manager when an upgrade made with
+// RollbackOnFailure failed and the release was successfully rolled back.
+// Release is the release created by the rollback, which is now deployed.
+type RolledBackError struct {
+	Release *rpb.Release
+	Err     error
+}
+
+func (e *RolledBackError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RolledBackError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/action"
+)
+
+func TestRollbackOnFailureMaxHistory(t *testing.T) {
+	tests := []struct {
+		name             string
+		rollback         bool
+		maxHistory       int
+		expectMaxHistory int
+	}{
+		{name: "no rollback keeps all revisions", rollback: false, maxHistory: 0, expectMaxHistory: 0},
+		{name: "rollback limits all revisions", rollback: true, maxHistory: 0,
+			expectMaxHistory: defaultRollbackMaxHistory},
+		{name: "rollback keeps configured limit", rollback: true, maxHistory: 3, expectMaxHistory: 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u := &action.Upgrade{MaxHistory: test.maxHistory}
+			assert.NoError(t, RollbackOnFailure(test.rollback)(u))
+			assert.Equal(t, test.rollback, u.Atomic)
+			assert.Equal(t, test.expectMaxHistory, u.MaxHistory)
+		})
+	}
+}
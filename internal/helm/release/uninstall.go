@@ -0,0 +1,177 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/operator-framework/operator-lib/handler"
+	"helm.sh/helm/v3/pkg/kube"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// defaultUninstallTimeout is how long uninstalls wait for resources to be
+// deleted if UninstallPolicy.Wait is set without a timeout.
+const defaultUninstallTimeout = 5 * time.Minute
+
+// UninstallPolicy configures how a Manager deletes a release's resources
+// when it uninstalls the release.
+type UninstallPolicy struct {
+	// PropagationPolicy is the deletion propagation policy used to delete
+	// the release's resources. Defaults to background deletion.
+	PropagationPolicy metav1.DeletionPropagation
+	// Wait, if true, waits until the release's resources are deleted, for
+	// up to Timeout, before the uninstall succeeds.
+	Wait bool
+	// Timeout is how long to wait for resources to be deleted and for
+	// uninstall hooks to complete.
+	Timeout time.Duration
+	// KeepResources, if true, orphans the release's resources by removing
+	// their owner references and owner annotations instead of deleting them.
+	// Uninstall hooks are not run.
+	KeepResources bool
+}
+
+// WithUninstallPolicy configures how a ManagerFactory's Managers delete the
+// resources of releases they uninstall.
+func WithUninstallPolicy(policy UninstallPolicy) ManagerFactoryOption {
+	return func(f *managerFactory) {
+		f.uninstallPolicy = policy
+	}
+}
+
+// uninstallingClient is a kube client that deletes resources according to an
+// UninstallPolicy.
+type uninstallingClient struct {
+	kube.Interface
+	policy   UninstallPolicy
+	ownerUID apitypes.UID
+}
+
+func (c *uninstallingClient) Delete(resources kube.ResourceList) (*kube.Result, []error) {
+	if c.policy.KeepResources {
+		return &kube.Result{}, c.orphan(resources)
+	}
+
+	var opts metav1.DeleteOptions
+	if c.policy.PropagationPolicy != "" {
+		opts.PropagationPolicy = &c.policy.PropagationPolicy
+	}
+	res := &kube.Result{}
+	var errs []error
+	_ = resources.Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		if _, err := helper.DeleteWithOptions(info.Namespace, info.Name, &opts); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete %s: %w", info.ObjectName(), err))
+			}
+			return nil
+		}
+		res.Deleted = append(res.Deleted, info)
+		return nil
+	})
+	if c.policy.Wait && len(errs) == 0 {
+		if err := c.waitForDeletion(res.Deleted); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return res, errs
+}
+
+// waitForDeletion waits until resources no longer exist.
+func (c *uninstallingClient) waitForDeletion(resources kube.ResourceList) error {
+	timeout := c.policy.Timeout
+	if timeout == 0 {
+		timeout = defaultUninstallTimeout
+	}
+	return wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		for _, info := range resources {
+			helper := resource.NewHelper(info.Client, info.Mapping)
+			_, err := helper.Get(info.Namespace, info.Name, false)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+// orphan removes the owner's references and the owner annotations from
+// resources.
+func (c *uninstallingClient) orphan(resources kube.ResourceList) []error {
+	var errs []error
+	_ = resources.Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		obj, err := helper.Get(info.Namespace, info.Name, false)
+		if apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get %s: %w", info.ObjectName(), err))
+			return nil
+		}
+		patch, err := c.orphanPatch(obj)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if _, err := helper.Patch(info.Namespace, info.Name, apitypes.MergePatchType, patch,
+			&metav1.PatchOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to orphan %s: %w", info.ObjectName(), err))
+		}
+		return nil
+	})
+	return errs
+}
+
+// orphanPatch returns a JSON merge patch that removes the owner's references
+// and the owner annotations from obj.
+func (c *uninstallingClient) orphanPatch(obj runtime.Object) ([]byte, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	ownerRefs := []metav1.OwnerReference{}
+	for _, ref := range accessor.GetOwnerReferences() {
+		if ref.UID != c.ownerUID {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"ownerReferences": ownerRefs,
+			"annotations": map[string]interface{}{
+				handler.NamespacedNameAnnotation: nil,
+				handler.TypeAnnotation:           nil,
+			},
+		},
+	})
+}
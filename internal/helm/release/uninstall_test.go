@@ -0,0 +1,45 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"testing"
+
+	"github.com/operator-framework/operator-lib/handler"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestOrphanPatch(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "example.com/v1", Kind: "Nginx", Name: "owner", UID: "owner-uid"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid"},
+	})
+	obj.SetAnnotations(map[string]string{
+		handler.NamespacedNameAnnotation: "default/owner",
+		handler.TypeAnnotation:           "Nginx.example.com",
+		"keep":                           "me",
+	})
+
+	c := &uninstallingClient{ownerUID: "owner-uid"}
+	patch, err := c.orphanPatch(obj)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"metadata": {
+		"ownerReferences": [{"apiVersion": "v1", "kind": "ConfigMap", "name": "other", "uid": "other-uid"}],
+		"annotations": {"`+handler.NamespacedNameAnnotation+`": null, "`+handler.TypeAnnotation+`": null}
+	}}`, string(patch))
+}
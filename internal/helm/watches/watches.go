@@ -20,10 +20,16 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"helm.sh/helm/v3/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/helm/conversion"
+	"github.com/operator-framework/operator-sdk/internal/helm/release"
 )
 
 const WatchesFile = "watches.yaml"
@@ -32,9 +38,159 @@ const WatchesFile = "watches.yaml"
 // custom resource.
 type Watch struct {
 	schema.GroupVersionKind `json:",inline"`
-	ChartDir                string            `json:"chart"`
-	WatchDependentResources *bool             `json:"watchDependentResources,omitempty"`
-	OverrideValues          map[string]string `json:"overrideValues,omitempty"`
+	// ChartDir is the path to a chart directory, or to a chart archive if
+	// ChartVerification is set, or an OCI chart reference of the form
+	// "oci://<registry>/<repository>:<tag>".
+	ChartDir                string             `json:"chart"`
+	WatchDependentResources *bool              `json:"watchDependentResources,omitempty"`
+	OverrideValues          map[string]string  `json:"overrideValues,omitempty"`
+	ChartVerification       *ChartVerification `json:"chartVerification,omitempty"`
+	// OverrideValuesFile, if set, is the path to a YAML file mapping chart
+	// value paths to override values, in the same format as OverrideValues.
+	// Values in OverrideValues take precedence over values in this file.
+	OverrideValuesFile string `json:"overrideValuesFile,omitempty"`
+	// DefaultValues, if set, are merged over the chart's values.yaml and
+	// under each custom resource's spec, so that several watches can share
+	// a chart with different defaults.
+	DefaultValues map[string]interface{} `json:"defaultValues,omitempty"`
+	// DefaultValuesFile, if set, is the path to a YAML values file merged
+	// under DefaultValues.
+	DefaultValuesFile string `json:"defaultValuesFile,omitempty"`
+	// InstallTimeout and UpgradeTimeout, if set, are how long installs and
+	// upgrades wait for the release's resources to become ready before the
+	// release fails.
+	InstallTimeout *metav1.Duration `json:"installTimeout,omitempty"`
+	UpgradeTimeout *metav1.Duration `json:"upgradeTimeout,omitempty"`
+	// ValidateValuesSchema, if true, validates the custom resource's spec
+	// against the chart's values.schema.json before installs and upgrades.
+	ValidateValuesSchema bool `json:"validateValuesSchema,omitempty"`
+	// RollbackOnFailure, if true, rolls the release back to its last
+	// successfully deployed revision when an upgrade fails.
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+	// MaxHistory, if set, is the maximum number of revisions kept for each
+	// release, overriding the operator's --max-release-history flag. If 0,
+	// revisions are never pruned.
+	MaxHistory *int `json:"maxHistory,omitempty"`
+	// DependentResources, if set, limits the kinds of dependent resources
+	// watched when WatchDependentResources is true.
+	DependentResources *DependentResources `json:"dependentResources,omitempty"`
+	// OperandNamespace, if set, configures the namespace that each custom
+	// resource's release deploys operands into, which is created or adopted
+	// before the release is installed or upgraded.
+	OperandNamespace *OperandNamespace `json:"operandNamespace,omitempty"`
+	// ReleaseNamespace, if set, is the namespace that releases of
+	// cluster-scoped custom resources are stored in. It is ignored for
+	// namespaced custom resources, whose releases are stored in their own
+	// namespace.
+	ReleaseNamespace string `json:"releaseNamespace,omitempty"`
+	// ReleaseName, if set, is a Go template that names each custom
+	// resource's release, executed with the custom resource's Name,
+	// Namespace, UID, Labels, and Annotations, ex.
+	// "{{ .Namespace }}-{{ .Name }}". If unset, releases are named after
+	// their custom resource.
+	ReleaseName string `json:"releaseName,omitempty"`
+	// AdoptExistingRelease, if true, takes ownership of an existing release
+	// with the same name as a custom resource's release, even if it was
+	// installed from another chart, instead of failing with a conflict.
+	AdoptExistingRelease bool `json:"adoptExistingRelease,omitempty"`
+	// AdoptExistingResources, if true, takes ownership of existing resources
+	// that a release install or upgrade would create, instead of failing
+	// because they already exist. Resources that belong to another release
+	// are not adopted.
+	AdoptExistingResources bool `json:"adoptExistingResources,omitempty"`
+	// DriftPatchStrategy, if set, is how drift between a release's manifest
+	// and its resources in the cluster is corrected, either "Merge" (the
+	// default) or "ServerSideApply".
+	DriftPatchStrategy release.PatchStrategy `json:"driftPatchStrategy,omitempty"`
+	// DriftPatchStrategyOverrides override DriftPatchStrategy for resources
+	// of specific kinds, ex. "Replace" for Jobs, whose pod templates are
+	// immutable.
+	DriftPatchStrategyOverrides []KindPatchStrategy `json:"driftPatchStrategyOverrides,omitempty"`
+	// Uninstall, if set, configures how a release's resources are deleted
+	// when its custom resource is deleted.
+	Uninstall *Uninstall `json:"uninstall,omitempty"`
+	// SubReleases are additional charts installed as separate releases for
+	// each custom resource, after the release of ChartDir is deployed.
+	SubReleases []SubRelease `json:"subReleases,omitempty"`
+	// Conversions map the fields of other versions of the kind to the fields
+	// of the watched version, and are served by the operator's conversion
+	// webhook.
+	Conversions []conversion.VersionMapping `json:"conversions,omitempty"`
+	// ConversionFile, if set, is the path to a YAML file of conversions, in
+	// the same format as Conversions, that are added to Conversions.
+	ConversionFile string `json:"conversionFile,omitempty"`
+}
+
+// SubRelease configures an additional chart installed for each custom
+// resource as a release named "<release-name>-<name>", where release-name is
+// the name of the custom resource's main release.
+type SubRelease struct {
+	// Name identifies the sub-release in the custom resource's status.
+	Name string `json:"name"`
+	// ChartDir is the path to a chart directory, or an OCI chart reference
+	// of the form "oci://<registry>/<repository>:<tag>".
+	ChartDir string `json:"chart"`
+	// ValuesField, if set, is the dot-separated path of the spec field used
+	// as the chart's values, ex. "monitoring". If unset, the whole spec is
+	// used.
+	ValuesField    string            `json:"valuesField,omitempty"`
+	OverrideValues map[string]string `json:"overrideValues,omitempty"`
+}
+
+// KindPatchStrategy is the drift patch strategy of a release's resources of a
+// kind. Kinds are matched by group and kind, regardless of version.
+type KindPatchStrategy struct {
+	metav1.GroupKind `json:",inline"`
+	// Strategy is "Merge", "ServerSideApply", or "Replace".
+	Strategy release.PatchStrategy `json:"strategy"`
+}
+
+// Uninstall configures how a release's resources are deleted when the
+// release is uninstalled.
+type Uninstall struct {
+	// PropagationPolicy is the deletion propagation policy used to delete
+	// the release's resources: "Foreground", "Background" (the default), or
+	// "Orphan".
+	PropagationPolicy metav1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+	// Wait, if true, waits until the release's resources are deleted before
+	// the custom resource's finalizer is removed.
+	Wait bool `json:"wait,omitempty"`
+	// Timeout, if set, is how long to wait for the release's resources to be
+	// deleted and for uninstall hooks to complete.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// KeepResources, if true, leaves the release's resources in the cluster,
+	// removing their references to the custom resource, instead of deleting
+	// them. Uninstall hooks are not run.
+	KeepResources bool `json:"keepResources,omitempty"`
+}
+
+// OperandNamespace configures a namespace named by a field of each custom
+// resource's spec.
+type OperandNamespace struct {
+	// ValuesField is the dot-separated path of the spec field holding the
+	// namespace name, ex. "operand.namespace".
+	ValuesField string `json:"valuesField"`
+	// Labels and Annotations are set on the namespace.
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// DependentResources selects the kinds of a release's resources that are
+// watched. Kinds are matched by group and kind, regardless of version.
+type DependentResources struct {
+	// Include, if not empty, lists the only kinds that are watched.
+	Include []metav1.GroupKind `json:"include,omitempty"`
+	// Exclude lists kinds that are never watched, even if included.
+	Exclude []metav1.GroupKind `json:"exclude,omitempty"`
+}
+
+// ChartVerification configures provenance verification of a chart archive.
+// When set, the watch's chart must be a chart archive with a provenance file
+// at "<chart>.prov", which is verified each time the chart is loaded.
+type ChartVerification struct {
+	// Keyring is the path to a keyring containing the public keys trusted
+	// to sign the chart.
+	Keyring string `json:"keyring"`
 }
 
 // UnmarshalYAML unmarshals an individual watch from the Helm watches.yaml file
@@ -98,10 +254,73 @@ func LoadReader(reader io.Reader) ([]Watch, error) {
 			return nil, fmt.Errorf("invalid GVK: %s: %w", gvk, err)
 		}
 
-		if _, err := chartutil.IsChartDir(w.ChartDir); err != nil {
+		if release.IsOCIChart(w.ChartDir) {
+			if w.ChartVerification != nil {
+				return nil, fmt.Errorf("invalid chart verification for %s: not supported for OCI charts", gvk)
+			}
+			if err := release.ValidateOCIChart(w.ChartDir); err != nil {
+				return nil, fmt.Errorf("invalid chart for %s: %w", gvk, err)
+			}
+		} else if w.ChartVerification != nil {
+			if err := verifyChartVerification(w.ChartDir, *w.ChartVerification); err != nil {
+				return nil, fmt.Errorf("invalid chart verification for %s: %w", gvk, err)
+			}
+		} else if _, err := chartutil.IsChartDir(w.ChartDir); err != nil {
 			return nil, fmt.Errorf("invalid chart directory %s: %w", w.ChartDir, err)
 		}
 
+		if err := verifyTimeout("installTimeout", w.InstallTimeout); err != nil {
+			return nil, fmt.Errorf("invalid timeout for %s: %w", gvk, err)
+		}
+		if err := verifyTimeout("upgradeTimeout", w.UpgradeTimeout); err != nil {
+			return nil, fmt.Errorf("invalid timeout for %s: %w", gvk, err)
+		}
+
+		if w.DependentResources != nil {
+			if err := verifyDependentResources(*w.DependentResources, w.WatchDependentResources); err != nil {
+				return nil, fmt.Errorf("invalid dependentResources for %s: %w", gvk, err)
+			}
+		}
+
+		if w.OperandNamespace != nil && w.OperandNamespace.ValuesField == "" {
+			return nil, fmt.Errorf("invalid operandNamespace for %s: valuesField must not be empty", gvk)
+		}
+
+		if w.ReleaseNamespace != "" {
+			if errs := validation.IsDNS1123Label(w.ReleaseNamespace); len(errs) != 0 {
+				return nil, fmt.Errorf("invalid releaseNamespace for %s: %s", gvk, strings.Join(errs, ", "))
+			}
+		}
+
+		if w.ReleaseName != "" {
+			if _, err := release.ParseReleaseNameTemplate(w.ReleaseName); err != nil {
+				return nil, fmt.Errorf("invalid releaseName for %s: %w", gvk, err)
+			}
+		}
+
+		if w.DriftPatchStrategy != "" {
+			if err := verifyPatchStrategy(w.DriftPatchStrategy); err != nil {
+				return nil, fmt.Errorf("invalid driftPatchStrategy for %s: %w", gvk, err)
+			}
+		}
+		if err := verifyKindPatchStrategies(w.DriftPatchStrategyOverrides); err != nil {
+			return nil, fmt.Errorf("invalid driftPatchStrategyOverrides for %s: %w", gvk, err)
+		}
+
+		if w.Uninstall != nil {
+			if err := verifyUninstall(*w.Uninstall); err != nil {
+				return nil, fmt.Errorf("invalid uninstall for %s: %w", gvk, err)
+			}
+		}
+
+		if err := verifySubReleases(w.SubReleases); err != nil {
+			return nil, fmt.Errorf("invalid subReleases for %s: %w", gvk, err)
+		}
+
+		if w.MaxHistory != nil && *w.MaxHistory < 0 {
+			return nil, fmt.Errorf("invalid maxHistory for %s: must not be negative", gvk)
+		}
+
 		if _, ok := watchesMap[gvk]; ok {
 			return nil, fmt.Errorf("duplicate GVK: %s", gvk)
 		}
@@ -110,7 +329,37 @@ func LoadReader(reader io.Reader) ([]Watch, error) {
 			trueVal := true
 			w.WatchDependentResources = &trueVal
 		}
+		if w.OverrideValuesFile != "" {
+			fileValues, err := loadOverrideValuesFile(w.OverrideValuesFile)
+			if err != nil {
+				return nil, fmt.Errorf("invalid overrideValuesFile for %s: %w", gvk, err)
+			}
+			for k, v := range w.OverrideValues {
+				fileValues[k] = v
+			}
+			w.OverrideValues = fileValues
+		}
+		if w.DefaultValuesFile != "" {
+			fileValues, err := chartutil.ReadValuesFile(w.DefaultValuesFile)
+			if err != nil {
+				return nil, fmt.Errorf("invalid defaultValuesFile for %s: %w", gvk, err)
+			}
+			w.DefaultValues = chartutil.CoalesceTables(w.DefaultValues, fileValues.AsMap())
+		}
+		if w.ConversionFile != "" {
+			fileConversions, err := conversion.LoadFile(w.ConversionFile)
+			if err != nil {
+				return nil, fmt.Errorf("invalid conversionFile for %s: %w", gvk, err)
+			}
+			w.Conversions = append(w.Conversions, fileConversions...)
+		}
+		if err := conversion.Validate(gvk.Version, w.Conversions); err != nil {
+			return nil, fmt.Errorf("invalid conversions for %s: %w", gvk, err)
+		}
 		w.OverrideValues = expandOverrideEnvs(w.OverrideValues)
+		for j := range w.SubReleases {
+			w.SubReleases[j].OverrideValues = expandOverrideEnvs(w.SubReleases[j].OverrideValues)
+		}
 		watches[i] = w
 	}
 	return watches, nil
@@ -127,6 +376,114 @@ func expandOverrideEnvs(in map[string]string) map[string]string {
 	return out
 }
 
+func loadOverrideValuesFile(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	if err := yaml.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func verifyChartVerification(chartPath string, v ChartVerification) error {
+	if v.Keyring == "" {
+		return errors.New("keyring must not be empty")
+	}
+	if _, err := os.Stat(v.Keyring); err != nil {
+		return fmt.Errorf("invalid keyring: %w", err)
+	}
+	info, err := os.Stat(chartPath)
+	if err != nil {
+		return fmt.Errorf("invalid chart archive: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("chart %s must be a chart archive, not a directory", chartPath)
+	}
+	return nil
+}
+
+func verifyPatchStrategy(strategy release.PatchStrategy) error {
+	switch strategy {
+	case release.PatchStrategyMerge, release.PatchStrategyServerSideApply, release.PatchStrategyReplace:
+		return nil
+	}
+	return fmt.Errorf("must be %q, %q, or %q", release.PatchStrategyMerge, release.PatchStrategyServerSideApply,
+		release.PatchStrategyReplace)
+}
+
+func verifyKindPatchStrategies(strategies []KindPatchStrategy) error {
+	kinds := map[metav1.GroupKind]struct{}{}
+	for _, s := range strategies {
+		if s.Kind == "" {
+			return fmt.Errorf("kind must not be empty for group %q", s.Group)
+		}
+		if _, ok := kinds[s.GroupKind]; ok {
+			return fmt.Errorf("duplicate kind %q in group %q", s.Kind, s.Group)
+		}
+		kinds[s.GroupKind] = struct{}{}
+		if err := verifyPatchStrategy(s.Strategy); err != nil {
+			return fmt.Errorf("invalid strategy for kind %q in group %q: %w", s.Kind, s.Group, err)
+		}
+	}
+	return nil
+}
+
+func verifyDependentResources(d DependentResources, watchDependentResources *bool) error {
+	if watchDependentResources != nil && !*watchDependentResources {
+		return errors.New("watchDependentResources must not be false")
+	}
+	for _, gk := range append(d.Include, d.Exclude...) {
+		if gk.Kind == "" {
+			return fmt.Errorf("kind must not be empty for group %q", gk.Group)
+		}
+	}
+	return nil
+}
+
+func verifySubReleases(subReleases []SubRelease) error {
+	names := map[string]struct{}{}
+	for _, sub := range subReleases {
+		if errs := validation.IsDNS1123Label(sub.Name); len(errs) != 0 {
+			return fmt.Errorf("invalid name %q: %s", sub.Name, strings.Join(errs, ", "))
+		}
+		if _, ok := names[sub.Name]; ok {
+			return fmt.Errorf("duplicate name %q", sub.Name)
+		}
+		names[sub.Name] = struct{}{}
+		if release.IsOCIChart(sub.ChartDir) {
+			if err := release.ValidateOCIChart(sub.ChartDir); err != nil {
+				return fmt.Errorf("invalid chart for %s: %w", sub.Name, err)
+			}
+		} else if _, err := chartutil.IsChartDir(sub.ChartDir); err != nil {
+			return fmt.Errorf("invalid chart directory %s: %w", sub.ChartDir, err)
+		}
+	}
+	return nil
+}
+
+func verifyUninstall(u Uninstall) error {
+	switch u.PropagationPolicy {
+	case "", metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan:
+	default:
+		return fmt.Errorf("propagationPolicy must be %q, %q, or %q", metav1.DeletePropagationForeground,
+			metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan)
+	}
+	if u.KeepResources && (u.Wait || u.PropagationPolicy != "") {
+		return errors.New("keepResources must not be set with wait or propagationPolicy")
+	}
+	return verifyTimeout("timeout", u.Timeout)
+}
+
+func verifyTimeout(field string, timeout *metav1.Duration) error {
+	if timeout != nil && timeout.Duration <= 0 {
+		return fmt.Errorf("%s must be positive", field)
+	}
+	return nil
+}
+
 func verifyGVK(gvk schema.GroupVersionKind) error {
 	// A GVK without a group is valid. Certain scenarios may cause a GVK
 	// without a group to fail in other ways later in the initialization
@@ -19,13 +19,19 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/operator-framework/operator-sdk/internal/helm/conversion"
+	"github.com/operator-framework/operator-sdk/internal/helm/release"
 )
 
 func TestLoadReader(t *testing.T) {
 	trueVal, falseVal := true, false
+	five := 5
 	testCases := []struct {
 		name          string
 		data          string
@@ -76,6 +82,147 @@ func TestLoadReader(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "valid override values file",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  overrideValuesFile: testdata/override-values.yaml
+  overrideValues:
+    image.tag: latest
+`,
+			env: map[string]string{"MY_REGISTRY": "quay.io/example"},
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					OverrideValuesFile:      "testdata/override-values.yaml",
+					OverrideValues: map[string]string{
+						"image.repository": "quay.io/example/nginx",
+						"image.tag":        "latest",
+						"proxy.httpProxy":  "http://proxy.example.com:3128",
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "nonexistent override values file",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  overrideValuesFile: testdata/nonexistent.yaml
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid default values for kinds sharing a chart",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: SmallKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  defaultValues:
+    replicaCount: 1
+- group: mygroup
+  version: v1alpha1
+  kind: LargeKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  defaultValuesFile: testdata/large-values.yaml
+  defaultValues:
+    resources:
+      limits:
+        memory: 8Gi
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "SmallKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					DefaultValues:           map[string]interface{}{"replicaCount": float64(1)},
+				},
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "LargeKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					DefaultValuesFile:       "testdata/large-values.yaml",
+					DefaultValues: map[string]interface{}{
+						"replicaCount": float64(5),
+						"resources": map[string]interface{}{
+							"limits": map[string]interface{}{"cpu": float64(2), "memory": "8Gi"},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "nonexistent default values file",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  defaultValuesFile: testdata/nonexistent.yaml
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid conversion file",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  conversions:
+  - version: v1
+  conversionFile: testdata/conversion.yaml
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					ConversionFile:          "testdata/conversion.yaml",
+					Conversions: []conversion.VersionMapping{
+						{Version: "v1"},
+						{
+							Version: "v1beta1",
+							Fields:  []conversion.FieldMapping{{From: "spec.replicas", To: "spec.replicaCount"}},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "nonexistent conversion file",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  conversionFile: testdata/nonexistent.yaml
+`,
+			expectErr: true,
+		},
+		{
+			name: "invalid conversion of watched version",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  conversions:
+  - version: v1alpha1
+`,
+			expectErr: true,
+		},
 		{
 			name: "multiple gvk",
 			data: `---
@@ -141,6 +288,579 @@ func TestLoadReader(t *testing.T) {
   version: v1alpha1
   kind: MyKind
   chart: nonexistent/path/to/chart
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid chart verification",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart-1.2.3.tgz
+  chartVerification:
+    keyring: watches_test.go
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart-1.2.3.tgz",
+					WatchDependentResources: &trueVal,
+					ChartVerification:       &ChartVerification{Keyring: "watches_test.go"},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "chart verification of chart directory",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  chartVerification:
+    keyring: watches_test.go
+`,
+			expectErr: true,
+		},
+		{
+			name: "chart verification without keyring",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart-1.2.3.tgz
+  chartVerification: {}
+`,
+			expectErr: true,
+		},
+		{
+			name: "chart verification with nonexistent keyring",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart-1.2.3.tgz
+  chartVerification:
+    keyring: nonexistent/pubring.gpg
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid timeouts",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  installTimeout: 5m
+  upgradeTimeout: 90s
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					InstallTimeout:          &metav1.Duration{Duration: 5 * time.Minute},
+					UpgradeTimeout:          &metav1.Duration{Duration: 90 * time.Second},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "valid values schema validation",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  validateValuesSchema: true
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					ValidateValuesSchema:    true,
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "valid rollback on failure",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  rollbackOnFailure: true
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					RollbackOnFailure:       true,
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "valid max history",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  maxHistory: 5
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					MaxHistory:              &five,
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "negative max history",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  maxHistory: -1
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid dependent resources",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  dependentResources:
+    include:
+    - group: apps
+      kind: Deployment
+    - kind: Service
+    exclude:
+    - kind: Endpoints
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					DependentResources: &DependentResources{
+						Include: []metav1.GroupKind{{Group: "apps", Kind: "Deployment"}, {Kind: "Service"}},
+						Exclude: []metav1.GroupKind{{Kind: "Endpoints"}},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "dependent resources without kind",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  dependentResources:
+    exclude:
+    - group: apps
+`,
+			expectErr: true,
+		},
+		{
+			name: "dependent resources with watchDependentResources false",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  watchDependentResources: false
+  dependentResources:
+    exclude:
+    - kind: Endpoints
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid operand namespace",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  operandNamespace:
+    valuesField: operand.namespace
+    labels:
+      team: web
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					OperandNamespace: &OperandNamespace{
+						ValuesField: "operand.namespace",
+						Labels:      map[string]string{"team": "web"},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "operand namespace without values field",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  operandNamespace:
+    labels:
+      team: web
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid release namespace",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  releaseNamespace: my-releases
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					ReleaseNamespace:        "my-releases",
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid release namespace",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  releaseNamespace: My_Releases
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid release name and adoption",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  releaseName: "{{ .Namespace }}-{{ .Name }}"
+  adoptExistingRelease: true
+  adoptExistingResources: true
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					ReleaseName:             "{{ .Namespace }}-{{ .Name }}",
+					AdoptExistingRelease:    true,
+					AdoptExistingResources:  true,
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid release name",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  releaseName: "{{ .Name "
+`,
+			expectErr: true,
+		},
+		{
+			name: "release name from labels",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  releaseName: '{{ index .Labels "app.kubernetes.io/instance" }}'
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid drift patch strategy",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  driftPatchStrategy: ServerSideApply
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					DriftPatchStrategy:      release.PatchStrategyServerSideApply,
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid drift patch strategy",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  driftPatchStrategy: Update
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid drift patch strategy overrides",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  driftPatchStrategy: Merge
+  driftPatchStrategyOverrides:
+  - group: batch
+    kind: Job
+    strategy: Replace
+  - group: apps
+    kind: Deployment
+    strategy: ServerSideApply
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					DriftPatchStrategy:      release.PatchStrategyMerge,
+					DriftPatchStrategyOverrides: []KindPatchStrategy{
+						{GroupKind: metav1.GroupKind{Group: "batch", Kind: "Job"}, Strategy: release.PatchStrategyReplace},
+						{GroupKind: metav1.GroupKind{Group: "apps", Kind: "Deployment"}, Strategy: release.PatchStrategyServerSideApply},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid drift patch strategy override",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  driftPatchStrategyOverrides:
+  - group: batch
+    kind: Job
+    strategy: Update
+`,
+			expectErr: true,
+		},
+		{
+			name: "drift patch strategy override without kind",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  driftPatchStrategyOverrides:
+  - group: batch
+    strategy: Replace
+`,
+			expectErr: true,
+		},
+		{
+			name: "duplicate drift patch strategy overrides",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  driftPatchStrategyOverrides:
+  - group: batch
+    kind: Job
+    strategy: Replace
+  - group: batch
+    kind: Job
+    strategy: Merge
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid uninstall",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  uninstall:
+    propagationPolicy: Foreground
+    wait: true
+    timeout: 2m
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					Uninstall: &Uninstall{
+						PropagationPolicy: metav1.DeletePropagationForeground,
+						Wait:              true,
+						Timeout:           &metav1.Duration{Duration: 2 * time.Minute},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid uninstall propagation policy",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  uninstall:
+    propagationPolicy: Cascade
+`,
+			expectErr: true,
+		},
+		{
+			name: "invalid uninstall keepResources with wait",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  uninstall:
+    keepResources: true
+    wait: true
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid sub-releases",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  subReleases:
+  - name: monitoring
+    chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+    valuesField: monitoring
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+					WatchDependentResources: &trueVal,
+					SubReleases: []SubRelease{
+						{
+							Name:        "monitoring",
+							ChartDir:    "../../../internal/plugins/helm/v1/chartutil/testdata/test-chart",
+							ValuesField: "monitoring",
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "invalid duplicate sub-release names",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  subReleases:
+  - name: monitoring
+    chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  - name: monitoring
+    chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+`,
+			expectErr: true,
+		},
+		{
+			name: "invalid sub-release chart",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  subReleases:
+  - name: monitoring
+    chart: nonexistent/path/to/chart
+`,
+			expectErr: true,
+		},
+		{
+			name: "valid oci chart",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: oci://quay.io/example/test-chart:1.2.3
+`,
+			expectWatches: []Watch{
+				{
+					GroupVersionKind:        schema.GroupVersionKind{Group: "mygroup", Version: "v1alpha1", Kind: "MyKind"},
+					ChartDir:                "oci://quay.io/example/test-chart:1.2.3",
+					WatchDependentResources: &trueVal,
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "oci chart without tag",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: oci://quay.io/example/test-chart
+`,
+			expectErr: true,
+		},
+		{
+			name: "chart verification of oci chart",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: oci://quay.io/example/test-chart:1.2.3
+  chartVerification:
+    keyring: watches_test.go
+`,
+			expectErr: true,
+		},
+		{
+			name: "non-positive timeout",
+			data: `---
+- group: mygroup
+  version: v1alpha1
+  kind: MyKind
+  chart: ../../../internal/plugins/helm/v1/chartutil/testdata/test-chart
+  installTimeout: 0s
 `,
 			expectErr: true,
 		},
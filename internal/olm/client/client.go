@@ -34,7 +34,9 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -97,40 +99,147 @@ func (c Client) DoCreate(ctx context.Context, objs ...runtime.Object) error {
 	return nil
 }
 
+// FieldManager is the field manager of objects that DoCreateOrUpdate applies.
+const FieldManager = "operator-sdk"
+
+// DoCreateOrUpdate creates each of objs, or, if it already exists, updates
+// it to match obj by server-side apply. Fields of existing objects that obj
+// sets are taken over from other field managers, so that drifted objects
+// are reconciled.
+func (c Client) DoCreateOrUpdate(ctx context.Context, objs ...runtime.Object) error {
+	for _, obj := range objs {
+		a, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		log.Infof("  Creating %s %q", kind, getName(a.GetNamespace(), a.GetName()))
+		err = c.KubeClient.Create(ctx, obj)
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		log.Infof("    %s %q already exists, applying", kind, getName(a.GetNamespace(), a.GetName()))
+		// Apply the object as defined, without server-populated metadata.
+		applyObj := obj.DeepCopyObject()
+		if a, err = meta.Accessor(applyObj); err != nil {
+			return err
+		}
+		a.SetResourceVersion("")
+		a.SetUID("")
+		a.SetManagedFields(nil)
+		if err := c.KubeClient.Patch(ctx, applyObj, client.Apply, client.FieldOwner(FieldManager),
+			client.ForceOwnership); err != nil {
+			return fmt.Errorf("error applying %s %q: %v", kind, getName(a.GetNamespace(), a.GetName()), err)
+		}
+	}
+	return nil
+}
+
+// DeleteOptions configures how DoDeleteWithOptions deletes objects.
+type DeleteOptions struct {
+	// PropagationPolicy is the deletion propagation policy of each object.
+	// Defaults to background deletion. With foreground deletion, an object is
+	// not deleted until its dependents are.
+	PropagationPolicy metav1.DeletionPropagation
+	// Timeout, if non-zero, is how long to wait for each object to be
+	// deleted. Objects are waited on until ctx is done otherwise.
+	Timeout time.Duration
+}
+
+// DeletionBlockedError is returned when an object is not deleted in time,
+// and lists the finalizers that are blocking its deletion.
+type DeletionBlockedError struct {
+	Kind       string
+	Name       string
+	Finalizers []string
+}
+
+func (e *DeletionBlockedError) Error() string {
+	msg := fmt.Sprintf("timed out waiting for %s %q to be deleted", e.Kind, e.Name)
+	if len(e.Finalizers) == 0 {
+		return msg
+	}
+	msg = fmt.Sprintf("%s: blocked by finalizers %s", msg, strings.Join(e.Finalizers, ", "))
+	for _, f := range e.Finalizers {
+		if f == metav1.FinalizerDeleteDependents {
+			msg += " (waiting for its dependents to be deleted)"
+			break
+		}
+	}
+	return msg
+}
+
+// DoDelete deletes objs in the background, waiting until each is deleted
+// before deleting the next.
 func (c Client) DoDelete(ctx context.Context, objs ...runtime.Object) error {
+	return c.DoDeleteWithOptions(ctx, DeleteOptions{}, objs...)
+}
+
+// DoDeleteWithOptions deletes objs according to opts, waiting until each is
+// deleted before deleting the next. If an object is not deleted in time, a
+// *DeletionBlockedError is returned.
+func (c Client) DoDeleteWithOptions(ctx context.Context, opts DeleteOptions, objs ...runtime.Object) error {
+	propagationPolicy := opts.PropagationPolicy
+	if propagationPolicy == "" {
+		propagationPolicy = metav1.DeletePropagationBackground
+	}
 	for _, obj := range objs {
 		a, err := meta.Accessor(obj)
 		if err != nil {
 			return err
 		}
 		kind := obj.GetObjectKind().GroupVersionKind().Kind
-		log.Infof("  Deleting %s %q", kind, getName(a.GetNamespace(), a.GetName()))
-		err = c.KubeClient.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		name := getName(a.GetNamespace(), a.GetName())
+		log.Infof("  Deleting %s %q", kind, name)
+		err = c.KubeClient.Delete(ctx, obj, client.PropagationPolicy(propagationPolicy))
 		if err != nil {
 			if !apierrors.IsNotFound(err) {
 				return err
 			}
-			log.Infof("    %s %q does not exist", kind, getName(a.GetNamespace(), a.GetName()))
+			log.Infof("    %s %q does not exist", kind, name)
 		}
-		key, err := client.ObjectKeyFromObject(obj)
-		if err != nil {
-			return err
-		}
-		if err := wait.PollImmediateUntil(time.Millisecond*100, func() (bool, error) {
-			err := c.KubeClient.Get(ctx, key, obj)
-			if apierrors.IsNotFound(err) {
-				return true, nil
-			} else if err != nil {
-				return false, err
+		if err := c.waitForDeletion(ctx, opts.Timeout, obj); err != nil {
+			if !errors.Is(err, wait.ErrWaitTimeout) {
+				return err
 			}
-			return false, nil
-		}, ctx.Done()); err != nil {
-			return err
+			return &DeletionBlockedError{Kind: kind, Name: name, Finalizers: a.GetFinalizers()}
 		}
 	}
 	return nil
 }
 
+// waitForDeletion waits until obj is deleted, for up to timeout if non-zero,
+// and returns wait.ErrWaitTimeout if it is not. obj is updated with its
+// latest state while waiting.
+func (c Client) waitForDeletion(ctx context.Context, timeout time.Duration, obj runtime.Object) error {
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err = wait.PollImmediateUntil(time.Millisecond*100, func() (bool, error) {
+		err := c.KubeClient.Get(ctx, key, obj)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+		return false, nil
+	}, ctx.Done())
+	if err != nil && ctx.Err() != nil {
+		// A Get cut short by the deadline also means the wait timed out.
+		return wait.ErrWaitTimeout
+	}
+	return err
+}
+
 func getName(namespace, name string) string {
 	if namespace != "" {
 		name = fmt.Sprintf("%s/%s", namespace, name)
@@ -138,7 +247,39 @@ func getName(namespace, name string) string {
 	return name
 }
 
+// WaitOptions configures how DoRolloutWaitWithOptions, DoCSVWaitWithOptions,
+// and DoCRDWaitWithOptions poll and report progress.
+type WaitOptions struct {
+	// Interval is the time between polls. Defaults to 1 second.
+	Interval time.Duration
+	// Progress, if set, is called with each progress message of a wait
+	// instead of the message being logged.
+	Progress func(msg string)
+}
+
+func (o WaitOptions) interval() time.Duration {
+	if o.Interval <= 0 {
+		return time.Second
+	}
+	return o.Interval
+}
+
+func (o WaitOptions) progress(format string, args ...interface{}) {
+	if o.Progress != nil {
+		o.Progress(fmt.Sprintf(format, args...))
+		return
+	}
+	log.Printf("  "+format, args...)
+}
+
+// DoRolloutWait waits for Deployment key to roll out using default WaitOptions.
 func (c Client) DoRolloutWait(ctx context.Context, key types.NamespacedName) error {
+	return c.DoRolloutWaitWithOptions(ctx, key, WaitOptions{})
+}
+
+// DoRolloutWaitWithOptions waits for Deployment key to roll out, polling and
+// reporting progress according to opts.
+func (c Client) DoRolloutWaitWithOptions(ctx context.Context, key types.NamespacedName, opts WaitOptions) error {
 	onceReplicasUpdated := sync.Once{}
 	oncePendingTermination := sync.Once{}
 	onceNotAvailable := sync.Once{}
@@ -157,39 +298,46 @@ func (c Client) DoRolloutWait(ctx context.Context, key types.NamespacedName) err
 			}
 			if deployment.Spec.Replicas != nil && deployment.Status.UpdatedReplicas < *deployment.Spec.Replicas {
 				onceReplicasUpdated.Do(func() {
-					log.Printf(
-						"  Waiting for Deployment %q to rollout: %d out of %d new replicas have been updated",
+					opts.progress("Waiting for Deployment %q to rollout: %d out of %d new replicas have been updated",
 						key, deployment.Status.UpdatedReplicas, *deployment.Spec.Replicas)
 				})
 				return false, nil
 			}
 			if deployment.Status.Replicas > deployment.Status.UpdatedReplicas {
 				oncePendingTermination.Do(func() {
-					log.Printf("  Waiting for Deployment %q to rollout: %d old replicas are pending termination",
+					opts.progress("Waiting for Deployment %q to rollout: %d old replicas are pending termination",
 						key, deployment.Status.Replicas-deployment.Status.UpdatedReplicas)
 				})
 				return false, nil
 			}
 			if deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas {
 				onceNotAvailable.Do(func() {
-					log.Printf("  Waiting for Deployment %q to rollout: %d of %d updated replicas are available",
+					opts.progress("Waiting for Deployment %q to rollout: %d of %d updated replicas are available",
 						key, deployment.Status.AvailableReplicas, deployment.Status.UpdatedReplicas)
 				})
 				return false, nil
 			}
-			log.Printf("  Deployment %q successfully rolled out", key)
+			opts.progress("Deployment %q successfully rolled out", key)
 			return true, nil
 		}
 		onceSpecUpdate.Do(func() {
-			log.Printf("Waiting for Deployment %q to rollout: waiting for deployment spec update to be observed",
+			opts.progress("Waiting for Deployment %q to rollout: waiting for deployment spec update to be observed",
 				key)
 		})
 		return false, nil
 	}
-	return wait.PollImmediateUntil(time.Second, rolloutComplete, ctx.Done())
+	return wait.PollImmediateUntil(opts.interval(), rolloutComplete, ctx.Done())
 }
 
+// DoCSVWait waits for ClusterServiceVersion key to reach phase Succeeded
+// using default WaitOptions.
 func (c Client) DoCSVWait(ctx context.Context, key types.NamespacedName) error {
+	return c.DoCSVWaitWithOptions(ctx, key, WaitOptions{})
+}
+
+// DoCSVWaitWithOptions waits for ClusterServiceVersion key to reach phase
+// Succeeded, polling and reporting progress according to opts.
+func (c Client) DoCSVWaitWithOptions(ctx context.Context, key types.NamespacedName, opts WaitOptions) error {
 	var (
 		curPhase olmapiv1alpha1.ClusterServiceVersionPhase
 		newPhase olmapiv1alpha1.ClusterServiceVersionPhase
@@ -202,7 +350,7 @@ func (c Client) DoCSVWait(ctx context.Context, key types.NamespacedName) error {
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				once.Do(func() {
-					log.Printf("  Waiting for ClusterServiceVersion %q to appear", key)
+					opts.progress("Waiting for ClusterServiceVersion %q to appear", key)
 				})
 				return false, nil
 			}
@@ -211,7 +359,7 @@ func (c Client) DoCSVWait(ctx context.Context, key types.NamespacedName) error {
 		newPhase = csv.Status.Phase
 		if newPhase != curPhase {
 			curPhase = newPhase
-			log.Printf("  Found ClusterServiceVersion %q phase: %s", key, curPhase)
+			opts.progress("Found ClusterServiceVersion %q phase: %s", key, curPhase)
 		}
 
 		switch curPhase {
@@ -224,7 +372,7 @@ func (c Client) DoCSVWait(ctx context.Context, key types.NamespacedName) error {
 		}
 	}
 
-	err := wait.PollImmediateUntil(time.Second, csvPhaseSucceeded, ctx.Done())
+	err := wait.PollImmediateUntil(opts.interval(), csvPhaseSucceeded, ctx.Done())
 	if err != nil && errors.Is(err, context.DeadlineExceeded) {
 		if depCheckErr := c.printDeploymentErrors(ctx, key, csv); depCheckErr != nil {
 			return fmt.Errorf("error printing operator resource errors: %v %v", err, depCheckErr)
@@ -233,6 +381,155 @@ func (c Client) DoCSVWait(ctx context.Context, key types.NamespacedName) error {
 	return err
 }
 
+// APIServiceGVK is the group, version, and kind of an aggregated API registration.
+var APIServiceGVK = schema.GroupVersionKind{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"}
+
+// DoAPIServiceWait waits for the APIService name to report condition Available.
+// The APIService is read as unstructured data so that the aggregator API
+// types need not be registered with the client's scheme.
+func (c Client) DoAPIServiceWait(ctx context.Context, name string) error {
+	once := sync.Once{}
+	var lastReason string
+
+	apiServiceAvailable := func() (bool, error) {
+		apiService := unstructured.Unstructured{}
+		apiService.SetGroupVersionKind(APIServiceGVK)
+		err := c.KubeClient.Get(ctx, types.NamespacedName{Name: name}, &apiService)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				once.Do(func() {
+					log.Printf("  Waiting for APIService %q to appear", name)
+				})
+				return false, nil
+			}
+			return false, err
+		}
+		conditions, _, err := unstructured.NestedSlice(apiService.Object, "status", "conditions")
+		if err != nil {
+			return false, fmt.Errorf("error reading APIService %q conditions: %v", name, err)
+		}
+		for _, cond := range conditions {
+			condMap, ok := cond.(map[string]interface{})
+			if !ok || condMap["type"] != "Available" {
+				continue
+			}
+			if condMap["status"] == string(corev1.ConditionTrue) {
+				log.Printf("  APIService %q is available", name)
+				return true, nil
+			}
+			if reason, _ := condMap["reason"].(string); reason != lastReason {
+				lastReason = reason
+				log.Printf("  Waiting for APIService %q to become available: %s", name, reason)
+			}
+		}
+		return false, nil
+	}
+	return wait.PollImmediateUntil(time.Second, apiServiceAvailable, ctx.Done())
+}
+
+// catalogSourceStateReady is the gRPC connection state of a CatalogSource
+// whose registry server can be queried.
+const catalogSourceStateReady = "READY"
+
+// DoCatalogSourceReadyWait waits for the registry server of the CatalogSource
+// key to accept connections.
+func (c Client) DoCatalogSourceReadyWait(ctx context.Context, key types.NamespacedName) error {
+	once := sync.Once{}
+	var curState string
+
+	catalogSourceReady := func() (bool, error) {
+		cs := olmapiv1alpha1.CatalogSource{}
+		err := c.KubeClient.Get(ctx, key, &cs)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				once.Do(func() {
+					log.Printf("  Waiting for CatalogSource %q to appear", key)
+				})
+				return false, nil
+			}
+			return false, err
+		}
+		if cs.Status.GRPCConnectionState == nil {
+			return false, nil
+		}
+		if newState := cs.Status.GRPCConnectionState.LastObservedState; newState != curState {
+			curState = newState
+			log.Printf("  Found CatalogSource %q connection state: %s", key, curState)
+		}
+		return curState == catalogSourceStateReady, nil
+	}
+	return wait.PollImmediateUntil(time.Second, catalogSourceReady, ctx.Done())
+}
+
+// CRDGVK is the group, version, and kind of a CustomResourceDefinition.
+var CRDGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// crdV1beta1GVK is the group, version, and kind of a CustomResourceDefinition
+// on clusters older than Kubernetes 1.16, which do not serve CRDGVK.
+var crdV1beta1GVK = CRDGVK.GroupKind().WithVersion("v1beta1")
+
+// DoCRDWait waits for the CustomResourceDefinition key to be established
+// using default WaitOptions.
+func (c Client) DoCRDWait(ctx context.Context, key types.NamespacedName) error {
+	return c.DoCRDWaitWithOptions(ctx, key, WaitOptions{})
+}
+
+// DoCRDWaitWithOptions waits for the CustomResourceDefinition key to report
+// condition Established, after which its custom resources can be created,
+// polling and reporting progress according to opts. An error is returned if
+// the CRD's names are not accepted, since it will never become established.
+// The CRD is read as unstructured data so that the apiextensions types need
+// not be registered with the client's scheme, from apiextensions.k8s.io/v1 or,
+// if the cluster does not serve it, v1beta1.
+func (c Client) DoCRDWaitWithOptions(ctx context.Context, key types.NamespacedName, opts WaitOptions) error {
+	once := sync.Once{}
+	gvk := CRDGVK
+
+	crdEstablished := func() (bool, error) {
+		crd := unstructured.Unstructured{}
+		crd.SetGroupVersionKind(gvk)
+		err := c.KubeClient.Get(ctx, key, &crd)
+		if meta.IsNoMatchError(err) && gvk == CRDGVK {
+			gvk = crdV1beta1GVK
+			crd.SetGroupVersionKind(gvk)
+			err = c.KubeClient.Get(ctx, key, &crd)
+		}
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				once.Do(func() {
+					opts.progress("Waiting for CustomResourceDefinition %q to appear", key.Name)
+				})
+				return false, nil
+			}
+			return false, err
+		}
+		conditions, _, err := unstructured.NestedSlice(crd.Object, "status", "conditions")
+		if err != nil {
+			return false, fmt.Errorf("error reading CustomResourceDefinition %q conditions: %v", key.Name, err)
+		}
+		for _, cond := range conditions {
+			condMap, ok := cond.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch condMap["type"] {
+			case "NamesAccepted":
+				if condMap["status"] == string(corev1.ConditionFalse) {
+					return false, fmt.Errorf("CustomResourceDefinition %q names not accepted: %v", key.Name,
+						condMap["message"])
+				}
+			case "Established":
+				if condMap["status"] == string(corev1.ConditionTrue) {
+					opts.progress("CustomResourceDefinition %q is established", key.Name)
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+	return wait.PollImmediateUntil(opts.interval(), crdEstablished, ctx.Done())
+}
+
 // TODO(btenneti) Refactor function to collect errors into customized error and return.
 // printDeploymentErrors function loops through deployment specs of a given CSV, and prints reason
 // in case of failures, based on deployment condition.
@@ -15,14 +15,24 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	olmapiv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"k8s.io/apimachinery/pkg/types"
 
@@ -193,4 +203,389 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Describe("DoCreateOrUpdate", func() {
+		It("creates objects that do not exist", func() {
+			cm := &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+				Data:       map[string]string{"key": "value"},
+			}
+			c := Client{KubeClient: fake.NewFakeClient()}
+			Expect(c.DoCreateOrUpdate(context.TODO(), cm.DeepCopy())).To(Succeed())
+
+			created := &corev1.ConfigMap{}
+			Expect(c.KubeClient.Get(context.TODO(), types.NamespacedName{Namespace: "testns", Name: "test"},
+				created)).To(Succeed())
+			Expect(created.Data).To(Equal(cm.Data))
+		})
+
+		It("applies objects that already exist", func() {
+			existing := &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+				Data:       map[string]string{"key": "drifted"},
+			}
+			cm := existing.DeepCopy()
+			cm.Data = map[string]string{"key": "value"}
+			cm.UID = "c0ffee"
+			cm.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}
+
+			kc := &applyClient{Client: fake.NewFakeClient(existing)}
+			c := Client{KubeClient: kc}
+			Expect(c.DoCreateOrUpdate(context.TODO(), cm)).To(Succeed())
+
+			Expect(kc.applied).To(HaveLen(1))
+			applied := kc.applied[0].(*corev1.ConfigMap)
+			Expect(applied.Data).To(Equal(cm.Data))
+			Expect(applied.UID).To(BeEmpty())
+			Expect(applied.ManagedFields).To(BeNil())
+			opts := &client.PatchOptions{}
+			opts.ApplyOptions(kc.opts)
+			Expect(opts.FieldManager).To(Equal(FieldManager))
+			Expect(opts.Force).NotTo(BeNil())
+			Expect(*opts.Force).To(BeTrue())
+			// The passed object is not modified.
+			Expect(cm.UID).To(BeEquivalentTo("c0ffee"))
+		})
+
+		It("returns apply errors", func() {
+			existing := &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+			}
+			kc := &applyClient{Client: fake.NewFakeClient(existing), err: errors.New("conflict")}
+			c := Client{KubeClient: kc}
+			err := c.DoCreateOrUpdate(context.TODO(), existing.DeepCopy())
+			Expect(err).To(MatchError(`error applying ConfigMap "testns/test": conflict`))
+		})
+	})
+
+	Describe("DoDeleteWithOptions", func() {
+		var cm *corev1.ConfigMap
+
+		BeforeEach(func() {
+			cm = &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "testns",
+					Finalizers: []string{"example.com/cleanup"},
+				},
+			}
+		})
+
+		It("deletes objects", func() {
+			c := Client{KubeClient: fake.NewFakeClient(cm.DeepCopy())}
+			err := c.DoDeleteWithOptions(context.TODO(), DeleteOptions{
+				PropagationPolicy: metav1.DeletePropagationForeground,
+			}, cm)
+			Expect(err).To(BeNil())
+		})
+
+		It("reports the finalizers blocking deletion", func() {
+			c := Client{KubeClient: noDeleteClient{fake.NewFakeClient(cm.DeepCopy())}}
+			err := c.DoDeleteWithOptions(context.TODO(), DeleteOptions{Timeout: 300 * time.Millisecond}, cm)
+			var blockedErr *DeletionBlockedError
+			Expect(errors.As(err, &blockedErr)).To(BeTrue())
+			Expect(blockedErr.Finalizers).To(Equal([]string{"example.com/cleanup"}))
+			Expect(err.Error()).To(Equal(`timed out waiting for ConfigMap "testns/test" to be deleted: ` +
+				`blocked by finalizers example.com/cleanup`))
+		})
+
+		It("reports dependents blocking foreground deletion", func() {
+			err := &DeletionBlockedError{
+				Kind:       "Deployment",
+				Name:       "olm/olm-operator",
+				Finalizers: []string{metav1.FinalizerDeleteDependents},
+			}
+			Expect(err.Error()).To(HaveSuffix("(waiting for its dependents to be deleted)"))
+		})
+	})
+
+	Describe("Status.Report", func() {
+		It("reports the condition of each resource", func() {
+			deployGVK := appsv1.SchemeGroupVersion.WithKind("Deployment")
+			csvGVK := olmapiv1alpha1.SchemeGroupVersion.WithKind("ClusterServiceVersion")
+			status := Status{Resources: []ResourceStatus{
+				{
+					NamespacedName: types.NamespacedName{Namespace: "olm", Name: "olm-operator"},
+					GVK:            deployGVK,
+					Resource:       &unstructured.Unstructured{},
+				},
+				{
+					NamespacedName: types.NamespacedName{Namespace: "olm", Name: "catalog-operator"},
+					GVK:            deployGVK,
+					Error:          apierrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "catalog-operator"),
+				},
+				{
+					NamespacedName: types.NamespacedName{Namespace: "olm", Name: "packageserver"},
+					GVK:            csvGVK,
+					Error:          errors.New("connection refused"),
+				},
+			}}
+
+			report := status.Report()
+			Expect(report.Healthy).To(BeFalse())
+			Expect(report.Resources).To(HaveLen(3))
+			Expect(report.Resources[0]).To(Equal(ResourceStatusReport{
+				Name:       "olm-operator",
+				Namespace:  "olm",
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Condition:  ConditionInstalled,
+			}))
+			Expect(report.Resources[1].Condition).To(Equal(ConditionNotFound))
+			Expect(report.Resources[2].Condition).To(Equal(ConditionError))
+			Expect(report.Resources[2].Error).To(Equal("connection refused"))
+			Expect(report.Resources[2].APIVersion).To(Equal("operators.coreos.com/v1alpha1"))
+		})
+
+		It("reports a status with all resources installed as healthy", func() {
+			status := Status{Resources: []ResourceStatus{{
+				NamespacedName: types.NamespacedName{Namespace: "olm", Name: "olm-operator"},
+				GVK:            appsv1.SchemeGroupVersion.WithKind("Deployment"),
+				Resource:       &unstructured.Unstructured{},
+			}}}
+			Expect(status.Report().Healthy).To(BeTrue())
+		})
+	})
+
+	Describe("DoAPIServiceWait", func() {
+		newAPIService := func(status string) *unstructured.Unstructured {
+			apiService := &unstructured.Unstructured{}
+			apiService.SetGroupVersionKind(APIServiceGVK)
+			apiService.SetName("v1.packages.operators.coreos.com")
+			Expect(unstructured.SetNestedSlice(apiService.Object, []interface{}{
+				map[string]interface{}{"type": "Available", "status": status, "reason": "Passed"},
+			}, "status", "conditions")).To(Succeed())
+			return apiService
+		}
+
+		It("returns once the APIService is available", func() {
+			c := Client{KubeClient: fake.NewFakeClient(newAPIService("True"))}
+			Expect(c.DoAPIServiceWait(context.TODO(), "v1.packages.operators.coreos.com")).To(Succeed())
+		})
+
+		It("times out if the APIService is not available", func() {
+			c := Client{KubeClient: fake.NewFakeClient(newAPIService("False"))}
+			ctx, cancel := context.WithTimeout(context.TODO(), 300*time.Millisecond)
+			defer cancel()
+			Expect(c.DoAPIServiceWait(ctx, "v1.packages.operators.coreos.com")).NotTo(Succeed())
+		})
+	})
+
+	Describe("DoCRDWait", func() {
+		key := types.NamespacedName{Name: "memcacheds.cache.example.com"}
+		newCRD := func(conditions ...interface{}) *unstructured.Unstructured {
+			crd := &unstructured.Unstructured{}
+			crd.SetGroupVersionKind(CRDGVK)
+			crd.SetName(key.Name)
+			Expect(unstructured.SetNestedSlice(crd.Object, conditions, "status", "conditions")).To(Succeed())
+			return crd
+		}
+
+		It("returns once the CRD is established", func() {
+			c := Client{KubeClient: fake.NewFakeClient(newCRD(
+				map[string]interface{}{"type": "NamesAccepted", "status": "True"},
+				map[string]interface{}{"type": "Established", "status": "True"},
+			))}
+			Expect(c.DoCRDWait(context.TODO(), key)).To(Succeed())
+		})
+
+		It("returns an error if the CRD names are not accepted", func() {
+			c := Client{KubeClient: fake.NewFakeClient(newCRD(
+				map[string]interface{}{"type": "NamesAccepted", "status": "False", "message": "name conflict"},
+			))}
+			Expect(c.DoCRDWait(context.TODO(), key)).To(MatchError(ContainSubstring("name conflict")))
+		})
+
+		It("times out if the CRD does not exist", func() {
+			c := Client{KubeClient: fake.NewFakeClient()}
+			ctx, cancel := context.WithTimeout(context.TODO(), 300*time.Millisecond)
+			defer cancel()
+			Expect(c.DoCRDWait(ctx, key)).NotTo(Succeed())
+		})
+
+		It("reports progress to the callback at the configured interval", func() {
+			c := Client{KubeClient: fake.NewFakeClient()}
+			var msgs []string
+			opts := WaitOptions{
+				Interval: 10 * time.Millisecond,
+				Progress: func(msg string) { msgs = append(msgs, msg) },
+			}
+			ctx, cancel := context.WithTimeout(context.TODO(), 200*time.Millisecond)
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				time.Sleep(30 * time.Millisecond)
+				Expect(c.KubeClient.Create(context.TODO(), newCRD(
+					map[string]interface{}{"type": "Established", "status": "True"},
+				))).To(Succeed())
+			}()
+			Expect(c.DoCRDWaitWithOptions(ctx, key, opts)).To(Succeed())
+			Expect(msgs).To(Equal([]string{
+				`Waiting for CustomResourceDefinition "memcacheds.cache.example.com" to appear`,
+				`CustomResourceDefinition "memcacheds.cache.example.com" is established`,
+			}))
+		})
+
+		It("falls back to v1beta1 CRDs", func() {
+			crd := newCRD(map[string]interface{}{"type": "Established", "status": "True"})
+			crd.SetGroupVersionKind(crdV1beta1GVK)
+			c := Client{KubeClient: noCRDV1Client{fake.NewFakeClient(crd)}}
+			Expect(c.DoCRDWait(context.TODO(), key)).To(Succeed())
+		})
+	})
+
+	Describe("DoCatalogSourceReadyWait", func() {
+		key := types.NamespacedName{Namespace: "olm", Name: "operatorhubio-catalog"}
+		newCatalogSource := func(state string) *olmapiv1alpha1.CatalogSource {
+			cs := &olmapiv1alpha1.CatalogSource{}
+			cs.SetNamespace(key.Namespace)
+			cs.SetName(key.Name)
+			cs.Status.GRPCConnectionState = &olmapiv1alpha1.GRPCConnectionState{LastObservedState: state}
+			return cs
+		}
+
+		It("returns once the CatalogSource is ready", func() {
+			c := Client{KubeClient: fake.NewFakeClient(newCatalogSource("READY"))}
+			Expect(c.DoCatalogSourceReadyWait(context.TODO(), key)).To(Succeed())
+		})
+
+		It("times out if the CatalogSource is not ready", func() {
+			c := Client{KubeClient: fake.NewFakeClient(newCatalogSource("CONNECTING"))}
+			ctx, cancel := context.WithTimeout(context.TODO(), 300*time.Millisecond)
+			defer cancel()
+			Expect(c.DoCatalogSourceReadyWait(ctx, key)).NotTo(Succeed())
+		})
+	})
+
+	Describe("DoRolloutWaitWithOptions", func() {
+		key := types.NamespacedName{Namespace: "testns", Name: "test-operator"}
+		newDeployment := func(available int32) *appsv1.Deployment {
+			replicas := int32(1)
+			dep := &appsv1.Deployment{}
+			dep.SetNamespace(key.Namespace)
+			dep.SetName(key.Name)
+			dep.Spec.Replicas = &replicas
+			dep.Status.Replicas = 1
+			dep.Status.UpdatedReplicas = 1
+			dep.Status.AvailableReplicas = available
+			return dep
+		}
+
+		It("reports progress to the callback", func() {
+			c := Client{KubeClient: fake.NewFakeClient(newDeployment(1))}
+			var msgs []string
+			opts := WaitOptions{Progress: func(msg string) { msgs = append(msgs, msg) }}
+			Expect(c.DoRolloutWaitWithOptions(context.TODO(), key, opts)).To(Succeed())
+			Expect(msgs).To(Equal([]string{`Deployment "testns/test-operator" successfully rolled out`}))
+		})
+
+		It("logs progress without a callback", func() {
+			out := &bytes.Buffer{}
+			log.SetOutput(out)
+			defer log.SetOutput(os.Stderr)
+			c := Client{KubeClient: fake.NewFakeClient(newDeployment(1))}
+			Expect(c.DoRolloutWaitWithOptions(context.TODO(), key, WaitOptions{})).To(Succeed())
+			Expect(out.String()).To(ContainSubstring(`  Deployment \"testns/test-operator\" successfully rolled out`))
+		})
+
+		It("polls at the configured interval", func() {
+			c := Client{KubeClient: fake.NewFakeClient(newDeployment(0))}
+			var msgs []string
+			opts := WaitOptions{
+				Interval: 10 * time.Millisecond,
+				Progress: func(msg string) { msgs = append(msgs, msg) },
+			}
+			ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				time.Sleep(30 * time.Millisecond)
+				dep := &appsv1.Deployment{}
+				Expect(c.KubeClient.Get(context.TODO(), key, dep)).To(Succeed())
+				dep.Status.AvailableReplicas = 1
+				Expect(c.KubeClient.Update(context.TODO(), dep)).To(Succeed())
+			}()
+			Expect(c.DoRolloutWaitWithOptions(ctx, key, opts)).To(Succeed())
+			Expect(msgs).To(HaveLen(2))
+			Expect(msgs[0]).To(ContainSubstring("0 of 1 updated replicas are available"))
+		})
+	})
+
+	Describe("DoCSVWaitWithOptions", func() {
+		key := types.NamespacedName{Namespace: "testns", Name: "test-operator.v0.0.1"}
+
+		It("reports each phase to the callback", func() {
+			csv := &olmapiv1alpha1.ClusterServiceVersion{}
+			csv.SetNamespace(key.Namespace)
+			csv.SetName(key.Name)
+			csv.Status.Phase = olmapiv1alpha1.CSVPhaseSucceeded
+			c := Client{KubeClient: fake.NewFakeClient(csv)}
+			var msgs []string
+			opts := WaitOptions{
+				Interval: 10 * time.Millisecond,
+				Progress: func(msg string) { msgs = append(msgs, msg) },
+			}
+			Expect(c.DoCSVWaitWithOptions(context.TODO(), key, opts)).To(Succeed())
+			Expect(msgs).To(Equal([]string{`Found ClusterServiceVersion "testns/test-operator.v0.0.1" phase: Succeeded`}))
+		})
+
+		It("reports a missing ClusterServiceVersion once", func() {
+			c := Client{KubeClient: fake.NewFakeClient()}
+			var msgs []string
+			opts := WaitOptions{
+				Interval: 10 * time.Millisecond,
+				Progress: func(msg string) { msgs = append(msgs, msg) },
+			}
+			ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+			defer cancel()
+			Expect(c.DoCSVWaitWithOptions(ctx, key, opts)).NotTo(Succeed())
+			Expect(msgs).To(Equal([]string{`Waiting for ClusterServiceVersion "testns/test-operator.v0.0.1" to appear`}))
+		})
+	})
 })
+
+// applyClient is a client that records the objects patched by server-side
+// apply, which the fake client does not support.
+type applyClient struct {
+	client.Client
+	applied []runtime.Object
+	opts    []client.PatchOption
+	err     error
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj runtime.Object, patch client.Patch,
+	opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	c.applied = append(c.applied, obj)
+	c.opts = opts
+	return c.err
+}
+
+// noCRDV1Client is a client of a cluster that does not serve
+// apiextensions.k8s.io/v1 CRDs.
+type noCRDV1Client struct {
+	client.Client
+}
+
+func (c noCRDV1Client) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); gvk == CRDGVK {
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+// noDeleteClient is a client whose deletes never complete, as if blocked by
+// a finalizer.
+type noDeleteClient struct {
+	client.Client
+}
+
+func (noDeleteClient) Delete(context.Context, runtime.Object, ...client.DeleteOption) error {
+	return nil
+}
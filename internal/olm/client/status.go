@@ -163,3 +163,63 @@ func (s Status) String() string {
 
 	return out.String()
 }
+
+// Resource conditions of a StatusReport.
+const (
+	ConditionInstalled = "Installed"
+	ConditionNotFound  = "NotFound"
+	ConditionError     = "Error"
+	ConditionUnknown   = "Unknown"
+)
+
+// StatusReport is the machine-readable form of a Status.
+type StatusReport struct {
+	// Version is the OLM version whose resources were checked, if known.
+	Version string `json:"version,omitempty"`
+	// Healthy is true if all resources are installed.
+	Healthy   bool                   `json:"healthy"`
+	Resources []ResourceStatusReport `json:"resources"`
+}
+
+// ResourceStatusReport is the machine-readable form of a ResourceStatus.
+type ResourceStatusReport struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Condition is one of "Installed", "NotFound", "Error", or "Unknown".
+	Condition string `json:"condition"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report returns the machine-readable form of s.
+func (s Status) Report() StatusReport {
+	report := StatusReport{Healthy: true, Resources: []ResourceStatusReport{}}
+	for _, r := range s.Resources {
+		rr := ResourceStatusReport{
+			Name:       r.NamespacedName.Name,
+			Namespace:  r.NamespacedName.Namespace,
+			APIVersion: r.GVK.GroupVersion().String(),
+			Kind:       r.GVK.Kind,
+		}
+		nkmerr := &meta.NoKindMatchError{}
+		switch {
+		case r.Error != nil:
+			rr.Error = r.Error.Error()
+			if apierrors.IsNotFound(r.Error) || errors.As(r.Error, &nkmerr) {
+				rr.Condition = ConditionNotFound
+			} else {
+				rr.Condition = ConditionError
+			}
+		case r.Resource != nil:
+			rr.Condition = ConditionInstalled
+		default:
+			rr.Condition = ConditionUnknown
+		}
+		if rr.Condition != ConditionInstalled {
+			report.Healthy = false
+		}
+		report.Resources = append(report.Resources, rr)
+	}
+	return report
+}
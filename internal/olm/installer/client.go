@@ -25,6 +25,8 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	olmapiv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
@@ -36,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 
 	olmresourceclient "github.com/operator-framework/operator-sdk/internal/olm/client"
@@ -46,15 +49,42 @@ const (
 	catalogOperatorName = "catalog-operator"
 	packageServerName   = "packageserver"
 
+	// packageServerAPIServiceName is the name of the APIService that
+	// packageserver registers to serve the packages.operators.coreos.com API.
+	packageServerAPIServiceName = "v1.packages.operators.coreos.com"
+
 	// These paths are keys to look up internal OLM bindata.
 	olmManifestBindataPath = "olm-manifests/olm.yaml"
 	crdManifestBindataPath = "olm-manifests/crds.yaml"
+
+	// These are the names of the manifest files of an OLM release.
+	olmManifestFile = "olm.yaml"
+	crdManifestFile = "crds.yaml"
+
+	// DefaultBaseDownloadURL is the URL of OLM's GitHub releases.
+	DefaultBaseDownloadURL = "https://github.com/operator-framework/operator-lifecycle-manager/releases"
 )
 
 type Client struct {
 	*olmresourceclient.Client
-	HTTPClient      http.Client
+	HTTPClient http.Client
+	// BaseDownloadURL is the URL that release manifests are downloaded from.
+	// It must have the layout of GitHub release downloads.
 	BaseDownloadURL string
+	// ManifestsDir, if set, is a directory containing the crds.yaml and
+	// olm.yaml manifests of the OLM release, which are read instead of
+	// downloaded.
+	ManifestsDir string
+	// Scheduling is set in the pod templates of OLM's deployments on install.
+	Scheduling Scheduling
+	// Reapply updates resources that already exist on install to match the
+	// release manifests, instead of failing or leaving them as they are.
+	Reapply bool
+	// Force installs the OLM release even if it does not support the
+	// cluster's Kubernetes version.
+	Force bool
+	// Discovery reports the cluster's Kubernetes version.
+	Discovery discovery.ServerVersionInterface
 }
 
 func ClientForConfig(cfg *rest.Config) (*Client, error) {
@@ -62,45 +92,107 @@ func ClientForConfig(cfg *rest.Config) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get OLM resource client: %v", err)
 	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discovery client: %v", err)
+	}
 	c := &Client{
 		Client:          cl,
 		HTTPClient:      *http.DefaultClient,
-		BaseDownloadURL: "https://github.com/operator-framework/operator-lifecycle-manager/releases",
+		BaseDownloadURL: DefaultBaseDownloadURL,
+		Discovery:       dc,
 	}
 	return c, nil
 }
 
-func (c Client) InstallVersion(ctx context.Context, namespace, version string) (*olmresourceclient.Status, error) {
+// InstallVersion installs OLM version in namespace. Install progress is recorded in a ConfigMap in namespace
+// so that, if resume is true, an interrupted install of the same version can be continued: existing resources
+// are not treated as an error, and wait steps that previously completed are skipped.
+// If c.Reapply is true, existing resources are updated to match the release manifests.
+func (c Client) InstallVersion(ctx context.Context, namespace, version string, resume bool) (*olmresourceclient.Status, error) {
 
-	resources, err := c.getResources(ctx, version)
+	resources, err := c.getResources(ctx, namespace, version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resources: %v", err)
 	}
+	if !c.Force && c.Discovery != nil {
+		serverVersion, err := c.Discovery.ServerVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Kubernetes server version: %v", err)
+		}
+		if err := checkCompatibility(resources, version, serverVersion); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.Scheduling.apply(resources); err != nil {
+		return nil, err
+	}
 	objs := toObjects(resources...)
 
-	status := c.GetObjectsStatus(ctx, objs...)
-	installed, err := status.HasInstalledResources()
-	if installed {
-		return nil, errors.New(
-			"detected existing OLM resources: OLM must be completely uninstalled before installation")
-	} else if err != nil {
-		return nil, errors.New("detected errored OLM resources, see resource statuses for more details")
+	progress := newInstallProgress(version)
+	if resume {
+		saved, err := c.getInstallProgress(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if saved == nil {
+			log.Print("No install progress found, installing all resources")
+		} else if saved.version != version {
+			return nil, fmt.Errorf("cannot resume install of version %q: in-progress install is for version %q",
+				version, saved.version)
+		} else {
+			progress = saved
+		}
+	} else if !c.Reapply {
+		status := c.GetObjectsStatus(ctx, objs...)
+		installed, err := status.HasInstalledResources()
+		if installed {
+			return nil, errors.New(
+				"detected existing OLM resources: OLM must be completely uninstalled before installation")
+		} else if err != nil {
+			return nil, errors.New("detected errored OLM resources, see resource statuses for more details")
+		}
+	}
+
+	// Create CRDs first and wait for them to be established, so that creating
+	// custom resources does not race against CRD establishment.
+	isCRD := func(r unstructured.Unstructured) bool {
+		return r.GroupVersionKind().GroupKind() == olmresourceclient.CRDGVK.GroupKind()
+	}
+	crds := filterResources(resources, isCRD)
+	log.Print("Creating CRDs")
+	if err := c.createObjects(ctx, namespace, progress, toObjects(crds...)...); err != nil {
+		return nil, fmt.Errorf("failed to create CRDs: %v", err)
+	}
+	for _, crd := range crds {
+		crdKey := types.NamespacedName{Name: crd.GetName()}
+		log.Printf("Waiting for customresourcedefinition/%s to be established", crdKey.Name)
+		if err := c.doStep(namespace, progress, "customresourcedefinition/"+crdKey.Name, func() error {
+			return c.DoCRDWait(ctx, crdKey)
+		}); err != nil {
+			return nil, fmt.Errorf("customresourcedefinition/%s failed to be established: %v", crdKey.Name, err)
+		}
 	}
 
-	log.Print("Creating CRDs and resources")
-	if err := c.DoCreate(ctx, objs...); err != nil {
-		return nil, fmt.Errorf("failed to create CRDs and resources: %v", err)
+	log.Print("Creating resources")
+	others := filterResources(resources, func(r unstructured.Unstructured) bool { return !isCRD(r) })
+	if err := c.createObjects(ctx, namespace, progress, toObjects(others...)...); err != nil {
+		return nil, fmt.Errorf("failed to create resources: %v", err)
 	}
 
 	log.Print("Waiting for deployment/olm-operator rollout to complete")
 	olmOperatorKey := types.NamespacedName{Namespace: namespace, Name: olmOperatorName}
-	if err := c.DoRolloutWait(ctx, olmOperatorKey); err != nil {
+	if err := c.doStep(namespace, progress, "deployment/"+olmOperatorName, func() error {
+		return c.DoRolloutWait(ctx, olmOperatorKey)
+	}); err != nil {
 		return nil, fmt.Errorf("deployment/%s failed to rollout: %v", olmOperatorKey.Name, err)
 	}
 
 	log.Print("Waiting for deployment/catalog-operator rollout to complete")
 	catalogOperatorKey := types.NamespacedName{Namespace: namespace, Name: catalogOperatorName}
-	if err := c.DoRolloutWait(ctx, catalogOperatorKey); err != nil {
+	if err := c.doStep(namespace, progress, "deployment/"+catalogOperatorName, func() error {
+		return c.DoRolloutWait(ctx, catalogOperatorKey)
+	}); err != nil {
 		return nil, fmt.Errorf("deployment/%s failed to rollout: %v", catalogOperatorKey.Name, err)
 	}
 
@@ -115,49 +207,136 @@ func (c Client) InstallVersion(ctx context.Context, namespace, version string) (
 	for _, sub := range subscriptions {
 		subscriptionKey := types.NamespacedName{Namespace: sub.GetNamespace(), Name: sub.GetName()}
 		log.Printf("Waiting for subscription/%s to install CSV", subscriptionKey.Name)
-		csvKey, err := c.getSubscriptionCSV(ctx, subscriptionKey)
-		if err != nil {
-			return nil, fmt.Errorf("subscription/%s failed to install CSV: %v", subscriptionKey.Name, err)
-		}
-		log.Printf("Waiting for clusterserviceversion/%s to reach 'Succeeded' phase", csvKey.Name)
-		if err := c.DoCSVWait(ctx, csvKey); err != nil {
-			return nil, fmt.Errorf("clusterserviceversion/%s failed to reach 'Succeeded' phase",
-				csvKey.Name)
+		var csvKey types.NamespacedName
+		if err := c.doStep(namespace, progress, "subscription/"+subscriptionKey.Name, func() (err error) {
+			csvKey, err = c.getSubscriptionCSV(ctx, subscriptionKey)
+			if err != nil {
+				return fmt.Errorf("subscription/%s failed to install CSV: %v", subscriptionKey.Name, err)
+			}
+			log.Printf("Waiting for clusterserviceversion/%s to reach 'Succeeded' phase", csvKey.Name)
+			if err := c.DoCSVWait(ctx, csvKey); err != nil {
+				return fmt.Errorf("clusterserviceversion/%s failed to reach 'Succeeded' phase",
+					csvKey.Name)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
 		}
 	}
 
 	packageServerKey := types.NamespacedName{Namespace: namespace, Name: packageServerName}
 	log.Printf("Waiting for deployment/%s rollout to complete", packageServerKey.Name)
-	if err := c.DoRolloutWait(ctx, packageServerKey); err != nil {
+	if err := c.doStep(namespace, progress, "deployment/"+packageServerName, func() error {
+		return c.DoRolloutWait(ctx, packageServerKey)
+	}); err != nil {
 		return nil, fmt.Errorf("deployment/%s failed to rollout: %v", packageServerKey.Name, err)
 	}
 
-	status = c.GetObjectsStatus(ctx, objs...)
+	log.Printf("Waiting for apiservice/%s to become available", packageServerAPIServiceName)
+	if err := c.doStep(namespace, progress, "apiservice/"+packageServerAPIServiceName, func() error {
+		return c.DoAPIServiceWait(ctx, packageServerAPIServiceName)
+	}); err != nil {
+		return nil, fmt.Errorf("apiservice/%s failed to become available: %v", packageServerAPIServiceName, err)
+	}
+
+	catalogSources := filterResources(resources, func(r unstructured.Unstructured) bool {
+		return r.GroupVersionKind() == schema.GroupVersionKind{
+			Group:   olmapiv1alpha1.GroupName,
+			Version: olmapiv1alpha1.GroupVersion,
+			Kind:    olmapiv1alpha1.CatalogSourceKind,
+		}
+	})
+
+	for _, cs := range catalogSources {
+		catalogSourceKey := types.NamespacedName{Namespace: cs.GetNamespace(), Name: cs.GetName()}
+		log.Printf("Waiting for catalogsource/%s to become ready", catalogSourceKey.Name)
+		if err := c.doStep(namespace, progress, "catalogsource/"+catalogSourceKey.Name, func() error {
+			return c.DoCatalogSourceReadyWait(ctx, catalogSourceKey)
+		}); err != nil {
+			return nil, fmt.Errorf("catalogsource/%s failed to become ready: %v", catalogSourceKey.Name, err)
+		}
+	}
+
+	if err := c.deleteInstallProgress(ctx, namespace); err != nil {
+		log.Warnf("Failed to delete install progress: %v", err)
+	}
+
+	status := c.GetObjectsStatus(ctx, objs...)
 	return &status, nil
 }
 
-func (c Client) UninstallVersion(ctx context.Context, namespace, version string) error {
-	resources, err := c.getResources(ctx, version)
-	if err != nil {
-		return fmt.Errorf("failed to get resources: %v", err)
+// createObjects creates each of objs not recorded as applied in progress,
+// then records it as applied. Progress is saved once all objects are created
+// or creation fails.
+func (c Client) createObjects(ctx context.Context, namespace string, progress *installProgress, objs ...runtime.Object) error {
+	defer c.saveInstallProgress(namespace, progress)
+	for _, obj := range objs {
+		key, err := objectKey(obj)
+		if err != nil {
+			return err
+		}
+		if progress.applied.Has(key) {
+			continue
+		}
+		create := c.DoCreate
+		if c.Reapply {
+			create = c.DoCreateOrUpdate
+		}
+		if err := create(ctx, obj); err != nil {
+			return err
+		}
+		progress.applied.Insert(key)
 	}
-	objs := toObjects(resources...)
+	return nil
+}
 
-	status := c.GetObjectsStatus(ctx, objs...)
-	installed, err := status.HasInstalledResources()
-	if !installed && err == nil {
-		return olmresourceclient.ErrOLMNotInstalled
+func (c Client) UninstallVersion(ctx context.Context, namespace, version string,
+	opts olmresourceclient.DeleteOptions) error {
+	objs, _, err := c.getUninstallObjects(ctx, namespace, version)
+	if err != nil {
+		return err
 	}
 
 	log.Infof("Uninstalling resources for version %q", version)
-	if err := c.DoDelete(ctx, objs...); err != nil {
+	if err := c.DoDeleteWithOptions(ctx, opts, objs...); err != nil {
 		return err
 	}
 	return nil
 }
 
+// PreviewUninstallVersion returns the resources that UninstallVersion would
+// delete, in the order they would be deleted, without deleting them.
+func (c Client) PreviewUninstallVersion(ctx context.Context, namespace, version string) ([]olmresourceclient.ResourceStatus, error) {
+	_, existing, err := c.getUninstallObjects(ctx, namespace, version)
+	return existing, err
+}
+
+// getUninstallObjects returns the objects of OLM version in namespace to
+// delete, and the statuses of those that exist in the order of objs.
+func (c Client) getUninstallObjects(ctx context.Context, namespace, version string) (objs []runtime.Object,
+	existing []olmresourceclient.ResourceStatus, err error) {
+	resources, err := c.getResources(ctx, namespace, version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get resources: %v", err)
+	}
+	objs = toObjects(resources...)
+
+	status := c.GetObjectsStatus(ctx, objs...)
+	// HasInstalledResources reorders status, so collect existing resources first.
+	for _, r := range status.Resources {
+		if r.Resource != nil {
+			existing = append(existing, r)
+		}
+	}
+	installed, err := status.HasInstalledResources()
+	if !installed && err == nil {
+		return nil, nil, olmresourceclient.ErrOLMNotInstalled
+	}
+	return objs, existing, nil
+}
+
 func (c Client) GetStatus(ctx context.Context, namespace, version string) (*olmresourceclient.Status, error) {
-	resources, err := c.getResources(ctx, version)
+	resources, err := c.getResources(ctx, namespace, version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resources: %v", err)
 	}
@@ -171,15 +350,28 @@ func (c Client) GetStatus(ctx context.Context, namespace, version string) (*olmr
 	return &status, nil
 }
 
-func (c Client) getResources(ctx context.Context, version string) ([]unstructured.Unstructured, error) {
+// getResources returns the resources of OLM version, installed in namespace.
+func (c Client) getResources(ctx context.Context, namespace, version string) ([]unstructured.Unstructured, error) {
 	log.Infof("Fetching CRDs for version %q", version)
 
 	var crdResources, olmResources []unstructured.Unstructured
 	var err error
 
-	// If the manifests for the requested version are saved as bindata in SDK, use
-	// them instead of fetching them from
-	if olmmanifests.HasVersion(version) {
+	// Manifests in a local directory take precedence. Otherwise, if the manifests
+	// for the requested version are saved as bindata in SDK, use them instead of
+	// fetching them from the default download URL.
+	if c.ManifestsDir != "" {
+		log.Infof("Reading resource manifests from directory %q", c.ManifestsDir)
+		crdResources, err = readManifestFile(filepath.Join(c.ManifestsDir, crdManifestFile))
+		if err != nil {
+			return nil, err
+		}
+
+		olmResources, err = readManifestFile(filepath.Join(c.ManifestsDir, olmManifestFile))
+		if err != nil {
+			return nil, err
+		}
+	} else if olmmanifests.HasVersion(version) && c.BaseDownloadURL == DefaultBaseDownloadURL {
 		log.Infof("Using locally stored resource manifests")
 		crdResources, err = getPackagedManifests(crdManifestBindataPath)
 		if err != nil {
@@ -203,6 +395,10 @@ func (c Client) getResources(ctx context.Context, version string) ([]unstructure
 		}
 	}
 
+	if err := setNamespace(olmResources, namespace); err != nil {
+		return nil, err
+	}
+
 	resources := append(crdResources, olmResources...)
 	return resources, nil
 }
@@ -239,12 +435,26 @@ func getPackagedManifests(manifestPath string) ([]unstructured.Unstructured, err
 	return resources, nil
 }
 
+// readManifestFile decodes the resources in the manifest file at path.
+func readManifestFile(path string) ([]unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest file: %v", err)
+	}
+	defer f.Close()
+	resources, err := decodeResources(f)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding manifest file %s: %v", path, err)
+	}
+	return resources, nil
+}
+
 func (c Client) crdsURL(version string) string {
-	return fmt.Sprintf("%s/crds.yaml", c.getBaseDownloadURL(version))
+	return fmt.Sprintf("%s/%s", c.getBaseDownloadURL(version), crdManifestFile)
 }
 
 func (c Client) olmURL(version string) string {
-	return fmt.Sprintf("%s/olm.yaml", c.getBaseDownloadURL(version))
+	return fmt.Sprintf("%s/%s", c.getBaseDownloadURL(version), olmManifestFile)
 }
 
 func (c Client) getBaseDownloadURL(version string) string {
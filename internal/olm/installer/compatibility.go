@@ -0,0 +1,86 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installer
+
+import (
+	"fmt"
+	"strings"
+
+	olmapiv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// kubeMinorRange is the range of Kubernetes 1.x minor versions, inclusive,
+// that an OLM release supports.
+type kubeMinorRange struct {
+	min, max uint
+}
+
+// compatibleKubeVersions maps OLM minor versions to the Kubernetes versions
+// they support. OLM releases not in the matrix are not checked.
+var compatibleKubeVersions = map[string]kubeMinorRange{
+	"0.13": {min: 11, max: 17},
+	"0.14": {min: 11, max: 18},
+	"0.15": {min: 12, max: 18},
+	// OLM 0.16 ships apiextensions.k8s.io/v1 CRDs, which require 1.16.
+	"0.16": {min: 16, max: 19},
+	"0.17": {min: 16, max: 20},
+}
+
+// checkCompatibility returns an error if the OLM release in resources, or
+// olmVersion if its version cannot be found in them, does not support the
+// Kubernetes server version.
+func checkCompatibility(resources []unstructured.Unstructured, olmVersion string, server *version.Info) error {
+	if v := olmReleaseVersion(resources); v != "" {
+		olmVersion = v
+	}
+	olmVer, err := utilversion.ParseGeneric(strings.TrimPrefix(olmVersion, "v"))
+	if err != nil {
+		log.Printf("Skipping compatibility check: unknown OLM version %q", olmVersion)
+		return nil
+	}
+	kubeVer, err := utilversion.ParseGeneric(server.GitVersion)
+	if err != nil {
+		return fmt.Errorf("failed to parse Kubernetes server version %q: %v", server.GitVersion, err)
+	}
+
+	supported, ok := compatibleKubeVersions[fmt.Sprintf("%d.%d", olmVer.Major(), olmVer.Minor())]
+	if !ok {
+		log.Printf("Skipping compatibility check: no known Kubernetes versions for OLM version %q", olmVersion)
+		return nil
+	}
+	if kubeVer.Major() != 1 || kubeVer.Minor() < supported.min || kubeVer.Minor() > supported.max {
+		return fmt.Errorf("OLM version %q supports Kubernetes versions 1.%d through 1.%d, but the cluster runs "+
+			"Kubernetes %s; install a compatible OLM version, or re-run with --force to install anyway",
+			olmVersion, supported.min, supported.max, server.GitVersion)
+	}
+	return nil
+}
+
+// olmReleaseVersion returns the OLM version that the packageserver CSV in
+// resources is labeled with, or "" if there is none.
+func olmReleaseVersion(resources []unstructured.Unstructured) string {
+	for _, r := range resources {
+		gvk := r.GroupVersionKind()
+		if gvk.Group == olmapiv1alpha1.GroupName && gvk.Kind == olmapiv1alpha1.ClusterServiceVersionKind &&
+			r.GetName() == packageServerName {
+			return r.GetLabels()["olm.version"]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installer
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	olmapiv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+var _ = Describe("checkCompatibility", func() {
+	packageServer := func(olmVersion string) []unstructured.Unstructured {
+		csv := unstructured.Unstructured{}
+		csv.SetGroupVersionKind(olmapiv1alpha1.SchemeGroupVersion.WithKind(olmapiv1alpha1.ClusterServiceVersionKind))
+		csv.SetNamespace(DefaultOLMNamespace)
+		csv.SetName(packageServerName)
+		csv.SetLabels(map[string]string{"olm.version": olmVersion})
+		return []unstructured.Unstructured{csv}
+	}
+
+	DescribeTable("compatible versions",
+		func(resources []unstructured.Unstructured, olmVersion, kubeVersion string) {
+			Expect(checkCompatibility(resources, olmVersion, &version.Info{GitVersion: kubeVersion})).To(Succeed())
+		},
+		Entry("oldest supported", nil, "0.16.1", "v1.16.0"),
+		Entry("newest supported", nil, "v0.16.1", "v1.19.3"),
+		Entry("provider version suffix", nil, "0.17.0", "v1.18.9-eks-d1db3c"),
+		Entry("version from the release", packageServer("0.17.0"), "latest", "v1.20.0"),
+		Entry("unknown OLM version", nil, "latest", "v1.10.0"),
+		Entry("OLM version not in the matrix", nil, "0.10.0", "v1.10.0"),
+	)
+
+	DescribeTable("incompatible versions",
+		func(resources []unstructured.Unstructured, olmVersion, kubeVersion, expectedErr string) {
+			err := checkCompatibility(resources, olmVersion, &version.Info{GitVersion: kubeVersion})
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+		},
+		Entry("older than supported", nil, "0.16.1", "v1.15.12",
+			`OLM version "0.16.1" supports Kubernetes versions 1.16 through 1.19, but the cluster runs `+
+				`Kubernetes v1.15.12`),
+		Entry("newer than supported", nil, "0.15.1", "v1.20.0",
+			`OLM version "0.15.1" supports Kubernetes versions 1.12 through 1.18, but the cluster runs `+
+				`Kubernetes v1.20.0; install a compatible OLM version, or re-run with --force to install anyway`),
+		Entry("version from the release", packageServer("0.16.1"), "0.13.0", "v1.15.12",
+			`OLM version "0.16.1" supports Kubernetes versions 1.16 through 1.19`),
+		Entry("unsupported major version", nil, "0.17.0", "v2.0.0",
+			`OLM version "0.17.0" supports Kubernetes versions 1.16 through 1.20`),
+		Entry("unparsable Kubernetes version", nil, "0.17.0", "unknown",
+			`failed to parse Kubernetes server version "unknown"`),
+	)
+})
@@ -16,13 +16,30 @@ package installer
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"sync"
+	"text/tabwriter"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	olmresourceclient "github.com/operator-framework/operator-sdk/internal/olm/client"
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
+)
+
+// Formats of Manager.Status's output.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputYAML = "yaml"
 )
 
 const (
@@ -33,29 +50,66 @@ const (
 )
 
 type Manager struct {
-	Client       *Client
-	Version      string
-	Timeout      time.Duration
-	OLMNamespace string
-	once         sync.Once
+	Client *Client
+	// KubeconfigPath and KubeContext select the kubeconfig file and context
+	// that Client is created from, if nil.
+	KubeconfigPath string
+	KubeContext    string
+	Version        string
+	Timeout        time.Duration
+	OLMNamespace   string
+	// Resume continues an interrupted install of Version instead of failing on existing resources.
+	Resume bool
+	// Reapply updates existing OLM resources to match Version's manifests on install.
+	Reapply bool
+	// Force installs Version even if it does not support the cluster's
+	// Kubernetes version.
+	Force bool
+	// Foreground uninstalls each resource with foreground deletion, so that
+	// it is not removed until its dependents are.
+	Foreground bool
+	// DryRun prints the resources that Uninstall would delete instead of
+	// deleting them.
+	DryRun bool
+	// ManifestsDir, if set, is a directory containing Version's crds.yaml and
+	// olm.yaml manifests. Otherwise manifests are downloaded from BaseURL, or
+	// OLM's GitHub releases if BaseURL is empty.
+	ManifestsDir string
+	BaseURL      string
+	// NodeSelector, Tolerations, and PriorityClassName are set in the pod
+	// templates of OLM's deployments on install. Tolerations have the form
+	// "key[=value][:effect]".
+	NodeSelector      map[string]string
+	Tolerations       []string
+	PriorityClassName string
+	// Output is the format that Status prints in: "text" (the default),
+	// "json", or "yaml".
+	Output string
+	once   sync.Once
 }
 
 func (m *Manager) initialize() (err error) {
 	m.once.Do(func() {
 		if m.Client == nil {
-			cfg, cerr := config.GetConfig()
+			cfg, cerr := k8sutil.NewClientConfig(m.KubeconfigPath, m.KubeContext, nil).ClientConfig()
 			if cerr != nil {
-				err = fmt.Errorf("failed to get Kubernetes config: %v", err)
+				err = fmt.Errorf("failed to get Kubernetes config: %v", cerr)
 				return
 			}
 
 			client, cerr := ClientForConfig(cfg)
 			if cerr != nil {
-				err = fmt.Errorf("failed to create manager client: %v", err)
+				err = fmt.Errorf("failed to create manager client: %v", cerr)
 				return
 			}
 			m.Client = client
 		}
+		if m.BaseURL != "" {
+			m.Client.BaseDownloadURL = strings.TrimSuffix(m.BaseURL, "/")
+		}
+		if m.ManifestsDir != "" {
+			m.Client.ManifestsDir = m.ManifestsDir
+		}
 		if m.Timeout <= 0 {
 			m.Timeout = DefaultTimeout
 		}
@@ -74,8 +128,25 @@ func (m *Manager) Install() error {
 	ctx, cancel := context.WithTimeout(context.Background(), m.Timeout)
 	defer cancel()
 
-	status, err := m.Client.InstallVersion(ctx, m.OLMNamespace, m.Version)
+	m.Client.Reapply = m.Reapply
+	m.Client.Force = m.Force
+	m.Client.Scheduling = Scheduling{
+		NodeSelector:      m.NodeSelector,
+		PriorityClassName: m.PriorityClassName,
+	}
+	for _, t := range m.Tolerations {
+		toleration, err := parseToleration(t)
+		if err != nil {
+			return err
+		}
+		m.Client.Scheduling.Tolerations = append(m.Client.Scheduling.Tolerations, toleration)
+	}
+
+	status, err := m.Client.InstallVersion(ctx, m.OLMNamespace, m.Version, m.Resume)
 	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%v; re-run with --resume to continue the install", err)
+		}
 		return err
 	}
 
@@ -105,7 +176,20 @@ func (m *Manager) Uninstall() error {
 		m.Version = version
 	}
 
-	if err := m.Client.UninstallVersion(ctx, m.OLMNamespace, m.Version); err != nil {
+	if m.DryRun {
+		resources, err := m.Client.PreviewUninstallVersion(ctx, m.OLMNamespace, m.Version)
+		if err != nil {
+			return err
+		}
+		log.Infof("Dry run: uninstalling OLM version %q would delete %d resources", m.Version, len(resources))
+		return printUninstallPreview(os.Stdout, resources)
+	}
+
+	var deleteOpts olmresourceclient.DeleteOptions
+	if m.Foreground {
+		deleteOpts.PropagationPolicy = metav1.DeletePropagationForeground
+	}
+	if err := m.Client.UninstallVersion(ctx, m.OLMNamespace, m.Version, deleteOpts); err != nil {
 		return err
 	}
 
@@ -113,7 +197,23 @@ func (m *Manager) Uninstall() error {
 	return nil
 }
 
+// printUninstallPreview prints the resources that an uninstall would delete to w.
+func printUninstallPreview(w io.Writer, resources []olmresourceclient.ResourceStatus) error {
+	tw := tabwriter.NewWriter(w, 8, 4, 4, ' ', 0)
+	fmt.Fprintf(tw, "\nNAME\tNAMESPACE\tKIND\n")
+	for _, r := range resources {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.NamespacedName.Name, r.NamespacedName.Namespace, r.GVK.Kind)
+	}
+	return tw.Flush()
+}
+
 func (m *Manager) Status() error {
+	switch m.Output {
+	case "", OutputText, OutputJSON, OutputYAML:
+	default:
+		return fmt.Errorf("invalid output format %q, must be one of: %s, %s, %s", m.Output,
+			OutputText, OutputJSON, OutputYAML)
+	}
 	if err := m.initialize(); err != nil {
 		return err
 	}
@@ -139,11 +239,44 @@ func (m *Manager) Status() error {
 	}
 
 	log.Infof("Successfully got OLM status for version %q", m.Version)
-	fmt.Print("\n")
-	fmt.Println(status)
-	return nil
+	return m.printStatus(os.Stdout, status)
+}
+
+// printStatus prints status to w in m's output format.
+func (m *Manager) printStatus(w io.Writer, status *olmresourceclient.Status) error {
+	var (
+		b   []byte
+		err error
+	)
+	switch m.Output {
+	case OutputJSON:
+		report := status.Report()
+		report.Version = m.Version
+		b, err = json.MarshalIndent(report, "", "  ")
+		b = append(b, '\n')
+	case OutputYAML:
+		report := status.Report()
+		report.Version = m.Version
+		b, err = yaml.Marshal(report)
+	default:
+		_, err = fmt.Fprintf(w, "\n%s\n", status)
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("error encoding OLM status: %v", err)
+	}
+	_, err = w.Write(b)
+	return err
 }
 
 func (m *Manager) AddToFlagSet(fs *pflag.FlagSet) {
+	k8sutil.BindKubeconfigFlags(fs, &m.KubeconfigPath, &m.KubeContext)
 	fs.DurationVar(&m.Timeout, "timeout", DefaultTimeout, "time to wait for the command to complete before failing")
+	fs.StringVar(&m.OLMNamespace, "olm-namespace", DefaultOLMNamespace, "namespace where OLM is installed; "+
+		"OLM's manifests are rewritten to use this namespace instead of the default")
+	fs.StringVar(&m.ManifestsDir, "manifests-dir", "", "directory containing the crds.yaml and olm.yaml "+
+		"manifests of the OLM version, used instead of downloading them, ex. in air-gapped clusters")
+	fs.StringVar(&m.BaseURL, "base-url", DefaultBaseDownloadURL, "URL that the crds.yaml and olm.yaml manifests "+
+		"of the OLM version are downloaded from, such as a mirror of OLM's releases; manifests are downloaded "+
+		"from <base-url>/download/<version>/, or <base-url>/latest/download/ for the latest version")
 }
@@ -0,0 +1,147 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installer
+
+import (
+	"fmt"
+	"strings"
+
+	olmapiv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// setNamespace moves the resources of an OLM release from the namespace that
+// release manifests install OLM into, DefaultOLMNamespace, to namespace.
+// Besides resource namespaces, references to the namespace in RBAC subjects,
+// OperatorGroup targets, Subscription sources, and the namespace flags of
+// OLM's containers are rewritten.
+func setNamespace(resources []unstructured.Unstructured, namespace string) error {
+	const from = DefaultOLMNamespace
+	if namespace == from {
+		return nil
+	}
+	for _, r := range resources {
+		if r.GetNamespace() == from {
+			r.SetNamespace(namespace)
+		}
+
+		var err error
+		switch gvk := r.GroupVersionKind(); {
+		case gvk.Group == "" && gvk.Kind == "Namespace":
+			if r.GetName() == from {
+				r.SetName(namespace)
+			}
+		case gvk.Group == "rbac.authorization.k8s.io" && (gvk.Kind == "ClusterRoleBinding" || gvk.Kind == "RoleBinding"):
+			err = setSubjectsNamespace(r.Object, from, namespace)
+		case gvk.Group == olmapiv1alpha1.GroupName && gvk.Kind == "OperatorGroup":
+			err = replaceInStringSlice(r.Object, from, namespace, "spec", "targetNamespaces")
+		case gvk.Group == olmapiv1alpha1.GroupName && gvk.Kind == olmapiv1alpha1.SubscriptionKind:
+			if source, _, _ := unstructured.NestedString(r.Object, "spec", "sourceNamespace"); source == from {
+				err = unstructured.SetNestedField(r.Object, namespace, "spec", "sourceNamespace")
+			}
+		case gvk.Group == "apps" && gvk.Kind == "Deployment":
+			err = setContainersNamespace(r.Object, from, namespace, "spec", "template", "spec", "containers")
+		case gvk.Group == olmapiv1alpha1.GroupName && gvk.Kind == olmapiv1alpha1.ClusterServiceVersionKind:
+			err = setCSVDeploymentsNamespace(r.Object, from, namespace)
+		}
+		if err != nil {
+			return fmt.Errorf("error setting namespace of %s %q: %v", strings.ToLower(r.GetKind()), r.GetName(), err)
+		}
+	}
+	return nil
+}
+
+func setSubjectsNamespace(obj map[string]interface{}, from, to string) error {
+	subjects, found, err := unstructured.NestedSlice(obj, "subjects")
+	if !found || err != nil {
+		return err
+	}
+	for _, s := range subjects {
+		if subject, ok := s.(map[string]interface{}); ok && subject["namespace"] == from {
+			subject["namespace"] = to
+		}
+	}
+	return unstructured.SetNestedSlice(obj, subjects, "subjects")
+}
+
+func replaceInStringSlice(obj map[string]interface{}, from, to string, fields ...string) error {
+	values, found, err := unstructured.NestedStringSlice(obj, fields...)
+	if !found || err != nil {
+		return err
+	}
+	for i, v := range values {
+		if v == from {
+			values[i] = to
+		}
+	}
+	return unstructured.SetNestedStringSlice(obj, values, fields...)
+}
+
+func setCSVDeploymentsNamespace(obj map[string]interface{}, from, to string) error {
+	deployments, found, err := unstructured.NestedSlice(obj, "spec", "install", "spec", "deployments")
+	if !found || err != nil {
+		return err
+	}
+	for _, d := range deployments {
+		if dep, ok := d.(map[string]interface{}); ok {
+			if err := setContainersNamespace(dep, from, to, "spec", "template", "spec", "containers"); err != nil {
+				return err
+			}
+		}
+	}
+	return unstructured.SetNestedSlice(obj, deployments, "spec", "install", "spec", "deployments")
+}
+
+// setContainersNamespace rewrites the values of namespace flags, ex.
+// "-namespace olm" or "--global-namespace=olm", in the args of the
+// containers at fields in obj.
+func setContainersNamespace(obj map[string]interface{}, from, to string, fields ...string) error {
+	containers, found, err := unstructured.NestedSlice(obj, fields...)
+	if !found || err != nil {
+		return err
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		args, _, err := unstructured.NestedStringSlice(container, "args")
+		if err != nil {
+			return err
+		}
+		for i, arg := range args {
+			if !strings.HasPrefix(arg, "-") {
+				continue
+			}
+			if flag := strings.SplitN(arg, "=", 2); len(flag) == 2 {
+				if isNamespaceFlag(flag[0]) && flag[1] == from {
+					args[i] = flag[0] + "=" + to
+				}
+			} else if isNamespaceFlag(arg) && i+1 < len(args) && args[i+1] == from {
+				args[i+1] = to
+			}
+		}
+		if len(args) != 0 {
+			if err := unstructured.SetNestedStringSlice(container, args, "args"); err != nil {
+				return err
+			}
+		}
+	}
+	return unstructured.SetNestedSlice(obj, containers, fields...)
+}
+
+func isNamespaceFlag(flag string) bool {
+	return strings.HasSuffix(strings.TrimLeft(flag, "-"), "namespace")
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installer
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("setNamespace", func() {
+	newResource := func(apiVersion, kind, namespace, name string,
+		fields map[string]interface{}) unstructured.Unstructured {
+		u := unstructured.Unstructured{Object: fields}
+		if u.Object == nil {
+			u.Object = map[string]interface{}{}
+		}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		return u
+	}
+	containers := func(args ...interface{}) []interface{} {
+		return []interface{}{map[string]interface{}{"name": "olm-operator", "args": args}}
+	}
+
+	DescribeTable("moving resources out of the default namespace",
+		func(r unstructured.Unstructured, fields []string, expected interface{}) {
+			Expect(setNamespace([]unstructured.Unstructured{r}, "operator-lifecycle")).To(Succeed())
+			actual, found, err := unstructured.NestedFieldNoCopy(r.Object, fields...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(actual).To(Equal(expected))
+		},
+		Entry("namespaced resource",
+			newResource("v1", "ServiceAccount", DefaultOLMNamespace, "olm-operator-serviceaccount", nil),
+			[]string{"metadata", "namespace"}, "operator-lifecycle"),
+		Entry("namespace",
+			newResource("v1", "Namespace", "", DefaultOLMNamespace, nil),
+			[]string{"metadata", "name"}, "operator-lifecycle"),
+		Entry("other namespace",
+			newResource("v1", "Namespace", "", "operators", nil),
+			[]string{"metadata", "name"}, "operators"),
+		Entry("cluster role binding subjects",
+			newResource("rbac.authorization.k8s.io/v1", "ClusterRoleBinding", "", "olm-operator-binding-olm",
+				map[string]interface{}{"subjects": []interface{}{
+					map[string]interface{}{"kind": "ServiceAccount", "name": "olm", "namespace": DefaultOLMNamespace},
+					map[string]interface{}{"kind": "ServiceAccount", "name": "other", "namespace": "operators"},
+				}}),
+			[]string{"subjects"}, []interface{}{
+				map[string]interface{}{"kind": "ServiceAccount", "name": "olm", "namespace": "operator-lifecycle"},
+				map[string]interface{}{"kind": "ServiceAccount", "name": "other", "namespace": "operators"},
+			}),
+		Entry("operator group target namespaces",
+			newResource("operators.coreos.com/v1", "OperatorGroup", DefaultOLMNamespace, "olm-operators",
+				map[string]interface{}{"spec": map[string]interface{}{
+					"targetNamespaces": []interface{}{DefaultOLMNamespace},
+				}}),
+			[]string{"spec", "targetNamespaces"}, []interface{}{"operator-lifecycle"}),
+		Entry("subscription source namespace",
+			newResource("operators.coreos.com/v1alpha1", "Subscription", "operators", "packageserver",
+				map[string]interface{}{"spec": map[string]interface{}{"sourceNamespace": DefaultOLMNamespace}}),
+			[]string{"spec", "sourceNamespace"}, "operator-lifecycle"),
+		Entry("deployment namespace flag",
+			newResource("apps/v1", "Deployment", DefaultOLMNamespace, olmOperatorName,
+				map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": containers("-namespace", DefaultOLMNamespace, "--writeStatusName", ""),
+					},
+				}}}),
+			[]string{"spec", "template", "spec", "containers"},
+			containers("-namespace", "operator-lifecycle", "--writeStatusName", "")),
+		Entry("deployment namespace flag with value",
+			newResource("apps/v1", "Deployment", DefaultOLMNamespace, catalogOperatorName,
+				map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": containers("--namespace="+DefaultOLMNamespace, "-configmapServerImage=olm"),
+					},
+				}}}),
+			[]string{"spec", "template", "spec", "containers"},
+			containers("--namespace=operator-lifecycle", "-configmapServerImage=olm")),
+		Entry("cluster service version deployments",
+			newResource("operators.coreos.com/v1alpha1", "ClusterServiceVersion", DefaultOLMNamespace,
+				packageServerName, map[string]interface{}{"spec": map[string]interface{}{
+					"install": map[string]interface{}{"spec": map[string]interface{}{"deployments": []interface{}{
+						map[string]interface{}{"name": packageServerName, "spec": map[string]interface{}{
+							"template": map[string]interface{}{"spec": map[string]interface{}{
+								"containers": containers("--global-namespace", DefaultOLMNamespace),
+							}},
+						}},
+					}}},
+				}}),
+			[]string{"spec", "install", "spec", "deployments"}, []interface{}{
+				map[string]interface{}{"name": packageServerName, "spec": map[string]interface{}{
+					"template": map[string]interface{}{"spec": map[string]interface{}{
+						"containers": containers("--global-namespace", "operator-lifecycle"),
+					}},
+				}},
+			}),
+	)
+
+	It("should leave resources unchanged in the default namespace", func() {
+		r := newResource("v1", "ServiceAccount", DefaultOLMNamespace, "olm-operator-serviceaccount", nil)
+		expected := r.DeepCopy()
+		Expect(setNamespace([]unstructured.Unstructured{r}, DefaultOLMNamespace)).To(Succeed())
+		Expect(&r).To(Equal(expected))
+	})
+
+	It("should fail on malformed subjects", func() {
+		r := newResource("rbac.authorization.k8s.io/v1", "RoleBinding", DefaultOLMNamespace, "packageserver",
+			map[string]interface{}{"subjects": "olm"})
+		err := setNamespace([]unstructured.Unstructured{r}, "operator-lifecycle")
+		Expect(err).To(MatchError(ContainSubstring(`error setting namespace of rolebinding "packageserver"`)))
+	})
+})
@@ -0,0 +1,158 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// progressConfigMapName is the name of the ConfigMap in the OLM namespace
+	// that records the progress of an install, so it can be resumed.
+	progressConfigMapName = "operator-sdk-olm-install-progress"
+
+	progressVersionKey   = "version"
+	progressAppliedKey   = "applied"
+	progressCompletedKey = "completed"
+
+	// progressSaveTimeout bounds saving progress, which must succeed after
+	// the install's context has timed out.
+	progressSaveTimeout = time.Second * 10
+)
+
+// installProgress is the set of resources created and wait steps completed
+// by an install of version.
+type installProgress struct {
+	version   string
+	applied   sets.String
+	completed sets.String
+}
+
+func newInstallProgress(version string) *installProgress {
+	return &installProgress{
+		version:   version,
+		applied:   sets.NewString(),
+		completed: sets.NewString(),
+	}
+}
+
+// objectKey returns a key identifying obj in installProgress.applied.
+func objectKey(obj runtime.Object) (string, error) {
+	a, err := meta.Accessor(obj)
+	if err != nil {
+		return "", err
+	}
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	return fmt.Sprintf("%s/%s", kind, getName(a.GetNamespace(), a.GetName())), nil
+}
+
+func getName(namespace, name string) string {
+	if namespace != "" {
+		name = fmt.Sprintf("%s/%s", namespace, name)
+	}
+	return name
+}
+
+// getInstallProgress returns the install progress recorded in namespace,
+// or nil if no progress is recorded.
+func (c Client) getInstallProgress(ctx context.Context, namespace string) (*installProgress, error) {
+	cm := corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: namespace, Name: progressConfigMapName}
+	if err := c.KubeClient.Get(ctx, key, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting install progress: %v", err)
+	}
+	p := newInstallProgress(cm.Data[progressVersionKey])
+	p.applied.Insert(splitLines(cm.Data[progressAppliedKey])...)
+	p.completed.Insert(splitLines(cm.Data[progressCompletedKey])...)
+	return p, nil
+}
+
+// saveInstallProgress records p in namespace. Failures are logged rather than
+// returned, since the namespace may not have been created yet.
+func (c Client) saveInstallProgress(namespace string, p *installProgress) {
+	ctx, cancel := context.WithTimeout(context.Background(), progressSaveTimeout)
+	defer cancel()
+
+	cm := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: progressConfigMapName},
+	}
+	key := types.NamespacedName{Namespace: namespace, Name: progressConfigMapName}
+	err := c.KubeClient.Get(ctx, key, &cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		log.Warnf("Failed to save install progress: %v", err)
+		return
+	}
+	cm.Data = map[string]string{
+		progressVersionKey:   p.version,
+		progressAppliedKey:   strings.Join(p.applied.List(), "\n"),
+		progressCompletedKey: strings.Join(p.completed.List(), "\n"),
+	}
+	if apierrors.IsNotFound(err) {
+		err = c.KubeClient.Create(ctx, &cm)
+	} else {
+		err = c.KubeClient.Update(ctx, &cm)
+	}
+	if err != nil {
+		log.Warnf("Failed to save install progress: %v", err)
+	}
+}
+
+// deleteInstallProgress deletes the install progress recorded in namespace, if any.
+func (c Client) deleteInstallProgress(ctx context.Context, namespace string) error {
+	cm := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: progressConfigMapName},
+	}
+	if err := c.KubeClient.Delete(ctx, &cm); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// doStep runs fn unless step is recorded as completed in p, then records step
+// as completed. Progress is saved whether or not fn succeeds.
+func (c Client) doStep(namespace string, p *installProgress, step string, fn func() error) error {
+	if p.completed.Has(step) {
+		log.Printf("  Skipping %s: already completed", step)
+		return nil
+	}
+	defer c.saveInstallProgress(namespace, p)
+	if err := fn(); err != nil {
+		return err
+	}
+	p.completed.Insert(step)
+	return nil
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
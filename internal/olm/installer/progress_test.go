@@ -0,0 +1,197 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installer
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	olmresourceclient "github.com/operator-framework/operator-sdk/internal/olm/client"
+)
+
+const testNamespace = "olm"
+
+// installSteps are the wait steps of an install, in order.
+var installSteps = []string{
+	"customresourcedefinition/clusterserviceversions.operators.coreos.com",
+	"deployment/" + olmOperatorName,
+	"deployment/" + catalogOperatorName,
+	"subscription/packageserver",
+	"deployment/" + packageServerName,
+	"apiservice/" + packageServerAPIServiceName,
+	"catalogsource/operatorhubio-catalog",
+}
+
+// failCreateClient fails to create objects named failName.
+type failCreateClient struct {
+	client.Client
+	failName string
+}
+
+func (c failCreateClient) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	if obj.(metav1.Object).GetName() == c.failName {
+		return errors.New("create failed")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func newServiceAccount(name string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+	}
+}
+
+var _ = Describe("Install progress", func() {
+	var (
+		c   Client
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		c = Client{Client: &olmresourceclient.Client{KubeClient: fake.NewFakeClient()}}
+		ctx = context.Background()
+	})
+
+	// runSteps runs each of installSteps with doStep, stopping at the first
+	// error, and returns the steps that were run.
+	runSteps := func(p *installProgress, failStep string) (ran []string, err error) {
+		for _, step := range installSteps {
+			step := step
+			err = c.doStep(testNamespace, p, step, func() error {
+				ran = append(ran, step)
+				if step == failStep {
+					return errors.New("step failed")
+				}
+				return nil
+			})
+			if err != nil {
+				return ran, err
+			}
+		}
+		return ran, nil
+	}
+
+	Describe("getInstallProgress", func() {
+		It("returns nil if no progress is recorded", func() {
+			p, err := c.getInstallProgress(ctx, testNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(BeNil())
+		})
+
+		It("returns the saved progress", func() {
+			p := newInstallProgress("0.16.1")
+			p.applied.Insert("ServiceAccount/olm/olm-operator-serviceaccount", "Namespace/olm")
+			p.completed.Insert(installSteps[0])
+			c.saveInstallProgress(testNamespace, p)
+
+			saved, err := c.getInstallProgress(ctx, testNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(Equal(p))
+
+			Expect(c.deleteInstallProgress(ctx, testNamespace)).To(Succeed())
+			saved, err = c.getInstallProgress(ctx, testNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved).To(BeNil())
+		})
+	})
+
+	Describe("doStep", func() {
+		It("runs and records every step of a new install", func() {
+			p := newInstallProgress("0.16.1")
+			ran, err := runSteps(p, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ran).To(Equal(installSteps))
+			Expect(p.completed.List()).To(ConsistOf(installSteps))
+		})
+
+		It("resumes from each step", func() {
+			for i := range installSteps {
+				By("resuming from " + installSteps[i])
+				p := newInstallProgress("0.16.1")
+				p.completed.Insert(installSteps[:i]...)
+				c.saveInstallProgress(testNamespace, p)
+
+				saved, err := c.getInstallProgress(ctx, testNamespace)
+				Expect(err).NotTo(HaveOccurred())
+				ran, err := runSteps(saved, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ran).To(Equal(installSteps[i:]))
+
+				saved, err = c.getInstallProgress(ctx, testNamespace)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(saved.completed.List()).To(ConsistOf(installSteps))
+			}
+		})
+
+		It("does not record a failed step and retries it on resume", func() {
+			failStep := installSteps[3]
+			p := newInstallProgress("0.16.1")
+			ran, err := runSteps(p, failStep)
+			Expect(err).To(MatchError("step failed"))
+			Expect(ran).To(Equal(installSteps[:4]))
+
+			saved, err := c.getInstallProgress(ctx, testNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved.completed.List()).To(ConsistOf(installSteps[:3]))
+
+			ran, err = runSteps(saved, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ran).To(Equal(installSteps[3:]))
+		})
+	})
+
+	Describe("createObjects", func() {
+		It("skips applied objects and records created objects", func() {
+			p := newInstallProgress("0.16.1")
+			p.applied.Insert("ServiceAccount/olm/applied")
+			Expect(c.createObjects(ctx, testNamespace, p,
+				newServiceAccount("applied"), newServiceAccount("created"))).To(Succeed())
+
+			sa := corev1.ServiceAccount{}
+			err := c.KubeClient.Get(ctx, client.ObjectKey{Namespace: testNamespace, Name: "applied"}, &sa)
+			Expect(err).To(HaveOccurred())
+			Expect(c.KubeClient.Get(ctx, client.ObjectKey{Namespace: testNamespace, Name: "created"}, &sa)).To(Succeed())
+
+			saved, err := c.getInstallProgress(ctx, testNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved.applied.List()).To(ConsistOf("ServiceAccount/olm/applied", "ServiceAccount/olm/created"))
+		})
+
+		It("records objects created before a failure and resumes after them", func() {
+			c.KubeClient = failCreateClient{Client: c.KubeClient, failName: "second"}
+			p := newInstallProgress("0.16.1")
+			objs := []runtime.Object{newServiceAccount("first"), newServiceAccount("second"), newServiceAccount("third")}
+			Expect(c.createObjects(ctx, testNamespace, p, objs...)).To(MatchError("create failed"))
+
+			saved, err := c.getInstallProgress(ctx, testNamespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved.applied.List()).To(ConsistOf("ServiceAccount/olm/first"))
+
+			c.KubeClient = c.KubeClient.(failCreateClient).Client
+			Expect(c.createObjects(ctx, testNamespace, saved, objs...)).To(Succeed())
+			Expect(saved.applied.List()).To(ConsistOf(
+				"ServiceAccount/olm/first", "ServiceAccount/olm/second", "ServiceAccount/olm/third"))
+		})
+	})
+})
@@ -0,0 +1,152 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installer
+
+import (
+	"fmt"
+	"strings"
+
+	olmapiv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Scheduling configures where the pods of OLM's deployments are scheduled,
+// for clusters whose infrastructure components run on dedicated nodes.
+type Scheduling struct {
+	// NodeSelector is merged into each pod's node selector.
+	NodeSelector map[string]string
+	// Tolerations are added to each pod's tolerations.
+	Tolerations []corev1.Toleration
+	// PriorityClassName, if set, replaces each pod's priority class.
+	PriorityClassName string
+}
+
+func (s Scheduling) isEmpty() bool {
+	return len(s.NodeSelector) == 0 && len(s.Tolerations) == 0 && s.PriorityClassName == ""
+}
+
+// apply sets s in the pod templates of the Deployments in resources, and of
+// the deployments that ClusterServiceVersions in resources install, such as
+// packageserver's.
+func (s Scheduling) apply(resources []unstructured.Unstructured) error {
+	if s.isEmpty() {
+		return nil
+	}
+	for _, r := range resources {
+		switch gvk := r.GroupVersionKind(); {
+		case gvk.Group == "apps" && gvk.Kind == "Deployment":
+			if err := s.applyToPodTemplate(r.Object, "spec", "template", "spec"); err != nil {
+				return fmt.Errorf("error scheduling deployment %q: %v", r.GetName(), err)
+			}
+		case gvk.Group == olmapiv1alpha1.GroupName && gvk.Kind == olmapiv1alpha1.ClusterServiceVersionKind:
+			deployments, _, err := unstructured.NestedSlice(r.Object, "spec", "install", "spec", "deployments")
+			if err != nil {
+				return fmt.Errorf("error scheduling clusterserviceversion %q: %v", r.GetName(), err)
+			}
+			for _, d := range deployments {
+				dep, ok := d.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if err := s.applyToPodTemplate(dep, "spec", "template", "spec"); err != nil {
+					return fmt.Errorf("error scheduling clusterserviceversion %q: %v", r.GetName(), err)
+				}
+			}
+			if err := unstructured.SetNestedSlice(r.Object, deployments, "spec", "install", "spec", "deployments"); err != nil {
+				return fmt.Errorf("error scheduling clusterserviceversion %q: %v", r.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyToPodTemplate sets s in the pod spec of obj at fields.
+func (s Scheduling) applyToPodTemplate(obj map[string]interface{}, fields ...string) error {
+	podSpec, _, err := unstructured.NestedMap(obj, fields...)
+	if err != nil {
+		return err
+	}
+	if podSpec == nil {
+		podSpec = map[string]interface{}{}
+	}
+
+	if len(s.NodeSelector) != 0 {
+		nodeSelector, _, err := unstructured.NestedStringMap(podSpec, "nodeSelector")
+		if err != nil {
+			return err
+		}
+		if nodeSelector == nil {
+			nodeSelector = map[string]string{}
+		}
+		for k, v := range s.NodeSelector {
+			nodeSelector[k] = v
+		}
+		if err := unstructured.SetNestedStringMap(podSpec, nodeSelector, "nodeSelector"); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Tolerations) != 0 {
+		tolerations, _, err := unstructured.NestedSlice(podSpec, "tolerations")
+		if err != nil {
+			return err
+		}
+		for i := range s.Tolerations {
+			toleration, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&s.Tolerations[i])
+			if err != nil {
+				return err
+			}
+			tolerations = append(tolerations, toleration)
+		}
+		if err := unstructured.SetNestedSlice(podSpec, tolerations, "tolerations"); err != nil {
+			return err
+		}
+	}
+
+	if s.PriorityClassName != "" {
+		podSpec["priorityClassName"] = s.PriorityClassName
+	}
+
+	return unstructured.SetNestedMap(obj, podSpec, fields...)
+}
+
+// parseToleration parses a toleration of the form "key[=value][:effect]".
+// A toleration without a value tolerates any value of key, and one without
+// an effect tolerates all effects.
+func parseToleration(toleration string) (corev1.Toleration, error) {
+	s := toleration
+	t := corev1.Toleration{Operator: corev1.TolerationOpExists}
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		t.Effect = corev1.TaintEffect(s[i+1:])
+		s = s[:i]
+		switch t.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return t, fmt.Errorf("invalid toleration effect %q, must be one of: %s, %s, %s", t.Effect,
+				corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+		}
+	}
+	t.Key = s
+	if i := strings.Index(s, "="); i >= 0 {
+		t.Key, t.Value = s[:i], s[i+1:]
+		t.Operator = corev1.TolerationOpEqual
+	}
+	if t.Key == "" {
+		return t, fmt.Errorf("invalid toleration %q: key must be set", toleration)
+	}
+	return t, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package installer
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	olmapiv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Scheduling", func() {
+	DescribeTable("parseToleration",
+		func(toleration string, expected corev1.Toleration) {
+			t, err := parseToleration(toleration)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(Equal(expected))
+		},
+		Entry("key", "dedicated",
+			corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists}),
+		Entry("key and value", "dedicated=olm",
+			corev1.Toleration{Key: "dedicated", Value: "olm", Operator: corev1.TolerationOpEqual}),
+		Entry("key and effect", "dedicated:NoSchedule",
+			corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists,
+				Effect: corev1.TaintEffectNoSchedule}),
+		Entry("key, value and effect", "dedicated=olm:NoExecute",
+			corev1.Toleration{Key: "dedicated", Value: "olm", Operator: corev1.TolerationOpEqual,
+				Effect: corev1.TaintEffectNoExecute}),
+		Entry("empty value", "dedicated=:PreferNoSchedule",
+			corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual,
+				Effect: corev1.TaintEffectPreferNoSchedule}),
+	)
+
+	DescribeTable("parseToleration errors",
+		func(toleration, expectedErr string) {
+			_, err := parseToleration(toleration)
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+		},
+		Entry("empty", "", "key must be set"),
+		Entry("empty key", "=olm", "key must be set"),
+		Entry("only effect", ":NoSchedule", "key must be set"),
+		Entry("invalid effect", "dedicated:Never", `invalid toleration effect "Never"`),
+		Entry("empty effect", "dedicated=olm:", `invalid toleration effect ""`),
+	)
+
+	podSpec := func(obj map[string]interface{}, fields ...string) map[string]interface{} {
+		spec, found, err := unstructured.NestedMap(obj, fields...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		return spec
+	}
+
+	DescribeTable("apply",
+		func(s Scheduling, initial, expected map[string]interface{}) {
+			deployment := unstructured.Unstructured{}
+			deployment.SetAPIVersion("apps/v1")
+			deployment.SetKind("Deployment")
+			deployment.SetName(olmOperatorName)
+			Expect(unstructured.SetNestedMap(deployment.Object, initial, "spec", "template", "spec")).To(Succeed())
+
+			csv := unstructured.Unstructured{}
+			csv.SetGroupVersionKind(olmapiv1alpha1.SchemeGroupVersion.WithKind(olmapiv1alpha1.ClusterServiceVersionKind))
+			csv.SetName(packageServerName)
+			Expect(unstructured.SetNestedSlice(csv.Object, []interface{}{
+				map[string]interface{}{
+					"name": packageServerName,
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{"spec": initial},
+					},
+				},
+			}, "spec", "install", "spec", "deployments")).To(Succeed())
+
+			service := unstructured.Unstructured{}
+			service.SetAPIVersion("v1")
+			service.SetKind("Service")
+			service.SetName("packageserver-service")
+			Expect(unstructured.SetNestedMap(service.Object, initial, "spec", "template", "spec")).To(Succeed())
+
+			Expect(s.apply([]unstructured.Unstructured{deployment, csv, service})).To(Succeed())
+
+			Expect(podSpec(deployment.Object, "spec", "template", "spec")).To(Equal(expected))
+			deployments, _, err := unstructured.NestedSlice(csv.Object, "spec", "install", "spec", "deployments")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployments).To(HaveLen(1))
+			Expect(podSpec(deployments[0].(map[string]interface{}), "spec", "template", "spec")).To(Equal(expected))
+			// Other kinds are left as they are.
+			Expect(podSpec(service.Object, "spec", "template", "spec")).To(Equal(initial))
+		},
+		Entry("empty scheduling",
+			Scheduling{},
+			map[string]interface{}{"serviceAccountName": "olm-operator-serviceaccount"},
+			map[string]interface{}{"serviceAccountName": "olm-operator-serviceaccount"},
+		),
+		Entry("node selector merged into an existing one",
+			Scheduling{NodeSelector: map[string]string{"node-role.kubernetes.io/infra": "", "kubernetes.io/os": "linux"}},
+			map[string]interface{}{
+				"nodeSelector": map[string]interface{}{"kubernetes.io/os": "windows", "zone": "a"},
+			},
+			map[string]interface{}{
+				"nodeSelector": map[string]interface{}{
+					"kubernetes.io/os":              "linux",
+					"node-role.kubernetes.io/infra": "",
+					"zone":                          "a",
+				},
+			},
+		),
+		Entry("tolerations appended to existing ones",
+			Scheduling{Tolerations: []corev1.Toleration{
+				{Key: "dedicated", Value: "olm", Operator: corev1.TolerationOpEqual, Effect: corev1.TaintEffectNoSchedule},
+			}},
+			map[string]interface{}{
+				"tolerations": []interface{}{
+					map[string]interface{}{"key": "CriticalAddonsOnly", "operator": "Exists"},
+				},
+			},
+			map[string]interface{}{
+				"tolerations": []interface{}{
+					map[string]interface{}{"key": "CriticalAddonsOnly", "operator": "Exists"},
+					map[string]interface{}{"key": "dedicated", "value": "olm", "operator": "Equal",
+						"effect": "NoSchedule"},
+				},
+			},
+		),
+		Entry("priority class replaced",
+			Scheduling{PriorityClassName: "system-cluster-critical"},
+			map[string]interface{}{"priorityClassName": "low"},
+			map[string]interface{}{"priorityClassName": "system-cluster-critical"},
+		),
+	)
+
+	It("should fail on a malformed pod template", func() {
+		deployment := unstructured.Unstructured{}
+		deployment.SetAPIVersion("apps/v1")
+		deployment.SetKind("Deployment")
+		deployment.SetName(olmOperatorName)
+		Expect(unstructured.SetNestedField(deployment.Object, "bad", "spec", "template", "spec",
+			"nodeSelector")).To(Succeed())
+
+		err := Scheduling{NodeSelector: map[string]string{"zone": "a"}}.apply([]unstructured.Unstructured{deployment})
+		Expect(err).To(MatchError(ContainSubstring(`error scheduling deployment "olm-operator"`)))
+	})
+})
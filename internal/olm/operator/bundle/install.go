@@ -16,6 +16,7 @@ package bundle
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -34,6 +35,17 @@ import (
 type Install struct {
 	BundleImage string
 
+	// VerifySignature verifies the cosign signatures of the bundle image and,
+	// if set, a custom index image before installing.
+	VerifySignature bool
+	// SignatureKey is the path or KMS URI of the public key to verify signatures
+	// with. If empty, keyless signatures are verified.
+	SignatureKey string
+	// UseExistingCatalog creates a catalog source that serves IndexImage as
+	// is, instead of adding BundleImage to it in a registry pod. IndexImage
+	// must already contain the bundle.
+	UseExistingCatalog bool
+
 	*registry.IndexImageCatalogCreator
 	*registry.OperatorInstaller
 
@@ -54,9 +66,24 @@ const defaultIndexImage = "quay.io/operator-framework/upstream-opm-builder:lates
 
 func (i *Install) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&i.IndexImage, "index-image", defaultIndexImage, "index image in which to inject bundle")
-	fs.Var(&i.InstallMode, "install-mode", "install mode")
+	fs.Var(&i.InstallMode, "install-mode", "install mode of the operator, one of AllNamespaces, OwnNamespace, "+
+		"SingleNamespace=<ns>, or MultiNamespace=<ns1>,<ns2>. It must be supported by the CSV. "+
+		"If unset, AllNamespaces or OwnNamespace is used if supported")
 	fs.StringVar(&i.InjectBundleMode, "mode", "", "mode to use for adding bundle to index")
 	_ = fs.MarkHidden("mode")
+	fs.BoolVar(&i.UseExistingCatalog, "use-existing-catalog", false, "serve --index-image, which must already "+
+		"contain the bundle, from a catalog source instead of adding the bundle to it in a registry pod. "+
+		"Use in disconnected clusters with a mirrored index image")
+	fs.BoolVar(&i.VerifySignature, "verify-signature", false, "verify the cosign signatures of the bundle image "+
+		"and, if set, the index image before installing")
+	fs.StringVar(&i.SignatureKey, "signature-key", "", "path or KMS URI of the public key to verify signatures with. "+
+		"If unset, keyless signatures are verified")
+	fs.DurationVar(&i.CatalogTimeout, "catalog-timeout", 0, "time to wait for the catalog source to be ready. "+
+		"If unset, catalog readiness is not checked")
+	fs.DurationVar(&i.SubscriptionTimeout, "subscription-timeout", 0, "time to wait for the subscription to "+
+		"resolve an install plan. If unset, only --timeout applies")
+	fs.DurationVar(&i.CSVTimeout, "csv-timeout", 0, "time to wait for the ClusterServiceVersion to succeed. "+
+		"If unset, only --timeout applies")
 }
 
 func (i Install) Run(ctx context.Context) (*v1alpha1.ClusterServiceVersion, error) {
@@ -67,6 +94,17 @@ func (i Install) Run(ctx context.Context) (*v1alpha1.ClusterServiceVersion, erro
 }
 
 func (i *Install) setup(ctx context.Context) error {
+	if i.UseExistingCatalog && i.IndexImage == defaultIndexImage {
+		return errors.New("--index-image must be set to an index image containing the bundle " +
+			"when --use-existing-catalog is set")
+	}
+
+	if i.VerifySignature {
+		if err := i.verifySignatures(ctx); err != nil {
+			return err
+		}
+	}
+
 	labels, csv, err := loadBundle(ctx, i.BundleImage)
 	if err != nil {
 		return err
@@ -89,6 +127,28 @@ func (i *Install) setup(ctx context.Context) error {
 		i.IndexImageCatalogCreator.InjectBundleMode = "semver"
 	}
 
+	if i.UseExistingCatalog {
+		c := registry.NewImageCatalogCreator(i.cfg)
+		c.PackageName = i.OperatorInstaller.PackageName
+		c.IndexImage = i.IndexImage
+		i.OperatorInstaller.CatalogCreator = c
+	}
+
+	return nil
+}
+
+// verifySignatures verifies the bundle image's signature, and the index image's
+// signature if it is not the default index image.
+func (i Install) verifySignatures(ctx context.Context) error {
+	images := []string{i.BundleImage}
+	if i.IndexImage != defaultIndexImage {
+		images = append(images, i.IndexImage)
+	}
+	for _, image := range images {
+		if err := registryutil.VerifyImageSignature(ctx, nil, image, i.SignatureKey); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -26,11 +26,14 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
 )
 
 type Configuration struct {
 	Namespace      string
 	KubeconfigPath string
+	KubeContext    string
 	RESTConfig     *rest.Config
 	Client         client.Client
 	Scheme         *runtime.Scheme
@@ -52,21 +55,14 @@ func (c *Configuration) BindFlags(fs *pflag.FlagSet) {
 			},
 		},
 	})
-	fs.StringVar(&c.KubeconfigPath, "kubeconfig", "",
-		"Path to the kubeconfig file to use for CLI requests.")
+	k8sutil.BindKubeconfigFlags(fs, &c.KubeconfigPath, &c.KubeContext)
 }
 
 func (c *Configuration) Load() error {
 	if c.overrides == nil {
 		c.overrides = &clientcmd.ConfigOverrides{}
 	}
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	loadingRules.ExplicitPath = c.KubeconfigPath
-	mergedConfig, err := loadingRules.Load()
-	if err != nil {
-		return err
-	}
-	cfg := clientcmd.NewDefaultClientConfig(*mergedConfig, c.overrides)
+	cfg := k8sutil.NewClientConfig(c.KubeconfigPath, c.KubeContext, c.overrides)
 	cc, err := cfg.ClientConfig()
 	if err != nil {
 		return err
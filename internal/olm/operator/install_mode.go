@@ -110,21 +110,24 @@ func (i InstallMode) CheckCompatibility(csv *v1alpha1.ClusterServiceVersion, ope
 
 	// single namespace and targetns == opname
 	if i.InstallModeType == v1alpha1.InstallModeTypeSingleNamespace {
-		if len(i.TargetNamespaces) < 1 || i.TargetNamespaces[0] == operatorNamespace {
+		if len(i.TargetNamespaces) != 1 {
+			return fmt.Errorf("install mode %q must have exactly one target namespace", i.InstallModeType)
+		}
+		if i.TargetNamespaces[0] == operatorNamespace {
 			return fmt.Errorf("use install mode %q to watch operator's namespace %q", v1alpha1.InstallModeTypeOwnNamespace, i.TargetNamespaces[0])
 		}
 	}
 
 	// ensure the CSV has an installmode
-	if len(csv.Spec.InstallModes) == 0 {
+	supported := GetSupportedInstallModes(csv.Spec.InstallModes)
+	if supported.Len() == 0 {
 		return fmt.Errorf("operator %q is not installable: no supported install modes", csv.Name)
 	}
 
 	// ensure the CSV supports the given installmode
-	for _, mode := range csv.Spec.InstallModes {
-		if mode.Type == i.InstallModeType && !mode.Supported {
-			return fmt.Errorf("install mode type %q not supported in CSV %q", i.InstallModeType, csv.GetName())
-		}
+	if !i.IsEmpty() && !supported.Has(string(i.InstallModeType)) {
+		return fmt.Errorf("install mode type %q not supported in CSV %q, supported install modes: %s",
+			i.InstallModeType, csv.GetName(), strings.Join(supported.List(), ", "))
 	}
 	return nil
 }
@@ -63,4 +63,41 @@ var _ = Describe("InstallMode", func() {
 			Expect(supported.Has(string(v1alpha1.InstallModeTypeAllNamespaces))).Should(BeFalse())
 		})
 	})
+
+	Describe("CheckCompatibility", func() {
+		var csv *v1alpha1.ClusterServiceVersion
+		BeforeEach(func() {
+			csv = &v1alpha1.ClusterServiceVersion{}
+			csv.SetName("memcached-operator.v0.0.1")
+			csv.Spec.InstallModes = []v1alpha1.InstallMode{
+				{Type: v1alpha1.InstallModeTypeOwnNamespace, Supported: true},
+				{Type: v1alpha1.InstallModeTypeMultiNamespace, Supported: true},
+				{Type: v1alpha1.InstallModeTypeAllNamespaces, Supported: false},
+			}
+		})
+		It("should accept a supported install mode", func() {
+			var i InstallMode
+			Expect(i.Set("MultiNamespace=ns1,ns2")).To(Succeed())
+			Expect(i.CheckCompatibility(csv, "testns")).To(Succeed())
+		})
+		It("should accept an empty install mode", func() {
+			Expect(InstallMode{}.CheckCompatibility(csv, "testns")).To(Succeed())
+		})
+		It("should reject an install mode the CSV marks as unsupported", func() {
+			var i InstallMode
+			Expect(i.Set("AllNamespaces")).To(Succeed())
+			err := i.CheckCompatibility(csv, "testns")
+			Expect(err).To(MatchError(ContainSubstring(`install mode type "AllNamespaces" not supported`)))
+		})
+		It("should reject an install mode the CSV does not list", func() {
+			var i InstallMode
+			Expect(i.Set("SingleNamespace=ns1")).To(Succeed())
+			err := i.CheckCompatibility(csv, "testns")
+			Expect(err).To(MatchError(ContainSubstring("supported install modes: MultiNamespace, OwnNamespace")))
+		})
+		It("should reject SingleNamespace without a target namespace", func() {
+			i := InstallMode{InstallModeType: v1alpha1.InstallModeTypeSingleNamespace}
+			Expect(i.CheckCompatibility(csv, "testns")).To(MatchError(ContainSubstring("exactly one target namespace")))
+		})
+	})
 })
@@ -48,7 +48,9 @@ func NewInstall(cfg *operator.Configuration) Install {
 }
 
 func (i *Install) BindFlags(fs *pflag.FlagSet) {
-	fs.Var(&i.InstallMode, "install-mode", "install mode")
+	fs.Var(&i.InstallMode, "install-mode", "install mode of the operator, one of AllNamespaces, OwnNamespace, "+
+		"SingleNamespace=<ns>, or MultiNamespace=<ns1>,<ns2>. It must be supported by the CSV. "+
+		"If unset, AllNamespaces or OwnNamespace is used if supported")
 	fs.StringVar(&i.Version, "version", "", "Packaged version of the operator to deploy")
 }
 
@@ -0,0 +1,62 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+
+	"github.com/operator-framework/operator-sdk/internal/olm/operator"
+)
+
+// ImageCatalogCreator creates a CatalogSource that serves an existing index
+// image, which must already contain the package's bundles. Unlike
+// IndexImageCatalogCreator, no registry pod is created to add bundles to the
+// index on the fly; OLM serves the index image itself, so it can be pulled
+// from a mirror in disconnected clusters.
+type ImageCatalogCreator struct {
+	PackageName string
+	IndexImage  string
+
+	cfg *operator.Configuration
+}
+
+func NewImageCatalogCreator(cfg *operator.Configuration) *ImageCatalogCreator {
+	return &ImageCatalogCreator{
+		cfg: cfg,
+	}
+}
+
+func (c ImageCatalogCreator) CreateCatalog(ctx context.Context, name string) (*v1alpha1.CatalogSource, error) {
+	cs := newCatalogSource(name, c.cfg.Namespace,
+		withSDKPublisher(c.PackageName),
+		withIndexImage(c.IndexImage))
+	if err := c.cfg.Client.Create(ctx, cs); err != nil {
+		return nil, fmt.Errorf("error creating catalog source: %v", err)
+	}
+	return cs, nil
+}
+
+func withIndexImage(image string) func(*v1alpha1.CatalogSource) {
+	return func(cs *v1alpha1.CatalogSource) {
+		cs.Spec.SourceType = v1alpha1.SourceTypeGrpc
+		cs.Spec.Image = image
+		cs.SetAnnotations(map[string]string{
+			"operators.operatorframework.io/index-image": image,
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/operator-framework/operator-sdk/internal/olm/operator"
+)
+
+var _ = Describe("ImageCatalogCreator", func() {
+	Describe("CreateCatalog", func() {
+		It("creates a catalog source serving the index image", func() {
+			sch := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(sch)).To(Succeed())
+			cfg := &operator.Configuration{
+				Scheme:    sch,
+				Client:    fake.NewFakeClientWithScheme(sch),
+				Namespace: "testns",
+			}
+			c := NewImageCatalogCreator(cfg)
+			c.PackageName = "memcached-operator"
+			c.IndexImage = "mirror.example.com/memcached-index:v0.0.1"
+
+			_, err := c.CreateCatalog(context.TODO(), "memcached-operator-catalog")
+			Expect(err).NotTo(HaveOccurred())
+
+			cs := &v1alpha1.CatalogSource{}
+			key := types.NamespacedName{Namespace: "testns", Name: "memcached-operator-catalog"}
+			Expect(cfg.Client.Get(context.TODO(), key, cs)).To(Succeed())
+			Expect(cs.Spec.SourceType).To(Equal(v1alpha1.SourceTypeGrpc))
+			Expect(cs.Spec.Image).To(Equal("mirror.example.com/memcached-index:v0.0.1"))
+			Expect(cs.Spec.Address).To(BeEmpty())
+			Expect(cs.Spec.DisplayName).To(Equal("memcached-operator"))
+		})
+	})
+})
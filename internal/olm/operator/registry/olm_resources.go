@@ -49,7 +49,7 @@ func withPackageChannel(pkgName, channelName, startingCSV string) func(*v1alpha1
 }
 
 // withInstallPlanApproval sets the Subscription's install plan approval field
-// to manual
+// to approval
 func withInstallPlanApproval(approval v1alpha1.Approval) func(*v1alpha1.Subscription) {
 	return func(sub *v1alpha1.Subscription) {
 		if sub.Spec == nil {
@@ -17,6 +17,7 @@ package registry
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	v1 "github.com/operator-framework/api/pkg/operators/v1"
@@ -41,6 +42,20 @@ type OperatorInstaller struct {
 	CatalogCreator        CatalogCreator
 	SupportedInstallModes sets.String
 
+	// CatalogTimeout, if positive, is how long to wait for the catalog source
+	// to report a ready connection. If zero, catalog readiness is not checked.
+	CatalogTimeout time.Duration
+	// SubscriptionTimeout and CSVTimeout, if positive, bound how long to wait
+	// for the subscription to resolve an install plan and for the CSV to
+	// succeed, respectively. Both are also bounded by the context passed to
+	// InstallOperator.
+	SubscriptionTimeout time.Duration
+	CSVTimeout          time.Duration
+	// NoWait creates the operator's resources and returns without waiting for
+	// them to be installed. The subscription's install plans are approved
+	// automatically instead.
+	NoWait bool
+
 	cfg *operator.Configuration
 }
 
@@ -55,15 +70,17 @@ func (o OperatorInstaller) InstallOperator(ctx context.Context) (*v1alpha1.Clust
 	}
 	log.Infof("Created CatalogSource: %s", cs.GetName())
 
-	// TODO: OLM doesn't appear to propagate the "READY" connection status to the
+	// OLM doesn't appear to propagate the "READY" connection status to the
 	// catalogsource in a timely manner even though its catalog-operator reports
-	// a connection almost immediately. This condition either needs to be
-	// propagated more quickly by OLM or we need to find a different resource to
-	// probe for readiness.
-	//
-	// if err := o.waitForCatalogSource(ctx, cs); err != nil {
-	// 	return nil, err
-	// }
+	// a connection almost immediately, so readiness is only checked if a
+	// catalog timeout is set.
+	if o.CatalogTimeout > 0 && !o.NoWait {
+		catalogCtx, cancel := context.WithTimeout(ctx, o.CatalogTimeout)
+		defer cancel()
+		if err := o.waitForCatalogSource(catalogCtx, cs); err != nil {
+			return nil, err
+		}
+	}
 
 	// Ensure Operator Group
 	if err = o.ensureOperatorGroup(ctx); err != nil {
@@ -76,8 +93,15 @@ func (o OperatorInstaller) InstallOperator(ctx context.Context) (*v1alpha1.Clust
 		return nil, err
 	}
 
+	if o.NoWait {
+		log.Infof("Not waiting for OLM to install %q", o.StartingCSV)
+		return nil, nil
+	}
+
 	// Wait for the Install Plan to be generated
-	if err = o.waitForInstallPlan(ctx, subscription); err != nil {
+	subscriptionCtx, cancel := withTimeout(ctx, o.SubscriptionTimeout)
+	defer cancel()
+	if err = o.waitForInstallPlan(subscriptionCtx, subscription); err != nil {
 		return nil, err
 	}
 
@@ -87,7 +111,9 @@ func (o OperatorInstaller) InstallOperator(ctx context.Context) (*v1alpha1.Clust
 	}
 
 	// Wait for successfully installed CSV
-	csv, err := o.getInstalledCSV(ctx)
+	csvCtx, cancel := withTimeout(ctx, o.CSVTimeout)
+	defer cancel()
+	csv, err := o.getInstalledCSV(csvCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +123,15 @@ func (o OperatorInstaller) InstallOperator(ctx context.Context) (*v1alpha1.Clust
 	return csv, nil
 }
 
-//nolint:unused
+// withTimeout returns a copy of ctx that is also cancelled after timeout, if
+// timeout is positive.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 func (o OperatorInstaller) waitForCatalogSource(ctx context.Context, cs *v1alpha1.CatalogSource) error {
 	catSrcKey, err := client.ObjectKeyFromObject(cs)
 	if err != nil {
@@ -212,7 +246,7 @@ func (o OperatorInstaller) createSubscription(ctx context.Context, cs *v1alpha1.
 	sub := newSubscription(o.StartingCSV, o.cfg.Namespace,
 		withPackageChannel(o.PackageName, o.Channel, o.StartingCSV),
 		withCatalogSource(cs.GetName(), o.cfg.Namespace),
-		withInstallPlanApproval(v1alpha1.ApprovalManual))
+		withInstallPlanApproval(o.installPlanApproval()))
 
 	if err := o.cfg.Client.Create(ctx, sub); err != nil {
 		return nil, fmt.Errorf("error creating subscription: %w", err)
@@ -222,6 +256,16 @@ func (o OperatorInstaller) createSubscription(ctx context.Context, cs *v1alpha1.
 	return sub, nil
 }
 
+// installPlanApproval returns the install plan approval strategy of the
+// subscription. Install plans are approved manually, so that only StartingCSV
+// is installed, unless the installer does not wait to approve them.
+func (o OperatorInstaller) installPlanApproval() v1alpha1.Approval {
+	if o.NoWait {
+		return v1alpha1.ApprovalAutomatic
+	}
+	return v1alpha1.ApprovalManual
+}
+
 func (o OperatorInstaller) getInstalledCSV(ctx context.Context) (*v1alpha1.ClusterServiceVersion, error) {
 	c, err := olmclient.NewClientForConfig(o.cfg.RESTConfig)
 	if err != nil {
@@ -244,6 +288,14 @@ func (o OperatorInstaller) getInstalledCSV(ctx context.Context) (*v1alpha1.Clust
 	if err = o.cfg.Client.Get(ctx, nn, csv); err != nil {
 		return nil, fmt.Errorf("error getting installed CSV: %w", err)
 	}
+
+	// Wait for the operator's CRDs to be established, so that custom resources
+	// can be created as soon as the operator is installed.
+	for _, desc := range csv.Spec.CustomResourceDefinitions.Owned {
+		if err = c.DoCRDWait(ctx, types.NamespacedName{Name: desc.Name}); err != nil {
+			return nil, fmt.Errorf("error waiting for CRD %q to be established: %w", desc.Name, err)
+		}
+	}
 	return csv, nil
 }
 
@@ -305,7 +357,13 @@ func (o *OperatorInstaller) getTargetNamespaces(supported sets.String) ([]string
 		return nil, nil
 	case supported.Has(string(v1alpha1.InstallModeTypeOwnNamespace)):
 		return []string{o.cfg.Namespace}, nil
-	case supported.Has(string(v1alpha1.InstallModeTypeSingleNamespace)):
+	case supported.Has(string(v1alpha1.InstallModeTypeSingleNamespace)),
+		supported.Has(string(v1alpha1.InstallModeTypeMultiNamespace)):
+		// Without target namespaces, an OperatorGroup targets all namespaces.
+		if len(o.InstallMode.TargetNamespaces) == 0 {
+			return nil, fmt.Errorf("operator %q supports only install modes with target namespaces (%s): "+
+				"set them with --install-mode", o.StartingCSV, strings.Join(supported.List(), ", "))
+		}
 		return o.InstallMode.TargetNamespaces, nil
 	default:
 		return nil, fmt.Errorf("no supported install modes")
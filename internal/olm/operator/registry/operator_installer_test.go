@@ -336,7 +336,40 @@ var _ = Describe("OperatorInstaller", func() {
 	})
 
 	Describe("createSubscription", func() {
-		// TODO: add them as part of a different story
+		var (
+			oi OperatorInstaller
+			cs *v1alpha1.CatalogSource
+		)
+		BeforeEach(func() {
+			sch := runtime.NewScheme()
+			Expect(v1alpha1.AddToScheme(sch)).To(Succeed())
+			oi = OperatorInstaller{
+				PackageName: "memcached-operator",
+				StartingCSV: "memcached-operator.v0.0.1",
+				Channel:     "alpha",
+				cfg: &operator.Configuration{
+					Scheme:    sch,
+					Client:    fake.NewFakeClientWithScheme(sch),
+					Namespace: "testnamespace",
+				},
+			}
+			cs = &v1alpha1.CatalogSource{}
+			cs.SetName("memcached-operator-catalog")
+		})
+		It("should create a subscription with manual install plan approval", func() {
+			sub, err := oi.createSubscription(context.TODO(), cs)
+			Expect(err).To(BeNil())
+			Expect(sub.Spec.Package).To(Equal("memcached-operator"))
+			Expect(sub.Spec.StartingCSV).To(Equal("memcached-operator.v0.0.1"))
+			Expect(sub.Spec.CatalogSource).To(Equal("memcached-operator-catalog"))
+			Expect(sub.Spec.InstallPlanApproval).To(Equal(v1alpha1.ApprovalManual))
+		})
+		It("should create a subscription with automatic install plan approval if not waiting", func() {
+			oi.NoWait = true
+			sub, err := oi.createSubscription(context.TODO(), cs)
+			Expect(err).To(BeNil())
+			Expect(sub.Spec.InstallPlanApproval).To(Equal(v1alpha1.ApprovalAutomatic))
+		})
 	})
 
 	Describe("getTargetNamespaces", func() {
@@ -383,6 +416,24 @@ var _ = Describe("OperatorInstaller", func() {
 			Expect(target[0]).To(Equal("test-ns"))
 			Expect(err).To(BeNil())
 		})
+		It("should return configured namespaces when MultiNamespace is passed in", func() {
+			oi.InstallMode = operator.InstallMode{
+				InstallModeType:  v1alpha1.InstallModeTypeMultiNamespace,
+				TargetNamespaces: []string{"test-ns1", "test-ns2"},
+			}
+
+			supported.Insert(string(v1alpha1.InstallModeTypeMultiNamespace))
+			target, err := oi.getTargetNamespaces(supported)
+			Expect(err).To(BeNil())
+			Expect(target).To(Equal([]string{"test-ns1", "test-ns2"}))
+		})
+		It("should return an error when only modes with target namespaces are supported and none are passed in", func() {
+			supported.Insert(string(v1alpha1.InstallModeTypeSingleNamespace), string(v1alpha1.InstallModeTypeMultiNamespace))
+			target, err := oi.getTargetNamespaces(supported)
+			Expect(target).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("--install-mode"))
+		})
 	})
 })
 
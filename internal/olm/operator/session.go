@@ -0,0 +1,124 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// SessionLabel is set on each resource created in a session to the session's
+// ID, so that the resources of a session can be found and cleaned up.
+const SessionLabel = "operators.operatorframework.io/run-session"
+
+// Session is a client.Client that labels the objects it creates with its ID
+// and records them.
+type Session struct {
+	client.Client
+	ID string
+
+	scheme *runtime.Scheme
+
+	mu      sync.Mutex
+	created []runtime.Object
+}
+
+// NewSession returns a Session with a random ID that creates objects with c.
+// The kinds of created objects that have no type metadata are looked up in
+// scheme.
+func NewSession(c client.Client, scheme *runtime.Scheme) *Session {
+	return &Session{Client: c, ID: rand.String(8), scheme: scheme}
+}
+
+func (s *Session) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	labels := accessor.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[SessionLabel] = s.ID
+	accessor.SetLabels(labels)
+
+	if err := s.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	created := obj.DeepCopyObject()
+	if created.GetObjectKind().GroupVersionKind().Empty() {
+		gvk, err := apiutil.GVKForObject(obj, s.scheme)
+		if err != nil {
+			return err
+		}
+		created.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.created = append(s.created, created)
+	return nil
+}
+
+// Created returns the objects created in the session, in creation order, as
+// they were when created.
+func (s *Session) Created() []runtime.Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]runtime.Object(nil), s.created...)
+}
+
+// WriteManifests writes the objects created in the session to dir, one file
+// per object named "<index>-<kind>-<name>.yaml" so that the files sort in
+// creation order. Server-populated metadata and status are removed.
+func (s *Session) WriteManifests(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for i, obj := range s.Created() {
+		u := &unstructured.Unstructured{}
+		var err error
+		if u.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+			return err
+		}
+		for _, field := range []string{"resourceVersion", "uid", "selfLink", "generation", "creationTimestamp",
+			"managedFields"} {
+			unstructured.RemoveNestedField(u.Object, "metadata", field)
+		}
+		unstructured.RemoveNestedField(u.Object, "status")
+
+		b, err := yaml.Marshal(u.Object)
+		if err != nil {
+			return fmt.Errorf("marshal %s %q: %v", strings.ToLower(u.GetKind()), u.GetName(), err)
+		}
+		name := fmt.Sprintf("%02d-%s-%s.yaml", i, strings.ToLower(u.GetKind()), u.GetName())
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Session", func() {
+	var s *Session
+
+	BeforeEach(func() {
+		sch := runtime.NewScheme()
+		Expect(corev1.AddToScheme(sch)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(sch)).To(Succeed())
+		s = NewSession(fake.NewFakeClientWithScheme(sch), sch)
+	})
+
+	createObjects := func() {
+		cs := &v1alpha1.CatalogSource{}
+		cs.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(v1alpha1.CatalogSourceKind))
+		cs.SetName("memcached-operator-catalog")
+		cs.SetNamespace("testns")
+		Expect(s.Create(context.TODO(), cs)).To(Succeed())
+
+		pod := &corev1.Pod{}
+		pod.SetName("registry")
+		pod.SetNamespace("testns")
+		pod.SetLabels(map[string]string{"app": "registry"})
+		Expect(s.Create(context.TODO(), pod)).To(Succeed())
+	}
+
+	It("labels created objects with the session ID", func() {
+		Expect(s.ID).To(HaveLen(8))
+		createObjects()
+
+		pod := &corev1.Pod{}
+		Expect(s.Get(context.TODO(), types.NamespacedName{Namespace: "testns", Name: "registry"}, pod)).To(Succeed())
+		Expect(pod.GetLabels()).To(Equal(map[string]string{"app": "registry", SessionLabel: s.ID}))
+		Expect(s.Created()).To(HaveLen(2))
+	})
+
+	It("writes created objects to a directory in creation order", func() {
+		createObjects()
+		dir, err := ioutil.TempDir("", "session-manifests-")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		Expect(s.WriteManifests(dir)).To(Succeed())
+		files, err := ioutil.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(2))
+		Expect(files[0].Name()).To(Equal("00-catalogsource-memcached-operator-catalog.yaml"))
+		Expect(files[1].Name()).To(Equal("01-pod-registry.yaml"))
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, files[1].Name()))
+		Expect(err).NotTo(HaveOccurred())
+		obj := map[string]interface{}{}
+		Expect(yaml.Unmarshal(b, &obj)).To(Succeed())
+		Expect(obj).NotTo(HaveKey("status"))
+		Expect(obj["metadata"]).NotTo(HaveKey("resourceVersion"))
+		Expect(obj["metadata"]).To(HaveKeyWithValue("labels", HaveKeyWithValue(SessionLabel, s.ID)))
+	})
+})
+
+var _ = Describe("Uninstall", func() {
+	Describe("matches", func() {
+		var sub v1alpha1.Subscription
+
+		BeforeEach(func() {
+			sub = v1alpha1.Subscription{Spec: &v1alpha1.SubscriptionSpec{Package: "memcached-operator"}}
+			sub.SetLabels(map[string]string{SessionLabel: "abcd1234"})
+		})
+
+		It("matches the subscription by package", func() {
+			Expect((&Uninstall{Package: "memcached-operator"}).matches(sub)).To(BeTrue())
+			Expect((&Uninstall{Package: "other-operator"}).matches(sub)).To(BeFalse())
+		})
+		It("matches the subscription by session", func() {
+			Expect((&Uninstall{Session: "abcd1234"}).matches(sub)).To(BeTrue())
+			Expect((&Uninstall{Session: "efgh5678"}).matches(sub)).To(BeFalse())
+		})
+		It("matches the subscription by package and session", func() {
+			Expect((&Uninstall{Package: "memcached-operator", Session: "abcd1234"}).matches(sub)).To(BeTrue())
+			Expect((&Uninstall{Package: "other-operator", Session: "abcd1234"}).matches(sub)).To(BeFalse())
+		})
+	})
+})
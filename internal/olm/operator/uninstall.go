@@ -22,6 +22,7 @@ import (
 
 	v1 "github.com/operator-framework/api/pkg/operators/v1"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -41,6 +42,12 @@ type Uninstall struct {
 	DeleteCRDs               bool
 	DeleteOperatorGroups     bool
 	DeleteOperatorGroupNames []string
+	// NoWait deletes resources without waiting for them to be removed.
+	NoWait bool
+	// Session, if set, is the ID of the run session whose operator is
+	// uninstalled. Resources labeled with the session that are not removed
+	// along with the operator, such as registry pods, are also deleted.
+	Session string
 
 	Logf func(string, ...interface{})
 }
@@ -65,14 +72,18 @@ func (u *Uninstall) Run(ctx context.Context) error {
 	var sub *v1alpha1.Subscription
 	for i := range subs.Items {
 		s := subs.Items[i]
-		if u.Package == s.Spec.Package {
+		if u.matches(s) {
 			sub = &s
 			break
 		}
 	}
 	if sub == nil {
+		if u.Session != "" {
+			return fmt.Errorf("operator of session %q not found", u.Session)
+		}
 		return fmt.Errorf("operator package %q not found", u.Package)
 	}
+	u.Package = sub.Spec.Package
 
 	catsrcKey := types.NamespacedName{
 		Namespace: sub.Spec.CatalogSourceNamespace,
@@ -127,6 +138,12 @@ func (u *Uninstall) Run(ctx context.Context) error {
 		return err
 	}
 
+	if u.Session != "" {
+		if err := u.deleteSessionPods(ctx); err != nil {
+			return err
+		}
+	}
+
 	// If this was the last subscription in the namespace and the operator group is
 	// the one we created, delete it
 	if u.DeleteOperatorGroups {
@@ -140,7 +157,8 @@ func (u *Uninstall) Run(ctx context.Context) error {
 			}
 			for _, og := range ogs.Items {
 				og := og
-				if len(u.DeleteOperatorGroupNames) == 0 || slice.ContainsString(u.DeleteOperatorGroupNames, og.GetName(), nil) {
+				if len(u.DeleteOperatorGroupNames) == 0 || slice.ContainsString(u.DeleteOperatorGroupNames, og.GetName(), nil) ||
+					u.inSession(&og) {
 					if err := u.deleteObjects(ctx, false, &og); err != nil {
 						return err
 					}
@@ -151,6 +169,37 @@ func (u *Uninstall) Run(ctx context.Context) error {
 	return nil
 }
 
+// matches returns true if sub is the subscription of the operator being
+// uninstalled.
+func (u *Uninstall) matches(sub v1alpha1.Subscription) bool {
+	if u.Package != "" && u.Package != sub.Spec.Package {
+		return false
+	}
+	return u.Session == "" || u.inSession(&sub)
+}
+
+func (u *Uninstall) inSession(obj controllerutil.Object) bool {
+	return u.Session != "" && obj.GetLabels()[SessionLabel] == u.Session
+}
+
+// deleteSessionPods deletes the pods labeled with the session, such as
+// registry pods, that were not garbage collected with their catalog source.
+func (u *Uninstall) deleteSessionPods(ctx context.Context) error {
+	pods := corev1.PodList{}
+	if err := u.config.Client.List(ctx, &pods, client.InNamespace(u.config.Namespace),
+		client.MatchingLabels{SessionLabel: u.Session}); err != nil {
+		return fmt.Errorf("list pods: %v", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		pod.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+		if err := u.deleteObjects(ctx, false, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (u *Uninstall) deleteObjects(ctx context.Context, waitForDelete bool, objs ...controllerutil.Object) error {
 	for _, obj := range objs {
 		obj := obj
@@ -160,7 +209,7 @@ func (u *Uninstall) deleteObjects(ctx context.Context, waitForDelete bool, objs
 		} else if err == nil {
 			u.Logf("%s %q deleted", lowerKind, obj.GetName())
 		}
-		if waitForDelete {
+		if waitForDelete && !u.NoWait {
 			key, err := client.ObjectKeyFromObject(obj)
 			if err != nil {
 				return fmt.Errorf("get %s key: %v", lowerKind, err)
@@ -27,13 +27,15 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/kubebuilder/cmdutil"
 	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds"
 	"github.com/operator-framework/operator-sdk/internal/plugins/manifests"
+	"github.com/operator-framework/operator-sdk/internal/plugins/util/crdvalidation"
 )
 
 const (
-	groupFlag      = "group"
-	versionFlag    = "version"
-	kindFlag       = "kind"
-	crdVersionFlag = "crd-version"
+	groupFlag              = "group"
+	versionFlag            = "version"
+	kindFlag               = "kind"
+	crdVersionFlag         = "crd-version"
+	crdValidationRulesFlag = "crd-validation-rules"
 
 	crdVersionV1      = "v1"
 	crdVersionV1beta1 = "v1beta1"
@@ -42,6 +44,9 @@ const (
 type createAPIPlugin struct {
 	config        *config.Config
 	createOptions scaffolds.CreateOptions
+
+	// validationRulesFile is the path to a file of CEL validation rules for the CRD.
+	validationRulesFile string
 }
 
 var (
@@ -96,6 +101,8 @@ func (p *createAPIPlugin) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&p.createOptions.GVK.Version, versionFlag, "", "resource version")
 	fs.StringVar(&p.createOptions.GVK.Kind, kindFlag, "", "resource kind")
 	fs.StringVar(&p.createOptions.CRDVersion, crdVersionFlag, crdVersionV1, "crd version to generate")
+	fs.StringVar(&p.validationRulesFile, crdValidationRulesFlag, "", "path to a YAML file containing a list of "+
+		"CEL validation rules to add to the CRD's spec schema as x-kubernetes-validations. Requires --"+crdVersionFlag+"=v1")
 	fs.BoolVarP(&p.createOptions.GeneratePlaybook, "generate-playbook", "", false, "Generate an Ansible playbook. If passed with --generate-role, the playbook will invoke the role.")
 	fs.BoolVarP(&p.createOptions.GenerateRole, "generate-role", "", false, "Generate an Ansible role skeleton.")
 }
@@ -128,6 +135,17 @@ func (p *createAPIPlugin) Validate() error {
 		return fmt.Errorf("value of --%s must be either %q or %q", crdVersionFlag, crdVersionV1, crdVersionV1beta1)
 	}
 
+	if p.validationRulesFile != "" {
+		if p.createOptions.CRDVersion != crdVersionV1 {
+			return fmt.Errorf("value of --%s can only be used with --%s=%s", crdValidationRulesFlag, crdVersionFlag, crdVersionV1)
+		}
+		rules, err := crdvalidation.ReadRules(p.validationRulesFile)
+		if err != nil {
+			return err
+		}
+		p.createOptions.ValidationRules = rules
+	}
+
 	if len(strings.TrimSpace(p.createOptions.GVK.Group)) == 0 {
 		return fmt.Errorf("value of --%s must not have empty value", groupFlag)
 	}
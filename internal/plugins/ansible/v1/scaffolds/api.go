@@ -36,6 +36,7 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/molecule/mdefault"
 	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/playbooks"
 	ansibleroles "github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/roles"
+	"github.com/operator-framework/operator-sdk/internal/plugins/util/crdvalidation"
 )
 
 var _ scaffold.Scaffolder = &apiScaffolder{}
@@ -43,7 +44,9 @@ var _ scaffold.Scaffolder = &apiScaffolder{}
 type CreateOptions struct {
 	GVK schema.GroupVersionKind
 	// CRDVersion is the version of the `apiextensions.k8s.io` API which will be used to generate the CRD.
-	CRDVersion       string
+	CRDVersion string
+	// ValidationRules are CEL validation rules added to the CRD's spec schema.
+	ValidationRules  []crdvalidation.Rule
 	GeneratePlaybook bool
 	GenerateRole     bool
 }
@@ -99,7 +102,7 @@ func (s *apiScaffolder) scaffold() error {
 		&rbac.CRDEditorRole{},
 		&rbac.ManagerRoleUpdater{},
 
-		&crd.CRD{CRDVersion: s.opts.CRDVersion},
+		&crd.CRD{CRDVersion: s.opts.CRDVersion, ValidationRules: s.opts.ValidationRules},
 		&crd.Kustomization{},
 		&samples.CR{},
 		&templates.WatchesUpdater{GeneratePlaybook: s.opts.GeneratePlaybook, GenerateRole: s.opts.GenerateRole, PlaybooksDir: constants.PlaybooksDir},
@@ -31,6 +31,7 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/config/testing/pullpolicy"
 	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/molecule/mdefault"
 	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/molecule/mkind"
+	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/molecule/mscorecard"
 	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/playbooks"
 	ansibleroles "github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/roles"
 
@@ -101,7 +102,7 @@ func (s *initScaffolder) scaffold() error {
 		&manager.Kustomization{},
 
 		&kdefault.Kustomize{},
-		&kdefault.AuthProxyPatch{},
+		&kdefault.MetricsPatch{},
 
 		&templates.Makefile{},
 		&ansibleroles.Placeholder{},
@@ -118,6 +119,9 @@ func (s *initScaffolder) scaffold() error {
 		&mkind.Create{},
 		&mkind.Destroy{},
 		&mkind.Molecule{},
+		&mscorecard.Molecule{},
+		&mscorecard.Entrypoint{},
+		&templates.ScorecardDockerfile{},
 		&pullpolicy.AlwaysPullPatch{},
 		&pullpolicy.IfNotPresentPullPatch{},
 		&pullpolicy.NeverPullPatch{},
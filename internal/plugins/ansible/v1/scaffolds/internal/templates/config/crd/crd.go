@@ -21,6 +21,8 @@ import (
 
 	"github.com/kr/text"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+
+	"github.com/operator-framework/operator-sdk/internal/plugins/util/crdvalidation"
 )
 
 var _ file.Template = &CRD{}
@@ -31,6 +33,9 @@ type CRD struct {
 	file.ResourceMixin
 
 	CRDVersion string
+
+	// ValidationRules are added to the spec schema as x-kubernetes-validations.
+	ValidationRules []crdvalidation.Rule
 }
 
 // SetTemplateDefaults implements input.Template
@@ -47,9 +52,18 @@ func (f *CRD) SetTemplateDefaults() error {
 	} else if f.CRDVersion != "v1" && f.CRDVersion != "v1beta1" {
 		return errors.New("the CRD version value must be either 'v1' or 'v1beta1'")
 	}
+	if len(f.ValidationRules) != 0 && f.CRDVersion != "v1" {
+		return errors.New("validation rules require CRD version 'v1'")
+	}
+	validations, err := crdvalidation.SchemaFragment(f.ValidationRules, "      ")
+	if err != nil {
+		return fmt.Errorf("error rendering validation rules: %v", err)
+	}
+	openAPIV3Schema := fmt.Sprintf(openAPIV3SchemaTemplate, validations)
+
 	f.TemplateBody = fmt.Sprintf(crdTemplate,
-		text.Indent(openAPIV3SchemaTemplate, "    "),
-		text.Indent(openAPIV3SchemaTemplate, "      "),
+		text.Indent(openAPIV3Schema, "    "),
+		text.Indent(openAPIV3Schema, "      "),
 	)
 	return nil
 }
@@ -87,6 +101,7 @@ spec:
 {{- end }}
 `
 
+// openAPIV3SchemaTemplate is formatted with the spec schema's x-kubernetes-validations, if any.
 const openAPIV3SchemaTemplate = `openAPIV3Schema:
   description: {{ .Resource.Kind }} is the Schema for the {{ .Resource.Plural }} API
   properties:
@@ -106,7 +121,7 @@ const openAPIV3SchemaTemplate = `openAPIV3Schema:
       description: Spec defines the desired state of {{ .Resource.Kind }}
       type: object
       x-kubernetes-preserve-unknown-fields: true
-    status:
+%s    status:
       description: Status defines the observed state of {{ .Resource.Kind }}
       type: object
       x-kubernetes-preserve-unknown-fields: true
@@ -66,8 +66,8 @@ bases:
 #- ../prometheus
 
 patchesStrategicMerge:
-  # Protect the /metrics endpoint by putting it behind auth.
+  # Protect the /metrics endpoint by serving it over HTTPS behind authn/z.
   # If you want your controller-manager to expose the /metrics
-  # endpoint w/o any authn/z, please comment the following line.
-- manager_auth_proxy_patch.yaml
+  # endpoint over HTTP w/o any authn/z, please comment the following line.
+- manager_metrics_patch.yaml
 `
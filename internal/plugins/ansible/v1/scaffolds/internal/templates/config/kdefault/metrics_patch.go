@@ -23,30 +23,30 @@ import (
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
 )
 
-var _ file.Template = &AuthProxyPatch{}
+var _ file.Template = &MetricsPatch{}
 
-// AuthProxyPatch scaffolds the patch file for enabling
-// prometheus metrics for manager Pod.
-type AuthProxyPatch struct {
+// MetricsPatch scaffolds the patch file for serving prometheus metrics
+// of the manager Pod securely.
+type MetricsPatch struct {
 	file.TemplateMixin
 	file.ProjectNameMixin
 }
 
 // SetTemplateDefaults implements input.Template
-func (f *AuthProxyPatch) SetTemplateDefaults() error {
+func (f *MetricsPatch) SetTemplateDefaults() error {
 	if f.Path == "" {
-		f.Path = filepath.Join("config", "default", "manager_auth_proxy_patch.yaml")
+		f.Path = filepath.Join("config", "default", "manager_metrics_patch.yaml")
 	}
 
-	f.TemplateBody = kustomizeAuthProxyPatchTemplate
+	f.TemplateBody = kustomizeMetricsPatchTemplate
 
 	f.IfExistsAction = file.Error
 
 	return nil
 }
 
-const kustomizeAuthProxyPatchTemplate = `# This patch inject a sidecar container which is a HTTP proxy for the
-# controller manager, it performs RBAC authorization against the Kubernetes API using SubjectAccessReviews.
+const kustomizeMetricsPatchTemplate = `# This patch serves the manager's /metrics endpoint over HTTPS, authenticating
+# and authorizing requests against the Kubernetes API with TokenReviews and SubjectAccessReviews.
 apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -56,19 +56,13 @@ spec:
   template:
     spec:
       containers:
-      - name: kube-rbac-proxy
-        image: gcr.io/kubebuilder/kube-rbac-proxy:v0.5.0
-        args:
-        - "--secure-listen-address=0.0.0.0:8443"
-        - "--upstream=http://127.0.0.1:8080/"
-        - "--logtostderr=true"
-        - "--v=10"
-        ports:
-        - containerPort: 8443
-          name: https
       - name: manager
         args:
-        - "--metrics-addr=127.0.0.1:8080"
+        - "--metrics-addr=:8443"
+        - "--metrics-require-rbac"
         - "--enable-leader-election"
         - "--leader-election-id={{ .ProjectName }}"
+        ports:
+        - containerPort: 8443
+          name: https
 `
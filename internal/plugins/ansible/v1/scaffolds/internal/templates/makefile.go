@@ -22,6 +22,7 @@ import (
 
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
 
+	"github.com/operator-framework/operator-sdk/internal/plugins/scorecard"
 	"github.com/operator-framework/operator-sdk/internal/version"
 )
 
@@ -30,10 +31,14 @@ var _ file.Template = &Makefile{}
 // Makefile scaffolds the Makefile
 type Makefile struct {
 	file.TemplateMixin
+	file.ProjectNameMixin
 
 	// Image is controller manager image name
 	Image string
 
+	// ScorecardTestImage is the molecule scorecard test image name
+	ScorecardTestImage string
+
 	// Kustomize version to use in the project
 	KustomizeVersion string
 
@@ -55,6 +60,10 @@ func (f *Makefile) SetTemplateDefaults() error {
 		f.Image = "controller:latest"
 	}
 
+	if f.ScorecardTestImage == "" {
+		f.ScorecardTestImage = scorecard.MoleculeTestImage(f.ProjectName)
+	}
+
 	if f.KustomizeVersion == "" {
 		f.KustomizeVersion = "v3.5.4"
 	}
@@ -69,6 +78,8 @@ func (f *Makefile) SetTemplateDefaults() error {
 const makefileTemplate = `
 # Image URL to use all building/pushing image targets
 IMG ?= {{ .Image }}
+# Scorecard test image URL, which must match the image in config/scorecard/patches/molecule.config.yaml
+SCORECARD_TEST_IMG ?= {{ .ScorecardTestImage }}
 
 all: docker-build
 
@@ -101,6 +112,14 @@ docker-build:
 docker-push:
 	docker push ${IMG}
 
+# Build the scorecard test image, which runs the molecule scorecard scenario
+scorecard-test-build:
+	docker build -f scorecard.Dockerfile -t ${SCORECARD_TEST_IMG} .
+
+# Push the scorecard test image
+scorecard-test-push:
+	docker push ${SCORECARD_TEST_IMG}
+
 PATH  := $(PATH):$(PWD)/bin
 SHELL := env PATH=$(PATH) /bin/sh
 OS    = $(shell uname -s | tr '[:upper:]' '[:lower:]')
@@ -0,0 +1,64 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mscorecard
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Entrypoint{}
+
+// Entrypoint scaffolds the scorecard test image's entrypoint, which runs the
+// scorecard scenario and reports its result to scorecard
+type Entrypoint struct {
+	file.TemplateMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Entrypoint) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("molecule", "scorecard", "entrypoint")
+	}
+	f.TemplateBody = entrypointTemplate
+	return nil
+}
+
+const entrypointTemplate = `#!/usr/bin/env python3
+"""Runs the molecule scorecard scenario and prints its result as a scorecard
+v1alpha3 TestStatus."""
+
+import json
+import subprocess
+
+proc = subprocess.run(
+    ["molecule", "verify", "--scenario-name", "scorecard"],
+    stdout=subprocess.PIPE,
+    stderr=subprocess.STDOUT,
+    universal_newlines=True,
+)
+
+result = {
+    "name": "molecule-verify",
+    "log": proc.stdout,
+    "state": "pass",
+}
+if proc.returncode != 0:
+    result["state"] = "fail"
+    result["errors"] = ["molecule verify exited with code {}".format(proc.returncode)]
+
+print(json.dumps({"results": [result]}, indent=4))
+`
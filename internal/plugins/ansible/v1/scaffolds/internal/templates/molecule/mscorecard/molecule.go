@@ -0,0 +1,64 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mscorecard
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Molecule{}
+
+// Molecule scaffolds a Molecule scenario that runs the default scenario's
+// verify playbook from the scorecard test image
+type Molecule struct {
+	file.TemplateMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Molecule) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("molecule", "scorecard", "molecule.yml")
+	}
+	f.TemplateBody = moleculeTemplate
+	return nil
+}
+
+// The operator is already deployed by scorecard, so the scenario only verifies
+// it, in the namespace scorecard runs its tests in and with the credentials of
+// the test pod's service account.
+const moleculeTemplate = `---
+driver:
+  name: delegated
+platforms:
+  - name: cluster
+    groups:
+      - k8s
+provisioner:
+  name: ansible
+  playbooks:
+    verify: ../default/verify.yml
+  inventory:
+    group_vars:
+      all:
+        namespace: ${SCORECARD_NAMESPACE:-osdk-test}
+    host_vars:
+      localhost:
+        ansible_python_interpreter: '{{ "{{ ansible_playbook_python }}" }}'
+        samples_dir: ${MOLECULE_PROJECT_DIRECTORY}/config/samples
+verifier:
+  name: ansible
+`
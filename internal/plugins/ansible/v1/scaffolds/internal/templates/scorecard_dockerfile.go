@@ -0,0 +1,61 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+
+	"github.com/operator-framework/operator-sdk/internal/version"
+)
+
+var _ file.Template = &ScorecardDockerfile{}
+
+// ScorecardDockerfile scaffolds a Dockerfile for building the scorecard test
+// image that runs the molecule scorecard scenario
+type ScorecardDockerfile struct {
+	file.TemplateMixin
+	ImageTag string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *ScorecardDockerfile) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = "scorecard.Dockerfile"
+	}
+
+	f.TemplateBody = scorecardDockerfileTemplate
+	f.ImageTag = strings.TrimSuffix(version.Version, "+git")
+	return nil
+}
+
+const scorecardDockerfileTemplate = `FROM quay.io/operator-framework/ansible-operator:{{.ImageTag}}
+
+USER root
+RUN pip3 install --no-cache-dir molecule
+USER ${USER_UID}
+
+COPY requirements.yml ${HOME}/requirements.yml
+RUN ansible-galaxy collection install -r ${HOME}/requirements.yml \
+ && chmod -R ug+rwx ${HOME}/.ansible
+
+ENV MOLECULE_PROJECT_DIRECTORY=${HOME}/project
+WORKDIR ${HOME}/project
+COPY molecule/ molecule/
+COPY config/samples/ config/samples/
+
+ENTRYPOINT ["python3", "molecule/scorecard/entrypoint"]
+`
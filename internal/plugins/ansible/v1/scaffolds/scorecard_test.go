@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Operator-SDK Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+
+	"github.com/operator-framework/operator-sdk/internal/kubebuilder/machinery"
+	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates"
+	"github.com/operator-framework/operator-sdk/internal/plugins/ansible/v1/scaffolds/internal/templates/molecule/mscorecard"
+	"github.com/operator-framework/operator-sdk/internal/version"
+)
+
+// captureFiles is a scaffold plugin that records the scaffolded files'
+// contents by path instead of writing them.
+type captureFiles map[string]string
+
+func (c captureFiles) Pipe(u *model.Universe) error {
+	for path, f := range u.Files {
+		c[path] = f.Contents
+	}
+	u.Files = nil
+	return nil
+}
+
+func TestScaffoldScorecardTestImage(t *testing.T) {
+	cfg := &config.Config{Version: config.Version3Alpha, ProjectName: "memcached-operator"}
+	files := captureFiles{}
+	err := machinery.NewScaffold(files).Execute(model.NewUniverse(model.WithConfig(cfg)), []file.Builder{
+		&templates.Makefile{},
+		&templates.ScorecardDockerfile{},
+		&mscorecard.Molecule{},
+		&mscorecard.Entrypoint{},
+	}...)
+	if err != nil {
+		t.Fatalf("Unexpected error scaffolding: %v", err)
+	}
+
+	imageTag := strings.TrimSuffix(version.Version, "+git")
+	testCases := []struct {
+		path             string
+		expectedContents []string
+	}{
+		{
+			path: "Makefile",
+			expectedContents: []string{
+				"SCORECARD_TEST_IMG ?= quay.io/example/memcached-operator-molecule-test:latest\n",
+				"docker build -f scorecard.Dockerfile -t ${SCORECARD_TEST_IMG} .\n",
+				"docker push ${SCORECARD_TEST_IMG}\n",
+			},
+		},
+		{
+			path: "scorecard.Dockerfile",
+			expectedContents: []string{
+				"FROM quay.io/operator-framework/ansible-operator:" + imageTag + "\n",
+				"COPY molecule/ molecule/\n",
+				"COPY config/samples/ config/samples/\n",
+				`ENTRYPOINT ["python3", "molecule/scorecard/entrypoint"]` + "\n",
+			},
+		},
+		{
+			path: "molecule/scorecard/molecule.yml",
+			expectedContents: []string{
+				"    verify: ../default/verify.yml\n",
+				"        namespace: ${SCORECARD_NAMESPACE:-osdk-test}\n",
+				"        ansible_python_interpreter: '{{ ansible_playbook_python }}'\n",
+			},
+		},
+		{
+			path: "molecule/scorecard/entrypoint",
+			expectedContents: []string{
+				`["molecule", "verify", "--scenario-name", "scorecard"]`,
+				`print(json.dumps({"results": [result]}, indent=4))`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		contents, ok := files[tc.path]
+		if !ok {
+			t.Errorf("%s was not scaffolded", tc.path)
+			continue
+		}
+		for _, expected := range tc.expectedContents {
+			if !strings.Contains(contents, expected) {
+				t.Errorf("%s does not contain %q:\n%s", tc.path, expected, contents)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+var _ = Describe("External plugins", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "external-plugins-")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	writeManifest := func(name, version, contents string) string {
+		manifestDir := filepath.Join(dir, name, version)
+		Expect(os.MkdirAll(manifestDir, 0755)).To(Succeed())
+		path := filepath.Join(manifestDir, ManifestFileName)
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+		return path
+	}
+
+	Describe("LoadManifest", func() {
+		It("loads a valid manifest and resolves a relative executable", func() {
+			path := writeManifest("java.example.com", "v1-alpha", `name: java.example.com
+version: v1-alpha
+supportedProjectVersions: ["3-alpha"]
+executable: ./java-plugin
+subcommands:
+- name: init
+  flags:
+  - name: package
+    usage: Java package
+- name: create api
+`)
+			m, err := LoadManifest(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Executable).To(Equal(filepath.Join(filepath.Dir(path), "java-plugin")))
+			Expect(m.getSubcommand(InitSubcommand)).NotTo(BeNil())
+			Expect(m.getSubcommand(CreateWebhookSubcommand)).To(BeNil())
+		})
+		It("rejects an unknown subcommand", func() {
+			path := writeManifest("java.example.com", "v1", `name: java.example.com
+version: v1
+supportedProjectVersions: ["3-alpha"]
+executable: ./java-plugin
+subcommands:
+- name: edit
+`)
+			_, err := LoadManifest(path)
+			Expect(err).To(HaveOccurred())
+		})
+		It("rejects a manifest with no executable", func() {
+			path := writeManifest("java.example.com", "v1", `name: java.example.com
+version: v1
+supportedProjectVersions: ["3-alpha"]
+`)
+			_, err := LoadManifest(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Discover", func() {
+		It("returns valid plugins and skips invalid ones", func() {
+			writeManifest("java.example.com", "v1", `name: java.example.com
+version: v1
+supportedProjectVersions: ["3-alpha"]
+executable: ./java-plugin
+`)
+			writeManifest("bad.example.com", "v1", `name: bad.example.com`)
+			plugins, err := Discover(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugins).To(HaveLen(1))
+			Expect(plugins[0].Name()).To(Equal("java.example.com"))
+		})
+		It("returns no plugins if the directory does not exist", func() {
+			plugins, err := Discover(filepath.Join(dir, "missing"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugins).To(BeEmpty())
+		})
+	})
+
+	Describe("WithoutCollisions", func() {
+		It("skips plugins with the keys of existing or earlier plugins", func() {
+			newTestPlugin := func(name, version string) plugin.Base {
+				return NewPlugin(Manifest{Name: name, Version: version})
+			}
+			existing := []plugin.Base{newTestPlugin("go.kubebuilder.io", "v2")}
+			plugins := WithoutCollisions(existing, []plugin.Base{
+				newTestPlugin("go.kubebuilder.io", "v2"),
+				newTestPlugin("java.example.com", "v1"),
+				newTestPlugin("java.example.com", "v1"),
+				newTestPlugin("java.example.com", "v2"),
+			})
+			Expect(plugins).To(HaveLen(2))
+			Expect(plugin.KeyFor(plugins[0])).To(Equal("java.example.com/v1"))
+			Expect(plugin.KeyFor(plugins[1])).To(Equal("java.example.com/v2"))
+		})
+	})
+
+	Describe("writeUniverse", func() {
+		It("writes files relative to the project root", func() {
+			Expect(writeUniverse(dir, map[string]string{"src/Main.java": "class Main {}"})).To(Succeed())
+			b, err := ioutil.ReadFile(filepath.Join(dir, "src", "Main.java"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal("class Main {}"))
+		})
+		It("rejects files outside of the project root", func() {
+			Expect(writeUniverse(dir, map[string]string{"../escape": ""})).NotTo(Succeed())
+			Expect(writeUniverse(dir, map[string]string{"/etc/passwd": ""})).NotTo(Succeed())
+		})
+	})
+})
@@ -0,0 +1,208 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ManifestFileName is the name of an external plugin's manifest file.
+	ManifestFileName = "plugin.yaml"
+
+	// PluginsDirEnv overrides the directory external plugins are discovered in.
+	PluginsDirEnv = "OPERATOR_SDK_PLUGINS_DIR"
+)
+
+// Subcommand names an external plugin can implement.
+const (
+	InitSubcommand          = "init"
+	CreateAPISubcommand     = "create api"
+	CreateWebhookSubcommand = "create webhook"
+)
+
+// Manifest declares an external plugin's identity, the executable that
+// implements it, and the subcommands and flags it supports.
+type Manifest struct {
+	// Name is the fully qualified plugin name, ex. "java.example.com".
+	Name string `json:"name"`
+	// Version is the plugin version, ex. "v1-alpha".
+	Version string `json:"version"`
+	// SupportedProjectVersions are the project config versions the plugin supports.
+	SupportedProjectVersions []string `json:"supportedProjectVersions"`
+	// Executable is the path to the plugin executable. Relative paths are
+	// relative to the directory containing the manifest.
+	Executable string `json:"executable"`
+	// Subcommands are the subcommands the plugin implements.
+	Subcommands []SubcommandManifest `json:"subcommands"`
+}
+
+// SubcommandManifest declares a subcommand implemented by an external plugin.
+type SubcommandManifest struct {
+	// Name is one of "init", "create api", or "create webhook".
+	Name string `json:"name"`
+	// Description is shown in the subcommand's help text.
+	Description string `json:"description,omitempty"`
+	// Examples are shown in the subcommand's help text.
+	Examples string `json:"examples,omitempty"`
+	// Flags are bound to the subcommand and passed to the executable.
+	Flags []FlagManifest `json:"flags,omitempty"`
+}
+
+// FlagManifest declares a flag accepted by an external plugin subcommand.
+type FlagManifest struct {
+	Name    string `json:"name"`
+	Type    string `json:"type,omitempty"`
+	Default string `json:"default,omitempty"`
+	Usage   string `json:"usage,omitempty"`
+}
+
+// Flag types.
+const (
+	flagTypeString = "string"
+	flagTypeBool   = "bool"
+)
+
+// LoadManifest reads and validates the manifest at path.
+func LoadManifest(path string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err := yaml.UnmarshalStrict(b, m); err != nil {
+		return nil, fmt.Errorf("error parsing plugin manifest %s: %v", path, err)
+	}
+	if m.Executable != "" && !filepath.IsAbs(m.Executable) {
+		m.Executable = filepath.Join(filepath.Dir(path), m.Executable)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid plugin manifest %s: %v", path, err)
+	}
+	return m, nil
+}
+
+// validate returns an error if m is not a valid manifest.
+func (m Manifest) validate() error {
+	if errs := validation.IsDNS1123Subdomain(m.Name); len(errs) != 0 {
+		return fmt.Errorf("invalid name %q: %v", m.Name, errs)
+	}
+	if _, err := plugin.ParseVersion(m.Version); err != nil {
+		return fmt.Errorf("invalid version %q: %v", m.Version, err)
+	}
+	if len(m.SupportedProjectVersions) == 0 {
+		return errors.New("supportedProjectVersions must be set")
+	}
+	if m.Executable == "" {
+		return errors.New("executable must be set")
+	}
+	seen := map[string]bool{}
+	for _, sub := range m.Subcommands {
+		switch sub.Name {
+		case InitSubcommand, CreateAPISubcommand, CreateWebhookSubcommand:
+		default:
+			return fmt.Errorf("unknown subcommand %q", sub.Name)
+		}
+		if seen[sub.Name] {
+			return fmt.Errorf("duplicate subcommand %q", sub.Name)
+		}
+		seen[sub.Name] = true
+		for _, f := range sub.Flags {
+			if f.Name == "" {
+				return fmt.Errorf("subcommand %q has a flag with no name", sub.Name)
+			}
+			switch f.Type {
+			case "", flagTypeString, flagTypeBool:
+			default:
+				return fmt.Errorf("subcommand %q flag %q has unknown type %q", sub.Name, f.Name, f.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// getSubcommand returns the manifest for subcommand name, or nil if m does not implement it.
+func (m Manifest) getSubcommand(name string) *SubcommandManifest {
+	for i, sub := range m.Subcommands {
+		if sub.Name == name {
+			return &m.Subcommands[i]
+		}
+	}
+	return nil
+}
+
+// DefaultPluginsDir returns the directory external plugins are discovered in:
+// $OPERATOR_SDK_PLUGINS_DIR if set, otherwise <user config dir>/operator-sdk/plugins.
+// An empty directory disables discovery.
+func DefaultPluginsDir() (string, error) {
+	if dir, ok := os.LookupEnv(PluginsDirEnv); ok {
+		return dir, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "operator-sdk", "plugins"), nil
+}
+
+// Discover returns a Plugin for each manifest found at <dir>/<name>/<version>/plugin.yaml.
+// Invalid manifests are logged and skipped. If dir does not exist, no plugins are returned.
+func Discover(dir string) ([]plugin.Base, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*", ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var plugins []plugin.Base
+	for _, path := range paths {
+		m, err := LoadManifest(path)
+		if err != nil {
+			log.Warnf("Skipping external plugin: %v", err)
+			continue
+		}
+		plugins = append(plugins, NewPlugin(*m))
+	}
+	return plugins, nil
+}
+
+// WithoutCollisions returns the plugins whose keys collide with neither a
+// plugin in existing nor an earlier plugin in plugins. Each skipped plugin is
+// logged.
+func WithoutCollisions(existing, plugins []plugin.Base) []plugin.Base {
+	keys := map[string]bool{}
+	for _, p := range existing {
+		keys[plugin.KeyFor(p)] = true
+	}
+	var out []plugin.Base
+	for _, p := range plugins {
+		key := plugin.KeyFor(p)
+		if keys[key] {
+			log.Warnf("Skipping external plugin %s: a plugin with the same name and version is already registered", key)
+			continue
+		}
+		keys[key] = true
+		out = append(out, p)
+	}
+	return out
+}
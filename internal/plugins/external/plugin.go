@@ -0,0 +1,165 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package external implements operator-sdk plugins backed by external
+// executables, allowing operators written in other languages to hook into
+// `init`, `create api`, and `create webhook` without forking the SDK.
+//
+// An external plugin is declared by a plugin.yaml manifest at
+// <plugins dir>/<name>/<version>/plugin.yaml. When one of its subcommands is
+// run, the SDK writes a JSON PluginRequest to the executable's stdin and reads
+// a JSON PluginResponse from its stdout, then writes the response's files to
+// the project.
+package external
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+var (
+	_ plugin.Base                      = Plugin{}
+	_ plugin.InitPluginGetter          = Plugin{}
+	_ plugin.CreateAPIPluginGetter     = Plugin{}
+	_ plugin.CreateWebhookPluginGetter = Plugin{}
+)
+
+// Plugin is a plugin implemented by an external executable.
+type Plugin struct {
+	manifest Manifest
+	version  plugin.Version
+}
+
+// NewPlugin returns a Plugin for a validated manifest m.
+func NewPlugin(m Manifest) Plugin {
+	// m has been validated, so its version parses.
+	v, _ := plugin.ParseVersion(m.Version)
+	return Plugin{manifest: m, version: v}
+}
+
+func (p Plugin) Name() string                       { return p.manifest.Name }
+func (p Plugin) Version() plugin.Version            { return p.version }
+func (p Plugin) SupportedProjectVersions() []string { return p.manifest.SupportedProjectVersions }
+
+func (p Plugin) GetInitPlugin() plugin.Init {
+	return &subcommand{plugin: p, name: InitSubcommand}
+}
+
+func (p Plugin) GetCreateAPIPlugin() plugin.CreateAPI {
+	return &subcommand{plugin: p, name: CreateAPISubcommand}
+}
+
+func (p Plugin) GetCreateWebhookPlugin() plugin.CreateWebhook {
+	return &subcommand{plugin: p, name: CreateWebhookSubcommand}
+}
+
+// subcommand runs one of an external plugin's subcommands.
+type subcommand struct {
+	plugin Plugin
+	name   string
+	config *config.Config
+
+	// Flag values bound from the subcommand's manifest, keyed by flag name.
+	stringFlags map[string]*string
+	boolFlags   map[string]*bool
+}
+
+var (
+	_ plugin.Init          = &subcommand{}
+	_ plugin.CreateAPI     = &subcommand{}
+	_ plugin.CreateWebhook = &subcommand{}
+)
+
+func (s *subcommand) UpdateContext(ctx *plugin.Context) {
+	sub := s.plugin.manifest.getSubcommand(s.name)
+	if sub == nil {
+		ctx.Description = fmt.Sprintf("Plugin %s does not implement %q.\n", plugin.KeyFor(s.plugin), s.name)
+		return
+	}
+	ctx.Description = sub.Description
+	ctx.Examples = sub.Examples
+}
+
+func (s *subcommand) BindFlags(fs *pflag.FlagSet) {
+	s.stringFlags = map[string]*string{}
+	s.boolFlags = map[string]*bool{}
+
+	sub := s.plugin.manifest.getSubcommand(s.name)
+	if sub == nil {
+		return
+	}
+	fs.SortFlags = false
+	for _, f := range sub.Flags {
+		switch f.Type {
+		case flagTypeBool:
+			def, _ := strconv.ParseBool(f.Default)
+			s.boolFlags[f.Name] = fs.Bool(f.Name, def, f.Usage)
+		default:
+			s.stringFlags[f.Name] = fs.String(f.Name, f.Default, f.Usage)
+		}
+	}
+}
+
+func (s *subcommand) InjectConfig(c *config.Config) {
+	if s.name == InitSubcommand {
+		c.Layout = plugin.KeyFor(s.plugin)
+	}
+	s.config = c
+}
+
+func (s *subcommand) Run() error {
+	if s.plugin.manifest.getSubcommand(s.name) == nil {
+		return fmt.Errorf("plugin %s does not implement %q", plugin.KeyFor(s.plugin), s.name)
+	}
+
+	cfgBytes, err := s.config.Marshal()
+	if err != nil {
+		return fmt.Errorf("error marshaling project config: %v", err)
+	}
+
+	req := PluginRequest{
+		APIVersion: ProtocolVersion,
+		Command:    s.name,
+		Args:       s.args(),
+		Config:     string(cfgBytes),
+	}
+	resp, err := runExecutable(s.plugin.manifest.Executable, req)
+	if err != nil {
+		return err
+	}
+
+	rootDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %v", err)
+	}
+	return writeUniverse(rootDir, resp.Universe)
+}
+
+// args returns bound flag values as "--name=value" strings in manifest order.
+func (s *subcommand) args() (args []string) {
+	sub := s.plugin.manifest.getSubcommand(s.name)
+	for _, f := range sub.Flags {
+		if v, ok := s.boolFlags[f.Name]; ok {
+			args = append(args, fmt.Sprintf("--%s=%t", f.Name, *v))
+		} else if v, ok := s.stringFlags[f.Name]; ok {
+			args = append(args, fmt.Sprintf("--%s=%s", f.Name, *v))
+		}
+	}
+	return args
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package external
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ProtocolVersion is the version of the request/response protocol spoken
+// with external plugin executables.
+const ProtocolVersion = "v1alpha1"
+
+// PluginRequest is written as JSON to an external plugin executable's stdin.
+type PluginRequest struct {
+	// APIVersion is the protocol version.
+	APIVersion string `json:"apiVersion"`
+	// Command is the subcommand being run, ex. "create api".
+	Command string `json:"command"`
+	// Args are the subcommand's flags as "--name=value" strings.
+	Args []string `json:"args"`
+	// Config is the project's PROJECT file contents.
+	Config string `json:"config"`
+}
+
+// PluginResponse is read as JSON from an external plugin executable's stdout.
+type PluginResponse struct {
+	// APIVersion is the protocol version.
+	APIVersion string `json:"apiVersion"`
+	// Command is the subcommand that was run.
+	Command string `json:"command"`
+	// Universe maps file paths, relative to the project root, to the file
+	// contents the plugin scaffolded.
+	Universe map[string]string `json:"universe,omitempty"`
+	// Error is true if the plugin failed.
+	Error bool `json:"error,omitempty"`
+	// ErrorMsgs describe why the plugin failed.
+	ErrorMsgs []string `json:"errorMsgs,omitempty"`
+}
+
+// runExecutable sends req to the executable at path and returns its response.
+// The executable's stderr is passed through to the user.
+func runExecutable(path string, req PluginRequest) (*PluginResponse, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &bytes.Buffer{}
+	cmd := exec.Command(path)
+	cmd.Stdin = bytes.NewReader(reqBytes)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error running plugin executable %s: %v", path, err)
+	}
+
+	resp := &PluginResponse{}
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return nil, fmt.Errorf("error parsing response from plugin executable %s: %v", path, err)
+	}
+	if resp.APIVersion != ProtocolVersion {
+		return nil, fmt.Errorf("plugin executable %s responded with unsupported apiVersion %q", path, resp.APIVersion)
+	}
+	if resp.Error {
+		return nil, fmt.Errorf("plugin executable %s failed: %s", path, strings.Join(resp.ErrorMsgs, "; "))
+	}
+	return resp, nil
+}
+
+// writeUniverse writes each file in universe relative to rootDir. Paths that
+// are absolute or escape rootDir are rejected before any file is written.
+func writeUniverse(rootDir string, universe map[string]string) error {
+	for path := range universe {
+		clean := filepath.Clean(filepath.FromSlash(path))
+		if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("plugin scaffolded file %q outside of the project", path)
+		}
+	}
+	if len(universe) != 0 && rootDir == "" {
+		return errors.New("project root directory must be set")
+	}
+
+	for path, contents := range universe {
+		fullPath := filepath.Join(rootDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(fullPath, []byte(contents), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -15,6 +15,8 @@
 package v2
 
 import (
+	"fmt"
+
 	"github.com/spf13/pflag"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
@@ -26,12 +28,32 @@ type createAPIPlugin struct {
 	plugin.CreateAPI
 
 	config *config.Config
+
+	// deprecated is true if the API version should be marked as deprecated in its CRD.
+	deprecated bool
+	// deprecationWarning is returned to API clients that use a deprecated API version.
+	deprecationWarning string
+	// kubectlPlugin is true if a kubectl plugin for the API's kind should be scaffolded.
+	kubectlPlugin bool
+	// gvkFlags are the group, version, and kind flags bound by the wrapped plugin,
+	// used to find the API version to deprecate or scaffold a kubectl plugin for when no
+	// resource is scaffolded.
+	gvkFlags [3]*pflag.Flag
 }
 
 var _ plugin.CreateAPI = &createAPIPlugin{}
 
 func (p *createAPIPlugin) UpdateContext(ctx *plugin.Context) { p.CreateAPI.UpdateContext(ctx) }
-func (p *createAPIPlugin) BindFlags(fs *pflag.FlagSet)       { p.CreateAPI.BindFlags(fs) }
+
+func (p *createAPIPlugin) BindFlags(fs *pflag.FlagSet) {
+	p.CreateAPI.BindFlags(fs)
+	fs.BoolVar(&p.deprecated, "deprecated", false, "mark the API version as deprecated in its CRD")
+	fs.StringVar(&p.deprecationWarning, "deprecation-warning", "", "warning returned to API clients "+
+		"that use the deprecated API version. Requires --deprecated")
+	fs.BoolVar(&p.kubectlPlugin, "kubectl-plugin", false, "scaffold a kubectl plugin for the kind in "+
+		"cmd/kubectl-<kind> with get, status, and trigger-reconcile subcommands")
+	p.gvkFlags = [3]*pflag.Flag{fs.Lookup("group"), fs.Lookup("version"), fs.Lookup("kind")}
+}
 
 func (p *createAPIPlugin) InjectConfig(c *config.Config) {
 	p.CreateAPI.InjectConfig(c)
@@ -39,6 +61,10 @@ func (p *createAPIPlugin) InjectConfig(c *config.Config) {
 }
 
 func (p *createAPIPlugin) Run() error {
+	if p.deprecationWarning != "" && !p.deprecated {
+		return fmt.Errorf("--deprecation-warning requires --deprecated")
+	}
+
 	// Run() may add a new resource to the config, so we can compare resources before/after to get the new resource.
 	oldResources := make(map[config.GVK]struct{}, len(p.config.Resources))
 	for _, r := range p.config.Resources {
@@ -48,11 +74,6 @@ func (p *createAPIPlugin) Run() error {
 		return err
 	}
 
-	// Emulate plugins phase 2 behavior by checking the config for this plugin's config object.
-	if !hasPluginConfig(p.config) {
-		return nil
-	}
-
 	// Find the new resource. Here we shouldn't worry about checking if one was found,
 	// since downstream plugins will do so.
 	var newResource config.GVK
@@ -63,11 +84,57 @@ func (p *createAPIPlugin) Run() error {
 		}
 	}
 
-	// Run SDK phase 2 plugins.
-	return p.runPhase2(newResource)
+	// Emulate plugins phase 2 behavior by checking the config for this plugin's config object.
+	if hasPluginConfig(p.config) {
+		// Run SDK phase 2 plugins.
+		if err := p.runPhase2(newResource); err != nil {
+			return err
+		}
+	}
+
+	// An existing API version can be deprecated or get a kubectl plugin with --resource=false.
+	gvk := newResource
+	if gvk.Kind == "" {
+		gvk = p.flagGVK()
+	}
+	if p.deprecated {
+		if err := markDeprecatedVersion(p.config, gvk, p.deprecationWarning); err != nil {
+			return err
+		}
+	}
+	if p.kubectlPlugin {
+		if err := scaffoldKubectlPlugin(p.config, gvk); err != nil {
+			return fmt.Errorf("error scaffolding kubectl plugin: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// flagGVK returns the GVK passed to the wrapped plugin's flags.
+func (p *createAPIPlugin) flagGVK() (gvk config.GVK) {
+	values := [3]string{}
+	for i, f := range p.gvkFlags {
+		if f != nil {
+			values[i] = f.Value.String()
+		}
+	}
+	gvk.Group, gvk.Version, gvk.Kind = values[0], values[1], values[2]
+	return gvk
 }
 
 // SDK phase 2 plugins.
 func (p *createAPIPlugin) runPhase2(gvk config.GVK) error {
-	return manifests.RunCreateAPI(p.config, gvk)
+	if err := manifests.RunCreateAPI(p.config, gvk); err != nil {
+		return err
+	}
+
+	pluginCfg, err := getPluginConfig(p.config)
+	if err != nil {
+		return err
+	}
+	if pluginCfg.GenerateClients {
+		return addClientMarkers(p.config, gvk)
+	}
+	return nil
 }
@@ -0,0 +1,167 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+const (
+	// genclientMarker marks an API type for code-generator's client, lister, and informer generators.
+	genclientMarker = "// +genclient"
+	// genclientNonNamespacedMarker marks a cluster-scoped API type for code-generator.
+	genclientNonNamespacedMarker = "// +genclient:nonNamespaced"
+	// kbRootMarker precedes kind types scaffolded by kubebuilder.
+	kbRootMarker = "// +kubebuilder:object:root=true"
+)
+
+// initUpdateMakefileClients appends the generate-clients recipe to the Makefile at filePath.
+func initUpdateMakefileClients(cfg *config.Config, filePath string) error {
+	makefileBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	makefileBytes = append(makefileBytes, []byte(fmt.Sprintf(makefileGenerateClientsFragment, cfg.Repo))...)
+	return ioutil.WriteFile(filePath, makefileBytes, 0644)
+}
+
+// addClientMarkers marks gvk's type with +genclient and adds the declarations
+// code-generator expects to gvk's group-version package, so `make generate-clients`
+// generates a typed client for it.
+func addClientMarkers(cfg *config.Config, gvk config.GVK) error {
+	apiDir := filepath.Join("api", gvk.Version)
+	if cfg.MultiGroup {
+		apiDir = filepath.Join("apis", gvk.Group, gvk.Version)
+	}
+
+	typesFile := filepath.Join(apiDir, strings.ToLower(gvk.Kind)+"_types.go")
+	if err := addGenclientMarker(typesFile, gvk.Kind); err != nil {
+		return fmt.Errorf("error adding client markers to %s: %v", typesFile, err)
+	}
+	gvFile := filepath.Join(apiDir, "groupversion_info.go")
+	if err := addSchemeGroupVersion(gvFile); err != nil {
+		return fmt.Errorf("error updating %s: %v", gvFile, err)
+	}
+	return nil
+}
+
+// clusterScopeMarkerRe matches a kubebuilder marker that makes a kind cluster-scoped.
+var clusterScopeMarkerRe = regexp.MustCompile(`(?m)^//\s*\+kubebuilder:resource:(.*,)?scope=Cluster(,|\s*$)`)
+
+// addGenclientMarker inserts genclientMarker above the kubebuilder markers of
+// kind's type declaration in the Go file at filePath, followed by
+// genclientNonNamespacedMarker if kind is marked cluster-scoped.
+func addGenclientMarker(filePath, kind string) error {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	contents := string(b)
+
+	typeIdx := strings.Index(contents, fmt.Sprintf("\ntype %s struct {", kind))
+	if typeIdx < 0 {
+		return fmt.Errorf("type %s not found", kind)
+	}
+	markerIdx := strings.LastIndex(contents[:typeIdx], kbRootMarker)
+	if markerIdx < 0 {
+		return fmt.Errorf("marker %q not found above type %s", kbRootMarker, kind)
+	}
+
+	// Only the markers between the previous declaration and kind's type apply to it.
+	declStart := strings.LastIndex(contents[:typeIdx], "\n}\n") + 1
+	markers := genclientMarker + "\n"
+	if clusterScopeMarkerRe.MatchString(contents[declStart:typeIdx]) {
+		markers += genclientNonNamespacedMarker + "\n"
+	}
+	before := contents[:markerIdx]
+	if strings.HasSuffix(before, markers) {
+		return nil
+	}
+	before = strings.TrimSuffix(before, genclientMarker+"\n")
+	contents = before + markers + contents[markerIdx:]
+	return ioutil.WriteFile(filePath, []byte(contents), 0644)
+}
+
+// addSchemeGroupVersion appends the SchemeGroupVersion and Resource declarations
+// generated clients and listers reference to the groupversion_info.go file at
+// filePath, if not already present.
+func addSchemeGroupVersion(filePath string) error {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(string(b), "SchemeGroupVersion") {
+		return nil
+	}
+	b = append(b, []byte(schemeGroupVersionFragment)...)
+	return ioutil.WriteFile(filePath, b, 0644)
+}
+
+const schemeGroupVersionFragment = `
+// SchemeGroupVersion is group version used to register these objects. It is
+// referenced by clients generated with 'make generate-clients'.
+var SchemeGroupVersion = GroupVersion
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource.
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+`
+
+// makefileGenerateClientsFragment is formatted with the project's repo.
+const makefileGenerateClientsFragment = `
+# Generate a typed clientset, listers, and informers for the project's APIs under pkg/client.
+CODE_GENERATOR_VERSION ?= v0.18.8
+CLIENT_REPO ?= %s
+CLIENT_PACKAGE ?= $(CLIENT_REPO)/pkg/client
+CLIENT_API_PACKAGES ?= $(shell go list ./api/... ./apis/... 2>/dev/null | paste -sd, -)
+.PHONY: generate-clients
+generate-clients: code-generator
+	$(eval CLIENT_OUT := $(shell mktemp -d))
+	$(CODE_GENERATOR_BIN)/client-gen --go-header-file hack/boilerplate.go.txt --output-base $(CLIENT_OUT) \
+		--input-base "" --input $(CLIENT_API_PACKAGES) \
+		--clientset-name versioned --output-package $(CLIENT_PACKAGE)/clientset
+	$(CODE_GENERATOR_BIN)/lister-gen --go-header-file hack/boilerplate.go.txt --output-base $(CLIENT_OUT) \
+		--input-dirs $(CLIENT_API_PACKAGES) --output-package $(CLIENT_PACKAGE)/listers
+	$(CODE_GENERATOR_BIN)/informer-gen --go-header-file hack/boilerplate.go.txt --output-base $(CLIENT_OUT) \
+		--input-dirs $(CLIENT_API_PACKAGES) --output-package $(CLIENT_PACKAGE)/informers \
+		--versioned-clientset-package $(CLIENT_PACKAGE)/clientset/versioned \
+		--listers-package $(CLIENT_PACKAGE)/listers
+	rm -rf pkg/client && mkdir -p pkg
+	cp -r $(CLIENT_OUT)/$(CLIENT_PACKAGE) pkg/client
+	rm -rf $(CLIENT_OUT)
+
+# Install code-generator's client-gen, lister-gen, and informer-gen to CODE_GENERATOR_BIN.
+CODE_GENERATOR_BIN ?= $(shell pwd)/bin
+.PHONY: code-generator
+code-generator:
+	@{ \
+	set -e ;\
+	CODE_GENERATOR_TMP_DIR=$$(mktemp -d) ;\
+	cd $$CODE_GENERATOR_TMP_DIR ;\
+	go mod init tmp ;\
+	GOBIN=$(CODE_GENERATOR_BIN) go get \
+		k8s.io/code-generator/cmd/client-gen@$(CODE_GENERATOR_VERSION) \
+		k8s.io/code-generator/cmd/lister-gen@$(CODE_GENERATOR_VERSION) \
+		k8s.io/code-generator/cmd/informer-gen@$(CODE_GENERATOR_VERSION) ;\
+	rm -rf $$CODE_GENERATOR_TMP_DIR ;\
+	}
+`
@@ -0,0 +1,178 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const memcachedTypes = `package v1alpha1
+
+// MemcachedSpec defines the desired state of Memcached
+type MemcachedSpec struct {
+	Size int32 ` + "`json:\"size\"`" + `
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// Memcached is the Schema for the memcacheds API
+type Memcached struct {
+	Spec MemcachedSpec ` + "`json:\"spec,omitempty\"`" + `
+}
+
+// +kubebuilder:object:root=true
+
+// MemcachedList contains a list of Memcached
+type MemcachedList struct {
+	Items []Memcached ` + "`json:\"items\"`" + `
+}
+`
+
+const clusterTypes = `package v1alpha1
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=memcachedpools,scope=Cluster
+
+// MemcachedPool is the Schema for the memcachedpools API
+type MemcachedPool struct {
+	Spec MemcachedPoolSpec ` + "`json:\"spec,omitempty\"`" + `
+}
+
+// +kubebuilder:object:root=true
+
+// MemcachedPoolList contains a list of MemcachedPool
+type MemcachedPoolList struct {
+	Items []MemcachedPool ` + "`json:\"items\"`" + `
+}
+`
+
+const memcachedGroupVersionInfo = `package v1alpha1
+
+var (
+	// GroupVersion is group version used to register these objects
+	GroupVersion = schema.GroupVersion{Group: "cache.example.com", Version: "v1alpha1"}
+)
+`
+
+var _ = Describe("Client markers", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "clients-")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	readFile := func(path string) string {
+		b, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	Describe("addGenclientMarker", func() {
+		var typesFile string
+
+		BeforeEach(func() {
+			typesFile = filepath.Join(dir, "memcached_types.go")
+			Expect(ioutil.WriteFile(typesFile, []byte(memcachedTypes), 0644)).To(Succeed())
+		})
+
+		It("marks only the kind's type in a fresh file", func() {
+			Expect(addGenclientMarker(typesFile, "Memcached")).To(Succeed())
+			contents := readFile(typesFile)
+			Expect(contents).To(ContainSubstring("// +genclient\n// +kubebuilder:object:root=true\n" +
+				"// +kubebuilder:subresource:status\n\n// Memcached is"))
+			Expect(strings.Count(contents, genclientMarker)).To(Equal(1))
+		})
+
+		It("does not mark an already spliced file again", func() {
+			Expect(addGenclientMarker(typesFile, "Memcached")).To(Succeed())
+			spliced := readFile(typesFile)
+			Expect(addGenclientMarker(typesFile, "Memcached")).To(Succeed())
+			Expect(readFile(typesFile)).To(Equal(spliced))
+		})
+
+		It("fails if the kind's type does not exist", func() {
+			Expect(addGenclientMarker(typesFile, "Nginx")).NotTo(Succeed())
+		})
+
+		It("does not mark a namespaced kind nonNamespaced", func() {
+			Expect(addGenclientMarker(typesFile, "Memcached")).To(Succeed())
+			Expect(readFile(typesFile)).NotTo(ContainSubstring(genclientNonNamespacedMarker))
+		})
+
+		Context("with a cluster-scoped kind", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(typesFile, []byte(clusterTypes), 0644)).To(Succeed())
+			})
+
+			It("marks the kind's type nonNamespaced", func() {
+				Expect(addGenclientMarker(typesFile, "MemcachedPool")).To(Succeed())
+				contents := readFile(typesFile)
+				Expect(contents).To(ContainSubstring("// +genclient\n// +genclient:nonNamespaced\n" +
+					"// +kubebuilder:object:root=true\n// +kubebuilder:resource:path=memcachedpools,scope=Cluster\n"))
+				Expect(strings.Count(contents, genclientNonNamespacedMarker)).To(Equal(1))
+			})
+
+			It("does not mark an already spliced file again", func() {
+				Expect(addGenclientMarker(typesFile, "MemcachedPool")).To(Succeed())
+				spliced := readFile(typesFile)
+				Expect(addGenclientMarker(typesFile, "MemcachedPool")).To(Succeed())
+				Expect(readFile(typesFile)).To(Equal(spliced))
+			})
+
+			It("adds the nonNamespaced marker to a kind already marked +genclient", func() {
+				marked := strings.Replace(clusterTypes, kbRootMarker, genclientMarker+"\n"+kbRootMarker, 1)
+				Expect(ioutil.WriteFile(typesFile, []byte(marked), 0644)).To(Succeed())
+				Expect(addGenclientMarker(typesFile, "MemcachedPool")).To(Succeed())
+				contents := readFile(typesFile)
+				Expect(contents).To(ContainSubstring("// +genclient\n// +genclient:nonNamespaced\n" +
+					"// +kubebuilder:object:root=true\n"))
+				Expect(strings.Count(contents, genclientMarker+"\n")).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("addSchemeGroupVersion", func() {
+		var gvFile string
+
+		BeforeEach(func() {
+			gvFile = filepath.Join(dir, "groupversion_info.go")
+			Expect(ioutil.WriteFile(gvFile, []byte(memcachedGroupVersionInfo), 0644)).To(Succeed())
+		})
+
+		It("appends the declarations to a fresh file", func() {
+			Expect(addSchemeGroupVersion(gvFile)).To(Succeed())
+			Expect(readFile(gvFile)).To(Equal(memcachedGroupVersionInfo + schemeGroupVersionFragment))
+		})
+
+		It("does not append the declarations to an already spliced file again", func() {
+			Expect(addSchemeGroupVersion(gvFile)).To(Succeed())
+			Expect(addSchemeGroupVersion(gvFile)).To(Succeed())
+			Expect(readFile(gvFile)).To(Equal(memcachedGroupVersionInfo + schemeGroupVersionFragment))
+		})
+	})
+})
@@ -14,10 +14,17 @@
 
 package v2
 
-import "sigs.k8s.io/kubebuilder/pkg/model/config"
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
 
 // Config configures this plugin, and is saved in the project config file.
-type Config struct{}
+type Config struct {
+	// GenerateClients is true if typed clients are generated for project APIs.
+	GenerateClients bool `json:"generateClients,omitempty"`
+}
 
 // hasPluginConfig returns true if cfg.Plugins contains an exact match for this plugin's key.
 func hasPluginConfig(cfg *config.Config) bool {
@@ -27,3 +34,12 @@ func hasPluginConfig(cfg *config.Config) bool {
 	_, hasKey := cfg.Plugins[pluginConfigKey]
 	return hasKey
 }
+
+// getPluginConfig returns this plugin's config object from cfg.
+func getPluginConfig(cfg *config.Config) (Config, error) {
+	pluginCfg := Config{}
+	if err := cfg.DecodePluginConfig(pluginConfigKey, &pluginCfg); err != nil {
+		return pluginCfg, fmt.Errorf("error reading plugin config for %s: %v", pluginConfigKey, err)
+	}
+	return pluginCfg, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+// deprecatedVersionMarker marks an API version as deprecated in its CRD's versions list.
+// controller-gen sets "deprecated: true" and, if present, "deprecationWarning" for that version.
+const deprecatedVersionMarker = "// +kubebuilder:deprecatedversion"
+
+// minControllerGenVersion is the first controller-gen version that supports
+// deprecatedVersionMarker. Older versions ignore it.
+var minControllerGenVersion = semver.MustParse("0.4.1")
+
+// controllerGenVersionPattern matches the controller-gen version installed by
+// the project Makefile's controller-gen recipe, capturing the version.
+var controllerGenVersionPattern = regexp.MustCompile(
+	`sigs\.k8s\.io/controller-tools/cmd/controller-gen@v([0-9A-Za-z.+-]+)`)
+
+// markDeprecatedVersion adds deprecatedVersionMarker with an optional warning to gvk's
+// type, bumps the Makefile's controller-gen to a version that supports the marker, then
+// points gvk's sample in config/samples/kustomization.yaml to the newest version of the
+// same kind, if one exists.
+func markDeprecatedVersion(cfg *config.Config, gvk config.GVK, warning string) error {
+	apiDir := filepath.Join("api", gvk.Version)
+	if cfg.MultiGroup {
+		apiDir = filepath.Join("apis", gvk.Group, gvk.Version)
+	}
+
+	typesFile := filepath.Join(apiDir, strings.ToLower(gvk.Kind)+"_types.go")
+	if err := addDeprecatedVersionMarker(typesFile, gvk.Kind, warning); err != nil {
+		return fmt.Errorf("error adding deprecation marker to %s: %v", typesFile, err)
+	}
+	if err := bumpControllerGen("Makefile"); err != nil {
+		return fmt.Errorf("error updating Makefile: %v", err)
+	}
+
+	newest, hasNewer := newestVersion(cfg, gvk)
+	if !hasNewer || !cfg.IsV3() {
+		return nil
+	}
+	samplesFile := filepath.Join("config", "samples", "kustomization.yaml")
+	if err := replaceSample(samplesFile, gvk, newest); err != nil {
+		return fmt.Errorf("error updating %s: %v", samplesFile, err)
+	}
+	return nil
+}
+
+// addDeprecatedVersionMarker inserts deprecatedVersionMarker above the kubebuilder
+// markers of kind's type declaration in the Go file at filePath.
+func addDeprecatedVersionMarker(filePath, kind, warning string) error {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	contents := string(b)
+
+	typeIdx := strings.Index(contents, fmt.Sprintf("\ntype %s struct {", kind))
+	if typeIdx < 0 {
+		return fmt.Errorf("type %s not found", kind)
+	}
+	markerIdx := strings.LastIndex(contents[:typeIdx], kbRootMarker)
+	if markerIdx < 0 {
+		return fmt.Errorf("marker %q not found above type %s", kbRootMarker, kind)
+	}
+	if strings.Contains(contents[:typeIdx], deprecatedVersionMarker) {
+		return nil
+	}
+	marker := deprecatedVersionMarker
+	if warning != "" {
+		marker += ":warning=" + strconv.Quote(warning)
+	}
+	contents = contents[:markerIdx] + marker + "\n" + contents[markerIdx:]
+	return ioutil.WriteFile(filePath, []byte(contents), 0644)
+}
+
+// bumpControllerGen sets the controller-gen version installed by the Makefile
+// at filePath to minControllerGenVersion if it is older.
+func bumpControllerGen(filePath string) error {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	contents := string(b)
+	m := controllerGenVersionPattern.FindStringSubmatch(contents)
+	if m == nil {
+		return fmt.Errorf("controller-gen version not found; controller-gen v%s or later is required "+
+			"to generate deprecated CRD versions", minControllerGenVersion)
+	}
+	current, err := semver.Parse(m[1])
+	if err != nil {
+		return fmt.Errorf("error parsing controller-gen version %q: %v", m[1], err)
+	}
+	if current.GE(minControllerGenVersion) {
+		return nil
+	}
+	contents = strings.Replace(contents, m[0],
+		strings.TrimSuffix(m[0], m[1])+minControllerGenVersion.String(), 1)
+	return ioutil.WriteFile(filePath, []byte(contents), 0644)
+}
+
+// newestVersion returns the GVK of the highest priority version of gvk's group and kind
+// in cfg, and true if that version is newer than gvk's.
+func newestVersion(cfg *config.Config, gvk config.GVK) (config.GVK, bool) {
+	newest := gvk
+	for _, r := range cfg.Resources {
+		if r.Group != gvk.Group || r.Kind != gvk.Kind {
+			continue
+		}
+		if version.CompareKubeAwareVersionStrings(r.Version, newest.Version) > 0 {
+			newest = r
+		}
+	}
+	return newest, newest.Version != gvk.Version
+}
+
+// replaceSample replaces the entry for oldGVK's sample with newGVK's sample in the
+// samples kustomization file at filePath. If newGVK's sample is already listed,
+// oldGVK's entry is removed.
+func replaceSample(filePath string, oldGVK, newGVK config.GVK) error {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	oldEntry := fmt.Sprintf(samplesEntryFragment, sampleFileName(oldGVK))
+	newEntry := fmt.Sprintf(samplesEntryFragment, sampleFileName(newGVK))
+
+	contents := string(b)
+	if !strings.Contains(contents, oldEntry) {
+		return nil
+	}
+	if strings.Contains(contents, newEntry) {
+		newEntry = ""
+	}
+	contents = strings.Replace(contents, oldEntry, newEntry, 1)
+	return ioutil.WriteFile(filePath, []byte(contents), 0644)
+}
+
+// samplesEntryFragment is formatted with a sample's file name.
+const samplesEntryFragment = "- %s\n"
+
+// sampleFileName returns the name of gvk's sample file in config/samples.
+func sampleFileName(gvk config.GVK) string {
+	return fmt.Sprintf("%s_%s_%s.yaml", gvk.Group, gvk.Version, strings.ToLower(gvk.Kind))
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+const controllerGenRecipe = `controller-gen:
+ifeq (, $(shell which controller-gen))
+	@{ \
+	set -e ;\
+	CONTROLLER_GEN_TMP_DIR=$$(mktemp -d) ;\
+	cd $$CONTROLLER_GEN_TMP_DIR ;\
+	go mod init tmp ;\
+	go get sigs.k8s.io/controller-tools/cmd/controller-gen@v%s ;\
+	rm -rf $$CONTROLLER_GEN_TMP_DIR ;\
+	}
+`
+
+var _ = Describe("Deprecated versions", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "deprecation-")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	writeFile := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+		return path
+	}
+	readFile := func(path string) string {
+		b, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	Describe("addDeprecatedVersionMarker", func() {
+		It("splices the marker with a warning above the kind's markers", func() {
+			typesFile := writeFile("memcached_types.go", memcachedTypes)
+			Expect(addDeprecatedVersionMarker(typesFile, "Memcached", `use "v1"`)).To(Succeed())
+			Expect(readFile(typesFile)).To(ContainSubstring(
+				"// +kubebuilder:deprecatedversion:warning=\"use \\\"v1\\\"\"\n" +
+					"// +kubebuilder:object:root=true\n// +kubebuilder:subresource:status\n\n// Memcached is"))
+		})
+
+		It("splices the marker without a warning once", func() {
+			typesFile := writeFile("memcached_types.go", memcachedTypes)
+			Expect(addDeprecatedVersionMarker(typesFile, "Memcached", "")).To(Succeed())
+			Expect(addDeprecatedVersionMarker(typesFile, "Memcached", "")).To(Succeed())
+			contents := readFile(typesFile)
+			Expect(strings.Count(contents, deprecatedVersionMarker)).To(Equal(1))
+			Expect(contents).To(ContainSubstring(deprecatedVersionMarker + "\n// +kubebuilder:object:root=true\n"))
+		})
+
+		It("fails if the kind's type does not exist", func() {
+			typesFile := writeFile("memcached_types.go", memcachedTypes)
+			Expect(addDeprecatedVersionMarker(typesFile, "Nginx", "")).NotTo(Succeed())
+		})
+	})
+
+	Describe("bumpControllerGen", func() {
+		It("bumps an older controller-gen", func() {
+			makefile := writeFile("Makefile", strings.Replace(controllerGenRecipe, "%s", "0.3.0", 1))
+			Expect(bumpControllerGen(makefile)).To(Succeed())
+			Expect(readFile(makefile)).To(Equal(strings.Replace(controllerGenRecipe, "%s", "0.4.1", 1)))
+		})
+
+		It("keeps a newer controller-gen", func() {
+			recipe := strings.Replace(controllerGenRecipe, "%s", "0.5.0", 1)
+			makefile := writeFile("Makefile", recipe)
+			Expect(bumpControllerGen(makefile)).To(Succeed())
+			Expect(readFile(makefile)).To(Equal(recipe))
+		})
+
+		It("fails if the Makefile does not install controller-gen", func() {
+			makefile := writeFile("Makefile", "all: build\n")
+			Expect(bumpControllerGen(makefile)).NotTo(Succeed())
+		})
+	})
+
+	Describe("replaceSample", func() {
+		var (
+			v1alpha1 = config.GVK{Group: "cache", Version: "v1alpha1", Kind: "Memcached"}
+			v1       = config.GVK{Group: "cache", Version: "v1", Kind: "Memcached"}
+		)
+
+		It("replaces the deprecated version's sample", func() {
+			samples := writeFile("kustomization.yaml", "resources:\n- cache_v1alpha1_memcached.yaml\n")
+			Expect(replaceSample(samples, v1alpha1, v1)).To(Succeed())
+			Expect(readFile(samples)).To(Equal("resources:\n- cache_v1_memcached.yaml\n"))
+		})
+
+		It("removes the deprecated version's sample if the newer one is listed", func() {
+			samples := writeFile("kustomization.yaml",
+				"resources:\n- cache_v1alpha1_memcached.yaml\n- cache_v1_memcached.yaml\n")
+			Expect(replaceSample(samples, v1alpha1, v1)).To(Succeed())
+			Expect(readFile(samples)).To(Equal("resources:\n- cache_v1_memcached.yaml\n"))
+		})
+	})
+})
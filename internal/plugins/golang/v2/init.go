@@ -29,12 +29,20 @@ type initPlugin struct {
 	plugin.Init
 
 	config *config.Config
+
+	// generateClients is true if typed clients should be generated for project APIs.
+	generateClients bool
 }
 
 var _ plugin.Init = &initPlugin{}
 
 func (p *initPlugin) UpdateContext(ctx *plugin.Context) { p.Init.UpdateContext(ctx) }
-func (p *initPlugin) BindFlags(fs *pflag.FlagSet)       { p.Init.BindFlags(fs) }
+
+func (p *initPlugin) BindFlags(fs *pflag.FlagSet) {
+	p.Init.BindFlags(fs)
+	fs.BoolVar(&p.generateClients, "generate-clients", false, "scaffold a 'generate-clients' Makefile target "+
+		"that generates a typed clientset, listers, and informers for project APIs with code-generator")
+}
 
 func (p *initPlugin) InjectConfig(c *config.Config) {
 	p.Init.InjectConfig(c)
@@ -53,7 +61,7 @@ func (p *initPlugin) Run() error {
 
 	// Update plugin config section with this plugin's configuration for v3 projects.
 	if p.config.IsV3() {
-		cfg := Config{}
+		cfg := Config{GenerateClients: p.generateClients}
 		if err := p.config.EncodePluginConfig(pluginConfigKey, cfg); err != nil {
 			return fmt.Errorf("error writing plugin config for %s: %v", pluginConfigKey, err)
 		}
@@ -70,5 +78,10 @@ func (p *initPlugin) runPhase2() error {
 	if err := scorecard.RunInit(p.config); err != nil {
 		return err
 	}
+	if p.generateClients && p.config.IsV3() {
+		if err := initUpdateMakefileClients(p.config, "Makefile"); err != nil {
+			return fmt.Errorf("error updating Makefile: %v", err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,285 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+
+	"github.com/operator-framework/operator-sdk/internal/kubebuilder/machinery"
+)
+
+const (
+	// boilerplatePath is the license header prepended to scaffolded Go files,
+	// relative to the project root, where the CLI runs as it does for
+	// kubebuilder's Go scaffolds.
+	boilerplatePath = "hack/boilerplate.go.txt"
+
+	// cobraModule is required by scaffolded kubectl plugins, at cobraVersion.
+	cobraModule  = "github.com/spf13/cobra"
+	cobraVersion = "v1.0.0"
+)
+
+// scaffoldKubectlPlugin scaffolds a kubectl plugin for gvk's kind in
+// cmd/kubectl-<kind>/main.go, and adds the plugin's dependencies to the
+// project's go.mod. The plugin uses the project's API types to get resources
+// of the kind, print their status, and trigger reconciles of them.
+func scaffoldKubectlPlugin(cfg *config.Config, gvk config.GVK) error {
+	res := (&resource.Options{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}).NewResource(cfg, true)
+	boilerplate, err := ioutil.ReadFile(boilerplatePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	universe := model.NewUniverse(
+		model.WithConfig(cfg),
+		model.WithBoilerplate(string(boilerplate)),
+		model.WithResource(res),
+	)
+	if err := machinery.NewScaffold().Execute(universe, &kubectlPluginMain{}); err != nil {
+		return err
+	}
+	return addGoModRequirement("go.mod", cobraModule, cobraVersion)
+}
+
+// addGoModRequirement adds a requirement of module at version to the go.mod
+// file at filePath, unless it already requires module.
+func addGoModRequirement(filePath, module, version string) error {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	contents := string(b)
+	for _, line := range strings.Split(contents, "\n") {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "require"))
+		if len(fields) != 0 && fields[0] == module {
+			return nil
+		}
+	}
+
+	requirement := fmt.Sprintf("%s %s", module, version)
+	if i := strings.Index(contents, "\nrequire ("); i >= 0 {
+		end := strings.Index(contents[i:], "\n)")
+		if end < 0 {
+			return fmt.Errorf("error adding %s to %s: require block is not closed", module, filePath)
+		}
+		end += i + 1
+		contents = contents[:end] + "\t" + requirement + "\n" + contents[end:]
+	} else {
+		if !strings.HasSuffix(contents, "\n") {
+			contents += "\n"
+		}
+		contents += "\nrequire " + requirement + "\n"
+	}
+	return ioutil.WriteFile(filePath, []byte(contents), 0644)
+}
+
+var _ file.Template = &kubectlPluginMain{}
+
+// kubectlPluginMain scaffolds the main package of a kubectl plugin for a kind.
+type kubectlPluginMain struct {
+	file.TemplateMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+
+	// Name is the plugin's binary name, kubectl-<kind>.
+	Name string
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *kubectlPluginMain) SetTemplateDefaults() error {
+	if f.Name == "" {
+		f.Name = "kubectl-" + strings.ToLower(f.Resource.Kind)
+	}
+	if f.Path == "" {
+		f.Path = filepath.Join("cmd", f.Name, "main.go")
+	}
+
+	f.TemplateBody = kubectlPluginTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const kubectlPluginTemplate = `{{ .Boilerplate }}
+
+
+// Command {{ .Name }} is a kubectl plugin for {{ .Resource.Kind }} resources. Build it
+// onto your PATH to run it as 'kubectl {{ lower .Resource.Kind }}':
+//
+//   go build -o $(go env GOPATH)/bin/{{ .Name }} ./cmd/{{ .Name }}
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+
+	{{ .Resource.ImportAlias }} "{{ .Resource.Package }}"
+)
+
+// reconcileRequestedAnnotation is set to the current time by trigger-reconcile,
+// which updates the {{ .Resource.Kind }} so that the operator reconciles it.
+const reconcileRequestedAnnotation = "{{ .Resource.Domain }}/reconcile-requested-at"
+
+var namespace string
+
+func main() {
+	root := &cobra.Command{
+		Use:          "{{ .Name }}",
+		Short:        "Manage {{ .Resource.Kind }} resources",
+		SilenceUsage: true,
+	}
+	// Add controller-runtime's --kubeconfig flag.
+	root.PersistentFlags().AddGoFlagSet(flag.CommandLine)
+	root.PersistentFlags().StringVarP(&namespace, "namespace", "n", "default", "namespace of the {{ .Resource.Kind }} resources")
+	root.AddCommand(newGetCmd(), newStatusCmd(), newTriggerReconcileCmd())
+
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get [NAME]",
+		Short: "List {{ .Resource.Kind }} resources, or print one as YAML",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				obj, err := get(c, args[0])
+				if err != nil {
+					return err
+				}
+				return printYAML(obj)
+			}
+
+			list := &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}List{}
+			if err := c.List(context.TODO(), list, client.InNamespace(namespace)); err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 3, ' ', 0)
+			fmt.Fprintln(w, "NAME\tAGE")
+			for _, item := range list.Items {
+				age := duration.HumanDuration(time.Since(item.CreationTimestamp.Time))
+				fmt.Fprintf(w, "%s\t%s\n", item.Name, age)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status NAME",
+		Short: "Print the status of a {{ .Resource.Kind }} as YAML",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			obj, err := get(c, args[0])
+			if err != nil {
+				return err
+			}
+			return printYAML(obj.Status)
+		},
+	}
+}
+
+func newTriggerReconcileCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "trigger-reconcile NAME",
+		Short: "Annotate a {{ .Resource.Kind }} so that the operator reconciles it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			obj, err := get(c, args[0])
+			if err != nil {
+				return err
+			}
+			patch := client.MergeFrom(obj.DeepCopy())
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[reconcileRequestedAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+			obj.SetAnnotations(annotations)
+			if err := c.Patch(context.TODO(), obj, patch); err != nil {
+				return err
+			}
+			fmt.Printf("{{ .Resource.Kind }} %q reconcile requested\n", obj.Name)
+			return nil
+		},
+	}
+}
+
+func newClient() (client.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err := {{ .Resource.ImportAlias }}.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
+
+func get(c client.Client, name string) (*{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}, error) {
+	obj := &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if err := c.Get(context.TODO(), key, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func printYAML(v interface{}) error {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
+`
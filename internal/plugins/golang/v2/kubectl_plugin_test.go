@@ -0,0 +1,120 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+
+	"github.com/operator-framework/operator-sdk/internal/kubebuilder/machinery"
+)
+
+// captureFiles is a scaffold plugin that records the contents of scaffolded
+// files instead of writing them.
+type captureFiles map[string]string
+
+func (c captureFiles) Pipe(u *model.Universe) error {
+	for path, f := range u.Files {
+		c[path] = f.Contents
+	}
+	u.Files = nil
+	return nil
+}
+
+var _ = Describe("kubectl plugin", func() {
+	DescribeTable("scaffolding the plugin",
+		func(multiGroup bool, importLine string) {
+			cfg := &config.Config{
+				Version:    config.Version3Alpha,
+				Domain:     "example.com",
+				Repo:       "github.com/example/memcached-operator",
+				MultiGroup: multiGroup,
+			}
+			res := (&resource.Options{
+				Namespaced: true,
+				Group:      "cache",
+				Version:    "v1alpha1",
+				Kind:       "Memcached",
+			}).NewResource(cfg, true)
+			universe := model.NewUniverse(
+				model.WithConfig(cfg),
+				model.WithBoilerplate("// Copyright 2020 Example Authors."),
+				model.WithResource(res),
+			)
+			files := captureFiles{}
+			Expect(machinery.NewScaffold(files).Execute(universe, &kubectlPluginMain{})).To(Succeed())
+
+			Expect(files).To(HaveKey(filepath.Join("cmd", "kubectl-memcached", "main.go")))
+			contents := files[filepath.Join("cmd", "kubectl-memcached", "main.go")]
+			Expect(contents).To(HavePrefix("// Copyright 2020 Example Authors.\n"))
+			Expect(contents).To(ContainSubstring("// Command kubectl-memcached is a kubectl plugin for Memcached " +
+				"resources."))
+			Expect(contents).To(ContainSubstring(importLine))
+			Expect(contents).To(ContainSubstring(`const reconcileRequestedAnnotation = ` +
+				`"cache.example.com/reconcile-requested-at"`))
+			Expect(contents).To(ContainSubstring(`Use:          "kubectl-memcached",`))
+			Expect(contents).To(ContainSubstring("list := &cachev1alpha1.MemcachedList{}"))
+			Expect(contents).To(ContainSubstring("if err := cachev1alpha1.AddToScheme(scheme); err != nil {"))
+		},
+		Entry("single group", false, `cachev1alpha1 "github.com/example/memcached-operator/api/v1alpha1"`),
+		Entry("multi-group", true, `cachev1alpha1 "github.com/example/memcached-operator/apis/cache/v1alpha1"`),
+	)
+
+	Describe("addGoModRequirement", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "kubectl-plugin-")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		DescribeTable("adding cobra",
+			func(goMod, expected string) {
+				goModPath := filepath.Join(dir, "go.mod")
+				Expect(ioutil.WriteFile(goModPath, []byte(goMod), 0644)).To(Succeed())
+				Expect(addGoModRequirement(goModPath, cobraModule, cobraVersion)).To(Succeed())
+				b, err := ioutil.ReadFile(goModPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal(expected))
+			},
+			Entry("to a require block",
+				"module example.com/m\n\ngo 1.13\n\nrequire (\n\tsigs.k8s.io/controller-runtime v0.6.2\n)\n",
+				"module example.com/m\n\ngo 1.13\n\nrequire (\n\tsigs.k8s.io/controller-runtime v0.6.2\n"+
+					"\tgithub.com/spf13/cobra v1.0.0\n)\n"),
+			Entry("without a require block",
+				"module example.com/m\n\ngo 1.13\n",
+				"module example.com/m\n\ngo 1.13\n\nrequire github.com/spf13/cobra v1.0.0\n"),
+			Entry("already required in a block",
+				"module example.com/m\n\nrequire (\n\tgithub.com/spf13/cobra v0.0.5\n)\n",
+				"module example.com/m\n\nrequire (\n\tgithub.com/spf13/cobra v0.0.5\n)\n"),
+			Entry("already required on a line",
+				"module example.com/m\n\nrequire github.com/spf13/cobra v0.0.5\n",
+				"module example.com/m\n\nrequire github.com/spf13/cobra v0.0.5\n"),
+		)
+	})
+})
@@ -28,12 +28,16 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/chartutil"
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds"
 	"github.com/operator-framework/operator-sdk/internal/plugins/manifests"
+	"github.com/operator-framework/operator-sdk/internal/plugins/util/crdvalidation"
 )
 
 type createAPIPlugin struct {
 	config *config.Config
 
 	createOptions chartutil.CreateOptions
+
+	// validationRulesFile is the path to a file of CEL validation rules for the CRD.
+	validationRulesFile string
 }
 
 var (
@@ -70,11 +74,22 @@ func (p createAPIPlugin) UpdateContext(ctx *plugin.Context) {
       --helm-chart-repo=https://charts.mycompany.com/ \
       --helm-chart-version=1.2.3
 
+  $ %s create api \
+      --helm-chart=app \
+      --helm-chart-repo=https://charts.mycompany.com/ \
+      --helm-chart-version=">=1.2.0 <2.0.0" \
+      --update-chart
+
   $ %s create api \
       --helm-chart=/path/to/local/chart-directories/app/
 
   $ %s create api \
       --helm-chart=/path/to/local/chart-archives/app-1.2.3.tgz
+
+  $ %s create api \
+      --group=apps --version=v1beta1 \
+      --kind=AppService \
+      --conversion-webhook
 `,
 		ctx.CommandName,
 		ctx.CommandName,
@@ -84,17 +99,22 @@ func (p createAPIPlugin) UpdateContext(ctx *plugin.Context) {
 		ctx.CommandName,
 		ctx.CommandName,
 		ctx.CommandName,
+		ctx.CommandName,
+		ctx.CommandName,
 	)
 }
 
 const (
-	groupFlag            = "group"
-	versionFlag          = "version"
-	kindFlag             = "kind"
-	helmChartFlag        = "helm-chart"
-	helmChartRepoFlag    = "helm-chart-repo"
-	helmChartVersionFlag = "helm-chart-version"
-	crdVersionFlag       = "crd-version"
+	groupFlag              = "group"
+	versionFlag            = "version"
+	kindFlag               = "kind"
+	helmChartFlag          = "helm-chart"
+	helmChartRepoFlag      = "helm-chart-repo"
+	helmChartVersionFlag   = "helm-chart-version"
+	updateChartFlag        = "update-chart"
+	crdVersionFlag         = "crd-version"
+	crdValidationRulesFlag = "crd-validation-rules"
+	conversionWebhookFlag  = "conversion-webhook"
 
 	crdVersionV1      = "v1"
 	crdVersionV1beta1 = "v1beta1"
@@ -111,9 +131,16 @@ func (p *createAPIPlugin) BindFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&p.createOptions.Chart, helmChartFlag, "", "helm chart")
 	fs.StringVar(&p.createOptions.Repo, helmChartRepoFlag, "", "helm chart repository")
-	fs.StringVar(&p.createOptions.Version, helmChartVersionFlag, "", "helm chart version (default: latest)")
+	fs.StringVar(&p.createOptions.Version, helmChartVersionFlag, "", "helm chart version or version range (default: latest)")
+	fs.BoolVar(&p.createOptions.UpdateChart, updateChartFlag, false,
+		"re-resolve the helm chart version and update its entry in "+chartutil.HelmChartsDir+"/"+chartutil.LockFileName)
 
 	fs.StringVar(&p.createOptions.CRDVersion, crdVersionFlag, crdVersionV1, "crd version to generate")
+	fs.StringVar(&p.validationRulesFile, crdValidationRulesFlag, "", "path to a YAML file containing a list of "+
+		"CEL validation rules to add to the CRD's spec schema as x-kubernetes-validations. Requires --"+crdVersionFlag+"=v1")
+	fs.BoolVar(&p.createOptions.ConversionWebhook, conversionWebhookFlag, false, "scaffold a conversion webhook and "+
+		"a values mapping file for the kind. If the kind already exists, its CRD and watch are given the new version. "+
+		"Requires --"+crdVersionFlag+"=v1")
 }
 
 // InjectConfig will inject the PROJECT file/config in the plugin
@@ -147,11 +174,28 @@ func (p *createAPIPlugin) Validate() error {
 		return fmt.Errorf("value of --%s must be either %q or %q", crdVersionFlag, crdVersionV1, crdVersionV1beta1)
 	}
 
+	if p.validationRulesFile != "" {
+		if p.createOptions.CRDVersion != crdVersionV1 {
+			return fmt.Errorf("value of --%s can only be used with --%s=%s", crdValidationRulesFlag, crdVersionFlag, crdVersionV1)
+		}
+		rules, err := crdvalidation.ReadRules(p.validationRulesFile)
+		if err != nil {
+			return err
+		}
+		p.createOptions.ValidationRules = rules
+	}
+
+	if p.createOptions.ConversionWebhook && p.createOptions.CRDVersion != crdVersionV1 {
+		return fmt.Errorf("value of --%s can only be used with --%s=%s", conversionWebhookFlag, crdVersionFlag, crdVersionV1)
+	}
+
 	if len(strings.TrimSpace(p.createOptions.Chart)) == 0 {
 		if len(strings.TrimSpace(p.createOptions.Repo)) != 0 {
 			return fmt.Errorf("value of --%s can only be used with --%s", helmChartRepoFlag, helmChartFlag)
 		} else if len(p.createOptions.Version) != 0 {
 			return fmt.Errorf("value of --%s can only be used with --%s", helmChartVersionFlag, helmChartFlag)
+		} else if p.createOptions.UpdateChart {
+			return fmt.Errorf("value of --%s can only be used with --%s", updateChartFlag, helmChartFlag)
 		}
 	}
 
@@ -33,6 +33,8 @@ import (
 	"helm.sh/helm/v3/pkg/repo"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+
+	"github.com/operator-framework/operator-sdk/internal/plugins/util/crdvalidation"
 )
 
 const (
@@ -61,11 +63,23 @@ type CreateOptions struct {
 	// Repo is a URL to a custom chart repository.
 	Repo string
 
-	// Version is the version of the chart to fetch.
+	// Version is the version or version range of the chart to fetch.
 	Version string
 
+	// UpdateChart re-resolves Version for a chart recorded in the lock file
+	// instead of fetching the locked version.
+	UpdateChart bool
+
 	// CRDVersion is the version of the `apiextensions.k8s.io` API which will be used to generate the CRD.
 	CRDVersion string
+
+	// ValidationRules are CEL validation rules added to the CRD's spec schema.
+	ValidationRules []crdvalidation.Rule
+
+	// ConversionWebhook scaffolds a conversion webhook for the API's kind. If
+	// the kind already exists in another version, GVK's version is added to
+	// its CRD instead of creating a new chart.
+	ConversionWebhook bool
 }
 
 // CreateChart scaffolds a new helm chart for the project rooted in projectDir
@@ -108,10 +122,16 @@ type CreateOptions struct {
 //                  specified by opts.Repo
 //
 // If opts.Version is not set, CreateChart will fetch the latest available version of
-// the helm chart. Otherwise, CreateChart will fetch the specified version.
+// the helm chart. Otherwise, CreateChart will fetch the specified version, or the
+// latest version satisfying opts.Version if it is a semantic version range.
 // opts.Version is not used when opts.Chart itself refers to a specific version, for
 // example when it is a local path or a URL.
 //
+// The exact version and digest of a chart fetched from a remote repository are
+// recorded in the project's helm charts directory lock file. Subsequent fetches
+// of the same chart with the same opts.Version use the locked version and verify
+// its digest, unless opts.UpdateChart is set.
+//
 // CreateChart returns an error if an error occurs creating the scaffold.Resource or
 // creating the chart.
 func CreateChart(projectDir string, opts CreateOptions) (*resource.Options, *chart.Chart, error) {
@@ -234,6 +254,19 @@ func createChartFromRemote(destDir string, opts CreateOptions) (*chart.Chart, er
 		RepositoryCache:  settings.RepositoryCache,
 	}
 
+	lock, err := ReadChartLock(destDir)
+	if err != nil {
+		return nil, err
+	}
+	chartRef, constraint := opts.Chart, opts.Version
+	locked := lock.Get(chartRef, opts.Repo)
+	if locked != nil && (opts.UpdateChart || locked.VersionConstraint != constraint) {
+		locked = nil
+	}
+	if locked != nil {
+		opts.Version = locked.Version
+	}
+
 	if opts.Repo != "" {
 		chartURL, err := repo.FindChartInRepoURL(opts.Repo, opts.Chart, opts.Version, "", "", "", getters)
 		if err != nil {
@@ -257,7 +290,32 @@ func createChartFromRemote(destDir string, opts CreateOptions) (*chart.Chart, er
 		return nil, err
 	}
 
-	return createChartFromDisk(destDir, chartArchive)
+	digest, err := digestFile(chartArchive)
+	if err != nil {
+		return nil, err
+	}
+	if locked != nil && locked.Digest != digest {
+		return nil, fmt.Errorf("digest %s of chart %s version %s does not match locked digest %s",
+			digest, chartRef, locked.Version, locked.Digest)
+	}
+
+	chrt, err := createChartFromDisk(destDir, chartArchive)
+	if err != nil {
+		return nil, err
+	}
+
+	lock.Set(LockedChart{
+		Name:              chrt.Name(),
+		Chart:             chartRef,
+		Repository:        opts.Repo,
+		VersionConstraint: constraint,
+		Version:           chrt.Metadata.Version,
+		Digest:            digest,
+	})
+	if err := WriteChartLock(destDir, lock); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %v", LockFileName, err)
+	}
+	return chrt, nil
 }
 
 func fetchChartDependencies(chartPath string) error {
@@ -183,6 +183,23 @@ func TestCreateChart(t *testing.T) {
 			expectChartName:    chartName,
 			expectChartVersion: previousVersion,
 		},
+		{
+			name:               "from name and repo url version range",
+			helmChart:          chartName,
+			helmChartRepo:      srv.URL(),
+			helmChartVersion:   "~1.2.0",
+			expectResource:     mustNewResource(chartutil.DefaultGroup, chartutil.DefaultVersion, expectDerivedKind),
+			expectChartName:    chartName,
+			expectChartVersion: latestVersion,
+		},
+		{
+			name:               "from repo and name version range excluding latest",
+			helmChart:          "test/" + chartName,
+			helmChartVersion:   "<" + latestVersion,
+			expectResource:     mustNewResource(chartutil.DefaultGroup, chartutil.DefaultVersion, expectDerivedKind),
+			expectChartName:    chartName,
+			expectChartVersion: previousVersion,
+		},
 	}
 
 	for _, tc := range testCases {
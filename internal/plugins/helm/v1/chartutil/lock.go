@@ -0,0 +1,113 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// LockFileName is the name of the file within HelmChartsDir that records the
+// exact versions and digests of charts fetched from remote repositories.
+const LockFileName = "charts.lock"
+
+// ChartLock records remote charts resolved by CreateChart.
+type ChartLock struct {
+	Charts []LockedChart `json:"charts"`
+}
+
+// LockedChart records the exact version and digest a chart reference and
+// version constraint resolved to.
+type LockedChart struct {
+	// Name is the name of the chart.
+	Name string `json:"name"`
+	// Chart is the chart reference passed to CreateChart.
+	Chart string `json:"chart"`
+	// Repository is the chart repository URL, if any.
+	Repository string `json:"repository,omitempty"`
+	// VersionConstraint is the version or version range requested.
+	VersionConstraint string `json:"versionConstraint,omitempty"`
+	// Version is the exact chart version the constraint resolved to.
+	Version string `json:"version"`
+	// Digest is the sha256 digest of the chart archive.
+	Digest string `json:"digest"`
+}
+
+// ReadChartLock reads the lock file in chartsDir. If no lock file exists,
+// an empty ChartLock is returned.
+func ReadChartLock(chartsDir string) (*ChartLock, error) {
+	lock := &ChartLock{}
+	b, err := ioutil.ReadFile(filepath.Join(chartsDir, LockFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lock, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, lock); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", LockFileName, err)
+	}
+	return lock, nil
+}
+
+// WriteChartLock writes lock to the lock file in chartsDir.
+func WriteChartLock(chartsDir string, lock *ChartLock) error {
+	b, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(chartsDir, LockFileName), b, 0644)
+}
+
+// Get returns the locked entry for chart in repo, or nil if none exists.
+func (l *ChartLock) Get(chart, repo string) *LockedChart {
+	for i, c := range l.Charts {
+		if c.Chart == chart && c.Repository == repo {
+			return &l.Charts[i]
+		}
+	}
+	return nil
+}
+
+// Set adds entry to l, replacing any existing entry for the same chart and repository.
+func (l *ChartLock) Set(entry LockedChart) {
+	if existing := l.Get(entry.Chart, entry.Repository); existing != nil {
+		*existing = entry
+		return
+	}
+	l.Charts = append(l.Charts, entry)
+}
+
+// digestFile returns the sha256 digest of the file at path.
+func digestFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chartutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/repo/repotest"
+
+	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/chartutil"
+)
+
+func TestChartLock(t *testing.T) {
+	srv, err := repotest.NewTempServer("testdata/*.tgz")
+	if err != nil {
+		t.Fatalf("Failed to create new temp server: %s", err)
+	}
+	defer srv.Stop()
+
+	if err := srv.LinkIndices(); err != nil {
+		t.Fatalf("Failed to link server indices: %s", err)
+	}
+
+	testDir := srv.Root()
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(testDir, ".config"))
+	os.Setenv("XDG_CACHE_HOME", filepath.Join(testDir, ".cache"))
+	os.Setenv("HELM_REPOSITORY_CONFIG", filepath.Join(testDir, "repositories.yaml"))
+	os.Setenv("HELM_REPOSITORY_CACHE", filepath.Join(testDir))
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	defer os.Unsetenv("XDG_CACHE_HOME")
+	defer os.Unsetenv("HELM_REPOSITORY_CONFIG")
+	defer os.Unsetenv("HELM_REPOSITORY_CACHE")
+
+	outputDir := filepath.Join(testDir, "output")
+	assert.NoError(t, os.Mkdir(outputDir, 0755))
+	defer os.RemoveAll(outputDir)
+	chartsDir := filepath.Join(outputDir, chartutil.HelmChartsDir)
+
+	opts := chartutil.CreateOptions{
+		Chart:   "test-chart",
+		Repo:    srv.URL(),
+		Version: "^1.2.0",
+	}
+
+	// Resolving a range records the exact version and digest.
+	_, chrt, err := chartutil.CreateChart(outputDir, opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "1.2.3", chrt.Metadata.Version)
+
+	lock, err := chartutil.ReadChartLock(chartsDir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	locked := lock.Get(opts.Chart, opts.Repo)
+	if !assert.NotNil(t, locked) {
+		return
+	}
+	assert.Equal(t, "test-chart", locked.Name)
+	assert.Equal(t, "^1.2.0", locked.VersionConstraint)
+	assert.Equal(t, "1.2.3", locked.Version)
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", locked.Digest)
+
+	// Pin the lock to a previous version, which must be honored on subsequent fetches.
+	prevDir := filepath.Join(testDir, "prev")
+	assert.NoError(t, os.Mkdir(prevDir, 0755))
+	defer os.RemoveAll(prevDir)
+	_, prev, err := chartutil.CreateChart(prevDir, chartutil.CreateOptions{
+		Chart:   opts.Chart,
+		Repo:    opts.Repo,
+		Version: "1.2.0",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	prevLock, err := chartutil.ReadChartLock(filepath.Join(prevDir, chartutil.HelmChartsDir))
+	if !assert.NoError(t, err) {
+		return
+	}
+	pinned := *prevLock.Get(opts.Chart, opts.Repo)
+	pinned.VersionConstraint = opts.Version
+	lock.Set(pinned)
+	assert.NoError(t, chartutil.WriteChartLock(chartsDir, lock))
+
+	_, chrt, err = chartutil.CreateChart(outputDir, opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, prev.Metadata.Version, chrt.Metadata.Version)
+
+	// A mismatched digest is an error.
+	bad := pinned
+	bad.Digest = "sha256:0000"
+	lock.Set(bad)
+	assert.NoError(t, chartutil.WriteChartLock(chartsDir, lock))
+	_, _, err = chartutil.CreateChart(outputDir, opts)
+	assert.Error(t, err)
+
+	// Updating the chart re-resolves the range.
+	opts.UpdateChart = true
+	_, chrt, err = chartutil.CreateChart(outputDir, opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "1.2.3", chrt.Metadata.Version)
+	lock, err = chartutil.ReadChartLock(chartsDir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "1.2.3", lock.Get(opts.Chart, opts.Repo).Version)
+}
@@ -23,17 +23,25 @@ import (
 	"os"
 	"path/filepath"
 
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 
 	"github.com/operator-framework/operator-sdk/internal/kubebuilder/machinery"
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/chartutil"
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates"
+	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/certmanager"
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/crd"
+	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/crd/patches"
+	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/kdefault"
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/rbac"
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/samples"
+	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/webhook"
+	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/test/kuttl"
 )
 
 var _ scaffold.Scaffolder = &apiScaffolder{}
@@ -66,6 +74,21 @@ func (s *apiScaffolder) newUniverse(r *resource.Resource) *model.Universe {
 }
 
 func (s *apiScaffolder) scaffold() error {
+	if s.opts.ConversionWebhook && s.opts.GVK.Kind != "" {
+		r := &resource.Options{
+			Namespaced: true,
+			Group:      s.opts.GVK.Group,
+			Version:    s.opts.GVK.Version,
+			Kind:       s.opts.GVK.Kind,
+		}
+		if s.hasKind(r) {
+			if s.opts.Chart != "" {
+				return fmt.Errorf("kind %s already exists: a chart cannot be used to add a version", r.Kind)
+			}
+			return s.scaffoldVersion(r)
+		}
+	}
+
 	projectDir, err := os.Getwd()
 	if err != nil {
 		return err
@@ -79,6 +102,10 @@ func (s *apiScaffolder) scaffold() error {
 	if s.config.HasResource(r.GVK()) {
 		return errors.New("the API resource already exists")
 	}
+	if s.hasKind(r) {
+		return fmt.Errorf("kind %s already exists in another version: use --group, --version, --kind, "+
+			"and --conversion-webhook to add version %s to it", r.Kind, r.Version)
+	}
 	// Check that the provided group can be added to the project
 	if !s.config.MultiGroup && len(s.config.Resources) != 0 && !s.config.HasGroup(r.Group) {
 		return errors.New("multiple groups are not allowed by default, to enable multi-group set 'multigroup: true' in your PROJECT file")
@@ -88,18 +115,113 @@ func (s *apiScaffolder) scaffold() error {
 	s.config.AddResource(res.GVK())
 
 	chartPath := filepath.Join(chartutil.HelmChartsDir, chrt.Metadata.Name)
-	if err := machinery.NewScaffold().Execute(
-		s.newUniverse(res),
-		&templates.WatchesUpdater{ChartPath: chartPath},
-		&crd.CRD{CRDVersion: s.opts.CRDVersion},
-		&crd.Kustomization{},
+	watchesUpdater := &templates.WatchesUpdater{ChartPath: chartPath}
+	files := []file.Builder{
+		watchesUpdater,
+		&crd.CRD{CRDVersion: s.opts.CRDVersion, ValidationRules: s.opts.ValidationRules},
+		&crd.Kustomization{ConversionWebhook: s.opts.ConversionWebhook},
 		&rbac.CRDEditorRole{},
 		&rbac.CRDViewerRole{},
 		&rbac.ManagerRoleUpdater{Chart: chrt},
 		&samples.CRDSample{ChartPath: chartPath, Chart: chrt},
-	); err != nil {
+	}
+	files = append(files, kuttlTestFiles(chartPath, chrt)...)
+	if s.opts.ConversionWebhook {
+		watchesUpdater.ConversionFile = filepath.Join(chartPath, templates.ConversionFileName)
+		files = append(files, &templates.Conversion{ConversionFile: watchesUpdater.ConversionFile})
+		files = append(files, conversionWebhookFiles()...)
+	}
+	if err := machinery.NewScaffold().Execute(s.newUniverse(res), files...); err != nil {
 		return fmt.Errorf("error scaffolding APIs: %v", err)
 	}
 
 	return nil
 }
+
+// hasKind returns true if the project has an API of r's group and kind.
+func (s *apiScaffolder) hasKind(r *resource.Options) bool {
+	for _, gvk := range s.config.Resources {
+		if gvk.Group == r.Group && gvk.Kind == r.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// scaffoldVersion adds r's version to the API of an existing kind, whose
+// versions are converted by the operator's conversion webhook. The kind's
+// chart and watch are unchanged, except that the watch is given a conversion
+// file if it has none.
+func (s *apiScaffolder) scaffoldVersion(r *resource.Options) error {
+	res := r.NewResource(s.config, true)
+	if s.config.HasResource(res.GVK()) {
+		return errors.New("the API resource already exists")
+	}
+
+	w, err := findWatch(templates.DefaultWatchesFile, res.Domain, res.Kind)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(w.Chart); err != nil || !info.IsDir() {
+		return fmt.Errorf("chart %s of kind %s must be a local chart directory to add a version", w.Chart, res.Kind)
+	}
+	chrt, err := loader.Load(w.Chart)
+	if err != nil {
+		return fmt.Errorf("failed to load chart %s: %v", w.Chart, err)
+	}
+	if w.ConversionFile == "" {
+		w.ConversionFile = filepath.Join(w.Chart, templates.ConversionFileName)
+		if err := setWatchConversionFile(templates.DefaultWatchesFile, w); err != nil {
+			return err
+		}
+	}
+
+	crdPath := filepath.Join("config", "crd", "bases", fmt.Sprintf("%s_%s.yaml", res.Domain, res.Plural))
+	if err := addCRDVersion(crdPath, res.Version); err != nil {
+		return err
+	}
+	s.config.AddResource(res.GVK())
+
+	files := []file.Builder{
+		&crd.Kustomization{ConversionWebhook: true},
+		&templates.Conversion{ConversionFile: w.ConversionFile},
+		&templates.ConversionUpdater{ConversionFile: w.ConversionFile},
+		&samples.CRDSample{ChartPath: w.Chart, Chart: chrt},
+	}
+	files = append(files, conversionWebhookFiles()...)
+	if err := machinery.NewScaffold().Execute(s.newUniverse(res), files...); err != nil {
+		return fmt.Errorf("error scaffolding API version: %v", err)
+	}
+
+	return nil
+}
+
+// kuttlTestFiles returns the files of a kuttl test that installs a sample CR
+// of the resource, asserts that its chart is deployed, and deletes it.
+func kuttlTestFiles(chartPath string, chrt *chart.Chart) []file.Builder {
+	install := &samples.CRDSample{ChartPath: chartPath, Chart: chrt}
+	install.Path = filepath.Join(kuttl.Dir, "%[kind]", "00-install.yaml")
+	return []file.Builder{
+		&kuttl.TestSuite{},
+		install,
+		&kuttl.InstallAssert{Chart: chrt},
+		&kuttl.DeleteStep{},
+		&kuttl.DeleteErrors{Chart: chrt},
+	}
+}
+
+// conversionWebhookFiles returns the files that deploy the operator's
+// conversion webhook.
+func conversionWebhookFiles() []file.Builder {
+	return []file.Builder{
+		&crd.KustomizeConfig{},
+		&patches.EnableWebhookPatch{},
+		&patches.EnableCAInjectionPatch{},
+		&webhook.Kustomization{},
+		&webhook.Service{},
+		&certmanager.Certificate{},
+		&certmanager.Kustomization{},
+		&certmanager.KustomizeConfig{},
+		&kdefault.ManagerWebhookPatch{},
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/manager"
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/prometheus"
 	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/config/rbac"
+	"github.com/operator-framework/operator-sdk/internal/plugins/helm/v1/scaffolds/internal/templates/test/kuttl"
 	"github.com/operator-framework/operator-sdk/internal/version"
 )
 
@@ -39,6 +40,10 @@ const (
 	// KustomizeVersion is the kubernetes-sigs/kustomize version to be used in the project
 	KustomizeVersion = "v3.5.4"
 
+	// KuttlVersion is the kudobuilder/kuttl version used to run the project's kuttl tests. It matches
+	// the version in the scorecard kuttl test image.
+	KuttlVersion = "0.6.1"
+
 	imageName = "controller:latest"
 )
 
@@ -91,6 +96,7 @@ func (s *initScaffolder) scaffold() error {
 			Image:               imageName,
 			KustomizeVersion:    KustomizeVersion,
 			HelmOperatorVersion: HelmOperatorVersion,
+			KuttlVersion:        KuttlVersion,
 		},
 		&templates.Watches{},
 		&rbac.AuthProxyRole{},
@@ -106,7 +112,8 @@ func (s *initScaffolder) scaffold() error {
 		&manager.Manager{Image: imageName},
 		&prometheus.Kustomization{},
 		&prometheus.ServiceMonitor{},
-		&kdefault.AuthProxyPatch{},
+		&kdefault.MetricsPatch{},
 		&kdefault.Kustomization{},
+		&kuttl.TestSuite{},
 	)
 }
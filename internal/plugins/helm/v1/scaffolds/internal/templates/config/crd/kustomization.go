@@ -31,6 +31,10 @@ var _ file.Inserter = &Kustomization{}
 type Kustomization struct {
 	file.TemplateMixin
 	file.ResourceMixin
+
+	// ConversionWebhook adds commented-out patches that enable a conversion
+	// webhook for the resource's CRD.
+	ConversionWebhook bool
 }
 
 // SetTemplateDefaults implements file.Template
@@ -42,24 +46,34 @@ func (f *Kustomization) SetTemplateDefaults() error {
 
 	f.TemplateBody = fmt.Sprintf(kustomizationTemplate,
 		file.NewMarkerFor(f.Path, resourceMarker),
+		file.NewMarkerFor(f.Path, webhookPatchMarker),
+		file.NewMarkerFor(f.Path, caInjectionPatchMarker),
 	)
 
 	return nil
 }
 
 const (
-	resourceMarker = "crdkustomizeresource"
+	resourceMarker         = "crdkustomizeresource"
+	webhookPatchMarker     = "crdkustomizewebhookpatch"
+	caInjectionPatchMarker = "crdkustomizecainjectionpatch"
 )
 
 // GetMarkers implements file.Inserter
 func (f *Kustomization) GetMarkers() []file.Marker {
 	return []file.Marker{
 		file.NewMarkerFor(f.Path, resourceMarker),
+		file.NewMarkerFor(f.Path, webhookPatchMarker),
+		file.NewMarkerFor(f.Path, caInjectionPatchMarker),
 	}
 }
 
 const (
 	resourceCodeFragment = `- bases/%s_%s.yaml
+`
+	webhookPatchCodeFragment = `#- patches/webhook_in_%s.yaml
+`
+	caInjectionPatchCodeFragment = `#- patches/cainjection_in_%s.yaml
 `
 )
 
@@ -75,6 +89,14 @@ func (f *Kustomization) GetCodeFragments() file.CodeFragmentsMap {
 	if len(res) != 0 {
 		fragments[file.NewMarkerFor(f.Path, resourceMarker)] = res
 	}
+	if f.ConversionWebhook {
+		fragments[file.NewMarkerFor(f.Path, webhookPatchMarker)] = []string{
+			fmt.Sprintf(webhookPatchCodeFragment, f.Resource.Plural),
+		}
+		fragments[file.NewMarkerFor(f.Path, caInjectionPatchMarker)] = []string{
+			fmt.Sprintf(caInjectionPatchCodeFragment, f.Resource.Plural),
+		}
+	}
 
 	return fragments
 }
@@ -84,4 +106,17 @@ var kustomizationTemplate = `# This kustomization.yaml is not intended to be run
 # It should be run by config/default
 resources:
 %s
+
+patchesStrategicMerge:
+# [WEBHOOK] To enable the conversion webhook, uncomment all the sections with [WEBHOOK] prefix.
+# patches here are for enabling the conversion webhook for each CRD
+%s
+
+# [CERTMANAGER] To enable cert-manager, uncomment all the sections with [CERTMANAGER] prefix.
+# patches here are for enabling the CA injection for each CRD
+%s
+
+# [WEBHOOK] the following config is for teaching kustomize how to do kustomization for CRDs.
+#configurations:
+#- kustomizeconfig.yaml
 `
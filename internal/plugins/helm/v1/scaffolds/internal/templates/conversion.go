@@ -0,0 +1,108 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package templates
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+// ConversionFileName is the name of the file of version mappings scaffolded
+// in a chart directory.
+const ConversionFileName = "conversion.yaml"
+
+var _ file.Template = &Conversion{}
+
+// Conversion scaffolds the file of version mappings that drives the
+// conversion webhook of a kind.
+type Conversion struct {
+	file.TemplateMixin
+	file.ResourceMixin
+
+	// ConversionFile is the path of the file, which is also the watch's
+	// conversionFile.
+	ConversionFile string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Conversion) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = f.ConversionFile
+	}
+
+	f.TemplateBody = fmt.Sprintf(conversionTemplate,
+		file.NewMarkerFor(f.Path, conversionMarker),
+	)
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+var _ file.Inserter = &ConversionUpdater{}
+
+// ConversionUpdater adds a mapping for the resource's version to a
+// conversion file.
+type ConversionUpdater struct {
+	file.TemplateMixin
+	file.ResourceMixin
+
+	ConversionFile string
+}
+
+func (f *ConversionUpdater) GetPath() string {
+	return f.ConversionFile
+}
+
+func (*ConversionUpdater) GetIfExistsAction() file.IfExistsAction {
+	return file.Overwrite
+}
+
+const (
+	conversionMarker = "conversion"
+)
+
+func (f *ConversionUpdater) GetMarkers() []file.Marker {
+	return []file.Marker{
+		file.NewMarkerFor(f.GetPath(), conversionMarker),
+	}
+}
+
+func (f *ConversionUpdater) GetCodeFragments() file.CodeFragmentsMap {
+	fragments := make(file.CodeFragmentsMap, 1)
+	if f.Resource == nil {
+		return fragments
+	}
+	fragments[file.NewMarkerFor(f.GetPath(), conversionMarker)] = []string{
+		fmt.Sprintf(conversionFragment, f.Resource.Version),
+	}
+	return fragments
+}
+
+const conversionFragment = `- version: %s
+  fields: []
+`
+
+const conversionTemplate = `# Maps the fields of each version of {{ .Resource.Kind }} to the fields of the version
+# in watches.yaml, whose spec is used as the chart's values. Fields without a
+# mapping are copied unchanged between versions. For example:
+#
+# - version: v1beta1
+#   fields:
+#   - from: spec.replicas     # path in v1beta1
+#     to: spec.replicaCount   # path in the version in watches.yaml
+%s
+`
@@ -39,6 +39,9 @@ type Makefile struct {
 
 	// HelmOperatorVersion is the version of the base image and operator binary used in the project
 	HelmOperatorVersion string
+
+	// KuttlVersion is the version of the kuttl binary used to run the project's kuttl tests
+	KuttlVersion string
 }
 
 // SetTemplateDefaults implements input.Template
@@ -63,6 +66,10 @@ func (f *Makefile) SetTemplateDefaults() error {
 		f.HelmOperatorVersion = strings.TrimSuffix(version.Version, "+git")
 	}
 
+	if f.KuttlVersion == "" {
+		f.KuttlVersion = "0.6.1"
+	}
+
 	return nil
 }
 
@@ -93,6 +100,10 @@ deploy: kustomize
 undeploy: kustomize
 	$(KUSTOMIZE) build config/default | kubectl delete -f -
 
+# Run the kuttl tests in test/kuttl against the configured Kubernetes cluster in ~/.kube/config
+test-kuttl: kuttl
+	$(KUTTL) test test/kuttl --config=test/kuttl/kuttl-test.yaml
+
 # Build the docker image
 docker-build:
 	docker build . -t ${IMG}
@@ -133,4 +144,17 @@ HELM_OPERATOR=$(realpath ./bin/helm-operator)
 else
 HELM_OPERATOR=$(shell which helm-operator)
 endif
+
+kuttl:
+ifeq (, $(shell which kubectl-kuttl 2>/dev/null))
+	@{ \
+	set -e ;\
+	mkdir -p bin ;\
+	curl -sSLo bin/kubectl-kuttl https://github.com/kudobuilder/kuttl/releases/download/v{{ .KuttlVersion }}/kubectl-kuttl_{{ .KuttlVersion }}_$(OS)_$(ARCHOPER) ;\
+	chmod +x bin/kubectl-kuttl ;\
+	}
+KUTTL=$(realpath ./bin/kubectl-kuttl)
+else
+KUTTL=$(shell which kubectl-kuttl)
+endif
 `
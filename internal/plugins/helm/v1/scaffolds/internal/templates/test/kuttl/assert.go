@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Operator-SDK Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kuttl
+
+import (
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &InstallAssert{}
+
+// InstallAssert scaffolds the assertions that a resource's sample CR was
+// reconciled and its chart's workloads became ready
+type InstallAssert struct {
+	file.TemplateMixin
+	file.ResourceMixin
+
+	// Chart is rendered to find the workloads of the sample CR's release.
+	Chart *chart.Chart
+
+	// Workloads are asserted to have their replicas ready. If nil, they are
+	// rendered from Chart.
+	Workloads []Workload
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *InstallAssert) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir, "%[kind]", "00-assert.yaml")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+
+	if f.Workloads == nil {
+		f.Workloads = sampleWorkloads(f.Chart, sampleName(f.Resource.Kind))
+	}
+
+	f.TemplateBody = installAssertTemplate
+
+	return nil
+}
+
+// sampleName returns the name of the sample CR of kind, which is also the
+// name of its release.
+func sampleName(kind string) string {
+	return strings.ToLower(kind) + "-sample"
+}
+
+const installAssertTemplate = `apiVersion: {{ .Resource.Domain }}/{{ .Resource.Version }}
+kind: {{ .Resource.Kind }}
+metadata:
+  name: {{ lower .Resource.Kind }}-sample
+status:
+  deployedRelease:
+    name: {{ lower .Resource.Kind }}-sample
+{{- range .Workloads }}
+---
+# Rendered from the chart's default values. Update this assertion if the
+# sample CR's spec changes the chart's workloads.
+apiVersion: apps/v1
+kind: {{ .Kind }}
+metadata:
+  name: {{ .Name }}
+{{- if .Replicas }}
+status:
+  readyReplicas: {{ .Replicas }}
+{{- end }}
+{{- end }}
+`
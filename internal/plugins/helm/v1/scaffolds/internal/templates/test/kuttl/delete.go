@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Operator-SDK Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kuttl
+
+import (
+	"path/filepath"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &DeleteStep{}
+
+// DeleteStep scaffolds the test step that deletes a resource's sample CR
+type DeleteStep struct {
+	file.TemplateMixin
+	file.ResourceMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *DeleteStep) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir, "%[kind]", "01-delete.yaml")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+
+	f.TemplateBody = deleteStepTemplate
+
+	return nil
+}
+
+const deleteStepTemplate = `apiVersion: kudo.dev/v1beta1
+kind: TestStep
+delete:
+- apiVersion: {{ .Resource.Domain }}/{{ .Resource.Version }}
+  kind: {{ .Resource.Kind }}
+  name: {{ lower .Resource.Kind }}-sample
+`
+
+var _ file.Template = &DeleteErrors{}
+
+// DeleteErrors scaffolds the assertions that a resource's sample CR and its
+// chart's workloads were deleted
+type DeleteErrors struct {
+	file.TemplateMixin
+	file.ResourceMixin
+
+	// Chart is rendered to find the workloads of the sample CR's release.
+	Chart *chart.Chart
+
+	// Workloads are asserted to be deleted. If nil, they are rendered from Chart.
+	Workloads []Workload
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *DeleteErrors) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir, "%[kind]", "01-errors.yaml")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+
+	if f.Workloads == nil {
+		f.Workloads = sampleWorkloads(f.Chart, sampleName(f.Resource.Kind))
+	}
+
+	f.TemplateBody = deleteErrorsTemplate
+
+	return nil
+}
+
+const deleteErrorsTemplate = `apiVersion: {{ .Resource.Domain }}/{{ .Resource.Version }}
+kind: {{ .Resource.Kind }}
+metadata:
+  name: {{ lower .Resource.Kind }}-sample
+{{- range .Workloads }}
+---
+apiVersion: apps/v1
+kind: {{ .Kind }}
+metadata:
+  name: {{ .Name }}
+{{- end }}
+`
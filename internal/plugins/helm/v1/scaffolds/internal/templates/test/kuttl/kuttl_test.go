@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Operator-SDK Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kuttl
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+
+	"github.com/operator-framework/operator-sdk/internal/kubebuilder/machinery"
+)
+
+var testdataDir = filepath.Join("..", "..", "..", "..", "..", "..", "..", "..", "..",
+	"testdata", "helm", "memcached-operator")
+
+// captureFiles is a scaffold plugin that records the scaffolded files'
+// contents by path instead of writing them.
+type captureFiles map[string]string
+
+func (c captureFiles) Pipe(u *model.Universe) error {
+	for path, f := range u.Files {
+		c[path] = f.Contents
+	}
+	u.Files = nil
+	return nil
+}
+
+func workloadChart(templates map[string]string) *chart.Chart {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "test", Version: "0.1.0"},
+		Values:   map[string]interface{}{},
+	}
+	for name, data := range templates {
+		c.Templates = append(c.Templates, &chart.File{Name: filepath.Join("templates", name), Data: []byte(data)})
+	}
+	return c
+}
+
+func TestRenderWorkloads(t *testing.T) {
+	memcachedChart, err := loader.Load(filepath.Join(testdataDir, "helm-charts", "memcached"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := []struct {
+		name              string
+		chart             *chart.Chart
+		expectedWorkloads []Workload
+	}{
+		{
+			name:              "statefulset with replicas",
+			chart:             memcachedChart,
+			expectedWorkloads: []Workload{{Kind: "StatefulSet", Name: "memcached-sample", Replicas: 3}},
+		},
+		{
+			name: "deployment, daemonset, and non-workload",
+			chart: workloadChart(map[string]string{
+				"deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: {{ .Release.Name }}\n",
+				"daemonset.yaml": "apiVersion: apps/v1\nkind: DaemonSet\nmetadata:\n  name: {{ .Release.Name }}-agent\n" +
+					"spec:\n  replicas: 2\n",
+				"service.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}\n",
+			}),
+			expectedWorkloads: []Workload{
+				{Kind: "DaemonSet", Name: "memcached-sample-agent"},
+				{Kind: "Deployment", Name: "memcached-sample", Replicas: 1},
+			},
+		},
+		{
+			name: "no workloads",
+			chart: workloadChart(map[string]string{
+				"configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n",
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			workloads, err := renderWorkloads(tc.chart, "memcached-sample")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedWorkloads, workloads)
+		})
+	}
+
+	_, err = renderWorkloads(workloadChart(map[string]string{"bad.yaml": "{{ .Values.missing.field }}"}),
+		"memcached-sample")
+	assert.Error(t, err)
+}
+
+func TestScaffoldAssertions(t *testing.T) {
+	memcachedChart, err := loader.Load(filepath.Join(testdataDir, "helm-charts", "memcached"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	cfg := &config.Config{Version: config.Version3Alpha, Domain: "example.com"}
+	r := &resource.Options{Namespaced: true, Group: "cache", Version: "v1alpha1", Kind: "Memcached"}
+	universe := model.NewUniverse(model.WithConfig(cfg), model.WithResource(r.NewResource(cfg, true)))
+
+	files := captureFiles{}
+	err = machinery.NewScaffold(files).Execute(universe, []file.Builder{
+		&InstallAssert{Chart: memcachedChart},
+		&DeleteErrors{Chart: memcachedChart},
+	}...)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The scaffolded files match the regenerated testdata.
+	for _, name := range []string{"00-assert.yaml", "01-errors.yaml"} {
+		path := filepath.Join(Dir, "memcached", name)
+		expected, err := ioutil.ReadFile(filepath.Join(testdataDir, path))
+		if assert.NoError(t, err) {
+			assert.Equal(t, string(expected), files[path], path)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Operator-SDK Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kuttl
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+// Dir is the directory containing the project's kuttl tests, one test per API.
+var Dir = filepath.Join("test", "kuttl")
+
+var _ file.Template = &TestSuite{}
+
+// TestSuite scaffolds the kuttl test suite configuration
+type TestSuite struct {
+	file.TemplateMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *TestSuite) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir, "kuttl-test.yaml")
+	}
+
+	// Projects scaffolded before kuttl tests were added get the suite with their next API.
+	f.IfExistsAction = file.Skip
+
+	f.TemplateBody = testSuiteTemplate
+
+	return nil
+}
+
+// startControlPlane is false because the tests are run against an existing
+// cluster, either by 'make test-kuttl' or by the scorecard kuttl test image.
+// Events are suppressed so that the scorecard's service account does not need
+// to read them.
+const testSuiteTemplate = `apiVersion: kudo.dev/v1beta1
+kind: TestSuite
+parallel: 4
+timeout: 300
+startControlPlane: false
+suppress:
+- events
+`
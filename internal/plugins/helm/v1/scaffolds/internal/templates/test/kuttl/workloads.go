@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Operator-SDK Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kuttl
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// workloadKinds are the apps/v1 kinds whose readiness is asserted.
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// Workload is a workload created by a chart's release
+type Workload struct {
+	Kind string
+	Name string
+	// Replicas is the number of replicas the workload deploys, or 0 for
+	// workloads whose number of pods is not known in advance.
+	Replicas int64
+}
+
+// sampleWorkloads returns the workloads of the release releaseName of c,
+// rendered with the chart's default values. If the chart cannot be rendered,
+// a warning is logged and no workloads are returned.
+func sampleWorkloads(c *chart.Chart, releaseName string) []Workload {
+	if c == nil {
+		return nil
+	}
+	workloads, err := renderWorkloads(c, releaseName)
+	if err != nil {
+		log.Warnf("Skipping kuttl assertions on the chart's workloads: %s", err)
+		return nil
+	}
+	return workloads
+}
+
+// renderWorkloads renders the release releaseName of c with the chart's
+// default values and returns its workloads, sorted by kind and name.
+func renderWorkloads(c *chart.Chart, releaseName string) ([]Workload, error) {
+	install := action.NewInstall(&action.Configuration{})
+	install.DryRun = true
+	install.ReleaseName = releaseName
+	install.Replace = true
+	install.ClientOnly = true
+	rel, err := install.Run(c, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart templates: %v", err)
+	}
+
+	var workloads []Workload
+	for _, manifest := range releaseutil.SplitManifests(rel.Manifest) {
+		u := unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest), &u.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse rendered manifest: %v", err)
+		}
+		if u.GetAPIVersion() != "apps/v1" || !workloadKinds[u.GetKind()] {
+			continue
+		}
+		w := Workload{Kind: u.GetKind(), Name: u.GetName()}
+		if w.Kind != "DaemonSet" {
+			// Deployments and StatefulSets default to one replica.
+			w.Replicas = 1
+			if replicas, ok, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "replicas"); ok {
+				w.Replicas = toInt64(replicas)
+			}
+		}
+		workloads = append(workloads, w)
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Kind != workloads[j].Kind {
+			return workloads[i].Kind < workloads[j].Kind
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	return workloads, nil
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
@@ -22,7 +22,8 @@ import (
 
 var _ file.Template = &Watches{}
 
-const defaultWatchesFile = "watches.yaml"
+// DefaultWatchesFile is the path of the watches file in a project.
+const DefaultWatchesFile = "watches.yaml"
 
 // Watches scaffolds the watches.yaml file
 type Watches struct {
@@ -32,7 +33,7 @@ type Watches struct {
 // SetTemplateDefaults implements input.Template
 func (f *Watches) SetTemplateDefaults() error {
 	if f.Path == "" {
-		f.Path = defaultWatchesFile
+		f.Path = DefaultWatchesFile
 	}
 
 	f.TemplateBody = fmt.Sprintf(watchesTemplate,
@@ -48,10 +49,12 @@ type WatchesUpdater struct {
 	file.ResourceMixin
 
 	ChartPath string
+	// ConversionFile, if set, is added to the watch as its conversionFile.
+	ConversionFile string
 }
 
 func (*WatchesUpdater) GetPath() string {
-	return defaultWatchesFile
+	return DefaultWatchesFile
 }
 
 func (*WatchesUpdater) GetIfExistsAction() file.IfExistsAction {
@@ -64,7 +67,7 @@ const (
 
 func (f *WatchesUpdater) GetMarkers() []file.Marker {
 	return []file.Marker{
-		file.NewMarkerFor(defaultWatchesFile, watchMarker),
+		file.NewMarkerFor(DefaultWatchesFile, watchMarker),
 	}
 }
 
@@ -78,11 +81,14 @@ func (f *WatchesUpdater) GetCodeFragments() file.CodeFragmentsMap {
 
 	// Generate watch fragments
 	watches := make([]string, 0)
-	watches = append(watches,
-		fmt.Sprintf(watchFragment, f.Resource.Domain, f.Resource.Version, f.Resource.Kind, f.ChartPath))
+	watch := fmt.Sprintf(watchFragment, f.Resource.Domain, f.Resource.Version, f.Resource.Kind, f.ChartPath)
+	if f.ConversionFile != "" {
+		watch += fmt.Sprintf(conversionFileFragment, f.ConversionFile)
+	}
+	watches = append(watches, watch)
 
 	if len(watches) != 0 {
-		fragments[file.NewMarkerFor(defaultWatchesFile, watchMarker)] = watches
+		fragments[file.NewMarkerFor(DefaultWatchesFile, watchMarker)] = watches
 	}
 	return fragments
 }
@@ -93,6 +99,9 @@ const watchFragment = `- group: %s
   chart: %s
 `
 
+const conversionFileFragment = `  conversionFile: %s
+`
+
 const watchesTemplate = `# Use the 'create api' subcommand to add watches to this file.
 %s
 `
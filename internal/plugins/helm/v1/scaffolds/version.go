@@ -0,0 +1,141 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffolds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// watch is the subset of a watches file entry needed to add a version to
+// its kind.
+type watch struct {
+	Group          string `json:"group"`
+	Version        string `json:"version"`
+	Kind           string `json:"kind"`
+	Chart          string `json:"chart"`
+	ConversionFile string `json:"conversionFile,omitempty"`
+}
+
+// findWatch returns the watch of group and kind in the watches file at path.
+func findWatch(path, group, kind string) (*watch, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watches file: %v", err)
+	}
+	var watches []watch
+	if err := yaml.Unmarshal(b, &watches); err != nil {
+		return nil, fmt.Errorf("failed to parse watches file: %v", err)
+	}
+	for i := range watches {
+		if watches[i].Group == group && watches[i].Kind == kind {
+			return &watches[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no watch for kind %s.%s in %s", kind, group, path)
+}
+
+// setWatchConversionFile adds w's conversion file to its entry in the
+// watches file at path. The entry is edited in place, so that the file's
+// comments and scaffold markers are kept.
+func setWatchConversionFile(path string, w *watch) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read watches file: %v", err)
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+
+	// Find the entry's kind line, which the conversion file is added after.
+	start, kindLine := -1, -1
+	matches := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(line, "- ") {
+			start, matches, kindLine = i, 0, -1
+			trimmed = strings.TrimSpace(strings.TrimPrefix(line, "- "))
+		}
+		if start < 0 {
+			continue
+		}
+		switch trimmed {
+		case "group: " + w.Group, "version: " + w.Version:
+			matches++
+		case "kind: " + w.Kind:
+			matches++
+			kindLine = i
+		}
+		if matches == 3 && kindLine >= 0 {
+			entry := fmt.Sprintf("  conversionFile: %s\n", w.ConversionFile)
+			lines = append(lines[:kindLine+1], append([]string{entry}, lines[kindLine+1:]...)...)
+			return ioutil.WriteFile(path, []byte(strings.Join(lines, "")), 0644)
+		}
+	}
+	return fmt.Errorf("could not add conversionFile %s to the watch for kind %s.%s in %s: add it manually",
+		w.ConversionFile, w.Kind, w.Group, path)
+}
+
+// addCRDVersion adds version to the apiextensions.k8s.io/v1 CRD in the file
+// at path. The new version has the same schema as the CRD's storage version,
+// and is served but not stored.
+func addCRDVersion(path, version string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read CRD: %v", err)
+	}
+	crd := map[string]interface{}{}
+	if err := yaml.Unmarshal(b, &crd); err != nil {
+		return fmt.Errorf("failed to parse CRD %s: %v", path, err)
+	}
+	if crd["apiVersion"] != "apiextensions.k8s.io/v1" {
+		return fmt.Errorf("CRD %s must have apiVersion apiextensions.k8s.io/v1 to be converted by a webhook", path)
+	}
+
+	spec, _ := crd["spec"].(map[string]interface{})
+	versions, _ := spec["versions"].([]interface{})
+	var storage map[string]interface{}
+	for _, v := range versions {
+		v, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if v["name"] == version {
+			return fmt.Errorf("CRD %s already has version %s", path, version)
+		}
+		if v["storage"] == true {
+			storage = v
+		}
+	}
+	if storage == nil {
+		return fmt.Errorf("CRD %s has no storage version", path)
+	}
+
+	newVersion := make(map[string]interface{}, len(storage))
+	for k, v := range storage {
+		newVersion[k] = v
+	}
+	newVersion["name"] = version
+	newVersion["served"] = true
+	newVersion["storage"] = false
+	spec["versions"] = append(versions, newVersion)
+
+	out, err := yaml.Marshal(crd)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append([]byte("---\n"), out...), 0644)
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const testWatches = `# Use the 'create api' subcommand to add watches to this file.
+- group: cache.example.com
+  version: v1alpha1
+  kind: Memcached
+  chart: helm-charts/memcached
+- group: cache.example.com
+  version: v1alpha1
+  kind: Redis
+  chart: helm-charts/redis
+# +kubebuilder:scaffold:watch
+`
+
+const testCRD = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: memcacheds.cache.example.com
+spec:
+  group: cache.example.com
+  names:
+    kind: Memcached
+    listKind: MemcachedList
+    plural: memcacheds
+    singular: memcached
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+    served: true
+    storage: true
+    subresources:
+      status: {}
+`
+
+func writeTempFile(t *testing.T, contents string) string {
+	f, err := ioutil.TempFile("", "helm-scaffolds-*.yaml")
+	require.NoError(t, err)
+	_, err = f.WriteString(contents)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	return f.Name()
+}
+
+func TestFindWatch(t *testing.T) {
+	path := writeTempFile(t, testWatches)
+	defer os.Remove(path)
+
+	w, err := findWatch(path, "cache.example.com", "Redis")
+	require.NoError(t, err)
+	assert.Equal(t, &watch{Group: "cache.example.com", Version: "v1alpha1", Kind: "Redis", Chart: "helm-charts/redis"}, w)
+
+	_, err = findWatch(path, "cache.example.com", "Nginx")
+	assert.Error(t, err)
+}
+
+func TestSetWatchConversionFile(t *testing.T) {
+	path := writeTempFile(t, testWatches)
+	defer os.Remove(path)
+
+	w, err := findWatch(path, "cache.example.com", "Redis")
+	require.NoError(t, err)
+	w.ConversionFile = "helm-charts/redis/conversion.yaml"
+	require.NoError(t, setWatchConversionFile(path, w))
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# Use the 'create api' subcommand to add watches to this file.
+- group: cache.example.com
+  version: v1alpha1
+  kind: Memcached
+  chart: helm-charts/memcached
+- group: cache.example.com
+  version: v1alpha1
+  kind: Redis
+  conversionFile: helm-charts/redis/conversion.yaml
+  chart: helm-charts/redis
+# +kubebuilder:scaffold:watch
+`, string(b))
+
+	w.Kind = "Nginx"
+	assert.Error(t, setWatchConversionFile(path, w))
+}
+
+func TestAddCRDVersion(t *testing.T) {
+	path := writeTempFile(t, testCRD)
+	defer os.Remove(path)
+	require.NoError(t, addCRDVersion(path, "v1beta1"))
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	crd := map[string]interface{}{}
+	require.NoError(t, yaml.Unmarshal(b, &crd))
+	versions := crd["spec"].(map[string]interface{})["versions"].([]interface{})
+	require.Len(t, versions, 2)
+	assert.Equal(t, "v1alpha1", versions[0].(map[string]interface{})["name"])
+	assert.Equal(t, true, versions[0].(map[string]interface{})["storage"])
+	newVersion := versions[1].(map[string]interface{})
+	assert.Equal(t, "v1beta1", newVersion["name"])
+	assert.Equal(t, true, newVersion["served"])
+	assert.Equal(t, false, newVersion["storage"])
+	assert.Equal(t, versions[0].(map[string]interface{})["schema"], newVersion["schema"])
+
+	assert.EqualError(t, addCRDVersion(path, "v1beta1"), "CRD "+path+" already has version v1beta1")
+
+	v1beta1Path := writeTempFile(t, "apiVersion: apiextensions.k8s.io/v1beta1\nkind: CustomResourceDefinition\n")
+	defer os.Remove(v1beta1Path)
+	assert.Error(t, addCRDVersion(v1beta1Path, "v1beta1"))
+}
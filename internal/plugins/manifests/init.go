@@ -55,6 +55,8 @@ func initUpdateMakefile(cfg *config.Config, filePath string) error {
 		return fmt.Errorf("unsupported plugin key %q", cfg.Layout)
 	case projutil.OperatorTypeGo:
 		makefileBytes = append(makefileBytes, []byte(makefileBundleFragmentGo)...)
+	case projutil.OperatorTypeHelm:
+		makefileBytes = append(makefileBytes, []byte(makefileBundleFragmentHelm)...)
 	default:
 		makefileBytes = append(makefileBytes, []byte(makefileBundleFragmentNonGo)...)
 	}
@@ -100,6 +102,17 @@ bundle: kustomize
 	operator-sdk bundle validate ./bundle
 `
 
+	makefileBundleFragmentHelm = `
+# Generate bundle manifests and metadata, copy kuttl tests into the bundle, then validate generated files.
+.PHONY: bundle
+bundle: kustomize
+	operator-sdk generate kustomize manifests -q
+	cd config/manager && $(KUSTOMIZE) edit set image controller=$(IMG)
+	$(KUSTOMIZE) build config/manifests | operator-sdk generate bundle -q --overwrite --version $(VERSION) $(BUNDLE_METADATA_OPTS)
+	rm -rf bundle/tests/scorecard/kuttl && mkdir -p bundle/tests/scorecard && cp -r test/kuttl bundle/tests/scorecard/kuttl
+	operator-sdk bundle validate ./bundle
+`
+
 	makefileBundleBuildFragment = `
 # Build the bundle image.
 .PHONY: bundle-build
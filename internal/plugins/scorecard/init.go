@@ -30,6 +30,7 @@ import (
 
 	"github.com/operator-framework/operator-sdk/internal/plugins/util/kustomize"
 	"github.com/operator-framework/operator-sdk/internal/scorecard"
+	"github.com/operator-framework/operator-sdk/internal/util/projutil"
 	"github.com/operator-framework/operator-sdk/internal/version"
 )
 
@@ -66,6 +67,10 @@ const (
 var defaultTestImageTag = fmt.Sprintf("quay.io/operator-framework/scorecard-test:%s",
 	strings.TrimSuffix(version.Version, "+git"))
 
+// defaultKuttlTestImageTag points to the latest-released kuttl test image.
+var defaultKuttlTestImageTag = fmt.Sprintf("quay.io/operator-framework/scorecard-test-kuttl:%s",
+	strings.TrimSuffix(version.Version, "+git"))
+
 // defaultDir is the default directory in which to generate kustomize bases and the kustomization.yaml.
 var defaultDir = filepath.Join("config", "scorecard")
 
@@ -76,7 +81,24 @@ func RunInit(cfg *config.Config) error {
 		return nil
 	}
 
-	return generate(defaultTestImageTag, defaultDir)
+	// Helm and Ansible projects have tests scaffolded for each API, which are run in their own stage.
+	var stages []stagePatch
+	switch projutil.PluginKeyToOperatorType(cfg.Layout) {
+	case projutil.OperatorTypeHelm:
+		stages = append(stages, newKuttlStagePatch(defaultKuttlTestImageTag))
+	case projutil.OperatorTypeAnsible:
+		stages = append(stages, newMoleculeStagePatch(MoleculeTestImage(cfg.ProjectName)))
+	}
+
+	return generate(defaultTestImageTag, defaultDir, stages...)
+}
+
+// MoleculeTestImage returns the default tag of an Ansible project's molecule scorecard test image, the
+// default SCORECARD_TEST_IMG in the project's Makefile. The tag is fully qualified so that it names the
+// image pushed to a registry, from which the scorecard test pod pulls it; quay.io/example is a placeholder
+// for the project's repository.
+func MoleculeTestImage(projectName string) string {
+	return fmt.Sprintf("quay.io/example/%s-molecule-test:latest", projectName)
 }
 
 // scorecardKustomizationValues holds data required to generate a scorecard's kustomization.yaml.
@@ -97,9 +119,10 @@ type patchTarget struct {
 	Name string
 }
 
-// generate scaffolds kustomize bundle bases and a kustomization.yaml.
+// generate scaffolds kustomize bundle bases and a kustomization.yaml. Each of stages is written
+// as a patch that appends a stage to the componentconfig, after the default stage.
 // TODO(estroz): refactor this to be testable (in-mem fs) and easier to read.
-func generate(testImageTag, outputDir string) error {
+func generate(testImageTag, outputDir string, stages ...stagePatch) error {
 
 	kustomizationValues := scorecardKustomizationValues{}
 
@@ -161,6 +184,22 @@ func generate(testImageTag, outputDir string) error {
 		Target: scorecardConfigTarget,
 	})
 
+	// Additional stage patches.
+	for _, stage := range stages {
+		b, err = yaml.Marshal(stage.patch)
+		if err != nil {
+			return fmt.Errorf("error marshaling %s patch config: %v", stage.name, err)
+		}
+		stagePatchFileName := fmt.Sprintf("%s.%s", stage.name, scorecard.ConfigFileName)
+		if err := ioutil.WriteFile(filepath.Join(patchesDir, stagePatchFileName), b, 0666); err != nil {
+			return fmt.Errorf("error writing %s scorecard config patch: %v", stage.name, err)
+		}
+		kustomizationValues.JSONPatches = append(kustomizationValues.JSONPatches, kustomizationJSON6902Patch{
+			Path:   filepath.Join("patches", stagePatchFileName),
+			Target: scorecardConfigTarget,
+		})
+	}
+
 	// Write a kustomization.yaml to outputDir if one does not exist.
 	t, err := template.New("scorecard").Parse(scorecardKustomizationTemplate)
 	if err != nil {
@@ -271,3 +310,54 @@ func makeDefaultOLMTestConfigs(testImageTag string) (cfgs []v1alpha3.TestConfigu
 
 	return cfgs
 }
+
+// stagePatchObject is a JSON 6902 patch object that adds a stage to the scorecard's componentconfig.
+type stagePatchObject struct {
+	Op    string                      `json:"op"`
+	Path  string                      `json:"path"`
+	Value v1alpha3.StageConfiguration `json:"value"`
+}
+
+// stagePatch holds a patch that adds a stage, written to a file prefixed with name.
+type stagePatch struct {
+	name  string
+	patch []stagePatchObject
+}
+
+// newStagePatch returns a stagePatch that appends a stage running tests to the componentconfig base.
+// These tests create and delete cluster resources, so they are run after, and not in parallel with,
+// the default stage.
+func newStagePatch(name string, tests ...v1alpha3.TestConfiguration) stagePatch {
+	return stagePatch{
+		name: name,
+		patch: []stagePatchObject{
+			{
+				Op:    "add",
+				Path:  "/stages/-",
+				Value: v1alpha3.StageConfiguration{Tests: tests},
+			},
+		},
+	}
+}
+
+// newKuttlStagePatch returns a stage patch running a Helm project's kuttl tests.
+func newKuttlStagePatch(kuttlTestImageTag string) stagePatch {
+	return newStagePatch("kuttl", v1alpha3.TestConfiguration{
+		Image: kuttlTestImageTag,
+		Labels: map[string]string{
+			"suite": "kuttlsuite",
+			"test":  "kuttltest",
+		},
+	})
+}
+
+// newMoleculeStagePatch returns a stage patch running an Ansible project's molecule verify scenario.
+func newMoleculeStagePatch(moleculeTestImageTag string) stagePatch {
+	return newStagePatch("molecule", v1alpha3.TestConfiguration{
+		Image: moleculeTestImageTag,
+		Labels: map[string]string{
+			"suite": "molecule",
+			"test":  "molecule-verify-test",
+		},
+	})
+}
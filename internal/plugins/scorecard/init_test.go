@@ -0,0 +1,130 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/plugins/util/kustomize"
+)
+
+var _ = Describe("Scorecard config scaffolding", func() {
+	Describe("MoleculeTestImage", func() {
+		It("returns a fully qualified image", func() {
+			Expect(MoleculeTestImage("memcached-operator")).To(
+				Equal("quay.io/example/memcached-operator-molecule-test:latest"))
+		})
+	})
+
+	Describe("stage patches", func() {
+		It("appends a sequential kuttl stage", func() {
+			p := newKuttlStagePatch("quay.io/operator-framework/scorecard-test-kuttl:v1.0.0")
+			Expect(p.name).To(Equal("kuttl"))
+			Expect(p.patch).To(Equal([]stagePatchObject{{
+				Op:   "add",
+				Path: "/stages/-",
+				Value: v1alpha3.StageConfiguration{Tests: []v1alpha3.TestConfiguration{{
+					Image:  "quay.io/operator-framework/scorecard-test-kuttl:v1.0.0",
+					Labels: map[string]string{"suite": "kuttlsuite", "test": "kuttltest"},
+				}}},
+			}}))
+		})
+
+		It("appends a sequential molecule stage", func() {
+			p := newMoleculeStagePatch(MoleculeTestImage("memcached-operator"))
+			Expect(p.name).To(Equal("molecule"))
+			Expect(p.patch).To(Equal([]stagePatchObject{{
+				Op:   "add",
+				Path: "/stages/-",
+				Value: v1alpha3.StageConfiguration{Tests: []v1alpha3.TestConfiguration{{
+					Image:  "quay.io/example/memcached-operator-molecule-test:latest",
+					Labels: map[string]string{"suite": "molecule", "test": "molecule-verify-test"},
+				}}},
+			}}))
+		})
+	})
+
+	Describe("generate", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "scorecard-config-")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		readFile := func(path ...string) string {
+			b, err := ioutil.ReadFile(filepath.Join(append([]string{dir}, path...)...))
+			Expect(err).NotTo(HaveOccurred())
+			return string(b)
+		}
+
+		It("writes only the default stage's patches without stages", func() {
+			Expect(generate(defaultTestImageTag, dir)).To(Succeed())
+
+			infos, err := ioutil.ReadDir(filepath.Join(dir, "patches"))
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, info := range infos {
+				names = append(names, info.Name())
+			}
+			Expect(names).To(ConsistOf("basic.config.yaml", "olm.config.yaml"))
+		})
+
+		It("writes stage patches and adds them to the kustomization after the default stage's", func() {
+			stages := []stagePatch{newMoleculeStagePatch(MoleculeTestImage("memcached-operator"))}
+			Expect(generate(defaultTestImageTag, dir, stages...)).To(Succeed())
+
+			var patch []stagePatchObject
+			Expect(yaml.Unmarshal([]byte(readFile("patches", "molecule.config.yaml")), &patch)).To(Succeed())
+			Expect(patch).To(Equal(stages[0].patch))
+
+			Expect(readFile(kustomize.File)).To(Equal(`resources:
+- bases/config.yaml
+patchesJson6902:
+- path: patches/basic.config.yaml
+  target:
+    group: scorecard.operatorframework.io
+    version: v1alpha3
+    kind: Configuration
+    name: config
+- path: patches/olm.config.yaml
+  target:
+    group: scorecard.operatorframework.io
+    version: v1alpha3
+    kind: Configuration
+    name: config
+- path: patches/molecule.config.yaml
+  target:
+    group: scorecard.operatorframework.io
+    version: v1alpha3
+    kind: Configuration
+    name: config
+# +kubebuilder:scaffold:patchesJson6902
+`))
+		})
+	})
+})
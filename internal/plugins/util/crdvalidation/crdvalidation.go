@@ -0,0 +1,84 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package crdvalidation reads CEL validation rules to add to scaffolded CRDs
+// as x-kubernetes-validations.
+package crdvalidation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Rule is a CEL validation rule, as found in a CRD schema's x-kubernetes-validations list.
+type Rule struct {
+	// Rule is the CEL expression to evaluate, ex. "self.minReplicas <= self.maxReplicas".
+	Rule string `json:"rule"`
+	// Message is returned to the client when Rule evaluates to false.
+	Message string `json:"message,omitempty"`
+	// MessageExpression is a CEL expression that evaluates to Message.
+	MessageExpression string `json:"messageExpression,omitempty"`
+	// Reason is a machine-readable reason for the failure, ex. "FieldValueInvalid".
+	Reason string `json:"reason,omitempty"`
+	// FieldPath is the path of the field the failure is reported for, ex. ".maxReplicas".
+	FieldPath string `json:"fieldPath,omitempty"`
+}
+
+// ReadRules reads a YAML or JSON list of Rules from the file at path.
+func ReadRules(path string) ([]Rule, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading validation rules: %v", err)
+	}
+	rules := []Rule{}
+	if err := yaml.UnmarshalStrict(b, &rules); err != nil {
+		return nil, fmt.Errorf("error parsing validation rules in %s: %v", path, err)
+	}
+	for i, r := range rules {
+		if strings.TrimSpace(r.Rule) == "" {
+			return nil, fmt.Errorf("validation rule %d in %s has an empty rule", i, path)
+		}
+	}
+	return rules, nil
+}
+
+// SchemaFragment returns rules as an x-kubernetes-validations schema key, indented by indent.
+// Template actions in rules are escaped so the fragment can be embedded in a text/template.
+// An empty string is returned if rules is empty.
+func SchemaFragment(rules []Rule, indent string) (string, error) {
+	if len(rules) == 0 {
+		return "", nil
+	}
+	b, err := yaml.Marshal(map[string][]Rule{"x-kubernetes-validations": rules})
+	if err != nil {
+		return "", err
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	sb := strings.Builder{}
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		sb.WriteString(indent + line)
+	}
+	return escapeTemplateActions(sb.String()), nil
+}
+
+// escapeTemplateActions replaces text/template delimiters in s with actions that print them.
+func escapeTemplateActions(s string) string {
+	return strings.NewReplacer("{{", `{{ "{{" }}`, "}}", `{{ "}}" }}`).Replace(s)
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package crdvalidation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crdvalidation")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		name      string
+		contents  string
+		expRules  []Rule
+		expectErr bool
+	}{
+		{
+			name: "valid rules",
+			contents: `- rule: self.minReplicas <= self.maxReplicas
+  message: minReplicas must not exceed maxReplicas
+- rule: has(self.image)
+  fieldPath: .image
+`,
+			expRules: []Rule{
+				{Rule: "self.minReplicas <= self.maxReplicas", Message: "minReplicas must not exceed maxReplicas"},
+				{Rule: "has(self.image)", FieldPath: ".image"},
+			},
+		},
+		{
+			name:      "empty rule",
+			contents:  "- message: no rule\n",
+			expectErr: true,
+		},
+		{
+			name:      "unknown field",
+			contents:  "- rule: has(self.image)\n  severity: high\n",
+			expectErr: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("rules-%d.yaml", i))
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.contents), 0644))
+
+			rules, err := ReadRules(path)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expRules, rules)
+		})
+	}
+}
+
+func TestSchemaFragment(t *testing.T) {
+	fragment, err := SchemaFragment(nil, "  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "", fragment)
+
+	rules := []Rule{
+		{Rule: "self.minReplicas <= self.maxReplicas", Message: "minReplicas must not exceed maxReplicas"},
+	}
+	fragment, err = SchemaFragment(rules, "  ")
+	assert.NoError(t, err)
+	assert.Equal(t, `  x-kubernetes-validations:
+  - message: minReplicas must not exceed maxReplicas
+    rule: self.minReplicas <= self.maxReplicas
+`, fragment)
+}
+
+func TestEscapeTemplateActions(t *testing.T) {
+	assert.Equal(t, `self.name != '{{ "{{" }}{{ "}}" }}'`, escapeTemplateActions("self.name != '{{}}'"))
+}
@@ -0,0 +1,230 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundlediff compares two operator bundles, reporting changes to
+// their CSVs, CRD schemas, RBAC, and images that affect upgrades between them.
+package bundlediff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	registrybundle "github.com/operator-framework/operator-registry/pkg/lib/bundle"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/operator-framework/operator-sdk/internal/generate/rbacdiff"
+	internalregistry "github.com/operator-framework/operator-sdk/internal/registry"
+)
+
+// Change is a change to a CSV field or image reference.
+type Change struct {
+	// Name identifies the changed field or image.
+	Name string
+	// Old and New are the values in the old and new bundles. Old is empty if
+	// the value was added, and New is empty if it was removed.
+	Old string
+	New string
+}
+
+// Report is the difference between an old and a new bundle.
+type Report struct {
+	// CSV are changes to upgrade-relevant CSV fields.
+	CSV []Change
+	// CRDs are changes to CRDs and their schemas.
+	CRDs []SchemaChange
+	// ClusterPermissions and Permissions are the RBAC changes in the CSV's
+	// cluster permissions and permissions. Missing permissions were added by
+	// the new bundle, and excess permissions were removed.
+	ClusterPermissions rbacdiff.Diff
+	Permissions        rbacdiff.Diff
+	// Images are changes to images of the CSV's deployments and related images.
+	Images []Change
+}
+
+// IsEmpty returns true if r has no changes.
+func (r Report) IsEmpty() bool {
+	return len(r.CSV) == 0 && len(r.CRDs) == 0 && r.ClusterPermissions.IsEmpty() &&
+		r.Permissions.IsEmpty() && len(r.Images) == 0
+}
+
+// HasBreakingChanges returns true if any CRD change in r is breaking.
+func (r Report) HasBreakingChanges() bool {
+	for _, c := range r.CRDs {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadBundle loads the bundle in dir, which is either a bundle's root
+// directory containing bundle metadata or its manifests directory.
+func LoadBundle(dir string) (*apimanifests.Bundle, error) {
+	manifestsDir := dir
+	if metadata, _, err := internalregistry.FindBundleMetadata(dir); err == nil {
+		manifestsDirName, hasLabel := metadata.GetManifestsDir()
+		if !hasLabel {
+			manifestsDirName = registrybundle.ManifestsDir
+		}
+		manifestsDir = filepath.Join(dir, manifestsDirName)
+	} else if info, err := os.Stat(filepath.Join(dir, registrybundle.ManifestsDir)); err == nil && info.IsDir() {
+		manifestsDir = filepath.Join(dir, registrybundle.ManifestsDir)
+	}
+	bundle, err := apimanifests.GetBundleFromDir(manifestsDir)
+	if err != nil {
+		return nil, fmt.Errorf("error loading bundle from %s: %v", manifestsDir, err)
+	}
+	if bundle.CSV == nil {
+		return nil, fmt.Errorf("no ClusterServiceVersion found in %s", manifestsDir)
+	}
+	return bundle, nil
+}
+
+// Compare returns the changes from bundle oldBundle to newBundle.
+func Compare(oldBundle, newBundle *apimanifests.Bundle) (Report, error) {
+	r := Report{CSV: compareCSVFields(oldBundle.CSV, newBundle.CSV)}
+
+	oldImages, err := bundleImages(oldBundle)
+	if err != nil {
+		return r, err
+	}
+	newImages, err := bundleImages(newBundle)
+	if err != nil {
+		return r, err
+	}
+	r.Images = compareImages(oldImages, newImages)
+
+	oldStrategy := oldBundle.CSV.Spec.InstallStrategy.StrategySpec
+	newStrategy := newBundle.CSV.Spec.InstallStrategy.StrategySpec
+	r.ClusterPermissions = rbacdiff.Compare(permissionRules(newStrategy.ClusterPermissions),
+		permissionRules(oldStrategy.ClusterPermissions))
+	r.Permissions = rbacdiff.Compare(permissionRules(newStrategy.Permissions),
+		permissionRules(oldStrategy.Permissions))
+
+	r.CRDs, err = CompareCRDs(oldBundle, newBundle)
+	return r, err
+}
+
+// compareCSVFields returns changes to CSV fields that affect upgrades.
+func compareCSVFields(oldCSV, newCSV *v1alpha1.ClusterServiceVersion) (changes []Change) {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"metadata.name", oldCSV.GetName(), newCSV.GetName()},
+		{"spec.version", oldCSV.Spec.Version.String(), newCSV.Spec.Version.String()},
+		{"spec.replaces", oldCSV.Spec.Replaces, newCSV.Spec.Replaces},
+		{"spec.skips", strings.Join(oldCSV.Spec.Skips, ", "), strings.Join(newCSV.Spec.Skips, ", ")},
+		{"olm.skipRange", oldCSV.GetAnnotations()["olm.skipRange"], newCSV.GetAnnotations()["olm.skipRange"]},
+		{"spec.minKubeVersion", oldCSV.Spec.MinKubeVersion, newCSV.Spec.MinKubeVersion},
+		{"spec.installModes", installModes(oldCSV), installModes(newCSV)},
+		{"spec.customresourcedefinitions.owned", crdDescriptions(oldCSV.Spec.CustomResourceDefinitions.Owned),
+			crdDescriptions(newCSV.Spec.CustomResourceDefinitions.Owned)},
+		{"spec.customresourcedefinitions.required", crdDescriptions(oldCSV.Spec.CustomResourceDefinitions.Required),
+			crdDescriptions(newCSV.Spec.CustomResourceDefinitions.Required)},
+	}
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, Change{Name: f.name, Old: f.old, New: f.new})
+		}
+	}
+	return changes
+}
+
+// installModes returns csv's supported install mode types.
+func installModes(csv *v1alpha1.ClusterServiceVersion) string {
+	var modes []string
+	for _, m := range csv.Spec.InstallModes {
+		if m.Supported {
+			modes = append(modes, string(m.Type))
+		}
+	}
+	sort.Strings(modes)
+	return strings.Join(modes, ", ")
+}
+
+// crdDescriptions returns the sorted "<name>/<version>" of each description.
+func crdDescriptions(descs []v1alpha1.CRDDescription) string {
+	var names []string
+	for _, d := range descs {
+		names = append(names, d.Name+"/"+d.Version)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// compareImages returns changes from oldImages to newImages, which are keyed by name.
+func compareImages(oldImages, newImages map[string]string) []Change {
+	var changes []Change
+	for name, oldImage := range oldImages {
+		if newImage := newImages[name]; newImage != oldImage {
+			changes = append(changes, Change{Name: name, Old: oldImage, New: newImage})
+		}
+	}
+	for name, newImage := range newImages {
+		if _, ok := oldImages[name]; !ok {
+			changes = append(changes, Change{Name: name, New: newImage})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// bundleImages returns the images of bundle's CSV keyed by "<deployment>/<container>"
+// for deployment containers and "relatedImages/<name>" for related images.
+func bundleImages(bundle *apimanifests.Bundle) (map[string]string, error) {
+	images := map[string]string{}
+	for _, dep := range bundle.CSV.Spec.InstallStrategy.StrategySpec.DeploymentSpecs {
+		podSpec := dep.Spec.Template.Spec
+		for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+			for _, c := range containers {
+				images[dep.Name+"/"+c.Name] = c.Image
+			}
+		}
+	}
+
+	// Read relatedImages from the unstructured CSV since not all CSV API versions define the field.
+	for _, obj := range bundle.Objects {
+		if obj.GetKind() != v1alpha1.ClusterServiceVersionKind {
+			continue
+		}
+		relatedImages, _, err := unstructured.NestedSlice(obj.Object, "spec", "relatedImages")
+		if err != nil {
+			return nil, fmt.Errorf("error reading relatedImages: %v", err)
+		}
+		for _, ri := range relatedImages {
+			if riMap, ok := ri.(map[string]interface{}); ok {
+				name, _ := riMap["name"].(string)
+				image, _ := riMap["image"].(string)
+				images["relatedImages/"+name] = image
+			}
+		}
+	}
+	return images, nil
+}
+
+// permissionRules returns the rules of all perms.
+func permissionRules(perms []v1alpha1.StrategyDeploymentPermissions) (rules []rbacv1.PolicyRule) {
+	for _, perm := range perms {
+		rules = append(rules, perm.Rules...)
+	}
+	return rules
+}
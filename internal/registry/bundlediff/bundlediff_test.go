@@ -0,0 +1,225 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlediff
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	"github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/generate/rbacdiff"
+)
+
+func TestBundleDiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bundle Diff Suite")
+}
+
+func mustCRD(s string) crd {
+	c := crd{}
+	ExpectWithOffset(1, yaml.Unmarshal([]byte(s), &c)).To(Succeed())
+	return c
+}
+
+const oldCRD = `
+spec:
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              size:
+                type: integer
+              mode:
+                type: string
+                enum: [fast, slow]
+              nodes:
+                type: array
+                items:
+                  type: string
+`
+
+var _ = Describe("compareCRDs", func() {
+	It("returns no changes for equal CRDs", func() {
+		crds := map[string]crd{"memcacheds.cache.example.com": mustCRD(oldCRD)}
+		Expect(compareCRDs(crds, crds)).To(BeEmpty())
+	})
+
+	It("classifies schema changes", func() {
+		newCRD := mustCRD(`
+spec:
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: false
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            required: [replicas]
+            properties:
+              mode:
+                type: string
+                enum: [fast, medium]
+              nodes:
+                type: array
+                items:
+                  type: integer
+              replicas:
+                type: integer
+              labels:
+                type: object
+  - name: v1beta1
+    served: true
+    storage: true
+`)
+		name := "memcacheds.cache.example.com"
+		changes := compareCRDs(map[string]crd{name: mustCRD(oldCRD)}, map[string]crd{name: newCRD})
+		Expect(changes).To(Equal([]SchemaChange{
+			{CRD: name, Version: "v1alpha1", Description: "no longer the storage version"},
+			{CRD: name, Version: "v1alpha1", Path: ".spec.labels", Description: "field added"},
+			{CRD: name, Version: "v1alpha1", Path: ".spec.mode", Description: `enum values removed: "slow"`, Breaking: true},
+			{CRD: name, Version: "v1alpha1", Path: ".spec.mode", Description: `enum values added: "medium"`},
+			{CRD: name, Version: "v1alpha1", Path: ".spec.nodes[]", Description: `type changed from "string" to "integer"`,
+				Breaking: true},
+			{CRD: name, Version: "v1alpha1", Path: ".spec.replicas", Description: "required field added", Breaking: true},
+			{CRD: name, Version: "v1alpha1", Path: ".spec.size", Description: "field removed", Breaking: true},
+			{CRD: name, Version: "v1beta1", Description: "version added"},
+		}))
+	})
+
+	It("reports removed CRDs and versions as breaking", func() {
+		changes := compareCRDs(map[string]crd{"a.example.com": mustCRD(oldCRD), "b.example.com": mustCRD(oldCRD)},
+			map[string]crd{"b.example.com": mustCRD(`
+spec:
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+`)})
+		Expect(changes).To(Equal([]SchemaChange{
+			{CRD: "a.example.com", Description: "CRD removed", Breaking: true},
+			{CRD: "b.example.com", Description: "scope changed from Namespaced to Cluster", Breaking: true},
+			{CRD: "b.example.com", Version: "v1", Description: "version added"},
+			{CRD: "b.example.com", Version: "v1alpha1", Description: "version removed", Breaking: true},
+		}))
+	})
+
+	It("classifies validation constraint changes", func() {
+		one, five, ten := 1.0, 5.0, 10.0
+		oldSchema := &schema{Minimum: &one, Maximum: &ten, MaxLength: &ten, MinItems: &one, Pattern: "^[a-z]+$"}
+		newSchema := &schema{Minimum: &five, Maximum: &ten, MaxLength: &five, MaxItems: &ten,
+			Pattern: "^[a-z]{3,}$", ExclusiveMaximum: true}
+		Expect(compareValidation("a.example.com", "v1", ".spec.x", oldSchema, newSchema)).To(Equal([]SchemaChange{
+			{CRD: "a.example.com", Version: "v1", Path: ".spec.x", Description: "minimum changed from 1 to 5", Breaking: true},
+			{CRD: "a.example.com", Version: "v1", Path: ".spec.x", Description: "maxLength changed from 10 to 5", Breaking: true},
+			{CRD: "a.example.com", Version: "v1", Path: ".spec.x", Description: "minItems removed"},
+			{CRD: "a.example.com", Version: "v1", Path: ".spec.x", Description: "maxItems added: 10", Breaking: true},
+			{CRD: "a.example.com", Version: "v1", Path: ".spec.x", Description: "maximum is now exclusive", Breaking: true},
+			{CRD: "a.example.com", Version: "v1", Path: ".spec.x",
+				Description: `pattern changed from "^[a-z]+$" to "^[a-z]{3,}$"`, Breaking: true},
+		}))
+		Expect(compareValidation("a.example.com", "v1", ".spec.x", newSchema, oldSchema)).To(ContainElement(
+			SchemaChange{CRD: "a.example.com", Version: "v1", Path: ".spec.x", Description: "minimum changed from 5 to 1"}))
+	})
+
+	It("uses the v1beta1 validation schema for versions without a schema", func() {
+		c := mustCRD(`
+spec:
+  scope: Namespaced
+  validation:
+    openAPIV3Schema:
+      type: object
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+`)
+		Expect(c.versions()["v1alpha1"].schema).To(Equal(&schema{Type: "object"}))
+	})
+})
+
+var _ = Describe("Compare", func() {
+	newBundle := func(version, image string, rules []rbacv1.PolicyRule) *apimanifests.Bundle {
+		csv := &v1alpha1.ClusterServiceVersion{}
+		csv.SetName("memcached-operator.v" + version)
+		csv.Spec.InstallStrategy.StrategySpec = v1alpha1.StrategyDetailsDeployment{
+			DeploymentSpecs: []v1alpha1.StrategyDeploymentSpec{{
+				Name: "memcached-operator-controller-manager",
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "manager", Image: image}},
+				}}},
+			}},
+			ClusterPermissions: []v1alpha1.StrategyDeploymentPermissions{{ServiceAccountName: "default", Rules: rules}},
+		}
+		return &apimanifests.Bundle{CSV: csv}
+	}
+
+	It("reports CSV, RBAC, and image changes", func() {
+		rule := rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get"}}
+		newRule := rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"list"}}
+		oldB := newBundle("0.0.1", "quay.io/example/memcached-operator:v0.0.1", []rbacv1.PolicyRule{rule})
+		newB := newBundle("0.0.2", "quay.io/example/memcached-operator:v0.0.2", []rbacv1.PolicyRule{newRule})
+		newB.CSV.Spec.Replaces = "memcached-operator.v0.0.1"
+
+		r, err := Compare(oldB, newB)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.CSV).To(Equal([]Change{
+			{Name: "metadata.name", Old: "memcached-operator.v0.0.1", New: "memcached-operator.v0.0.2"},
+			{Name: "spec.replaces", New: "memcached-operator.v0.0.1"},
+		}))
+		Expect(r.ClusterPermissions).To(Equal(rbacdiff.Diff{
+			Missing: []rbacdiff.Permission{{APIGroup: "apps", Resource: "deployments", Verb: "list"}},
+			Excess:  []rbacdiff.Permission{{APIGroup: "apps", Resource: "deployments", Verb: "get"}},
+		}))
+		Expect(r.Images).To(Equal([]Change{{
+			Name: "memcached-operator-controller-manager/manager",
+			Old:  "quay.io/example/memcached-operator:v0.0.1",
+			New:  "quay.io/example/memcached-operator:v0.0.2",
+		}}))
+		Expect(r.HasBreakingChanges()).To(BeFalse())
+
+		out := &bytes.Buffer{}
+		r.WriteText(out)
+		Expect(out.String()).To(ContainSubstring(`spec.replaces: added "memcached-operator.v0.0.1"`))
+		Expect(out.String()).To(ContainSubstring("  + list deployments.apps\n  - get deployments.apps\n"))
+	})
+
+	It("reports no changes for equal bundles", func() {
+		b := newBundle("0.0.1", "quay.io/example/memcached-operator:v0.0.1", nil)
+		r, err := Compare(b, b)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.IsEmpty()).To(BeTrue())
+	})
+})
@@ -0,0 +1,356 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlediff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// SchemaChange is a change to a CRD, one of its versions, or a field of a
+// version's schema.
+type SchemaChange struct {
+	// CRD is the name of the changed CRD.
+	CRD string
+	// Version is the changed version, if any.
+	Version string
+	// Path is the changed field's path in the version's schema, ex. ".spec.size".
+	Path string
+	// Description describes the change.
+	Description string
+	// Breaking is true if existing objects or clients may be incompatible
+	// with the change.
+	Breaking bool
+}
+
+func (c SchemaChange) String() string {
+	s := c.CRD
+	if c.Version != "" {
+		s += " " + c.Version
+	}
+	if c.Path != "" {
+		s += " " + c.Path
+	}
+	return s + ": " + c.Description
+}
+
+// crd holds the fields of v1 and v1beta1 CRDs that are compared.
+type crd struct {
+	Spec struct {
+		Scope string `json:"scope"`
+		// Validation is the schema of all versions of a v1beta1 CRD.
+		Validation *crdValidation `json:"validation,omitempty"`
+		Versions   []struct {
+			Name    string         `json:"name"`
+			Served  bool           `json:"served"`
+			Storage bool           `json:"storage"`
+			Schema  *crdValidation `json:"schema,omitempty"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+type crdValidation struct {
+	OpenAPIV3Schema *schema `json:"openAPIV3Schema,omitempty"`
+}
+
+// schema holds the fields of a JSON schema that are compared.
+type schema struct {
+	Type       string             `json:"type,omitempty"`
+	Properties map[string]*schema `json:"properties,omitempty"`
+	Items      *schema            `json:"items,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Enum       []json.RawMessage  `json:"enum,omitempty"`
+	Pattern    string             `json:"pattern,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty"`
+	MinLength        *float64 `json:"minLength,omitempty"`
+	MaxLength        *float64 `json:"maxLength,omitempty"`
+	MinItems         *float64 `json:"minItems,omitempty"`
+	MaxItems         *float64 `json:"maxItems,omitempty"`
+}
+
+// crdVersion is a version of a CRD.
+type crdVersion struct {
+	served  bool
+	storage bool
+	schema  *schema
+}
+
+// versions returns c's versions keyed by name.
+func (c crd) versions() map[string]crdVersion {
+	versions := map[string]crdVersion{}
+	for _, v := range c.Spec.Versions {
+		cv := crdVersion{served: v.Served, storage: v.Storage}
+		if v.Schema != nil {
+			cv.schema = v.Schema.OpenAPIV3Schema
+		} else if c.Spec.Validation != nil {
+			cv.schema = c.Spec.Validation.OpenAPIV3Schema
+		}
+		versions[v.Name] = cv
+	}
+	return versions
+}
+
+// bundleCRDs returns bundle's v1 and v1beta1 CRDs keyed by name.
+func bundleCRDs(bundle *apimanifests.Bundle) (map[string]crd, error) {
+	crds := map[string]crd{}
+	var objs []interface{}
+	for _, c := range bundle.V1CRDs {
+		objs = append(objs, c)
+	}
+	for _, c := range bundle.V1beta1CRDs {
+		objs = append(objs, c)
+	}
+	for _, obj := range objs {
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		var meta struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		}
+		c := crd{}
+		if err := json.Unmarshal(b, &meta); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &c); err != nil {
+			return nil, fmt.Errorf("error reading CRD %s: %v", meta.Metadata.Name, err)
+		}
+		crds[meta.Metadata.Name] = c
+	}
+	return crds, nil
+}
+
+// CompareCRDs returns the changes from oldBundle's CRDs to newBundle's CRDs,
+// sorted by CRD, version, and path.
+func CompareCRDs(oldBundle, newBundle *apimanifests.Bundle) ([]SchemaChange, error) {
+	oldCRDs, err := bundleCRDs(oldBundle)
+	if err != nil {
+		return nil, err
+	}
+	newCRDs, err := bundleCRDs(newBundle)
+	if err != nil {
+		return nil, err
+	}
+	return compareCRDs(oldCRDs, newCRDs), nil
+}
+
+// compareCRDs returns changes from oldCRDs to newCRDs, sorted by CRD, version, and path.
+func compareCRDs(oldCRDs, newCRDs map[string]crd) (changes []SchemaChange) {
+	for name, oldCRD := range oldCRDs {
+		newCRD, ok := newCRDs[name]
+		if !ok {
+			changes = append(changes, SchemaChange{CRD: name, Description: "CRD removed", Breaking: true})
+			continue
+		}
+		if oldCRD.Spec.Scope != newCRD.Spec.Scope {
+			changes = append(changes, SchemaChange{CRD: name, Breaking: true,
+				Description: fmt.Sprintf("scope changed from %s to %s", oldCRD.Spec.Scope, newCRD.Spec.Scope)})
+		}
+		changes = append(changes, compareVersions(name, oldCRD.versions(), newCRD.versions())...)
+	}
+	for name := range newCRDs {
+		if _, ok := oldCRDs[name]; !ok {
+			changes = append(changes, SchemaChange{CRD: name, Description: "CRD added"})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].CRD != changes[j].CRD {
+			return changes[i].CRD < changes[j].CRD
+		}
+		if changes[i].Version != changes[j].Version {
+			return changes[i].Version < changes[j].Version
+		}
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// compareVersions returns changes from oldVersions to newVersions of CRD name.
+func compareVersions(name string, oldVersions, newVersions map[string]crdVersion) (changes []SchemaChange) {
+	for v, oldVersion := range oldVersions {
+		newVersion, ok := newVersions[v]
+		switch {
+		case !ok:
+			changes = append(changes, SchemaChange{CRD: name, Version: v, Description: "version removed",
+				Breaking: oldVersion.served || oldVersion.storage})
+			continue
+		case oldVersion.served && !newVersion.served:
+			changes = append(changes, SchemaChange{CRD: name, Version: v, Description: "version no longer served",
+				Breaking: true})
+		case !oldVersion.served && newVersion.served:
+			changes = append(changes, SchemaChange{CRD: name, Version: v, Description: "version now served"})
+		}
+		if oldVersion.storage != newVersion.storage {
+			desc := "storage version changed to " + v
+			if oldVersion.storage {
+				desc = "no longer the storage version"
+			}
+			changes = append(changes, SchemaChange{CRD: name, Version: v, Description: desc})
+		}
+		changes = append(changes, compareSchemas(name, v, "", oldVersion.schema, newVersion.schema)...)
+	}
+	for v := range newVersions {
+		if _, ok := oldVersions[v]; !ok {
+			changes = append(changes, SchemaChange{CRD: name, Version: v, Description: "version added"})
+		}
+	}
+	return changes
+}
+
+// compareSchemas returns changes from oldSchema to newSchema of the field at path.
+func compareSchemas(name, version, path string, oldSchema, newSchema *schema) (changes []SchemaChange) {
+	change := func(desc string, breaking bool) {
+		changes = append(changes, SchemaChange{CRD: name, Version: version, Path: path, Description: desc,
+			Breaking: breaking})
+	}
+	switch {
+	case oldSchema == nil && newSchema == nil:
+		return nil
+	case oldSchema == nil:
+		change("schema added", true)
+		return changes
+	case newSchema == nil:
+		change("schema removed", false)
+		return changes
+	}
+
+	if oldSchema.Type != newSchema.Type {
+		change(fmt.Sprintf("type changed from %q to %q", oldSchema.Type, newSchema.Type), true)
+		return changes
+	}
+
+	oldEnum, newEnum := enumValues(oldSchema.Enum), enumValues(newSchema.Enum)
+	switch {
+	case oldEnum.Len() == 0 && newEnum.Len() != 0:
+		change("enum added: "+strings.Join(newEnum.List(), ", "), true)
+	case oldEnum.Len() != 0 && newEnum.Len() != 0:
+		if removed := oldEnum.Difference(newEnum); removed.Len() != 0 {
+			change("enum values removed: "+strings.Join(removed.List(), ", "), true)
+		}
+		if added := newEnum.Difference(oldEnum); added.Len() != 0 {
+			change("enum values added: "+strings.Join(added.List(), ", "), false)
+		}
+	case oldEnum.Len() != 0:
+		change("enum removed", false)
+	}
+
+	changes = append(changes, compareValidation(name, version, path, oldSchema, newSchema)...)
+
+	oldRequired, newRequired := sets.NewString(oldSchema.Required...), sets.NewString(newSchema.Required...)
+	for field, oldProp := range oldSchema.Properties {
+		fieldPath := path + "." + field
+		newProp, ok := newSchema.Properties[field]
+		if !ok {
+			changes = append(changes, SchemaChange{CRD: name, Version: version, Path: fieldPath,
+				Description: "field removed", Breaking: true})
+			continue
+		}
+		if !oldRequired.Has(field) && newRequired.Has(field) {
+			changes = append(changes, SchemaChange{CRD: name, Version: version, Path: fieldPath,
+				Description: "field now required", Breaking: true})
+		} else if oldRequired.Has(field) && !newRequired.Has(field) {
+			changes = append(changes, SchemaChange{CRD: name, Version: version, Path: fieldPath,
+				Description: "field no longer required"})
+		}
+		changes = append(changes, compareSchemas(name, version, fieldPath, oldProp, newProp)...)
+	}
+	for field := range newSchema.Properties {
+		if _, ok := oldSchema.Properties[field]; ok {
+			continue
+		}
+		desc, breaking := "field added", false
+		if newRequired.Has(field) {
+			desc, breaking = "required field added", true
+		}
+		changes = append(changes, SchemaChange{CRD: name, Version: version, Path: path + "." + field,
+			Description: desc, Breaking: breaking})
+	}
+
+	changes = append(changes, compareSchemas(name, version, path+"[]", oldSchema.Items, newSchema.Items)...)
+	return changes
+}
+
+// compareValidation returns changes from oldSchema to newSchema to the value
+// constraints of the field at path. Tightened constraints are breaking, since
+// existing objects may no longer be valid.
+func compareValidation(name, version, path string, oldSchema, newSchema *schema) (changes []SchemaChange) {
+	change := func(desc string, breaking bool) {
+		changes = append(changes, SchemaChange{CRD: name, Version: version, Path: path, Description: desc,
+			Breaking: breaking})
+	}
+	bounds := []struct {
+		name     string
+		old, new *float64
+		lower    bool
+	}{
+		{"minimum", oldSchema.Minimum, newSchema.Minimum, true},
+		{"maximum", oldSchema.Maximum, newSchema.Maximum, false},
+		{"minLength", oldSchema.MinLength, newSchema.MinLength, true},
+		{"maxLength", oldSchema.MaxLength, newSchema.MaxLength, false},
+		{"minItems", oldSchema.MinItems, newSchema.MinItems, true},
+		{"maxItems", oldSchema.MaxItems, newSchema.MaxItems, false},
+	}
+	for _, b := range bounds {
+		switch {
+		case b.old == nil && b.new == nil:
+		case b.old == nil:
+			change(fmt.Sprintf("%s added: %v", b.name, *b.new), true)
+		case b.new == nil:
+			change(fmt.Sprintf("%s removed", b.name), false)
+		case *b.old != *b.new:
+			tightened := *b.new > *b.old
+			if !b.lower {
+				tightened = !tightened
+			}
+			change(fmt.Sprintf("%s changed from %v to %v", b.name, *b.old, *b.new), tightened)
+		}
+	}
+	if !oldSchema.ExclusiveMinimum && newSchema.ExclusiveMinimum {
+		change("minimum is now exclusive", true)
+	}
+	if !oldSchema.ExclusiveMaximum && newSchema.ExclusiveMaximum {
+		change("maximum is now exclusive", true)
+	}
+	switch {
+	case oldSchema.Pattern == newSchema.Pattern:
+	case oldSchema.Pattern == "":
+		change(fmt.Sprintf("pattern added: %q", newSchema.Pattern), true)
+	case newSchema.Pattern == "":
+		change("pattern removed", false)
+	default:
+		change(fmt.Sprintf("pattern changed from %q to %q", oldSchema.Pattern, newSchema.Pattern), true)
+	}
+	return changes
+}
+
+// enumValues returns the set of JSON-encoded enum values.
+func enumValues(enum []json.RawMessage) sets.String {
+	values := sets.NewString()
+	for _, v := range enum {
+		values.Insert(string(v))
+	}
+	return values
+}
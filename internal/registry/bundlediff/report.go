@@ -0,0 +1,80 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundlediff
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/operator-framework/operator-sdk/internal/generate/rbacdiff"
+)
+
+// WriteText writes r to w as human-readable text, one section per kind of change.
+func (r Report) WriteText(w io.Writer) {
+	if r.IsEmpty() {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+
+	if len(r.CSV) != 0 {
+		fmt.Fprintln(w, "CSV:")
+		writeChanges(w, r.CSV)
+	}
+
+	if len(r.CRDs) != 0 {
+		fmt.Fprintln(w, "CRDs:")
+		for _, c := range r.CRDs {
+			class := "compatible"
+			if c.Breaking {
+				class = "BREAKING"
+			}
+			fmt.Fprintf(w, "  [%s] %s\n", class, c)
+		}
+	}
+
+	writeRBAC(w, "Cluster permissions", r.ClusterPermissions)
+	writeRBAC(w, "Permissions", r.Permissions)
+
+	if len(r.Images) != 0 {
+		fmt.Fprintln(w, "Images:")
+		writeChanges(w, r.Images)
+	}
+}
+
+func writeChanges(w io.Writer, changes []Change) {
+	for _, c := range changes {
+		switch {
+		case c.Old == "":
+			fmt.Fprintf(w, "  %s: added %q\n", c.Name, c.New)
+		case c.New == "":
+			fmt.Fprintf(w, "  %s: removed %q\n", c.Name, c.Old)
+		default:
+			fmt.Fprintf(w, "  %s: %q -> %q\n", c.Name, c.Old, c.New)
+		}
+	}
+}
+
+func writeRBAC(w io.Writer, section string, diff rbacdiff.Diff) {
+	if diff.IsEmpty() {
+		return
+	}
+	fmt.Fprintf(w, "%s:\n", section)
+	for _, p := range diff.Missing {
+		fmt.Fprintf(w, "  + %s\n", p)
+	}
+	for _, p := range diff.Excess {
+		fmt.Fprintf(w, "  - %s\n", p)
+	}
+}
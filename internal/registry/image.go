@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/docker/distribution/reference"
 	registryimage "github.com/operator-framework/operator-registry/pkg/image"
 	"github.com/operator-framework/operator-registry/pkg/image/containerdregistry"
 	log "github.com/sirupsen/logrus"
@@ -108,3 +109,22 @@ func GetImageLabels(ctx context.Context, logger *log.Entry, image string, local
 
 	return labels, err
 }
+
+// ResolveImageDigest returns image pinned by the digest its tag refers to in
+// its remote registry, ex. quay.io/example/memcached@sha256:<digest>.
+// Registry credentials are read from the default docker config.
+func ResolveImageDigest(ctx context.Context, image string) (string, error) {
+	resolver, err := containerdregistry.NewResolver("", false, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating image resolver: %v", err)
+	}
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("error parsing image %s: %v", image, err)
+	}
+	_, desc, err := resolver.Resolve(ctx, reference.TagNameOnly(ref).String())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s@%s", reference.FamiliarName(ref), desc.Digest), nil
+}
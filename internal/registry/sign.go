@@ -0,0 +1,97 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// CosignEnv overrides the path to the cosign binary used to sign and verify images.
+	CosignEnv = "COSIGN"
+
+	defaultCosign = "cosign"
+	// cosignKeylessEnv enables keyless signing and verification in cosign.
+	cosignKeylessEnv = "COSIGN_EXPERIMENTAL=1"
+)
+
+// SignImage signs image with cosign. If key is empty, image is signed keylessly,
+// otherwise key is the path or KMS URI of the private key to sign with.
+func SignImage(ctx context.Context, logger *log.Entry, image, key string) error {
+	if logger == nil {
+		logger = DiscardLogger()
+	}
+	logger.WithField("image", image).Info("Signing image")
+	if _, err := runCosign(ctx, cosignArgs("sign", image, key), key == ""); err != nil {
+		return fmt.Errorf("error signing image %s: %v", image, err)
+	}
+	return nil
+}
+
+// VerifyImageSignature verifies image's cosign signature. If key is empty,
+// image's keyless signature is verified, otherwise key is the path or KMS URI
+// of the public key to verify with.
+func VerifyImageSignature(ctx context.Context, logger *log.Entry, image, key string) error {
+	if logger == nil {
+		logger = DiscardLogger()
+	}
+	logger.WithField("image", image).Info("Verifying image signature")
+	if _, err := runCosign(ctx, cosignArgs("verify", image, key), key == ""); err != nil {
+		return fmt.Errorf("error verifying signature of image %s: %v", image, err)
+	}
+	return nil
+}
+
+// cosignArgs returns arguments for the cosign subcommand on image with key.
+func cosignArgs(subcommand, image, key string) []string {
+	args := []string{subcommand}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	return append(args, image)
+}
+
+// runCosign runs cosign with args and returns its stdout. If keyless is true,
+// cosign's keyless mode is enabled.
+func runCosign(ctx context.Context, args []string, keyless bool) ([]byte, error) {
+	cosign := defaultCosign
+	if path, ok := os.LookupEnv(CosignEnv); ok {
+		cosign = path
+	}
+	if _, err := exec.LookPath(cosign); err != nil {
+		return nil, fmt.Errorf("cosign binary %q not found, install it or set $%s: %v", cosign, CosignEnv, err)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, cosign, args...)
+	cmd.Stdout = stdout
+	// Pass stderr through so users can follow cosign's keyless login prompts.
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+	if keyless {
+		cmd.Env = append(os.Environ(), cosignKeylessEnv)
+	}
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeCosign records its arguments and $COSIGN_EXPERIMENTAL next to itself,
+// and fails with a message on stderr if $FAKE_COSIGN_ERROR is set.
+const fakeCosign = `#!/bin/sh
+dir=$(dirname "$0")
+echo "$@" > "$dir/args"
+echo "$COSIGN_EXPERIMENTAL" > "$dir/experimental"
+if [ -n "$FAKE_COSIGN_ERROR" ]; then
+  echo "$FAKE_COSIGN_ERROR" >&2
+  exit 1
+fi
+`
+
+var _ = Describe("Sign", func() {
+	Describe("cosignArgs", func() {
+		It("passes a key if set", func() {
+			Expect(cosignArgs("sign", "quay.io/org/bundle:v0.0.1", "cosign.key")).To(Equal(
+				[]string{"sign", "--key", "cosign.key", "quay.io/org/bundle:v0.0.1"}))
+		})
+		It("omits the key for keyless mode", func() {
+			Expect(cosignArgs("verify", "quay.io/org/bundle:v0.0.1", "")).To(Equal(
+				[]string{"verify", "quay.io/org/bundle:v0.0.1"}))
+		})
+	})
+
+	Context("with a fake cosign", func() {
+		const image = "quay.io/org/bundle:v0.0.1"
+		var (
+			tmpDir  string
+			envVars = []string{CosignEnv, "COSIGN_EXPERIMENTAL", "FAKE_COSIGN_ERROR"}
+			oldEnv  map[string]*string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "cosign-")
+			Expect(err).NotTo(HaveOccurred())
+			cosign := filepath.Join(tmpDir, "cosign")
+			Expect(ioutil.WriteFile(cosign, []byte(fakeCosign), 0755)).To(Succeed())
+
+			oldEnv = map[string]*string{}
+			for _, k := range envVars {
+				if v, ok := os.LookupEnv(k); ok {
+					oldEnv[k] = &v
+				} else {
+					oldEnv[k] = nil
+				}
+				Expect(os.Unsetenv(k)).To(Succeed())
+			}
+			Expect(os.Setenv(CosignEnv, cosign)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			for k, v := range oldEnv {
+				if v != nil {
+					Expect(os.Setenv(k, *v)).To(Succeed())
+				} else {
+					Expect(os.Unsetenv(k)).To(Succeed())
+				}
+			}
+			Expect(os.RemoveAll(tmpDir)).To(Succeed())
+		})
+
+		// recorded returns what the fake cosign recorded in file.
+		recorded := func(file string) string {
+			b, err := ioutil.ReadFile(filepath.Join(tmpDir, file))
+			Expect(err).NotTo(HaveOccurred())
+			return string(b)
+		}
+
+		Describe("SignImage", func() {
+			It("signs with a key", func() {
+				Expect(SignImage(context.TODO(), nil, image, "cosign.key")).To(Succeed())
+				Expect(recorded("args")).To(Equal("sign --key cosign.key " + image + "\n"))
+				Expect(recorded("experimental")).To(Equal("\n"))
+			})
+			It("signs keylessly", func() {
+				Expect(SignImage(context.TODO(), nil, image, "")).To(Succeed())
+				Expect(recorded("args")).To(Equal("sign " + image + "\n"))
+				Expect(recorded("experimental")).To(Equal("1\n"))
+			})
+			It("returns cosign's stderr on failure", func() {
+				Expect(os.Setenv("FAKE_COSIGN_ERROR", "no matching signatures")).To(Succeed())
+				err := SignImage(context.TODO(), nil, image, "cosign.key")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("error signing image " + image))
+				Expect(err.Error()).To(ContainSubstring("no matching signatures"))
+			})
+		})
+
+		Describe("VerifyImageSignature", func() {
+			It("verifies with a key", func() {
+				Expect(VerifyImageSignature(context.TODO(), nil, image, "cosign.pub")).To(Succeed())
+				Expect(recorded("args")).To(Equal("verify --key cosign.pub " + image + "\n"))
+				Expect(recorded("experimental")).To(Equal("\n"))
+			})
+			It("verifies keylessly", func() {
+				Expect(VerifyImageSignature(context.TODO(), nil, image, "")).To(Succeed())
+				Expect(recorded("args")).To(Equal("verify " + image + "\n"))
+				Expect(recorded("experimental")).To(Equal("1\n"))
+			})
+			It("returns cosign's stderr on failure", func() {
+				Expect(os.Setenv("FAKE_COSIGN_ERROR", "no matching signatures")).To(Succeed())
+				err := VerifyImageSignature(context.TODO(), nil, image, "cosign.pub")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("error verifying signature of image " + image))
+				Expect(err.Error()).To(ContainSubstring("no matching signatures"))
+			})
+		})
+
+		It("fails if cosign is not found", func() {
+			Expect(os.Setenv(CosignEnv, filepath.Join(tmpDir, "missing"))).To(Succeed())
+			err := VerifyImageSignature(context.TODO(), nil, image, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not found"))
+		})
+	})
+})
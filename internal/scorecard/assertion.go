@@ -0,0 +1,211 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultAssertionTimeout is the time an assertion is retried if its
+// configuration does not set a timeout.
+const defaultAssertionTimeout = 30 * time.Second
+
+// AssertionConfiguration configures a declarative test, run in place of a
+// test image, that passes once the value at JSONPath in a resource matches
+// Value or Regex.
+type AssertionConfiguration struct {
+	// APIVersion and Kind are the type of the resource.
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Namespace is the namespace of the resource, which defaults to the
+	// namespace scorecard runs in. It is ignored for cluster-scoped resources.
+	Namespace string `json:"namespace,omitempty"`
+	// JSONPath is a kubectl-style JSONPath expression, ex. "{.status.phase}".
+	// The enclosing braces are optional.
+	JSONPath string `json:"jsonPath"`
+	// Value is the expected value at JSONPath.
+	Value *string `json:"value,omitempty"`
+	// Regex is a regular expression the value at JSONPath must match.
+	Regex string `json:"regex,omitempty"`
+	// Timeout is how long the assertion is retried before the test fails.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// assertionsConfig holds the assertions of a scorecard config's tests, which
+// are not part of v1alpha3.TestConfiguration.
+type assertionsConfig struct {
+	Stages []struct {
+		Tests []struct {
+			Assertion *AssertionConfiguration `json:"assertion,omitempty"`
+		} `json:"tests"`
+	} `json:"stages"`
+}
+
+// LoadAssertions returns the assertion of each test in the scorecard config
+// at configFilePath, indexed by stage then test. Tests without an assertion
+// have a nil entry.
+func LoadAssertions(configFilePath string) ([][]*AssertionConfiguration, error) {
+	yamlFile, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := assertionsConfig{}
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	assertions := make([][]*AssertionConfiguration, len(cfg.Stages))
+	for i, stage := range cfg.Stages {
+		assertions[i] = make([]*AssertionConfiguration, len(stage.Tests))
+		for j, test := range stage.Tests {
+			if test.Assertion == nil {
+				continue
+			}
+			if err := test.Assertion.validate(); err != nil {
+				return nil, fmt.Errorf("invalid assertion in stage %d test %d: %v", i, j, err)
+			}
+			assertions[i][j] = test.Assertion
+		}
+	}
+	return assertions, nil
+}
+
+func (a AssertionConfiguration) validate() error {
+	switch {
+	case a.APIVersion == "" || a.Kind == "":
+		return errors.New("apiVersion and kind must be set")
+	case a.Name == "":
+		return errors.New("name must be set")
+	case a.JSONPath == "":
+		return errors.New("jsonPath must be set")
+	case (a.Value == nil) == (a.Regex == ""):
+		return errors.New("exactly one of value and regex must be set")
+	}
+	if _, err := schema.ParseGroupVersion(a.APIVersion); err != nil {
+		return err
+	}
+	if _, err := a.parseJSONPath(); err != nil {
+		return fmt.Errorf("error parsing jsonPath: %v", err)
+	}
+	if a.Regex != "" {
+		if _, err := regexp.Compile(a.Regex); err != nil {
+			return fmt.Errorf("error parsing regex: %v", err)
+		}
+	}
+	return nil
+}
+
+func (a AssertionConfiguration) parseJSONPath() (*jsonpath.JSONPath, error) {
+	path := a.JSONPath
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New("assertion")
+	if err := jp.Parse(path); err != nil {
+		return nil, err
+	}
+	return jp, nil
+}
+
+// AssertionRunner runs assertion tests against resources in a cluster.
+type AssertionRunner struct {
+	Client    client.Client
+	Namespace string
+}
+
+// RunAssertion retries a until it passes or its timeout expires, and returns
+// the resulting test status.
+func (r AssertionRunner) RunAssertion(ctx context.Context, a AssertionConfiguration) *v1alpha3.TestStatus {
+	timeout := a.Timeout.Duration
+	if timeout == 0 {
+		timeout = defaultAssertionTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	jp, err := a.parseJSONPath()
+	if err != nil {
+		return convertErrorToStatus(err, "")
+	}
+	var re *regexp.Regexp
+	if a.Regex != "" {
+		if re, err = regexp.Compile(a.Regex); err != nil {
+			return convertErrorToStatus(err, "")
+		}
+	}
+
+	key := types.NamespacedName{Namespace: a.Namespace, Name: a.Name}
+	if key.Namespace == "" {
+		key.Namespace = r.Namespace
+	}
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion(a.APIVersion)
+	obj.SetKind(a.Kind)
+
+	var lastErr error
+	assertionPassed := func() (bool, error) {
+		if err := r.Client.Get(ctx, key, &obj); err != nil {
+			lastErr = fmt.Errorf("error getting %s %q: %v", a.Kind, key, err)
+			return false, nil
+		}
+		buf := &bytes.Buffer{}
+		if err := jp.Execute(buf, obj.Object); err != nil {
+			lastErr = fmt.Errorf("error evaluating jsonPath %q: %v", a.JSONPath, err)
+			return false, nil
+		}
+		actual := buf.String()
+		switch {
+		case a.Value != nil && actual != *a.Value:
+			lastErr = fmt.Errorf("%s %q %s: expected %q, got %q", a.Kind, key, a.JSONPath, *a.Value, actual)
+			return false, nil
+		case re != nil && !re.MatchString(actual):
+			lastErr = fmt.Errorf("%s %q %s: expected a match of %q, got %q", a.Kind, key, a.JSONPath, a.Regex, actual)
+			return false, nil
+		}
+		return true, nil
+	}
+
+	result := v1alpha3.TestResult{
+		Name:  fmt.Sprintf("%s %s %s", a.Kind, key, a.JSONPath),
+		State: v1alpha3.PassState,
+	}
+	if err := wait.PollImmediateUntil(time.Second, assertionPassed, ctx.Done()); err != nil {
+		if lastErr == nil {
+			lastErr = err
+		}
+		result.State = v1alpha3.FailState
+		result.Errors = []string{lastErr.Error()}
+	}
+	return &v1alpha3.TestStatus{Results: []v1alpha3.TestResult{result}}
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const assertionConfig = `kind: Configuration
+apiversion: scorecard.operatorframework.io/v1alpha3
+stages:
+- tests:
+  - image: quay.io/operator-framework/scorecard-test:dev
+    entrypoint:
+    - scorecard-test
+    - basic-check-spec
+  - assertion:
+      apiVersion: v1
+      kind: ConfigMap
+      name: test
+      jsonPath: '{.data.phase}'
+      value: Ready
+      timeout: 10s
+`
+
+var _ = Describe("Assertion tests", func() {
+	Describe("LoadAssertions", func() {
+		var configPath string
+
+		writeConfig := func(contents string) {
+			f, err := ioutil.TempFile("", "scorecard-config-*.yaml")
+			Expect(err).To(BeNil())
+			_, err = f.WriteString(contents)
+			Expect(err).To(BeNil())
+			Expect(f.Close()).To(Succeed())
+			configPath = f.Name()
+		}
+
+		AfterEach(func() {
+			Expect(os.Remove(configPath)).To(Succeed())
+		})
+
+		It("returns the assertion of each test", func() {
+			writeConfig(assertionConfig)
+			assertions, err := LoadAssertions(configPath)
+			Expect(err).To(BeNil())
+			Expect(assertions).To(HaveLen(1))
+			Expect(assertions[0]).To(HaveLen(2))
+			Expect(assertions[0][0]).To(BeNil())
+			Expect(assertions[0][1].Kind).To(Equal("ConfigMap"))
+			Expect(*assertions[0][1].Value).To(Equal("Ready"))
+			Expect(assertions[0][1].Timeout.Duration).To(Equal(10 * time.Second))
+		})
+
+		It("rejects an assertion with both a value and a regex", func() {
+			writeConfig(assertionConfig + "      regex: ^Ready$\n")
+			_, err := LoadAssertions(configPath)
+			Expect(err).To(MatchError(ContainSubstring("exactly one of value and regex")))
+		})
+	})
+
+	Describe("RunAssertion", func() {
+		var r AssertionRunner
+
+		BeforeEach(func() {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+				Data:       map[string]string{"phase": "Ready"},
+			}
+			r = AssertionRunner{Client: fake.NewFakeClient(cm), Namespace: "testns"}
+		})
+
+		newAssertion := func() AssertionConfiguration {
+			return AssertionConfiguration{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       "test",
+				JSONPath:   ".data.phase",
+				Timeout:    metav1.Duration{Duration: 300 * time.Millisecond},
+			}
+		}
+
+		It("passes when the value matches", func() {
+			a := newAssertion()
+			value := "Ready"
+			a.Value = &value
+			status := r.RunAssertion(context.TODO(), a)
+			Expect(status.Results).To(HaveLen(1))
+			Expect(status.Results[0].State).To(Equal(v1alpha3.PassState))
+		})
+
+		It("passes when the regex matches", func() {
+			a := newAssertion()
+			a.Regex = "^Re"
+			status := r.RunAssertion(context.TODO(), a)
+			Expect(status.Results[0].State).To(Equal(v1alpha3.PassState))
+		})
+
+		It("fails with the last mismatch once the timeout expires", func() {
+			a := newAssertion()
+			value := "Failed"
+			a.Value = &value
+			status := r.RunAssertion(context.TODO(), a)
+			Expect(status.Results[0].State).To(Equal(v1alpha3.FailState))
+			Expect(status.Results[0].Errors).To(ConsistOf(ContainSubstring(`expected "Failed", got "Ready"`)))
+		})
+
+		It("fails if the resource does not exist", func() {
+			a := newAssertion()
+			a.Name = "missing"
+			a.Regex = ".*"
+			status := r.RunAssertion(context.TODO(), a)
+			Expect(status.Results[0].State).To(Equal(v1alpha3.FailState))
+			Expect(status.Results[0].Errors).To(ConsistOf(ContainSubstring("not found")))
+		})
+
+		It("is run by scorecard in place of the test runner", func() {
+			a := newAssertion()
+			a.Regex = "^Ready$"
+			o := Scorecard{
+				Config: v1alpha3.Configuration{Stages: []v1alpha3.StageConfiguration{{
+					Tests: []v1alpha3.TestConfiguration{{}},
+				}}},
+				Assertions:      [][]*AssertionConfiguration{{&a}},
+				TestRunner:      FakeTestRunner{Error: context.Canceled},
+				AssertionRunner: &r,
+				SkipCleanup:     true,
+			}
+			output, err := o.Run(context.TODO())
+			Expect(err).To(BeNil())
+			Expect(output.Items).To(HaveLen(1))
+			Expect(output.Items[0].Status.Results[0].State).To(Equal(v1alpha3.PassState))
+		})
+	})
+})
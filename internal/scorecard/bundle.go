@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"fmt"
+	"io/ioutil"
 	"os"
 
 	log "github.com/sirupsen/logrus"
@@ -51,6 +52,29 @@ func (r PodTestRunner) getBundleData() (bundleData []byte, err error) {
 	return buf.Bytes(), nil
 }
 
+// getCRManifestsData returns the contents of all CR manifest files as a single
+// YAML manifest, or nil if there are none.
+func (r PodTestRunner) getCRManifestsData() ([]byte, error) {
+	return readCRManifests(r.CRManifests)
+}
+
+// readCRManifests returns the contents of the CR manifest files at paths as a
+// single YAML manifest, or nil if there are none.
+func readCRManifests(paths []string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CR manifest: %w", err)
+		}
+		if buf.Len() != 0 {
+			buf.WriteString("\n---\n")
+		}
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
 type closeFuncs []func() error
 
 func (fs closeFuncs) close() {
@@ -42,6 +42,8 @@ func LoadConfig(configFilePath string) (v1alpha3.Configuration, error) {
 		return c, err
 	}
 
-	err = yaml.Unmarshal(yamlFile, &c)
-	return c, err
+	if err := yaml.Unmarshal(yamlFile, &c); err != nil {
+		return c, err
+	}
+	return c, validateSeverities(c)
 }
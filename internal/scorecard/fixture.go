@@ -0,0 +1,184 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
+)
+
+// FixtureConfiguration configures objects created before a stage's tests run
+// and deleted after they finish, such as namespaces, secrets, or sample CRs
+// that tests expect to exist.
+type FixtureConfiguration struct {
+	// Manifest is the path to a file of one or more YAML manifests. A
+	// relative path is relative to the config file's directory.
+	Manifest string `json:"manifest"`
+	// Overrides are merged into each object in Manifest, ex. to change the
+	// spec of a sample CR. A null value removes a field.
+	Overrides map[string]interface{} `json:"overrides,omitempty"`
+}
+
+// fixturesConfig holds the fixtures of a scorecard config's stages, which
+// are not part of v1alpha3.StageConfiguration.
+type fixturesConfig struct {
+	Stages []struct {
+		Fixtures []FixtureConfiguration `json:"fixtures,omitempty"`
+	} `json:"stages"`
+}
+
+// LoadFixtures returns the fixture objects of each stage in the scorecard
+// config at configFilePath, indexed by stage, in the order they are to be
+// created.
+func LoadFixtures(configFilePath string) ([][]*unstructured.Unstructured, error) {
+	yamlFile, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := fixturesConfig{}
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	configDir := filepath.Dir(configFilePath)
+	fixtures := make([][]*unstructured.Unstructured, len(cfg.Stages))
+	for i, stage := range cfg.Stages {
+		for j, f := range stage.Fixtures {
+			objs, err := f.load(configDir)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fixture in stage %d fixture %d: %v", i, j, err)
+			}
+			fixtures[i] = append(fixtures[i], objs...)
+		}
+	}
+	return fixtures, nil
+}
+
+// load reads the objects in f's manifest, relative to configDir, and applies
+// f's overrides to them.
+func (f FixtureConfiguration) load(configDir string) ([]*unstructured.Unstructured, error) {
+	if f.Manifest == "" {
+		return nil, errors.New("manifest must be set")
+	}
+	path := f.Manifest
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(configDir, path)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []*unstructured.Unstructured
+	scanner := k8sutil.NewYAMLScanner(bytes.NewBuffer(b))
+	for scanner.Scan() {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(scanner.Bytes(), &obj.Object); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", f.Manifest, err)
+		}
+		if f.Overrides != nil {
+			mergeOverrides(obj.Object, runtime.DeepCopyJSON(f.Overrides))
+		}
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+			return nil, fmt.Errorf("object in %s must set apiVersion and kind", f.Manifest)
+		}
+		if obj.GetName() == "" {
+			return nil, fmt.Errorf("%s in %s must set metadata.name", obj.GetKind(), f.Manifest)
+		}
+		objs = append(objs, obj)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", f.Manifest, err)
+	}
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("no objects in %s", f.Manifest)
+	}
+	return objs, nil
+}
+
+// mergeOverrides merges overrides into obj with JSON merge patch semantics:
+// maps are merged, null values remove fields, and other values replace them.
+func mergeOverrides(obj, overrides map[string]interface{}) {
+	for k, v := range overrides {
+		if v == nil {
+			delete(obj, k)
+			continue
+		}
+		if vm, ok := v.(map[string]interface{}); ok {
+			if om, ok := obj[k].(map[string]interface{}); ok {
+				mergeOverrides(om, vm)
+				continue
+			}
+		}
+		obj[k] = v
+	}
+}
+
+// FixtureRunner creates and deletes fixture objects in a cluster.
+type FixtureRunner struct {
+	Client client.Client
+	// Mapper determines whether an object is namespaced.
+	Mapper meta.RESTMapper
+	// Namespace is the namespace of namespaced objects that do not set one.
+	Namespace string
+}
+
+// Create creates objs in order. It returns the objects that were created,
+// which should be passed to Delete even if an error is returned.
+func (r FixtureRunner) Create(ctx context.Context, objs []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	created := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		obj := o.DeepCopy()
+		gvk := obj.GroupVersionKind()
+		mapping, err := r.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return created, fmt.Errorf("error getting mapping of %s: %v", gvk, err)
+		}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace && obj.GetNamespace() == "" {
+			obj.SetNamespace(r.Namespace)
+		}
+		if err := r.Client.Create(ctx, obj); err != nil {
+			return created, fmt.Errorf("error creating %s %q: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		created = append(created, obj)
+	}
+	return created, nil
+}
+
+// Delete deletes objs in reverse order, ignoring objects that no longer exist.
+func (r FixtureRunner) Delete(ctx context.Context, objs []*unstructured.Unstructured) error {
+	for i := len(objs) - 1; i >= 0; i-- {
+		obj := objs[i]
+		err := r.Client.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error deleting %s %q: %v", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const fixtureConfig = `kind: Configuration
+apiversion: scorecard.operatorframework.io/v1alpha3
+stages:
+- fixtures:
+  - manifest: fixtures.yaml
+    overrides:
+      data:
+        phase: Ready
+        unused: null
+  tests:
+  - image: quay.io/operator-framework/scorecard-test:dev
+- tests:
+  - image: quay.io/operator-framework/scorecard-test:dev
+`
+
+const fixtureManifests = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: fixture-ns
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: fixture
+data:
+  phase: Pending
+  unused: "true"
+`
+
+var _ = Describe("Fixtures", func() {
+	var configPath string
+
+	BeforeEach(func() {
+		dir, err := ioutil.TempDir("", "scorecard-fixtures-")
+		Expect(err).To(BeNil())
+		configPath = filepath.Join(dir, ConfigFileName)
+		Expect(ioutil.WriteFile(configPath, []byte(fixtureConfig), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(dir, "fixtures.yaml"), []byte(fixtureManifests), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(filepath.Dir(configPath))).To(Succeed())
+	})
+
+	Describe("LoadFixtures", func() {
+		It("returns the objects of each stage with overrides applied", func() {
+			fixtures, err := LoadFixtures(configPath)
+			Expect(err).To(BeNil())
+			Expect(fixtures).To(HaveLen(2))
+			Expect(fixtures[0]).To(HaveLen(2))
+			Expect(fixtures[0][0].GetKind()).To(Equal("Namespace"))
+			Expect(fixtures[0][1].Object["data"]).To(Equal(map[string]interface{}{"phase": "Ready"}))
+			Expect(fixtures[1]).To(BeEmpty())
+		})
+
+		It("rejects a fixture whose manifest does not exist", func() {
+			Expect(os.Remove(filepath.Join(filepath.Dir(configPath), "fixtures.yaml"))).To(Succeed())
+			_, err := LoadFixtures(configPath)
+			Expect(err).To(MatchError(ContainSubstring("invalid fixture in stage 0 fixture 0")))
+		})
+	})
+
+	Describe("Run", func() {
+		var (
+			c       client.Client
+			runner  *FixtureRunner
+			objects [][]*unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+			mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+			c = fake.NewFakeClient()
+			runner = &FixtureRunner{Client: c, Mapper: mapper, Namespace: "testns"}
+
+			var err error
+			objects, err = LoadFixtures(configPath)
+			Expect(err).To(BeNil())
+		})
+
+		newScorecard := func(skipCleanup bool) Scorecard {
+			return Scorecard{
+				Config: v1alpha3.Configuration{Stages: []v1alpha3.StageConfiguration{
+					{Tests: []v1alpha3.TestConfiguration{{}}},
+				}},
+				Fixtures:      objects,
+				TestRunner:    fixtureCheckRunner{client: c},
+				FixtureRunner: runner,
+				SkipCleanup:   skipCleanup,
+			}
+		}
+
+		It("creates fixtures before a stage and deletes them afterward", func() {
+			output, err := newScorecard(false).Run(context.TODO())
+			Expect(err).To(BeNil())
+			Expect(output.Items).To(HaveLen(1))
+			Expect(output.Items[0].Status.Results[0].State).To(Equal(v1alpha3.PassState))
+
+			cm := &corev1.ConfigMap{}
+			err = c.Get(context.TODO(), types.NamespacedName{Namespace: "testns", Name: "fixture"}, cm)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("keeps fixtures when cleanup is skipped", func() {
+			_, err := newScorecard(true).Run(context.TODO())
+			Expect(err).To(BeNil())
+
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "testns", Name: "fixture"}, cm)).To(Succeed())
+		})
+
+		It("returns errors deleting fixtures", func() {
+			runner.Client = failDeleteClient{c}
+			output, err := newScorecard(false).Run(context.TODO())
+			Expect(err).To(MatchError(ContainSubstring(`error deleting ConfigMap "fixture"`)))
+			Expect(output.Items).To(HaveLen(1))
+			Expect(output.Items[0].Status.Results[0].State).To(Equal(v1alpha3.PassState))
+		})
+
+		It("fails a stage's tests if its fixtures cannot be created", func() {
+			runner.Mapper = meta.NewDefaultRESTMapper(nil)
+			output, err := newScorecard(false).Run(context.TODO())
+			Expect(err).To(BeNil())
+			Expect(output.Items).To(HaveLen(1))
+			Expect(output.Items[0].Status.Results[0].State).To(Equal(v1alpha3.FailState))
+			Expect(output.Items[0].Status.Results[0].Errors).To(ConsistOf(ContainSubstring("error creating fixtures")))
+		})
+	})
+})
+
+// failDeleteClient is a client whose deletes fail.
+type failDeleteClient struct {
+	client.Client
+}
+
+func (failDeleteClient) Delete(context.Context, runtime.Object, ...client.DeleteOption) error {
+	return errors.New("forbidden")
+}
+
+// fixtureCheckRunner is a TestRunner whose tests pass if the fixture
+// ConfigMap exists with overrides applied.
+type fixtureCheckRunner struct {
+	FakeTestRunner
+	client client.Client
+}
+
+func (r fixtureCheckRunner) RunTest(ctx context.Context, _ v1alpha3.TestConfiguration) (*v1alpha3.TestStatus, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: "testns", Name: "fixture"}, cm); err != nil {
+		return nil, err
+	}
+	state := v1alpha3.PassState
+	if cm.Data["phase"] != "Ready" {
+		state = v1alpha3.FailState
+	}
+	return &v1alpha3.TestStatus{Results: []v1alpha3.TestResult{{State: state}}}, nil
+}
@@ -40,14 +40,23 @@ func (r PodTestRunner) getTestStatus(ctx context.Context, p *v1.Pod) (output *v1
 // List lists the scorecard tests as configured that would be
 // run based on user selection
 func (o Scorecard) List() v1alpha3.TestList {
-	output := v1alpha3.NewTestList()
-	for _, stage := range o.Config.Stages {
-		tests := o.selectTests(stage)
-		for _, test := range tests {
+	return FlattenStages(o.ListStages())
+}
+
+// ListStages lists the scorecard tests as configured that would be run based
+// on user selection, by stage.
+func (o Scorecard) ListStages() []StageTests {
+	var stages []StageTests
+	for i, stage := range o.Config.Stages {
+		var tests []v1alpha3.Test
+		for _, j := range o.selectTests(stage) {
 			item := v1alpha3.NewTest()
-			item.Spec = test
-			output.Items = append(output.Items, item)
+			item.Spec = stage.Tests[j]
+			tests = append(tests, item)
+		}
+		if len(tests) != 0 {
+			stages = append(stages, StageTests{Stage: i, Tests: tests})
 		}
 	}
-	return output
+	return stages
 }
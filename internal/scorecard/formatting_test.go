@@ -18,6 +18,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -58,3 +59,29 @@ func TestList(t *testing.T) {
 
 	}
 }
+
+func TestListStages(t *testing.T) {
+	test := func(suite string) v1alpha3.TestConfiguration {
+		return v1alpha3.TestConfiguration{Image: "quay.io/example/tests:dev", Labels: map[string]string{"suite": suite}}
+	}
+	o := Scorecard{Config: v1alpha3.Configuration{Stages: []v1alpha3.StageConfiguration{
+		{Tests: []v1alpha3.TestConfiguration{test("basic")}},
+		{Tests: []v1alpha3.TestConfiguration{test("olm")}},
+		{Tests: []v1alpha3.TestConfiguration{test("basic"), test("olm")}},
+	}}}
+	var err error
+	if o.Selector, err = labels.Parse("suite=basic"); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	stages := o.ListStages()
+	if len(stages) != 2 || stages[0].Stage != 0 || stages[1].Stage != 2 {
+		t.Fatalf("Unexpected stages: %+v", stages)
+	}
+	if len(stages[1].Tests) != 1 || stages[1].Tests[0].Spec.Labels["suite"] != "basic" {
+		t.Fatalf("Unexpected tests of stage 2: %+v", stages[1].Tests)
+	}
+	if list := FlattenStages(stages); len(list.Items) != 2 {
+		t.Fatalf("Wanted result count 2 but got : %d", len(list.Items))
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite holds the test cases of a scorecard stage.
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is the result of a single scorecard check.
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Error     *JUnitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure describes why a test case failed or errored.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	// Type is the severity of the test that failed.
+	Type     string `xml:"type,attr,omitempty"`
+	Contents string `xml:",chardata"`
+}
+
+// JUnit converts the tests run or listed by each stage to a JUnit report with
+// a test suite per stage and a test case per test result.
+func JUnit(stages []StageTests) JUnitTestSuites {
+	report := JUnitTestSuites{}
+	for _, stage := range stages {
+		suite := JUnitTestSuite{Name: fmt.Sprintf("stage-%d", stage.Stage+1)}
+		for _, test := range stage.Tests {
+			className := suite.Name
+			if s, ok := test.Spec.Labels["suite"]; ok {
+				className = s
+			}
+			for _, r := range test.Status.Results {
+				tc := JUnitTestCase{Name: r.Name, ClassName: className, SystemOut: r.Log}
+				failure := &JUnitFailure{
+					Message:  strings.Join(r.Errors, "; "),
+					Type:     string(TestSeverity(test.Spec)),
+					Contents: strings.Join(append(append([]string{}, r.Errors...), r.Suggestions...), "\n"),
+				}
+				switch r.State {
+				case v1alpha3.PassState:
+				case v1alpha3.FailState:
+					tc.Failure = failure
+					suite.Failures++
+				default:
+					tc.Error = failure
+					suite.Errors++
+				}
+				suite.TestCases = append(suite.TestCases, tc)
+				suite.Tests++
+			}
+		}
+		if suite.Tests != 0 {
+			report.Suites = append(report.Suites, suite)
+		}
+	}
+	return report
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+)
+
+func TestJUnit(t *testing.T) {
+	basic := v1alpha3.TestConfiguration{
+		Image:      "quay.io/operator-framework/scorecard-test:dev",
+		Entrypoint: []string{"scorecard-test", "basic-check-spec"},
+		Labels:     map[string]string{"suite": "basic", SeverityLabel: string(SeverityWarning)},
+	}
+	olm := v1alpha3.TestConfiguration{
+		Image:      "quay.io/operator-framework/scorecard-test:dev",
+		Entrypoint: []string{"scorecard-test", "olm-bundle-validation"},
+		Labels:     map[string]string{"suite": "olm"},
+	}
+	custom := v1alpha3.TestConfiguration{
+		Image:      "quay.io/example/custom-scorecard-tests:dev",
+		Entrypoint: []string{"custom-scorecard-tests", "customtest1"},
+	}
+
+	newTest := func(spec v1alpha3.TestConfiguration, results ...v1alpha3.TestResult) v1alpha3.Test {
+		test := v1alpha3.NewTest()
+		test.Spec = spec
+		test.Status.Results = results
+		return test
+	}
+	// The same test may be configured in several stages, ex. to rerun it
+	// after a stage that changes the cluster.
+	stages := []StageTests{
+		{Stage: 0, Tests: []v1alpha3.Test{
+			newTest(olm, v1alpha3.TestResult{Name: "olm-bundle-validation", State: v1alpha3.PassState, Log: "ok"}),
+			newTest(basic, v1alpha3.TestResult{
+				Name:        "basic-check-spec",
+				State:       v1alpha3.FailState,
+				Errors:      []string{"spec missing"},
+				Suggestions: []string{"add a spec"},
+			}),
+		}},
+		{Stage: 1, Tests: []v1alpha3.Test{newTest(custom)}},
+		{Stage: 2, Tests: []v1alpha3.Test{
+			newTest(custom, v1alpha3.TestResult{Name: "customtest1", State: v1alpha3.ErrorState,
+				Errors: []string{"timed out"}}),
+			newTest(olm, v1alpha3.TestResult{Name: "olm-bundle-validation", State: v1alpha3.PassState}),
+		}},
+	}
+
+	expected := JUnitTestSuites{Suites: []JUnitTestSuite{
+		{
+			Name:     "stage-1",
+			Tests:    2,
+			Failures: 1,
+			TestCases: []JUnitTestCase{
+				{Name: "olm-bundle-validation", ClassName: "olm", SystemOut: "ok"},
+				{
+					Name:      "basic-check-spec",
+					ClassName: "basic",
+					Failure:   &JUnitFailure{Message: "spec missing", Type: "warning", Contents: "spec missing\nadd a spec"},
+				},
+			},
+		},
+		{
+			Name:   "stage-3",
+			Tests:  2,
+			Errors: 1,
+			TestCases: []JUnitTestCase{
+				{
+					Name:      "customtest1",
+					ClassName: "stage-3",
+					Error:     &JUnitFailure{Message: "timed out", Type: "error", Contents: "timed out"},
+				},
+				{Name: "olm-bundle-validation", ClassName: "olm"},
+			},
+		},
+	}}
+	if report := JUnit(stages); !reflect.DeepEqual(report, expected) {
+		t.Fatalf("Unexpected report:\n\tgot %+v\n\texpected %+v", report, expected)
+	}
+}
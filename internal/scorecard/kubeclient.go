@@ -15,12 +15,12 @@
 package scorecard
 
 import (
-	"os"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
-	cruntime "sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 // GetKubeClient will get a kubernetes client from the following sources:
@@ -29,14 +29,11 @@ import (
 // - the user's $HOME/.kube/config file
 // - in-cluster connection for when the sdk is run within a cluster instead of
 //   the command line
+// The kubeconfig's context kubeContext is used, or its current context if
+// kubeContext is empty.
 // TODO(joelanford): migrate scorecard use `internal/operator.Configuration`
-func GetKubeClient(kubeconfig string) (client kubernetes.Interface, err error) {
-
-	if kubeconfig != "" {
-		os.Setenv(k8sutil.KubeConfigEnvVar, kubeconfig)
-	}
-
-	config, err := cruntime.GetConfig()
+func GetKubeClient(kubeconfig, kubeContext string) (client kubernetes.Interface, err error) {
+	config, err := k8sutil.NewClientConfig(kubeconfig, kubeContext, nil).ClientConfig()
 	if err != nil {
 		return client, err
 	}
@@ -50,30 +47,45 @@ func GetKubeClient(kubeconfig string) (client kubernetes.Interface, err error) {
 	return clientset, err
 }
 
+// GetRuntimeClient returns a controller-runtime client, which can get
+// resources of any kind, from the same sources as GetKubeClient.
+func GetRuntimeClient(kubeconfig, kubeContext string) (client.Client, error) {
+	config, err := k8sutil.NewClientConfig(kubeconfig, kubeContext, nil).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{})
+}
+
+// GetRESTMapper returns a RESTMapper that discovers resources from the cluster
+// of the kubeconfig's context kubeContext.
+func GetRESTMapper(kubeconfig, kubeContext string) (meta.RESTMapper, error) {
+	config, err := k8sutil.NewClientConfig(kubeconfig, kubeContext, nil).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return apiutil.NewDynamicRESTMapper(config)
+}
+
 // GetKubeNamespace returns the kubernetes namespace to use
 // for scorecard pod creation
 // the order of how the namespace is determined is as follows:
 // - a namespace command line argument
-// - a namespace determined from the kubeconfig file
+// - a namespace determined from the kubeconfig context kubeContext, or the
+//   current context if kubeContext is empty
 // - the kubeconfig file is determined in the following order:
 //   - from the kubeconfig flag if set
 //   - from the KUBECONFIG env var if set
 //   - from the $HOME/.kube/config path if exists
 //   - returns 'default' as the namespace if not set in the kubeconfig
 // TODO(joelanford): migrate scorecard to use `internal/operator.Configuration`
-func GetKubeNamespace(kubeconfigPath, namespace string) string {
+func GetKubeNamespace(kubeconfigPath, kubeContext, namespace string) string {
 
 	if namespace != "" {
 		return namespace
 	}
 
-	rules := clientcmd.NewDefaultClientConfigLoadingRules()
-
-	if kubeconfigPath != "" {
-		rules.ExplicitPath = kubeconfigPath
-	}
-
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+	kubeConfig := k8sutil.NewClientConfig(kubeconfigPath, kubeContext, nil)
 
 	ns, _, err := kubeConfig.Namespace()
 	if err != nil {
@@ -38,18 +38,21 @@ func TestGetKubeNamespace(t *testing.T) {
 
 	cases := []struct {
 		kubeconfigPath string
+		kubeContext    string
 		namespace      string
 		expectedValue  string
 	}{
-		{"", "userspecified", "userspecified"},
-		{"/tmp/doesnotexist", "", "default"},
-		{file.Name(), "", "goo"},
+		{"", "", "userspecified", "userspecified"},
+		{"/tmp/doesnotexist", "", "", "default"},
+		{file.Name(), "", "", "goo"},
+		{file.Name(), "dev", "", "foo"},
+		{file.Name(), "doesnotexist", "", "default"},
 	}
 
 	for _, c := range cases {
-		t.Run(c.kubeconfigPath, func(t *testing.T) {
+		t.Run(c.kubeconfigPath+"/"+c.kubeContext, func(t *testing.T) {
 
-			oNamespace := GetKubeNamespace(c.kubeconfigPath, c.namespace)
+			oNamespace := GetKubeNamespace(c.kubeconfigPath, c.kubeContext, c.namespace)
 			if oNamespace != c.expectedValue {
 				t.Errorf("Wanted namespace %s, got: %s", c.expectedValue, oNamespace)
 			}
@@ -89,7 +92,7 @@ func TestGetKubeNamespaceEnvVar(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.kubeconfigPath, func(t *testing.T) {
-			oNamespace := GetKubeNamespace(c.kubeconfigPath, c.namespace)
+			oNamespace := GetKubeNamespace(c.kubeconfigPath, "", c.namespace)
 			if oNamespace != c.expectedValue {
 				t.Errorf("Wanted namespace %s, got: %s", c.expectedValue, oNamespace)
 			}
@@ -0,0 +1,152 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+	apimanifests "github.com/operator-framework/api/pkg/manifests"
+
+	registryutil "github.com/operator-framework/operator-sdk/internal/registry"
+	"github.com/operator-framework/operator-sdk/internal/scorecard/tests"
+)
+
+const (
+	// builtinTestImageName is the name of the image that runs built-in tests.
+	builtinTestImageName = "scorecard-test"
+	// builtinTestEntrypoint is the command of built-in tests in that image.
+	builtinTestEntrypoint = "scorecard-test"
+)
+
+// LocalTestRunner runs built-in basic and olm tests in-process against a
+// bundle on disk, without a cluster. Other tests are run by Fallback, which
+// is initialized when the first of them runs.
+type LocalTestRunner struct {
+	BundlePath     string
+	BundleMetadata registryutil.Labels
+	// CRManifests are paths to CR manifest files used by tests instead of
+	// the CSV's alm-examples.
+	CRManifests []string
+	// Fallback runs tests that are not built-in. If nil, those tests fail.
+	Fallback TestRunner
+
+	bundle          *apimanifests.Bundle
+	crManifestsPath string
+
+	fallbackOnce        sync.Once
+	fallbackErr         error
+	fallbackInitialized bool
+}
+
+// Initialize reads the bundle and writes CR manifests to a temporary file
+// that tests read them from.
+func (r *LocalTestRunner) Initialize(ctx context.Context) (err error) {
+	if r.bundle, err = apimanifests.GetBundleFromDir(r.BundlePath); err != nil {
+		return fmt.Errorf("error reading bundle %w", err)
+	}
+
+	crManifestsData, err := readCRManifests(r.CRManifests)
+	if err != nil {
+		return fmt.Errorf("error getting CR manifests %w", err)
+	}
+	if len(crManifestsData) == 0 {
+		return nil
+	}
+	f, err := ioutil.TempFile("", "scorecard-cr-manifests-*.yaml")
+	if err != nil {
+		return err
+	}
+	r.crManifestsPath = f.Name()
+	if _, err := f.Write(crManifestsData); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// RunTest runs test in-process if it is a built-in test, and with Fallback
+// otherwise.
+func (r *LocalTestRunner) RunTest(ctx context.Context, test v1alpha3.TestConfiguration) (*v1alpha3.TestStatus, error) {
+	return r.RunTestPod(ctx, test, nil)
+}
+
+// RunTestPod is like RunTest, but passes pod to Fallback if it is a
+// TestPodRunner. Built-in tests ignore pod.
+func (r *LocalTestRunner) RunTestPod(ctx context.Context, test v1alpha3.TestConfiguration,
+	pod *TestPodConfiguration) (*v1alpha3.TestStatus, error) {
+	if isBuiltinTest(test) {
+		status, ok := tests.Run(test.Entrypoint[1], r.BundlePath, r.bundle, r.BundleMetadata, r.crManifestsPath)
+		if ok {
+			return &status, nil
+		}
+	}
+
+	if r.Fallback == nil {
+		return nil, fmt.Errorf("test image %s cannot run locally and no cluster is configured", test.Image)
+	}
+	r.fallbackOnce.Do(func() {
+		r.fallbackErr = r.Fallback.Initialize(ctx)
+		r.fallbackInitialized = r.fallbackErr == nil
+	})
+	if r.fallbackErr != nil {
+		return nil, r.fallbackErr
+	}
+	if podRunner, ok := r.Fallback.(TestPodRunner); ok && pod != nil {
+		return podRunner.RunTestPod(ctx, test, pod)
+	}
+	return r.Fallback.RunTest(ctx, test)
+}
+
+// Cleanup removes the CR manifests file, and cleans up Fallback if it ran any tests.
+func (r *LocalTestRunner) Cleanup(ctx context.Context) error {
+	var errs []string
+	if r.crManifestsPath != "" {
+		if err := os.Remove(r.crManifestsPath); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err.Error())
+		}
+	}
+	if r.fallbackInitialized {
+		if err := r.Fallback.Cleanup(ctx); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) != 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// isBuiltinTest returns true if test runs a built-in test of the scorecard-test image.
+func isBuiltinTest(test v1alpha3.TestConfiguration) bool {
+	if len(test.Entrypoint) != 2 || test.Entrypoint[0] != builtinTestEntrypoint {
+		return false
+	}
+	image := test.Image
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return path.Base(image) == builtinTestImageName
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+)
+
+var _ = Describe("LocalTestRunner", func() {
+	var r *LocalTestRunner
+
+	BeforeEach(func() {
+		r = &LocalTestRunner{BundlePath: filepath.Join("testdata", "bundle")}
+		Expect(r.Initialize(context.TODO())).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(r.Cleanup(context.TODO())).To(Succeed())
+	})
+
+	It("runs built-in tests in-process", func() {
+		test := v1alpha3.TestConfiguration{
+			Image:      "quay.io/operator-framework/scorecard-test:dev",
+			Entrypoint: []string{"scorecard-test", "basic-check-spec"},
+		}
+		status, err := r.RunTest(context.TODO(), test)
+		Expect(err).To(BeNil())
+		Expect(status.Results).To(HaveLen(1))
+		Expect(status.Results[0].Name).To(Equal("basic-check-spec"))
+	})
+
+	It("fails other tests without a fallback runner", func() {
+		test := v1alpha3.TestConfiguration{
+			Image:      "quay.io/example/custom-scorecard-tests:dev",
+			Entrypoint: []string{"custom-scorecard-tests", "customtest1"},
+		}
+		_, err := r.RunTest(context.TODO(), test)
+		Expect(err).To(MatchError(ContainSubstring("cannot run locally")))
+	})
+
+	It("runs other tests with the fallback runner", func() {
+		fallbackStatus := &v1alpha3.TestStatus{Results: []v1alpha3.TestResult{{Name: "customtest1"}}}
+		r.Fallback = FakeTestRunner{TestStatus: fallbackStatus}
+		test := v1alpha3.TestConfiguration{
+			Image:      "quay.io/example/custom-scorecard-tests:dev",
+			Entrypoint: []string{"scorecard-test", "basic-check-spec"},
+		}
+		status, err := r.RunTest(context.TODO(), test)
+		Expect(err).To(BeNil())
+		Expect(status).To(Equal(fallbackStatus))
+	})
+
+	Describe("isBuiltinTest", func() {
+		It("matches the scorecard-test image by name", func() {
+			Expect(isBuiltinTest(v1alpha3.TestConfiguration{
+				Image:      "localhost:5000/scorecard-test@sha256:abc",
+				Entrypoint: []string{"scorecard-test", "olm-bundle-validation"},
+			})).To(BeTrue())
+			Expect(isBuiltinTest(v1alpha3.TestConfiguration{
+				Image:      "quay.io/operator-framework/scorecard-test-kuttl:dev",
+				Entrypoint: []string{"scorecard-test", "olm-bundle-validation"},
+			})).To(BeFalse())
+		})
+	})
+})
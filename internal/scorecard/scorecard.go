@@ -16,6 +16,7 @@ package scorecard
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -36,11 +38,38 @@ type TestRunner interface {
 	Cleanup(context.Context) error
 }
 
+// TestPodRunner is a TestRunner that can run a test in a pod configured by
+// a TestPodConfiguration.
+type TestPodRunner interface {
+	TestRunner
+	RunTestPod(context.Context, v1alpha3.TestConfiguration, *TestPodConfiguration) (*v1alpha3.TestStatus, error)
+}
+
 type Scorecard struct {
-	Config      v1alpha3.Configuration
-	Selector    labels.Selector
-	TestRunner  TestRunner
-	SkipCleanup bool
+	Config v1alpha3.Configuration
+	// Assertions holds the assertion of each test in Config, indexed by
+	// stage then test. Tests with an assertion are run by AssertionRunner
+	// instead of TestRunner.
+	Assertions [][]*AssertionConfiguration
+	// TestPods holds the pod configuration of each test in Config, indexed
+	// by stage then test, which is used if TestRunner is a TestPodRunner.
+	TestPods [][]*TestPodConfiguration
+	// Fixtures holds the objects created before each stage in Config runs,
+	// indexed by stage. They are created and deleted by FixtureRunner.
+	Fixtures        [][]*unstructured.Unstructured
+	Selector        labels.Selector
+	TestRunner      TestRunner
+	AssertionRunner *AssertionRunner
+	FixtureRunner   *FixtureRunner
+	SkipCleanup     bool
+}
+
+// stageTest is a test selected from a stage, with its assertion and pod
+// configuration if any.
+type stageTest struct {
+	config    v1alpha3.TestConfiguration
+	assertion *AssertionConfiguration
+	pod       *TestPodConfiguration
 }
 
 type PodTestRunner struct {
@@ -49,6 +78,9 @@ type PodTestRunner struct {
 	BundlePath     string
 	BundleMetadata registryutil.Labels
 	Client         kubernetes.Interface
+	// CRManifests are paths to CR manifest files used by tests instead of
+	// the CSV's alm-examples.
+	CRManifests []string
 
 	configMapName string
 }
@@ -62,29 +94,54 @@ type FakeTestRunner struct {
 // cleanupTimeout is the time given to clean up resources, regardless of how long ctx's deadline is.
 var cleanupTimeout = time.Second * 30
 
+// StageTests are the tests of a stage in the configuration that were run or
+// listed.
+type StageTests struct {
+	// Stage is the index of the stage in the configuration.
+	Stage int
+	Tests []v1alpha3.Test
+}
+
+// FlattenStages returns the tests of stages as a single list.
+func FlattenStages(stages []StageTests) v1alpha3.TestList {
+	list := v1alpha3.NewTestList()
+	for _, stage := range stages {
+		list.Items = append(list.Items, stage.Tests...)
+	}
+	return list
+}
+
 // Run executes the scorecard tests as configured
-func (o Scorecard) Run(ctx context.Context) (testOutput v1alpha3.TestList, err error) {
-	testOutput = v1alpha3.NewTestList()
+func (o Scorecard) Run(ctx context.Context) (v1alpha3.TestList, error) {
+	stages, err := o.RunStages(ctx)
+	return FlattenStages(stages), err
+}
 
+// RunStages executes the scorecard tests as configured, returning the tests
+// run by each stage that had tests selected.
+func (o Scorecard) RunStages(ctx context.Context) (stages []StageTests, err error) {
 	if err := o.TestRunner.Initialize(ctx); err != nil {
-		return testOutput, err
+		return nil, err
 	}
 
-	for _, stage := range o.Config.Stages {
-		tests := o.selectTests(stage)
+	var fixtureErr error
+	for i, stage := range o.Config.Stages {
+		var tests []stageTest
+		for _, j := range o.selectTests(stage) {
+			tests = append(tests, stageTest{
+				config:    stage.Tests[j],
+				assertion: o.assertion(i, j),
+				pod:       o.testPod(i, j),
+			})
+		}
 		if len(tests) == 0 {
 			continue
 		}
 
-		output := make(chan v1alpha3.Test, len(tests))
-		if stage.Parallel {
-			o.runStageParallel(ctx, tests, output)
-		} else {
-			o.runStageSequential(ctx, tests, output)
-		}
-		close(output)
-		for o := range output {
-			testOutput.Items = append(testOutput.Items, o)
+		stageOutput, err := o.runStage(ctx, i, stage.Parallel, tests)
+		stages = append(stages, StageTests{Stage: i, Tests: stageOutput})
+		if err != nil && fixtureErr == nil {
+			fixtureErr = err
 		}
 	}
 
@@ -93,6 +150,7 @@ func (o Scorecard) Run(ctx context.Context) (testOutput v1alpha3.TestList, err e
 	case <-ctx.Done():
 		err = ctx.Err()
 	default:
+		err = fixtureErr
 	}
 
 	if !o.SkipCleanup {
@@ -100,18 +158,69 @@ func (o Scorecard) Run(ctx context.Context) (testOutput v1alpha3.TestList, err e
 		clctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
 		defer cancel()
 		if err := o.TestRunner.Cleanup(clctx); err != nil {
-			return testOutput, err
+			return stages, err
 		}
 	}
 
-	return testOutput, err
+	return stages, err
 }
 
-func (o Scorecard) runStageParallel(ctx context.Context, tests []v1alpha3.TestConfiguration, results chan<- v1alpha3.Test) {
+// runStage runs the tests of stage i, with the stage's fixtures created
+// beforehand. Tests fail without running if fixtures cannot be created. The
+// returned error is from deleting fixtures.
+func (o Scorecard) runStage(ctx context.Context, i int, parallel bool,
+	tests []stageTest) (results []v1alpha3.Test, err error) {
+	var fixtures []*unstructured.Unstructured
+	if i < len(o.Fixtures) {
+		fixtures = o.Fixtures[i]
+	}
+
+	var createErr error
+	if len(fixtures) != 0 {
+		var created []*unstructured.Unstructured
+		if o.FixtureRunner == nil {
+			createErr = errors.New("no fixture runner configured")
+		} else {
+			created, createErr = o.FixtureRunner.Create(ctx, fixtures)
+		}
+		if !o.SkipCleanup && len(created) != 0 {
+			defer func() {
+				// Use a separate context for cleanup, which needs to run regardless of a prior timeout.
+				clctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+				defer cancel()
+				err = o.FixtureRunner.Delete(clctx, created)
+			}()
+		}
+	}
+
+	output := make(chan v1alpha3.Test, len(tests))
+	switch {
+	case createErr != nil:
+		for _, test := range tests {
+			out := v1alpha3.NewTest()
+			out.Spec = test.config
+			out.Status = *convertErrorToStatus(fmt.Errorf("error creating fixtures: %w", createErr), "")
+			output <- out
+		}
+	case parallel:
+		o.runStageParallel(ctx, tests, output)
+	default:
+		o.runStageSequential(ctx, tests, output)
+	}
+	close(output)
+
+	results = make([]v1alpha3.Test, 0, len(tests))
+	for t := range output {
+		results = append(results, t)
+	}
+	return results, nil
+}
+
+func (o Scorecard) runStageParallel(ctx context.Context, tests []stageTest, results chan<- v1alpha3.Test) {
 	var wg sync.WaitGroup
 	for _, t := range tests {
 		wg.Add(1)
-		go func(test v1alpha3.TestConfiguration) {
+		go func(test stageTest) {
 			results <- o.runTest(ctx, test)
 			wg.Done()
 		}(t)
@@ -119,37 +228,70 @@ func (o Scorecard) runStageParallel(ctx context.Context, tests []v1alpha3.TestCo
 	wg.Wait()
 }
 
-func (o Scorecard) runStageSequential(ctx context.Context, tests []v1alpha3.TestConfiguration, results chan<- v1alpha3.Test) {
+func (o Scorecard) runStageSequential(ctx context.Context, tests []stageTest, results chan<- v1alpha3.Test) {
 	for _, test := range tests {
 		results <- o.runTest(ctx, test)
 	}
 }
 
-func (o Scorecard) runTest(ctx context.Context, test v1alpha3.TestConfiguration) v1alpha3.Test {
-	result, err := o.TestRunner.RunTest(ctx, test)
-	if err != nil {
-		result = convertErrorToStatus(err, "")
+func (o Scorecard) runTest(ctx context.Context, test stageTest) v1alpha3.Test {
+	var result *v1alpha3.TestStatus
+	if test.assertion != nil {
+		if o.AssertionRunner == nil {
+			result = convertErrorToStatus(errors.New("no assertion runner configured"), "")
+		} else {
+			result = o.AssertionRunner.RunAssertion(ctx, *test.assertion)
+		}
+	} else {
+		var err error
+		if podRunner, ok := o.TestRunner.(TestPodRunner); ok && test.pod != nil {
+			result, err = podRunner.RunTestPod(ctx, test.config, test.pod)
+		} else {
+			result, err = o.TestRunner.RunTest(ctx, test.config)
+		}
+		if err != nil {
+			result = convertErrorToStatus(err, "")
+		}
 	}
 
 	out := v1alpha3.NewTest()
-	out.Spec = test
+	out.Spec = test.config
 	out.Status = *result
 	return out
 }
 
 // selectTests applies an optionally passed selector expression
-// against the configured set of tests, returning the selected tests
-func (o *Scorecard) selectTests(stage v1alpha3.StageConfiguration) []v1alpha3.TestConfiguration {
-	selected := make([]v1alpha3.TestConfiguration, 0)
-	for _, test := range stage.Tests {
+// against the configured set of tests, returning the indexes of the
+// selected tests in stage
+func (o *Scorecard) selectTests(stage v1alpha3.StageConfiguration) []int {
+	selected := make([]int, 0)
+	for i, test := range stage.Tests {
 		if o.Selector == nil || o.Selector.String() == "" || o.Selector.Matches(labels.Set(test.Labels)) {
 			// TODO olm manifests check
-			selected = append(selected, test)
+			selected = append(selected, i)
 		}
 	}
 	return selected
 }
 
+// assertion returns the assertion of test j in stage i, or nil if that test
+// runs an image.
+func (o Scorecard) assertion(i, j int) *AssertionConfiguration {
+	if i < len(o.Assertions) && j < len(o.Assertions[i]) {
+		return o.Assertions[i][j]
+	}
+	return nil
+}
+
+// testPod returns the pod configuration of test j in stage i, or nil if that
+// test has none.
+func (o Scorecard) testPod(i, j int) *TestPodConfiguration {
+	if i < len(o.TestPods) && j < len(o.TestPods[i]) {
+		return o.TestPods[i][j]
+	}
+	return nil
+}
+
 func (r FakeTestRunner) Initialize(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
@@ -166,7 +308,12 @@ func (r *PodTestRunner) Initialize(ctx context.Context) error {
 		return fmt.Errorf("error getting bundle data %w", err)
 	}
 
-	r.configMapName, err = r.CreateConfigMap(ctx, bundleData)
+	crManifestsData, err := r.getCRManifestsData()
+	if err != nil {
+		return fmt.Errorf("error getting CR manifests %w", err)
+	}
+
+	r.configMapName, err = r.CreateConfigMap(ctx, bundleData, crManifestsData)
 	if err != nil {
 		return fmt.Errorf("error creating ConfigMap %w", err)
 	}
@@ -198,8 +345,18 @@ func (r PodTestRunner) Cleanup(ctx context.Context) (err error) {
 
 // RunTest executes a single test
 func (r PodTestRunner) RunTest(ctx context.Context, test v1alpha3.TestConfiguration) (*v1alpha3.TestStatus, error) {
+	return r.RunTestPod(ctx, test, nil)
+}
+
+// RunTestPod executes a single test in a pod configured by podConfig, if not
+// nil.
+func (r PodTestRunner) RunTestPod(ctx context.Context, test v1alpha3.TestConfiguration,
+	podConfig *TestPodConfiguration) (*v1alpha3.TestStatus, error) {
 	// Create a Pod to run the test
 	podDef := getPodDefinition(r.configMapName, test, r)
+	if podConfig != nil {
+		podConfig.apply(podDef)
+	}
 	pod, err := r.Client.CoreV1().Pods(r.Namespace).Create(ctx, podDef, metav1.CreateOptions{})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,94 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"fmt"
+
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+)
+
+// SeverityLabel is the test label that sets the severity of a test's
+// failures. Tests without it have SeverityError.
+const SeverityLabel = "severity"
+
+// Severity is how serious a test's failures are, which determines whether
+// they fail a scorecard run.
+type Severity string
+
+const (
+	// SeverityError failures fail a scorecard run.
+	SeverityError Severity = "error"
+	// SeverityWarning failures fail a scorecard run only if it fails on
+	// warnings.
+	SeverityWarning Severity = "warning"
+	// SeveritySuggestion failures never fail a scorecard run.
+	SeveritySuggestion Severity = "suggestion"
+)
+
+// severityRanks orders severities from least to most serious.
+var severityRanks = map[Severity]int{
+	SeveritySuggestion: 0,
+	SeverityWarning:    1,
+	SeverityError:      2,
+}
+
+// ParseFailOn parses the least serious severity that fails a scorecard run,
+// which is either "error" or "warning".
+func ParseFailOn(s string) (Severity, error) {
+	switch sev := Severity(s); sev {
+	case SeverityError, SeverityWarning:
+		return sev, nil
+	}
+	return "", fmt.Errorf("invalid severity %q: must be one of %q or %q", s, SeverityError, SeverityWarning)
+}
+
+// TestSeverity returns the severity set by test's severity label.
+func TestSeverity(test v1alpha3.TestConfiguration) Severity {
+	if s, ok := test.Labels[SeverityLabel]; ok {
+		return Severity(s)
+	}
+	return SeverityError
+}
+
+// validateSeverities returns an error if a test in config has an invalid
+// severity label.
+func validateSeverities(config v1alpha3.Configuration) error {
+	for i, stage := range config.Stages {
+		for j, test := range stage.Tests {
+			if _, ok := severityRanks[TestSeverity(test)]; !ok {
+				return fmt.Errorf("invalid %s label %q in stage %d test %d: must be one of %q, %q, or %q",
+					SeverityLabel, test.Labels[SeverityLabel], i, j, SeverityError, SeverityWarning, SeveritySuggestion)
+			}
+		}
+	}
+	return nil
+}
+
+// HasFailingTest returns true if a test in list that did not pass has a
+// severity at least as serious as failOn.
+func HasFailingTest(list v1alpha3.TestList, failOn Severity) bool {
+	for _, t := range list.Items {
+		if severityRanks[TestSeverity(t.Spec)] < severityRanks[failOn] {
+			continue
+		}
+		for _, r := range t.Status.Results {
+			if r.State != v1alpha3.PassState {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+)
+
+func TestParseFailOn(t *testing.T) {
+	cases := []struct {
+		value     string
+		expected  Severity
+		wantError bool
+	}{
+		{"error", SeverityError, false},
+		{"warning", SeverityWarning, false},
+		{"suggestion", "", true},
+		{"", "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			sev, err := ParseFailOn(c.value)
+			if (err != nil) != c.wantError {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if sev != c.expected {
+				t.Fatalf("Expected severity %q, got %q", c.expected, sev)
+			}
+		})
+	}
+}
+
+func TestValidateSeverities(t *testing.T) {
+	newConfig := func(severity string) v1alpha3.Configuration {
+		return v1alpha3.Configuration{Stages: []v1alpha3.StageConfiguration{{Tests: []v1alpha3.TestConfiguration{
+			{Image: "image", Labels: map[string]string{SeverityLabel: severity}},
+		}}}}
+	}
+	for _, sev := range []Severity{SeverityError, SeverityWarning, SeveritySuggestion} {
+		if err := validateSeverities(newConfig(string(sev))); err != nil {
+			t.Errorf("Unexpected error for severity %q: %v", sev, err)
+		}
+	}
+	if err := validateSeverities(newConfig("critical")); err == nil {
+		t.Error("Expected an error for an invalid severity")
+	}
+}
+
+func TestHasFailingTest(t *testing.T) {
+	newTest := func(severity string, state v1alpha3.State) v1alpha3.Test {
+		test := v1alpha3.NewTest()
+		if severity != "" {
+			test.Spec.Labels = map[string]string{SeverityLabel: severity}
+		}
+		test.Status.Results = []v1alpha3.TestResult{{State: state}}
+		return test
+	}
+	cases := []struct {
+		name      string
+		tests     []v1alpha3.Test
+		failOn    Severity
+		expFailed bool
+	}{
+		{"passing tests", []v1alpha3.Test{newTest("", v1alpha3.PassState)}, SeverityError, false},
+		{"unlabelled failure", []v1alpha3.Test{newTest("", v1alpha3.FailState)}, SeverityError, true},
+		{"error on warning", []v1alpha3.Test{newTest("error", v1alpha3.ErrorState)}, SeverityWarning, true},
+		{"warning on error", []v1alpha3.Test{newTest("warning", v1alpha3.FailState)}, SeverityError, false},
+		{"warning on warning", []v1alpha3.Test{newTest("warning", v1alpha3.FailState)}, SeverityWarning, true},
+		{"suggestion on warning", []v1alpha3.Test{newTest("suggestion", v1alpha3.FailState)}, SeverityWarning, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			list := v1alpha3.NewTestList()
+			list.Items = c.tests
+			if failed := HasFailingTest(list, c.failOn); failed != c.expFailed {
+				t.Fatalf("Expected failed to be %v, got %v", c.expFailed, failed)
+			}
+		})
+	}
+}
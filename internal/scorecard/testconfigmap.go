@@ -23,10 +23,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 )
 
+// crManifestsKey is the ConfigMap key holding CR manifests passed to scorecard.
+const crManifestsKey = "cr-manifests.yaml"
+
 // CreateConfigMap creates a ConfigMap that will hold the bundle
-// contents to be mounted into the test Pods
-func (r PodTestRunner) CreateConfigMap(ctx context.Context, bundleData []byte) (configMapName string, err error) {
-	cfg := getConfigMapDefinition(r.Namespace, bundleData)
+// contents, and CR manifests if any, to be mounted into the test Pods
+func (r PodTestRunner) CreateConfigMap(ctx context.Context, bundleData, crManifestsData []byte) (configMapName string, err error) {
+	cfg := getConfigMapDefinition(r.Namespace, bundleData, crManifestsData)
 	configMap, err := r.Client.CoreV1().ConfigMaps(r.Namespace).Create(ctx, cfg, metav1.CreateOptions{})
 	if err != nil {
 		return configMapName, err
@@ -37,10 +40,13 @@ func (r PodTestRunner) CreateConfigMap(ctx context.Context, bundleData []byte) (
 // getConfigMapDefinition returns a ConfigMap definition that
 // will hold the bundle contents and eventually will be mounted
 // into each test Pod
-func getConfigMapDefinition(namespace string, bundleData []byte) *v1.ConfigMap {
+func getConfigMapDefinition(namespace string, bundleData, crManifestsData []byte) *v1.ConfigMap {
 	configMapName := fmt.Sprintf("scorecard-test-%s", rand.String(4))
 	data := make(map[string][]byte)
 	data["bundle.tar.gz"] = bundleData
+	if len(crManifestsData) != 0 {
+		data[crManifestsKey] = crManifestsData
+	}
 	return &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      configMapName,
@@ -30,12 +30,15 @@ import (
 const (
 	// PodBundleRoot is the directory containing all bundle data within a test pod.
 	PodBundleRoot = "/bundle"
+	// PodCRManifestsPath is the path of the CR manifests passed to scorecard
+	// within a test pod, if any.
+	PodCRManifestsPath = "/cr-manifests/" + crManifestsKey
 )
 
 // getPodDefinition fills out a Pod definition based on
 // information from the test
 func getPodDefinition(configMapName string, test v1alpha3.TestConfiguration, r PodTestRunner) *v1.Pod {
-	return &v1.Pod{
+	pod := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("scorecard-test-%s", rand.String(4)),
 			Namespace: r.Namespace,
@@ -118,6 +121,18 @@ func getPodDefinition(configMapName string, test v1alpha3.TestConfiguration, r P
 			},
 		},
 	}
+
+	// Mount CR manifests from the bundle ConfigMap at a well-known path.
+	if len(r.CRManifests) != 0 {
+		c := &pod.Spec.Containers[0]
+		c.VolumeMounts = append(c.VolumeMounts, v1.VolumeMount{
+			MountPath: PodCRManifestsPath,
+			Name:      "scorecard-bundle",
+			SubPath:   crManifestsKey,
+			ReadOnly:  true,
+		})
+	}
+	return pod
 }
 
 // getPodLog fetches the test results which are found in the pod log
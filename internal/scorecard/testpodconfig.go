@@ -0,0 +1,152 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// TestPodConfiguration configures the pod a test image runs in, beyond the
+// image and entrypoint set by v1alpha3.TestConfiguration.
+type TestPodConfiguration struct {
+	// Env are environment variables set in the test container.
+	Env []v1.EnvVar `json:"env,omitempty"`
+	// Volumes are secrets and config maps mounted in the test container.
+	Volumes []TestVolume `json:"volumes,omitempty"`
+	// Resources are the compute resources of the test container.
+	Resources v1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// TestVolume mounts a secret or config map in the scorecard namespace in a
+// test container.
+type TestVolume struct {
+	// Name is the name of the volume.
+	Name string `json:"name"`
+	// MountPath is the absolute path the volume is mounted at.
+	MountPath string `json:"mountPath"`
+	// Secret is the name of a secret. Exactly one of Secret and ConfigMap
+	// must be set.
+	Secret string `json:"secret,omitempty"`
+	// ConfigMap is the name of a config map.
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// testPodsConfig holds the pod configuration of a scorecard config's tests,
+// which is not part of v1alpha3.TestConfiguration.
+type testPodsConfig struct {
+	Stages []struct {
+		Tests []TestPodConfiguration `json:"tests"`
+	} `json:"stages"`
+}
+
+// reservedVolumeNames are the names of volumes scorecard adds to test pods.
+var reservedVolumeNames = map[string]bool{
+	"scorecard-bundle": true,
+	"scorecard-untar":  true,
+}
+
+// LoadTestPods returns the pod configuration of each test in the scorecard
+// config at configFilePath, indexed by stage then test. Tests without pod
+// configuration have a nil entry.
+func LoadTestPods(configFilePath string) ([][]*TestPodConfiguration, error) {
+	yamlFile, err := ioutil.ReadFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	cfg := testPodsConfig{}
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	pods := make([][]*TestPodConfiguration, len(cfg.Stages))
+	for i, stage := range cfg.Stages {
+		pods[i] = make([]*TestPodConfiguration, len(stage.Tests))
+		for j := range stage.Tests {
+			pod := stage.Tests[j]
+			if pod.isEmpty() {
+				continue
+			}
+			if err := pod.validate(); err != nil {
+				return nil, fmt.Errorf("invalid pod configuration in stage %d test %d: %v", i, j, err)
+			}
+			pods[i][j] = &pod
+		}
+	}
+	return pods, nil
+}
+
+func (c TestPodConfiguration) isEmpty() bool {
+	return len(c.Env) == 0 && len(c.Volumes) == 0 &&
+		len(c.Resources.Requests) == 0 && len(c.Resources.Limits) == 0
+}
+
+func (c TestPodConfiguration) validate() error {
+	for _, env := range c.Env {
+		if env.Name == "" {
+			return errors.New("env name must be set")
+		}
+	}
+	names := map[string]bool{}
+	for _, vol := range c.Volumes {
+		switch {
+		case vol.Name == "":
+			return errors.New("volume name must be set")
+		case reservedVolumeNames[vol.Name]:
+			return fmt.Errorf("volume name %q is reserved", vol.Name)
+		case names[vol.Name]:
+			return fmt.Errorf("duplicate volume name %q", vol.Name)
+		case !path.IsAbs(vol.MountPath):
+			return fmt.Errorf("volume %q mountPath must be an absolute path", vol.Name)
+		case (vol.Secret == "") == (vol.ConfigMap == ""):
+			return fmt.Errorf("volume %q must set exactly one of secret and configMap", vol.Name)
+		}
+		if errs := validation.IsDNS1123Label(vol.Name); len(errs) != 0 {
+			return fmt.Errorf("invalid volume name %q: %v", vol.Name, errs)
+		}
+		names[vol.Name] = true
+	}
+	return nil
+}
+
+// apply adds c's environment variables, volumes, and resources to the test
+// container of pod.
+func (c TestPodConfiguration) apply(pod *v1.Pod) {
+	container := &pod.Spec.Containers[0]
+	container.Env = append(container.Env, c.Env...)
+	container.Resources = c.Resources
+	for _, vol := range c.Volumes {
+		source := v1.VolumeSource{}
+		if vol.Secret != "" {
+			source.Secret = &v1.SecretVolumeSource{SecretName: vol.Secret}
+		} else {
+			source.ConfigMap = &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: vol.ConfigMap},
+			}
+		}
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{Name: vol.Name, VolumeSource: source})
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      vol.Name,
+			MountPath: vol.MountPath,
+			ReadOnly:  true,
+		})
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+const testPodConfig = `kind: Configuration
+apiversion: scorecard.operatorframework.io/v1alpha3
+stages:
+- tests:
+  - image: quay.io/operator-framework/scorecard-test:dev
+    entrypoint:
+    - scorecard-test
+    - basic-check-spec
+  - image: quay.io/example/custom-scorecard-tests:dev
+    env:
+    - name: API_TOKEN
+      valueFrom:
+        secretKeyRef:
+          name: test-credentials
+          key: token
+    volumes:
+    - name: credentials
+      mountPath: /etc/credentials
+      secret: test-credentials
+    resources:
+      requests:
+        memory: 128Mi
+`
+
+var _ = Describe("Test pod configuration", func() {
+	Describe("LoadTestPods", func() {
+		var configPath string
+
+		writeConfig := func(contents string) {
+			f, err := ioutil.TempFile("", "scorecard-config-*.yaml")
+			Expect(err).To(BeNil())
+			_, err = f.WriteString(contents)
+			Expect(err).To(BeNil())
+			Expect(f.Close()).To(Succeed())
+			configPath = f.Name()
+		}
+
+		AfterEach(func() {
+			Expect(os.Remove(configPath)).To(Succeed())
+		})
+
+		It("returns the pod configuration of each test", func() {
+			writeConfig(testPodConfig)
+			pods, err := LoadTestPods(configPath)
+			Expect(err).To(BeNil())
+			Expect(pods).To(HaveLen(1))
+			Expect(pods[0]).To(HaveLen(2))
+			Expect(pods[0][0]).To(BeNil())
+			Expect(pods[0][1].Env).To(HaveLen(1))
+			Expect(pods[0][1].Volumes).To(Equal([]TestVolume{
+				{Name: "credentials", MountPath: "/etc/credentials", Secret: "test-credentials"},
+			}))
+			Expect(pods[0][1].Resources.Requests.Memory().String()).To(Equal("128Mi"))
+		})
+
+		It("rejects a volume with a reserved name", func() {
+			writeConfig(`stages:
+- tests:
+  - image: quay.io/example/custom-scorecard-tests:dev
+    volumes:
+    - name: scorecard-bundle
+      mountPath: /etc/bundle
+      configMap: bundle
+`)
+			_, err := LoadTestPods(configPath)
+			Expect(err).To(MatchError(ContainSubstring(`volume name "scorecard-bundle" is reserved`)))
+		})
+
+		It("rejects a volume with both a secret and a config map", func() {
+			writeConfig(`stages:
+- tests:
+  - image: quay.io/example/custom-scorecard-tests:dev
+    volumes:
+    - name: credentials
+      mountPath: /etc/credentials
+      secret: test-credentials
+      configMap: test-credentials
+`)
+			_, err := LoadTestPods(configPath)
+			Expect(err).To(MatchError(ContainSubstring("exactly one of secret and configMap")))
+		})
+	})
+
+	It("configures the test pod", func() {
+		c := TestPodConfiguration{
+			Env:     []v1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+			Volumes: []TestVolume{{Name: "settings", MountPath: "/etc/settings", ConfigMap: "test-settings"}},
+			Resources: v1.ResourceRequirements{
+				Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+			},
+		}
+		pod := getPodDefinition("test-configmap", v1alpha3.TestConfiguration{}, PodTestRunner{})
+		c.apply(pod)
+
+		container := pod.Spec.Containers[0]
+		Expect(container.Env).To(ContainElement(v1.EnvVar{Name: "LOG_LEVEL", Value: "debug"}))
+		Expect(container.Resources.Limits.Cpu().String()).To(Equal("500m"))
+		Expect(container.VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name: "settings", MountPath: "/etc/settings", ReadOnly: true,
+		}))
+		Expect(pod.Spec.Volumes).To(ContainElement(v1.Volume{
+			Name: "settings",
+			VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: "test-settings"},
+			}},
+		}))
+	})
+
+	It("configures the pod created by PodTestRunner", func() {
+		client := fake.NewSimpleClientset()
+		// Complete test pods as they are created, so that RunTestPod returns
+		// without waiting for a kubelet.
+		client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			action.(clienttesting.CreateAction).GetObject().(*v1.Pod).Status.Phase = v1.PodSucceeded
+			return false, nil, nil
+		})
+		r := PodTestRunner{Namespace: "default", Client: client}
+		podConfig := &TestPodConfiguration{Env: []v1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}}
+
+		_, err := r.RunTestPod(context.TODO(), v1alpha3.TestConfiguration{Image: "quay.io/example/test:dev"},
+			podConfig)
+		Expect(err).To(BeNil())
+
+		pods, err := client.CoreV1().Pods("default").List(context.TODO(), metav1.ListOptions{})
+		Expect(err).To(BeNil())
+		Expect(pods.Items).To(HaveLen(1))
+		container := pods.Items[0].Spec.Containers[0]
+		Expect(container.Image).To(Equal("quay.io/example/test:dev"))
+		Expect(container.Env).To(ContainElement(v1.EnvVar{Name: "LOG_LEVEL", Value: "debug"}))
+	})
+
+	It("is passed to a TestPodRunner by scorecard", func() {
+		pod := &TestPodConfiguration{Env: []v1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}}
+		runner := &recordingPodRunner{}
+		o := Scorecard{
+			Config: v1alpha3.Configuration{Stages: []v1alpha3.StageConfiguration{{
+				Tests: []v1alpha3.TestConfiguration{{}, {}},
+			}}},
+			TestPods:    [][]*TestPodConfiguration{{nil, pod}},
+			TestRunner:  runner,
+			SkipCleanup: true,
+		}
+		_, err := o.Run(context.TODO())
+		Expect(err).To(BeNil())
+		Expect(runner.pods).To(ConsistOf(BeNil(), Equal(pod)))
+	})
+})
+
+// recordingPodRunner is a TestPodRunner that records the pod configuration
+// of each test it runs.
+type recordingPodRunner struct {
+	FakeTestRunner
+	pods []*TestPodConfiguration
+}
+
+func (r *recordingPodRunner) RunTest(ctx context.Context, test v1alpha3.TestConfiguration) (*v1alpha3.TestStatus, error) {
+	return r.RunTestPod(ctx, test, nil)
+}
+
+func (r *recordingPodRunner) RunTestPod(_ context.Context, _ v1alpha3.TestConfiguration,
+	pod *TestPodConfiguration) (*v1alpha3.TestStatus, error) {
+	r.pods = append(r.pods, pod)
+	return &v1alpha3.TestStatus{Results: []v1alpha3.TestResult{{State: v1alpha3.PassState}}}, nil
+}
@@ -15,6 +15,8 @@
 package tests
 
 import (
+	"fmt"
+
 	scapiv1alpha3 "github.com/operator-framework/api/pkg/apis/scorecard/v1alpha3"
 	apimanifests "github.com/operator-framework/api/pkg/manifests"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -24,8 +26,9 @@ const (
 	BasicCheckSpecTest = "basic-check-spec"
 )
 
-// CheckSpecTest verifies that CRs have a spec block
-func CheckSpecTest(bundle *apimanifests.Bundle) scapiv1alpha3.TestStatus {
+// CheckSpecTest verifies that CRs have a spec block. CRs are loaded from the
+// crManifests file if it is not empty, and from alm-examples otherwise.
+func CheckSpecTest(bundle *apimanifests.Bundle, crManifests string) scapiv1alpha3.TestStatus {
 	r := scapiv1alpha3.TestResult{
 		Name:        BasicCheckSpecTest,
 		State:       scapiv1alpha3.PassState,
@@ -33,11 +36,12 @@ func CheckSpecTest(bundle *apimanifests.Bundle) scapiv1alpha3.TestStatus {
 		Suggestions: make([]string, 0),
 	}
 
-	crSet, err := GetCRs(bundle)
+	crSet, source, err := LoadCRs(bundle, crManifests)
 	if err != nil {
 		r.Errors = append(r.Errors, "error getting custom resources")
 		r.State = scapiv1alpha3.FailState
 	}
+	r.Log = fmt.Sprintf("Loaded %d Custom Resources from %s\n", len(crSet), source)
 
 	return scapiv1alpha3.TestStatus{
 		Results: []scapiv1alpha3.TestResult{checkSpec(crSet, r)},
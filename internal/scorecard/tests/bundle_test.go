@@ -32,9 +32,10 @@ import (
 
 var _ = Describe("Basic and OLM tests", func() {
 	var (
-		testBundle = filepath.Join("..", "testdata", "bundle")
-		status     scapiv1alpha3.TestStatus
-		result     scapiv1alpha3.TestResult
+		testBundle  = filepath.Join("..", "testdata", "bundle")
+		crManifests = filepath.Join("..", "testdata", "crs", "memcacheds.yaml")
+		status      scapiv1alpha3.TestStatus
+		result      scapiv1alpha3.TestResult
 	)
 
 	BeforeEach(func() {
@@ -66,6 +67,28 @@ var _ = Describe("Basic and OLM tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(crCount).To(Equal(len(crList)))
 		})
+
+		It("Check CRs from manifests", func() {
+			bundle, err = apimanifests.GetBundleFromDir(testBundle)
+			Expect(err).ToNot(HaveOccurred())
+
+			crList, source, err := LoadCRs(bundle, crManifests)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(source).To(Equal(crManifests))
+			Expect(crList).To(HaveLen(2))
+			Expect(crList[0].GetName()).To(Equal("memcached-large"))
+			Expect(crList[1].GetName()).To(Equal("memcached-no-spec"))
+
+			crList, source, err = LoadCRs(bundle, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(source).To(Equal("alm-examples"))
+			Expect(crList).To(HaveLen(1))
+		})
+
+		It("Fails to load missing CR manifests", func() {
+			_, err = GetCRsFromManifests(filepath.Join("..", "testdata", "crs", "missing.yaml"))
+			Expect(err).To(HaveOccurred())
+		})
 	})
 
 	Describe("Testing Basic and OLM tests", func() {
@@ -81,14 +104,18 @@ var _ = Describe("Basic and OLM tests", func() {
 
 		Context("CheckSpecTest", func() {
 			It("returns a pass state when Spec field exists", func() {
-				status = CheckSpecTest(bundle)
+				status = CheckSpecTest(bundle, "")
 				Expect(status.Results[0].State).To(Equal(scapiv1alpha3.PassState))
 			})
+			It("returns a fail state when a CR from manifests has no Spec field", func() {
+				status = CheckSpecTest(bundle, crManifests)
+				Expect(status.Results[0].State).To(Equal(scapiv1alpha3.FailState))
+			})
 		})
 
 		Context("CRDsHaveValidationTest", func() {
 			It("returns a pass state when CRDs have validations", func() {
-				status = CRDsHaveValidationTest(bundle)
+				status = CRDsHaveValidationTest(bundle, "")
 				Expect(status.Results[0].State).To(Equal(scapiv1alpha3.PassState))
 			})
 		})
@@ -102,14 +129,14 @@ var _ = Describe("Basic and OLM tests", func() {
 
 		Context("SpecDescriptorsTest", func() {
 			It("returns a pass state then spec descriptors are present", func() {
-				status = SpecDescriptorsTest(bundle)
+				status = SpecDescriptorsTest(bundle, "")
 				Expect(status.Results[0].State).To(Equal(scapiv1alpha3.PassState))
 			})
 		})
 
 		Context("StatusDescriptorsTest", func() {
 			It("returns a pass state then status descriptors are present", func() {
-				status = StatusDescriptorsTest(bundle)
+				status = StatusDescriptorsTest(bundle, "")
 				Expect(status.Results[0].State).To(Equal(scapiv1alpha3.PassState))
 			})
 		})
@@ -17,11 +17,53 @@ package tests
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	apimanifests "github.com/operator-framework/api/pkg/manifests"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
 )
 
+// almExamplesSource is the CR source reported for CRs from a CSV's alm-examples.
+const almExamplesSource = "alm-examples"
+
+// LoadCRs returns the CRs in the YAML manifests file crManifests if it is not
+// empty, and the CRs in bundle's CSV alm-examples annotation otherwise. source
+// describes where the CRs were loaded from.
+func LoadCRs(bundle *apimanifests.Bundle, crManifests string) (crList []unstructured.Unstructured, source string, err error) {
+	if crManifests == "" {
+		crList, err = GetCRs(bundle)
+		return crList, almExamplesSource, err
+	}
+	crList, err = GetCRsFromManifests(crManifests)
+	return crList, crManifests, err
+}
+
+// GetCRsFromManifests parses the CRs in a YAML file, which may contain
+// multiple documents.
+func GetCRsFromManifests(path string) (crList []unstructured.Unstructured, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CR manifests: %v", err)
+	}
+	defer f.Close()
+
+	scanner := k8sutil.NewYAMLScanner(f)
+	for scanner.Scan() {
+		cr := unstructured.Unstructured{}
+		if err := yaml.Unmarshal(scanner.Bytes(), &cr.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse CR manifests %s: %v", path, err)
+		}
+		crList = append(crList, cr)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CR manifests %s: %v", path, err)
+	}
+	return crList, nil
+}
+
 // GetCRs parses a Bundle's CSV for CRs
 func GetCRs(bundle *apimanifests.Bundle) (crList []unstructured.Unstructured, err error) {
 	if bundle.CSV.GetAnnotations() == nil {
@@ -122,20 +122,20 @@ func BundleValidationTest(bundleRoot string, metadata registryutil.Labels) scapi
 }
 
 // CRDsHaveValidationTest verifies all CRDs have a validation section
-func CRDsHaveValidationTest(bundle *apimanifests.Bundle) scapiv1alpha3.TestStatus {
+func CRDsHaveValidationTest(bundle *apimanifests.Bundle, crManifests string) scapiv1alpha3.TestStatus {
 	r := scapiv1alpha3.TestResult{}
 	r.Name = OLMCRDsHaveValidationTest
 	r.State = scapiv1alpha3.PassState
 	r.Errors = make([]string, 0)
 	r.Suggestions = make([]string, 0)
 
-	crs, err := GetCRs(bundle)
+	crs, source, err := LoadCRs(bundle, crManifests)
 	if err != nil {
 		r.Errors = append(r.Errors, err.Error())
 		r.State = scapiv1alpha3.ErrorState
 		return wrapResult(r)
 	}
-	r.Log += fmt.Sprintf("Loaded %d Custom Resources from alm-examples\n", len(crs))
+	r.Log += fmt.Sprintf("Loaded %d Custom Resources from %s\n", len(crs), source)
 
 	var crds []*apiextv1.CustomResourceDefinition
 	for _, crd := range bundle.V1CRDs {
@@ -185,39 +185,39 @@ func CheckResources(crd operatorsv1alpha1.CustomResourceDefinitions,
 }
 
 // SpecDescriptorsTest verifies all spec fields have descriptors
-func SpecDescriptorsTest(bundle *apimanifests.Bundle) scapiv1alpha3.TestStatus {
+func SpecDescriptorsTest(bundle *apimanifests.Bundle, crManifests string) scapiv1alpha3.TestStatus {
 	r := scapiv1alpha3.TestResult{}
 	r.Name = OLMSpecDescriptorsTest
 	r.State = scapiv1alpha3.PassState
 	r.Errors = make([]string, 0)
 	r.Suggestions = make([]string, 0)
-	r = checkCSVDescriptors(bundle, r, specDescriptor)
+	r = checkCSVDescriptors(bundle, crManifests, r, specDescriptor)
 	return wrapResult(r)
 }
 
 // StatusDescriptorsTest verifies all CRDs have status descriptors
-func StatusDescriptorsTest(bundle *apimanifests.Bundle) scapiv1alpha3.TestStatus {
+func StatusDescriptorsTest(bundle *apimanifests.Bundle, crManifests string) scapiv1alpha3.TestStatus {
 	r := scapiv1alpha3.TestResult{}
 	r.Name = OLMStatusDescriptorsTest
 	r.State = scapiv1alpha3.PassState
 	r.Errors = make([]string, 0)
 	r.Suggestions = make([]string, 0)
-	r = checkCSVDescriptors(bundle, r, statusDescriptor)
+	r = checkCSVDescriptors(bundle, crManifests, r, statusDescriptor)
 	return wrapResult(r)
 }
 
-func checkCSVDescriptors(bundle *apimanifests.Bundle, r scapiv1alpha3.TestResult,
+func checkCSVDescriptors(bundle *apimanifests.Bundle, crManifests string, r scapiv1alpha3.TestResult,
 	descriptor string) scapiv1alpha3.TestResult {
 
 	r.Log += fmt.Sprintf("Loaded ClusterServiceVersion: %s\n", bundle.CSV.GetName())
 
-	crs, err := GetCRs(bundle)
+	crs, source, err := LoadCRs(bundle, crManifests)
 	if err != nil {
 		r.Errors = append(r.Errors, err.Error())
 		r.State = scapiv1alpha3.ErrorState
 		return r
 	}
-	r.Log += fmt.Sprintf("Loaded %d Custom Resources from alm-examples\n", len(crs))
+	r.Log += fmt.Sprintf("Loaded %d Custom Resources from %s\n", len(crs), source)
 
 	for _, cr := range crs {
 		r = checkOwnedCSVDescriptors(cr, bundle.CSV, descriptor, r)
@@ -0,0 +1,221 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry records anonymized operator-sdk usage events to a local
+// report file, and optionally an HTTP endpoint. Telemetry is disabled unless
+// explicitly enabled with EnabledEnv.
+//
+// Events never contain command arguments, flag values, file paths, or project
+// names; only the command path, project plugin keys, SDK version, platform,
+// duration, and a coarse error category are recorded.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// EnabledEnv enables telemetry if set to a true value, ex. "true" or "1".
+	EnabledEnv = "OPERATOR_SDK_TELEMETRY"
+	// ReportFileEnv overrides the default report file path.
+	ReportFileEnv = "OPERATOR_SDK_TELEMETRY_FILE"
+	// EndpointEnv is a URL events are additionally POSTed to as JSON.
+	EndpointEnv = "OPERATOR_SDK_TELEMETRY_ENDPOINT"
+)
+
+// Error categories.
+const (
+	ErrorCategoryNone       = ""
+	ErrorCategoryKubernetes = "kubernetes"
+	ErrorCategoryNetwork    = "network"
+	ErrorCategoryFilesystem = "filesystem"
+	ErrorCategoryOther      = "other"
+)
+
+// sendTimeout bounds how long sending an event to an endpoint may delay a command.
+const sendTimeout = 3 * time.Second
+
+// Event is a single anonymized command invocation.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Command is the invoked command's path, ex. "operator-sdk create api".
+	Command string `json:"command"`
+	// Plugins are the plugin keys of the project the command was run in, if any.
+	Plugins    []string `json:"plugins,omitempty"`
+	SDKVersion string   `json:"sdkVersion"`
+	GOOS       string   `json:"goos"`
+	GOARCH     string   `json:"goarch"`
+	// DurationSeconds is the command's run time.
+	DurationSeconds float64 `json:"durationSeconds"`
+	// ErrorCategory is the category of the error the command returned, if any.
+	ErrorCategory string `json:"errorCategory,omitempty"`
+}
+
+// Enabled returns true if telemetry was opted into with EnabledEnv.
+func Enabled() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(EnabledEnv))
+	return err == nil && enabled
+}
+
+// DefaultReportFile returns the path of the local report file, which can be
+// overridden with ReportFileEnv.
+func DefaultReportFile() (string, error) {
+	if path, ok := os.LookupEnv(ReportFileEnv); ok {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "operator-sdk", "telemetry.jsonl"), nil
+}
+
+// CategorizeError returns the category of err, or ErrorCategoryNone if err is nil.
+func CategorizeError(err error) string {
+	if err == nil {
+		return ErrorCategoryNone
+	}
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		return ErrorCategoryKubernetes
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorCategoryNetwork
+	}
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return ErrorCategoryFilesystem
+	}
+	return ErrorCategoryOther
+}
+
+// Record appends e to the report file at path as a JSON line.
+func Record(path string, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+// Send POSTs e to endpoint as JSON.
+func Send(endpoint string, e Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: sendTimeout}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+// ReadEvents returns all events in the report file at path. No events are
+// returned if path does not exist.
+func ReadEvents(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("error parsing %s line %d: %v", path, line, err)
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// Summary aggregates events.
+type Summary struct {
+	Total int `json:"total"`
+	// Errors is the number of events with an error category.
+	Errors int `json:"errors"`
+	// Commands, Plugins, SDKVersions, and ErrorCategories count events by each field's value.
+	Commands        map[string]int `json:"commands"`
+	Plugins         map[string]int `json:"plugins"`
+	SDKVersions     map[string]int `json:"sdkVersions"`
+	ErrorCategories map[string]int `json:"errorCategories"`
+	// First and Last are the timestamps of the earliest and latest events.
+	First time.Time `json:"first,omitempty"`
+	Last  time.Time `json:"last,omitempty"`
+}
+
+// Summarize aggregates events into a Summary.
+func Summarize(events []Event) Summary {
+	s := Summary{
+		Commands:        make(map[string]int),
+		Plugins:         make(map[string]int),
+		SDKVersions:     make(map[string]int),
+		ErrorCategories: make(map[string]int),
+	}
+	for _, e := range events {
+		s.Total++
+		s.Commands[e.Command]++
+		for _, p := range e.Plugins {
+			s.Plugins[p]++
+		}
+		s.SDKVersions[e.SDKVersion]++
+		if e.ErrorCategory != ErrorCategoryNone {
+			s.Errors++
+			s.ErrorCategories[e.ErrorCategory]++
+		}
+		if s.First.IsZero() || e.Timestamp.Before(s.First) {
+			s.First = e.Timestamp
+		}
+		if e.Timestamp.After(s.Last) {
+			s.Last = e.Timestamp
+		}
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTelemetry(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Telemetry Suite")
+}
+
+var _ = Describe("Telemetry", func() {
+	Describe("Enabled", func() {
+		AfterEach(func() {
+			Expect(os.Unsetenv(EnabledEnv)).To(Succeed())
+		})
+
+		It("is disabled by default", func() {
+			Expect(os.Unsetenv(EnabledEnv)).To(Succeed())
+			Expect(Enabled()).To(BeFalse())
+		})
+		It("is disabled for non-boolean values", func() {
+			Expect(os.Setenv(EnabledEnv, "yes please")).To(Succeed())
+			Expect(Enabled()).To(BeFalse())
+		})
+		It("is enabled for true values", func() {
+			Expect(os.Setenv(EnabledEnv, "true")).To(Succeed())
+			Expect(Enabled()).To(BeTrue())
+		})
+	})
+
+	Describe("CategorizeError", func() {
+		It("categorizes errors", func() {
+			Expect(CategorizeError(nil)).To(Equal(ErrorCategoryNone))
+			notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "foo")
+			Expect(CategorizeError(fmt.Errorf("wrapped: %w", notFound))).To(Equal(ErrorCategoryKubernetes))
+			Expect(CategorizeError(&net.DNSError{Err: "no such host"})).To(Equal(ErrorCategoryNetwork))
+			_, err := os.Open(filepath.Join("does", "not", "exist"))
+			Expect(CategorizeError(err)).To(Equal(ErrorCategoryFilesystem))
+			Expect(CategorizeError(errors.New("foo"))).To(Equal(ErrorCategoryOther))
+		})
+	})
+
+	Describe("Record and ReadEvents", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "telemetry")
+			Expect(err).NotTo(HaveOccurred())
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("returns no events for a missing report", func() {
+			events, err := ReadEvents(filepath.Join(dir, "missing.jsonl"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+		It("reads recorded events", func() {
+			path := filepath.Join(dir, "sub", "telemetry.jsonl")
+			e1 := Event{Timestamp: time.Unix(100, 0).UTC(), Command: "operator-sdk init", Plugins: []string{"go.kubebuilder.io/v2"}}
+			e2 := Event{Timestamp: time.Unix(200, 0).UTC(), Command: "operator-sdk create api", ErrorCategory: ErrorCategoryOther}
+			Expect(Record(path, e1)).To(Succeed())
+			Expect(Record(path, e2)).To(Succeed())
+
+			events, err := ReadEvents(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Equal([]Event{e1, e2}))
+		})
+	})
+
+	Describe("Summarize", func() {
+		It("aggregates events", func() {
+			s := Summarize([]Event{
+				{Timestamp: time.Unix(200, 0), Command: "operator-sdk init", Plugins: []string{"helm.sdk.operatorframework.io/v1"}, SDKVersion: "v1.0.0"},
+				{Timestamp: time.Unix(100, 0), Command: "operator-sdk create api", Plugins: []string{"helm.sdk.operatorframework.io/v1"}, SDKVersion: "v1.0.0", ErrorCategory: ErrorCategoryNetwork},
+				{Timestamp: time.Unix(300, 0), Command: "operator-sdk create api", SDKVersion: "v1.1.0"},
+			})
+			Expect(s.Total).To(Equal(3))
+			Expect(s.Errors).To(Equal(1))
+			Expect(s.Commands).To(Equal(map[string]int{"operator-sdk init": 1, "operator-sdk create api": 2}))
+			Expect(s.Plugins).To(Equal(map[string]int{"helm.sdk.operatorframework.io/v1": 2}))
+			Expect(s.SDKVersions).To(Equal(map[string]int{"v1.0.0": 2, "v1.1.0": 1}))
+			Expect(s.ErrorCategories).To(Equal(map[string]int{ErrorCategoryNetwork: 1}))
+			Expect(s.First).To(Equal(time.Unix(100, 0)))
+			Expect(s.Last).To(Equal(time.Unix(300, 0)))
+		})
+	})
+})
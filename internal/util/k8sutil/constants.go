@@ -23,4 +23,9 @@ const (
 	// which is the namespace where the watch activity happens.
 	// this value is empty if the operator is running with clusterScope.
 	WatchNamespaceEnvVar = "WATCH_NAMESPACE"
+
+	// DefaultConditionReason is the reason given to status conditions set
+	// without one by operators run with standard conditions, since the
+	// reason of a metav1.Condition is required.
+	DefaultConditionReason = "Unspecified"
 )
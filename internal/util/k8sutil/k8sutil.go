@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/yaml"
 )
@@ -107,6 +108,15 @@ func TrimDNS1123Label(label string) string {
 	return label
 }
 
+// IsClusterScoped returns true if gvk is mapped to a cluster-scoped resource.
+func IsClusterScoped(restMapper meta.RESTMapper, gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
 // SupportsOwnerReference checks whether a given dependent supports owner references, based on the owner.
 // This function performs following checks:
 //  -- True: Owner is cluster-scoped.
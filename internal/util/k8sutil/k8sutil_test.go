@@ -240,3 +240,22 @@ func TestSupportsOwnerReference(t *testing.T) {
 		})
 	}
 }
+
+func TestIsClusterScoped(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	namespacedGVK := schema.GroupVersionKind{Group: "apps", Version: "v1alpha1", Kind: "MyNamespaceKind"}
+	clusterGVK := schema.GroupVersionKind{Group: "rbac", Version: "v1alpha1", Kind: "MyClusterKind"}
+	restMapper.Add(namespacedGVK, meta.RESTScopeNamespace)
+	restMapper.Add(clusterGVK, meta.RESTScopeRoot)
+
+	clusterScoped, err := IsClusterScoped(restMapper, namespacedGVK)
+	assert.NoError(t, err)
+	assert.False(t, clusterScoped)
+
+	clusterScoped, err = IsClusterScoped(restMapper, clusterGVK)
+	assert.NoError(t, err)
+	assert.True(t, clusterScoped)
+
+	_, err = IsClusterScoped(restMapper, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Unknown"})
+	assert.Error(t, err)
+}
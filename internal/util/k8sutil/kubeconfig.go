@@ -0,0 +1,51 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sutil
+
+import (
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// BindKubeconfigFlags binds the --kubeconfig flag, the path to a kubeconfig
+// file, to path and the --kubeconfig-context flag, the name of a context in
+// that file, to kubeContext.
+func BindKubeconfigFlags(fs *pflag.FlagSet, path, kubeContext *string) {
+	fs.StringVar(path, "kubeconfig", "",
+		"Path to the kubeconfig file to use for CLI requests.")
+	fs.StringVar(kubeContext, "kubeconfig-context", "",
+		"Name of the kubeconfig context to use for CLI requests. Defaults to the kubeconfig's current context.")
+}
+
+// NewClientConfig returns the client config of the kubeconfig file at path
+// and its context kubeContext, with overrides applied if not nil. If path is
+// empty, the kubeconfig is found with the default loading rules: the
+// KUBECONFIG env var, then $HOME/.kube/config, then the in-cluster config.
+// Like kubectl, KUBECONFIG may be a list of paths, whose files are merged;
+// the first file to set a value, such as the current context, wins. If
+// kubeContext is empty, the kubeconfig's current context is used. The
+// kubeconfig is loaded lazily, so errors are returned by the client config's
+// methods.
+func NewClientConfig(path, kubeContext string, overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig {
+	if overrides == nil {
+		overrides = &clientcmd.ConfigOverrides{}
+	}
+	if kubeContext != "" {
+		overrides.CurrentContext = kubeContext
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = path
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+}
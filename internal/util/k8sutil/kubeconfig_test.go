@@ -0,0 +1,175 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    namespace: dev-ns
+    user: admin
+- name: prod
+  context:
+    cluster: prod
+    namespace: prod-ns
+    user: admin
+current-context: prod
+users:
+- name: admin
+  user:
+    token: test
+`
+
+func TestBindKubeconfigFlags(t *testing.T) {
+	var path, kubeContext string
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindKubeconfigFlags(fs, &path, &kubeContext)
+	require.NoError(t, fs.Parse([]string{"--kubeconfig=/tmp/kubeconfig", "--kubeconfig-context=dev"}))
+	assert.Equal(t, "/tmp/kubeconfig", path)
+	assert.Equal(t, "dev", kubeContext)
+}
+
+// devKubeconfig and prodKubeconfig split testKubeconfig into two files.
+const devKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    namespace: dev-ns
+    user: admin
+users:
+- name: admin
+  user:
+    token: test
+`
+
+const prodKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    namespace: prod-ns
+    user: admin
+current-context: prod
+users:
+- name: admin
+  user:
+    token: other
+`
+
+func writeKubeconfig(t *testing.T, contents string) string {
+	f, err := ioutil.TempFile("", "kubeconfig-")
+	require.NoError(t, err)
+	_, err = f.WriteString(contents)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	return f.Name()
+}
+
+func TestNewClientConfig(t *testing.T) {
+	path := writeKubeconfig(t, testKubeconfig)
+	defer os.Remove(path)
+
+	testCases := []struct {
+		name        string
+		kubeContext string
+		overrides   *clientcmd.ConfigOverrides
+		host        string
+		namespace   string
+	}{
+		{"current context", "", nil, "https://prod.example.com:6443", "prod-ns"},
+		{"selected context", "dev", nil, "https://dev.example.com:6443", "dev-ns"},
+		{
+			"selected context with overrides", "dev",
+			&clientcmd.ConfigOverrides{Context: clientcmdapi.Context{Namespace: "override-ns"}},
+			"https://dev.example.com:6443", "override-ns",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewClientConfig(path, tc.kubeContext, tc.overrides)
+			restConfig, err := cfg.ClientConfig()
+			require.NoError(t, err)
+			assert.Equal(t, tc.host, restConfig.Host)
+			ns, _, err := cfg.Namespace()
+			require.NoError(t, err)
+			assert.Equal(t, tc.namespace, ns)
+		})
+	}
+
+	_, err := NewClientConfig(path, "doesnotexist", nil).ClientConfig()
+	assert.Error(t, err)
+}
+
+func TestNewClientConfigKubeconfigPathList(t *testing.T) {
+	devPath := writeKubeconfig(t, devKubeconfig)
+	defer os.Remove(devPath)
+	prodPath := writeKubeconfig(t, prodKubeconfig)
+	defer os.Remove(prodPath)
+
+	defer os.Setenv(KubeConfigEnvVar, os.Getenv(KubeConfigEnvVar))
+	require.NoError(t, os.Setenv(KubeConfigEnvVar, strings.Join([]string{devPath, prodPath}, string(filepath.ListSeparator))))
+
+	// The current context is only set in the second file.
+	cfg := NewClientConfig("", "", nil)
+	restConfig, err := cfg.ClientConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "https://prod.example.com:6443", restConfig.Host)
+	// Both files define the admin user; the first file's wins.
+	assert.Equal(t, "test", restConfig.BearerToken)
+
+	// Contexts of every file can be selected.
+	cfg = NewClientConfig("", "dev", nil)
+	ns, _, err := cfg.Namespace()
+	require.NoError(t, err)
+	assert.Equal(t, "dev-ns", ns)
+
+	// An explicit path is not merged with KUBECONFIG.
+	_, err = NewClientConfig(prodPath, "dev", nil).ClientConfig()
+	assert.Error(t, err)
+}
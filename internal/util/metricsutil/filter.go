@@ -0,0 +1,109 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsutil
+
+import (
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("metrics")
+
+// WithAuthenticationAndAuthorization returns a filter that authenticates
+// requests and authorizes them against RBAC, as kube-rbac-proxy does. A client
+// is identified by its verified certificate, whose common name is the user and
+// organizations the groups, or else by a bearer token checked with a
+// TokenReview. A SubjectAccessReview then checks that the user may perform the
+// request's verb on its path, ex. "get" on the "/metrics" non-resource URL.
+func WithAuthenticationAndAuthorization(client kubernetes.Interface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := authenticate(client, r)
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			allowed, err := authorize(client, r, user)
+			if err != nil {
+				log.Error(err, "Failed to authorize metrics request.", "user", user.Username)
+				http.Error(w, "Authorization error", http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// authenticate returns the user that made r, and whether r is authenticated.
+func authenticate(client kubernetes.Interface, r *http.Request) (authenticationv1.UserInfo, bool) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) != 0 && len(r.TLS.PeerCertificates) != 0 {
+		subject := r.TLS.PeerCertificates[0].Subject
+		return authenticationv1.UserInfo{Username: subject.CommonName, Groups: subject.Organization}, true
+	}
+
+	const bearerPrefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, bearerPrefix) {
+		return authenticationv1.UserInfo{}, false
+	}
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: strings.TrimSpace(strings.TrimPrefix(auth, bearerPrefix))},
+	}
+	review, err := client.AuthenticationV1().TokenReviews().Create(r.Context(), review, metav1.CreateOptions{})
+	if err != nil {
+		log.Error(err, "Failed to review metrics request token.")
+		return authenticationv1.UserInfo{}, false
+	}
+	return review.Status.User, review.Status.Authenticated
+}
+
+// authorize returns whether user may make request r.
+func authorize(client kubernetes.Interface, r *http.Request, user authenticationv1.UserInfo) (bool, error) {
+	verb := strings.ToLower(r.Method)
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		verb = "get"
+	}
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: r.URL.Path,
+				Verb: verb,
+			},
+		},
+	}
+	review, err := client.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metricsutil serves operator metrics securely, in place of a
+// kube-rbac-proxy sidecar.
+package metricsutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// metricsPath is the path that metrics are served at, as by controller-runtime.
+const metricsPath = "/metrics"
+
+// shutdownTimeout is the time given to in-flight scrapes when the server stops.
+const shutdownTimeout = 5 * time.Second
+
+// TLSServer serves the metrics in controller-runtime's registry over TLS,
+// optionally authenticating clients by certificate, so that metrics can be
+// secured without a kube-rbac-proxy sidecar. It implements manager.Runnable.
+type TLSServer struct {
+	// BindAddress is the address the server listens on.
+	BindAddress string
+	// CertFile and KeyFile are the server's certificate and key. If both are
+	// empty, the server generates a self-signed certificate on start, as
+	// kube-rbac-proxy does.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile, if set, is a bundle of CA certificates that clients must
+	// present a certificate signed by.
+	ClientCAFile string
+	// Filter, if set, wraps the metrics handler, ex. with
+	// WithAuthenticationAndAuthorization.
+	Filter func(http.Handler) http.Handler
+}
+
+// NewTLSServer returns s, with a filter that requires clients to be authorized
+// by RBAC through cfg if requireRBAC is set. It returns nil if s sets none of
+// its files and requireRBAC is not set, in which case metrics are served by
+// the manager.
+func NewTLSServer(cfg *rest.Config, s TLSServer, requireRBAC bool) (*TLSServer, error) {
+	if s.CertFile == "" && s.KeyFile == "" && s.ClientCAFile == "" && !requireRBAC {
+		return nil, nil
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	if requireRBAC {
+		client, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		s.Filter = WithAuthenticationAndAuthorization(client)
+	}
+	return &s, nil
+}
+
+// Validate returns an error if s is incompletely configured.
+func (s TLSServer) Validate() error {
+	if (s.CertFile == "") != (s.KeyFile == "") {
+		return errors.New("a certificate file and a key file must both be set to serve metrics over TLS")
+	}
+	return nil
+}
+
+// TLSConfig returns the TLS configuration that s serves with.
+func (s TLSServer) TLSConfig() (*tls.Config, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	var cert tls.Certificate
+	var err error
+	if s.CertFile == "" {
+		cert, err = selfSignedCertificate()
+	} else {
+		cert, err = tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading metrics certificate: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if s.ClientCAFile != "" {
+		caPEM, err := ioutil.ReadFile(s.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading metrics client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in metrics client CA file %q", s.ClientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		// A filter may authenticate clients without certificates by token.
+		if s.Filter != nil {
+			cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+	return cfg, nil
+}
+
+// Start serves metrics until stop is closed.
+func (s TLSServer) Start(stop <-chan struct{}) error {
+	tlsConfig, err := s.TLSConfig()
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", s.BindAddress)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", s.BindAddress, err)
+	}
+
+	var handler http.Handler = promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.HTTPErrorOnError,
+	})
+	if s.Filter != nil {
+		handler = s.Filter(handler)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, handler)
+	server := &http.Server{Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := server.Serve(tls.NewListener(ln, tlsConfig)); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case <-stop:
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(ctx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+// selfSignedCertificate returns a certificate for serving metrics, valid for
+// a year, whose key is held only in memory.
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	// The certificate signs itself, so it is marked as a CA whose key signs
+	// certificates.
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s@%d", host, now.Unix())},
+		DNSNames:              []string{host, "localhost"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that
+// every replica serves metrics.
+func (TLSServer) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsutil
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+)
+
+func TestNewTLSServer(t *testing.T) {
+	cfg := &rest.Config{Host: "https://localhost:6443"}
+
+	s, err := NewTLSServer(cfg, TLSServer{BindAddress: ":8443"}, false)
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	s, err = NewTLSServer(cfg, TLSServer{BindAddress: ":8443", CertFile: "tls.crt", KeyFile: "tls.key"}, false)
+	if assert.NoError(t, err) && assert.NotNil(t, s) {
+		assert.Equal(t, ":8443", s.BindAddress)
+		assert.Nil(t, s.Filter)
+	}
+
+	s, err = NewTLSServer(cfg, TLSServer{BindAddress: ":8443"}, true)
+	if assert.NoError(t, err) && assert.NotNil(t, s) {
+		assert.NotNil(t, s.Filter)
+	}
+
+	_, err = NewTLSServer(cfg, TLSServer{CertFile: "tls.crt"}, false)
+	assert.Error(t, err)
+}
+
+func TestTLSServerValidate(t *testing.T) {
+	assert.NoError(t, TLSServer{CertFile: "tls.crt", KeyFile: "tls.key"}.Validate())
+	assert.NoError(t, TLSServer{}.Validate())
+	assert.Error(t, TLSServer{CertFile: "tls.crt"}.Validate())
+	assert.Error(t, TLSServer{KeyFile: "tls.key"}.Validate())
+}
+
+func TestTLSServerTLSConfigSelfSigned(t *testing.T) {
+	cfg, err := TLSServer{}.TLSConfig()
+	if assert.NoError(t, err) && assert.Len(t, cfg.Certificates, 1) {
+		cert, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+		if assert.NoError(t, err) {
+			assert.Contains(t, cert.DNSNames, "localhost")
+			assert.NoError(t, cert.CheckSignatureFrom(cert))
+		}
+	}
+}
+
+func TestTLSServerTLSConfigMissingFiles(t *testing.T) {
+	_, err := TLSServer{CertFile: "missing.crt", KeyFile: "missing.key"}.TLSConfig()
+	assert.Error(t, err)
+}
+
+func TestTLSServerNeedLeaderElection(t *testing.T) {
+	assert.False(t, TLSServer{}.NeedLeaderElection())
+}
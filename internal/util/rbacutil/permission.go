@@ -0,0 +1,71 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbacutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Permission is a verb on a resource that an operator was denied. Namespace
+// is empty for requests at the cluster scope.
+type Permission struct {
+	// User is the user that was denied, if known.
+	User string
+	Verb string
+	// Resource is the resource of the request, including its subresource,
+	// ex. "pods/log".
+	Resource  string
+	APIGroup  string
+	Namespace string
+}
+
+func (p Permission) String() string {
+	resource := p.Resource
+	if p.APIGroup != "" {
+		resource += "." + p.APIGroup
+	}
+	if p.Namespace == "" {
+		return fmt.Sprintf("%s %s at the cluster scope", p.Verb, resource)
+	}
+	return fmt.Sprintf("%s %s in namespace %q", p.Verb, resource, p.Namespace)
+}
+
+// SortPermissions sorts perms by namespace, API group, resource, and verb.
+func SortPermissions(perms []Permission) {
+	sort.Slice(perms, func(i, j int) bool {
+		a, b := perms[i], perms[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.APIGroup != b.APIGroup {
+			return a.APIGroup < b.APIGroup
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Verb < b.Verb
+	})
+}
+
+// MissingPermissionsMessage lists perms in a single condition message.
+func MissingPermissionsMessage(perms []Permission) string {
+	descriptions := make([]string, len(perms))
+	for i, p := range perms {
+		descriptions[i] = p.String()
+	}
+	return fmt.Sprintf("missing RBAC permissions: %s", strings.Join(descriptions, "; "))
+}
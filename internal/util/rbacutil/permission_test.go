@@ -0,0 +1,51 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbacutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortPermissions(t *testing.T) {
+	perms := []Permission{
+		{Verb: "patch", Resource: "deployments", APIGroup: "apps", Namespace: "default"},
+		{Verb: "create", Resource: "services", Namespace: "default"},
+		{Verb: "get", Resource: "clusterroles", APIGroup: "rbac.authorization.k8s.io"},
+		{Verb: "get", Resource: "deployments", APIGroup: "apps", Namespace: "default"},
+	}
+	SortPermissions(perms)
+	expected := []Permission{
+		{Verb: "get", Resource: "clusterroles", APIGroup: "rbac.authorization.k8s.io"},
+		{Verb: "create", Resource: "services", Namespace: "default"},
+		{Verb: "get", Resource: "deployments", APIGroup: "apps", Namespace: "default"},
+		{Verb: "patch", Resource: "deployments", APIGroup: "apps", Namespace: "default"},
+	}
+	if !reflect.DeepEqual(perms, expected) {
+		t.Errorf("expected %v, got %v", expected, perms)
+	}
+}
+
+func TestMissingPermissionsMessage(t *testing.T) {
+	perms := []Permission{
+		{Verb: "get", Resource: "clusterroles", APIGroup: "rbac.authorization.k8s.io"},
+		{Verb: "get", Resource: "pods/log", Namespace: "default"},
+	}
+	expected := `missing RBAC permissions: get clusterroles.rbac.authorization.k8s.io at the cluster scope; ` +
+		`get pods/log in namespace "default"`
+	if msg := MissingPermissionsMessage(perms); msg != expected {
+		t.Errorf("expected %q, got %q", expected, msg)
+	}
+}
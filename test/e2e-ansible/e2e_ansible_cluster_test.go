@@ -55,6 +55,9 @@ var _ = Describe("Running ansible projects", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 		AfterEach(func() {
+			By("capturing artifacts if the spec failed")
+			tc.CaptureArtifactsOnFailure()
+
 			By("deleting Curl Pod created")
 			_, _ = tc.Kubectl.Delete(false, "pod", "curl")
 
@@ -43,6 +43,9 @@ var _ = Describe("operator-sdk", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 		AfterEach(func() {
+			By("capturing artifacts if the spec failed")
+			tc.CaptureArtifactsOnFailure()
+
 			By("cleaning up the operator and resources")
 			defaultOutput, err := tc.KustomizeBuild(filepath.Join("config", "default"))
 			Expect(err).NotTo(HaveOccurred())
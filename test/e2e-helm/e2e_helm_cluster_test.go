@@ -43,6 +43,9 @@ var _ = Describe("Running Helm projects", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 		AfterEach(func() {
+			By("capturing artifacts if the spec failed")
+			tc.CaptureArtifactsOnFailure()
+
 			By("deleting Curl Pod created")
 			_, _ = tc.Kubectl.Delete(true, "pod", "curl")
 
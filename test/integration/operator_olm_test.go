@@ -33,7 +33,6 @@ import (
 
 	"github.com/operator-framework/operator-sdk/internal/olm/operator"
 	"github.com/operator-framework/operator-sdk/internal/olm/operator/packagemanifests"
-	"github.com/operator-framework/operator-sdk/internal/util/k8sutil"
 )
 
 const (
@@ -43,10 +42,6 @@ const (
 	defaultOperatorVersion = "0.0.2"
 )
 
-var (
-	kubeconfigPath = os.Getenv(k8sutil.KubeConfigEnvVar)
-)
-
 // TODO(estroz): rewrite these in the style of e2e tests (ginkgo/gomega + scaffold a project for each scenario).
 
 func TestOLMIntegration(t *testing.T) {
@@ -101,7 +96,7 @@ func PackageManifestsOwnNamespace(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	cfg := &operator.Configuration{KubeconfigPath: kubeconfigPath}
+	cfg := &operator.Configuration{}
 	assert.NoError(t, cfg.Load())
 	i := packagemanifests.NewInstall(cfg)
 	i.PackageManifestsDirectory = manifestsDir
@@ -113,7 +108,7 @@ func PackageManifestsOwnNamespace(t *testing.T) {
 
 	// Cleanup.
 	defer func() {
-		if err := doUninstall(t, kubeconfigPath); err != nil {
+		if err := doUninstall(t); err != nil {
 			t.Fatal(err)
 		}
 	}()
@@ -163,14 +158,14 @@ func PackageManifestsBasic(t *testing.T) {
 		os.RemoveAll(tmp)
 		t.Fatal(err)
 	}
-	cfg := &operator.Configuration{KubeconfigPath: kubeconfigPath}
+	cfg := &operator.Configuration{}
 	assert.NoError(t, cfg.Load())
 	i := packagemanifests.NewInstall(cfg)
 	i.PackageManifestsDirectory = manifestsDir
 	i.Version = defaultOperatorVersion
 
 	// Remove operator before deploy
-	assert.Error(t, doUninstall(t, kubeconfigPath))
+	assert.Error(t, doUninstall(t))
 
 	// Deploy operator
 	assert.NoError(t, doInstall(i))
@@ -178,9 +173,9 @@ func PackageManifestsBasic(t *testing.T) {
 	assert.Error(t, doInstall(i))
 
 	// Remove operator after deploy
-	assert.NoError(t, doUninstall(t, kubeconfigPath))
+	assert.NoError(t, doUninstall(t))
 	// Remove operator after removal
-	assert.Error(t, doUninstall(t, kubeconfigPath))
+	assert.Error(t, doUninstall(t))
 }
 
 func PackageManifestsMultiplePackages(t *testing.T) {
@@ -255,7 +250,7 @@ func PackageManifestsMultiplePackages(t *testing.T) {
 		os.RemoveAll(tmp)
 		t.Fatal(err)
 	}
-	cfg := &operator.Configuration{KubeconfigPath: kubeconfigPath}
+	cfg := &operator.Configuration{}
 	assert.NoError(t, cfg.Load())
 	i := packagemanifests.NewInstall(cfg)
 	i.PackageManifestsDirectory = manifestsDir
@@ -264,11 +259,11 @@ func PackageManifestsMultiplePackages(t *testing.T) {
 	// Deploy operator
 	assert.NoError(t, doInstall(i))
 	// Remove operator after deploy
-	assert.NoError(t, doUninstall(t, kubeconfigPath))
+	assert.NoError(t, doUninstall(t))
 }
 
-func doUninstall(t *testing.T, kubeconfigPath string) error {
-	cfg := &operator.Configuration{KubeconfigPath: kubeconfigPath}
+func doUninstall(t *testing.T) error {
+	cfg := &operator.Configuration{}
 	assert.NoError(t, cfg.Load())
 	uninstall := operator.NewUninstall(cfg)
 	uninstall.DeleteAll = true
@@ -0,0 +1,88 @@
+// Copyright 2020 The Operator-SDK Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+)
+
+// ArtifactsDirEnv is the environment variable containing the directory that
+// CaptureArtifactsOnFailure writes to. CI systems such as Prow set this variable
+// to a directory that is uploaded after a job runs.
+const ArtifactsDirEnv = "ARTIFACTS"
+
+// ArtifactsDir returns the directory that CaptureArtifactsOnFailure writes to,
+// which is $ARTIFACTS if set and "<temp dir>/operator-sdk-e2e-artifacts" otherwise.
+func ArtifactsDir() string {
+	if dir := os.Getenv(ArtifactsDirEnv); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "operator-sdk-e2e-artifacts")
+}
+
+var unsafePathChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// CaptureArtifactsOnFailure writes the controller-manager logs, Events, and
+// custom resources of tc's Kind to a subdirectory of ArtifactsDir() named after
+// the current spec, if that spec failed. It should be called in an AfterEach
+// before the project is undeployed. Errors are written to GinkgoWriter, since
+// capturing artifacts must not fail or hide the spec's failure.
+func (tc TestContext) CaptureArtifactsOnFailure() {
+	desc := CurrentGinkgoTestDescription()
+	if !desc.Failed {
+		return
+	}
+
+	name := strings.Trim(unsafePathChars.ReplaceAllString(desc.FullTestText, "-"), "-")
+	dir := filepath.Join(ArtifactsDir(), tc.ProjectName, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fmt.Fprintln(GinkgoWriter, "warning: error creating artifacts directory:", err)
+		return
+	}
+	fmt.Fprintf(GinkgoWriter, "capturing artifacts of failed spec to %s\n", dir)
+
+	tc.captureArtifact(dir, "controller-manager.log", func() (string, error) {
+		return tc.Kubectl.Logs("-l", "control-plane=controller-manager", "-c", "manager", "--tail=-1")
+	})
+	tc.captureArtifact(dir, "events.yaml", func() (string, error) {
+		return tc.Kubectl.Get(false, "events", "--all-namespaces", "--sort-by=.lastTimestamp", "-o", "yaml")
+	})
+	if tc.Kind != "" {
+		tc.captureArtifact(dir, strings.ToLower(tc.Kind)+".yaml", func() (string, error) {
+			return tc.Kubectl.Get(false, tc.Kind, "--all-namespaces", "-o", "yaml")
+		})
+	}
+}
+
+// captureArtifact writes the output of get to the file named name in dir.
+func (tc TestContext) captureArtifact(dir, name string, get func() (string, error)) {
+	out, err := get()
+	if err != nil {
+		fmt.Fprintf(GinkgoWriter, "warning: error capturing artifact %s: %v\n", name, err)
+		if out == "" {
+			return
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(out), 0644); err != nil {
+		fmt.Fprintf(GinkgoWriter, "warning: error writing artifact %s: %v\n", name, err)
+	}
+}